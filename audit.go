@@ -0,0 +1,105 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// ops for audit log error.
+const (
+	OpRecordAuditEvent = "RecordAuditEvent"
+	OpFindAuditEvents  = "FindAuditEvents"
+)
+
+// AuditAction is the kind of change made by an audited operation.
+type AuditAction string
+
+// Audit actions.
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// AuditEvent is an entry of the audit log, recording a single mutating
+// operation, who made it and what it changed.
+type AuditEvent struct {
+	ID   ID        `json:"id"`
+	Time time.Time `json:"time"`
+
+	// UserID is the user the actor acted on behalf of.
+	UserID ID `json:"userID,omitempty"`
+	// ActorID identifies the authorizer the operation was made with,
+	// the id of an authorization for tokens or of a session.
+	ActorID ID `json:"actorID,omitempty"`
+	// ActorKind is the kind of the authorizer, such as "authorization" or "session".
+	ActorKind string `json:"actorKind,omitempty"`
+	// SourceIP is the address of the client that made the request.
+	SourceIP string `json:"sourceIP,omitempty"`
+
+	Action       AuditAction  `json:"action"`
+	ResourceType ResourceType `json:"resourceType"`
+	ResourceID   ID           `json:"resourceID,omitempty"`
+	// OrgID is the organization of the resource. It is unset for resources
+	// that do not belong to one, such as users.
+	OrgID ID `json:"orgID,omitempty"`
+
+	// Before and After are the resource prior to and as a result of the
+	// operation. Before is empty for creations and After for deletions.
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditEventFilter represents a set of filters that restrict the returned audit events.
+type AuditEventFilter struct {
+	OrgID        *ID
+	UserID       *ID
+	ResourceType *ResourceType
+	ResourceID   *ID
+	Action       *AuditAction
+	// Since and Until restrict the events to those recorded within [Since, Until).
+	Since *time.Time
+	Until *time.Time
+}
+
+// QueryParams converts AuditEventFilter fields to url query params.
+func (f AuditEventFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.OrgID != nil {
+		qp["orgID"] = []string{f.OrgID.String()}
+	}
+	if f.UserID != nil {
+		qp["userID"] = []string{f.UserID.String()}
+	}
+	if f.ResourceType != nil {
+		qp["resourceType"] = []string{string(*f.ResourceType)}
+	}
+	if f.ResourceID != nil {
+		qp["resourceID"] = []string{f.ResourceID.String()}
+	}
+	if f.Action != nil {
+		qp["action"] = []string{string(*f.Action)}
+	}
+	if f.Since != nil {
+		qp["since"] = []string{f.Since.Format(time.RFC3339Nano)}
+	}
+	if f.Until != nil {
+		qp["until"] = []string{f.Until.Format(time.RFC3339Nano)}
+	}
+	return qp
+}
+
+// AuditRecorder records audit events.
+type AuditRecorder interface {
+	// RecordAuditEvent appends the event to the audit log, assigning it an ID.
+	RecordAuditEvent(ctx context.Context, e *AuditEvent) error
+}
+
+// AuditLogService is an append-only log of audit events.
+type AuditLogService interface {
+	AuditRecorder
+
+	// FindAuditEvents returns the events matching the filter, in the order they were recorded.
+	FindAuditEvents(ctx context.Context, filter AuditEventFilter, opt ...FindOptions) ([]*AuditEvent, int, error)
+}
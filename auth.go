@@ -3,6 +3,7 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // AuthorizationKind is returned by (*Authorization).Kind().
@@ -23,13 +24,31 @@ type Authorization struct {
 	OrgID       ID           `json:"orgID"`
 	UserID      ID           `json:"userID,omitempty"`
 	Permissions []Permission `json:"permissions"`
+	// Roles are the ids of the roles granted to the authorization in addition to its permissions.
+	Roles []ID `json:"roles,omitempty"`
+	// RolePermissions are the permissions of the roles, resolved when the authorization is found.
+	RolePermissions []Permission `json:"-"`
+
+	// ExpiresAt is the time after which the token is rejected, the token never expires if it is nil.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// LastUsedAt and LastUsedIP describe the last request authenticated with the token.
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIP string     `json:"lastUsedIP,omitempty"`
+	// PreviousTokenExpiresAt is the end of the grace period of the token replaced by the last rotation.
+	PreviousTokenExpiresAt *time.Time `json:"previousTokenExpiresAt,omitempty"`
+	// TokenPrefix is the start of the token, which identifies it once the token itself
+	// is no longer known: only a hash of the token is stored, Token is only set when
+	// the authorization is created, rotated or found by its token.
+	TokenPrefix string `json:"tokenPrefix,omitempty"`
 	CRUDLog
 }
 
 // AuthorizationUpdate is the authorization update request.
 type AuthorizationUpdate struct {
-	Status      *Status `json:"status,omitempty"`
-	Description *string `json:"description,omitempty"`
+	Status      *Status    `json:"status,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	Roles       *[]ID      `json:"roles,omitempty"`
 }
 
 // Valid ensures that the authorization is valid.
@@ -47,13 +66,13 @@ func (a *Authorization) Valid() error {
 }
 
 // Allowed returns true if the authorization is active and request permission
-// exists in the authorization's list of permissions.
+// exists in the authorization's list of permissions, or in those of its roles.
 func (a *Authorization) Allowed(p Permission) bool {
 	if !a.IsActive() {
 		return false
 	}
 
-	return PermissionAllowed(p, a.Permissions)
+	return PermissionAllowed(p, a.Permissions) || PermissionAllowed(p, a.RolePermissions)
 }
 
 // IsActive is a stub for idpe.
@@ -61,9 +80,23 @@ func IsActive(a *Authorization) bool {
 	return a.IsActive()
 }
 
-// IsActive returns true if the authorization active.
+// IsActive returns true if the authorization active and not expired.
 func (a *Authorization) IsActive() bool {
-	return a.Status == Active
+	return a.Status == Active && !a.IsExpired(time.Now())
+}
+
+// IsExpired returns true if the authorization expired at or before now.
+func (a *Authorization) IsExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
+}
+
+// MaskedToken returns the token of the authorization if it is known,
+// and its prefix followed by a mask otherwise.
+func (a *Authorization) MaskedToken() string {
+	if a.Token != "" || a.TokenPrefix == "" {
+		return a.Token
+	}
+	return a.TokenPrefix + "********"
 }
 
 // GetUserID returns the user id.
@@ -84,6 +117,7 @@ const (
 	OpFindAuthorizations       = "FindAuthorizations"
 	OpCreateAuthorization      = "CreateAuthorization"
 	OpUpdateAuthorization      = "UpdateAuthorization"
+	OpRotateAuthorization      = "RotateAuthorization"
 	OpDeleteAuthorization      = "DeleteAuthorization"
 )
 
@@ -105,10 +139,20 @@ type AuthorizationService interface {
 	// UpdateAuthorization updates the status and description if available.
 	UpdateAuthorization(ctx context.Context, id ID, udp *AuthorizationUpdate) (*Authorization, error)
 
+	// RotateAuthorization issues a new token for the authorization, keeping its ID and permissions.
+	// The replaced token keeps working for gracePeriod, so clients can switch over to the new token.
+	RotateAuthorization(ctx context.Context, id ID, gracePeriod time.Duration) (*Authorization, error)
+
 	// Removes a authorization by token.
 	DeleteAuthorization(ctx context.Context, id ID) error
 }
 
+// AuthorizationUsageRecorder records the requests authenticated with an authorization's token.
+type AuthorizationUsageRecorder interface {
+	// RecordAuthorizationUse sets the last time, and address, the authorization was used at.
+	RecordAuthorizationUse(ctx context.Context, id ID, at time.Time, ip string) error
+}
+
 // AuthorizationFilter represents a set of filter that restrict the returned results.
 type AuthorizationFilter struct {
 	Token *string
@@ -0,0 +1,104 @@
+package authorizer
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+)
+
+var _ influxdb.AuditLogService = (*AuditLogService)(nil)
+
+// AuditLogService wraps a influxdb.AuditLogService and authorizes actions
+// against it appropriately.
+type AuditLogService struct {
+	s influxdb.AuditLogService
+}
+
+// NewAuditLogService constructs an instance of an authorizing audit log service.
+func NewAuditLogService(s influxdb.AuditLogService) *AuditLogService {
+	return &AuditLogService{
+		s: s,
+	}
+}
+
+// FindAuditEvents checks to see if the authorizer on context has read access to the audit log.
+func (s *AuditLogService) FindAuditEvents(ctx context.Context, filter influxdb.AuditEventFilter, opt ...influxdb.FindOptions) ([]*influxdb.AuditEvent, int, error) {
+	if _, _, err := AuthorizeReadGlobal(ctx, influxdb.AuditResourceType); err != nil {
+		return nil, 0, err
+	}
+	return s.s.FindAuditEvents(ctx, filter, opt...)
+}
+
+// RecordAuditEvent checks to see if the authorizer on context has write access to the audit log.
+func (s *AuditLogService) RecordAuditEvent(ctx context.Context, e *influxdb.AuditEvent) error {
+	if _, _, err := AuthorizeWriteGlobal(ctx, influxdb.AuditResourceType); err != nil {
+		return err
+	}
+	return s.s.RecordAuditEvent(ctx, e)
+}
+
+// auditCreate records the creation of a resource with the audit recorder on context.
+func auditCreate(ctx context.Context, rt influxdb.ResourceType, id, orgID influxdb.ID, after interface{}) {
+	audit(ctx, influxdb.AuditActionCreate, rt, id, orgID, nil, after)
+}
+
+// auditUpdate records the update of a resource with the audit recorder on context.
+func auditUpdate(ctx context.Context, rt influxdb.ResourceType, id, orgID influxdb.ID, before, after interface{}) {
+	audit(ctx, influxdb.AuditActionUpdate, rt, id, orgID, before, after)
+}
+
+// auditDelete records the deletion of a resource with the audit recorder on context.
+func auditDelete(ctx context.Context, rt influxdb.ResourceType, id, orgID influxdb.ID, before interface{}) {
+	audit(ctx, influxdb.AuditActionDelete, rt, id, orgID, before, nil)
+}
+
+// audit records an event with the audit recorder on context, noting the
+// authorizer on context as its actor. It is a noop when no recorder is set.
+// The operation audited has already succeeded and is not failed when the
+// event cannot be recorded, the recorder is left to report its failures.
+func audit(ctx context.Context, action influxdb.AuditAction, rt influxdb.ResourceType, id, orgID influxdb.ID, before, after interface{}) {
+	rec, ip := icontext.GetAuditRecorder(ctx)
+	if rec == nil {
+		return
+	}
+
+	e := &influxdb.AuditEvent{
+		SourceIP:     ip,
+		Action:       action,
+		ResourceType: rt,
+		ResourceID:   id,
+		OrgID:        orgID,
+		Before:       auditValue(before),
+		After:        auditValue(after),
+	}
+	if a, err := icontext.GetAuthorizer(ctx); err == nil {
+		e.UserID = a.GetUserID()
+		e.ActorID = a.Identifier()
+		e.ActorKind = a.Kind()
+	}
+
+	_ = rec.RecordAuditEvent(ctx, e)
+}
+
+// auditValue returns the JSON encoding of the state of a resource. A state
+// that cannot be encoded is recorded as absent rather than losing the event.
+func auditValue(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	// a nil resource of a concrete type is recorded as absent
+	if err != nil || string(b) == "null" {
+		return nil
+	}
+	return b
+}
+
+// auditing returns whether operations are audited, for wrappers to only look up
+// the state of resources to record when it is needed.
+func auditing(ctx context.Context) bool {
+	rec, _ := icontext.GetAuditRecorder(ctx)
+	return rec != nil
+}
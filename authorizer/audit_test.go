@@ -0,0 +1,206 @@
+package authorizer_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditRecorder struct {
+	events []*influxdb.AuditEvent
+}
+
+func (r *auditRecorder) RecordAuditEvent(_ context.Context, e *influxdb.AuditEvent) error {
+	r.events = append(r.events, e)
+	return nil
+}
+
+func TestAudit_BucketService(t *testing.T) {
+	orgID := influxdb.ID(1)
+	auth := &influxdb.Authorization{
+		ID:     10,
+		UserID: 20,
+		OrgID:  orgID,
+		Status: influxdb.Active,
+		Permissions: []influxdb.Permission{{
+			Action:   influxdb.WriteAction,
+			Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+		}},
+	}
+
+	existing := &influxdb.Bucket{ID: 3, OrgID: orgID, Name: "b1"}
+	bktSVC := mock.NewBucketService()
+	bktSVC.CreateBucketFn = func(ctx context.Context, b *influxdb.Bucket) error {
+		b.ID = 3
+		return nil
+	}
+	bktSVC.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return existing, nil
+	}
+	bktSVC.UpdateBucketFn = func(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, OrgID: orgID, Name: *upd.Name}, nil
+	}
+	bktSVC.DeleteBucketFn = func(ctx context.Context, id influxdb.ID) error {
+		return nil
+	}
+	s := authorizer.NewBucketService(bktSVC, mock.NewUserResourceMappingService())
+
+	rec := &auditRecorder{}
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), auth)
+	ctx = influxdbcontext.SetAuditRecorder(ctx, rec, "10.0.0.1")
+
+	require.NoError(t, s.CreateBucket(ctx, &influxdb.Bucket{OrgID: orgID, Name: "b1"}))
+	name := "b2"
+	_, err := s.UpdateBucket(ctx, 3, influxdb.BucketUpdate{Name: &name})
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteBucket(ctx, 3))
+
+	require.Len(t, rec.events, 3)
+	for _, e := range rec.events {
+		assert.Equal(t, influxdb.ID(20), e.UserID)
+		assert.Equal(t, influxdb.ID(10), e.ActorID)
+		assert.Equal(t, influxdb.AuthorizationKind, e.ActorKind)
+		assert.Equal(t, "10.0.0.1", e.SourceIP)
+		assert.Equal(t, influxdb.BucketsResourceType, e.ResourceType)
+		assert.Equal(t, influxdb.ID(3), e.ResourceID)
+		assert.Equal(t, orgID, e.OrgID)
+	}
+
+	create, update, del := rec.events[0], rec.events[1], rec.events[2]
+	assert.Equal(t, influxdb.AuditActionCreate, create.Action)
+	assert.Nil(t, create.Before)
+	assert.Contains(t, string(create.After), `"name":"b1"`)
+
+	assert.Equal(t, influxdb.AuditActionUpdate, update.Action)
+	assert.Contains(t, string(update.Before), `"name":"b1"`)
+	assert.Contains(t, string(update.After), `"name":"b2"`)
+
+	assert.Equal(t, influxdb.AuditActionDelete, del.Action)
+	assert.Contains(t, string(del.Before), `"name":"b1"`)
+	assert.Nil(t, del.After)
+}
+
+func TestAudit_NotRecordedOnFailure(t *testing.T) {
+	orgID := influxdb.ID(1)
+	bktSVC := mock.NewBucketService()
+	bktSVC.CreateBucketFn = func(ctx context.Context, b *influxdb.Bucket) error {
+		return &influxdb.Error{Code: influxdb.EConflict}
+	}
+	s := authorizer.NewBucketService(bktSVC, mock.NewUserResourceMappingService())
+
+	rec := &auditRecorder{}
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, []influxdb.Permission{{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+	}}))
+	ctx = influxdbcontext.SetAuditRecorder(ctx, rec, "10.0.0.1")
+
+	require.Error(t, s.CreateBucket(ctx, &influxdb.Bucket{OrgID: orgID, Name: "b1"}))
+	assert.Empty(t, rec.events)
+}
+
+type failingAuditRecorder struct{}
+
+func (failingAuditRecorder) RecordAuditEvent(context.Context, *influxdb.AuditEvent) error {
+	return &influxdb.Error{Code: influxdb.EInternal, Msg: "audit log unavailable"}
+}
+
+func TestAudit_RecordingFailureDoesNotFailOperation(t *testing.T) {
+	orgID := influxdb.ID(1)
+	created := false
+	bktSVC := mock.NewBucketService()
+	bktSVC.CreateBucketFn = func(ctx context.Context, b *influxdb.Bucket) error {
+		created = true
+		b.ID = 3
+		return nil
+	}
+	s := authorizer.NewBucketService(bktSVC, mock.NewUserResourceMappingService())
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, []influxdb.Permission{{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+	}}))
+	ctx = influxdbcontext.SetAuditRecorder(ctx, failingAuditRecorder{}, "10.0.0.1")
+
+	require.NoError(t, s.CreateBucket(ctx, &influxdb.Bucket{OrgID: orgID, Name: "b1"}))
+	assert.True(t, created)
+}
+
+func TestAudit_SecretValuesAndTokensAreNotRecorded(t *testing.T) {
+	orgID := influxdb.ID(1)
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(true, nil))
+	rec := &auditRecorder{}
+	ctx = influxdbcontext.SetAuditRecorder(ctx, rec, "10.0.0.1")
+
+	secretSVC := &mock.SecretService{
+		PatchSecretsFn: func(ctx context.Context, orgID influxdb.ID, m map[string]string) error {
+			return nil
+		},
+	}
+	err := authorizer.NewSecretService(secretSVC).PatchSecrets(ctx, orgID, map[string]string{
+		"b-key": "b-value",
+		"a-key": "a-value",
+	})
+	require.NoError(t, err)
+
+	authSVC := &mock.AuthorizationService{
+		CreateAuthorizationFn: func(ctx context.Context, a *influxdb.Authorization) error {
+			a.ID = 2
+			a.Token = "secret-token"
+			return nil
+		},
+	}
+	a := &influxdb.Authorization{OrgID: orgID, UserID: 3}
+	require.NoError(t, authorizer.NewAuthorizationService(authSVC).CreateAuthorization(ctx, a))
+	assert.Equal(t, "secret-token", a.Token)
+
+	require.Len(t, rec.events, 2)
+
+	var keys []string
+	require.NoError(t, json.Unmarshal(rec.events[0].After, &keys))
+	assert.Equal(t, []string{"a-key", "b-key"}, keys)
+
+	var recorded influxdb.Authorization
+	require.NoError(t, json.Unmarshal(rec.events[1].After, &recorded))
+	assert.Equal(t, influxdb.ID(2), recorded.ID)
+	assert.Empty(t, recorded.Token)
+}
+
+func TestAuditLogService_FindAuditEvents(t *testing.T) {
+	svc := authorizer.NewAuditLogService(&fakeAuditLog{})
+
+	t.Run("requires read permission for the audit log", func(t *testing.T) {
+		ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, []influxdb.Permission{{
+			Action:   influxdb.ReadAction,
+			Resource: influxdb.Resource{Type: influxdb.BucketsResourceType},
+		}}))
+		_, _, err := svc.FindAuditEvents(ctx, influxdb.AuditEventFilter{})
+		require.Error(t, err)
+		assert.Equal(t, influxdb.EUnauthorized, influxdb.ErrorCode(err))
+	})
+
+	t.Run("allowed with read permission for the audit log", func(t *testing.T) {
+		ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, []influxdb.Permission{{
+			Action:   influxdb.ReadAction,
+			Resource: influxdb.Resource{Type: influxdb.AuditResourceType},
+		}}))
+		events, _, err := svc.FindAuditEvents(ctx, influxdb.AuditEventFilter{})
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+}
+
+type fakeAuditLog struct {
+	auditRecorder
+}
+
+func (f *fakeAuditLog) FindAuditEvents(ctx context.Context, filter influxdb.AuditEventFilter, opt ...influxdb.FindOptions) ([]*influxdb.AuditEvent, int, error) {
+	return []*influxdb.AuditEvent{{ID: 1}}, 1, nil
+}
@@ -3,6 +3,7 @@ package authorizer
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/influxdb/v2"
 )
@@ -13,6 +14,10 @@ var _ influxdb.AuthorizationService = (*AuthorizationService)(nil)
 // against it appropriately.
 type AuthorizationService struct {
 	s influxdb.AuthorizationService
+
+	// RoleService finds the roles granted to authorizations, whose permissions
+	// the authorizer on context must be allowed. Roles cannot be granted when it is nil.
+	RoleService influxdb.RoleService
 }
 
 // NewAuthorizationService constructs an instance of an authorizing authorization serivce.
@@ -74,7 +79,14 @@ func (s *AuthorizationService) CreateAuthorization(ctx context.Context, a *influ
 	if err := VerifyPermissions(ctx, a.Permissions); err != nil {
 		return err
 	}
-	return s.s.CreateAuthorization(ctx, a)
+	if err := s.verifyRoles(ctx, a.Roles); err != nil {
+		return err
+	}
+	if err := s.s.CreateAuthorization(ctx, a); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.AuthorizationsResourceType, a.ID, a.OrgID, withoutToken(a))
+	return nil
 }
 
 // UpdateAuthorization checks to see if the authorizer on context has write access to the authorization provided.
@@ -89,7 +101,37 @@ func (s *AuthorizationService) UpdateAuthorization(ctx context.Context, id influ
 	if _, _, err := AuthorizeWriteResource(ctx, influxdb.UsersResourceType, a.UserID); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateAuthorization(ctx, id, upd)
+	if upd.Roles != nil {
+		if err := s.verifyRoles(ctx, *upd.Roles); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := s.s.UpdateAuthorization(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.AuthorizationsResourceType, id, a.OrgID, withoutToken(a), withoutToken(updated))
+	return updated, nil
+}
+
+// RotateAuthorization checks to see if the authorizer on context has write access to the authorization provided.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id influxdb.ID, gracePeriod time.Duration) (*influxdb.Authorization, error) {
+	a, err := s.s.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeWrite(ctx, influxdb.AuthorizationsResourceType, a.ID, a.OrgID); err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeWriteResource(ctx, influxdb.UsersResourceType, a.UserID); err != nil {
+		return nil, err
+	}
+	rotated, err := s.s.RotateAuthorization(ctx, id, gracePeriod)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.AuthorizationsResourceType, id, a.OrgID, withoutToken(a), withoutToken(rotated))
+	return rotated, nil
 }
 
 // DeleteAuthorization checks to see if the authorizer on context has write access to the authorization provided.
@@ -104,7 +146,45 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id influ
 	if _, _, err := AuthorizeWriteResource(ctx, influxdb.UsersResourceType, a.UserID); err != nil {
 		return err
 	}
-	return s.s.DeleteAuthorization(ctx, id)
+	if err := s.s.DeleteAuthorization(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.AuthorizationsResourceType, id, a.OrgID, withoutToken(a))
+	return nil
+}
+
+// withoutToken returns a copy of the authorization without its token, for it
+// to be recorded in the audit log.
+func withoutToken(a *influxdb.Authorization) *influxdb.Authorization {
+	if a == nil {
+		return nil
+	}
+	cp := *a
+	cp.Token = ""
+	return &cp
+}
+
+// verifyRoles ensures that the authorizer on context is allowed all of the permissions of the roles.
+func (s *AuthorizationService) verifyRoles(ctx context.Context, ids []influxdb.ID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if s.RoleService == nil {
+		return &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  "roles cannot be granted to authorizations",
+		}
+	}
+	for _, id := range ids {
+		r, err := s.RoleService.FindRoleByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := VerifyPermissions(ctx, r.Permissions); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // VerifyPermissions ensures that an authorization is allowed all of the appropriate permissions.
@@ -175,6 +175,60 @@ func AuthorizeFindVariables(ctx context.Context, rs []*influxdb.Variable) ([]*in
 	return rrs, len(rrs), nil
 }
 
+// AuthorizeFindRoles takes the given items and returns only the ones that the user is authorized to read.
+func AuthorizeFindRoles(ctx context.Context, rs []*influxdb.Role) ([]*influxdb.Role, int, error) {
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rrs := rs[:0]
+	for _, r := range rs {
+		_, _, err := AuthorizeRead(ctx, influxdb.RolesResourceType, r.ID, r.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		rrs = append(rrs, r)
+	}
+	return rrs, len(rrs), nil
+}
+
+// AuthorizeFindCertificateMappings takes the given items and returns only the ones that the user is authorized to read.
+func AuthorizeFindCertificateMappings(ctx context.Context, rs []*influxdb.CertificateMapping) ([]*influxdb.CertificateMapping, int, error) {
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rrs := rs[:0]
+	for _, r := range rs {
+		_, _, err := AuthorizeRead(ctx, influxdb.AuthorizationsResourceType, r.AuthorizationID, r.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		rrs = append(rrs, r)
+	}
+	return rrs, len(rrs), nil
+}
+
+// AuthorizeFindDashboardShares takes the given items and returns only the ones that the user is authorized to read.
+func AuthorizeFindDashboardShares(ctx context.Context, rs []*influxdb.DashboardShare) ([]*influxdb.DashboardShare, int, error) {
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rrs := rs[:0]
+	for _, r := range rs {
+		_, _, err := AuthorizeRead(ctx, influxdb.DashboardsResourceType, r.DashboardID, r.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+		rrs = append(rrs, r)
+	}
+	return rrs, len(rrs), nil
+}
+
 // AuthorizeFindScrapers takes the given items and returns only the ones that the user is authorize to read.
 func AuthorizeFindScrapers(ctx context.Context, rs []influxdb.ScraperTarget) ([]influxdb.ScraperTarget, int, error) {
 	// This filters without allocating
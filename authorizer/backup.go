@@ -43,6 +43,26 @@ func (b BackupService) FetchBackupFile(ctx context.Context, backupID int, backup
 	return b.s.FetchBackupFile(ctx, backupID, backupFile, w)
 }
 
+func (b BackupService) CreateBucketBackup(ctx context.Context, bucketID influxdb.ID) (int, []string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := IsAllowedAll(ctx, influxdb.ReadAllPermissions()); err != nil {
+		return 0, nil, err
+	}
+	return b.s.CreateBucketBackup(ctx, bucketID)
+}
+
+func (b BackupService) FetchBucketBackupFile(ctx context.Context, bucketID influxdb.ID, backupID int, backupFile string, w io.Writer) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := IsAllowedAll(ctx, influxdb.ReadAllPermissions()); err != nil {
+		return err
+	}
+	return b.s.FetchBucketBackupFile(ctx, bucketID, backupID, backupFile, w)
+}
+
 func (b BackupService) InternalBackupPath(backupID int) string {
 	return b.s.InternalBackupPath(backupID)
 }
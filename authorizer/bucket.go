@@ -90,7 +90,11 @@ func (s *BucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) er
 	if _, _, err := AuthorizeCreate(ctx, influxdb.BucketsResourceType, b.OrgID); err != nil {
 		return err
 	}
-	return s.s.CreateBucket(ctx, b)
+	if err := s.s.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.BucketsResourceType, b.ID, b.OrgID, b)
+	return nil
 }
 
 // UpdateBucket checks to see if the authorizer on context has write access to the bucket provided.
@@ -102,7 +106,12 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd in
 	if _, _, err := AuthorizeWrite(ctx, influxdb.BucketsResourceType, id, b.OrgID); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateBucket(ctx, id, upd)
+	nb, err := s.s.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.BucketsResourceType, id, b.OrgID, b, nb)
+	return nb, nil
 }
 
 // DeleteBucket checks to see if the authorizer on context has write access to the bucket provided.
@@ -114,5 +123,9 @@ func (s *BucketService) DeleteBucket(ctx context.Context, id influxdb.ID) error
 	if _, _, err := AuthorizeWrite(ctx, influxdb.BucketsResourceType, id, b.OrgID); err != nil {
 		return err
 	}
-	return s.s.DeleteBucket(ctx, id)
+	if err := s.s.DeleteBucket(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.BucketsResourceType, id, b.OrgID, b)
+	return nil
 }
@@ -0,0 +1,82 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.CertificateMappingService = (*CertificateMappingService)(nil)
+
+// CertificateMappingService wraps a influxdb.CertificateMappingService and authorizes actions
+// against it appropriately. Mapping a certificate to an authorization grants the holders of
+// the certificate its permissions, so mappings are authorized as their authorization.
+type CertificateMappingService struct {
+	s  influxdb.CertificateMappingService
+	as influxdb.AuthorizationService
+}
+
+// NewCertificateMappingService constructs an instance of an authorizing certificate mapping service.
+func NewCertificateMappingService(s influxdb.CertificateMappingService, as influxdb.AuthorizationService) *CertificateMappingService {
+	return &CertificateMappingService{
+		s:  s,
+		as: as,
+	}
+}
+
+// FindCertificateMappingByID checks to see if the authorizer on context has read access to the
+// authorization of the mapping.
+func (s *CertificateMappingService) FindCertificateMappingByID(ctx context.Context, id influxdb.ID) (*influxdb.CertificateMapping, error) {
+	m, err := s.s.FindCertificateMappingByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeRead(ctx, influxdb.AuthorizationsResourceType, m.AuthorizationID, m.OrgID); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FindCertificateMappings retrieves all certificate mappings that match the provided filter and then
+// filters the list down to only the resources that are authorized.
+func (s *CertificateMappingService) FindCertificateMappings(ctx context.Context, filter influxdb.CertificateMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.CertificateMapping, int, error) {
+	ms, _, err := s.s.FindCertificateMappings(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return AuthorizeFindCertificateMappings(ctx, ms)
+}
+
+// CreateCertificateMapping checks to see if the authorizer on context has write access to the
+// authorization the certificates are mapped to.
+func (s *CertificateMappingService) CreateCertificateMapping(ctx context.Context, m *influxdb.CertificateMapping) error {
+	a, err := s.as.FindAuthorizationByID(ctx, m.AuthorizationID)
+	if err != nil {
+		return err
+	}
+	if _, _, err := AuthorizeWrite(ctx, influxdb.AuthorizationsResourceType, a.ID, a.OrgID); err != nil {
+		return err
+	}
+	if err := s.s.CreateCertificateMapping(ctx, m); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.AuthorizationsResourceType, a.ID, a.OrgID, nil, m)
+	return nil
+}
+
+// DeleteCertificateMapping checks to see if the authorizer on context has write access to the
+// authorization of the mapping.
+func (s *CertificateMappingService) DeleteCertificateMapping(ctx context.Context, id influxdb.ID) error {
+	m, err := s.FindCertificateMappingByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, _, err := AuthorizeWrite(ctx, influxdb.AuthorizationsResourceType, m.AuthorizationID, m.OrgID); err != nil {
+		return err
+	}
+	if err := s.s.DeleteCertificateMapping(ctx, id); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.AuthorizationsResourceType, m.AuthorizationID, m.OrgID, m, nil)
+	return nil
+}
@@ -66,7 +66,11 @@ func (s *CheckService) CreateCheck(ctx context.Context, chk influxdb.CheckCreate
 	if _, _, err := AuthorizeCreate(ctx, influxdb.ChecksResourceType, chk.GetOrgID()); err != nil {
 		return err
 	}
-	return s.s.CreateCheck(ctx, chk, userID)
+	if err := s.s.CreateCheck(ctx, chk, userID); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.ChecksResourceType, chk.GetID(), chk.GetOrgID(), chk)
+	return nil
 }
 
 // UpdateCheck checks to see if the authorizer on context has write access to the check provided.
@@ -78,7 +82,12 @@ func (s *CheckService) UpdateCheck(ctx context.Context, id influxdb.ID, upd infl
 	if _, _, err := AuthorizeWrite(ctx, influxdb.ChecksResourceType, chk.GetID(), chk.GetOrgID()); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateCheck(ctx, id, upd)
+	updated, err := s.s.UpdateCheck(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.ChecksResourceType, id, chk.GetOrgID(), chk, updated)
+	return updated, nil
 }
 
 // PatchCheck checks to see if the authorizer on context has write access to the check provided.
@@ -90,7 +99,12 @@ func (s *CheckService) PatchCheck(ctx context.Context, id influxdb.ID, upd influ
 	if _, _, err := AuthorizeWrite(ctx, influxdb.ChecksResourceType, chk.GetID(), chk.GetOrgID()); err != nil {
 		return nil, err
 	}
-	return s.s.PatchCheck(ctx, id, upd)
+	updated, err := s.s.PatchCheck(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.ChecksResourceType, id, chk.GetOrgID(), chk, updated)
+	return updated, nil
 }
 
 // DeleteCheck checks to see if the authorizer on context has write access to the check provided.
@@ -102,5 +116,9 @@ func (s *CheckService) DeleteCheck(ctx context.Context, id influxdb.ID) error {
 	if _, _, err := AuthorizeWrite(ctx, influxdb.ChecksResourceType, chk.GetID(), chk.GetOrgID()); err != nil {
 		return err
 	}
-	return s.s.DeleteCheck(ctx, id)
+	if err := s.s.DeleteCheck(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.ChecksResourceType, id, chk.GetOrgID(), chk)
+	return nil
 }
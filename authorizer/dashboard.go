@@ -49,7 +49,11 @@ func (s *DashboardService) CreateDashboard(ctx context.Context, b *influxdb.Dash
 	if _, _, err := AuthorizeCreate(ctx, influxdb.DashboardsResourceType, b.OrganizationID); err != nil {
 		return err
 	}
-	return s.s.CreateDashboard(ctx, b)
+	if err := s.s.CreateDashboard(ctx, b); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.DashboardsResourceType, b.ID, b.OrganizationID, b)
+	return nil
 }
 
 // UpdateDashboard checks to see if the authorizer on context has write access to the dashboard provided.
@@ -61,7 +65,12 @@ func (s *DashboardService) UpdateDashboard(ctx context.Context, id influxdb.ID,
 	if _, _, err := AuthorizeWrite(ctx, influxdb.DashboardsResourceType, id, b.OrganizationID); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateDashboard(ctx, id, upd)
+	updated, err := s.s.UpdateDashboard(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.DashboardsResourceType, id, b.OrganizationID, b, updated)
+	return updated, nil
 }
 
 // DeleteDashboard checks to see if the authorizer on context has write access to the dashboard provided.
@@ -73,7 +82,11 @@ func (s *DashboardService) DeleteDashboard(ctx context.Context, id influxdb.ID)
 	if _, _, err := AuthorizeWrite(ctx, influxdb.DashboardsResourceType, id, b.OrganizationID); err != nil {
 		return err
 	}
-	return s.s.DeleteDashboard(ctx, id)
+	if err := s.s.DeleteDashboard(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.DashboardsResourceType, id, b.OrganizationID, b)
+	return nil
 }
 
 func (s *DashboardService) AddDashboardCell(ctx context.Context, id influxdb.ID, c *influxdb.Cell, opts influxdb.AddDashboardCellOptions) error {
@@ -84,7 +97,10 @@ func (s *DashboardService) AddDashboardCell(ctx context.Context, id influxdb.ID,
 	if _, _, err := AuthorizeWrite(ctx, influxdb.DashboardsResourceType, id, b.OrganizationID); err != nil {
 		return err
 	}
-	return s.s.AddDashboardCell(ctx, id, c, opts)
+	if err := s.s.AddDashboardCell(ctx, id, c, opts); err != nil {
+		return err
+	}
+	return s.auditDashboardUpdate(ctx, b)
 }
 
 func (s *DashboardService) RemoveDashboardCell(ctx context.Context, dashboardID influxdb.ID, cellID influxdb.ID) error {
@@ -95,7 +111,10 @@ func (s *DashboardService) RemoveDashboardCell(ctx context.Context, dashboardID
 	if _, _, err := AuthorizeWrite(ctx, influxdb.DashboardsResourceType, dashboardID, b.OrganizationID); err != nil {
 		return err
 	}
-	return s.s.RemoveDashboardCell(ctx, dashboardID, cellID)
+	if err := s.s.RemoveDashboardCell(ctx, dashboardID, cellID); err != nil {
+		return err
+	}
+	return s.auditDashboardUpdate(ctx, b)
 }
 
 func (s *DashboardService) UpdateDashboardCell(ctx context.Context, dashboardID influxdb.ID, cellID influxdb.ID, upd influxdb.CellUpdate) (*influxdb.Cell, error) {
@@ -106,7 +125,14 @@ func (s *DashboardService) UpdateDashboardCell(ctx context.Context, dashboardID
 	if _, _, err := AuthorizeWrite(ctx, influxdb.DashboardsResourceType, dashboardID, b.OrganizationID); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateDashboardCell(ctx, dashboardID, cellID, upd)
+	cell, err := s.s.UpdateDashboardCell(ctx, dashboardID, cellID, upd)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditDashboardUpdate(ctx, b); err != nil {
+		return nil, err
+	}
+	return cell, nil
 }
 
 func (s *DashboardService) GetDashboardCellView(ctx context.Context, dashboardID influxdb.ID, cellID influxdb.ID) (*influxdb.View, error) {
@@ -128,7 +154,14 @@ func (s *DashboardService) UpdateDashboardCellView(ctx context.Context, dashboar
 	if _, _, err := AuthorizeWrite(ctx, influxdb.DashboardsResourceType, dashboardID, b.OrganizationID); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateDashboardCellView(ctx, dashboardID, cellID, upd)
+	view, err := s.s.UpdateDashboardCellView(ctx, dashboardID, cellID, upd)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditDashboardUpdate(ctx, b); err != nil {
+		return nil, err
+	}
+	return view, nil
 }
 
 func (s *DashboardService) ReplaceDashboardCells(ctx context.Context, id influxdb.ID, c []*influxdb.Cell) error {
@@ -139,5 +172,21 @@ func (s *DashboardService) ReplaceDashboardCells(ctx context.Context, id influxd
 	if _, _, err := AuthorizeWrite(ctx, influxdb.DashboardsResourceType, id, b.OrganizationID); err != nil {
 		return err
 	}
-	return s.s.ReplaceDashboardCells(ctx, id, c)
+	if err := s.s.ReplaceDashboardCells(ctx, id, c); err != nil {
+		return err
+	}
+	return s.auditDashboardUpdate(ctx, b)
+}
+
+// auditDashboardUpdate records an update of the cells of a dashboard, looking up the dashboard as updated.
+func (s *DashboardService) auditDashboardUpdate(ctx context.Context, before *influxdb.Dashboard) error {
+	if !auditing(ctx) {
+		return nil
+	}
+	after, err := s.s.FindDashboardByID(ctx, before.ID)
+	if err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.DashboardsResourceType, before.ID, before.OrganizationID, before, after)
+	return nil
 }
@@ -0,0 +1,90 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.DashboardShareService = (*DashboardShareService)(nil)
+
+// DashboardShareService wraps a influxdb.DashboardShareService and authorizes actions
+// against it appropriately. Share links are authorized as their dashboard.
+type DashboardShareService struct {
+	s  influxdb.DashboardShareService
+	ds influxdb.DashboardService
+}
+
+// NewDashboardShareService constructs an instance of an authorizing dashboard share service.
+func NewDashboardShareService(s influxdb.DashboardShareService, ds influxdb.DashboardService) *DashboardShareService {
+	return &DashboardShareService{
+		s:  s,
+		ds: ds,
+	}
+}
+
+// FindDashboardShareByID checks to see if the authorizer on context has read access to the
+// dashboard of the share link.
+func (s *DashboardShareService) FindDashboardShareByID(ctx context.Context, id influxdb.ID) (*influxdb.DashboardShare, error) {
+	ds, err := s.s.FindDashboardShareByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := AuthorizeRead(ctx, influxdb.DashboardsResourceType, ds.DashboardID, ds.OrgID); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+// FindDashboardShareByToken is used to authenticate requests made with a share link,
+// it is not authorized as the authorizer is not known yet.
+func (s *DashboardShareService) FindDashboardShareByToken(ctx context.Context, token string) (*influxdb.DashboardShare, error) {
+	return s.s.FindDashboardShareByToken(ctx, token)
+}
+
+// FindDashboardShares retrieves all share links that match the provided filter and then
+// filters the list down to only the resources that are authorized.
+func (s *DashboardShareService) FindDashboardShares(ctx context.Context, filter influxdb.DashboardShareFilter) ([]*influxdb.DashboardShare, int, error) {
+	ds, _, err := s.s.FindDashboardShares(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return AuthorizeFindDashboardShares(ctx, ds)
+}
+
+// CreateDashboardShare checks to see if the authorizer on context has write access to the
+// shared dashboard.
+func (s *DashboardShareService) CreateDashboardShare(ctx context.Context, ds *influxdb.DashboardShare) error {
+	d, err := s.ds.FindDashboardByID(ctx, ds.DashboardID)
+	if err != nil {
+		return err
+	}
+	if _, _, err := AuthorizeWrite(ctx, influxdb.DashboardsResourceType, d.ID, d.OrganizationID); err != nil {
+		return err
+	}
+	if err := s.s.CreateDashboardShare(ctx, ds); err != nil {
+		return err
+	}
+	// the token is left out of the audit log
+	logged := *ds
+	logged.Token = ""
+	auditUpdate(ctx, influxdb.DashboardsResourceType, d.ID, d.OrganizationID, nil, &logged)
+	return nil
+}
+
+// DeleteDashboardShare checks to see if the authorizer on context has write access to the
+// dashboard of the share link.
+func (s *DashboardShareService) DeleteDashboardShare(ctx context.Context, id influxdb.ID) error {
+	ds, err := s.FindDashboardShareByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, _, err := AuthorizeWrite(ctx, influxdb.DashboardsResourceType, ds.DashboardID, ds.OrgID); err != nil {
+		return err
+	}
+	if err := s.s.DeleteDashboardShare(ctx, id); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.DashboardsResourceType, ds.DashboardID, ds.OrgID, ds, nil)
+	return nil
+}
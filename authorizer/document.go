@@ -71,7 +71,11 @@ func (s *documentStore) CreateDocument(ctx context.Context, d *influxdb.Document
 	if err := IsAllowedAny(ctx, ps); err != nil {
 		return err
 	}
-	return s.s.CreateDocument(ctx, d)
+	if err := s.s.CreateDocument(ctx, d); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.DocumentsResourceType, d.ID, documentOrgID(d), d)
+	return nil
 }
 
 func (s *documentStore) FindDocument(ctx context.Context, id influxdb.ID) (*influxdb.Document, error) {
@@ -105,7 +109,17 @@ func (s *documentStore) UpdateDocument(ctx context.Context, d *influxdb.Document
 	if err := IsAllowedAny(ctx, ps); err != nil {
 		return err
 	}
-	return s.s.UpdateDocument(ctx, d)
+	var before *influxdb.Document
+	if auditing(ctx) {
+		if before, err = s.s.FindDocument(ctx, d.ID); err != nil {
+			return err
+		}
+	}
+	if err := s.s.UpdateDocument(ctx, d); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.DocumentsResourceType, d.ID, documentOrgID(d), before, d)
+	return nil
 }
 
 func (s *documentStore) DeleteDocument(ctx context.Context, id influxdb.ID) error {
@@ -120,7 +134,11 @@ func (s *documentStore) DeleteDocument(ctx context.Context, id influxdb.ID) erro
 	if err := IsAllowedAny(ctx, ps); err != nil {
 		return err
 	}
-	return s.s.DeleteDocument(ctx, id)
+	if err := s.s.DeleteDocument(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.DocumentsResourceType, id, documentOrgID(d), d)
+	return nil
 }
 
 func (s *documentStore) findDocs(ctx context.Context, action influxdb.Action, opts ...influxdb.DocumentFindOptions) ([]*influxdb.Document, error) {
@@ -167,9 +185,28 @@ func (s *documentStore) DeleteDocuments(ctx context.Context, opts ...influxdb.Do
 	for i, d := range ds {
 		ids[i] = d.ID
 	}
-	return s.s.DeleteDocuments(ctx,
+	err = s.s.DeleteDocuments(ctx,
 		func(_ influxdb.DocumentIndex, _ influxdb.DocumentDecorator) (ids []influxdb.ID, e error) {
 			return ids, nil
 		},
 	)
+	if err != nil {
+		return err
+	}
+	for _, d := range ds {
+		auditDelete(ctx, influxdb.DocumentsResourceType, d.ID, documentOrgID(d), d)
+	}
+	return nil
+}
+
+// documentOrgID returns the organization of a document for its audit events,
+// which is unset when the document belongs to many.
+func documentOrgID(d *influxdb.Document) influxdb.ID {
+	if len(d.Organizations) != 1 {
+		return 0
+	}
+	for orgID := range d.Organizations {
+		return orgID
+	}
+	return 0
 }
@@ -79,7 +79,11 @@ func (s *LabelService) CreateLabel(ctx context.Context, l *influxdb.Label) error
 	if _, _, err := AuthorizeCreate(ctx, influxdb.LabelsResourceType, l.OrgID); err != nil {
 		return err
 	}
-	return s.s.CreateLabel(ctx, l)
+	if err := s.s.CreateLabel(ctx, l); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.LabelsResourceType, l.ID, l.OrgID, l)
+	return nil
 }
 
 // CreateLabelMapping checks to see if the authorizer on context has write access to the label and the resource contained by the label mapping in creation.
@@ -94,7 +98,11 @@ func (s *LabelService) CreateLabelMapping(ctx context.Context, m *influxdb.Label
 	if _, _, err := AuthorizeWrite(ctx, m.ResourceType, m.ResourceID, l.OrgID); err != nil {
 		return err
 	}
-	return s.s.CreateLabelMapping(ctx, m)
+	if err := s.s.CreateLabelMapping(ctx, m); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.LabelsResourceType, m.LabelID, l.OrgID, nil, m)
+	return nil
 }
 
 // UpdateLabel checks to see if the authorizer on context has write access to the label provided.
@@ -106,7 +114,12 @@ func (s *LabelService) UpdateLabel(ctx context.Context, id influxdb.ID, upd infl
 	if _, _, err := AuthorizeWrite(ctx, influxdb.LabelsResourceType, l.ID, l.OrgID); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateLabel(ctx, id, upd)
+	updated, err := s.s.UpdateLabel(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.LabelsResourceType, id, l.OrgID, l, updated)
+	return updated, nil
 }
 
 // DeleteLabel checks to see if the authorizer on context has write access to the label provided.
@@ -118,7 +131,11 @@ func (s *LabelService) DeleteLabel(ctx context.Context, id influxdb.ID) error {
 	if _, _, err := AuthorizeWrite(ctx, influxdb.LabelsResourceType, l.ID, l.OrgID); err != nil {
 		return err
 	}
-	return s.s.DeleteLabel(ctx, id)
+	if err := s.s.DeleteLabel(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.LabelsResourceType, id, l.OrgID, l)
+	return nil
 }
 
 // DeleteLabelMapping checks to see if the authorizer on context has write access to the label and the resource of the label mapping to delete.
@@ -133,5 +150,9 @@ func (s *LabelService) DeleteLabelMapping(ctx context.Context, m *influxdb.Label
 	if _, _, err := AuthorizeWrite(ctx, m.ResourceType, m.ResourceID, l.OrgID); err != nil {
 		return err
 	}
-	return s.s.DeleteLabelMapping(ctx, m)
+	if err := s.s.DeleteLabelMapping(ctx, m); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.LabelsResourceType, m.LabelID, l.OrgID, m, nil)
+	return nil
 }
@@ -0,0 +1,71 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.MFAService = (*MFAService)(nil)
+
+// MFAService wraps a influxdb.MFAService and authorizes actions
+// against it appropriately.
+type MFAService struct {
+	s influxdb.MFAService
+}
+
+// NewMFAService constructs an instance of an authorizing mfa service.
+func NewMFAService(s influxdb.MFAService) *MFAService {
+	return &MFAService{
+		s: s,
+	}
+}
+
+// MFAEnabled checks to see if the authorizer on context has read access to the user.
+func (s *MFAService) MFAEnabled(ctx context.Context, userID influxdb.ID) (bool, error) {
+	if _, _, err := AuthorizeReadResource(ctx, influxdb.UsersResourceType, userID); err != nil {
+		return false, err
+	}
+	return s.s.MFAEnabled(ctx, userID)
+}
+
+// EnrollMFA checks to see if the authorizer on context has write access to the user.
+func (s *MFAService) EnrollMFA(ctx context.Context, userID influxdb.ID, code string) (*influxdb.MFAEnrollment, error) {
+	if _, _, err := AuthorizeWriteResource(ctx, influxdb.UsersResourceType, userID); err != nil {
+		return nil, err
+	}
+	return s.s.EnrollMFA(ctx, userID, code)
+}
+
+// ConfirmMFA checks to see if the authorizer on context has write access to the user.
+func (s *MFAService) ConfirmMFA(ctx context.Context, userID influxdb.ID, code string) error {
+	if _, _, err := AuthorizeWriteResource(ctx, influxdb.UsersResourceType, userID); err != nil {
+		return err
+	}
+	if err := s.s.ConfirmMFA(ctx, userID, code); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.UsersResourceType, userID, 0, nil, nil)
+	return nil
+}
+
+// VerifyMFA checks to see if the authorizer on context has write access to the user,
+// as verifying a code uses it up.
+func (s *MFAService) VerifyMFA(ctx context.Context, userID influxdb.ID, code string) error {
+	if _, _, err := AuthorizeWriteResource(ctx, influxdb.UsersResourceType, userID); err != nil {
+		return err
+	}
+	return s.s.VerifyMFA(ctx, userID, code)
+}
+
+// DisableMFA checks to see if the authorizer on context has write access to the user.
+func (s *MFAService) DisableMFA(ctx context.Context, userID influxdb.ID, code string) error {
+	if _, _, err := AuthorizeWriteResource(ctx, influxdb.UsersResourceType, userID); err != nil {
+		return err
+	}
+	if err := s.s.DisableMFA(ctx, userID, code); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.UsersResourceType, userID, 0, nil, nil)
+	return nil
+}
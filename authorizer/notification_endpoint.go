@@ -65,7 +65,11 @@ func (s *NotificationEndpointService) CreateNotificationEndpoint(ctx context.Con
 	if _, _, err := AuthorizeCreate(ctx, influxdb.NotificationEndpointResourceType, edp.GetOrgID()); err != nil {
 		return err
 	}
-	return s.s.CreateNotificationEndpoint(ctx, edp, userID)
+	if err := s.s.CreateNotificationEndpoint(ctx, edp, userID); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.NotificationEndpointResourceType, edp.GetID(), edp.GetOrgID(), edp)
+	return nil
 }
 
 // UpdateNotificationEndpoint checks to see if the authorizer on context has write access to the notification endpoint provided.
@@ -77,7 +81,12 @@ func (s *NotificationEndpointService) UpdateNotificationEndpoint(ctx context.Con
 	if _, _, err := AuthorizeWrite(ctx, influxdb.NotificationEndpointResourceType, edp.GetID(), edp.GetOrgID()); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateNotificationEndpoint(ctx, id, upd, userID)
+	updated, err := s.s.UpdateNotificationEndpoint(ctx, id, upd, userID)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.NotificationEndpointResourceType, id, edp.GetOrgID(), edp, updated)
+	return updated, nil
 }
 
 // PatchNotificationEndpoint checks to see if the authorizer on context has write access to the notification endpoint provided.
@@ -89,7 +98,12 @@ func (s *NotificationEndpointService) PatchNotificationEndpoint(ctx context.Cont
 	if _, _, err := AuthorizeWrite(ctx, influxdb.NotificationEndpointResourceType, edp.GetID(), edp.GetOrgID()); err != nil {
 		return nil, err
 	}
-	return s.s.PatchNotificationEndpoint(ctx, id, upd)
+	updated, err := s.s.PatchNotificationEndpoint(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.NotificationEndpointResourceType, id, edp.GetOrgID(), edp, updated)
+	return updated, nil
 }
 
 // DeleteNotificationEndpoint checks to see if the authorizer on context has write access to the notification endpoint provided.
@@ -101,5 +115,10 @@ func (s *NotificationEndpointService) DeleteNotificationEndpoint(ctx context.Con
 	if _, _, err := AuthorizeWrite(ctx, influxdb.NotificationEndpointResourceType, edp.GetID(), edp.GetOrgID()); err != nil {
 		return nil, 0, err
 	}
-	return s.s.DeleteNotificationEndpoint(ctx, id)
+	flds, orgID, err := s.s.DeleteNotificationEndpoint(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	auditDelete(ctx, influxdb.NotificationEndpointResourceType, id, edp.GetOrgID(), edp)
+	return flds, orgID, nil
 }
@@ -53,7 +53,11 @@ func (s *NotificationRuleStore) CreateNotificationRule(ctx context.Context, nr i
 	if _, _, err := AuthorizeCreate(ctx, influxdb.NotificationRuleResourceType, nr.GetOrgID()); err != nil {
 		return err
 	}
-	return s.s.CreateNotificationRule(ctx, nr, userID)
+	if err := s.s.CreateNotificationRule(ctx, nr, userID); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.NotificationRuleResourceType, nr.GetID(), nr.GetOrgID(), nr.NotificationRule)
+	return nil
 }
 
 // UpdateNotificationRule checks to see if the authorizer on context has write access to the notification rule provided.
@@ -65,7 +69,12 @@ func (s *NotificationRuleStore) UpdateNotificationRule(ctx context.Context, id i
 	if _, _, err := AuthorizeWrite(ctx, influxdb.NotificationRuleResourceType, nr.GetID(), nr.GetOrgID()); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateNotificationRule(ctx, id, upd, userID)
+	updated, err := s.s.UpdateNotificationRule(ctx, id, upd, userID)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.NotificationRuleResourceType, id, nr.GetOrgID(), nr, updated)
+	return updated, nil
 }
 
 // PatchNotificationRule checks to see if the authorizer on context has write access to the notification rule provided.
@@ -77,7 +86,12 @@ func (s *NotificationRuleStore) PatchNotificationRule(ctx context.Context, id in
 	if _, _, err := AuthorizeWrite(ctx, influxdb.NotificationRuleResourceType, nr.GetID(), nr.GetOrgID()); err != nil {
 		return nil, err
 	}
-	return s.s.PatchNotificationRule(ctx, id, upd)
+	updated, err := s.s.PatchNotificationRule(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.NotificationRuleResourceType, id, nr.GetOrgID(), nr, updated)
+	return updated, nil
 }
 
 // DeleteNotificationRule checks to see if the authorizer on context has write access to the notification rule provided.
@@ -89,5 +103,9 @@ func (s *NotificationRuleStore) DeleteNotificationRule(ctx context.Context, id i
 	if _, _, err := AuthorizeWrite(ctx, influxdb.NotificationRuleResourceType, nr.GetID(), nr.GetOrgID()); err != nil {
 		return err
 	}
-	return s.s.DeleteNotificationRule(ctx, id)
+	if err := s.s.DeleteNotificationRule(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.NotificationRuleResourceType, id, nr.GetOrgID(), nr)
+	return nil
 }
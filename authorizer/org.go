@@ -57,7 +57,11 @@ func (s *OrgService) CreateOrganization(ctx context.Context, o *influxdb.Organiz
 	if _, _, err := AuthorizeWriteGlobal(ctx, influxdb.OrgsResourceType); err != nil {
 		return err
 	}
-	return s.s.CreateOrganization(ctx, o)
+	if err := s.s.CreateOrganization(ctx, o); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.OrgsResourceType, o.ID, o.ID, o)
+	return nil
 }
 
 // UpdateOrganization checks to see if the authorizer on context has write access to the organization provided.
@@ -65,7 +69,16 @@ func (s *OrgService) UpdateOrganization(ctx context.Context, id influxdb.ID, upd
 	if _, _, err := AuthorizeWriteOrg(ctx, id); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateOrganization(ctx, id, upd)
+	before, err := s.auditedOrganization(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	o, err := s.s.UpdateOrganization(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.OrgsResourceType, id, id, before, o)
+	return o, nil
 }
 
 // DeleteOrganization checks to see if the authorizer on context has write access to the organization provided.
@@ -73,5 +86,21 @@ func (s *OrgService) DeleteOrganization(ctx context.Context, id influxdb.ID) err
 	if _, _, err := AuthorizeWriteOrg(ctx, id); err != nil {
 		return err
 	}
-	return s.s.DeleteOrganization(ctx, id)
+	before, err := s.auditedOrganization(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.s.DeleteOrganization(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.OrgsResourceType, id, id, before)
+	return nil
+}
+
+// auditedOrganization looks up the organization to record in audit events, when operations are audited.
+func (s *OrgService) auditedOrganization(ctx context.Context, id influxdb.ID) (*influxdb.Organization, error) {
+	if !auditing(ctx) {
+		return nil, nil
+	}
+	return s.s.FindOrganizationByID(ctx, id)
 }
@@ -21,7 +21,13 @@ func (s *PasswordService) SetPassword(ctx context.Context, userID influxdb.ID, p
 	if _, _, err := AuthorizeWriteResource(ctx, influxdb.UsersResourceType, userID); err != nil {
 		return err
 	}
-	return s.next.SetPassword(ctx, userID, password)
+	if err := s.next.SetPassword(ctx, userID, password); err != nil {
+		return err
+	}
+	// users do not belong to an organization and passwords are never recorded,
+	// the event only notes that the password of the user was set.
+	auditUpdate(ctx, influxdb.UsersResourceType, userID, 0, nil, nil)
+	return nil
 }
 
 // ComparePassword checks if the password matches the password recorded.
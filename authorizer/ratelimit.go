@@ -0,0 +1,81 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.RateLimitService = (*RateLimitService)(nil)
+
+// RateLimitService wraps a influxdb.RateLimitService and authorizes actions
+// against it appropriately.
+type RateLimitService struct {
+	s influxdb.RateLimitService
+}
+
+// NewRateLimitService constructs an instance of an authorizing rate limit service.
+func NewRateLimitService(s influxdb.RateLimitService) *RateLimitService {
+	return &RateLimitService{
+		s: s,
+	}
+}
+
+// FindRateLimits checks to see if the authorizer on context has read access to the
+// organization of the limits, or to all organizations when the filter has none.
+func (s *RateLimitService) FindRateLimits(ctx context.Context, filter influxdb.RateLimitFilter) ([]*influxdb.RateLimit, error) {
+	if filter.OrgID != nil {
+		if _, _, err := AuthorizeReadOrg(ctx, *filter.OrgID); err != nil {
+			return nil, err
+		}
+	} else if _, _, err := AuthorizeReadGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return nil, err
+	}
+	return s.s.FindRateLimits(ctx, filter)
+}
+
+// SetRateLimit checks to see if the authorizer on context has write access to all
+// organizations. Members of an organization may not lift the limits set on it.
+func (s *RateLimitService) SetRateLimit(ctx context.Context, l *influxdb.RateLimit) error {
+	if _, _, err := AuthorizeWriteGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return err
+	}
+	before, err := s.auditedRateLimit(ctx, l.OrgID, l.AuthorizationID)
+	if err != nil {
+		return err
+	}
+	if err := s.s.SetRateLimit(ctx, l); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.OrgsResourceType, l.OrgID, l.OrgID, before, l)
+	return nil
+}
+
+// DeleteRateLimit checks to see if the authorizer on context has write access to all organizations.
+func (s *RateLimitService) DeleteRateLimit(ctx context.Context, orgID, authID influxdb.ID) error {
+	if _, _, err := AuthorizeWriteGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return err
+	}
+	before, err := s.auditedRateLimit(ctx, orgID, authID)
+	if err != nil {
+		return err
+	}
+	if err := s.s.DeleteRateLimit(ctx, orgID, authID); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.OrgsResourceType, orgID, orgID, before, nil)
+	return nil
+}
+
+// auditedRateLimit returns the limit being changed, if any, when operations are audited.
+// Changes to limits are recorded as updates of their organization.
+func (s *RateLimitService) auditedRateLimit(ctx context.Context, orgID, authID influxdb.ID) (*influxdb.RateLimit, error) {
+	if !auditing(ctx) {
+		return nil, nil
+	}
+	limits, err := s.s.FindRateLimits(ctx, influxdb.RateLimitFilter{OrgID: &orgID, AuthorizationID: &authID})
+	if err != nil || len(limits) == 0 {
+		return nil, err
+	}
+	return limits[0], nil
+}
@@ -59,7 +59,11 @@ func (s *ScraperTargetStoreService) AddTarget(ctx context.Context, st *influxdb.
 	if _, _, err := AuthorizeWrite(ctx, influxdb.BucketsResourceType, st.BucketID, st.OrgID); err != nil {
 		return err
 	}
-	return s.s.AddTarget(ctx, st, userID)
+	if err := s.s.AddTarget(ctx, st, userID); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.ScraperResourceType, st.ID, st.OrgID, st)
+	return nil
 }
 
 // UpdateTarget checks to see if the authorizer on context has write access to the scraper target provided.
@@ -74,7 +78,12 @@ func (s *ScraperTargetStoreService) UpdateTarget(ctx context.Context, upd *influ
 	if _, _, err := AuthorizeWrite(ctx, influxdb.BucketsResourceType, st.BucketID, st.OrgID); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateTarget(ctx, upd, userID)
+	updated, err := s.s.UpdateTarget(ctx, upd, userID)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.ScraperResourceType, upd.ID, st.OrgID, st, updated)
+	return updated, nil
 }
 
 // RemoveTarget checks to see if the authorizer on context has write access to the scraper target provided.
@@ -86,5 +95,9 @@ func (s *ScraperTargetStoreService) RemoveTarget(ctx context.Context, id influxd
 	if _, _, err := AuthorizeWrite(ctx, influxdb.ScraperResourceType, st.ID, st.OrgID); err != nil {
 		return err
 	}
-	return s.s.RemoveTarget(ctx, id)
+	if err := s.s.RemoveTarget(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.ScraperResourceType, id, st.OrgID, st)
+	return nil
 }
@@ -2,6 +2,7 @@ package authorizer
 
 import (
 	"context"
+	"sort"
 
 	"github.com/influxdata/influxdb/v2"
 )
@@ -54,6 +55,7 @@ func (s *SecretService) PutSecret(ctx context.Context, orgID influxdb.ID, key st
 	if err != nil {
 		return err
 	}
+	auditUpdate(ctx, influxdb.SecretsResourceType, 0, orgID, nil, []string{key})
 	return nil
 }
 
@@ -71,6 +73,7 @@ func (s *SecretService) PutSecrets(ctx context.Context, orgID influxdb.ID, m map
 	if err != nil {
 		return err
 	}
+	auditUpdate(ctx, influxdb.SecretsResourceType, 0, orgID, nil, secretKeys(m))
 	return nil
 }
 
@@ -83,6 +86,7 @@ func (s *SecretService) PatchSecrets(ctx context.Context, orgID influxdb.ID, m m
 	if err != nil {
 		return err
 	}
+	auditUpdate(ctx, influxdb.SecretsResourceType, 0, orgID, nil, secretKeys(m))
 	return nil
 }
 
@@ -95,5 +99,17 @@ func (s *SecretService) DeleteSecret(ctx context.Context, orgID influxdb.ID, key
 	if err != nil {
 		return err
 	}
+	auditUpdate(ctx, influxdb.SecretsResourceType, 0, orgID, keys, nil)
 	return nil
 }
+
+// secretKeys returns the sorted keys of the secrets, only the keys of secrets
+// are recorded in the audit log, never their values.
+func secretKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
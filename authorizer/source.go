@@ -60,7 +60,11 @@ func (s *SourceService) CreateSource(ctx context.Context, src *influxdb.Source)
 	if _, _, err := AuthorizeCreate(ctx, influxdb.SourcesResourceType, src.OrganizationID); err != nil {
 		return err
 	}
-	return s.s.CreateSource(ctx, src)
+	if err := s.s.CreateSource(ctx, src); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.SourcesResourceType, src.ID, src.OrganizationID, src)
+	return nil
 }
 
 // UpdateSource checks to see if the authorizer on context has write access to the source provided.
@@ -72,7 +76,12 @@ func (s *SourceService) UpdateSource(ctx context.Context, id influxdb.ID, upd in
 	if _, _, err := AuthorizeWrite(ctx, influxdb.SourcesResourceType, src.ID, src.OrganizationID); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateSource(ctx, id, upd)
+	updated, err := s.s.UpdateSource(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.SourcesResourceType, id, src.OrganizationID, src, updated)
+	return updated, nil
 }
 
 // DeleteSource checks to see if the authorizer on context has write access to the source provided.
@@ -84,5 +93,9 @@ func (s *SourceService) DeleteSource(ctx context.Context, id influxdb.ID) error
 	if _, _, err := AuthorizeWrite(ctx, influxdb.SourcesResourceType, src.ID, src.OrganizationID); err != nil {
 		return err
 	}
-	return s.s.DeleteSource(ctx, id)
+	if err := s.s.DeleteSource(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.SourcesResourceType, id, src.OrganizationID, src)
+	return nil
 }
@@ -100,7 +100,12 @@ func (ts *taskServiceValidator) CreateTask(ctx context.Context, t influxdb.TaskC
 	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
 		return nil, err
 	}
-	return ts.TaskService.CreateTask(ctx, t)
+	task, err := ts.TaskService.CreateTask(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	auditCreate(ctx, influxdb.TasksResourceType, task.ID, task.OrganizationID, task)
+	return task, nil
 }
 
 func (ts *taskServiceValidator) UpdateTask(ctx context.Context, id influxdb.ID, upd influxdb.TaskUpdate) (*influxdb.Task, error) {
@@ -118,7 +123,12 @@ func (ts *taskServiceValidator) UpdateTask(ctx context.Context, id influxdb.ID,
 	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
 		return nil, err
 	}
-	return ts.TaskService.UpdateTask(ctx, id, upd)
+	updated, err := ts.TaskService.UpdateTask(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.TasksResourceType, id, task.OrganizationID, task, updated)
+	return updated, nil
 }
 
 func (ts *taskServiceValidator) DeleteTask(ctx context.Context, id influxdb.ID) error {
@@ -136,7 +146,11 @@ func (ts *taskServiceValidator) DeleteTask(ctx context.Context, id influxdb.ID)
 	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
 		return err
 	}
-	return ts.TaskService.DeleteTask(ctx, id)
+	if err := ts.TaskService.DeleteTask(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.TasksResourceType, id, task.OrganizationID, task)
+	return nil
 }
 
 func (ts *taskServiceValidator) FindLogs(ctx context.Context, filter influxdb.LogFilter) ([]*influxdb.Log, int, error) {
@@ -204,7 +218,11 @@ func (ts *taskServiceValidator) CancelRun(ctx context.Context, taskID, runID inf
 	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
 		return err
 	}
-	return ts.TaskService.CancelRun(ctx, taskID, runID)
+	if err := ts.TaskService.CancelRun(ctx, taskID, runID); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.TasksResourceType, taskID, task.OrganizationID, nil, nil)
+	return nil
 }
 
 func (ts *taskServiceValidator) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
@@ -226,7 +244,12 @@ func (ts *taskServiceValidator) RetryRun(ctx context.Context, taskID, runID infl
 	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
 		return nil, err
 	}
-	return ts.TaskService.RetryRun(ctx, taskID, runID)
+	run, err := ts.TaskService.RetryRun(ctx, taskID, runID)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.TasksResourceType, taskID, task.OrganizationID, nil, run)
+	return run, nil
 }
 
 func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
@@ -248,5 +271,10 @@ func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID influxdb.ID
 	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
 		return nil, err
 	}
-	return ts.TaskService.ForceRun(ctx, taskID, scheduledFor)
+	run, err := ts.TaskService.ForceRun(ctx, taskID, scheduledFor)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.TasksResourceType, taskID, task.OrganizationID, nil, run)
+	return run, nil
 }
@@ -51,7 +51,11 @@ func (s *TelegrafConfigService) CreateTelegrafConfig(ctx context.Context, tc *in
 	if _, _, err := AuthorizeCreate(ctx, influxdb.TelegrafsResourceType, tc.OrgID); err != nil {
 		return err
 	}
-	return s.s.CreateTelegrafConfig(ctx, tc, userID)
+	if err := s.s.CreateTelegrafConfig(ctx, tc, userID); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.TelegrafsResourceType, tc.ID, tc.OrgID, tc)
+	return nil
 }
 
 // UpdateTelegrafConfig checks to see if the authorizer on context has write access to the telegraf config provided.
@@ -63,7 +67,12 @@ func (s *TelegrafConfigService) UpdateTelegrafConfig(ctx context.Context, id inf
 	if _, _, err := AuthorizeWrite(ctx, influxdb.TelegrafsResourceType, tc.ID, tc.OrgID); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateTelegrafConfig(ctx, id, upd, userID)
+	updated, err := s.s.UpdateTelegrafConfig(ctx, id, upd, userID)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.TelegrafsResourceType, id, tc.OrgID, tc, updated)
+	return updated, nil
 }
 
 // DeleteTelegrafConfig checks to see if the authorizer on context has write access to the telegraf config provided.
@@ -75,5 +84,9 @@ func (s *TelegrafConfigService) DeleteTelegrafConfig(ctx context.Context, id inf
 	if _, _, err := AuthorizeWrite(ctx, influxdb.TelegrafsResourceType, tc.ID, tc.OrgID); err != nil {
 		return err
 	}
-	return s.s.DeleteTelegrafConfig(ctx, id)
+	if err := s.s.DeleteTelegrafConfig(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.TelegrafsResourceType, id, tc.OrgID, tc)
+	return nil
 }
@@ -13,6 +13,11 @@ type OrganizationService interface {
 type URMService struct {
 	s          influxdb.UserResourceMappingService
 	orgService OrganizationService
+
+	// RoleService finds the roles users are made members of, whose permissions
+	// the authorizer on context must be allowed. Users cannot be made members
+	// of roles when it is nil.
+	RoleService influxdb.RoleService
 }
 
 func NewURMService(orgSvc OrganizationService, s influxdb.UserResourceMappingService) *URMService {
@@ -38,7 +43,32 @@ func (s *URMService) CreateUserResourceMapping(ctx context.Context, m *influxdb.
 	if _, _, err := AuthorizeWrite(ctx, m.ResourceType, m.ResourceID, orgID); err != nil {
 		return err
 	}
-	return s.s.CreateUserResourceMapping(ctx, m)
+	if m.ResourceType == influxdb.RolesResourceType {
+		if err := s.verifyRole(ctx, m.ResourceID); err != nil {
+			return err
+		}
+	}
+	if err := s.s.CreateUserResourceMapping(ctx, m); err != nil {
+		return err
+	}
+	auditUpdate(ctx, m.ResourceType, m.ResourceID, orgID, nil, m)
+	return nil
+}
+
+// verifyRole ensures that the authorizer on context is allowed all of the
+// permissions of the role, so it cannot grant more than it holds.
+func (s *URMService) verifyRole(ctx context.Context, id influxdb.ID) error {
+	if s.RoleService == nil {
+		return &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  "users cannot be made members of roles",
+		}
+	}
+	r, err := s.RoleService.FindRoleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return VerifyPermissions(ctx, r.Permissions)
 }
 
 func (s *URMService) DeleteUserResourceMapping(ctx context.Context, resourceID influxdb.ID, userID influxdb.ID) error {
@@ -59,6 +89,7 @@ func (s *URMService) DeleteUserResourceMapping(ctx context.Context, resourceID i
 		if err := s.s.DeleteUserResourceMapping(ctx, urm.ResourceID, urm.UserID); err != nil {
 			return err
 		}
+		auditUpdate(ctx, urm.ResourceType, urm.ResourceID, orgID, urm, nil)
 	}
 	return nil
 }
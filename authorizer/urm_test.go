@@ -256,3 +256,59 @@ func TestURMService_WriteUserResourceMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestURMService_CreateRoleMapping(t *testing.T) {
+	orgID, roleID := influxdb.ID(10), influxdb.ID(1)
+	rolesWrite := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.RolesResourceType, OrgID: &orgID},
+	}
+	bucketsWrite := influxdb.Permission{
+		Action:   influxdb.WriteAction,
+		Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID},
+	}
+
+	var created int
+	urms := &mock.UserResourceMappingService{
+		CreateMappingFn: func(ctx context.Context, m *influxdb.UserResourceMapping) error {
+			created++
+			return nil
+		},
+	}
+	roles := mock.NewRoleService()
+	roles.FindRoleByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+		return &influxdb.Role{ID: id, OrgID: orgID, Name: "admin", Permissions: []influxdb.Permission{bucketsWrite}}, nil
+	}
+	s := authorizer.NewURMService(&OrgService{OrgID: orgID}, urms)
+	s.RoleService = roles
+	m := &influxdb.UserResourceMapping{ResourceType: influxdb.RolesResourceType, ResourceID: roleID, UserID: 100}
+
+	t.Run("role with wider permissions", func(t *testing.T) {
+		ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, []influxdb.Permission{rolesWrite}))
+		err := s.CreateUserResourceMapping(ctx, m)
+		if influxdb.ErrorCode(err) != influxdb.EForbidden {
+			t.Fatalf("expected forbidden error, got %v", err)
+		}
+		if created != 0 {
+			t.Fatal("unexpected mapping to role")
+		}
+	})
+
+	t.Run("role within permissions", func(t *testing.T) {
+		ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, []influxdb.Permission{rolesWrite, bucketsWrite}))
+		if err := s.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+		if created != 1 {
+			t.Fatal("expected mapping to role")
+		}
+	})
+
+	t.Run("no role service", func(t *testing.T) {
+		s := authorizer.NewURMService(&OrgService{OrgID: orgID}, urms)
+		ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(true, nil))
+		if err := s.CreateUserResourceMapping(ctx, m); influxdb.ErrorCode(err) != influxdb.EForbidden {
+			t.Fatalf("expected forbidden error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.UsageService = (*UsageService)(nil)
+
+// UsageService wraps a influxdb.UsageService and authorizes actions
+// against it appropriately.
+type UsageService struct {
+	s influxdb.UsageService
+}
+
+// NewUsageService constructs an instance of an authorizing usage service.
+func NewUsageService(s influxdb.UsageService) *UsageService {
+	return &UsageService{
+		s: s,
+	}
+}
+
+// GetUsage checks to see if the authorizer on context has read access to the
+// organization of the filter, or to all organizations when the filter has none.
+func (s *UsageService) GetUsage(ctx context.Context, filter influxdb.UsageFilter) (map[influxdb.UsageMetric]*influxdb.Usage, error) {
+	if filter.OrgID != nil {
+		if _, _, err := AuthorizeReadOrg(ctx, *filter.OrgID); err != nil {
+			return nil, err
+		}
+	} else if _, _, err := AuthorizeReadGlobal(ctx, influxdb.OrgsResourceType); err != nil {
+		return nil, err
+	}
+	return s.s.GetUsage(ctx, filter)
+}
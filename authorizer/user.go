@@ -57,7 +57,11 @@ func (s *UserService) CreateUser(ctx context.Context, o *influxdb.User) error {
 	if _, _, err := AuthorizeWriteGlobal(ctx, influxdb.UsersResourceType); err != nil {
 		return err
 	}
-	return s.s.CreateUser(ctx, o)
+	if err := s.s.CreateUser(ctx, o); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.UsersResourceType, o.ID, 0, o)
+	return nil
 }
 
 // UpdateUser checks to see if the authorizer on context has write access to the user provided.
@@ -65,7 +69,16 @@ func (s *UserService) UpdateUser(ctx context.Context, id influxdb.ID, upd influx
 	if _, _, err := AuthorizeWriteResource(ctx, influxdb.UsersResourceType, id); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateUser(ctx, id, upd)
+	before, err := s.auditedUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.s.UpdateUser(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.UsersResourceType, id, 0, before, u)
+	return u, nil
 }
 
 // DeleteUser checks to see if the authorizer on context has write access to the user provided.
@@ -73,5 +86,21 @@ func (s *UserService) DeleteUser(ctx context.Context, id influxdb.ID) error {
 	if _, _, err := AuthorizeWriteResource(ctx, influxdb.UsersResourceType, id); err != nil {
 		return err
 	}
-	return s.s.DeleteUser(ctx, id)
+	before, err := s.auditedUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.s.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.UsersResourceType, id, 0, before)
+	return nil
+}
+
+// auditedUser looks up the user to record in audit events, when operations are audited.
+func (s *UserService) auditedUser(ctx context.Context, id influxdb.ID) (*influxdb.User, error) {
+	if !auditing(ctx) {
+		return nil, nil
+	}
+	return s.s.FindUserByID(ctx, id)
 }
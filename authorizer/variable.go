@@ -49,7 +49,11 @@ func (s *VariableService) CreateVariable(ctx context.Context, v *influxdb.Variab
 	if _, _, err := AuthorizeCreate(ctx, influxdb.VariablesResourceType, v.OrganizationID); err != nil {
 		return err
 	}
-	return s.s.CreateVariable(ctx, v)
+	if err := s.s.CreateVariable(ctx, v); err != nil {
+		return err
+	}
+	auditCreate(ctx, influxdb.VariablesResourceType, v.ID, v.OrganizationID, v)
+	return nil
 }
 
 // UpdateVariable checks to see if the authorizer on context has write access to the variable provided.
@@ -61,7 +65,12 @@ func (s *VariableService) UpdateVariable(ctx context.Context, id influxdb.ID, up
 	if _, _, err := AuthorizeWrite(ctx, influxdb.VariablesResourceType, v.ID, v.OrganizationID); err != nil {
 		return nil, err
 	}
-	return s.s.UpdateVariable(ctx, id, upd)
+	updated, err := s.s.UpdateVariable(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	auditUpdate(ctx, influxdb.VariablesResourceType, id, v.OrganizationID, v, updated)
+	return updated, nil
 }
 
 // ReplaceVariable checks to see if the authorizer on context has write access to the variable provided.
@@ -73,7 +82,11 @@ func (s *VariableService) ReplaceVariable(ctx context.Context, m *influxdb.Varia
 	if _, _, err := AuthorizeWrite(ctx, influxdb.VariablesResourceType, v.ID, v.OrganizationID); err != nil {
 		return err
 	}
-	return s.s.ReplaceVariable(ctx, m)
+	if err := s.s.ReplaceVariable(ctx, m); err != nil {
+		return err
+	}
+	auditUpdate(ctx, influxdb.VariablesResourceType, m.ID, v.OrganizationID, v, m)
+	return nil
 }
 
 // DeleteVariable checks to see if the authorizer on context has write access to the variable provided.
@@ -85,5 +98,9 @@ func (s *VariableService) DeleteVariable(ctx context.Context, id influxdb.ID) er
 	if _, _, err := AuthorizeWrite(ctx, influxdb.VariablesResourceType, v.ID, v.OrganizationID); err != nil {
 		return err
 	}
-	return s.s.DeleteVariable(ctx, id)
+	if err := s.s.DeleteVariable(ctx, id); err != nil {
+		return err
+	}
+	auditDelete(ctx, influxdb.VariablesResourceType, id, v.OrganizationID, v)
+	return nil
 }
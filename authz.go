@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 )
 
 var (
@@ -129,6 +130,10 @@ const (
 	NotificationEndpointResourceType = ResourceType("notificationEndpoints") // 15
 	// ChecksResourceType gives permission to one or more Checks.
 	ChecksResourceType = ResourceType("checks") // 16
+	// RolesResourceType gives permission to one or more roles.
+	RolesResourceType = ResourceType("roles") // 17
+	// AuditResourceType gives permission to the audit log.
+	AuditResourceType = ResourceType("audit") // 18
 )
 
 // AllResourceTypes is the list of all known resource types.
@@ -150,6 +155,8 @@ var AllResourceTypes = []ResourceType{
 	NotificationRuleResourceType,     // 14
 	NotificationEndpointResourceType, // 15
 	ChecksResourceType,               // 16
+	RolesResourceType,                // 17
+	AuditResourceType,                // 18
 	// NOTE: when modifying this list, please update the swagger for components.schemas.Permission resource enum.
 }
 
@@ -167,6 +174,7 @@ var OrgResourceTypes = []ResourceType{
 	NotificationRuleResourceType,     // 14
 	NotificationEndpointResourceType, // 15
 	ChecksResourceType,               // 16
+	RolesResourceType,                // 17
 }
 
 // Valid checks if the resource type is a member of the ResourceType enum.
@@ -194,6 +202,8 @@ func (t ResourceType) Valid() (err error) {
 	case NotificationRuleResourceType: // 14
 	case NotificationEndpointResourceType: // 15
 	case ChecksResourceType: // 16
+	case RolesResourceType: // 17
+	case AuditResourceType: // 18
 	default:
 		err = ErrInvalidResourceType
 	}
@@ -248,6 +258,48 @@ func (p Permission) String() string {
 	return fmt.Sprintf("%s:%s", p.Action, p.Resource)
 }
 
+// ParsePermission parses a permission from its string form,
+// e.g. "read:orgs/0000000000000001/buckets".
+func ParsePermission(s string) (*Permission, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return nil, &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid permission %q", s),
+		}
+	}
+
+	p := &Permission{Action: Action(parts[0])}
+	segments := strings.Split(parts[1], "/")
+	if len(segments) > 2 && segments[0] == string(OrgsResourceType) {
+		orgID, err := IDFromString(segments[1])
+		if err != nil {
+			return nil, err
+		}
+		p.Resource.OrgID = orgID
+		segments = segments[2:]
+	}
+
+	switch len(segments) {
+	case 2:
+		id, err := IDFromString(segments[1])
+		if err != nil {
+			return nil, err
+		}
+		p.Resource.ID = id
+		fallthrough
+	case 1:
+		p.Resource.Type = ResourceType(segments[0])
+	default:
+		return nil, &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid permission resource %q", parts[1]),
+		}
+	}
+
+	return p, p.Valid()
+}
+
 // Valid checks if there the resource and action provided is known.
 func (p *Permission) Valid() error {
 	if err := p.Resource.Valid(); err != nil {
@@ -435,3 +435,69 @@ func validID() *platform.ID {
 	id := platform.ID(100)
 	return &id
 }
+
+func TestParsePermission(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    platform.Permission
+		wantErr bool
+	}{
+		{
+			s: "write:orgs/0000000000000001/buckets/0000000000000064",
+			want: platform.Permission{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+					ID:    validID(),
+				},
+			},
+		},
+		{
+			s: "read:orgs/0000000000000001/buckets",
+			want: platform.Permission{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			s: "read:orgs/0000000000000001",
+			want: platform.Permission{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type: platform.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			s: "write:dashboards",
+			want: platform.Permission{
+				Action:   platform.WriteAction,
+				Resource: platform.Resource{Type: platform.DashboardsResourceType},
+			},
+		},
+		{s: "read", wantErr: true},
+		{s: "delete:buckets", wantErr: true},
+		{s: "read:widgets", wantErr: true},
+		{s: "read:buckets/nope", wantErr: true},
+		{s: "read:orgs/0000000000000001/buckets/0000000000000064/extra", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := platform.ParsePermission(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePermission() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.String() != tt.want.String() || got.String() != tt.s {
+				t.Errorf("ParsePermission() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
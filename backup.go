@@ -14,6 +14,11 @@ type BackupService interface {
 	FetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error
 	// InternalBackupPath is a utility to determine the on-disk location of a backup fileset.
 	InternalBackupPath(backupID int) string
+	// CreateBucketBackup creates a local copy of the TSM data of a bucket stored in a shard
+	// of its own. The backup ID is only valid for the bucket.
+	CreateBucketBackup(ctx context.Context, bucketID ID) (backupID int, backupFiles []string, err error)
+	// FetchBucketBackupFile downloads one file of a backup of a bucket.
+	FetchBucketBackupFile(ctx context.Context, bucketID ID, backupID int, backupFile string, w io.Writer) error
 }
 
 // KVBackupService represents the meta data backup functions of InfluxDB.
@@ -32,6 +32,74 @@ const (
 // InfiniteRetention is default infinite retention period.
 const InfiniteRetention = 0
 
+// Compressions of the values of the string fields of a bucket in storage.
+const (
+	// BucketStringCompressionSnappy is the default, fast compression.
+	BucketStringCompressionSnappy = "snappy"
+	// BucketStringCompressionZstd compresses log-like values better than snappy.
+	BucketStringCompressionZstd = "zstd"
+)
+
+// ValidBucketStringCompression returns an error if c is not a compression of
+// the values of string fields. An empty compression is the default snappy one.
+func ValidBucketStringCompression(c string) error {
+	switch c {
+	case "", BucketStringCompressionSnappy, BucketStringCompressionZstd:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg: fmt.Sprintf("invalid string compression %q, expected %q or %q",
+			c, BucketStringCompressionSnappy, BucketStringCompressionZstd),
+	}
+}
+
+// WAL durabilities of the writes to a bucket in storage.
+const (
+	// BucketWALDurabilitySync fsyncs every write on its own.
+	BucketWALDurabilitySync = "sync"
+	// BucketWALDurabilityGroup shares the fsyncs of the writes made within the
+	// fsync delay of the WAL.
+	BucketWALDurabilityGroup = "group"
+	// BucketWALDurabilityAsync returns from writes before they are fsynced.
+	BucketWALDurabilityAsync = "async"
+)
+
+// ValidBucketWALDurability returns an error if d is not a WAL durability. An
+// empty durability is the default durability of the storage engine.
+func ValidBucketWALDurability(d string) error {
+	switch d {
+	case "", BucketWALDurabilitySync, BucketWALDurabilityGroup, BucketWALDurabilityAsync:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg: fmt.Sprintf("invalid WAL durability %q, expected %q, %q or %q",
+			d, BucketWALDurabilitySync, BucketWALDurabilityGroup, BucketWALDurabilityAsync),
+	}
+}
+
+// ValidBucketIndexedFields returns an error if fields are not valid names of
+// the fields whose values are indexed in storage.
+func ValidBucketIndexedFields(fields []string) error {
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if f == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "indexed field names must not be empty",
+			}
+		} else if seen[f] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("indexed field %q is listed more than once", f),
+			}
+		}
+		seen[f] = true
+	}
+	return nil
+}
+
 // Bucket is a bucket. 🎉
 type Bucket struct {
 	ID                  ID            `json:"id,omitempty"`
@@ -41,6 +109,9 @@ type Bucket struct {
 	Description         string        `json:"description"`
 	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
 	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	StringCompression   string        `json:"stringCompression,omitempty"`
+	IndexedFields       []string      `json:"indexedFields,omitempty"`
+	WALDurability       string        `json:"walDurability,omitempty"`
 	CRUDLog
 }
 
@@ -101,9 +172,12 @@ type BucketService interface {
 // BucketUpdate represents updates to a bucket.
 // Only fields which are set are updated.
 type BucketUpdate struct {
-	Name            *string        `json:"name,omitempty"`
-	Description     *string        `json:"description,omitempty"`
-	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+	Name              *string        `json:"name,omitempty"`
+	Description       *string        `json:"description,omitempty"`
+	RetentionPeriod   *time.Duration `json:"retentionPeriod,omitempty"`
+	StringCompression *string        `json:"stringCompression,omitempty"`
+	IndexedFields     *[]string      `json:"indexedFields,omitempty"`
+	WALDurability     *string        `json:"walDurability,omitempty"`
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
@@ -0,0 +1,56 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// BucketCardinality is the series cardinality of a bucket.
+type BucketCardinality struct {
+	BucketID ID `json:"bucketID"`
+
+	// Series is the number of series of the bucket.
+	Series int64 `json:"series"`
+
+	// Measurements are the measurements of the bucket with the most series.
+	Measurements []MeasurementCardinality `json:"measurements"`
+
+	// TagKeys are the tag keys of the bucket with the most distinct values.
+	TagKeys []TagKeyCardinality `json:"tagKeys"`
+
+	// Growth is the number of series of the bucket over time, oldest first.
+	Growth []CardinalitySample `json:"growth"`
+}
+
+// MeasurementCardinality is an estimate of the number of series of a measurement.
+type MeasurementCardinality struct {
+	Name   string `json:"name"`
+	Series int64  `json:"series"`
+}
+
+// TagKeyCardinality is the number of distinct values of a tag key.
+type TagKeyCardinality struct {
+	Key    string `json:"key"`
+	Values int64  `json:"values"`
+
+	// Truncated is true if the values were only counted up to Values.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// CardinalitySample is the number of series of a bucket at some time.
+type CardinalitySample struct {
+	Time   time.Time `json:"time"`
+	Series int64     `json:"series"`
+}
+
+// CardinalityOptions are the options of looking up the cardinality of a bucket.
+type CardinalityOptions struct {
+	// Limit is the maximum number of measurements and of tag keys returned.
+	Limit int
+}
+
+// CardinalityService looks up the series cardinality of buckets.
+type CardinalityService interface {
+	// FindBucketCardinality returns the series cardinality of a bucket.
+	FindBucketCardinality(ctx context.Context, orgID, bucketID ID, opts CardinalityOptions) (*BucketCardinality, error)
+}
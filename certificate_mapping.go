@@ -0,0 +1,100 @@
+package influxdb
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+)
+
+// ErrCertificateMappingNotFound is the error msg for a missing certificate mapping.
+const ErrCertificateMappingNotFound = "certificate mapping not found"
+
+// ops for certificate mapping error.
+const (
+	OpFindCertificateMappingByID = "FindCertificateMappingByID"
+	OpFindCertificateMappings    = "FindCertificateMappings"
+	OpCreateCertificateMapping   = "CreateCertificateMapping"
+	OpDeleteCertificateMapping   = "DeleteCertificateMapping"
+)
+
+// CertificateMappingService describes a service for managing the mappings of
+// client certificates to authorizations.
+type CertificateMappingService interface {
+	// FindCertificateMappingByID finds a single certificate mapping by its ID.
+	FindCertificateMappingByID(ctx context.Context, id ID) (*CertificateMapping, error)
+
+	// FindCertificateMappings returns the certificate mappings matching the filter.
+	FindCertificateMappings(ctx context.Context, filter CertificateMappingFilter, opt ...FindOptions) ([]*CertificateMapping, int, error)
+
+	// CreateCertificateMapping creates a new certificate mapping and assigns it an ID.
+	CreateCertificateMapping(ctx context.Context, m *CertificateMapping) error
+
+	// DeleteCertificateMapping removes a certificate mapping.
+	DeleteCertificateMapping(ctx context.Context, id ID) error
+}
+
+// CertificateMapping authenticates the requests made with a verified client
+// certificate as an authorization. It matches certificates either by the
+// distinguished name of their subject or by one of their subject alternative names.
+type CertificateMapping struct {
+	ID    ID `json:"id,omitempty"`
+	OrgID ID `json:"orgID,omitempty"`
+	// Subject is the distinguished name of the certificate subject in RFC 2253
+	// form, such as "CN=telegraf,O=Acme".
+	Subject string `json:"subject,omitempty"`
+	// SAN is a DNS name, email address, IP address or URI of the certificate
+	// subject alternative names, such as "spiffe://cluster.local/ns/default/sa/telegraf".
+	SAN             string `json:"san,omitempty"`
+	AuthorizationID ID     `json:"authorizationID"`
+	Description     string `json:"description,omitempty"`
+	CRUDLog
+}
+
+// Valid returns an error if the mapping does not match certificates by exactly
+// one of subject or SAN, or is missing its authorization.
+func (m *CertificateMapping) Valid() error {
+	if (m.Subject == "") == (m.SAN == "") {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "certificate mapping requires exactly one of subject or san",
+		}
+	}
+	if !m.AuthorizationID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "certificate mapping authorization id is required",
+		}
+	}
+	return nil
+}
+
+// CertificateMappingFilter represents a set of filters that restrict the returned certificate mappings.
+type CertificateMappingFilter struct {
+	ID              *ID
+	OrgID           *ID
+	AuthorizationID *ID
+	Subject         *string
+	SAN             *string
+}
+
+// CertificateSANs returns the subject alternative names of the certificate
+// in the order they are matched by certificate mappings: URIs, DNS names,
+// email addresses and then IP addresses.
+func CertificateSANs(cert *x509.Certificate) []string {
+	var sans []string
+	for _, u := range cert.URIs {
+		sans = append(sans, u.String())
+	}
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+// CertificateSubject returns the distinguished name of the certificate subject
+// as it is matched by certificate mappings.
+func CertificateSubject(cert *x509.Certificate) string {
+	return strings.TrimSpace(cert.Subject.String())
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"io"
+	"time"
 
 	platform "github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/cmd/influx/internal"
@@ -17,6 +18,28 @@ type token struct {
 	UserName    string      `json:"userName"`
 	UserID      platform.ID `json:"userID"`
 	Permissions []string    `json:"permissions"`
+	ExpiresAt   *time.Time  `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time  `json:"lastUsedAt,omitempty"`
+	LastUsedIP  string      `json:"lastUsedIP,omitempty"`
+}
+
+func newToken(a *platform.Authorization, user *platform.User) token {
+	ps := make([]string, 0, len(a.Permissions))
+	for _, p := range a.Permissions {
+		ps = append(ps, p.String())
+	}
+
+	return token{
+		ID:          a.ID,
+		Token:       a.MaskedToken(),
+		Status:      string(a.Status),
+		UserName:    user.Name,
+		UserID:      user.ID,
+		Permissions: ps,
+		ExpiresAt:   a.ExpiresAt,
+		LastUsedAt:  a.LastUsedAt,
+		LastUsedIP:  a.LastUsedIP,
+	}
 }
 
 func cmdAuth(f *globalFlags, opt genericCLIOpts) *cobra.Command {
@@ -31,6 +54,7 @@ func cmdAuth(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 		authDeleteCmd(),
 		authFindCmd(),
 		authInactiveCmd(),
+		authRotateCmd(),
 	)
 
 	return cmd
@@ -43,8 +67,9 @@ var authCRUDFlags struct {
 }
 
 var authCreateFlags struct {
-	user string
-	org  organization
+	user      string
+	org       organization
+	expiresIn time.Duration
 
 	writeUserPermission bool
 	readUserPermission  bool
@@ -86,6 +111,7 @@ func authCreateCmd() *cobra.Command {
 	authCreateFlags.org.register(cmd, false)
 
 	cmd.Flags().StringVarP(&authCreateFlags.user, "user", "u", "", "The user name")
+	cmd.Flags().DurationVarP(&authCreateFlags.expiresIn, "expires-in", "", 0, "The duration after which the token expires, i.e. 720h; the token never expires if unset")
 	registerPrintOptions(cmd, &authCRUDFlags.hideHeaders, &authCRUDFlags.json)
 
 	cmd.Flags().BoolVarP(&authCreateFlags.writeUserPermission, "write-user", "", false, "Grants the permission to perform mutative actions against organization users")
@@ -240,6 +266,10 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		Permissions: permissions,
 		OrgID:       orgID,
 	}
+	if authCreateFlags.expiresIn > 0 {
+		expiresAt := time.Now().Add(authCreateFlags.expiresIn).UTC()
+		authorization.ExpiresAt = &expiresAt
+	}
 
 	if userName := authCreateFlags.user; userName != "" {
 		user, err := userSvc.FindUser(context.Background(), platform.UserFilter{
@@ -265,22 +295,10 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	ps := make([]string, 0, len(authorization.Permissions))
-	for _, p := range authorization.Permissions {
-		ps = append(ps, p.String())
-	}
-
 	return writeTokens(cmd.OutOrStdout(), tokenPrintOpt{
 		jsonOut:     authCRUDFlags.json,
 		hideHeaders: authCRUDFlags.hideHeaders,
-		token: token{
-			ID:          authorization.ID,
-			Token:       authorization.Token,
-			Status:      string(authorization.Status),
-			UserName:    user.Name,
-			UserID:      user.ID,
-			Permissions: ps,
-		},
+		token:       newToken(authorization, user),
 	})
 }
 
@@ -355,24 +373,12 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 
 	var tokens []token
 	for _, a := range authorizations {
-		var permissions []string
-		for _, p := range a.Permissions {
-			permissions = append(permissions, p.String())
-		}
-
 		user, err := us.FindUserByID(context.Background(), a.UserID)
 		if err != nil {
 			return err
 		}
 
-		tokens = append(tokens, token{
-			ID:          a.ID,
-			Token:       a.Token,
-			Status:      string(a.Status),
-			UserName:    user.Name,
-			UserID:      a.UserID,
-			Permissions: permissions,
-		})
+		tokens = append(tokens, newToken(a, user))
 	}
 
 	return writeTokens(cmd.OutOrStdout(), tokenPrintOpt{
@@ -427,23 +433,11 @@ func authorizationDeleteF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	ps := make([]string, 0, len(a.Permissions))
-	for _, p := range a.Permissions {
-		ps = append(ps, p.String())
-	}
-
 	return writeTokens(cmd.OutOrStdout(), tokenPrintOpt{
 		jsonOut:     authCRUDFlags.json,
 		deleted:     true,
 		hideHeaders: authCRUDFlags.hideHeaders,
-		token: token{
-			ID:          a.ID,
-			Token:       a.Token,
-			Status:      string(a.Status),
-			UserName:    user.Name,
-			UserID:      user.ID,
-			Permissions: ps,
-		},
+		token:       newToken(a, user),
 	})
 }
 
@@ -494,22 +488,10 @@ func authorizationActiveF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	ps := make([]string, 0, len(a.Permissions))
-	for _, p := range a.Permissions {
-		ps = append(ps, p.String())
-	}
-
 	return writeTokens(cmd.OutOrStdout(), tokenPrintOpt{
 		jsonOut:     authCRUDFlags.json,
 		hideHeaders: authCRUDFlags.hideHeaders,
-		token: token{
-			ID:          a.ID,
-			Token:       a.Token,
-			Status:      string(a.Status),
-			UserName:    user.Name,
-			UserID:      user.ID,
-			Permissions: ps,
-		},
+		token:       newToken(a, user),
 	})
 }
 
@@ -560,22 +542,62 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	ps := make([]string, 0, len(a.Permissions))
-	for _, p := range a.Permissions {
-		ps = append(ps, p.String())
+	return writeTokens(cmd.OutOrStdout(), tokenPrintOpt{
+		jsonOut:     authCRUDFlags.json,
+		hideHeaders: authCRUDFlags.hideHeaders,
+		token:       newToken(a, user),
+	})
+}
+
+var authRotateFlags struct {
+	gracePeriod time.Duration
+}
+
+func authRotateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Issue a new token for an authorization, keeping its ID and permissions",
+		RunE:  checkSetupRunEMiddleware(&flags)(authorizationRotateF),
+	}
+
+	registerPrintOptions(cmd, &authCRUDFlags.hideHeaders, &authCRUDFlags.json)
+	cmd.Flags().StringVarP(&authCRUDFlags.id, "id", "i", "", "The authorization ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().DurationVarP(&authRotateFlags.gracePeriod, "grace-period", "", 0, "The duration the replaced token is still accepted for")
+
+	return cmd
+}
+
+func authorizationRotateF(cmd *cobra.Command, args []string) error {
+	s, err := newAuthorizationService()
+	if err != nil {
+		return err
+	}
+
+	us, err := newUserService()
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(authCRUDFlags.id); err != nil {
+		return err
+	}
+
+	a, err := s.RotateAuthorization(context.Background(), id, authRotateFlags.gracePeriod)
+	if err != nil {
+		return err
+	}
+
+	user, err := us.FindUserByID(context.Background(), a.UserID)
+	if err != nil {
+		return err
 	}
 
 	return writeTokens(cmd.OutOrStdout(), tokenPrintOpt{
 		jsonOut:     authCRUDFlags.json,
 		hideHeaders: authCRUDFlags.hideHeaders,
-		token: token{
-			ID:          a.ID,
-			Token:       a.Token,
-			Status:      string(a.Status),
-			UserName:    user.Name,
-			UserID:      user.ID,
-			Permissions: ps,
-		},
+		token:       newToken(a, user),
 	})
 }
 
@@ -607,6 +629,8 @@ func writeTokens(w io.Writer, printOpts tokenPrintOpt) error {
 		"User Name",
 		"User ID",
 		"Permissions",
+		"Expires At",
+		"Last Used",
 	}
 	if printOpts.deleted {
 		headers = append(headers, "Deleted")
@@ -624,6 +648,8 @@ func writeTokens(w io.Writer, printOpts tokenPrintOpt) error {
 			"User Name":   t.UserName,
 			"User ID":     t.UserID.String(),
 			"Permissions": t.Permissions,
+			"Expires At":  formatTokenTime(t.ExpiresAt),
+			"Last Used":   formatTokenUse(t),
 		}
 		if printOpts.deleted {
 			m["Deleted"] = true
@@ -634,6 +660,20 @@ func writeTokens(w io.Writer, printOpts tokenPrintOpt) error {
 	return nil
 }
 
+func formatTokenTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatTokenUse(t token) string {
+	if t.LastUsedAt == nil {
+		return ""
+	}
+	return formatTokenTime(t.LastUsedAt) + " from " + t.LastUsedIP
+}
+
 func newAuthorizationService() (platform.AuthorizationService, error) {
 	if flags.local {
 		return newLocalKVService()
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -20,7 +21,10 @@ func cmdBackup(f *globalFlags, opt genericCLIOpts) *cobra.Command {
 		`Backs up data and meta data for the running InfluxDB instance.
 Downloaded files are written to the directory indicated by --path.
 The target directory, and any parent directories, are created automatically.
-Data file have extension .tsm; meta data is written to %s in the same directory.`,
+Data file have extension .tsm; meta data is written to %s in the same directory.
+With --bucket-id, only the data of a bucket stored in a shard of its own is
+backed up, without meta data. It is restored with "influxd restore --bucket-id",
+into an instance where the bucket exists.`,
 		bolt.DefaultFilename)
 
 	opts := flagOpts{
@@ -32,6 +36,11 @@ Data file have extension .tsm; meta data is written to %s in the same directory.
 			Desc:     "directory path to write backup files to",
 			Required: true,
 		},
+		{
+			DestP: &backupFlags.BucketID,
+			Flag:  "bucket-id",
+			Desc:  "The ID of a bucket stored on its own to back up alone",
+		},
 	}
 	opts.mustRegister(cmd)
 
@@ -39,7 +48,8 @@ Data file have extension .tsm; meta data is written to %s in the same directory.
 }
 
 var backupFlags struct {
-	Path string
+	Path     string
+	BucketID string
 }
 
 func newBackupService() (influxdb.BackupService, error) {
@@ -70,7 +80,22 @@ func backupF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	id, backupFilenames, err := backupService.CreateBackup(ctx)
+	createBackup := backupService.CreateBackup
+	fetchBackupFile := backupService.FetchBackupFile
+	if backupFlags.BucketID != "" {
+		bucketID, err := influxdb.IDFromString(backupFlags.BucketID)
+		if err != nil {
+			return fmt.Errorf("invalid bucket ID: %v", err)
+		}
+		createBackup = func(ctx context.Context) (int, []string, error) {
+			return backupService.CreateBucketBackup(ctx, *bucketID)
+		}
+		fetchBackupFile = func(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+			return backupService.FetchBucketBackupFile(ctx, *bucketID, backupID, backupFile, w)
+		}
+	}
+
+	id, backupFilenames, err := createBackup(ctx)
 	if err != nil {
 		return err
 	}
@@ -78,12 +103,16 @@ func backupF(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Backup ID %d contains %d files\n", id, len(backupFilenames))
 
 	for _, backupFilename := range backupFilenames {
-		dest := filepath.Join(backupFlags.Path, backupFilename)
+		// The files of the buckets stored on their own are in directories.
+		dest := filepath.Join(backupFlags.Path, filepath.FromSlash(backupFilename))
+		if err := os.MkdirAll(filepath.Dir(dest), 0777); err != nil {
+			return err
+		}
 		w, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
 		if err != nil {
 			return err
 		}
-		err = backupService.FetchBackupFile(ctx, id, backupFilename, w)
+		err = fetchBackupFile(ctx, id, backupFilename, w)
 		if err != nil {
 			return multierr.Append(fmt.Errorf("error fetching file %s: %v", backupFilename, err), w.Close())
 		}
@@ -31,6 +31,10 @@ type cmdBucketBuilder struct {
 	description string
 	org         organization
 	retention   time.Duration
+
+	stringCompression string
+	indexedFields     []string
+	walDurability     string
 }
 
 func newCmdBucketBuilder(svcsFn bucketSVCsFn, opts genericCLIOpts) *cmdBucketBuilder {
@@ -73,6 +77,9 @@ func (b *cmdBucketBuilder) cmdCreate() *cobra.Command {
 
 	cmd.Flags().StringVarP(&b.description, "description", "d", "", "Description of bucket that will be created")
 	cmd.Flags().DurationVarP(&b.retention, "retention", "r", 0, "Duration bucket will retain data. 0 is infinite. Default is 0.")
+	cmd.Flags().StringVar(&b.stringCompression, "string-compression", "", "Compression of string field values in storage, snappy or zstd. Default is snappy.")
+	cmd.Flags().StringSliceVar(&b.indexedFields, "indexed-fields", nil, "Fields whose values are indexed in storage to speed up queries filtering on them.")
+	cmd.Flags().StringVar(&b.walDurability, "wal-durability", "", "Durability of writes to the bucket in the WAL, sync, group or async. Default is the durability of the storage engine.")
 	b.org.register(cmd, false)
 	b.registerPrintFlags(cmd)
 
@@ -90,9 +97,12 @@ func (b *cmdBucketBuilder) cmdCreateRunEFn(*cobra.Command, []string) error {
 	}
 
 	bkt := &influxdb.Bucket{
-		Name:            b.name,
-		Description:     b.description,
-		RetentionPeriod: b.retention,
+		Name:              b.name,
+		Description:       b.description,
+		RetentionPeriod:   b.retention,
+		StringCompression: b.stringCompression,
+		IndexedFields:     b.indexedFields,
+		WALDurability:     b.walDurability,
 	}
 	bkt.OrgID, err = b.org.getID(orgSVC)
 	if err != nil {
@@ -246,6 +256,9 @@ func (b *cmdBucketBuilder) cmdUpdate() *cobra.Command {
 	cmd.Flags().StringVarP(&b.description, "description", "d", "", "Description of bucket that will be created")
 	cmd.MarkFlagRequired("id")
 	cmd.Flags().DurationVarP(&b.retention, "retention", "r", 0, "Duration bucket will retain data. 0 is infinite. Default is 0.")
+	cmd.Flags().StringVar(&b.stringCompression, "string-compression", "", "Compression of string field values in storage, snappy or zstd. Existing data is compressed again as it is compacted.")
+	cmd.Flags().StringSliceVar(&b.indexedFields, "indexed-fields", nil, "Fields whose values are indexed in storage. Existing data is indexed as it is compacted.")
+	cmd.Flags().StringVar(&b.walDurability, "wal-durability", "", "Durability of writes to the bucket in the WAL, sync, group or async.")
 
 	return cmd
 }
@@ -271,6 +284,15 @@ func (b *cmdBucketBuilder) cmdUpdateRunEFn(cmd *cobra.Command, args []string) er
 	if b.retention != 0 {
 		update.RetentionPeriod = &b.retention
 	}
+	if b.stringCompression != "" {
+		update.StringCompression = &b.stringCompression
+	}
+	if cmd.Flags().Changed("indexed-fields") {
+		update.IndexedFields = &b.indexedFields
+	}
+	if b.walDurability != "" {
+		update.WALDurability = &b.walDurability
+	}
 
 	bkt, err := bktSVC.UpdateBucket(context.Background(), id, update)
 	if err != nil {
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/http"
+	"github.com/spf13/cobra"
+)
+
+// cardinalityService looks up buckets and their series cardinality.
+type cardinalityService interface {
+	influxdb.BucketService
+	FindBucketCardinality(ctx context.Context, id influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error)
+}
+
+type cardinalitySVCFn func() (cardinalityService, error)
+
+func cmdCardinality(f *globalFlags, opt genericCLIOpts) *cobra.Command {
+	builder := newCmdCardinalityBuilder(newCardinalitySVC, opt)
+	builder.globalFlags = f
+	return builder.cmd()
+}
+
+type cmdCardinalityBuilder struct {
+	genericCLIOpts
+	*globalFlags
+
+	svcFn cardinalitySVCFn
+
+	bucketID    string
+	bucketName  string
+	org         organization
+	limit       int
+	hideHeaders bool
+	json        bool
+}
+
+func newCmdCardinalityBuilder(svcFn cardinalitySVCFn, opts genericCLIOpts) *cmdCardinalityBuilder {
+	return &cmdCardinalityBuilder{
+		genericCLIOpts: opts,
+		svcFn:          svcFn,
+	}
+}
+
+func (b *cmdCardinalityBuilder) cmd() *cobra.Command {
+	cmd := b.newCmd("cardinality", b.cmdSummaryRunEFn, true)
+	cmd.Short = "Series cardinality of a bucket"
+	cmd.Long = `Series cardinality of a bucket: the number of series of the bucket, the
+	measurements with the most series, the tag keys with the most values and the
+	growth of the bucket over the last day.`
+	cmd.TraverseChildren = true
+
+	opts := flagOpts{
+		{
+			DestP:      &b.bucketID,
+			Flag:       "bucket-id",
+			Desc:       "The ID of the bucket",
+			Persistent: true,
+		},
+		{
+			DestP:      &b.bucketName,
+			Flag:       "bucket",
+			Short:      'b',
+			EnvVar:     "BUCKET_NAME",
+			Desc:       "The name of the bucket",
+			Persistent: true,
+		},
+	}
+	opts.mustRegister(cmd)
+	b.org.register(cmd, true)
+	b.registerPrintFlags(cmd)
+	cmd.PersistentFlags().IntVarP(&b.limit, "limit", "l", influxdb.DefaultPageSize, "Maximum number of measurements and tag keys")
+
+	cmd.AddCommand(
+		b.cmdMeasurements(),
+		b.cmdTagKeys(),
+		b.cmdGrowth(),
+	)
+
+	return cmd
+}
+
+func (b *cmdCardinalityBuilder) registerPrintFlags(cmd *cobra.Command) {
+	registerPrintOptions(cmd, &b.hideHeaders, &b.json)
+}
+
+func (b *cmdCardinalityBuilder) cmdSummaryRunEFn(*cobra.Command, []string) error {
+	c, err := b.findCardinality()
+	if err != nil {
+		return err
+	}
+	if b.json {
+		return b.writeJSON(c)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+	w.WriteHeaders("Bucket ID", "Series")
+	w.Write(map[string]interface{}{
+		"Bucket ID": c.BucketID.String(),
+		"Series":    c.Series,
+	})
+	return nil
+}
+
+func (b *cmdCardinalityBuilder) cmdMeasurements() *cobra.Command {
+	cmd := b.newCmd("measurements", b.cmdMeasurementsRunEFn, true)
+	cmd.Short = "Measurements with the most series"
+	b.registerPrintFlags(cmd)
+	return cmd
+}
+
+func (b *cmdCardinalityBuilder) cmdMeasurementsRunEFn(*cobra.Command, []string) error {
+	c, err := b.findCardinality()
+	if err != nil {
+		return err
+	}
+	if b.json {
+		return b.writeJSON(c.Measurements)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+	w.WriteHeaders("Measurement", "Series")
+	for _, m := range c.Measurements {
+		w.Write(map[string]interface{}{
+			"Measurement": m.Name,
+			"Series":      m.Series,
+		})
+	}
+	return nil
+}
+
+func (b *cmdCardinalityBuilder) cmdTagKeys() *cobra.Command {
+	cmd := b.newCmd("tag-keys", b.cmdTagKeysRunEFn, true)
+	cmd.Short = "Tag keys with the most values"
+	b.registerPrintFlags(cmd)
+	return cmd
+}
+
+func (b *cmdCardinalityBuilder) cmdTagKeysRunEFn(*cobra.Command, []string) error {
+	c, err := b.findCardinality()
+	if err != nil {
+		return err
+	}
+	if b.json {
+		return b.writeJSON(c.TagKeys)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+	w.WriteHeaders("Tag Key", "Values")
+	for _, k := range c.TagKeys {
+		values := fmt.Sprint(k.Values)
+		if k.Truncated {
+			values += "+"
+		}
+		w.Write(map[string]interface{}{
+			"Tag Key": k.Key,
+			"Values":  values,
+		})
+	}
+	return nil
+}
+
+func (b *cmdCardinalityBuilder) cmdGrowth() *cobra.Command {
+	cmd := b.newCmd("growth", b.cmdGrowthRunEFn, true)
+	cmd.Short = "Number of series over the last day"
+	b.registerPrintFlags(cmd)
+	return cmd
+}
+
+func (b *cmdCardinalityBuilder) cmdGrowthRunEFn(*cobra.Command, []string) error {
+	c, err := b.findCardinality()
+	if err != nil {
+		return err
+	}
+	if b.json {
+		return b.writeJSON(c.Growth)
+	}
+
+	w := b.newTabWriter()
+	defer w.Flush()
+
+	w.HideHeaders(b.hideHeaders)
+	w.WriteHeaders("Time", "Series", "Change")
+	for i, s := range c.Growth {
+		var change int64
+		if i > 0 {
+			change = s.Series - c.Growth[i-1].Series
+		}
+		w.Write(map[string]interface{}{
+			"Time":   s.Time.Format(time.RFC3339),
+			"Series": s.Series,
+			"Change": fmt.Sprintf("%+d", change),
+		})
+	}
+	return nil
+}
+
+// findCardinality looks up the cardinality of the bucket given by the flags.
+func (b *cmdCardinalityBuilder) findCardinality() (*influxdb.BucketCardinality, error) {
+	svc, err := b.svcFn()
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := b.findBucketID(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := svc.FindBucketCardinality(context.Background(), id, influxdb.CardinalityOptions{Limit: b.limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve bucket cardinality: %v", err)
+	}
+	return c, nil
+}
+
+func (b *cmdCardinalityBuilder) findBucketID(svc influxdb.BucketService) (influxdb.ID, error) {
+	if b.bucketID != "" && b.bucketName != "" {
+		return 0, fmt.Errorf("must specify bucket-id, or bucket name not both")
+	} else if b.bucketID != "" {
+		id, err := influxdb.IDFromString(b.bucketID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode bucket id %q: %v", b.bucketID, err)
+		}
+		return *id, nil
+	} else if b.bucketName == "" {
+		return 0, fmt.Errorf("must specify bucket-id, or bucket name")
+	}
+
+	if err := b.org.validOrgFlags(b.globalFlags); err != nil {
+		return 0, err
+	}
+	filter := influxdb.BucketFilter{Name: &b.bucketName}
+	if b.org.id != "" {
+		orgID, err := influxdb.IDFromString(b.org.id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode org id %q: %v", b.org.id, err)
+		}
+		filter.OrganizationID = orgID
+	}
+	if b.org.name != "" {
+		filter.Org = &b.org.name
+	}
+
+	bkt, err := svc.FindBucket(context.Background(), filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve bucket %q: %v", b.bucketName, err)
+	}
+	return bkt.ID, nil
+}
+
+func newCardinalitySVC() (cardinalityService, error) {
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	return &http.BucketService{Client: httpClient}, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCardinalityService struct {
+	*mock.BucketService
+	FindBucketCardinalityFn func(context.Context, influxdb.ID, influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error)
+}
+
+func (s *fakeCardinalityService) FindBucketCardinality(ctx context.Context, id influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error) {
+	return s.FindBucketCardinalityFn(ctx, id, opts)
+}
+
+func TestCmdCardinality(t *testing.T) {
+	type called struct {
+		id    influxdb.ID
+		limit int
+		name  string
+		org   string
+	}
+
+	tests := []struct {
+		name     string
+		command  []string
+		flags    []string
+		expected called
+		output   string
+	}{
+		{
+			name:     "summary",
+			flags:    []string{"--bucket-id=" + influxdb.ID(2).String()},
+			expected: called{id: 2, limit: influxdb.DefaultPageSize},
+			output:   "Bucket ID\t\tSeries\n0000000000000002\t3\n",
+		},
+		{
+			name:     "measurements by bucket name",
+			command:  []string{"measurements"},
+			flags:    []string{"--bucket=b1", "--org=rg", "--limit=2"},
+			expected: called{id: 1, limit: 2, name: "b1", org: "rg"},
+			output:   "Measurement\tSeries\ncpu\t\t2\nmem\t\t1\n",
+		},
+		{
+			name:     "tag keys",
+			command:  []string{"tag-keys"},
+			flags:    []string{"-b=b1", "-o=rg", "--hide-headers"},
+			expected: called{id: 1, limit: influxdb.DefaultPageSize, name: "b1", org: "rg"},
+			output:   "host\t10000+\n",
+		},
+		{
+			name:     "growth",
+			command:  []string{"growth"},
+			flags:    []string{"--bucket-id=" + influxdb.ID(2).String(), "--hide-headers"},
+			expected: called{id: 2, limit: influxdb.DefaultPageSize},
+			output:   "1970-01-01T00:00:00Z\t1\t+0\n1970-01-01T00:10:00Z\t3\t+2\n",
+		},
+	}
+
+	cmdFn := func() (func(*globalFlags, genericCLIOpts) *cobra.Command, *called) {
+		calls := new(called)
+
+		bktSVC := mock.NewBucketService()
+		bktSVC.FindBucketFn = func(ctx context.Context, f influxdb.BucketFilter) (*influxdb.Bucket, error) {
+			if f.Name != nil {
+				calls.name = *f.Name
+			}
+			if f.Org != nil {
+				calls.org = *f.Org
+			}
+			return &influxdb.Bucket{ID: 1}, nil
+		}
+		svc := &fakeCardinalityService{
+			BucketService: bktSVC,
+			FindBucketCardinalityFn: func(ctx context.Context, id influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error) {
+				calls.id, calls.limit = id, opts.Limit
+				return &influxdb.BucketCardinality{
+					BucketID:     id,
+					Series:       3,
+					Measurements: []influxdb.MeasurementCardinality{{Name: "cpu", Series: 2}, {Name: "mem", Series: 1}},
+					TagKeys:      []influxdb.TagKeyCardinality{{Key: "host", Values: 10000, Truncated: true}},
+					Growth: []influxdb.CardinalitySample{
+						{Time: time.Unix(0, 0).UTC(), Series: 1},
+						{Time: time.Unix(600, 0).UTC(), Series: 3},
+					},
+				}, nil
+			},
+		}
+
+		return func(g *globalFlags, opt genericCLIOpts) *cobra.Command {
+			return newCmdCardinalityBuilder(func() (cardinalityService, error) { return svc, nil }, opt).cmd()
+		}, calls
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			defer addEnvVars(t, envVarsZeroMap)()
+
+			var buf bytes.Buffer
+			builder := newInfluxCmdBuilder(
+				in(new(bytes.Buffer)),
+				out(&buf),
+			)
+
+			cmdFn, calls := cmdFn()
+			cmd := builder.cmd(cmdFn)
+			args := append([]string{"cardinality"}, tt.command...)
+			cmd.SetArgs(append(args, tt.flags...))
+
+			require.NoError(t, cmd.Execute())
+			assert.Equal(t, tt.expected, *calls)
+			assert.Equal(t, tt.output, buf.String())
+		}
+
+		t.Run(tt.name, fn)
+	}
+}
@@ -214,6 +214,7 @@ func influxCmd(opts ...genericCLIOptFn) *cobra.Command {
 		cmdAuth,
 		cmdBackup,
 		cmdBucket,
+		cmdCardinality,
 		cmdDelete,
 		cmdOrganization,
 		cmdPing,
@@ -66,6 +66,7 @@ type cmdPkgBuilder struct {
 		dashboards   string
 		endpoints    string
 		labels       string
+		roles        string
 		rules        string
 		tasks        string
 		telegrafs    string
@@ -209,6 +210,7 @@ func (b *cmdPkgBuilder) cmdPkgExport() *cobra.Command {
 	cmd.Flags().StringVar(&b.exportOpts.dashboards, "dashboards", "", "List of dashboard ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.endpoints, "endpoints", "", "List of notification endpoint ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.labels, "labels", "", "List of label ids comma separated")
+	cmd.Flags().StringVar(&b.exportOpts.roles, "roles", "", "List of role ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.rules, "rules", "", "List of notification rule ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.tasks, "tasks", "", "List of task ids comma separated")
 	cmd.Flags().StringVar(&b.exportOpts.telegrafs, "telegraf-configs", "", "List of telegraf config ids comma separated")
@@ -235,6 +237,7 @@ func (b *cmdPkgBuilder) pkgExportRunEFn(cmd *cobra.Command, args []string) error
 		{kind: pkger.KindLabel, idStrs: strings.Split(b.exportOpts.labels, ",")},
 		{kind: pkger.KindNotificationEndpoint, idStrs: strings.Split(b.exportOpts.endpoints, ",")},
 		{kind: pkger.KindNotificationRule, idStrs: strings.Split(b.exportOpts.rules, ",")},
+		{kind: pkger.KindRole, idStrs: strings.Split(b.exportOpts.roles, ",")},
 		{kind: pkger.KindTask, idStrs: strings.Split(b.exportOpts.tasks, ",")},
 		{kind: pkger.KindTelegraf, idStrs: strings.Split(b.exportOpts.telegrafs, ",")},
 		{kind: pkger.KindVariable, idStrs: strings.Split(b.exportOpts.variables, ",")},
@@ -908,6 +911,32 @@ func (b *cmdPkgBuilder) printPkgDiff(diff pkger.Diff) error {
 		printer.Render()
 	}
 
+	if roles := diff.Roles; len(roles) > 0 {
+		printer := diffPrinterGen("Roles", []string{"Description", "Permissions"})
+
+		appendValues := func(id pkger.SafeID, pkgName string, v pkger.DiffRoleValues) []string {
+			return []string{pkgName, id.String(), v.Name, v.Description, printRolePermissions(v.Permissions)}
+		}
+
+		for _, r := range roles {
+			var oldRow []string
+			if r.Old != nil {
+				oldRow = appendValues(r.ID, r.PkgName, *r.Old)
+			}
+
+			newRow := appendValues(r.ID, r.PkgName, r.New)
+			switch {
+			case r.IsNew():
+				printer.AppendDiff(nil, newRow)
+			case r.Remove:
+				printer.AppendDiff(oldRow, nil)
+			default:
+				printer.AppendDiff(oldRow, newRow)
+			}
+		}
+		printer.Render()
+	}
+
 	if tasks := diff.Tasks; len(tasks) > 0 {
 		printer := diffPrinterGen("Tasks", []string{"Description", "Cycle"})
 		appendValues := func(id pkger.SafeID, pkgName string, v pkger.DiffTaskValues) []string {
@@ -1087,6 +1116,20 @@ func (b *cmdPkgBuilder) printPkgSummary(sum pkger.Summary) error {
 		})
 	}
 
+	if roles := sum.Roles; len(roles) > 0 {
+		headers := append(commonHeaders, "Description", "Permissions")
+		tablePrintFn("ROLES", headers, len(roles), func(i int) []string {
+			r := roles[i]
+			return []string{
+				r.PkgName,
+				r.ID.String(),
+				r.Name,
+				r.Description,
+				printRolePermissions(r.Permissions),
+			}
+		})
+	}
+
 	if tasks := sum.Tasks; len(tasks) > 0 {
 		headers := []string{"ID", "Name", "Description", "Cycle"}
 		tablePrintFn("TASKS", headers, len(tasks), func(i int) []string {
@@ -1417,6 +1460,21 @@ func printVarArgs(a *influxdb.VariableArguments) string {
 	return "unknown variable argument"
 }
 
+func printRolePermissions(perms []pkger.SummaryRolePermission) string {
+	var out []string
+	for _, p := range perms {
+		resource := string(p.ResourceType)
+		switch {
+		case p.ResourceName != "":
+			resource += "/" + p.ResourceName
+		case p.ResourceID != 0:
+			resource += "/" + p.ResourceID.String()
+		}
+		out = append(out, fmt.Sprintf("%s:%s", p.Action, resource))
+	}
+	return strings.Join(out, " ")
+}
+
 func formatDuration(d time.Duration) string {
 	if d == 0 {
 		return "inf"
@@ -25,9 +25,11 @@ var _ Engine = (*storage.Engine)(nil)
 // to facilitate testing.
 type Engine interface {
 	influxdb.DeleteService
+	influxdb.CardinalityService
 	reads.Viewer
 	storage.PointsWriter
 	storage.BucketDeleter
+	storage.BucketSettingsUpdater
 	prom.PrometheusCollector
 	influxdb.BackupService
 
@@ -120,6 +122,11 @@ func (t *TemporaryEngine) DeleteBucketRangePredicate(ctx context.Context, orgID,
 
 }
 
+// FindBucketCardinality returns the series cardinality of a bucket.
+func (t *TemporaryEngine) FindBucketCardinality(ctx context.Context, orgID, bucketID influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error) {
+	return t.engine.FindBucketCardinality(ctx, orgID, bucketID, opts)
+}
+
 // DeleteBucket deletes a bucket from the time-series data.
 func (t *TemporaryEngine) DeleteBucket(ctx context.Context, orgID, bucketID influxdb.ID) error {
 	return t.engine.DeleteBucket(ctx, orgID, bucketID)
@@ -178,3 +185,15 @@ func (t *TemporaryEngine) FetchBackupFile(ctx context.Context, backupID int, bac
 func (t *TemporaryEngine) InternalBackupPath(backupID int) string {
 	return t.engine.InternalBackupPath(backupID)
 }
+
+func (t *TemporaryEngine) UpdateBucketSettings(b *influxdb.Bucket) {
+	t.engine.UpdateBucketSettings(b)
+}
+
+func (t *TemporaryEngine) CreateBucketBackup(ctx context.Context, bucketID influxdb.ID) (int, []string, error) {
+	return t.engine.CreateBucketBackup(ctx, bucketID)
+}
+
+func (t *TemporaryEngine) FetchBucketBackupFile(ctx context.Context, bucketID influxdb.ID, backupID int, backupFile string, w io.Writer) error {
+	return t.engine.FetchBucketBackupFile(ctx, bucketID, backupID, backupFile, w)
+}
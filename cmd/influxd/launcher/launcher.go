@@ -3,9 +3,11 @@ package launcher
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"net"
 	nethttp "net/http"
@@ -26,6 +28,7 @@ import (
 	"github.com/influxdata/influxdb/v2/http"
 	"github.com/influxdata/influxdb/v2/inmem"
 	"github.com/influxdata/influxdb/v2/internal/fs"
+	"github.com/influxdata/influxdb/v2/jsonweb"
 	"github.com/influxdata/influxdb/v2/kit/cli"
 	"github.com/influxdata/influxdb/v2/kit/prom"
 	"github.com/influxdata/influxdb/v2/kit/signals"
@@ -34,6 +37,8 @@ import (
 	"github.com/influxdata/influxdb/v2/kv"
 	influxlogger "github.com/influxdata/influxdb/v2/logger"
 	"github.com/influxdata/influxdb/v2/nats"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+	"github.com/influxdata/influxdb/v2/oidc"
 	"github.com/influxdata/influxdb/v2/pkger"
 	infprom "github.com/influxdata/influxdb/v2/prometheus"
 	"github.com/influxdata/influxdb/v2/query"
@@ -51,8 +56,10 @@ import (
 	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
 	"github.com/influxdata/influxdb/v2/telemetry"
 	"github.com/influxdata/influxdb/v2/tenant"
+	"github.com/influxdata/influxdb/v2/toml"
+	"github.com/influxdata/influxdb/v2/tsdb/seriesfile"
 	_ "github.com/influxdata/influxdb/v2/tsdb/tsi1" // needed for tsi1
-	_ "github.com/influxdata/influxdb/v2/tsdb/tsm1" // needed for tsm1
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
 	"github.com/influxdata/influxdb/v2/vault"
 	pzap "github.com/influxdata/influxdb/v2/zap"
 	"github.com/opentracing/opentracing-go"
@@ -175,6 +182,81 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: filepath.Join(dir, "engine"),
 			Desc:    "path to persistent engine files",
 		},
+		{
+			DestP:   &l.storageCacheMaxLateness,
+			Flag:    "storage-cache-max-lateness",
+			Default: time.Duration(tsm1.DefaultCacheMaxLateness),
+			Desc:    "how old points can be before writes of them are rejected; 0 accepts points of any age",
+		},
+		{
+			DestP: &l.StorageConfig.MaxSeriesPerBucket,
+			Flag:  "storage-max-series-per-bucket",
+			Desc:  "maximum number of series of each bucket; writes creating more series are dropped; 0 disables the limit",
+		},
+		{
+			DestP: &l.StorageConfig.MaxSeriesPerMeasurement,
+			Flag:  "storage-max-series-per-measurement",
+			Desc:  "maximum number of series of each measurement; writes creating more series are dropped; 0 disables the limit",
+		},
+		{
+			DestP: &l.StorageConfig.BucketShards,
+			Flag:  "storage-bucket-shards",
+			Desc:  "store each new bucket in its own series file, index, WAL and TSM files so that it is dropped, compacted and backed up on its own; the cache memory limits apply to each bucket",
+		},
+		{
+			DestP:   &l.StorageConfig.WAL.Durability,
+			Flag:    "storage-wal-durability",
+			Default: tsm1.DefaultWALDurability,
+			Desc:    "durability of the writes to the WAL of buckets without their own: sync fsyncs each write, group shares fsyncs between concurrent writes, async returns before the fsync",
+		},
+		{
+			DestP:   &l.StorageConfig.SeriesFile.SegmentCompactThreshold,
+			Flag:    "storage-series-file-segment-compact-threshold",
+			Default: seriesfile.DefaultSegmentCompactThreshold,
+			Desc:    "number of series deleted from a partition of the series file before its segments are compacted; 0 disables segment compactions",
+		},
+		{
+			DestP:   &l.storageTierAge,
+			Flag:    "storage-tier-age",
+			Default: time.Duration(tsm1.DefaultTierAge),
+			Desc:    "how old the newest point of a fully compacted TSM file must be before the file is moved to the storage tier",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.Tier.Dir,
+			Flag:  "storage-tier-dir",
+			Desc:  "moves cold TSM files to this directory, typically on a cheaper disk",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.Tier.S3.Endpoint,
+			Flag:  "storage-tier-s3-endpoint",
+			Desc:  "base URL of the S3 compatible object store cold TSM files are moved to",
+		},
+		{
+			DestP:   &l.StorageConfig.Engine.Tier.S3.Region,
+			Flag:    "storage-tier-s3-region",
+			Default: tsm1.DefaultTierS3Region,
+			Desc:    "region of the S3 compatible object store",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.Tier.S3.Bucket,
+			Flag:  "storage-tier-s3-bucket",
+			Desc:  "moves cold TSM files to this bucket of the S3 compatible object store",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.Tier.S3.Prefix,
+			Flag:  "storage-tier-s3-prefix",
+			Desc:  "prefix of the names of the TSM files in the bucket",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.Tier.S3.AccessKeyID,
+			Flag:  "storage-tier-s3-access-key-id",
+			Desc:  "access key id of the S3 compatible object store",
+		},
+		{
+			DestP: &l.StorageConfig.Engine.Tier.S3.SecretAccessKey,
+			Flag:  "storage-tier-s3-secret-access-key",
+			Desc:  "secret access key of the S3 compatible object store",
+		},
 		{
 			DestP:   &l.secretStore,
 			Flag:    "secret-store",
@@ -199,6 +281,106 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP:   &l.passwordPolicy.MinLength,
+			Flag:    "password-min-length",
+			Default: platform.DefaultPasswordPolicy.MinLength,
+			Desc:    "minimum number of characters of user passwords",
+		},
+		{
+			DestP:   &l.passwordPolicy.RequireUppercase,
+			Flag:    "password-require-uppercase",
+			Default: false,
+			Desc:    "require user passwords to contain an uppercase letter",
+		},
+		{
+			DestP:   &l.passwordPolicy.RequireLowercase,
+			Flag:    "password-require-lowercase",
+			Default: false,
+			Desc:    "require user passwords to contain a lowercase letter",
+		},
+		{
+			DestP:   &l.passwordPolicy.RequireDigit,
+			Flag:    "password-require-digit",
+			Default: false,
+			Desc:    "require user passwords to contain a digit",
+		},
+		{
+			DestP:   &l.passwordPolicy.RequireSymbol,
+			Flag:    "password-require-symbol",
+			Default: false,
+			Desc:    "require user passwords to contain a symbol or punctuation character",
+		},
+		{
+			DestP:   &l.signinMaxFailures,
+			Flag:    "signin-max-failures",
+			Default: platform.DefaultSigninMaxFailures,
+			Desc:    "failed sign in attempts after which a user is locked out",
+		},
+		{
+			DestP:   &l.signinLockoutDuration,
+			Flag:    "signin-lockout-duration",
+			Default: platform.DefaultSigninLockoutDuration,
+			Desc:    "how long a user is first locked out for, doubling with each further failed attempt",
+		},
+		{
+			DestP: &l.oidcConfig.Issuer,
+			Flag:  "oidc-issuer",
+			Desc:  "URL of the OpenID Connect provider users can sign in with at /api/v2/signin/oidc. Empty disables OpenID Connect sign in",
+		},
+		{
+			DestP: &l.oidcConfig.ClientID,
+			Flag:  "oidc-client-id",
+			Desc:  "client id influxd is registered with at the OpenID Connect provider",
+		},
+		{
+			DestP: &l.oidcConfig.ClientSecret,
+			Flag:  "oidc-client-secret",
+			Desc:  "client secret influxd is registered with at the OpenID Connect provider",
+		},
+		{
+			DestP: &l.oidcConfig.RedirectURL,
+			Flag:  "oidc-redirect-url",
+			Desc:  "external URL of the /api/v2/signin/oidc/callback route of influxd, registered at the OpenID Connect provider",
+		},
+		{
+			DestP: &l.oidcConfig.Scopes,
+			Flag:  "oidc-scopes",
+			Desc:  "scopes requested in addition to openid, for example profile,groups",
+		},
+		{
+			DestP:   &l.oidcConfig.UsernameClaim,
+			Flag:    "oidc-username-claim",
+			Default: oidc.DefaultUsernameClaim,
+			Desc:    "ID token claim holding the name of the user, users are created on their first sign in",
+		},
+		{
+			DestP:   &l.oidcConfig.OrgsClaim,
+			Flag:    "oidc-orgs-claim",
+			Default: oidc.DefaultOrgsClaim,
+			Desc:    "ID token claim holding the names of the existing organizations the user is made a member of",
+		},
+		{
+			DestP: &l.jwtJWKS,
+			Flag:  "jwt-jwks",
+			Desc:  "path or URL of a JSON Web Key Set, RS256, ES256 and HS256 JWTs signed with its keys are accepted as tokens. Empty disables JWT tokens",
+		},
+		{
+			DestP:   &l.jwtJWKSRefresh,
+			Flag:    "jwt-jwks-refresh-interval",
+			Default: jsonweb.DefaultJWKSRefreshInterval,
+			Desc:    "how often the JSON Web Key Set is reloaded",
+		},
+		{
+			DestP: &l.jwtIssuer,
+			Flag:  "jwt-issuer",
+			Desc:  "required iss claim of JWT tokens",
+		},
+		{
+			DestP: &l.jwtAudience,
+			Flag:  "jwt-audience",
+			Desc:  "required aud claim of JWT tokens",
+		},
 		{
 			DestP: &vaultConfig.Address,
 			Flag:  "vault-addr",
@@ -261,6 +443,18 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: "",
 			Desc:    "TLS key for HTTPs",
 		},
+		{
+			DestP:   &l.httpTLSClientCA,
+			Flag:    "tls-client-ca",
+			Default: "",
+			Desc:    "PEM-encoded CA bundle to verify client certificates against. Clients with a verified certificate are authenticated by the certificate mappings at /api/v2/certificatemappings",
+		},
+		{
+			DestP:   &l.httpTLSClientCertRequired,
+			Flag:    "tls-client-cert-required",
+			Default: false,
+			Desc:    "refuse TLS connections from clients without a certificate verified against --tls-client-ca",
+		},
 		{
 			DestP:   &l.enableNewMetaStore,
 			Flag:    "new-meta-store",
@@ -279,6 +473,18 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables the task scheduler",
 		},
+		{
+			DestP:   &l.taskLeaseTTL,
+			Flag:    "task-lease-ttl",
+			Default: time.Duration(0),
+			Desc:    "shares the execution of tasks with the other influxd nodes using the same metadata store; each task is executed by the node holding its lease, which expires after this duration unless renewed. Zero disables leases, otherwise it must be at least 1s",
+		},
+		{
+			DestP:   &l.taskLeaseOwner,
+			Flag:    "task-lease-owner",
+			Default: "",
+			Desc:    "the name task leases are held under, unique to this node. Defaults to the host name and a random suffix",
+		},
 		{
 			DestP:   &l.concurrencyQuota,
 			Flag:    "query-concurrency",
@@ -321,11 +527,19 @@ type Launcher struct {
 	cancel  func()
 	running bool
 
-	storeType            string
-	assetsPath           string
-	testing              bool
-	sessionLength        int // in minutes
-	sessionRenewDisabled bool
+	storeType             string
+	assetsPath            string
+	testing               bool
+	sessionLength         int // in minutes
+	sessionRenewDisabled  bool
+	passwordPolicy        platform.PasswordPolicy
+	signinMaxFailures     int
+	signinLockoutDuration time.Duration
+	oidcConfig            oidc.Config
+	jwtJWKS               string
+	jwtJWKSRefresh        time.Duration
+	jwtIssuer             string
+	jwtAudience           string
 
 	logLevel          string
 	tracingType       string
@@ -346,24 +560,31 @@ type Launcher struct {
 	maxMemoryBytes                  int
 	queueSize                       int
 
-	boltClient    *bolt.Client
-	kvStore       kv.Store
-	kvService     *kv.Service
-	engine        Engine
-	StorageConfig storage.Config
+	boltClient              *bolt.Client
+	kvStore                 kv.Store
+	kvService               *kv.Service
+	engine                  Engine
+	StorageConfig           storage.Config
+	storageTierAge          time.Duration
+	storageCacheMaxLateness time.Duration
 
 	queryController *control.Controller
 
-	httpPort    int
-	httpServer  *nethttp.Server
-	httpTLSCert string
-	httpTLSKey  string
+	httpPort                  int
+	httpServer                *nethttp.Server
+	httpTLSCert               string
+	httpTLSKey                string
+	httpTLSClientCA           string
+	httpTLSClientCertRequired bool
 
 	natsServer *nats.Server
 	natsPort   int
 
 	noTasks            bool
+	taskLeaseTTL       time.Duration
+	taskLeaseOwner     string
 	scheduler          stoppingScheduler
+	taskLeases         *coordinator.Leases
 	executor           *executor.Executor
 	taskControlService taskbackend.TaskControlService
 
@@ -496,6 +717,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return fmt.Errorf("unknown log level; supported levels are debug, info, and error")
 	}
 
+	if m.taskLeaseTTL < 0 || m.taskLeaseTTL > 0 && m.taskLeaseTTL < coordinator.MinLeaseTTL {
+		return fmt.Errorf("task lease ttl must be zero or at least %s", coordinator.MinLeaseTTL)
+	}
+
 	// Create top level logger
 	logconf := &influxlogger.Config{
 		Format: "auto",
@@ -544,7 +769,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	serviceConfig := kv.ServiceConfig{
-		SessionLength: time.Duration(m.sessionLength) * time.Minute,
+		SessionLength:         time.Duration(m.sessionLength) * time.Minute,
+		PasswordPolicy:        m.passwordPolicy,
+		SigninMaxFailures:     m.signinMaxFailures,
+		SigninLockoutDuration: m.signinLockoutDuration,
 	}
 
 	flushers := flushers{}
@@ -587,6 +815,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		authSvc                   platform.AuthorizationService            = m.kvService
 		userSvc                   platform.UserService                     = m.kvService
 		variableSvc               platform.VariableService                 = m.kvService
+		roleSvc                   platform.RoleService                     = m.kvService
 		bucketSvc                 platform.BucketService                   = m.kvService
 		sourceSvc                 platform.SourceService                   = m.kvService
 		sessionSvc                platform.SessionService                  = m.kvService
@@ -621,10 +850,10 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 				return err
 			}
 			oldSvc := m.kvService
-			newSvc := tenant.NewService(store)
+			newSvc := tenant.NewService(store, tenant.WithPasswordPolicy(m.passwordPolicy))
 			ts = tenant.NewDuplicateReadTenantService(m.log, oldSvc, newSvc)
 		} else {
-			ts = tenant.NewService(store)
+			ts = tenant.NewService(store, tenant.WithPasswordPolicy(m.passwordPolicy))
 		}
 		userSvcForAuth = ts
 
@@ -659,13 +888,15 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	m.StorageConfig.Engine.Tier.Age = toml.Duration(m.storageTierAge)
+	m.StorageConfig.Engine.Cache.MaxLateness = toml.Duration(m.storageCacheMaxLateness)
 	if m.testing {
 		// the testing engine will write/read into a temporary directory
-		engine := NewTemporaryEngine(m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc))
+		engine := NewTemporaryEngine(m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc), storage.WithBucketSettings(bucketSvc))
 		flushers = append(flushers, engine)
 		m.engine = engine
 	} else {
-		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc))
+		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc), storage.WithBucketSettings(bucketSvc))
 	}
 	m.engine.WithLogger(m.log)
 	if err := m.engine.Open(ctx); err != nil {
@@ -722,11 +953,25 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			authSvc,
 			combinedTaskService,
 			combinedTaskService,
+			executor.WithNotificationEndpoints(m.kvService, endpoint.NewSender(secretSvc)),
 		)
 		m.executor = executor
 		m.reg.MustRegister(executorMetrics.PrometheusCollectors()...)
 		schLogger := m.log.With(zap.String("service", "task-scheduler"))
 
+		var leases *coordinator.Leases
+		var schExecutor scheduler.Executor = executor
+		if m.taskLeaseTTL > 0 {
+			owner := m.taskLeaseOwner
+			if owner == "" {
+				host, _ := os.Hostname()
+				owner = host + "-" + snowflake.NewIDGenerator().ID().String()
+			}
+			leases = coordinator.NewLeases(m.kvService, owner, m.taskLeaseTTL)
+			schExecutor = leases.Executor(executor)
+			m.log.Info("Sharing tasks with other nodes", zap.String("owner", owner), zap.Duration("ttl", m.taskLeaseTTL))
+		}
+
 		var sch stoppingScheduler = &scheduler.NoopScheduler{}
 		if !m.noTasks {
 			var (
@@ -734,7 +979,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 				err error
 			)
 			sch, sm, err = scheduler.NewScheduler(
-				executor,
+				schExecutor,
 				taskbackend.NewSchedulableTaskService(m.kvService),
 				scheduler.WithOnErrorFn(func(ctx context.Context, taskID scheduler.ID, scheduledAt time.Time, err error) {
 					schLogger.Info(
@@ -756,7 +1001,8 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		taskCoord := coordinator.NewCoordinator(
 			coordLogger,
 			sch,
-			executor)
+			executor,
+			coordinator.WithLeases(leases))
 
 		taskSvc = middleware.New(combinedTaskService, taskCoord)
 		m.taskControlService = combinedTaskService
@@ -772,17 +1018,19 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			coordLogger); err != nil {
 			m.log.Error("Failed to resume existing tasks", zap.Error(err))
 		}
+		m.taskLeases = leases
+		go taskCoord.RunHeartbeats(ctx, combinedTaskService)
 	}
 
 	var checkSvc platform.CheckService
 	{
-		coordinator := coordinator.NewCoordinator(m.log, m.scheduler, m.executor)
+		coordinator := coordinator.NewCoordinator(m.log, m.scheduler, m.executor, coordinator.WithLeases(m.taskLeases))
 		checkSvc = middleware.NewCheckService(m.kvService, m.kvService, coordinator)
 	}
 
 	var notificationRuleSvc platform.NotificationRuleStore
 	{
-		coordinator := coordinator.NewCoordinator(m.log, m.scheduler, m.executor)
+		coordinator := coordinator.NewCoordinator(m.log, m.scheduler, m.executor, coordinator.WithLeases(m.taskLeases))
 		notificationRuleSvc = middleware.NewNotificationRuleStore(m.kvService, m.kvService, coordinator)
 	}
 
@@ -864,6 +1112,24 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		Addr: m.httpBindAddress,
 	}
 
+	var oidcProvider *oidc.Provider
+	if m.oidcConfig.Issuer != "" {
+		if oidcProvider, err = oidc.NewProvider(m.oidcConfig, nil); err != nil {
+			m.log.Error("Failed to configure oidc sign in", zap.Error(err))
+			return err
+		}
+	}
+
+	var tokenParser *jsonweb.TokenParser
+	if m.jwtJWKS != "" {
+		keyStore := jsonweb.NewJWKSKeyStore(m.jwtJWKS, jsonweb.WithJWKSRefreshInterval(m.jwtJWKSRefresh))
+		if err := keyStore.Load(); err != nil {
+			m.log.Error("Failed to load jwt key set", zap.Error(err))
+			return err
+		}
+		tokenParser = jsonweb.NewTokenParser(keyStore, jsonweb.WithIssuer(m.jwtIssuer), jsonweb.WithAudience(m.jwtAudience))
+	}
+
 	m.apibackend = &http.APIBackend{
 		AssetsPath:           m.assetsPath,
 		HTTPErrorHandler:     kithttp.ErrorHandler(0),
@@ -873,6 +1139,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		NewQueryService:      source.NewQueryService,
 		PointsWriter:         pointsWriter,
 		DeleteService:        deleteService,
+		CardinalityService:   m.engine,
 		BackupService:        backupService,
 		KVBackupService:      m.kvService,
 		AuthorizationService: authSvc,
@@ -890,6 +1157,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		OrganizationOperationLogService: orgLogSvc,
 		SourceService:                   sourceSvc,
 		VariableService:                 variableSvc,
+		RoleService:                     roleSvc,
 		PasswordsService:                passwdsSvc,
 		InfluxQLService:                 storageQueryService,
 		FluxService:                     storageQueryService,
@@ -904,6 +1172,15 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		LookupService:                   lookupSvc,
 		DocumentService:                 m.kvService,
 		OrgLookupService:                m.kvService,
+		AuthorizationUsageRecorder:      m.kvService,
+		CertificateMappingService:       m.kvService,
+		DashboardShareService:           m.kvService,
+		AuditLogService:                 m.kvService,
+		RateLimitService:                m.kvService,
+		SigninLockoutService:            m.kvService,
+		MFAService:                      m.kvService,
+		OIDCProvider:                    oidcProvider,
+		TokenParser:                     tokenParser,
 		WriteEventRecorder:              infprom.NewEventRecorder("write"),
 		QueryEventRecorder:              infprom.NewEventRecorder("query"),
 	}
@@ -917,6 +1194,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		b := m.apibackend
 		authedOrgSVC := authorizer.NewOrgService(b.OrganizationService)
 		authedURMSVC := authorizer.NewURMService(b.OrgLookupService, b.UserResourceMappingService)
+		authedURMSVC.RoleService = b.RoleService
 		pkgerLogger := m.log.With(zap.String("service", "pkger"))
 		pkgSVC = pkger.NewService(
 			pkger.WithLogger(pkgerLogger),
@@ -928,6 +1206,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			pkger.WithNotificationEndpointSVC(authorizer.NewNotificationEndpointService(b.NotificationEndpointService, authedURMSVC, authedOrgSVC)),
 			pkger.WithNotificationRuleSVC(authorizer.NewNotificationRuleStore(b.NotificationRuleStore, authedURMSVC, authedOrgSVC)),
 			pkger.WithOrganizationService(authorizer.NewOrgService(b.OrganizationService)),
+			pkger.WithRoleSVC(authorizer.NewRoleService(b.RoleService)),
 			pkger.WithSecretSVC(authorizer.NewSecretService(b.SecretService)),
 			pkger.WithTaskSVC(authorizer.NewTaskService(pkgerLogger, b.TaskService)),
 			pkger.WithTelegrafSVC(authorizer.NewTelegrafConfigService(b.TelegrafService, b.UserResourceMappingService)),
@@ -997,6 +1276,18 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		transport = "https"
 
 		m.httpServer.TLSConfig = &tls.Config{}
+		if m.httpTLSClientCA != "" {
+			if err := m.configureClientCertificates(m.httpServer.TLSConfig); err != nil {
+				m.log.Error("failed to load client CA bundle", zap.Error(err))
+				m.log.Info("Stopping")
+				return err
+			}
+		}
+	} else if m.httpTLSClientCA != "" {
+		err := errors.New("--tls-client-ca requires --tls-cert and --tls-key")
+		m.log.Error("failed to configure client certificates", zap.Error(err))
+		m.log.Info("Stopping")
+		return err
 	}
 
 	if addr, ok := ln.Addr().(*net.TCPAddr); ok {
@@ -1023,6 +1314,27 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	return nil
 }
 
+// configureClientCertificates makes the TLS server verify client certificates
+// against the client CA bundle, requiring them if configured to.
+func (m *Launcher) configureClientCertificates(c *tls.Config) error {
+	pem, err := ioutil.ReadFile(m.httpTLSClientCA)
+	if err != nil {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("no certificates found in %s", m.httpTLSClientCA)
+	}
+	c.ClientCAs = pool
+
+	c.ClientAuth = tls.VerifyClientCertIfGiven
+	if m.httpTLSClientCertRequired {
+		c.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return nil
+}
+
 // isAddressPortAvailable checks whether the address:port is available to listen,
 // by using net.Listen to verify that the port opens successfully, then closes the listener.
 func isAddressPortAvailable(address string, port int) (bool, error) {
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/bolt"
 	"github.com/influxdata/influxdb/v2/cmd/influxd/inspect"
 	"github.com/influxdata/influxdb/v2/http"
@@ -30,6 +31,16 @@ Rebuilding the index and series file uses default options as in
 For additional performance options, run restore with "-rebuild-index false"
 and build-tsi afterwards.
 
+The TSM files of the buckets stored on their own (see
+"--storage-bucket-shards") are restored to the shards of the buckets in
+the buckets directory of the target engine path, and their index and
+series file are rebuilt in the same way.
+
+With --bucket-id, a backup of a single bucket (see "influx backup
+--bucket-id") is restored to the shard of the bucket, replacing only the data
+of the bucket. The bucket must exist in the target metadata, which is left
+as is.
+
 NOTES:
 
 * The influxd server should not be running when using the restore tool
@@ -44,6 +55,7 @@ var flags struct {
 	enginePath string
 	credPath   string
 	backupPath string
+	bucketID   string
 	rebuildTSI bool
 }
 
@@ -83,6 +95,11 @@ func init() {
 			Default: "",
 			Desc:    "path to backup files",
 		},
+		{
+			DestP: &flags.bucketID,
+			Flag:  "bucket-id",
+			Desc:  "the ID of the bucket of a backup of a single bucket to restore",
+		},
 		{
 			DestP:   &flags.rebuildTSI,
 			Flag:    "rebuild-index",
@@ -99,6 +116,10 @@ func restoreE(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no backup path given")
 	}
 
+	if flags.bucketID != "" {
+		return restoreBucket()
+	}
+
 	if err := moveBolt(); err != nil {
 		return fmt.Errorf("failed to move existing bolt file: %v", err)
 	}
@@ -119,17 +140,15 @@ func restoreE(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to restore credentials file: %v", err)
 	}
 
-	if err := restoreEngine(); err != nil {
+	shardPaths, err := restoreEngine()
+	if err != nil {
 		return fmt.Errorf("failed to restore all TSM files: %v", err)
 	}
 
 	if flags.rebuildTSI {
-		sFilePath := filepath.Join(flags.enginePath, storage.DefaultSeriesFileDirectoryName)
-		indexPath := filepath.Join(flags.enginePath, storage.DefaultIndexDirectoryName)
-
-		rebuild := inspect.NewBuildTSICommand()
-		rebuild.SetArgs([]string{"--sfile-path", sFilePath, "--tsi-path", indexPath})
-		rebuild.Execute()
+		for _, shardPath := range shardPaths {
+			rebuildTSI(shardPath)
+		}
 	}
 
 	if err := removeTmpBolt(); err != nil {
@@ -147,6 +166,60 @@ func restoreE(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// restoreBucket restores a backup of a single bucket to the shard of the
+// bucket, moving the existing data of the bucket while it runs.
+func restoreBucket() error {
+	bucketID, err := influxdb.IDFromString(flags.bucketID)
+	if err != nil {
+		return fmt.Errorf("invalid bucket ID: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(flags.backupPath, "*.tsm"))
+	if err != nil {
+		return err
+	} else if len(files) == 0 {
+		return fmt.Errorf("no TSM files in backup")
+	}
+
+	shardPath := filepath.Join(flags.enginePath, storage.DefaultBucketsDirectoryName, bucketID.String())
+	tmpShardPath := shardPath + ".tmp"
+	if err := removeIfExists(tmpShardPath); err != nil {
+		return fmt.Errorf("failed to move existing bucket data: %v", err)
+	} else if err := os.Rename(shardPath, tmpShardPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to move existing bucket data: %v", err)
+	}
+
+	dataDir := filepath.Join(shardPath, storage.DefaultEngineDirectoryName)
+	if err := os.MkdirAll(dataDir, 0777); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := restoreFile(file, filepath.Join(dataDir, filepath.Base(file)), "TSM"); err != nil {
+			return fmt.Errorf("failed to restore all TSM files: %v", err)
+		}
+	}
+	fmt.Printf("Restored %d TSM files to %v\n", len(files), shardPath)
+
+	if flags.rebuildTSI {
+		rebuildTSI(shardPath)
+	}
+
+	if err := removeIfExists(tmpShardPath); err != nil {
+		return fmt.Errorf("restore completed, but failed to cleanup temporary bucket data: %v", err)
+	}
+	return nil
+}
+
+// rebuildTSI rebuilds the index and series file of the shard at shardPath.
+func rebuildTSI(shardPath string) {
+	sFilePath := filepath.Join(shardPath, storage.DefaultSeriesFileDirectoryName)
+	indexPath := filepath.Join(shardPath, storage.DefaultIndexDirectoryName)
+
+	rebuild := inspect.NewBuildTSICommand()
+	rebuild.SetArgs([]string{"--sfile-path", sFilePath, "--tsi-path", indexPath})
+	rebuild.Execute()
+}
+
 func moveBolt() error {
 	if _, err := os.Stat(flags.boltPath); os.IsNotExist(err) {
 		return nil
@@ -230,14 +303,25 @@ func restoreBolt() error {
 	return nil
 }
 
-func restoreEngine() error {
+// restoreEngine restores the TSM files of the backup, returning the paths of
+// the shards restored. The TSM files of the buckets stored on their own are
+// restored in the shards of the buckets.
+func restoreEngine() ([]string, error) {
 	dataDir := filepath.Join(flags.enginePath, "/data")
 	if err := os.MkdirAll(dataDir, 0777); err != nil {
-		return err
+		return nil, err
 	}
+	shardPaths := []string{flags.enginePath}
+	backupPath := filepath.Clean(flags.backupPath)
 
 	count := 0
-	err := filepath.Walk(flags.backupPath, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(backupPath, func(path string, info os.FileInfo, err error) error {
+		if info != nil && info.IsDir() && filepath.Dir(path) == filepath.Join(backupPath, storage.DefaultBucketsDirectoryName) {
+			shardPath := filepath.Join(flags.enginePath, storage.DefaultBucketsDirectoryName, info.Name())
+			shardPaths = append(shardPaths, shardPath)
+			return os.MkdirAll(filepath.Join(shardPath, storage.DefaultEngineDirectoryName), 0777)
+		}
+
 		if strings.Contains(path, ".tsm") {
 			f, err := os.OpenFile(path, os.O_RDONLY, 0666)
 			if err != nil {
@@ -246,6 +330,9 @@ func restoreEngine() error {
 			defer f.Close()
 
 			tsmPath := filepath.Join(dataDir, filepath.Base(path))
+			if shardPath := shardPaths[len(shardPaths)-1]; filepath.Dir(path) != backupPath {
+				tsmPath = filepath.Join(shardPath, storage.DefaultEngineDirectoryName, filepath.Base(path))
+			}
 			w, err := os.OpenFile(tsmPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
 			if err != nil {
 				return err
@@ -261,8 +348,8 @@ func restoreEngine() error {
 		}
 		return nil
 	})
-	fmt.Printf("Restored %d TSM files to %v\n", count, dataDir)
-	return err
+	fmt.Printf("Restored %d TSM files to %v\n", count, flags.enginePath)
+	return shardPaths, err
 }
 
 func restoreFile(backup string, target string, filetype string) error {
@@ -0,0 +1,32 @@
+package context
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+const (
+	auditCtxKey contextKey = "influx/audit/v1"
+)
+
+type auditSource struct {
+	rec influxdb.AuditRecorder
+	ip  string
+}
+
+// SetAuditRecorder sets the recorder of audit events on context, along with
+// the address of the client the operations are made by.
+func SetAuditRecorder(ctx context.Context, rec influxdb.AuditRecorder, sourceIP string) context.Context {
+	return context.WithValue(ctx, auditCtxKey, auditSource{rec: rec, ip: sourceIP})
+}
+
+// GetAuditRecorder retrieves the audit recorder and the client address from context.
+// The recorder is nil when operations are not audited.
+func GetAuditRecorder(ctx context.Context) (influxdb.AuditRecorder, string) {
+	src, ok := ctx.Value(auditCtxKey).(auditSource)
+	if !ok {
+		return nil, ""
+	}
+	return src.rec, src.ip
+}
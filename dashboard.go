@@ -858,6 +858,31 @@ func (v MarkdownViewProperties) GetType() string       { return v.Type }
 func (v LogViewProperties) GetType() string            { return v.Type }
 func (v CheckViewProperties) GetType() string          { return v.Type }
 
+// ViewQueries returns the queries of the view properties, or nil for views without queries.
+func ViewQueries(p ViewProperties) []DashboardQuery {
+	switch p := p.(type) {
+	case LinePlusSingleStatProperties:
+		return p.Queries
+	case XYViewProperties:
+		return p.Queries
+	case CheckViewProperties:
+		return p.Queries
+	case SingleStatViewProperties:
+		return p.Queries
+	case HistogramViewProperties:
+		return p.Queries
+	case HeatmapViewProperties:
+		return p.Queries
+	case ScatterViewProperties:
+		return p.Queries
+	case GaugeViewProperties:
+		return p.Queries
+	case TableViewProperties:
+		return p.Queries
+	}
+	return nil
+}
+
 /////////////////////////////
 // Old Chronograf Types
 /////////////////////////////
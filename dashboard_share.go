@@ -0,0 +1,116 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// DashboardShareKind is returned by (*DashboardShare).Kind().
+const DashboardShareKind = "dashboardshare"
+
+// ErrDashboardShareNotFound is the error msg for a missing dashboard share link.
+const ErrDashboardShareNotFound = "dashboard share link not found"
+
+// ops for dashboard share errors.
+const (
+	OpFindDashboardShareByID    = "FindDashboardShareByID"
+	OpFindDashboardShareByToken = "FindDashboardShareByToken"
+	OpFindDashboardShares       = "FindDashboardShares"
+	OpCreateDashboardShare      = "CreateDashboardShare"
+	OpDeleteDashboardShare      = "DeleteDashboardShare"
+)
+
+// DashboardShareService describes a service for managing the share links of dashboards.
+type DashboardShareService interface {
+	// FindDashboardShareByID finds a single share link by its ID.
+	FindDashboardShareByID(ctx context.Context, id ID) (*DashboardShare, error)
+
+	// FindDashboardShareByToken finds a single share link by its token.
+	FindDashboardShareByToken(ctx context.Context, token string) (*DashboardShare, error)
+
+	// FindDashboardShares returns the share links matching the filter.
+	FindDashboardShares(ctx context.Context, filter DashboardShareFilter) ([]*DashboardShare, int, error)
+
+	// CreateDashboardShare creates a share link of a dashboard, assigning it an ID and token.
+	CreateDashboardShare(ctx context.Context, s *DashboardShare) error
+
+	// DeleteDashboardShare revokes a share link.
+	DeleteDashboardShare(ctx context.Context, id ID) error
+}
+
+// DashboardShare is a share link of a dashboard. Its token grants read-only access
+// to the dashboard, and to run the queries stored in the views of its cells.
+type DashboardShare struct {
+	ID          ID     `json:"id,omitempty"`
+	DashboardID ID     `json:"dashboardID"`
+	OrgID       ID     `json:"orgID,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Token is only known when the share link is created.
+	Token     string     `json:"token,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CRUDLog
+}
+
+// Valid returns an error if the share link is missing its dashboard.
+func (s *DashboardShare) Valid() error {
+	if !s.DashboardID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "dashboard share link dashboard id is required",
+		}
+	}
+	return nil
+}
+
+// IsExpired returns true if the share link expired at or before now.
+func (s *DashboardShare) IsExpired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// Allowed returns true for reading the shared dashboard and the variables of its
+// organization, used to offer the choices of the variables of the dashboard queries.
+func (s *DashboardShare) Allowed(p Permission) bool {
+	if s.IsExpired(time.Now()) || p.Action != ReadAction {
+		return false
+	}
+
+	switch p.Resource.Type {
+	case DashboardsResourceType:
+		return p.Resource.ID != nil && *p.Resource.ID == s.DashboardID
+	case VariablesResourceType:
+		return p.Resource.OrgID != nil && *p.Resource.OrgID == s.OrgID
+	}
+	return false
+}
+
+// Identifier returns the share link's ID and is used for auditing.
+func (s *DashboardShare) Identifier() ID { return s.ID }
+
+// GetUserID returns an invalid id, share links are not held by a user.
+func (s *DashboardShare) GetUserID() ID { return 0 }
+
+// Kind returns dashboardshare and is used for auditing.
+func (s *DashboardShare) Kind() string { return DashboardShareKind }
+
+// EphemeralAuth generates an Authorization to read the buckets of the organization
+// of the shared dashboard. It must only run the queries of the dashboard.
+func (s *DashboardShare) EphemeralAuth() *Authorization {
+	return &Authorization{
+		ID:     s.ID,
+		OrgID:  s.OrgID,
+		Status: Active,
+		Permissions: []Permission{
+			{
+				Action:   ReadAction,
+				Resource: Resource{Type: BucketsResourceType, OrgID: &s.OrgID},
+			},
+		},
+	}
+}
+
+// DashboardShareFilter represents a set of filters that restrict the returned share links.
+type DashboardShareFilter struct {
+	ID          *ID
+	DashboardID *ID
+	OrgID       *ID
+}
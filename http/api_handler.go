@@ -8,8 +8,10 @@ import (
 	"github.com/influxdata/influxdb/v2/authorizer"
 	"github.com/influxdata/influxdb/v2/chronograf/server"
 	"github.com/influxdata/influxdb/v2/http/metric"
+	"github.com/influxdata/influxdb/v2/jsonweb"
 	"github.com/influxdata/influxdb/v2/kit/prom"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/oidc"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/storage"
 	"github.com/prometheus/client_golang/prometheus"
@@ -52,9 +54,14 @@ type APIBackend struct {
 
 	PointsWriter                    storage.PointsWriter
 	DeleteService                   influxdb.DeleteService
+	CardinalityService              influxdb.CardinalityService
 	BackupService                   influxdb.BackupService
 	KVBackupService                 influxdb.KVBackupService
 	AuthorizationService            influxdb.AuthorizationService
+	AuthorizationUsageRecorder      influxdb.AuthorizationUsageRecorder
+	CertificateMappingService       influxdb.CertificateMappingService
+	OIDCProvider                    *oidc.Provider
+	TokenParser                     *jsonweb.TokenParser
 	BucketService                   influxdb.BucketService
 	SessionService                  influxdb.SessionService
 	UserService                     influxdb.UserService
@@ -63,11 +70,17 @@ type APIBackend struct {
 	LabelService                    influxdb.LabelService
 	DashboardService                influxdb.DashboardService
 	DashboardOperationLogService    influxdb.DashboardOperationLogService
+	DashboardShareService           influxdb.DashboardShareService
 	BucketOperationLogService       influxdb.BucketOperationLogService
 	UserOperationLogService         influxdb.UserOperationLogService
 	OrganizationOperationLogService influxdb.OrganizationOperationLogService
 	SourceService                   influxdb.SourceService
 	VariableService                 influxdb.VariableService
+	RoleService                     influxdb.RoleService
+	AuditLogService                 influxdb.AuditLogService
+	RateLimitService                influxdb.RateLimitService
+	SigninLockoutService            influxdb.SigninLockoutService
+	MFAService                      influxdb.MFAService
 	PasswordsService                influxdb.PasswordsService
 	InfluxQLService                 query.ProxyQueryService
 	FluxService                     query.ProxyQueryService
@@ -117,19 +130,35 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	}
 
 	noAuthUserResourceMappingService := b.UserResourceMappingService
-	b.UserResourceMappingService = authorizer.NewURMService(b.OrgLookupService, b.UserResourceMappingService)
+	urmService := authorizer.NewURMService(b.OrgLookupService, b.UserResourceMappingService)
+	urmService.RoleService = b.RoleService
+	b.UserResourceMappingService = urmService
 	b.LabelService = authorizer.NewLabelServiceWithOrg(b.LabelService, b.OrgLookupService)
 
 	h.Mount("/api/v2", serveLinksHandler(b.HTTPErrorHandler))
 
+	// the rate limiter enforces limits as they are changed through it, and
+	// keeps the usage of the write and query APIs.
+	rateLimiter := NewRateLimiter(b.Logger.With(zap.String("service", "ratelimit")), b.HTTPErrorHandler, b.RateLimitService)
+
+	auditBackend := NewAuditBackend(b.Logger.With(zap.String("handler", "audit")), b)
+	auditBackend.AuditLogService = authorizer.NewAuditLogService(b.AuditLogService)
+	h.Mount(prefixAudit, NewAuditHandler(b.Logger, auditBackend))
+
 	authorizationBackend := NewAuthorizationBackend(b.Logger.With(zap.String("handler", "authorization")), b)
-	authorizationBackend.AuthorizationService = authorizer.NewAuthorizationService(b.AuthorizationService)
+	authorizationService := authorizer.NewAuthorizationService(b.AuthorizationService)
+	authorizationService.RoleService = b.RoleService
+	authorizationBackend.AuthorizationService = authorizationService
 	h.Mount(prefixAuthorization, NewAuthorizationHandler(b.Logger, authorizationBackend))
 
 	bucketBackend := NewBucketBackend(b.Logger.With(zap.String("handler", "bucket")), b)
 	bucketBackend.BucketService = authorizer.NewBucketService(b.BucketService, noAuthUserResourceMappingService)
 	h.Mount(prefixBuckets, NewBucketHandler(b.Logger, bucketBackend))
 
+	certificateMappingBackend := NewCertificateMappingBackend(b.Logger.With(zap.String("handler", "certificatemapping")), b)
+	certificateMappingBackend.CertificateMappingService = authorizer.NewCertificateMappingService(b.CertificateMappingService, b.AuthorizationService)
+	h.Mount(prefixCertificateMappings, NewCertificateMappingHandler(b.Logger, certificateMappingBackend))
+
 	checkBackend := NewCheckBackend(b.Logger.With(zap.String("handler", "check")), b)
 	checkBackend.CheckService = authorizer.NewCheckService(b.CheckService,
 		b.UserResourceMappingService, b.OrganizationService)
@@ -139,6 +168,7 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 
 	dashboardBackend := NewDashboardBackend(b.Logger.With(zap.String("handler", "dashboard")), b)
 	dashboardBackend.DashboardService = authorizer.NewDashboardService(b.DashboardService)
+	dashboardBackend.DashboardShareService = authorizer.NewDashboardShareService(b.DashboardShareService, dashboardBackend.DashboardService)
 	h.Mount(prefixDashboards, NewDashboardHandler(b.Logger, dashboardBackend))
 
 	deleteBackend := NewDeleteBackend(b.Logger.With(zap.String("handler", "delete")), b)
@@ -149,10 +179,16 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.Mount(prefixDocuments, NewDocumentHandler(documentBackend))
 
 	fluxBackend := NewFluxBackend(b.Logger.With(zap.String("handler", "query")), b)
-	h.Mount(prefixQuery, NewFluxHandler(b.Logger, fluxBackend))
+	fluxBackend.DashboardService = authorizer.NewDashboardService(b.DashboardService)
+	fluxBackend.VariableService = authorizer.NewVariableService(b.VariableService)
+	h.Mount(prefixQuery, rateLimiter.LimitQueries(NewFluxHandler(b.Logger, fluxBackend)))
 
 	h.Mount(prefixLabels, NewLabelHandler(b.Logger, b.LabelService, b.HTTPErrorHandler))
 
+	mfaBackend := NewMFABackend(b.Logger.With(zap.String("handler", "mfa")), b)
+	mfaBackend.MFAService = authorizer.NewMFAService(b.MFAService)
+	h.Mount(prefixMFA, NewMFAHandler(b.Logger, mfaBackend))
+
 	notificationEndpointBackend := NewNotificationEndpointBackend(b.Logger.With(zap.String("handler", "notificationEndpoint")), b)
 	notificationEndpointBackend.NotificationEndpointService = authorizer.NewNotificationEndpointService(b.NotificationEndpointService,
 		b.UserResourceMappingService, b.OrganizationService)
@@ -168,6 +204,14 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	orgBackend.SecretService = authorizer.NewSecretService(b.SecretService)
 	h.Mount(prefixOrganizations, NewOrgHandler(b.Logger, orgBackend))
 
+	roleBackend := NewRoleBackend(b.Logger.With(zap.String("handler", "role")), b)
+	roleBackend.RoleService = authorizer.NewRoleService(b.RoleService)
+	h.Mount(prefixRoles, NewRoleHandler(b.Logger, roleBackend))
+
+	rateLimitBackend := NewRateLimitBackend(b.Logger.With(zap.String("handler", "ratelimit")), b)
+	rateLimitBackend.RateLimitService = authorizer.NewRateLimitService(rateLimiter)
+	h.Mount(prefixRateLimits, NewRateLimitHandler(b.Logger, rateLimitBackend))
+
 	scraperBackend := NewScraperBackend(b.Logger.With(zap.String("handler", "scraper")), b)
 	scraperBackend.ScraperStorageService = authorizer.NewScraperTargetStoreService(b.ScraperTargetStoreService,
 		b.UserResourceMappingService,
@@ -175,6 +219,7 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.Mount(prefixTargets, NewScraperHandler(b.Logger, scraperBackend))
 
 	sessionBackend := newSessionBackend(b.Logger.With(zap.String("handler", "session")), b)
+	sessionBackend.UserResourceMappingService = noAuthUserResourceMappingService
 	sessionHandler := NewSessionHandler(b.Logger, sessionBackend)
 	h.Mount(prefixSignIn, sessionHandler)
 	h.Mount(prefixSignOut, sessionHandler)
@@ -204,6 +249,10 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.Mount(prefixMe, userHandler)
 	h.Mount(prefixUsers, userHandler)
 
+	usageHandler := NewUsageHandler(b.Logger.With(zap.String("handler", "usage")), b.HTTPErrorHandler)
+	usageHandler.UsageService = authorizer.NewUsageService(rateLimiter)
+	h.Mount(prefixUsage, usageHandler)
+
 	variableBackend := NewVariableBackend(b.Logger.With(zap.String("handler", "variable")), b)
 	variableBackend.VariableService = authorizer.NewVariableService(b.VariableService)
 	h.Mount(prefixVariables, NewVariableHandler(b.Logger, variableBackend))
@@ -213,12 +262,12 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	h.Mount(prefixBackup, NewBackupHandler(backupBackend))
 
 	writeBackend := NewWriteBackend(b.Logger.With(zap.String("handler", "write")), b)
-	h.Mount(prefixWrite, NewWriteHandler(b.Logger, writeBackend,
+	h.Mount(prefixWrite, rateLimiter.LimitWrites(NewWriteHandler(b.Logger, writeBackend,
 		WithMaxBatchSizeBytes(b.MaxBatchSizeBytes),
 		WithParserMaxBytes(b.WriteParserMaxBytes),
 		WithParserMaxLines(b.WriteParserMaxLines),
 		WithParserMaxValues(b.WriteParserMaxValues),
-	))
+	)))
 
 	for _, o := range opts {
 		o(h)
@@ -229,10 +278,12 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
-	"authorizations": "/api/v2/authorizations",
-	"backup":         "/api/v2/backup",
-	"buckets":        "/api/v2/buckets",
-	"dashboards":     "/api/v2/dashboards",
+	"audit":               "/api/v2/audit",
+	"authorizations":      "/api/v2/authorizations",
+	"backup":              "/api/v2/backup",
+	"buckets":             "/api/v2/buckets",
+	"certificatemappings": "/api/v2/certificatemappings",
+	"dashboards":          "/api/v2/dashboards",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
@@ -248,12 +299,14 @@ var apiLinks = map[string]interface{}{
 		"analyze":     "/api/v2/query/analyze",
 		"suggestions": "/api/v2/query/suggestions",
 	},
-	"setup":    "/api/v2/setup",
-	"signin":   "/api/v2/signin",
-	"signout":  "/api/v2/signout",
-	"sources":  "/api/v2/sources",
-	"scrapers": "/api/v2/scrapers",
-	"swagger":  "/api/v2/swagger.json",
+	"ratelimits": "/api/v2/ratelimits",
+	"roles":      "/api/v2/roles",
+	"setup":      "/api/v2/setup",
+	"signin":     "/api/v2/signin",
+	"signout":    "/api/v2/signout",
+	"sources":    "/api/v2/sources",
+	"scrapers":   "/api/v2/scrapers",
+	"swagger":    "/api/v2/swagger.json",
 	"system": map[string]string{
 		"metrics": "/metrics",
 		"debug":   "/debug/pprof",
@@ -263,6 +316,7 @@ var apiLinks = map[string]interface{}{
 	"checks":    "/api/v2/checks",
 	"telegrafs": "/api/v2/telegrafs",
 	"plugins":   "/api/v2/telegraf/plugins",
+	"usage":     "/api/v2/usage",
 	"users":     "/api/v2/users",
 	"write":     "/api/v2/write",
 	"delete":    "/api/v2/delete",
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"go.uber.org/zap"
+)
+
+// AuditBackend is all services and associated parameters required to construct
+// the AuditHandler.
+type AuditBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	AuditLogService influxdb.AuditLogService
+}
+
+// NewAuditBackend creates a backend used by the audit handler.
+func NewAuditBackend(log *zap.Logger, b *APIBackend) *AuditBackend {
+	return &AuditBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		AuditLogService:  b.AuditLogService,
+	}
+}
+
+// AuditHandler represents an HTTP API handler for the audit log.
+type AuditHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	AuditLogService influxdb.AuditLogService
+}
+
+const (
+	prefixAudit     = "/api/v2/audit"
+	auditExportPath = "/api/v2/audit/export"
+)
+
+// NewAuditHandler returns a new instance of AuditHandler.
+func NewAuditHandler(log *zap.Logger, b *AuditBackend) *AuditHandler {
+	h := &AuditHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		AuditLogService:  b.AuditLogService,
+	}
+
+	h.HandlerFunc("GET", prefixAudit, h.handleGetAuditEvents)
+	h.HandlerFunc("GET", auditExportPath, h.handleExportAuditEvents)
+	return h
+}
+
+type auditEventsResponse struct {
+	Links  *influxdb.PagingLinks  `json:"links"`
+	Events []*influxdb.AuditEvent `json:"events"`
+}
+
+// handleGetAuditEvents is the HTTP handler for the GET /api/v2/audit route.
+func (h *AuditHandler) handleGetAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, err := decodeAuditEventFilter(r.URL.Query())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	opts, err := decodeFindOptions(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	events, _, err := h.AuditLogService.FindAuditEvents(ctx, filter, *opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Audit events retrieved", zap.Int("count", len(events)))
+
+	res := auditEventsResponse{
+		Links:  newPagingLinks(prefixAudit, *opts, filter, len(events)),
+		Events: events,
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleExportAuditEvents is the HTTP handler for the GET /api/v2/audit/export route.
+// It writes all of the matching events as JSON lines.
+func (h *AuditHandler) handleExportAuditEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qp := r.URL.Query()
+	filter, err := decodeAuditEventFilter(qp)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var opts influxdb.FindOptions
+	if limit := qp.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "limit is invalid",
+			}, w)
+			return
+		}
+		opts.Limit = l
+	}
+
+	events, _, err := h.AuditLogService.FindAuditEvents(ctx, filter, opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Audit events exported", zap.Int("count", len(events)))
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			logEncodingError(h.log, r, err)
+			return
+		}
+	}
+}
+
+func decodeAuditEventFilter(qp url.Values) (influxdb.AuditEventFilter, error) {
+	var filter influxdb.AuditEventFilter
+
+	ids := []struct {
+		param string
+		dst   **influxdb.ID
+	}{
+		{param: "orgID", dst: &filter.OrgID},
+		{param: "userID", dst: &filter.UserID},
+		{param: "resourceID", dst: &filter.ResourceID},
+	}
+	for _, p := range ids {
+		v := qp.Get(p.param)
+		if v == "" {
+			continue
+		}
+		id, err := influxdb.IDFromString(v)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  p.param + " is invalid",
+				Err:  err,
+			}
+		}
+		*p.dst = id
+	}
+
+	if rt := qp.Get("resourceType"); rt != "" {
+		t := influxdb.ResourceType(rt)
+		if err := t.Valid(); err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "resourceType is invalid",
+				Err:  err,
+			}
+		}
+		filter.ResourceType = &t
+	}
+
+	if action := qp.Get("action"); action != "" {
+		a := influxdb.AuditAction(action)
+		switch a {
+		case influxdb.AuditActionCreate, influxdb.AuditActionUpdate, influxdb.AuditActionDelete:
+		default:
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "action is invalid",
+			}
+		}
+		filter.Action = &a
+	}
+
+	times := []struct {
+		param string
+		dst   **time.Time
+	}{
+		{param: "since", dst: &filter.Since},
+		{param: "until", dst: &filter.Until},
+	}
+	for _, p := range times {
+		v := qp.Get(p.param)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  p.param + " must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+		*p.dst = &t
+	}
+
+	return filter, nil
+}
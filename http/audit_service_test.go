@@ -0,0 +1,118 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	"github.com/influxdata/influxdb/v2/inmem"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestAuditHandler(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	require.NoError(t, svc.Initialize(ctx))
+
+	org := &platform.Organization{Name: "org"}
+	require.NoError(t, svc.CreateOrganization(ctx, org))
+	user := &platform.User{Name: "user", Status: platform.Active}
+	require.NoError(t, svc.CreateUser(ctx, user))
+	auth := &platform.Authorization{
+		OrgID:  org.ID,
+		UserID: user.ID,
+		Permissions: []platform.Permission{
+			{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: &org.ID}},
+			{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.AuditResourceType}},
+		},
+	}
+	require.NoError(t, svc.CreateAuthorization(ctx, auth))
+
+	auditHandler := NewAuditHandler(zaptest.NewLogger(t), &AuditBackend{
+		HTTPErrorHandler: kithttp.ErrorHandler(0),
+		log:              zaptest.NewLogger(t),
+		AuditLogService:  authorizer.NewAuditLogService(svc),
+	})
+	bucketSVC := authorizer.NewBucketService(svc, svc)
+
+	h := NewAuthenticationHandler(zaptest.NewLogger(t), kithttp.ErrorHandler(0))
+	h.AuthorizationService = svc
+	h.UserService = svc
+	h.AuditRecorder = svc
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			b := &platform.Bucket{OrgID: org.ID, Name: r.URL.Query().Get("name")}
+			if err := bucketSVC.CreateBucket(r.Context(), b); err != nil {
+				t.Errorf("failed to create bucket: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		auditHandler.ServeHTTP(w, r)
+	})
+
+	do := func(t *testing.T, method, target string) *http.Response {
+		t.Helper()
+		r := httptest.NewRequest(method, target, nil)
+		r.RemoteAddr = "10.0.0.1:5000"
+		r.Header.Set("Authorization", "Token "+auth.Token)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	require.Equal(t, http.StatusCreated, do(t, "POST", "http://any.url/buckets?name=b1").StatusCode)
+	require.Equal(t, http.StatusCreated, do(t, "POST", "http://any.url/buckets?name=b2").StatusCode)
+
+	t.Run("lists the events", func(t *testing.T) {
+		res := do(t, "GET", "http://any.url/api/v2/audit?resourceType=buckets&descending=true")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var body struct {
+			Events []*platform.AuditEvent `json:"events"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.Len(t, body.Events, 2)
+
+		e := body.Events[0]
+		assert.Equal(t, platform.AuditActionCreate, e.Action)
+		assert.Equal(t, platform.BucketsResourceType, e.ResourceType)
+		assert.Equal(t, org.ID, e.OrgID)
+		assert.Equal(t, user.ID, e.UserID)
+		assert.Equal(t, auth.ID, e.ActorID)
+		assert.Equal(t, "10.0.0.1", e.SourceIP)
+		assert.Contains(t, string(e.After), `"name":"b2"`)
+	})
+
+	t.Run("exports the events as JSON lines", func(t *testing.T) {
+		res := do(t, "GET", "http://any.url/api/v2/audit/export?orgID="+org.ID.String())
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+
+		var names []string
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			var e platform.AuditEvent
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+			var b platform.Bucket
+			require.NoError(t, json.Unmarshal(e.After, &b))
+			names = append(names, b.Name)
+		}
+		require.NoError(t, scanner.Err())
+		assert.Equal(t, []string{"b1", "b2"}, names)
+	})
+
+	t.Run("rejects invalid filters", func(t *testing.T) {
+		res := do(t, "GET", "http://any.url/api/v2/audit?since=yesterday")
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
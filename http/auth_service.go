@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -71,13 +72,15 @@ func NewAuthorizationHandler(log *zap.Logger, b *AuthorizationBackend) *Authoriz
 	h.HandlerFunc("GET", "/api/v2/authorizations", h.handleGetAuthorizations)
 	h.HandlerFunc("GET", "/api/v2/authorizations/:id", h.handleGetAuthorization)
 	h.HandlerFunc("PATCH", "/api/v2/authorizations/:id", h.handleUpdateAuthorization)
+	h.HandlerFunc("POST", "/api/v2/authorizations/:id/rotate", h.handleRotateAuthorization)
 	h.HandlerFunc("DELETE", "/api/v2/authorizations/:id", h.handleDeleteAuthorization)
 	return h
 }
 
 type authResponse struct {
 	ID          platform.ID          `json:"id"`
-	Token       string               `json:"token"`
+	Token       string               `json:"token,omitempty"`
+	TokenPrefix string               `json:"tokenPrefix,omitempty"`
 	Status      platform.Status      `json:"status"`
 	Description string               `json:"description"`
 	OrgID       platform.ID          `json:"orgID"`
@@ -85,15 +88,20 @@ type authResponse struct {
 	UserID      platform.ID          `json:"userID"`
 	User        string               `json:"user"`
 	Permissions []permissionResponse `json:"permissions"`
+	Roles       []platform.ID        `json:"roles,omitempty"`
 	Links       map[string]string    `json:"links"`
 	CreatedAt   time.Time            `json:"createdAt"`
 	UpdatedAt   time.Time            `json:"updatedAt"`
+	ExpiresAt   *time.Time           `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time           `json:"lastUsedAt,omitempty"`
+	LastUsedIP  string               `json:"lastUsedIP,omitempty"`
 }
 
 func newAuthResponse(a *platform.Authorization, org *platform.Organization, user *platform.User, ps []permissionResponse) *authResponse {
 	res := &authResponse{
 		ID:          a.ID,
 		Token:       a.Token,
+		TokenPrefix: a.TokenPrefix,
 		Status:      a.Status,
 		Description: a.Description,
 		OrgID:       a.OrgID,
@@ -101,12 +109,16 @@ func newAuthResponse(a *platform.Authorization, org *platform.Organization, user
 		User:        user.Name,
 		Org:         org.Name,
 		Permissions: ps,
+		Roles:       a.Roles,
 		Links: map[string]string{
 			"self": fmt.Sprintf("/api/v2/authorizations/%s", a.ID),
 			"user": fmt.Sprintf("/api/v2/users/%s", a.UserID),
 		},
-		CreatedAt: a.CreatedAt,
-		UpdatedAt: a.UpdatedAt,
+		CreatedAt:  a.CreatedAt,
+		UpdatedAt:  a.UpdatedAt,
+		ExpiresAt:  a.ExpiresAt,
+		LastUsedAt: a.LastUsedAt,
+		LastUsedIP: a.LastUsedIP,
 	}
 	return res
 }
@@ -115,10 +127,15 @@ func (a *authResponse) toPlatform() *platform.Authorization {
 	res := &platform.Authorization{
 		ID:          a.ID,
 		Token:       a.Token,
+		TokenPrefix: a.TokenPrefix,
 		Status:      a.Status,
 		Description: a.Description,
 		OrgID:       a.OrgID,
 		UserID:      a.UserID,
+		Roles:       a.Roles,
+		ExpiresAt:   a.ExpiresAt,
+		LastUsedAt:  a.LastUsedAt,
+		LastUsedIP:  a.LastUsedIP,
 		CRUDLog: platform.CRUDLog{
 			CreatedAt: a.CreatedAt,
 			UpdatedAt: a.UpdatedAt,
@@ -245,6 +262,8 @@ type postAuthorizationRequest struct {
 	UserID      *platform.ID          `json:"userID,omitempty"`
 	Description string                `json:"description"`
 	Permissions []platform.Permission `json:"permissions"`
+	Roles       []platform.ID         `json:"roles,omitempty"`
+	ExpiresAt   *time.Time            `json:"expiresAt,omitempty"`
 }
 
 func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Authorization {
@@ -253,7 +272,9 @@ func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Auth
 		Status:      p.Status,
 		Description: p.Description,
 		Permissions: p.Permissions,
+		Roles:       p.Roles,
 		UserID:      userID,
+		ExpiresAt:   p.ExpiresAt,
 	}
 }
 
@@ -262,7 +283,9 @@ func newPostAuthorizationRequest(a *platform.Authorization) (*postAuthorizationR
 		OrgID:       a.OrgID,
 		Description: a.Description,
 		Permissions: a.Permissions,
+		Roles:       a.Roles,
 		Status:      a.Status,
+		ExpiresAt:   a.ExpiresAt,
 	}
 
 	if a.UserID.Valid() {
@@ -281,10 +304,10 @@ func (p *postAuthorizationRequest) SetDefaults() {
 }
 
 func (p *postAuthorizationRequest) Validate() error {
-	if len(p.Permissions) == 0 {
+	if len(p.Permissions) == 0 && len(p.Roles) == 0 {
 		return &platform.Error{
 			Code: platform.EInvalid,
-			Msg:  "authorization must include permissions",
+			Msg:  "authorization must include permissions or roles",
 		}
 	}
 
@@ -308,6 +331,13 @@ func (p *postAuthorizationRequest) Validate() error {
 		p.Status = platform.Active
 	}
 
+	if p.ExpiresAt != nil && !p.ExpiresAt.After(time.Now()) {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "expiresAt must be in the future",
+		}
+	}
+
 	err := p.Status.Valid()
 	if err != nil {
 		return err
@@ -573,6 +603,98 @@ func decodeUpdateAuthorizationRequest(ctx context.Context, r *http.Request) (*up
 	}, nil
 }
 
+// handleRotateAuthorization is the HTTP handler for the POST /api/v2/authorizations/:id/rotate route
+// that issues a new token for the authorization.
+func (h *AuthorizationHandler) handleRotateAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeRotateAuthorizationRequest(ctx, r)
+	if err != nil {
+		h.log.Info("Failed to decode request", zap.String("handler", "rotateAuthorization"), zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	a, err := h.AuthorizationService.RotateAuthorization(ctx, req.ID, req.GracePeriod)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	o, err := h.OrganizationService.FindOrganizationByID(ctx, a.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	u, err := h.UserService.FindUserByID(ctx, a.UserID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ps, err := newPermissionsResponse(ctx, a.Permissions, h.LookupService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Auth rotated", zap.String("authID", a.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newAuthResponse(a, o, u, ps)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+type rotateAuthorizationRequest struct {
+	ID          platform.ID
+	GracePeriod time.Duration
+}
+
+type rotateAuthorizationBody struct {
+	// GracePeriod is the duration the replaced token is still accepted for, i.e. "1h".
+	GracePeriod string `json:"gracePeriod,omitempty"`
+}
+
+func decodeRotateAuthorizationRequest(ctx context.Context, r *http.Request) (*rotateAuthorizationRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i platform.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	req := &rotateAuthorizationRequest{ID: i}
+
+	// the body is optional, a rotation without one has no grace period.
+	var body rotateAuthorizationBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+	if body.GracePeriod != "" {
+		d, err := time.ParseDuration(body.GracePeriod)
+		if err != nil || d < 0 {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("invalid grace period %q", body.GracePeriod),
+			}
+		}
+		req.GracePeriod = d
+	}
+
+	return req, nil
+}
+
 // handleDeleteAuthorization is the HTTP handler for the DELETE /api/v2/authorizations/:id route.
 func (h *AuthorizationHandler) handleDeleteAuthorization(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -719,6 +841,20 @@ func (s *AuthorizationService) UpdateAuthorization(ctx context.Context, id platf
 	return res.toPlatform(), nil
 }
 
+// RotateAuthorization issues a new token for the authorization, the replaced token is accepted for gracePeriod.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, error) {
+	var res authResponse
+	err := s.Client.
+		PostJSON(rotateAuthorizationBody{GracePeriod: gracePeriod.String()}, prefixAuthorization, id.String(), "rotate").
+		DecodeJSON(&res).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.toPlatform(), nil
+}
+
 // DeleteAuthorization removes a authorization by id.
 func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platform.ID) error {
 	return s.Client.
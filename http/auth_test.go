@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb/v2"
@@ -855,6 +856,95 @@ func TestService_handleDeleteAuthorization(t *testing.T) {
 	}
 }
 
+func TestService_handleRotateAuthorization(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		gracePeriod time.Duration
+		statusCode  int
+	}{
+		{
+			name:       "rotate without body",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "rotate with grace period",
+			body:        `{"gracePeriod": "1h"}`,
+			gracePeriod: time.Hour,
+			statusCode:  http.StatusOK,
+		},
+		{
+			name:       "invalid grace period",
+			body:       `{"gracePeriod": "soon"}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorizationBackend := NewMockAuthorizationBackend(t)
+			authorizationBackend.HTTPErrorHandler = kithttp.ErrorHandler(0)
+			authorizationBackend.AuthorizationService = &mock.AuthorizationService{
+				RotateAuthorizationFn: func(ctx context.Context, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, error) {
+					if gracePeriod != tt.gracePeriod {
+						t.Errorf("expected grace period %s, got %s", tt.gracePeriod, gracePeriod)
+					}
+					return &platform.Authorization{
+						ID:     id,
+						Token:  "new-token",
+						Status: platform.Active,
+						OrgID:  platformtesting.MustIDBase16("020f755c3c083000"),
+						UserID: platformtesting.MustIDBase16("020f755c3c082000"),
+					}, nil
+				},
+			}
+			authorizationBackend.UserService = &mock.UserService{
+				FindUserByIDFn: func(ctx context.Context, id platform.ID) (*platform.User, error) {
+					return &platform.User{ID: id, Name: "u1"}, nil
+				},
+			}
+			authorizationBackend.OrganizationService = &mock.OrganizationService{
+				FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+					return &platform.Organization{ID: id, Name: "o1"}, nil
+				},
+			}
+			h := NewAuthorizationHandler(zaptest.NewLogger(t), authorizationBackend)
+
+			r := httptest.NewRequest("POST", "http://any.url", bytes.NewBufferString(tt.body))
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{
+						Key:   "id",
+						Value: "020f755c3c082000",
+					},
+				}))
+
+			w := httptest.NewRecorder()
+
+			h.handleRotateAuthorization(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.statusCode {
+				t.Fatalf("%q. handleRotateAuthorization() = %v, want %v: %s", tt.name, res.StatusCode, tt.statusCode, body)
+			}
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			var auth authResponse
+			if err := json.Unmarshal(body, &auth); err != nil {
+				t.Fatal(err)
+			}
+			if auth.Token != "new-token" {
+				t.Errorf("%q. handleRotateAuthorization() token = %q, want %q", tt.name, auth.Token, "new-token")
+			}
+		})
+	}
+}
+
 func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.T) (platform.AuthorizationService, string, func()) {
 	t.Helper()
 	if t.Name() == "TestAuthorizationService_FindAuthorizations/find_authorization_by_token" {
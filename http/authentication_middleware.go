@@ -2,8 +2,10 @@ package http
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -26,6 +28,20 @@ type AuthenticationHandler struct {
 	TokenParser          *jsonweb.TokenParser
 	SessionRenewDisabled bool
 
+	// AuthorizationUsageRecorder, if set, records the last use of the tokens requests are authenticated with.
+	AuthorizationUsageRecorder platform.AuthorizationUsageRecorder
+
+	// CertificateMappingService, if set, authenticates requests made with a verified
+	// client certificate as the authorization the certificate is mapped to.
+	CertificateMappingService platform.CertificateMappingService
+
+	// DashboardShareService, if set, authenticates requests made with the token of
+	// a dashboard share link.
+	DashboardShareService platform.DashboardShareService
+
+	// AuditRecorder, if set, records the changes made by authenticated requests to the audit log.
+	AuditRecorder platform.AuditRecorder
+
 	// This is only really used for it's lookup method the specific http
 	// handler used to register routes does not matter.
 	noAuthRouter *httprouter.Router
@@ -51,24 +67,44 @@ func (h *AuthenticationHandler) RegisterNoAuthRoute(method, path string) {
 }
 
 const (
-	tokenAuthScheme   = "token"
-	sessionAuthScheme = "session"
+	tokenAuthScheme       = "token"
+	sessionAuthScheme     = "session"
+	certificateAuthScheme = "certificate"
+	shareAuthScheme       = "share"
 )
 
-// ProbeAuthScheme probes the http request for the requests for token or cookie session.
+// ProbeAuthScheme probes the http request for the requests for token, dashboard share link
+// or cookie session, and then for a client certificate verified by the TLS server.
 func ProbeAuthScheme(r *http.Request) (string, error) {
 	_, tokenErr := GetToken(r)
 	_, sessErr := decodeCookieSession(r.Context(), r)
 
-	if tokenErr != nil && sessErr != nil {
-		return "", fmt.Errorf("token required")
-	}
-
 	if tokenErr == nil {
 		return tokenAuthScheme, nil
 	}
 
-	return sessionAuthScheme, nil
+	if _, err := GetShareToken(r); err == nil {
+		return shareAuthScheme, nil
+	}
+
+	if sessErr == nil {
+		return sessionAuthScheme, nil
+	}
+
+	if clientCertificate(r) != nil {
+		return certificateAuthScheme, nil
+	}
+
+	return "", fmt.Errorf("token required")
+}
+
+// clientCertificate returns the leaf certificate of the client of the request
+// if it was verified against the client CAs of the server, or nil otherwise.
+func clientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
 }
 
 func (h *AuthenticationHandler) unauthorized(ctx context.Context, w http.ResponseWriter, err error) {
@@ -96,6 +132,10 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		auth, err = h.extractAuthorization(ctx, r)
 	case sessionAuthScheme:
 		auth, err = h.extractSession(ctx, r)
+	case certificateAuthScheme:
+		auth, err = h.extractCertificate(ctx, r)
+	case shareAuthScheme:
+		auth, err = h.extractDashboardShare(ctx, r)
 	default:
 		// TODO: this error will be nil if it gets here, this should be remedied with some
 		//  sentinel error I'm thinking
@@ -106,7 +146,7 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// jwt based auth is permission based rather than identity based
+	// jwt and share link based auth is permission based rather than identity based
 	// and therefor has no associated user. if the user ID is invalid
 	// disregard the user active check
 	if auth.GetUserID().Valid() {
@@ -117,6 +157,9 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	}
 
 	ctx = platcontext.SetAuthorizer(ctx, auth)
+	if h.AuditRecorder != nil {
+		ctx = platcontext.SetAuditRecorder(ctx, &auditLogger{rec: h.AuditRecorder, log: h.log}, remoteIP(r))
+	}
 
 	if span := opentracing.SpanFromContext(ctx); span != nil {
 		span.SetTag("user_id", auth.GetUserID().String())
@@ -157,7 +200,148 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 		return nil, err
 	}
 
-	return h.AuthorizationService.FindAuthorizationByToken(ctx, t)
+	a, err := h.AuthorizationService.FindAuthorizationByToken(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if a.IsExpired(now) {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  "token expired",
+		}
+	}
+
+	h.recordAuthorizationUse(ctx, a, r, now)
+
+	return a, nil
+}
+
+// authorizationUseResolution is how often the last use of a token is recorded,
+// unless it is used from another address.
+const authorizationUseResolution = time.Minute
+
+func (h *AuthenticationHandler) recordAuthorizationUse(ctx context.Context, a *platform.Authorization, r *http.Request, now time.Time) {
+	if h.AuthorizationUsageRecorder == nil {
+		return
+	}
+
+	ip := remoteIP(r)
+	if a.LastUsedAt != nil && a.LastUsedIP == ip && now.Sub(*a.LastUsedAt) < authorizationUseResolution {
+		return
+	}
+
+	if err := h.AuthorizationUsageRecorder.RecordAuthorizationUse(ctx, a.ID, now, ip); err != nil {
+		h.log.Info("Failed to record authorization use", zap.String("authID", a.ID.String()), zap.Error(err))
+	}
+}
+
+// remoteIP returns the address of the client of the request without its port.
+func remoteIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// auditLogger logs the audit events its recorder fails to record, as the
+// changes they audit have already been made by the request.
+type auditLogger struct {
+	rec platform.AuditRecorder
+	log *zap.Logger
+}
+
+func (l *auditLogger) RecordAuditEvent(ctx context.Context, e *platform.AuditEvent) error {
+	err := l.rec.RecordAuditEvent(ctx, e)
+	if err != nil {
+		l.log.Error("Failed to record audit event",
+			zap.String("action", string(e.Action)),
+			zap.String("resource_type", string(e.ResourceType)),
+			zap.Stringer("resource_id", e.ResourceID),
+			zap.Error(err),
+		)
+	}
+	return err
+}
+
+// extractCertificate finds the authorization the client certificate of the request
+// is mapped to. The subject alternative names of the certificate are matched
+// before its subject.
+func (h *AuthenticationHandler) extractCertificate(ctx context.Context, r *http.Request) (*platform.Authorization, error) {
+	if h.CertificateMappingService == nil {
+		return nil, errors.New("client certificate authentication is not enabled")
+	}
+	cert := clientCertificate(r)
+
+	m, err := h.findCertificateMapping(ctx, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := h.AuthorizationService.FindAuthorizationByID(ctx, m.AuthorizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if a.IsExpired(now) {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  "token expired",
+		}
+	}
+
+	h.recordAuthorizationUse(ctx, a, r, now)
+
+	return a, nil
+}
+
+func (h *AuthenticationHandler) findCertificateMapping(ctx context.Context, cert *x509.Certificate) (*platform.CertificateMapping, error) {
+	var filters []platform.CertificateMappingFilter
+	for _, san := range platform.CertificateSANs(cert) {
+		san := san
+		filters = append(filters, platform.CertificateMappingFilter{SAN: &san})
+	}
+	subject := platform.CertificateSubject(cert)
+	filters = append(filters, platform.CertificateMappingFilter{Subject: &subject})
+
+	for _, f := range filters {
+		ms, _, err := h.CertificateMappingService.FindCertificateMappings(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		if len(ms) > 0 {
+			return ms[0], nil
+		}
+	}
+	return nil, fmt.Errorf("no certificate mapping for subject %q", subject)
+}
+
+// extractDashboardShare finds the unexpired dashboard share link of the token of the request.
+func (h *AuthenticationHandler) extractDashboardShare(ctx context.Context, r *http.Request) (*platform.DashboardShare, error) {
+	if h.DashboardShareService == nil {
+		return nil, errors.New("dashboard share links are not enabled")
+	}
+
+	t, err := GetShareToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := h.DashboardShareService.FindDashboardShareByToken(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.IsExpired(time.Now()) {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  "share link expired",
+		}
+	}
+
+	return s, nil
 }
 
 func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Request) (*platform.Session, error) {
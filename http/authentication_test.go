@@ -2,18 +2,25 @@ package http_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
 	influxdb "github.com/influxdata/influxdb/v2"
 	platform "github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
 	platformhttp "github.com/influxdata/influxdb/v2/http"
+	"github.com/influxdata/influxdb/v2/inmem"
 	"github.com/influxdata/influxdb/v2/jsonweb"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/kv"
 	"github.com/influxdata/influxdb/v2/mock"
 	"go.uber.org/zap/zaptest"
 )
@@ -114,6 +121,24 @@ func TestAuthenticationHandler(t *testing.T) {
 				code: http.StatusUnauthorized,
 			},
 		},
+		{
+			name: "token expired",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{
+					FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+						expiredAt := time.Now().Add(-time.Minute)
+						return &platform.Authorization{ExpiresAt: &expiredAt}, nil
+					},
+				},
+				SessionService: mock.NewSessionService(),
+			},
+			args: args{
+				token: "abc123",
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
 		{
 			name: "associated user is inactive",
 			fields: fields{
@@ -249,10 +274,72 @@ func TestAuthenticationHandler(t *testing.T) {
 	}
 }
 
+type authorizationUseFunc func(ctx context.Context, id platform.ID, at time.Time, ip string) error
+
+func (f authorizationUseFunc) RecordAuthorizationUse(ctx context.Context, id platform.ID, at time.Time, ip string) error {
+	return f(ctx, id, at, ip)
+}
+
+func TestAuthenticationHandler_RecordsAuthorizationUse(t *testing.T) {
+	auth := &platform.Authorization{ID: one, Status: platform.Active}
+
+	var recorded int
+	h := platformhttp.NewAuthenticationHandler(zaptest.NewLogger(t), kithttp.ErrorHandler(0))
+	h.AuthorizationService = &mock.AuthorizationService{
+		FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+			a := *auth
+			return &a, nil
+		},
+	}
+	h.AuthorizationUsageRecorder = authorizationUseFunc(func(ctx context.Context, id platform.ID, at time.Time, ip string) error {
+		recorded++
+		if id != auth.ID {
+			t.Errorf("expected use of authorization %s to be recorded, got %s", auth.ID, id)
+		}
+		auth.LastUsedAt = &at
+		auth.LastUsedIP = ip
+		return nil
+	})
+	h.SessionService = mock.NewSessionService()
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serve := func(remoteAddr string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "http://any.url", nil)
+		r.RemoteAddr = remoteAddr
+		platformhttp.SetToken("abc123", r)
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status code to be %d got %d", http.StatusOK, w.Code)
+		}
+	}
+
+	serve("10.0.0.1:1234")
+	if recorded != 1 || auth.LastUsedIP != "10.0.0.1" {
+		t.Fatalf("expected use from 10.0.0.1 to be recorded, got %d uses from %q", recorded, auth.LastUsedIP)
+	}
+
+	// repeated use from the same address is only recorded once per minute.
+	serve("10.0.0.1:4321")
+	if recorded != 1 {
+		t.Fatalf("expected repeated use to not be recorded, got %d uses", recorded)
+	}
+
+	serve("10.0.0.2:1234")
+	if recorded != 2 || auth.LastUsedIP != "10.0.0.2" {
+		t.Fatalf("expected use from 10.0.0.2 to be recorded, got %d uses from %q", recorded, auth.LastUsedIP)
+	}
+}
+
 func TestProbeAuthScheme(t *testing.T) {
 	type args struct {
 		token   string
+		share   string
 		session string
+		cert    bool
 	}
 	type wants struct {
 		scheme string
@@ -282,6 +369,34 @@ func TestProbeAuthScheme(t *testing.T) {
 				scheme: "token",
 			},
 		},
+		{
+			name: "share link token provided",
+			args: args{
+				share: "abc123",
+			},
+			wants: wants{
+				scheme: "share",
+			},
+		},
+		{
+			name: "verified client certificate provided",
+			args: args{
+				cert: true,
+			},
+			wants: wants{
+				scheme: "certificate",
+			},
+		},
+		{
+			name: "token takes precedence over client certificate",
+			args: args{
+				token: "abc123",
+				cert:  true,
+			},
+			wants: wants{
+				scheme: "token",
+			},
+		},
 		{
 			name: "no auth provided",
 			args: args{},
@@ -303,6 +418,16 @@ func TestProbeAuthScheme(t *testing.T) {
 				platformhttp.SetToken(tt.args.token, r)
 			}
 
+			if tt.args.share != "" {
+				r.Header.Set("Authorization", "Share "+tt.args.share)
+			}
+
+			if tt.args.cert {
+				r.TLS = &tls.ConnectionState{
+					VerifiedChains: [][]*x509.Certificate{{{}}},
+				}
+			}
+
 			scheme, err := platformhttp.ProbeAuthScheme(r)
 			if (err != nil) != (tt.wants.err != nil) {
 				t.Errorf("unexpected error got %v want %v", err, tt.wants.err)
@@ -316,6 +441,96 @@ func TestProbeAuthScheme(t *testing.T) {
 	}
 }
 
+func TestAuthenticationHandler_ClientCertificate(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	org := &platform.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &platform.User{Name: "user", Status: platform.Active}
+	if err := svc.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	auth := &platform.Authorization{OrgID: org.ID, UserID: user.ID}
+	if err := svc.CreateAuthorization(ctx, auth); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateCertificateMapping(ctx, &platform.CertificateMapping{
+		SAN:             "spiffe://cluster.local/ns/default/sa/telegraf",
+		AuthorizationID: auth.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateCertificateMapping(ctx, &platform.CertificateMapping{
+		Subject:         "CN=chronograf,O=Acme",
+		AuthorizationID: auth.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	h := platformhttp.NewAuthenticationHandler(zaptest.NewLogger(t), kithttp.ErrorHandler(0))
+	h.AuthorizationService = svc
+	h.CertificateMappingService = svc
+	h.UserService = svc
+	h.SessionService = svc
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a, err := icontext.GetAuthorizer(r.Context())
+		if err != nil || a.Identifier() != auth.ID {
+			t.Errorf("expected request to be authorized as %s, got %v, %v", auth.ID, a, err)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	spiffe, _ := url.Parse("spiffe://cluster.local/ns/default/sa/telegraf")
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		code int
+	}{
+		{
+			name: "mapped uri san",
+			cert: &x509.Certificate{
+				Subject: pkix.Name{CommonName: "telegraf"},
+				URIs:    []*url.URL{spiffe},
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "mapped subject",
+			cert: &x509.Certificate{
+				Subject: pkix.Name{CommonName: "chronograf", Organization: []string{"Acme"}},
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "unmapped certificate",
+			cert: &x509.Certificate{
+				Subject:  pkix.Name{CommonName: "kapacitor"},
+				DNSNames: []string{"kapacitor.default.svc"},
+			},
+			code: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "https://any.url", nil)
+			r.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{tt.cert}},
+			}
+			h.ServeHTTP(w, r)
+			if w.Code != tt.code {
+				t.Errorf("expected status code to be %d got %d", tt.code, w.Code)
+			}
+		})
+	}
+}
+
 func TestAuthenticationHandler_NoAuthRoutes(t *testing.T) {
 	type route struct {
 		method string
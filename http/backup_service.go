@@ -7,10 +7,12 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/httprouter"
@@ -62,7 +64,14 @@ const (
 	prefixBackup        = "/api/v2/backup"
 	backupIDParamName   = "backup_id"
 	backupFileParamName = "backup_file"
-	backupFilePath      = prefixBackup + "/:" + backupIDParamName + "/file/:" + backupFileParamName
+
+	// The backup file is a catch-all parameter, as the files of the buckets
+	// stored on their own are in directories.
+	backupFilePath = prefixBackup + "/:" + backupIDParamName + "/file/*" + backupFileParamName
+
+	// The bucketID query parameter restricts a backup to a bucket stored in a
+	// shard of its own.
+	backupBucketIDParamName = "bucketID"
 
 	httpClientTimeout = time.Hour
 )
@@ -92,12 +101,53 @@ type backup struct {
 	Files []string `json:"files,omitempty"`
 }
 
+// decodeBackupBucketID returns the ID of the bucket to back up on its own, if
+// any.
+func decodeBackupBucketID(r *http.Request) (*influxdb.ID, error) {
+	s := r.URL.Query().Get(backupBucketIDParamName)
+	if s == "" {
+		return nil, nil
+	}
+	id, err := influxdb.IDFromString(s)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid bucket id",
+			Err:  err,
+		}
+	}
+	return id, nil
+}
+
+// decodeBackupFile returns the relative path of a backup file from the
+// catch-all parameter, rejecting the paths that leave the backup.
+func decodeBackupFile(s string) (string, error) {
+	file := strings.TrimPrefix(s, "/")
+	if file == "" || path.IsAbs(file) || path.Clean(file) != file || file == ".." || strings.HasPrefix(file, "../") {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid backup file %q", s),
+		}
+	}
+	return file, nil
+}
+
 func (h *BackupHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 	span, r := tracing.ExtractFromHTTPRequest(r, "BackupHandler.handleCreate")
 	defer span.Finish()
 
 	ctx := r.Context()
 
+	bucketID, err := decodeBackupBucketID(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if bucketID != nil {
+		h.handleCreateBucket(w, r, *bucketID)
+		return
+	}
+
 	id, files, err := h.BackupService.CreateBackup(ctx)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
@@ -144,6 +194,27 @@ func (h *BackupHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleCreateBucket backs up the TSM data of a bucket stored on its own. The
+// meta data is not part of the backup of a bucket.
+func (h *BackupHandler) handleCreateBucket(w http.ResponseWriter, r *http.Request, bucketID influxdb.ID) {
+	ctx := r.Context()
+
+	id, files, err := h.BackupService.CreateBucketBackup(ctx, bucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b := backup{
+		ID:    id,
+		Files: files,
+	}
+	if err = json.NewEncoder(w).Encode(&b); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+}
+
 func (h *BackupHandler) backupCredentials(internalBackupPath string) (bool, error) {
 	credBackupPath := filepath.Join(internalBackupPath, DefaultConfigsFile)
 
@@ -171,14 +242,28 @@ func (h *BackupHandler) handleFetchFile(w http.ResponseWriter, r *http.Request)
 	ctx := r.Context()
 
 	params := httprouter.ParamsFromContext(ctx)
-	backupID, err := strconv.Atoi(params.ByName("backup_id"))
+	backupID, err := strconv.Atoi(params.ByName(backupIDParamName))
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	backupFile, err := decodeBackupFile(params.ByName(backupFileParamName))
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
-	backupFile := params.ByName("backup_file")
 
-	if err = h.BackupService.FetchBackupFile(ctx, backupID, backupFile, w); err != nil {
+	bucketID, err := decodeBackupBucketID(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if bucketID != nil {
+		err = h.BackupService.FetchBucketBackupFile(ctx, *bucketID, backupID, backupFile, w)
+	} else {
+		err = h.BackupService.FetchBackupFile(ctx, backupID, backupFile, w)
+	}
+	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
@@ -195,10 +280,22 @@ func (s *BackupService) CreateBackup(ctx context.Context) (int, []string, error)
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	return s.createBackup(ctx, nil)
+}
+
+func (s *BackupService) CreateBucketBackup(ctx context.Context, bucketID influxdb.ID) (int, []string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.createBackup(ctx, &bucketID)
+}
+
+func (s *BackupService) createBackup(ctx context.Context, bucketID *influxdb.ID) (int, []string, error) {
 	u, err := NewURL(s.Addr, prefixBackup)
 	if err != nil {
 		return 0, nil, err
 	}
+	setBackupBucketID(u, bucketID)
 
 	req, err := http.NewRequest(http.MethodPost, u.String(), nil)
 	if err != nil {
@@ -231,10 +328,22 @@ func (s *BackupService) FetchBackupFile(ctx context.Context, backupID int, backu
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	return s.fetchBackupFile(ctx, nil, backupID, backupFile, w)
+}
+
+func (s *BackupService) FetchBucketBackupFile(ctx context.Context, bucketID influxdb.ID, backupID int, backupFile string, w io.Writer) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.fetchBackupFile(ctx, &bucketID, backupID, backupFile, w)
+}
+
+func (s *BackupService) fetchBackupFile(ctx context.Context, bucketID *influxdb.ID, backupID int, backupFile string, w io.Writer) error {
 	u, err := NewURL(s.Addr, composeBackupFilePath(backupID, backupFile))
 	if err != nil {
 		return err
 	}
+	setBackupBucketID(u, bucketID)
 
 	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
 	if err != nil {
@@ -263,6 +372,16 @@ func (s *BackupService) FetchBackupFile(ctx context.Context, backupID int, backu
 	return nil
 }
 
+// setBackupBucketID restricts the backup request of u to the bucket, if any.
+func setBackupBucketID(u *url.URL, bucketID *influxdb.ID) {
+	if bucketID == nil {
+		return
+	}
+	params := u.Query()
+	params.Set(backupBucketIDParamName, bucketID.String())
+	u.RawQuery = params.Encode()
+}
+
 func defaultConfigsPath() (string, error) {
 	dir, err := fs.InfluxDir()
 	if err != nil {
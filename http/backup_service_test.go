@@ -0,0 +1,148 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// bucketBackupService backs up the buckets it stores on their own.
+type bucketBackupService struct {
+	influxdb.BackupService
+	bucketID influxdb.ID
+}
+
+func (s *bucketBackupService) CreateBucketBackup(ctx context.Context, bucketID influxdb.ID) (int, []string, error) {
+	if bucketID != s.bucketID {
+		return 0, nil, &influxdb.Error{Code: influxdb.EInvalid, Msg: "bucket is not stored in a shard of its own"}
+	}
+	return 7, []string{"000000001-000000001.tsm"}, nil
+}
+
+func (s *bucketBackupService) FetchBucketBackupFile(ctx context.Context, bucketID influxdb.ID, backupID int, backupFile string, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s/%d/%s", bucketID, backupID, backupFile)
+	return err
+}
+
+func TestBackupService_BucketBackup(t *testing.T) {
+	bucketID := influxdb.ID(1)
+	h := NewBackupHandler(&BackupBackend{
+		Logger:           zaptest.NewLogger(t),
+		HTTPErrorHandler: kithttp.ErrorHandler(0),
+		BackupService:    &bucketBackupService{bucketID: bucketID},
+	})
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	client := &BackupService{Addr: server.URL}
+	ctx := context.Background()
+
+	id, files, err := client.CreateBucketBackup(ctx, bucketID)
+	require.NoError(t, err)
+	assert.Equal(t, 7, id)
+	assert.Equal(t, []string{"000000001-000000001.tsm"}, files)
+
+	var buf bytes.Buffer
+	require.NoError(t, client.FetchBucketBackupFile(ctx, bucketID, id, files[0], &buf))
+	assert.Equal(t, "0000000000000001/7/000000001-000000001.tsm", buf.String())
+
+	_, _, err = client.CreateBucketBackup(ctx, influxdb.ID(2))
+	assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+}
+
+// kvBackupService writes a stand-in of the meta data backup.
+type kvBackupService struct{}
+
+func (kvBackupService) Backup(ctx context.Context, w io.Writer) error {
+	_, err := io.WriteString(w, "bolt")
+	return err
+}
+
+func TestBackupService_BucketShards(t *testing.T) {
+	path, err := ioutil.TempDir("", "backup_service_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	config := storage.NewConfig()
+	config.BucketShards = true
+	engine := storage.NewEngine(path, config, storage.WithEngineID(rand.Int()), storage.WithNodeID(rand.Int()))
+	require.NoError(t, engine.Open(context.Background()))
+	defer engine.Close()
+
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(2)
+	point := models.MustNewPoint(
+		tsdb.EncodeNameString(orgID, bucketID),
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+	require.NoError(t, engine.WritePoints(context.Background(), []models.Point{point}))
+
+	h := NewBackupHandler(&BackupBackend{
+		Logger:           zaptest.NewLogger(t),
+		HTTPErrorHandler: kithttp.ErrorHandler(0),
+		BackupService:    engine,
+		KVBackupService:  kvBackupService{},
+	})
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	client := &BackupService{Addr: server.URL}
+	ctx := context.Background()
+
+	id, files, err := client.CreateBackup(ctx)
+	require.NoError(t, err)
+
+	// The files of the bucket shard are in its directory.
+	var nested int
+	for _, file := range files {
+		if strings.Contains(file, "/") {
+			nested++
+		}
+		var buf bytes.Buffer
+		require.NoError(t, client.FetchBackupFile(ctx, id, file, &buf), file)
+		assert.NotZero(t, buf.Len(), file)
+	}
+	assert.NotZero(t, nested)
+}
+
+func TestDecodeBackupFile(t *testing.T) {
+	for _, tc := range []struct {
+		param string
+		exp   string
+		valid bool
+	}{
+		{param: "/000000001-000000001.tsm", exp: "000000001-000000001.tsm", valid: true},
+		{param: "/buckets/0000000000000002/000000001-000000001.tsm", exp: "buckets/0000000000000002/000000001-000000001.tsm", valid: true},
+		{param: "/"},
+		{param: "/.."},
+		{param: "/../engine"},
+		{param: "/buckets/../../engine"},
+		{param: "//etc/passwd"},
+	} {
+		file, err := decodeBackupFile(tc.param)
+		if !tc.valid {
+			assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err), tc.param)
+			continue
+		}
+		require.NoError(t, err, tc.param)
+		assert.Equal(t, tc.exp, file)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,6 +25,7 @@ type BucketBackend struct {
 
 	BucketService              influxdb.BucketService
 	BucketOperationLogService  influxdb.BucketOperationLogService
+	CardinalityService         influxdb.CardinalityService
 	UserResourceMappingService influxdb.UserResourceMappingService
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
@@ -38,6 +40,7 @@ func NewBucketBackend(log *zap.Logger, b *APIBackend) *BucketBackend {
 
 		BucketService:              b.BucketService,
 		BucketOperationLogService:  b.BucketOperationLogService,
+		CardinalityService:         b.CardinalityService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
@@ -53,6 +56,7 @@ type BucketHandler struct {
 
 	BucketService              influxdb.BucketService
 	BucketOperationLogService  influxdb.BucketOperationLogService
+	CardinalityService         influxdb.CardinalityService
 	UserResourceMappingService influxdb.UserResourceMappingService
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
@@ -60,15 +64,16 @@ type BucketHandler struct {
 }
 
 const (
-	prefixBuckets          = "/api/v2/buckets"
-	bucketsIDPath          = "/api/v2/buckets/:id"
-	bucketsIDLogPath       = "/api/v2/buckets/:id/logs"
-	bucketsIDMembersPath   = "/api/v2/buckets/:id/members"
-	bucketsIDMembersIDPath = "/api/v2/buckets/:id/members/:userID"
-	bucketsIDOwnersPath    = "/api/v2/buckets/:id/owners"
-	bucketsIDOwnersIDPath  = "/api/v2/buckets/:id/owners/:userID"
-	bucketsIDLabelsPath    = "/api/v2/buckets/:id/labels"
-	bucketsIDLabelsIDPath  = "/api/v2/buckets/:id/labels/:lid"
+	prefixBuckets            = "/api/v2/buckets"
+	bucketsIDPath            = "/api/v2/buckets/:id"
+	bucketsIDLogPath         = "/api/v2/buckets/:id/logs"
+	bucketsIDCardinalityPath = "/api/v2/buckets/:id/cardinality"
+	bucketsIDMembersPath     = "/api/v2/buckets/:id/members"
+	bucketsIDMembersIDPath   = "/api/v2/buckets/:id/members/:userID"
+	bucketsIDOwnersPath      = "/api/v2/buckets/:id/owners"
+	bucketsIDOwnersIDPath    = "/api/v2/buckets/:id/owners/:userID"
+	bucketsIDLabelsPath      = "/api/v2/buckets/:id/labels"
+	bucketsIDLabelsIDPath    = "/api/v2/buckets/:id/labels/:lid"
 )
 
 // NewBucketHandler returns a new instance of BucketHandler.
@@ -80,6 +85,7 @@ func NewBucketHandler(log *zap.Logger, b *BucketBackend) *BucketHandler {
 
 		BucketService:              b.BucketService,
 		BucketOperationLogService:  b.BucketOperationLogService,
+		CardinalityService:         b.CardinalityService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
@@ -90,6 +96,7 @@ func NewBucketHandler(log *zap.Logger, b *BucketBackend) *BucketHandler {
 	h.HandlerFunc("GET", prefixBuckets, h.handleGetBuckets)
 	h.HandlerFunc("GET", bucketsIDPath, h.handleGetBucket)
 	h.HandlerFunc("GET", bucketsIDLogPath, h.handleGetBucketLog)
+	h.HandlerFunc("GET", bucketsIDCardinalityPath, h.handleGetBucketCardinality)
 	h.HandlerFunc("PATCH", bucketsIDPath, h.handlePatchBucket)
 	h.HandlerFunc("DELETE", bucketsIDPath, h.handleDeleteBucket)
 
@@ -139,6 +146,9 @@ type bucket struct {
 	Name                string          `json:"name"`
 	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
 	RetentionRules      []retentionRule `json:"retentionRules"`
+	StringCompression   string          `json:"stringCompression,omitempty"`
+	IndexedFields       []string        `json:"indexedFields,omitempty"`
+	WALDurability       string          `json:"walDurability,omitempty"`
 	influxdb.CRUDLog
 }
 
@@ -186,6 +196,9 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		StringCompression:   b.StringCompression,
+		IndexedFields:       b.IndexedFields,
+		WALDurability:       b.WALDurability,
 		CRUDLog:             b.CRUDLog,
 	}, nil
 }
@@ -212,15 +225,21 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		Description:         pb.Description,
 		RetentionPolicyName: pb.RetentionPolicyName,
 		RetentionRules:      rules,
+		StringCompression:   pb.StringCompression,
+		IndexedFields:       pb.IndexedFields,
+		WALDurability:       pb.WALDurability,
 		CRUDLog:             pb.CRUDLog,
 	}
 }
 
 // bucketUpdate is used for serialization/deserialization with retention rules.
 type bucketUpdate struct {
-	Name           *string         `json:"name,omitempty"`
-	Description    *string         `json:"description,omitempty"`
-	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+	Name              *string         `json:"name,omitempty"`
+	Description       *string         `json:"description,omitempty"`
+	RetentionRules    []retentionRule `json:"retentionRules,omitempty"`
+	StringCompression *string         `json:"stringCompression,omitempty"`
+	IndexedFields     *[]string       `json:"indexedFields,omitempty"`
+	WALDurability     *string         `json:"walDurability,omitempty"`
 }
 
 func (b *bucketUpdate) OK() error {
@@ -230,6 +249,21 @@ func (b *bucketUpdate) OK() error {
 			return err
 		}
 	}
+	if b.StringCompression != nil {
+		if err := influxdb.ValidBucketStringCompression(*b.StringCompression); err != nil {
+			return err
+		}
+	}
+	if b.IndexedFields != nil {
+		if err := influxdb.ValidBucketIndexedFields(*b.IndexedFields); err != nil {
+			return err
+		}
+	}
+	if b.WALDurability != nil {
+		if err := influxdb.ValidBucketWALDurability(*b.WALDurability); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -245,9 +279,12 @@ func (b *bucketUpdate) toInfluxDB() *influxdb.BucketUpdate {
 	}
 
 	return &influxdb.BucketUpdate{
-		Name:            b.Name,
-		Description:     b.Description,
-		RetentionPeriod: &d,
+		Name:              b.Name,
+		Description:       b.Description,
+		RetentionPeriod:   &d,
+		StringCompression: b.StringCompression,
+		IndexedFields:     b.IndexedFields,
+		WALDurability:     b.WALDurability,
 	}
 }
 
@@ -257,9 +294,12 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 	}
 
 	up := &bucketUpdate{
-		Name:           pb.Name,
-		Description:    pb.Description,
-		RetentionRules: []retentionRule{},
+		Name:              pb.Name,
+		Description:       pb.Description,
+		RetentionRules:    []retentionRule{},
+		StringCompression: pb.StringCompression,
+		IndexedFields:     pb.IndexedFields,
+		WALDurability:     pb.WALDurability,
 	}
 
 	if pb.RetentionPeriod != nil {
@@ -341,6 +381,9 @@ type postBucketRequest struct {
 	Description         string          `json:"description"`
 	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
 	RetentionRules      []retentionRule `json:"retentionRules"`
+	StringCompression   string          `json:"stringCompression,omitempty"`
+	IndexedFields       []string        `json:"indexedFields,omitempty"`
+	WALDurability       string          `json:"walDurability,omitempty"`
 }
 
 func (b *postBucketRequest) OK() error {
@@ -369,6 +412,27 @@ func (b *postBucketRequest) OK() error {
 		}
 	}
 
+	if err := influxdb.ValidBucketStringCompression(b.StringCompression); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EUnprocessableEntity,
+			Msg:  err.Error(),
+		}
+	}
+
+	if err := influxdb.ValidBucketIndexedFields(b.IndexedFields); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EUnprocessableEntity,
+			Msg:  err.Error(),
+		}
+	}
+
+	if err := influxdb.ValidBucketWALDurability(b.WALDurability); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EUnprocessableEntity,
+			Msg:  err.Error(),
+		}
+	}
+
 	return nil
 }
 
@@ -386,6 +450,9 @@ func (b postBucketRequest) toInfluxDB() *influxdb.Bucket {
 		Type:                influxdb.BucketTypeUser,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     dur,
+		StringCompression:   b.StringCompression,
+		IndexedFields:       b.IndexedFields,
+		WALDurability:       b.WALDurability,
 	}
 }
 
@@ -458,6 +525,61 @@ func newBucketLogResponse(id influxdb.ID, es []*influxdb.OperationLogEntry) *ope
 	}
 }
 
+// handleGetBucketCardinality is the HTTP handler for the GET /api/v2/buckets/:id/cardinality route.
+func (h *BucketHandler) handleGetBucketCardinality(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDFromCtx(ctx, "id")
+	if err != nil {
+		h.api.Err(w, err)
+		return
+	}
+
+	opts, err := decodeFindOptions(r)
+	if err != nil {
+		h.api.Err(w, err)
+		return
+	}
+
+	if h.CardinalityService == nil {
+		h.api.Err(w, &influxdb.Error{
+			Code: influxdb.EMethodNotAllowed,
+			Msg:  "bucket cardinality is not available",
+		})
+		return
+	}
+
+	// Looking up the bucket checks that it may be read.
+	b, err := h.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		h.api.Err(w, err)
+		return
+	}
+
+	c, err := h.CardinalityService.FindBucketCardinality(ctx, b.OrgID, b.ID, influxdb.CardinalityOptions{Limit: opts.Limit})
+	if err != nil {
+		h.api.Err(w, err)
+		return
+	}
+
+	h.api.Respond(w, http.StatusOK, newBucketCardinalityResponse(c))
+}
+
+type bucketCardinalityResponse struct {
+	Links map[string]string `json:"links"`
+	*influxdb.BucketCardinality
+}
+
+func newBucketCardinalityResponse(c *influxdb.BucketCardinality) *bucketCardinalityResponse {
+	return &bucketCardinalityResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/buckets/%s/cardinality", c.BucketID),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", c.BucketID),
+		},
+		BucketCardinality: c,
+	}
+}
+
 // handleDeleteBucket is the HTTP handler for the DELETE /api/v2/buckets/:id route.
 func (h *BucketHandler) handleDeleteBucket(w http.ResponseWriter, r *http.Request) {
 	id, err := decodeIDFromCtx(r.Context(), "id")
@@ -668,6 +790,28 @@ func (s *BucketService) FindBucketByID(ctx context.Context, id influxdb.ID) (*in
 	return br.toInfluxDB()
 }
 
+// FindBucketCardinality returns the series cardinality of the bucket.
+func (s *BucketService) FindBucketCardinality(ctx context.Context, id influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var params [][2]string
+	if opts.Limit > 0 {
+		params = append(params, [2]string{"limit", strconv.Itoa(opts.Limit)})
+	}
+
+	var c influxdb.BucketCardinality
+	err := s.Client.
+		Get(bucketIDPath(id), "cardinality").
+		QueryParams(params...).
+		DecodeJSON(&c).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
 // FindBucket returns the first bucket that matches filter.
 func (s *BucketService) FindBucket(ctx context.Context, filter influxdb.BucketFilter) (*influxdb.Bucket, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
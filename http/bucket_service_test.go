@@ -367,6 +367,77 @@ func TestService_handleGetBucket(t *testing.T) {
 	}
 }
 
+func TestService_handleGetBucketCardinality(t *testing.T) {
+	bucketID := platformtesting.MustIDBase16("020f755c3c082000")
+	orgID := platformtesting.MustIDBase16("020f755c3c082001")
+
+	bucketBackend := NewMockBucketBackend(t)
+	bucketBackend.HTTPErrorHandler = kithttp.ErrorHandler(0)
+	bucketBackend.BucketService = &mock.BucketService{
+		FindBucketByIDFn: func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+			if id != bucketID {
+				return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+			}
+			return &platform.Bucket{ID: bucketID, OrgID: orgID}, nil
+		},
+	}
+	bucketBackend.CardinalityService = &mock.CardinalityService{
+		FindBucketCardinalityF: func(ctx context.Context, oid, bid platform.ID, opts platform.CardinalityOptions) (*platform.BucketCardinality, error) {
+			if oid != orgID || bid != bucketID || opts.Limit != 5 {
+				return nil, fmt.Errorf("unexpected look up of %s/%s with %+v", oid, bid, opts)
+			}
+			return &platform.BucketCardinality{
+				BucketID:     bid,
+				Series:       3,
+				Measurements: []platform.MeasurementCardinality{{Name: "cpu", Series: 2}, {Name: "mem", Series: 1}},
+				TagKeys:      []platform.TagKeyCardinality{{Key: "host", Values: 10000, Truncated: true}},
+				Growth:       []platform.CardinalitySample{{Time: time.Unix(0, 0).UTC(), Series: 3}},
+			}, nil
+		},
+	}
+	h := NewBucketHandler(zaptest.NewLogger(t), bucketBackend)
+
+	t.Run("found", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://any.url/api/v2/buckets/020f755c3c082000/cardinality?limit=5", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		res := w.Result()
+		body, _ := ioutil.ReadAll(res.Body)
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status: got %d, exp %d: %s", res.StatusCode, http.StatusOK, body)
+		}
+		exp := `
+		{
+		  "links": {
+		    "self": "/api/v2/buckets/020f755c3c082000/cardinality",
+		    "bucket": "/api/v2/buckets/020f755c3c082000"
+		  },
+		  "bucketID": "020f755c3c082000",
+		  "series": 3,
+		  "measurements": [{"name": "cpu", "series": 2}, {"name": "mem", "series": 1}],
+		  "tagKeys": [{"key": "host", "values": 10000, "truncated": true}],
+		  "growth": [{"time": "1970-01-01T00:00:00Z", "series": 3}]
+		}
+		`
+		if eq, diff, err := jsonEqual(string(body), exp); err != nil {
+			t.Fatalf("error unmarshaling json %v", err)
+		} else if !eq {
+			t.Fatalf("handleGetBucketCardinality() = ***%s***", diff)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://any.url/api/v2/buckets/020f755c3c082002/cardinality", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if got, exp := w.Result().StatusCode, http.StatusNotFound; got != exp {
+			t.Fatalf("unexpected status: got %d, exp %d", got, exp)
+		}
+	})
+}
+
 func TestService_handlePostBucket(t *testing.T) {
 	type fields struct {
 		BucketService       platform.BucketService
@@ -459,6 +530,133 @@ func TestService_handlePostBucket(t *testing.T) {
 				statusCode: http.StatusUnprocessableEntity,
 			},
 		},
+		{
+			name: "create a new bucket compressing strings with zstd",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					CreateBucketFn: func(ctx context.Context, c *platform.Bucket) error {
+						c.ID = platformtesting.MustIDBase16("020f755c3c082000")
+						return nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: platformtesting.MustIDBase16("6f626f7274697320")}, nil
+					},
+				},
+			},
+			args: args{
+				bucket: &platform.Bucket{
+					Name:              "logs",
+					OrgID:             platformtesting.MustIDBase16("6f626f7274697320"),
+					StringCompression: platform.BucketStringCompressionZstd,
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusCreated,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "org": "/api/v2/orgs/6f626f7274697320",
+    "self": "/api/v2/buckets/020f755c3c082000",
+    "logs": "/api/v2/buckets/020f755c3c082000/logs",
+    "labels": "/api/v2/buckets/020f755c3c082000/labels",
+    "members": "/api/v2/buckets/020f755c3c082000/members",
+    "owners": "/api/v2/buckets/020f755c3c082000/owners",
+    "write": "/api/v2/write?org=6f626f7274697320&bucket=020f755c3c082000"
+  },
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z",
+  "id": "020f755c3c082000",
+  "orgID": "6f626f7274697320",
+  "type": "user",
+  "name": "logs",
+  "retentionRules": [],
+  "stringCompression": "zstd",
+  "labels": []
+}
+`,
+			},
+		},
+		{
+			name: "create a new bucket with invalid string compression",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					CreateBucketFn: func(ctx context.Context, c *platform.Bucket) error {
+						c.ID = platformtesting.MustIDBase16("020f755c3c082000")
+						return nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: platformtesting.MustIDBase16("6f626f7274697320")}, nil
+					},
+				},
+			},
+			args: args{
+				bucket: &platform.Bucket{
+					Name:              "logs",
+					OrgID:             platformtesting.MustIDBase16("6f626f7274697320"),
+					StringCompression: "gzip",
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusUnprocessableEntity,
+			},
+		},
+		{
+			name: "create a new bucket with duplicate indexed fields",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					CreateBucketFn: func(ctx context.Context, c *platform.Bucket) error {
+						c.ID = platformtesting.MustIDBase16("020f755c3c082000")
+						return nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: platformtesting.MustIDBase16("6f626f7274697320")}, nil
+					},
+				},
+			},
+			args: args{
+				bucket: &platform.Bucket{
+					Name:          "logs",
+					OrgID:         platformtesting.MustIDBase16("6f626f7274697320"),
+					IndexedFields: []string{"status", "status"},
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusUnprocessableEntity,
+			},
+		},
+		{
+			name: "create a new bucket with an invalid WAL durability",
+			fields: fields{
+				BucketService: &mock.BucketService{
+					CreateBucketFn: func(ctx context.Context, c *platform.Bucket) error {
+						c.ID = platformtesting.MustIDBase16("020f755c3c082000")
+						return nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{ID: platformtesting.MustIDBase16("6f626f7274697320")}, nil
+					},
+				},
+			},
+			args: args{
+				bucket: &platform.Bucket{
+					Name:          "logs",
+					OrgID:         platformtesting.MustIDBase16("6f626f7274697320"),
+					WALDurability: "fsync",
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusUnprocessableEntity,
+			},
+		},
 	}
 
 	for _, tt := range tests {
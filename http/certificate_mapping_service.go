@@ -0,0 +1,227 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"go.uber.org/zap"
+)
+
+// CertificateMappingBackend is all services and associated parameters required to construct
+// the CertificateMappingHandler.
+type CertificateMappingBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	CertificateMappingService influxdb.CertificateMappingService
+}
+
+// NewCertificateMappingBackend creates a backend used by the certificate mapping handler.
+func NewCertificateMappingBackend(log *zap.Logger, b *APIBackend) *CertificateMappingBackend {
+	return &CertificateMappingBackend{
+		HTTPErrorHandler:          b.HTTPErrorHandler,
+		log:                       log,
+		CertificateMappingService: b.CertificateMappingService,
+	}
+}
+
+// CertificateMappingHandler represents an HTTP API handler for the mappings of
+// client certificates to authorizations.
+type CertificateMappingHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	CertificateMappingService influxdb.CertificateMappingService
+}
+
+const (
+	prefixCertificateMappings = "/api/v2/certificatemappings"
+	certificateMappingsIDPath = "/api/v2/certificatemappings/:id"
+)
+
+// NewCertificateMappingHandler returns a new instance of CertificateMappingHandler.
+func NewCertificateMappingHandler(log *zap.Logger, b *CertificateMappingBackend) *CertificateMappingHandler {
+	h := &CertificateMappingHandler{
+		Router:                    NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler:          b.HTTPErrorHandler,
+		log:                       log,
+		CertificateMappingService: b.CertificateMappingService,
+	}
+
+	h.HandlerFunc("POST", prefixCertificateMappings, h.handlePostCertificateMapping)
+	h.HandlerFunc("GET", prefixCertificateMappings, h.handleGetCertificateMappings)
+	h.HandlerFunc("GET", certificateMappingsIDPath, h.handleGetCertificateMapping)
+	h.HandlerFunc("DELETE", certificateMappingsIDPath, h.handleDeleteCertificateMapping)
+	return h
+}
+
+type certificateMappingResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.CertificateMapping
+}
+
+func newCertificateMappingResponse(m *influxdb.CertificateMapping) *certificateMappingResponse {
+	return &certificateMappingResponse{
+		Links: map[string]string{
+			"self":          fmt.Sprintf("/api/v2/certificatemappings/%s", m.ID),
+			"authorization": fmt.Sprintf("/api/v2/authorizations/%s", m.AuthorizationID),
+			"org":           fmt.Sprintf("/api/v2/orgs/%s", m.OrgID),
+		},
+		CertificateMapping: *m,
+	}
+}
+
+type certificateMappingsResponse struct {
+	Links               map[string]string             `json:"links"`
+	CertificateMappings []*certificateMappingResponse `json:"certificateMappings"`
+}
+
+func newCertificateMappingsResponse(ms []*influxdb.CertificateMapping) *certificateMappingsResponse {
+	res := &certificateMappingsResponse{
+		Links: map[string]string{
+			"self": prefixCertificateMappings,
+		},
+		CertificateMappings: make([]*certificateMappingResponse, 0, len(ms)),
+	}
+	for _, m := range ms {
+		res.CertificateMappings = append(res.CertificateMappings, newCertificateMappingResponse(m))
+	}
+	return res
+}
+
+// handlePostCertificateMapping is the HTTP handler for the POST /api/v2/certificatemappings route.
+func (h *CertificateMappingHandler) handlePostCertificateMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	m := &influxdb.CertificateMapping{}
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode certificate mapping request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.CertificateMappingService.CreateCertificateMapping(ctx, m); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Certificate mapping created", zap.String("certificateMapping", fmt.Sprint(m)))
+	if err := encodeResponse(ctx, w, http.StatusCreated, newCertificateMappingResponse(m)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetCertificateMappings is the HTTP handler for the GET /api/v2/certificatemappings route.
+func (h *CertificateMappingHandler) handleGetCertificateMappings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, err := decodeCertificateMappingFilter(r.URL.Query())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	opts, err := decodeFindOptions(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ms, _, err := h.CertificateMappingService.FindCertificateMappings(ctx, filter, *opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Certificate mappings retrieved", zap.String("certificateMappings", fmt.Sprint(ms)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newCertificateMappingsResponse(ms)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodeCertificateMappingFilter(qp url.Values) (influxdb.CertificateMappingFilter, error) {
+	var filter influxdb.CertificateMappingFilter
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = id
+	}
+	if authID := qp.Get("authorizationID"); authID != "" {
+		id, err := influxdb.IDFromString(authID)
+		if err != nil {
+			return filter, err
+		}
+		filter.AuthorizationID = id
+	}
+	if subject := qp.Get("subject"); subject != "" {
+		filter.Subject = &subject
+	}
+	if san := qp.Get("san"); san != "" {
+		filter.SAN = &san
+	}
+	return filter, nil
+}
+
+// handleGetCertificateMapping is the HTTP handler for the GET /api/v2/certificatemappings/:id route.
+func (h *CertificateMappingHandler) handleGetCertificateMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeCertificateMappingID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	m, err := h.CertificateMappingService.FindCertificateMappingByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Certificate mapping retrieved", zap.String("certificateMapping", fmt.Sprint(m)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newCertificateMappingResponse(m)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteCertificateMapping is the HTTP handler for the DELETE /api/v2/certificatemappings/:id route.
+func (h *CertificateMappingHandler) handleDeleteCertificateMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeCertificateMappingID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.CertificateMappingService.DeleteCertificateMapping(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Certificate mapping deleted", zap.String("certificateMappingID", id.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeCertificateMappingID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
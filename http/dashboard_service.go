@@ -9,6 +9,7 @@ import (
 
 	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
 	"github.com/influxdata/influxdb/v2/pkg/httpc"
 	"go.uber.org/zap"
 )
@@ -24,6 +25,7 @@ type DashboardBackend struct {
 	UserResourceMappingService   influxdb.UserResourceMappingService
 	LabelService                 influxdb.LabelService
 	UserService                  influxdb.UserService
+	DashboardShareService        influxdb.DashboardShareService
 }
 
 // NewDashboardBackend creates a backend used by the dashboard handler.
@@ -37,6 +39,7 @@ func NewDashboardBackend(log *zap.Logger, b *APIBackend) *DashboardBackend {
 		UserResourceMappingService:   b.UserResourceMappingService,
 		LabelService:                 b.LabelService,
 		UserService:                  b.UserService,
+		DashboardShareService:        b.DashboardShareService,
 	}
 }
 
@@ -52,6 +55,11 @@ type DashboardHandler struct {
 	UserResourceMappingService   influxdb.UserResourceMappingService
 	LabelService                 influxdb.LabelService
 	UserService                  influxdb.UserService
+	DashboardShareService        influxdb.DashboardShareService
+
+	// This is only used for its lookup method of the routes open to
+	// requests made with a share link.
+	shareRouter *httprouter.Router
 }
 
 const (
@@ -67,6 +75,8 @@ const (
 	dashboardsIDOwnersIDPath    = "/api/v2/dashboards/:id/owners/:userID"
 	dashboardsIDLabelsPath      = "/api/v2/dashboards/:id/labels"
 	dashboardsIDLabelsIDPath    = "/api/v2/dashboards/:id/labels/:lid"
+	dashboardsIDSharesPath      = "/api/v2/dashboards/:id/shares"
+	dashboardsIDSharesIDPath    = "/api/v2/dashboards/:id/shares/:shareID"
 )
 
 // NewDashboardHandler returns a new instance of DashboardHandler.
@@ -81,6 +91,9 @@ func NewDashboardHandler(log *zap.Logger, b *DashboardBackend) *DashboardHandler
 		UserResourceMappingService:   b.UserResourceMappingService,
 		LabelService:                 b.LabelService,
 		UserService:                  b.UserService,
+		DashboardShareService:        b.DashboardShareService,
+
+		shareRouter: httprouter.New(),
 	}
 
 	h.HandlerFunc("POST", prefixDashboards, h.handlePostDashboard)
@@ -132,9 +145,40 @@ func NewDashboardHandler(log *zap.Logger, b *DashboardBackend) *DashboardHandler
 	h.HandlerFunc("POST", dashboardsIDLabelsPath, newPostLabelHandler(labelBackend))
 	h.HandlerFunc("DELETE", dashboardsIDLabelsIDPath, newDeleteLabelHandler(labelBackend))
 
+	h.HandlerFunc("GET", dashboardsIDSharesPath, h.handleGetDashboardShares)
+	h.HandlerFunc("POST", dashboardsIDSharesPath, h.handlePostDashboardShare)
+	h.HandlerFunc("DELETE", dashboardsIDSharesIDPath, h.handleDeleteDashboardShare)
+
+	// a share link only reads the dashboard and the views of its cells
+	h.registerShareRoute(prefixDashboards)
+	h.registerShareRoute(dashboardsIDPath)
+	h.registerShareRoute(dashboardsIDCellsIDViewPath)
+
 	return h
 }
 
+func (h *DashboardHandler) registerShareRoute(path string) {
+	// the handler specified here does not matter.
+	h.shareRouter.HandlerFunc("GET", path, func(w http.ResponseWriter, r *http.Request) {})
+}
+
+// ServeHTTP forbids the requests made with a share link to the routes not open to them.
+func (h *DashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if a, err := pcontext.GetAuthorizer(ctx); err == nil {
+		if _, ok := a.(*influxdb.DashboardShare); ok {
+			if handler, _, _ := h.shareRouter.Lookup(r.Method, r.URL.Path); handler == nil {
+				h.HandleHTTPError(ctx, &influxdb.Error{
+					Code: influxdb.EForbidden,
+					Msg:  "share links are read-only",
+				}, w)
+				return
+			}
+		}
+	}
+	h.Router.ServeHTTP(w, r)
+}
+
 type dashboardLinks struct {
 	Self         string `json:"self"`
 	Members      string `json:"members"`
@@ -1084,6 +1128,127 @@ func (h *DashboardHandler) handlePatchDashboardCell(w http.ResponseWriter, r *ht
 	}
 }
 
+type dashboardShareResponse struct {
+	influxdb.DashboardShare
+	Links map[string]string `json:"links"`
+}
+
+func newDashboardShareResponse(ds *influxdb.DashboardShare) *dashboardShareResponse {
+	return &dashboardShareResponse{
+		DashboardShare: *ds,
+		Links: map[string]string{
+			"self":      fmt.Sprintf("/api/v2/dashboards/%s/shares/%s", ds.DashboardID, ds.ID),
+			"dashboard": fmt.Sprintf("/api/v2/dashboards/%s", ds.DashboardID),
+		},
+	}
+}
+
+type dashboardSharesResponse struct {
+	Links  map[string]string         `json:"links"`
+	Shares []*dashboardShareResponse `json:"shares"`
+}
+
+// handleGetDashboardShares lists the share links of a dashboard.
+func (h *DashboardHandler) handleGetDashboardShares(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	shares, _, err := h.DashboardShareService.FindDashboardShares(ctx, influxdb.DashboardShareFilter{DashboardID: &req.DashboardID})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := dashboardSharesResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/dashboards/%s/shares", req.DashboardID),
+		},
+		Shares: make([]*dashboardShareResponse, 0, len(shares)),
+	}
+	for _, ds := range shares {
+		res.Shares = append(res.Shares, newDashboardShareResponse(ds))
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePostDashboardShare creates a share link of a dashboard. Its token is only
+// returned in this response.
+func (h *DashboardHandler) handlePostDashboardShare(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var ds influxdb.DashboardShare
+	if err := json.NewDecoder(r.Body).Decode(&ds); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode share link request",
+			Err:  err,
+		}, w)
+		return
+	}
+	ds.DashboardID = req.DashboardID
+
+	if err := h.DashboardShareService.CreateDashboardShare(ctx, &ds); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Dashboard share link created", zap.String("dashboardID", ds.DashboardID.String()), zap.String("shareID", ds.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newDashboardShareResponse(&ds)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteDashboardShare revokes a share link of a dashboard.
+func (h *DashboardHandler) handleDeleteDashboardShare(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var shareID influxdb.ID
+	if err := shareID.DecodeFromString(httprouter.ParamsFromContext(ctx).ByName("shareID")); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ds, err := h.DashboardShareService.FindDashboardShareByID(ctx, shareID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if ds.DashboardID != req.DashboardID {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrDashboardShareNotFound,
+		}, w)
+		return
+	}
+
+	if err := h.DashboardShareService.DeleteDashboardShare(ctx, shareID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Dashboard share link revoked", zap.String("dashboardID", ds.DashboardID.String()), zap.String("shareID", shareID.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // DashboardService is a dashboard service over HTTP to the influxdb server.
 type DashboardService struct {
 	Client *httpc.Client
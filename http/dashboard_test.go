@@ -8,12 +8,15 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	pcontext "github.com/influxdata/influxdb/v2/context"
 	"github.com/influxdata/influxdb/v2/inmem"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
 	"github.com/influxdata/influxdb/v2/kv"
@@ -1929,3 +1932,94 @@ func newInMemKVSVC(t *testing.T) *kv.Service {
 	}
 	return svc
 }
+
+func TestService_handleDashboardShares(t *testing.T) {
+	svc := newInMemKVSVC(t)
+	ctx := context.Background()
+
+	org := &platform.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	d := &platform.Dashboard{Name: "dashboard", OrganizationID: org.ID}
+	if err := svc.CreateDashboard(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+
+	dashboardBackend := NewMockDashboardBackend(t)
+	dashboardBackend.HTTPErrorHandler = kithttp.ErrorHandler(0)
+	dashboardBackend.DashboardService = authorizer.NewDashboardService(svc)
+	dashboardBackend.DashboardShareService = authorizer.NewDashboardShareService(svc, dashboardBackend.DashboardService)
+	h := NewDashboardHandler(zaptest.NewLogger(t), dashboardBackend)
+
+	owner := &platform.Authorization{OrgID: org.ID, Status: platform.Active, Permissions: platform.OwnerPermissions(org.ID)}
+	serve := func(a platform.Authorizer, method, path string, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "http://localhost:9999"+path, strings.NewReader(body))
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), a))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(owner, "POST", fmt.Sprintf("/api/v2/dashboards/%s/shares", d.ID), `{"description": "wall screen"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got %v creating share link, want %v: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var created platform.DashboardShare
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Token == "" {
+		t.Fatal("share link created without a token")
+	}
+
+	share, err := svc.FindDashboardShareByToken(ctx, created.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("share links read the dashboard", func(t *testing.T) {
+		for _, path := range []string{
+			fmt.Sprintf("/api/v2/dashboards/%s", d.ID),
+			"/api/v2/dashboards",
+		} {
+			if w := serve(share, "GET", path, ""); w.Code != http.StatusOK {
+				t.Errorf("GET %s got %v, want %v: %s", path, w.Code, http.StatusOK, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("share links are read-only", func(t *testing.T) {
+		for _, req := range []struct{ method, path string }{
+			{"PATCH", fmt.Sprintf("/api/v2/dashboards/%s", d.ID)},
+			{"GET", fmt.Sprintf("/api/v2/dashboards/%s/logs", d.ID)},
+			{"GET", fmt.Sprintf("/api/v2/dashboards/%s/members", d.ID)},
+			{"GET", fmt.Sprintf("/api/v2/dashboards/%s/shares", d.ID)},
+			{"DELETE", fmt.Sprintf("/api/v2/dashboards/%s/shares/%s", d.ID, share.ID)},
+		} {
+			if w := serve(share, req.method, req.path, `{"name": "renamed"}`); w.Code != http.StatusForbidden {
+				t.Errorf("%s %s got %v, want %v", req.method, req.path, w.Code, http.StatusForbidden)
+			}
+		}
+	})
+
+	t.Run("lists share links without their tokens", func(t *testing.T) {
+		w := serve(owner, "GET", fmt.Sprintf("/api/v2/dashboards/%s/shares", d.ID), "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("got %v, want %v", w.Code, http.StatusOK)
+		}
+		if strings.Contains(w.Body.String(), created.Token) {
+			t.Errorf("share link token listed: %s", w.Body.String())
+		}
+	})
+
+	t.Run("revokes share links", func(t *testing.T) {
+		w := serve(owner, "DELETE", fmt.Sprintf("/api/v2/dashboards/%s/shares/%s", d.ID, share.ID), "")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("got %v, want %v: %s", w.Code, http.StatusNoContent, w.Body.String())
+		}
+		if _, err := svc.FindDashboardShareByToken(ctx, created.Token); platform.ErrorCode(err) != platform.ENotFound {
+			t.Errorf("revoked share link found: %v", err)
+		}
+	})
+}
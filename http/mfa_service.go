@@ -0,0 +1,190 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	platcontext "github.com/influxdata/influxdb/v2/context"
+	"go.uber.org/zap"
+)
+
+// MFABackend is all services and associated parameters required to construct
+// the MFAHandler.
+type MFABackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	MFAService influxdb.MFAService
+}
+
+// NewMFABackend creates a backend used by the mfa handler.
+func NewMFABackend(log *zap.Logger, b *APIBackend) *MFABackend {
+	return &MFABackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		MFAService:       b.MFAService,
+	}
+}
+
+// MFAHandler represents an HTTP API handler for the second factor of the
+// user making the request.
+type MFAHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	MFAService influxdb.MFAService
+}
+
+const (
+	prefixMFA      = "/api/v2/me/mfa"
+	mfaConfirmPath = "/api/v2/me/mfa/confirm"
+)
+
+// NewMFAHandler returns a new instance of MFAHandler.
+func NewMFAHandler(log *zap.Logger, b *MFABackend) *MFAHandler {
+	h := &MFAHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		MFAService:       b.MFAService,
+	}
+
+	h.HandlerFunc("GET", prefixMFA, h.handleGetMFA)
+	h.HandlerFunc("POST", prefixMFA, h.handlePostMFA)
+	h.HandlerFunc("DELETE", prefixMFA, h.handleDeleteMFA)
+	h.HandlerFunc("POST", mfaConfirmPath, h.handlePostMFAConfirm)
+	return h
+}
+
+type mfaResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+// handleGetMFA is the HTTP handler for the GET /api/v2/me/mfa route.
+func (h *MFAHandler) handleGetMFA(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := platcontext.GetUserID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	enabled, err := h.MFAService.MFAEnabled(ctx, userID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, mfaResponse{Enabled: enabled}); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePostMFA is the HTTP handler for the POST /api/v2/me/mfa route.
+// A code of the second factor the user has, if any, is required to replace it.
+func (h *MFAHandler) handlePostMFA(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := platcontext.GetUserID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	code, err := decodeMFACode(r, false)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	e, err := h.MFAService.EnrollMFA(ctx, userID, code)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Second factor enrollment started", zap.String("userID", userID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, e); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePostMFAConfirm is the HTTP handler for the POST /api/v2/me/mfa/confirm route.
+func (h *MFAHandler) handlePostMFAConfirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := platcontext.GetUserID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	code, err := decodeMFACode(r, true)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.MFAService.ConfirmMFA(ctx, userID, code); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Second factor enabled", zap.String("userID", userID.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeleteMFA is the HTTP handler for the DELETE /api/v2/me/mfa route.
+// A code of the second factor is required to remove it.
+func (h *MFAHandler) handleDeleteMFA(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := platcontext.GetUserID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	code, err := decodeMFACode(r, true)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.MFAService.DisableMFA(ctx, userID, code); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Second factor disabled", zap.String("userID", userID.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeMFACode decodes the code of the request body, which may be omitted
+// when the code is not required.
+func decodeMFACode(r *http.Request, required bool) (string, error) {
+	var body struct {
+		Code string `json:"code"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err == io.EOF && !required {
+		return "", nil
+	}
+	if err != nil {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode code request",
+			Err:  err,
+		}
+	}
+	if body.Code == "" && required {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "code is required",
+		}
+	}
+	return body.Code, nil
+}
@@ -131,6 +131,8 @@ var blacklistEndpoints = map[string]isValidMethodFn{
 	prefixSignOut:                    ignoreMethod(),
 	prefixMe:                         ignoreMethod(),
 	mePasswordPath:                   ignoreMethod(),
+	prefixMFA:                        ignoreMethod(),
+	mfaConfirmPath:                   ignoreMethod(),
 	usersPasswordPath:                ignoreMethod(),
 	"/api/v2/packages/apply":         ignoreMethod(),
 	prefixWrite:                      ignoreMethod("POST"),
@@ -20,13 +20,24 @@ func NewPlatformHandler(b *APIBackend, us influxdb.UserService, opts ...APIHandl
 	h := NewAuthenticationHandler(b.Logger, b.HTTPErrorHandler)
 	h.Handler = NewAPIHandler(b, opts...)
 	h.AuthorizationService = b.AuthorizationService
+	h.AuthorizationUsageRecorder = b.AuthorizationUsageRecorder
+	h.CertificateMappingService = b.CertificateMappingService
+	h.DashboardShareService = b.DashboardShareService
+	if b.AuditLogService != nil {
+		h.AuditRecorder = b.AuditLogService
+	}
 	h.SessionService = b.SessionService
 	h.SessionRenewDisabled = b.SessionRenewDisabled
 	h.UserService = us
+	if b.TokenParser != nil {
+		h.TokenParser = b.TokenParser
+	}
 
 	h.RegisterNoAuthRoute("GET", "/api/v2")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signin")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signout")
+	h.RegisterNoAuthRoute("GET", "/api/v2/signin/oidc")
+	h.RegisterNoAuthRoute("GET", "/api/v2/signin/oidc/callback")
 	h.RegisterNoAuthRoute("POST", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/swagger.json")
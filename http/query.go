@@ -446,6 +446,8 @@ func decodeProxyQueryRequest(ctx context.Context, r *http.Request, auth influxdb
 		token = a.EphemeralAuth(req.Org.ID)
 	case *jsonweb.Token:
 		token = a.EphemeralAuth(req.Org.ID)
+	case *influxdb.DashboardShare:
+		token = a.EphemeralAuth()
 	default:
 		return pr, n, influxdb.ErrAuthorizerNotSupported
 	}
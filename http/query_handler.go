@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/NYTimes/gziphandler"
@@ -18,6 +19,7 @@ import (
 	"github.com/influxdata/flux/complete"
 	"github.com/influxdata/flux/csv"
 	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/parser"
 	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb/v2"
@@ -48,6 +50,11 @@ type FluxBackend struct {
 
 	OrganizationService influxdb.OrganizationService
 	ProxyQueryService   query.ProxyQueryService
+
+	// DashboardService and VariableService look up the queries and variables
+	// of the dashboards queried with a share link.
+	DashboardService influxdb.DashboardService
+	VariableService  influxdb.VariableService
 }
 
 // NewFluxBackend returns a new instance of FluxBackend.
@@ -62,6 +69,8 @@ func NewFluxBackend(log *zap.Logger, b *APIBackend) *FluxBackend {
 			DefaultService:  b.FluxService,
 		},
 		OrganizationService: b.OrganizationService,
+		DashboardService:    b.DashboardService,
+		VariableService:     b.VariableService,
 	}
 }
 
@@ -79,6 +88,8 @@ type FluxHandler struct {
 	Now                 func() time.Time
 	OrganizationService influxdb.OrganizationService
 	ProxyQueryService   query.ProxyQueryService
+	DashboardService    influxdb.DashboardService
+	VariableService     influxdb.VariableService
 
 	EventRecorder metric.EventRecorder
 }
@@ -98,6 +109,8 @@ func NewFluxHandler(log *zap.Logger, b *FluxBackend) *FluxHandler {
 
 		ProxyQueryService:   b.ProxyQueryService,
 		OrganizationService: b.OrganizationService,
+		DashboardService:    b.DashboardService,
+		VariableService:     b.VariableService,
 		EventRecorder:       b.QueryEventRecorder,
 	}
 
@@ -165,6 +178,13 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 	orgID = req.Request.OrganizationID
 	requestBytes = n
 
+	if share, ok := a.(*influxdb.DashboardShare); ok {
+		if err := h.checkDashboardShareQuery(ctx, share, req); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
 	// Transform the context into one with the request's authorization.
 	ctx = pcontext.SetAuthorizer(ctx, req.Request.Authorization)
 
@@ -195,6 +215,169 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// checkDashboardShareQuery returns an error unless the query made with a share link
+// is one of the queries of the shared dashboard. Only the time range and the
+// variables of the dashboard may be set, and variables only to one of their choices.
+func (h *FluxHandler) checkDashboardShareQuery(ctx context.Context, share *influxdb.DashboardShare, req *query.ProxyRequest) error {
+	forbidden := func(msg string) error {
+		return &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  msg,
+		}
+	}
+
+	if req.Request.OrganizationID != share.OrgID {
+		return forbidden("share links only query the organization of the dashboard")
+	}
+
+	c, ok := req.Request.Compiler.(lang.FluxCompiler)
+	if !ok {
+		return forbidden("share links only run the flux queries of the dashboard")
+	}
+
+	found, err := h.isDashboardQuery(ctx, share.DashboardID, c.Query)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return forbidden("share links only run the queries of the dashboard")
+	}
+
+	return h.checkDashboardShareExtern(ctx, share.OrgID, c.Extern)
+}
+
+// isDashboardQuery returns true if the query is one of the queries of the views of
+// the cells of the dashboard.
+func (h *FluxHandler) isDashboardQuery(ctx context.Context, dashboardID influxdb.ID, q string) (bool, error) {
+	d, err := h.DashboardService.FindDashboardByID(ctx, dashboardID)
+	if err != nil {
+		return false, err
+	}
+
+	q = strings.TrimSpace(q)
+	for _, c := range d.Cells {
+		v, err := h.DashboardService.GetDashboardCellView(ctx, d.ID, c.ID)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		for _, dq := range influxdb.ViewQueries(v.Properties) {
+			if strings.TrimSpace(dq.Text) == q {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// dashboardTimeRangeVariables are the variables set by a dashboard for its time range.
+var dashboardTimeRangeVariables = map[string]bool{
+	"timeRangeStart": true,
+	"timeRangeStop":  true,
+	"windowPeriod":   true,
+}
+
+// checkDashboardShareExtern returns an error unless the extern of a query made with a
+// share link only sets the v option the dashboard queries read their variables from.
+func (h *FluxHandler) checkDashboardShareExtern(ctx context.Context, orgID influxdb.ID, extern *ast.File) error {
+	invalid := &influxdb.Error{
+		Code: influxdb.EForbidden,
+		Msg:  "share links only set the time range and the variables of the dashboard",
+	}
+	if extern == nil {
+		return nil
+	}
+	if len(extern.Imports) > 0 {
+		return invalid
+	}
+
+	var variables []*influxdb.Variable
+	for _, stmt := range extern.Body {
+		opt, ok := stmt.(*ast.OptionStatement)
+		if !ok {
+			return invalid
+		}
+		va, ok := opt.Assignment.(*ast.VariableAssignment)
+		if !ok || va.ID.Name != "v" {
+			return invalid
+		}
+		obj, ok := va.Init.(*ast.ObjectExpression)
+		if !ok || obj.With != nil {
+			return invalid
+		}
+
+		for _, p := range obj.Properties {
+			name := p.Key.Key()
+			if dashboardTimeRangeVariables[name] {
+				if !isTimeRangeValue(p.Value) {
+					return invalid
+				}
+				continue
+			}
+
+			if variables == nil {
+				vs, err := h.VariableService.FindVariables(ctx, influxdb.VariableFilter{OrganizationID: &orgID})
+				if err != nil {
+					return err
+				}
+				variables = vs
+			}
+
+			lit, ok := p.Value.(*ast.StringLiteral)
+			if !ok || !isVariableChoice(variables, name, lit.Value) {
+				return &influxdb.Error{
+					Code: influxdb.EForbidden,
+					Msg:  fmt.Sprintf("variable %q is not set to one of its choices", name),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isTimeRangeValue returns true for the durations, times and calls of now() the
+// time range of a dashboard is set with.
+func isTimeRangeValue(e ast.Expression) bool {
+	switch e := e.(type) {
+	case *ast.DurationLiteral, *ast.DateTimeLiteral:
+		return true
+	case *ast.UnaryExpression:
+		_, ok := e.Argument.(*ast.DurationLiteral)
+		return ok && e.Operator == ast.SubtractionOperator
+	case *ast.CallExpression:
+		id, ok := e.Callee.(*ast.Identifier)
+		return ok && id.Name == "now" && len(e.Arguments) == 0
+	}
+	return false
+}
+
+// isVariableChoice returns true if the value is one of the choices of the constant
+// or map variable of the name. The choices of query variables are not known.
+func isVariableChoice(variables []*influxdb.Variable, name, value string) bool {
+	for _, v := range variables {
+		if v.Name != name || v.Arguments == nil {
+			continue
+		}
+		switch values := v.Arguments.Values.(type) {
+		case influxdb.VariableConstantValues:
+			for _, c := range values {
+				if c == value {
+					return true
+				}
+			}
+		case influxdb.VariableMapValues:
+			for _, c := range values {
+				if c == value {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 type langRequest struct {
 	Query string `json:"query"`
 }
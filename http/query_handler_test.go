@@ -19,6 +19,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/csv"
 	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
 	"github.com/influxdata/influxdb/v2"
 	platform "github.com/influxdata/influxdb/v2"
 	icontext "github.com/influxdata/influxdb/v2/context"
@@ -449,6 +450,148 @@ func TestFluxHandler_PostQuery_Errors(t *testing.T) {
 	})
 }
 
+func TestFluxHandler_PostQuery_DashboardShare(t *testing.T) {
+	orgSVC := newInMemKVSVC(t)
+	org := influxdb.Organization{Name: t.Name()}
+	if err := orgSVC.CreateOrganization(context.Background(), &org); err != nil {
+		t.Fatal(err)
+	}
+
+	const storedQuery = `from(bucket: v.bucket) |> range(start: v.timeRangeStart, stop: v.timeRangeStop)`
+	dashboardID := influxdb.ID(10)
+	share := &influxdb.DashboardShare{ID: influxdb.ID(11), DashboardID: dashboardID, OrgID: org.ID}
+
+	var queried bool
+	b := &FluxBackend{
+		HTTPErrorHandler:    kithttp.ErrorHandler(0),
+		log:                 zaptest.NewLogger(t),
+		QueryEventRecorder:  noopEventRecorder{},
+		OrganizationService: orgSVC,
+		ProxyQueryService: &mock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				queried = true
+				return flux.Statistics{}, nil
+			},
+		},
+		DashboardService: &influxmock.DashboardService{
+			FindDashboardByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.Dashboard, error) {
+				return &influxdb.Dashboard{
+					ID:             id,
+					OrganizationID: org.ID,
+					Cells:          []*influxdb.Cell{{ID: influxdb.ID(12)}},
+				}, nil
+			},
+			GetDashboardCellViewF: func(ctx context.Context, dashboardID, cellID influxdb.ID) (*influxdb.View, error) {
+				return &influxdb.View{
+					Properties: influxdb.XYViewProperties{
+						Queries: []influxdb.DashboardQuery{{Text: storedQuery}},
+					},
+				}, nil
+			},
+		},
+		VariableService: &influxmock.VariableService{
+			FindVariablesF: func(ctx context.Context, filter influxdb.VariableFilter, opts ...influxdb.FindOptions) ([]*influxdb.Variable, error) {
+				return []*influxdb.Variable{
+					{
+						Name:      "bucket",
+						Arguments: &influxdb.VariableArguments{Type: "constant", Values: influxdb.VariableConstantValues{"telegraf", "system"}},
+					},
+					{
+						Name:      "host",
+						Arguments: &influxdb.VariableArguments{Type: "query", Values: influxdb.VariableQueryValues{Query: "buckets()", Language: "flux"}},
+					},
+				}, nil
+			},
+		},
+	}
+	h := NewFluxHandler(zaptest.NewLogger(t), b)
+
+	tests := []struct {
+		name     string
+		otherOrg bool
+		query    string
+		extern   string
+		status   int
+	}{
+		{
+			name:   "stored query with time range and variable choice",
+			query:  storedQuery,
+			extern: `option v = {timeRangeStart: -1h, timeRangeStop: now(), windowPeriod: 10000ms, bucket: "system"}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "query not stored in the dashboard",
+			query:  `from(bucket: "secrets") |> range(start: -1h)`,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "variable not set to one of its choices",
+			query:  storedQuery,
+			extern: `option v = {timeRangeStart: -1h, bucket: "secrets"}`,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "query variable",
+			query:  storedQuery,
+			extern: `option v = {host: "server01"}`,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "time range set with an expression",
+			query:  storedQuery,
+			extern: `option v = {timeRangeStart: (() => -1h)()}`,
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "other options",
+			query:  storedQuery,
+			extern: `option now = () => 2020-01-01T00:00:00Z`,
+			status: http.StatusForbidden,
+		},
+		{
+			name:     "other organization",
+			otherOrg: true,
+			query:    storedQuery,
+			status:   http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queried = false
+			orgID := org.ID
+			if tt.otherOrg {
+				other := influxdb.Organization{Name: tt.name}
+				if err := orgSVC.CreateOrganization(context.Background(), &other); err != nil {
+					t.Fatal(err)
+				}
+				orgID = other.ID
+			}
+
+			qr := QueryRequest{Query: tt.query}
+			if tt.extern != "" {
+				qr.Extern = parser.ParseSource(tt.extern).Files[0]
+			}
+			body, err := json.Marshal(qr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest("POST", "/api/v2/query?orgID="+orgID.String(), bytes.NewReader(body))
+			req = req.WithContext(icontext.SetAuthorizer(req.Context(), share))
+			w := httptest.NewRecorder()
+			h.handleQuery(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if queried != (tt.status == http.StatusOK) {
+				t.Errorf("expected the query to run %v, ran %v", tt.status == http.StatusOK, queried)
+			}
+		})
+	}
+}
+
 func TestFluxService_Query_gzip(t *testing.T) {
 	// orgService is just to mock out orgs by returning
 	// the same org every time.
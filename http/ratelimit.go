@@ -0,0 +1,455 @@
+package http
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	platcontext "github.com/influxdata/influxdb/v2/context"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+// usageRetention is how long the usage of the write and query APIs is kept.
+const usageRetention = 32 * 24 * time.Hour
+
+var (
+	_ influxdb.RateLimitService = (*RateLimiter)(nil)
+	_ influxdb.UsageService     = (*RateLimiter)(nil)
+)
+
+// RateLimiter enforces the rate limits of organizations and their tokens on
+// the write and query APIs, and keeps the usage of the APIs by each organization.
+// Only requests authorized by tokens are limited and counted, as the organization
+// of a session is not known until the request is handled.
+//
+// Changes to limits made through the RateLimiter take effect immediately. The
+// usage is kept in memory, hourly, and is lost on restart.
+type RateLimiter struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	RateLimitService influxdb.RateLimitService
+	TimeGenerator    influxdb.TimeGenerator
+
+	mu     sync.Mutex
+	limits map[influxdb.ID][]*influxdb.RateLimit
+	states map[rateLimitKey]*rateLimitState
+	usage  map[influxdb.ID]map[time.Time]*usageCounts
+}
+
+// NewRateLimiter returns a rate limiter enforcing the limits of the service.
+func NewRateLimiter(log *zap.Logger, he influxdb.HTTPErrorHandler, s influxdb.RateLimitService) *RateLimiter {
+	return &RateLimiter{
+		HTTPErrorHandler: he,
+		log:              log,
+		RateLimitService: s,
+		TimeGenerator:    influxdb.RealTimeGenerator{},
+		limits:           make(map[influxdb.ID][]*influxdb.RateLimit),
+		states:           make(map[rateLimitKey]*rateLimitState),
+		usage:            make(map[influxdb.ID]map[time.Time]*usageCounts),
+	}
+}
+
+// FindRateLimits returns the rate limits matching the filter.
+func (l *RateLimiter) FindRateLimits(ctx context.Context, filter influxdb.RateLimitFilter) ([]*influxdb.RateLimit, error) {
+	return l.RateLimitService.FindRateLimits(ctx, filter)
+}
+
+// SetRateLimit sets the rate limit and enforces it from the next request.
+func (l *RateLimiter) SetRateLimit(ctx context.Context, rl *influxdb.RateLimit) error {
+	if err := l.RateLimitService.SetRateLimit(ctx, rl); err != nil {
+		return err
+	}
+	l.invalidate(rl.OrgID)
+	return nil
+}
+
+// DeleteRateLimit removes the rate limit and stops enforcing it from the next request.
+func (l *RateLimiter) DeleteRateLimit(ctx context.Context, orgID, authID influxdb.ID) error {
+	if err := l.RateLimitService.DeleteRateLimit(ctx, orgID, authID); err != nil {
+		return err
+	}
+	l.invalidate(orgID)
+	return nil
+}
+
+func (l *RateLimiter) invalidate(orgID influxdb.ID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.limits, orgID)
+}
+
+// GetUsage returns the usage of the write and query APIs within the range of the
+// filter, at an hourly granularity. Usage is not kept by bucket.
+func (l *RateLimiter) GetUsage(ctx context.Context, filter influxdb.UsageFilter) (map[influxdb.UsageMetric]*influxdb.Usage, error) {
+	if filter.BucketID != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "usage of the write and query APIs is not kept by bucket",
+		}
+	}
+
+	var sum usageCounts
+	l.mu.Lock()
+	for orgID, hours := range l.usage {
+		if filter.OrgID != nil && orgID != *filter.OrgID {
+			continue
+		}
+		for hour, c := range hours {
+			if filter.Range != nil && (!hour.Add(time.Hour).After(filter.Range.Start) || !hour.Before(filter.Range.Stop)) {
+				continue
+			}
+			sum.add(c)
+		}
+	}
+	l.mu.Unlock()
+
+	usage := make(map[influxdb.UsageMetric]*influxdb.Usage)
+	for metric, v := range map[influxdb.UsageMetric]float64{
+		influxdb.UsageWriteRequestCount:       sum.writeRequests,
+		influxdb.UsageWriteRequestBytes:       sum.writeBytes,
+		influxdb.UsageQueryRequestCount:       sum.queryRequests,
+		influxdb.UsageQueryRequestBytes:       sum.queryBytes,
+		influxdb.UsageRateLimitedRequestCount: sum.rateLimited,
+	} {
+		usage[metric] = &influxdb.Usage{
+			OrganizationID: filter.OrgID,
+			Type:           metric,
+			Value:          v,
+		}
+	}
+	return usage, nil
+}
+
+// LimitWrites returns a handler enforcing the request rate and write bytes
+// limits on the write requests handled by next.
+func (l *RateLimiter) LimitWrites(next http.Handler) http.Handler {
+	return l.limit(next, rateLimitedWrite)
+}
+
+// LimitQueries returns a handler enforcing the request rate and query
+// concurrency limits on the queries executed by next.
+func (l *RateLimiter) LimitQueries(next http.Handler) http.Handler {
+	return l.limit(next, rateLimitedQuery)
+}
+
+type rateLimitedRequest int
+
+const (
+	rateLimitedWrite rateLimitedRequest = iota
+	rateLimitedQuery
+)
+
+func (l *RateLimiter) limit(next http.Handler, kind rateLimitedRequest) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		// the query handler also serves the analysis of queries, which is not limited.
+		if kind == rateLimitedQuery && (r.Method != http.MethodPost || r.URL.Path != prefixQuery) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		a, err := platcontext.GetAuthorizer(ctx)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		var auth *influxdb.Authorization
+		switch a := a.(type) {
+		case *influxdb.Authorization:
+			auth = a
+		case *influxdb.DashboardShare:
+			// the queries of share links count towards the limits of the organization of the dashboard
+			auth = a.EphemeralAuth()
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limits, err := l.findLimits(ctx, auth.OrgID)
+		if err != nil {
+			l.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		states, retryAfter, limited := l.acquire(auth, limits, kind)
+		if limited != nil {
+			l.log.Debug("Request rate limited",
+				zap.String("orgID", auth.OrgID.String()),
+				zap.String("authorizationID", auth.ID.String()),
+				zap.Duration("retryAfter", retryAfter),
+			)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			l.HandleHTTPError(ctx, limited, w)
+			return
+		}
+
+		body := &countingReadCloser{ReadCloser: r.Body}
+		r.Body = body
+		sw := kithttp.NewStatusResponseWriter(w)
+		defer func() {
+			l.release(auth.OrgID, states, kind, body.n, sw.ResponseBytes())
+		}()
+		next.ServeHTTP(sw, r)
+	})
+}
+
+// findLimits returns the limits of the organization, looking them up on the
+// first request after they change.
+func (l *RateLimiter) findLimits(ctx context.Context, orgID influxdb.ID) ([]*influxdb.RateLimit, error) {
+	if l.RateLimitService == nil {
+		return nil, nil
+	}
+
+	l.mu.Lock()
+	limits, ok := l.limits[orgID]
+	l.mu.Unlock()
+	if ok {
+		return limits, nil
+	}
+
+	limits, err := l.RateLimitService.FindRateLimits(ctx, influxdb.RateLimitFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	l.limits[orgID] = limits
+	l.mu.Unlock()
+	return limits, nil
+}
+
+// acquire admits a request made with the authorization, returning the states of
+// the limits it counts towards, or how long to wait when a limit is exceeded.
+func (l *RateLimiter) acquire(auth *influxdb.Authorization, limits []*influxdb.RateLimit, kind rateLimitedRequest) ([]*rateLimitState, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.TimeGenerator.Now()
+
+	var (
+		states     []*rateLimitState
+		retryAfter time.Duration
+		exceeded   *influxdb.RateLimit
+	)
+	for _, limit := range limits {
+		if limit.AuthorizationID.Valid() && limit.AuthorizationID != auth.ID {
+			continue
+		}
+
+		s := l.state(limit, now)
+		states = append(states, s)
+
+		var wait time.Duration
+		if s.requests != nil {
+			wait = s.requests.wait(now, 1)
+		}
+		if kind == rateLimitedWrite && s.writeBytes != nil {
+			wait = maxDuration(wait, s.writeBytes.wait(now, 0))
+		}
+		if kind == rateLimitedQuery && limit.QueryConcurrency > 0 && s.queries >= limit.QueryConcurrency {
+			// there is no telling when a query will finish
+			wait = maxDuration(wait, time.Second)
+		}
+		if wait > retryAfter {
+			retryAfter, exceeded = wait, limit
+		}
+	}
+
+	if exceeded != nil {
+		l.record(auth.OrgID, now, func(c *usageCounts) {
+			c.rateLimited++
+		})
+		msg := "organization rate limit exceeded"
+		if exceeded.AuthorizationID.Valid() {
+			msg = "token rate limit exceeded"
+		}
+		return nil, retryAfter, &influxdb.Error{
+			Code: influxdb.ETooManyRequests,
+			Msg:  msg,
+		}
+	}
+
+	for _, s := range states {
+		if s.requests != nil {
+			s.requests.take(now, 1)
+		}
+		if kind == rateLimitedQuery {
+			s.queries++
+		}
+	}
+	return states, 0, nil
+}
+
+// release ends a request admitted by acquire, accounting for the bytes it wrote or read.
+func (l *RateLimiter) release(orgID influxdb.ID, states []*rateLimitState, kind rateLimitedRequest, requestBytes int64, responseBytes int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.TimeGenerator.Now()
+
+	for _, s := range states {
+		switch kind {
+		case rateLimitedWrite:
+			if s.writeBytes != nil {
+				s.writeBytes.take(now, float64(requestBytes))
+			}
+		case rateLimitedQuery:
+			s.queries--
+		}
+	}
+
+	l.record(orgID, now, func(c *usageCounts) {
+		switch kind {
+		case rateLimitedWrite:
+			c.writeRequests++
+			c.writeBytes += float64(requestBytes)
+		case rateLimitedQuery:
+			// the bytes of a query are those of its results
+			c.queryRequests++
+			c.queryBytes += float64(responseBytes)
+		}
+	})
+}
+
+// state returns the state of the limit, updating it to the current value of the
+// limit. It must be called with the lock held.
+func (l *RateLimiter) state(limit *influxdb.RateLimit, now time.Time) *rateLimitState {
+	k := rateLimitKey{orgID: limit.OrgID, authID: limit.AuthorizationID}
+	s, ok := l.states[k]
+	if !ok {
+		s = &rateLimitState{}
+		l.states[k] = s
+	}
+	if s.limit != limit {
+		s.limit = limit
+		s.requests = updateTokenBucket(s.requests, limit.RequestsPerSecond, now)
+		s.writeBytes = updateTokenBucket(s.writeBytes, limit.WriteBytesPerSecond, now)
+	}
+	return s
+}
+
+// record updates the usage of the organization in the current hour, dropping
+// the usage older than is kept. It must be called with the lock held.
+func (l *RateLimiter) record(orgID influxdb.ID, now time.Time, fn func(*usageCounts)) {
+	hour := now.UTC().Truncate(time.Hour)
+	hours, ok := l.usage[orgID]
+	if !ok {
+		hours = make(map[time.Time]*usageCounts)
+		l.usage[orgID] = hours
+	}
+	c, ok := hours[hour]
+	if !ok {
+		for h := range hours {
+			if hour.Sub(h) > usageRetention {
+				delete(hours, h)
+			}
+		}
+		c = &usageCounts{}
+		hours[hour] = c
+	}
+	fn(c)
+}
+
+type rateLimitKey struct {
+	orgID  influxdb.ID
+	authID influxdb.ID
+}
+
+// rateLimitState is the use of a limit by the requests made so far.
+type rateLimitState struct {
+	limit      *influxdb.RateLimit
+	requests   *tokenBucket
+	writeBytes *tokenBucket
+	queries    int
+}
+
+type usageCounts struct {
+	writeRequests float64
+	writeBytes    float64
+	queryRequests float64
+	queryBytes    float64
+	rateLimited   float64
+}
+
+func (c *usageCounts) add(o *usageCounts) {
+	c.writeRequests += o.writeRequests
+	c.writeBytes += o.writeBytes
+	c.queryRequests += o.queryRequests
+	c.queryBytes += o.queryBytes
+	c.rateLimited += o.rateLimited
+}
+
+// tokenBucket allows a rate of units a second, bursting up to a second's worth.
+// The units of a request are taken once it is known how many it used, so the
+// bucket may fall into debt, which is paid back before more are allowed.
+type tokenBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// updateTokenBucket returns a bucket for the rate, keeping the tokens of b if
+// any, or nil if the rate is unlimited.
+func updateTokenBucket(b *tokenBucket, rate float64, now time.Time) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if b == nil {
+		b = &tokenBucket{rate: rate, last: now}
+		b.tokens = b.burst()
+		return b
+	}
+	b.refill(now)
+	b.rate = rate
+	b.tokens = math.Min(b.tokens, b.burst())
+	return b
+}
+
+func (b *tokenBucket) burst() float64 {
+	return math.Max(b.rate, 1)
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst(), b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// wait returns how long it is until n units are available.
+func (b *tokenBucket) wait(now time.Time, n float64) time.Duration {
+	b.refill(now)
+	if b.tokens >= n {
+		return 0
+	}
+	return time.Duration((n - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) take(now time.Time, n float64) {
+	b.refill(now)
+	b.tokens -= n
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// countingReadCloser counts the bytes read from the body of a request.
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"go.uber.org/zap"
+)
+
+// RateLimitBackend is all services and associated parameters required to construct
+// the RateLimitHandler.
+type RateLimitBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	RateLimitService influxdb.RateLimitService
+}
+
+// NewRateLimitBackend creates a backend used by the rate limit handler.
+func NewRateLimitBackend(log *zap.Logger, b *APIBackend) *RateLimitBackend {
+	return &RateLimitBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		RateLimitService: b.RateLimitService,
+	}
+}
+
+// RateLimitHandler represents an HTTP API handler for the rate limits of
+// organizations and tokens.
+type RateLimitHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	RateLimitService influxdb.RateLimitService
+}
+
+const (
+	prefixRateLimits = "/api/v2/ratelimits"
+)
+
+// NewRateLimitHandler returns a new instance of RateLimitHandler.
+func NewRateLimitHandler(log *zap.Logger, b *RateLimitBackend) *RateLimitHandler {
+	h := &RateLimitHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		RateLimitService: b.RateLimitService,
+	}
+
+	h.HandlerFunc("GET", prefixRateLimits, h.handleGetRateLimits)
+	h.HandlerFunc("PUT", prefixRateLimits, h.handlePutRateLimit)
+	h.HandlerFunc("DELETE", prefixRateLimits, h.handleDeleteRateLimit)
+	return h
+}
+
+type rateLimitsResponse struct {
+	Links      map[string]string     `json:"links"`
+	RateLimits []*influxdb.RateLimit `json:"rateLimits"`
+}
+
+// handleGetRateLimits is the HTTP handler for the GET /api/v2/ratelimits route.
+func (h *RateLimitHandler) handleGetRateLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, err := decodeRateLimitFilter(r.URL.Query())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	limits, err := h.RateLimitService.FindRateLimits(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Rate limits retrieved", zap.String("rateLimits", fmt.Sprint(limits)))
+
+	res := rateLimitsResponse{
+		Links: map[string]string{
+			"self": prefixRateLimits,
+		},
+		RateLimits: limits,
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePutRateLimit is the HTTP handler for the PUT /api/v2/ratelimits route.
+func (h *RateLimitHandler) handlePutRateLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := &influxdb.RateLimit{}
+	if err := json.NewDecoder(r.Body).Decode(l); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode rate limit request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.RateLimitService.SetRateLimit(ctx, l); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Rate limit set", zap.String("rateLimit", fmt.Sprint(l)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, l); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteRateLimit is the HTTP handler for the DELETE /api/v2/ratelimits route.
+func (h *RateLimitHandler) handleDeleteRateLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qp := r.URL.Query()
+	if qp.Get("orgID") == "" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "orgID is required",
+		}, w)
+		return
+	}
+
+	orgID, err := decodeIDFromQuery(qp, "orgID")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	authID, err := decodeIDFromQuery(qp, "authorizationID")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RateLimitService.DeleteRateLimit(ctx, orgID, authID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Rate limit deleted", zap.String("orgID", orgID.String()), zap.String("authorizationID", authID.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeRateLimitFilter(qp url.Values) (influxdb.RateLimitFilter, error) {
+	var filter influxdb.RateLimitFilter
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "orgID is invalid",
+				Err:  err,
+			}
+		}
+		filter.OrgID = id
+	}
+	if authID := qp.Get("authorizationID"); authID != "" {
+		id, err := influxdb.IDFromString(authID)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "authorizationID is invalid",
+				Err:  err,
+			}
+		}
+		filter.AuthorizationID = id
+	}
+	return filter, nil
+}
@@ -0,0 +1,184 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type fakeRateLimits []*influxdb.RateLimit
+
+func (f *fakeRateLimits) FindRateLimits(ctx context.Context, filter influxdb.RateLimitFilter) ([]*influxdb.RateLimit, error) {
+	var limits []*influxdb.RateLimit
+	for _, l := range *f {
+		if filter.OrgID == nil || l.OrgID == *filter.OrgID {
+			limits = append(limits, l)
+		}
+	}
+	return limits, nil
+}
+
+func (f *fakeRateLimits) SetRateLimit(ctx context.Context, l *influxdb.RateLimit) error {
+	*f = append(*f, l)
+	return nil
+}
+
+func (f *fakeRateLimits) DeleteRateLimit(ctx context.Context, orgID, authID influxdb.ID) error {
+	return nil
+}
+
+func TestRateLimiter(t *testing.T) {
+	orgID := influxdb.ID(1)
+	auth := &influxdb.Authorization{ID: 10, OrgID: orgID}
+	otherAuth := &influxdb.Authorization{ID: 11, OrgID: orgID}
+
+	newLimiter := func(t *testing.T, limits ...*influxdb.RateLimit) (*RateLimiter, *mock.TimeGenerator) {
+		svc := fakeRateLimits(limits)
+		l := NewRateLimiter(zaptest.NewLogger(t), kithttp.ErrorHandler(0), &svc)
+		now := &mock.TimeGenerator{FakeValue: time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)}
+		l.TimeGenerator = now
+		return l, now
+	}
+
+	do := func(h http.Handler, a influxdb.Authorizer, method, target, body string) *http.Response {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		if a != nil {
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), a))
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	write := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	t.Run("limits the requests of an organization", func(t *testing.T) {
+		l, now := newLimiter(t, &influxdb.RateLimit{OrgID: orgID, RequestsPerSecond: 2})
+		h := l.LimitWrites(write)
+
+		assert.Equal(t, http.StatusNoContent, do(h, auth, "POST", "/api/v2/write", "").StatusCode)
+		assert.Equal(t, http.StatusNoContent, do(h, otherAuth, "POST", "/api/v2/write", "").StatusCode)
+
+		res := do(h, auth, "POST", "/api/v2/write", "")
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		assert.Equal(t, "1", res.Header.Get("Retry-After"))
+
+		now.FakeValue = now.FakeValue.Add(500 * time.Millisecond)
+		assert.Equal(t, http.StatusNoContent, do(h, auth, "POST", "/api/v2/write", "").StatusCode)
+	})
+
+	t.Run("limits the requests of a token", func(t *testing.T) {
+		l, _ := newLimiter(t, &influxdb.RateLimit{OrgID: orgID, AuthorizationID: auth.ID, RequestsPerSecond: 1})
+		h := l.LimitWrites(write)
+
+		assert.Equal(t, http.StatusNoContent, do(h, auth, "POST", "/api/v2/write", "").StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, do(h, auth, "POST", "/api/v2/write", "").StatusCode)
+		assert.Equal(t, http.StatusNoContent, do(h, otherAuth, "POST", "/api/v2/write", "").StatusCode)
+	})
+
+	t.Run("limits the bytes written", func(t *testing.T) {
+		l, now := newLimiter(t, &influxdb.RateLimit{OrgID: orgID, WriteBytesPerSecond: 10})
+		h := l.LimitWrites(write)
+
+		assert.Equal(t, http.StatusNoContent, do(h, auth, "POST", "/api/v2/write", strings.Repeat("x", 25)).StatusCode)
+
+		res := do(h, auth, "POST", "/api/v2/write", "m f=1")
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		assert.Equal(t, "2", res.Header.Get("Retry-After"))
+
+		now.FakeValue = now.FakeValue.Add(2 * time.Second)
+		assert.Equal(t, http.StatusNoContent, do(h, auth, "POST", "/api/v2/write", "m f=1").StatusCode)
+	})
+
+	t.Run("limits the queries executing at a time", func(t *testing.T) {
+		l, _ := newLimiter(t, &influxdb.RateLimit{OrgID: orgID, QueryConcurrency: 1})
+		started, finish := make(chan struct{}), make(chan struct{})
+		var calls int32
+		h := l.LimitQueries(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				close(started)
+				<-finish
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		done := make(chan *http.Response)
+		go func() {
+			done <- do(h, auth, "POST", prefixQuery, `{"query":"from(bucket:\"b\")"}`)
+		}()
+		<-started
+
+		res := do(h, otherAuth, "POST", prefixQuery, `{"query":"from(bucket:\"b\")"}`)
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		assert.Equal(t, "1", res.Header.Get("Retry-After"))
+		assert.Equal(t, http.StatusOK, do(h, otherAuth, "POST", prefixQuery+"/ast", "").StatusCode)
+
+		close(finish)
+		assert.Equal(t, http.StatusOK, (<-done).StatusCode)
+		assert.Equal(t, http.StatusOK, do(h, otherAuth, "POST", prefixQuery, "").StatusCode)
+	})
+
+	t.Run("does not limit sessions", func(t *testing.T) {
+		l, _ := newLimiter(t, &influxdb.RateLimit{OrgID: orgID, RequestsPerSecond: 1})
+		h := l.LimitWrites(write)
+		session := &influxdb.Session{ID: 20, UserID: 30}
+
+		assert.Equal(t, http.StatusNoContent, do(h, session, "POST", "/api/v2/write", "").StatusCode)
+		assert.Equal(t, http.StatusNoContent, do(h, session, "POST", "/api/v2/write", "").StatusCode)
+	})
+
+	t.Run("applies changed limits", func(t *testing.T) {
+		l, _ := newLimiter(t)
+		h := l.LimitWrites(write)
+
+		assert.Equal(t, http.StatusNoContent, do(h, auth, "POST", "/api/v2/write", "").StatusCode)
+		require.NoError(t, l.SetRateLimit(context.Background(), &influxdb.RateLimit{OrgID: orgID, RequestsPerSecond: 1}))
+		assert.Equal(t, http.StatusNoContent, do(h, auth, "POST", "/api/v2/write", "").StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, do(h, auth, "POST", "/api/v2/write", "").StatusCode)
+	})
+
+	t.Run("reports usage", func(t *testing.T) {
+		l, now := newLimiter(t, &influxdb.RateLimit{OrgID: orgID, RequestsPerSecond: 1})
+		writes := l.LimitWrites(write)
+		queries := l.LimitQueries(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("result"))
+		}))
+
+		assert.Equal(t, http.StatusNoContent, do(writes, auth, "POST", "/api/v2/write", "m f=1").StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, do(writes, auth, "POST", "/api/v2/write", "m f=1").StatusCode)
+		now.FakeValue = now.FakeValue.Add(time.Hour)
+		assert.Equal(t, http.StatusOK, do(queries, auth, "POST", prefixQuery, "").StatusCode)
+
+		usage, err := l.GetUsage(context.Background(), influxdb.UsageFilter{OrgID: &orgID})
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), usage[influxdb.UsageWriteRequestCount].Value)
+		assert.Equal(t, float64(5), usage[influxdb.UsageWriteRequestBytes].Value)
+		assert.Equal(t, float64(1), usage[influxdb.UsageQueryRequestCount].Value)
+		assert.Equal(t, float64(6), usage[influxdb.UsageQueryRequestBytes].Value)
+		assert.Equal(t, float64(1), usage[influxdb.UsageRateLimitedRequestCount].Value)
+
+		usage, err = l.GetUsage(context.Background(), influxdb.UsageFilter{
+			OrgID: &orgID,
+			Range: &influxdb.Timespan{Start: now.FakeValue, Stop: now.FakeValue.Add(time.Minute)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, float64(0), usage[influxdb.UsageWriteRequestCount].Value)
+		assert.Equal(t, float64(1), usage[influxdb.UsageQueryRequestCount].Value)
+	})
+}
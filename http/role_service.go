@@ -0,0 +1,360 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/influxdata/httprouter"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/pkg/httpc"
+	"go.uber.org/zap"
+)
+
+// RoleBackend is all services and associated parameters required to construct
+// the RoleHandler.
+type RoleBackend struct {
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	RoleService                influxdb.RoleService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	UserService                influxdb.UserService
+}
+
+// NewRoleBackend creates a backend used by the role handler.
+func NewRoleBackend(log *zap.Logger, b *APIBackend) *RoleBackend {
+	return &RoleBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+
+		RoleService:                b.RoleService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+}
+
+// RoleHandler represents an HTTP API handler for roles.
+type RoleHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	log *zap.Logger
+
+	RoleService influxdb.RoleService
+}
+
+const (
+	prefixRoles          = "/api/v2/roles"
+	rolesIDPath          = "/api/v2/roles/:id"
+	rolesIDMembersPath   = "/api/v2/roles/:id/members"
+	rolesIDMembersIDPath = "/api/v2/roles/:id/members/:userID"
+)
+
+// NewRoleHandler returns a new instance of RoleHandler.
+func NewRoleHandler(log *zap.Logger, b *RoleBackend) *RoleHandler {
+	h := &RoleHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		log:              log,
+		RoleService:      b.RoleService,
+	}
+
+	h.HandlerFunc("POST", prefixRoles, h.handlePostRole)
+	h.HandlerFunc("GET", prefixRoles, h.handleGetRoles)
+
+	h.HandlerFunc("GET", rolesIDPath, h.handleGetRole)
+	h.HandlerFunc("PATCH", rolesIDPath, h.handlePatchRole)
+	h.HandlerFunc("DELETE", rolesIDPath, h.handleDeleteRole)
+
+	// users are assigned a role by making them a member of it.
+	memberBackend := MemberBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		log:                        b.log.With(zap.String("handler", "member")),
+		ResourceType:               influxdb.RolesResourceType,
+		UserType:                   influxdb.Member,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+	h.HandlerFunc("POST", rolesIDMembersPath, newPostMemberHandler(memberBackend))
+	h.HandlerFunc("GET", rolesIDMembersPath, newGetMembersHandler(memberBackend))
+	h.HandlerFunc("DELETE", rolesIDMembersIDPath, newDeleteMemberHandler(memberBackend))
+
+	return h
+}
+
+type roleResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Role
+}
+
+func newRoleResponse(r *influxdb.Role) *roleResponse {
+	return &roleResponse{
+		Links: map[string]string{
+			"self":    fmt.Sprintf("/api/v2/roles/%s", r.ID),
+			"members": fmt.Sprintf("/api/v2/roles/%s/members", r.ID),
+			"org":     fmt.Sprintf("/api/v2/orgs/%s", r.OrgID),
+		},
+		Role: *r,
+	}
+}
+
+type rolesResponse struct {
+	Links map[string]string `json:"links"`
+	Roles []*roleResponse   `json:"roles"`
+}
+
+func newRolesResponse(rs []*influxdb.Role) *rolesResponse {
+	res := &rolesResponse{
+		Links: map[string]string{
+			"self": prefixRoles,
+		},
+		Roles: make([]*roleResponse, 0, len(rs)),
+	}
+	for _, r := range rs {
+		res.Roles = append(res.Roles, newRoleResponse(r))
+	}
+	return res
+}
+
+// handlePostRole is the HTTP handler for the POST /api/v2/roles route.
+func (h *RoleHandler) handlePostRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	role := &influxdb.Role{}
+	if err := json.NewDecoder(r.Body).Decode(role); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode role request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.RoleService.CreateRole(ctx, role); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role created", zap.String("role", fmt.Sprint(role)))
+	if err := encodeResponse(ctx, w, http.StatusCreated, newRoleResponse(role)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleGetRoles is the HTTP handler for the GET /api/v2/roles route.
+func (h *RoleHandler) handleGetRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, err := decodeRoleFilter(r.URL.Query())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	opts, err := decodeFindOptions(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	roles, _, err := h.RoleService.FindRoles(ctx, filter, *opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Roles retrieved", zap.String("roles", fmt.Sprint(roles)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newRolesResponse(roles)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func decodeRoleFilter(qp url.Values) (influxdb.RoleFilter, error) {
+	var filter influxdb.RoleFilter
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = id
+	}
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+	return filter, nil
+}
+
+// handleGetRole is the HTTP handler for the GET /api/v2/roles/:id route.
+func (h *RoleHandler) handleGetRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	role, err := h.RoleService.FindRoleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role retrieved", zap.String("role", fmt.Sprint(role)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handlePatchRole is the HTTP handler for the PATCH /api/v2/roles/:id route.
+func (h *RoleHandler) handlePatchRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.RoleUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode role update",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	role, err := h.RoleService.UpdateRole(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role updated", zap.String("role", fmt.Sprint(role)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// handleDeleteRole is the HTTP handler for the DELETE /api/v2/roles/:id route.
+func (h *RoleHandler) handleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := decodeRoleID(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.DeleteRole(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Role deleted", zap.String("roleID", id.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeRoleID(ctx context.Context) (influxdb.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i influxdb.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+// RoleService connects to Influx via HTTP using tokens to manage roles.
+type RoleService struct {
+	Client *httpc.Client
+}
+
+var _ influxdb.RoleService = (*RoleService)(nil)
+
+// FindRoleByID returns a single role by ID.
+func (s *RoleService) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	var rr roleResponse
+	err := s.Client.
+		Get(prefixRoles, id.String()).
+		DecodeJSON(&rr).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &rr.Role, nil
+}
+
+// FindRoles returns a list of roles that match filter.
+func (s *RoleService) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opts ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	if filter.ID != nil {
+		r, err := s.FindRoleByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*influxdb.Role{r}, 1, nil
+	}
+
+	params := findOptionParams(opts...)
+	if filter.OrgID != nil {
+		params = append(params, [2]string{"orgID", filter.OrgID.String()})
+	}
+	if filter.Name != nil {
+		params = append(params, [2]string{"name", *filter.Name})
+	}
+
+	var rs rolesResponse
+	err := s.Client.
+		Get(prefixRoles).
+		QueryParams(params...).
+		DecodeJSON(&rs).
+		Do(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	roles := make([]*influxdb.Role, 0, len(rs.Roles))
+	for _, r := range rs.Roles {
+		roles = append(roles, &r.Role)
+	}
+	return roles, len(roles), nil
+}
+
+// CreateRole creates a new role and sets r.ID with the new identifier.
+func (s *RoleService) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	var rr roleResponse
+	err := s.Client.
+		PostJSON(r, prefixRoles).
+		DecodeJSON(&rr).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	*r = rr.Role
+	return nil
+}
+
+// UpdateRole updates a single role with a changeset.
+func (s *RoleService) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	var rr roleResponse
+	err := s.Client.
+		PatchJSON(upd, prefixRoles, id.String()).
+		DecodeJSON(&rr).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &rr.Role, nil
+}
+
+// DeleteRole removes a role by ID.
+func (s *RoleService) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	return s.Client.
+		Delete(prefixRoles, id.String()).
+		Do(ctx)
+}
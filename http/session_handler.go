@@ -2,10 +2,16 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/influxdata/httprouter"
 	platform "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/oidc"
 	"go.uber.org/zap"
 )
 
@@ -23,6 +29,16 @@ type SessionBackend struct {
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
 	UserService      platform.UserService
+
+	// SigninLockoutService, if set, locks users out after failing to sign in too often.
+	SigninLockoutService platform.SigninLockoutService
+	// MFAService, if set, requires users with a second factor to sign in with a code of it.
+	MFAService platform.MFAService
+
+	// OIDCProvider enables signing in with OpenID Connect when it is set.
+	OIDCProvider               *oidc.Provider
+	OrganizationService        platform.OrganizationService
+	UserResourceMappingService platform.UserResourceMappingService
 }
 
 // newSessionBackend creates a new SessionBackend with associated logger.
@@ -34,6 +50,13 @@ func newSessionBackend(log *zap.Logger, b *APIBackend) *SessionBackend {
 		PasswordsService: b.PasswordsService,
 		SessionService:   b.SessionService,
 		UserService:      b.UserService,
+
+		SigninLockoutService: b.SigninLockoutService,
+		MFAService:           b.MFAService,
+
+		OIDCProvider:               b.OIDCProvider,
+		OrganizationService:        b.OrganizationService,
+		UserResourceMappingService: b.UserResourceMappingService,
 	}
 }
 
@@ -46,6 +69,13 @@ type SessionHandler struct {
 	PasswordsService platform.PasswordsService
 	SessionService   platform.SessionService
 	UserService      platform.UserService
+
+	SigninLockoutService platform.SigninLockoutService
+	MFAService           platform.MFAService
+
+	OIDCProvider               *oidc.Provider
+	OrganizationService        platform.OrganizationService
+	UserResourceMappingService platform.UserResourceMappingService
 }
 
 // NewSessionHandler returns a new instance of SessionHandler.
@@ -58,10 +88,21 @@ func NewSessionHandler(log *zap.Logger, b *SessionBackend) *SessionHandler {
 		PasswordsService: b.PasswordsService,
 		SessionService:   b.SessionService,
 		UserService:      b.UserService,
+
+		SigninLockoutService: b.SigninLockoutService,
+		MFAService:           b.MFAService,
+
+		OIDCProvider:               b.OIDCProvider,
+		OrganizationService:        b.OrganizationService,
+		UserResourceMappingService: b.UserResourceMappingService,
 	}
 
 	h.HandlerFunc("POST", prefixSignIn, h.handleSignin)
 	h.HandlerFunc("POST", prefixSignOut, h.handleSignout)
+	if h.OIDCProvider != nil {
+		h.HandlerFunc("GET", prefixSignInOIDC, h.handleSigninOIDC)
+		h.HandlerFunc("GET", prefixSignInOIDCCallback, h.handleSigninOIDCCallback)
+	}
 	return h
 }
 
@@ -83,12 +124,52 @@ func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.SigninLockoutService != nil {
+		until, err := h.SigninLockoutService.SigninLockedUntil(ctx, u.ID)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		if !until.IsZero() {
+			h.lockedOut(ctx, w, until)
+			return
+		}
+	}
+
 	if err := h.PasswordsService.ComparePassword(ctx, u.ID, req.Password); err != nil {
 		// Don't log here, it should already be handled by the service
-		UnauthorizedError(ctx, h, w)
+		h.signinFailed(ctx, w, u.ID)
 		return
 	}
 
+	if h.MFAService != nil {
+		enabled, err := h.MFAService.MFAEnabled(ctx, u.ID)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		if enabled && req.Code == "" {
+			h.HandleHTTPError(ctx, &platform.Error{
+				Code: platform.EUnauthorized,
+				Msg:  "a one-time code is required",
+			}, w)
+			return
+		}
+		if enabled {
+			if err := h.MFAService.VerifyMFA(ctx, u.ID, req.Code); err != nil {
+				h.signinFailed(ctx, w, u.ID)
+				return
+			}
+		}
+	}
+
+	if h.SigninLockoutService != nil {
+		if err := h.SigninLockoutService.ResetSigninFailures(ctx, u.ID); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
 	s, e := h.SessionService.CreateSession(ctx, req.Username)
 	if e != nil {
 		UnauthorizedError(ctx, h, w)
@@ -99,9 +180,35 @@ func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// signinFailed counts the failed attempt of the user, responding that they are
+// locked out once they have failed too often.
+func (h *SessionHandler) signinFailed(ctx context.Context, w http.ResponseWriter, userID platform.ID) {
+	if h.SigninLockoutService != nil {
+		until, err := h.SigninLockoutService.RecordSigninFailure(ctx, userID)
+		if err != nil {
+			h.log.Error("Failed to record failed sign in", zap.Error(err))
+		} else if !until.IsZero() {
+			h.lockedOut(ctx, w, until)
+			return
+		}
+	}
+	UnauthorizedError(ctx, h, w)
+}
+
+func (h *SessionHandler) lockedOut(ctx context.Context, w http.ResponseWriter, until time.Time) {
+	retryAfter := math.Ceil(time.Until(until).Seconds())
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Max(retryAfter, 1))))
+	h.HandleHTTPError(ctx, &platform.Error{
+		Code: platform.ETooManyRequests,
+		Msg:  "too many failed sign in attempts",
+	}, w)
+}
+
 type signinRequest struct {
 	Username string
 	Password string
+	// Code is a one-time code, or a recovery code, of the second factor of the user.
+	Code string
 }
 
 func decodeSigninRequest(ctx context.Context, r *http.Request) (*signinRequest, *platform.Error) {
@@ -113,10 +220,27 @@ func decodeSigninRequest(ctx context.Context, r *http.Request) (*signinRequest,
 		}
 	}
 
-	return &signinRequest{
+	req := &signinRequest{
 		Username: u,
 		Password: p,
-	}, nil
+	}
+
+	// the body is optional, it is only needed by users with a second factor.
+	if r.Body != nil {
+		var body struct {
+			Code string `json:"code"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "invalid sign in request body",
+				Err:  err,
+			}
+		}
+		req.Code = body.Code
+	}
+
+	return req, nil
 }
 
 // handleSignout is the HTTP handler for the POST /signout route.
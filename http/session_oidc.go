@@ -0,0 +1,187 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	platform "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/oidc"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixSignInOIDC         = "/api/v2/signin/oidc"
+	prefixSignInOIDCCallback = "/api/v2/signin/oidc/callback"
+
+	// oidcCookieName holds the state, nonce and PKCE verifier of a sign in in progress.
+	oidcCookieName = "oidc_signin"
+	oidcCookieTTL  = 10 * time.Minute
+)
+
+// handleSigninOIDC is the HTTP handler for the GET /signin/oidc route,
+// it sends the user to the OpenID Connect provider to sign in.
+func (h *SessionHandler) handleSigninOIDC(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	secrets := make([]string, 3)
+	for i := range secrets {
+		s, err := oidc.NewSecret()
+		if err != nil {
+			h.HandleHTTPError(ctx, &platform.Error{Code: platform.EInternal, Err: err}, w)
+			return
+		}
+		secrets[i] = s
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	u, err := h.OIDCProvider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		h.log.Error("Failed to start oidc sign in", zap.Error(err))
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EUnavailable,
+			Msg:  "oidc provider is unavailable",
+		}, w)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookieName,
+		Value:    strings.Join(secrets, "."),
+		Path:     prefixSignInOIDC,
+		MaxAge:   int(oidcCookieTTL / time.Second),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, u, http.StatusFound)
+}
+
+// handleSigninOIDCCallback is the HTTP handler for the GET /signin/oidc/callback route,
+// the OpenID Connect provider sends users back to it once they signed in.
+// It creates a session for the user the identity of the provider maps to.
+func (h *SessionHandler) handleSigninOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	c, err := r.Cookie(oidcCookieName)
+	if err != nil {
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:   oidcCookieName,
+		Path:   prefixSignInOIDC,
+		MaxAge: -1,
+	})
+
+	secrets := strings.Split(c.Value, ".")
+	q := r.URL.Query()
+	if len(secrets) != 3 || q.Get("state") != secrets[0] {
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+	if e := q.Get("error"); e != "" {
+		h.log.Info("Oidc provider rejected sign in", zap.String("error", e), zap.String("description", q.Get("error_description")))
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	id, err := h.OIDCProvider.Exchange(ctx, q.Get("code"), secrets[1], secrets[2])
+	if err != nil {
+		h.log.Info("Failed oidc sign in", zap.Error(err))
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	u, err := h.oidcUser(ctx, id)
+	if err != nil {
+		h.log.Info("Failed to map oidc identity to a user", zap.String("username", id.Username), zap.Error(err))
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+	if u.Status == platform.Inactive {
+		InactiveUserError(ctx, h, w)
+		return
+	}
+
+	if err := h.syncOIDCOrgs(ctx, u, id.Orgs); err != nil {
+		h.log.Error("Failed to add oidc user to organizations", zap.String("username", u.Name), zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	s, err := h.SessionService.CreateSession(ctx, u.Name)
+	if err != nil {
+		UnauthorizedError(ctx, h, w)
+		return
+	}
+
+	encodeCookieSession(w, s)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// oidcUser returns the user named by the identity, creating it on its first sign in.
+// Users created otherwise, or signed in with another subject, cannot sign in with the provider.
+func (h *SessionHandler) oidcUser(ctx context.Context, id *oidc.Identity) (*platform.User, error) {
+	u, err := h.UserService.FindUser(ctx, platform.UserFilter{Name: &id.Username})
+	if err != nil {
+		if platform.ErrorCode(err) != platform.ENotFound {
+			return nil, err
+		}
+		u = &platform.User{
+			Name:    id.Username,
+			OAuthID: id.Subject,
+			Status:  platform.Active,
+		}
+		if err := h.UserService.CreateUser(ctx, u); err != nil {
+			return nil, err
+		}
+		return u, nil
+	}
+
+	if u.OAuthID != id.Subject {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  "user is not linked to the oidc identity",
+		}
+	}
+	return u, nil
+}
+
+// syncOIDCOrgs makes the user a member of the existing organizations named in orgs.
+// Organizations are never created, and memberships are never removed.
+func (h *SessionHandler) syncOIDCOrgs(ctx context.Context, u *platform.User, orgs []string) error {
+	for _, name := range orgs {
+		name := name
+		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &name})
+		if err != nil {
+			if platform.ErrorCode(err) == platform.ENotFound {
+				continue
+			}
+			return err
+		}
+
+		_, n, err := h.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+			ResourceID:   o.ID,
+			ResourceType: platform.OrgsResourceType,
+			UserID:       u.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			continue
+		}
+
+		if err := h.UserResourceMappingService.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+			UserID:       u.ID,
+			UserType:     platform.Member,
+			ResourceType: platform.OrgsResourceType,
+			ResourceID:   o.ID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/oidc"
+	"github.com/influxdata/influxdb/v2/oidc/oidctest"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSessionHandler_handleSigninOIDC(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &platform.Organization{Name: "ops"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUser(ctx, &platform.User{Name: "admin", Status: platform.Active}); err != nil {
+		t.Fatal(err)
+	}
+
+	idp := oidctest.NewServer("influxd", "secret")
+	defer idp.Close()
+	provider, err := oidc.NewProvider(oidc.Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		RedirectURL:  "http://influxd.test" + prefixSignInOIDCCallback,
+	}, idp.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewSessionHandler(zaptest.NewLogger(t), &SessionBackend{
+		HTTPErrorHandler:           kithttp.ErrorHandler(0),
+		log:                        zaptest.NewLogger(t),
+		SessionService:             svc,
+		UserService:                svc,
+		OrganizationService:        svc,
+		UserResourceMappingService: svc,
+		OIDCProvider:               provider,
+	})
+
+	// signIn signs in at the provider with claims and returns the response to the callback.
+	signIn := func(t *testing.T, claims map[string]interface{}) *http.Response {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "http://influxd.test"+prefixSignInOIDC, nil))
+		res := w.Result()
+		if res.StatusCode != http.StatusFound {
+			t.Fatalf("expected a redirect to the provider, got %d", res.StatusCode)
+		}
+
+		callback, err := idp.SignIn(res.Header.Get("Location"), claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest("GET", callback, nil)
+		for _, c := range res.Cookies() {
+			r.AddCookie(c)
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	t.Run("creates the user and a session", func(t *testing.T) {
+		res := signIn(t, map[string]interface{}{
+			"sub":                "123",
+			"preferred_username": "jane",
+			"groups":             []string{"ops", "unknown"},
+		})
+		if res.StatusCode != http.StatusSeeOther {
+			t.Fatalf("expected a redirect to the ui, got %d", res.StatusCode)
+		}
+
+		var key string
+		for _, c := range res.Cookies() {
+			if c.Name == cookieSessionName {
+				key = c.Value
+			}
+		}
+		s, err := svc.FindSession(ctx, key)
+		if err != nil {
+			t.Fatalf("expected a session: %v", err)
+		}
+
+		name := "jane"
+		u, err := svc.FindUser(ctx, platform.UserFilter{Name: &name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.OAuthID != "123" || s.UserID != u.ID {
+			t.Fatalf("unexpected user %+v for session %+v", u, s)
+		}
+
+		_, n, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+			UserID:       u.ID,
+			ResourceID:   org.ID,
+			ResourceType: platform.OrgsResourceType,
+			UserType:     platform.Member,
+		})
+		if err != nil || n != 1 {
+			t.Fatalf("expected the user to be a member of the org, got %d mappings: %v", n, err)
+		}
+	})
+
+	t.Run("signs in again", func(t *testing.T) {
+		res := signIn(t, map[string]interface{}{"sub": "123", "preferred_username": "jane", "groups": []string{"ops"}})
+		if res.StatusCode != http.StatusSeeOther {
+			t.Fatalf("expected a redirect to the ui, got %d", res.StatusCode)
+		}
+	})
+
+	t.Run("rejects users not linked to the subject", func(t *testing.T) {
+		for _, claims := range []map[string]interface{}{
+			{"sub": "456", "preferred_username": "jane"},
+			{"sub": "789", "preferred_username": "admin"},
+		} {
+			if res := signIn(t, claims); res.StatusCode != http.StatusUnauthorized {
+				t.Errorf("expected %v to be unauthorized, got %d", claims, res.StatusCode)
+			}
+		}
+	})
+
+	t.Run("rejects callbacks without a sign in", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "http://influxd.test"+prefixSignInOIDCCallback+"?code=code-1&state=abc", nil))
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected unauthorized, got %d", w.Code)
+		}
+	})
+}
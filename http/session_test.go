@@ -4,11 +4,18 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	platform "github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/kv"
 	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -102,3 +109,63 @@ func TestSessionHandler_handleSignin(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionHandler_handleSigninLockoutAndMFA(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore(), kv.ServiceConfig{
+		SigninMaxFailures:     2,
+		SigninLockoutDuration: time.Minute,
+	})
+	require.NoError(t, svc.Initialize(ctx))
+
+	user := &platform.User{Name: "user", Status: platform.Active}
+	require.NoError(t, svc.CreateUser(ctx, user))
+	require.NoError(t, svc.SetPassword(ctx, user.ID, "supersecret"))
+
+	h := NewSessionHandler(zaptest.NewLogger(t), &SessionBackend{
+		log:                  zaptest.NewLogger(t),
+		HTTPErrorHandler:     kithttp.ErrorHandler(0),
+		SessionService:       svc,
+		PasswordsService:     svc,
+		UserService:          svc,
+		SigninLockoutService: svc,
+		MFAService:           svc,
+	})
+
+	signin := func(password, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "http://localhost:9999/api/v2/signin", strings.NewReader(body))
+		r.SetBasicAuth(user.Name, password)
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("locks out after too many failures", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, signin("wrong", "").Code)
+
+		w := signin("wrong", "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+		// the correct password is refused while locked out
+		assert.Equal(t, http.StatusTooManyRequests, signin("supersecret", "").Code)
+
+		require.NoError(t, svc.ResetSigninFailures(ctx, user.ID))
+		assert.Equal(t, http.StatusNoContent, signin("supersecret", "").Code)
+	})
+
+	t.Run("requires the second factor once enabled", func(t *testing.T) {
+		e, err := svc.EnrollMFA(ctx, user.ID, "")
+		require.NoError(t, err)
+		code, err := totp.Code(e.Secret, totp.Step(time.Now()))
+		require.NoError(t, err)
+		require.NoError(t, svc.ConfirmMFA(ctx, user.ID, code))
+
+		assert.Equal(t, http.StatusUnauthorized, signin("supersecret", "").Code)
+		assert.Equal(t, http.StatusUnauthorized, signin("supersecret", `{"code":"000000"}`).Code)
+
+		w := signin("supersecret", `{"code":"`+e.RecoveryCodes[0]+`"}`)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Set-Cookie"))
+	})
+}
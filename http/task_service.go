@@ -163,13 +163,20 @@ type Task struct {
 	Flux            string                 `json:"flux"`
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
+	Location        string                 `json:"location,omitempty"`
 	Offset          string                 `json:"offset,omitempty"`
 	LatestCompleted string                 `json:"latestCompleted,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
 	LastRunError    string                 `json:"lastRunError,omitempty"`
+	Alert           *influxdb.TaskAlert    `json:"alert,omitempty"`
 	CreatedAt       string                 `json:"createdAt,omitempty"`
 	UpdatedAt       string                 `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+
+	// ConsecutiveFailures is the number of runs that failed since the last successful run.
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+	// TemplateID is the task the task was created from, if any.
+	TemplateID influxdb.ID `json:"templateID,omitempty"`
 }
 
 type taskResponse struct {
@@ -208,13 +215,18 @@ func NewFrontEndTask(t influxdb.Task) Task {
 		Flux:            t.Flux,
 		Every:           t.Every,
 		Cron:            t.Cron,
+		Location:        t.Location,
 		Offset:          offset,
 		LatestCompleted: latestCompleted,
 		LastRunStatus:   t.LastRunStatus,
 		LastRunError:    t.LastRunError,
+		Alert:           t.Alert,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Metadata:        t.Metadata,
+
+		ConsecutiveFailures: t.ConsecutiveFailures,
+		TemplateID:          t.TemplateID,
 	}
 }
 
@@ -342,6 +354,9 @@ type httpRun struct {
 	FinishedAt   *time.Time     `json:"finishedAt,omitempty"`
 	RequestedAt  *time.Time     `json:"requestedAt,omitempty"`
 	Log          []influxdb.Log `json:"log,omitempty"`
+
+	Output     []influxdb.RunOutput    `json:"output,omitempty"`
+	Statistics *influxdb.RunStatistics `json:"statistics,omitempty"`
 }
 
 func newRunResponse(r influxdb.Run) runResponse {
@@ -351,6 +366,8 @@ func newRunResponse(r influxdb.Run) runResponse {
 		Status:       r.Status,
 		Log:          r.Log,
 		ScheduledFor: &r.ScheduledFor,
+		Output:       r.Output,
+		Statistics:   r.Statistics,
 	}
 
 	if !r.StartedAt.IsZero() {
@@ -376,10 +393,12 @@ func newRunResponse(r influxdb.Run) runResponse {
 
 func convertRun(r httpRun) *influxdb.Run {
 	run := &influxdb.Run{
-		ID:     r.ID,
-		TaskID: r.TaskID,
-		Status: r.Status,
-		Log:    r.Log,
+		ID:         r.ID,
+		TaskID:     r.TaskID,
+		Status:     r.Status,
+		Log:        r.Log,
+		Output:     r.Output,
+		Statistics: r.Statistics,
 	}
 
 	if r.StartedAt != nil {
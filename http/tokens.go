@@ -9,6 +9,9 @@ import (
 
 const tokenScheme = "Token " // TODO(goller): I'd like this to be Bearer
 
+// shareScheme is the scheme of the tokens of dashboard share links.
+const shareScheme = "Share "
+
 // errors
 var (
 	ErrAuthHeaderMissing = errors.New("authorization Header is missing")
@@ -27,6 +30,18 @@ func GetToken(r *http.Request) (string, error) {
 	return header[len(tokenScheme):], nil
 }
 
+// GetShareToken will parse the token of a dashboard share link from http Authorization Header.
+func GetShareToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", ErrAuthHeaderMissing
+	}
+	if !strings.HasPrefix(header, shareScheme) {
+		return "", ErrAuthBadScheme
+	}
+	return header[len(shareScheme):], nil
+}
+
 // SetToken adds the token to the request.
 func SetToken(token string, req *http.Request) {
 	req.Header.Set("Authorization", fmt.Sprintf("%s%s", tokenScheme, token))
@@ -20,14 +20,19 @@ type UsageHandler struct {
 	UsageService platform.UsageService
 }
 
+const (
+	prefixUsage = "/api/v2/usage"
+)
+
 // NewUsageHandler returns a new instance of UsageHandler.
 func NewUsageHandler(log *zap.Logger, he platform.HTTPErrorHandler) *UsageHandler {
 	h := &UsageHandler{
-		Router: NewRouter(he),
-		log:    log,
+		Router:           NewRouter(he),
+		HTTPErrorHandler: he,
+		log:              log,
 	}
 
-	h.HandlerFunc("GET", "/api/v2/usage", h.handleGetUsage)
+	h.HandlerFunc("GET", prefixUsage, h.handleGetUsage)
 	return h
 }
 
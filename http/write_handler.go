@@ -115,6 +115,7 @@ func (*WriteHandler) Prefix() string {
 
 const (
 	prefixWrite          = "/api/v2/write"
+	walDurabilityHeader  = "X-Influxdb-Wal-Durability"
 	errInvalidGzipHeader = "gzipped HTTP body contains an invalid header"
 	errInvalidPrecision  = "invalid precision; valid precision units are ns, us, ms, and s"
 )
@@ -301,12 +302,16 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+	var report storage.WriteReport
+	if err := h.PointsWriter.WritePoints(storage.WithWriteReport(ctx, &report), points); err != nil {
 		log.Error("Error writing points", zap.Error(err))
 		handleError(err, influxdb.EInternal, "unexpected error writing points to database")
 		return
 	}
 
+	if report.Reported {
+		w.Header().Set(walDurabilityHeader, report.Durability.String())
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
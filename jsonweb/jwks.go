@@ -0,0 +1,305 @@
+package jsonweb
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// PublicKeyStore is a KeyStore which also holds the public keys
+// of asymmetric (RS256 and ES256) signatures, accessed via an id
+type PublicKeyStore interface {
+	KeyStore
+	PublicKey(string) (crypto.PublicKey, error)
+}
+
+const (
+	// DefaultJWKSRefreshInterval is how often a JWKSKeyStore reloads its key set by default.
+	DefaultJWKSRefreshInterval = time.Hour
+	// jwksMinRefreshInterval limits how often an unknown key id reloads the key set.
+	jwksMinRefreshInterval = time.Minute
+)
+
+// JWKSKeyStore is a PublicKeyStore backed by a JSON Web Key Set, read from a file or a URL.
+// The key set is cached and reloaded every refresh interval, as well as when a token
+// is signed with a key it does not hold yet, so keys can be rotated at the source.
+type JWKSKeyStore struct {
+	source          string
+	client          *http.Client
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	// loads makes concurrent callers share a single fetch of the key set,
+	// which is made without holding mu.
+	loads singleflight.Group
+
+	mu          sync.Mutex
+	keys        map[string]interface{}
+	loadedAt    time.Time
+	attemptedAt time.Time
+}
+
+// JWKSOption configures a JWKSKeyStore.
+type JWKSOption func(*JWKSKeyStore)
+
+// WithJWKSRefreshInterval sets how often the key set is reloaded.
+func WithJWKSRefreshInterval(d time.Duration) JWKSOption {
+	return func(s *JWKSKeyStore) {
+		s.refreshInterval = d
+	}
+}
+
+// WithJWKSHTTPClient sets the client used to fetch a key set from a URL.
+func WithJWKSHTTPClient(c *http.Client) JWKSOption {
+	return func(s *JWKSKeyStore) {
+		s.client = c
+	}
+}
+
+// NewJWKSKeyStore returns a JWKSKeyStore reading the key set from source,
+// which is either an http(s) URL or the path of a file.
+func NewJWKSKeyStore(source string, opts ...JWKSOption) *JWKSKeyStore {
+	s := &JWKSKeyStore{
+		source:          source,
+		client:          &http.Client{Timeout: 30 * time.Second},
+		refreshInterval: DefaultJWKSRefreshInterval,
+		now:             time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Key returns the HMAC secret with the provided key ID.
+func (s *JWKSKeyStore) Key(kid string) ([]byte, error) {
+	k, err := s.key(kid)
+	if err != nil {
+		return nil, err
+	}
+	secret, ok := k.([]byte)
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return secret, nil
+}
+
+// PublicKey returns the RSA or ECDSA public key with the provided key ID.
+func (s *JWKSKeyStore) PublicKey(kid string) (crypto.PublicKey, error) {
+	k, err := s.key(kid)
+	if err != nil {
+		return nil, err
+	}
+	switch k := k.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return k, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// Load reloads the key set from its source. Concurrent calls share a single
+// reload.
+func (s *JWKSKeyStore) Load() error {
+	_, err, _ := s.loads.Do(s.source, func() (interface{}, error) {
+		return nil, s.load()
+	})
+	return err
+}
+
+func (s *JWKSKeyStore) key(kid string) (interface{}, error) {
+	s.mu.Lock()
+	now := s.now()
+	keys, stale := s.keys, s.keys == nil || now.Sub(s.loadedAt) >= s.refreshInterval
+	s.mu.Unlock()
+
+	if stale {
+		// keep using the cached keys if the source is unavailable.
+		if err := s.Load(); err != nil && keys == nil {
+			return nil, err
+		}
+		keys = s.cachedKeys()
+	}
+	if k, ok := keys[kid]; ok {
+		return k, nil
+	}
+
+	// the key may have been added to the source since the key set was loaded.
+	s.mu.Lock()
+	attempted := now.Sub(s.attemptedAt) < jwksMinRefreshInterval
+	s.mu.Unlock()
+	if attempted {
+		return nil, ErrKeyNotFound
+	}
+	if err := s.Load(); err != nil {
+		return nil, err
+	}
+	if k, ok := s.cachedKeys()[kid]; ok {
+		return k, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// cachedKeys returns the key set last loaded. The map is replaced, never
+// modified, on reloads.
+func (s *JWKSKeyStore) cachedKeys() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys
+}
+
+// load fetches the key set without holding s.mu, then swaps it in.
+func (s *JWKSKeyStore) load() error {
+	s.mu.Lock()
+	attemptedAt := s.now()
+	s.attemptedAt = attemptedAt
+	s.mu.Unlock()
+
+	b, err := s.read()
+	if err != nil {
+		return fmt.Errorf("failed to read key set %s: %v", s.source, err)
+	}
+	keys, err := ParseJWKS(b)
+	if err != nil {
+		return fmt.Errorf("failed to parse key set %s: %v", s.source, err)
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.loadedAt = attemptedAt
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *JWKSKeyStore) read() ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return ioutil.ReadFile(s.source)
+	}
+
+	resp, err := s.client.Get(s.source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC keys
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	// symmetric keys
+	K string `json:"k"`
+}
+
+// ParseJWKS returns the signature keys of a JSON Web Key Set by key ID.
+// RSA and EC keys are returned as *rsa.PublicKey and *ecdsa.PublicKey, symmetric keys as []byte.
+// Encryption keys, and keys of other types, are skipped.
+func ParseJWKS(b []byte) (map[string]interface{}, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		var (
+			key interface{}
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			key, err = k.rsaKey()
+		case "EC":
+			key, err = k.ecKey()
+		case "oct":
+			key, err = base64.RawURLEncoding.DecodeString(k.K)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %v", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+func (k jwk) rsaKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeBigInt(k.E)
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsInt64() || e.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k jwk) ecKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+	x, err := decodeBigInt(k.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeBigInt(k.Y)
+	if err != nil {
+		return nil, err
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("point is not on curve %s", k.Crv)
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("missing key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,354 @@
+package jsonweb
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/influxdata/influxdb/v2"
+)
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func rsaJWK(kid string, k *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"n":   encodeBigInt(k.N),
+		"e":   encodeBigInt(big.NewInt(int64(k.E))),
+	}
+}
+
+func ecJWK(kid string, k *ecdsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "EC",
+		"kid": kid,
+		"crv": "P-256",
+		"x":   encodeBigInt(k.X),
+		"y":   encodeBigInt(k.Y),
+	}
+}
+
+// jwksServer serves a key set which can be replaced while it runs.
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	keys     []map[string]string
+	requests int
+	// hold, if set, is sent on when a request arrives, which then waits to
+	// receive from it.
+	hold chan struct{}
+}
+
+func newJWKSServer(keys ...map[string]string) *jwksServer {
+	s := &jwksServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		hold := s.hold
+		s.mu.Unlock()
+		if hold != nil {
+			hold <- struct{}{}
+			<-hold
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests++
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+	}))
+	return s
+}
+
+func (s *jwksServer) setKeys(keys ...map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func Test_TokenParser_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := newJWKSServer(
+		rsaJWK("rsa-1", &rsaKey.PublicKey),
+		ecJWK("ec-1", &ecKey.PublicKey),
+		map[string]string{"kty": "oct", "kid": "hmac-1", "k": base64.RawURLEncoding.EncodeToString([]byte("secret"))},
+	)
+	defer srv.Close()
+
+	parser := NewTokenParser(NewJWKSKeyStore(srv.URL), WithIssuer("gateway"), WithAudience("influxd"))
+
+	claims := func(extra jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":   "gateway",
+			"aud":   "influxd",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"scope": "read:orgs/0000000000000001/buckets write:orgs/0000000000000001/buckets/0000000000000002",
+		}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+
+	t.Run("RS256", func(t *testing.T) {
+		token, err := parser.Parse(signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(nil)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		org, bucket := influxdb.ID(1), influxdb.ID(2)
+		if !token.Allowed(influxdb.Permission{
+			Action:   influxdb.WriteAction,
+			Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &org, ID: &bucket},
+		}) {
+			t.Error("expected the scope to allow writing to the bucket")
+		}
+		if token.Allowed(influxdb.Permission{
+			Action:   influxdb.WriteAction,
+			Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &org},
+		}) {
+			t.Error("expected the scope not to allow writing to all buckets")
+		}
+	})
+
+	t.Run("ES256", func(t *testing.T) {
+		if _, err := parser.Parse(signToken(t, jwt.SigningMethodES256, "ec-1", ecKey, claims(nil))); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("HS256", func(t *testing.T) {
+		if _, err := parser.Parse(signToken(t, jwt.SigningMethodHS256, "hmac-1", []byte("secret"), claims(nil))); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	for _, tt := range []struct {
+		name  string
+		token func(t *testing.T) string
+	}{
+		{
+			name: "wrong issuer",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(jwt.MapClaims{"iss": "other"}))
+			},
+		},
+		{
+			name: "wrong audience",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(jwt.MapClaims{"aud": "other"}))
+			},
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}))
+			},
+		},
+		{
+			name: "invalid scope",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(jwt.MapClaims{"scope": "read:widgets"}))
+			},
+		},
+		{
+			name: "signed by another key",
+			token: func(t *testing.T) string {
+				return signToken(t, jwt.SigningMethodES256, "rsa-1", ecKey, claims(nil))
+			},
+		},
+		{
+			name: "public key used as hmac secret",
+			token: func(t *testing.T) string {
+				secret := []byte(rsaJWK("rsa-1", &rsaKey.PublicKey)["n"])
+				return signToken(t, jwt.SigningMethodHS256, "rsa-1", secret, claims(nil))
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.Parse(tt.token(t))
+			if err == nil {
+				t.Fatal("expected the token to be rejected")
+			}
+			if IsMalformedError(err) {
+				t.Fatalf("expected a validation error, got a malformed error: %v", err)
+			}
+		})
+	}
+}
+
+func Test_JWKSKeyStore_Rotation(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := newJWKSServer(ecJWK("old", &oldKey.PublicKey))
+	defer srv.Close()
+
+	now := time.Now()
+	store := NewJWKSKeyStore(srv.URL, WithJWKSRefreshInterval(10*time.Minute))
+	store.now = func() time.Time { return now }
+
+	if _, err := store.PublicKey("old"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.PublicKey("old"); err != nil {
+		t.Fatal(err)
+	}
+	if srv.requests != 1 {
+		t.Fatalf("expected the key set to be cached, got %d requests", srv.requests)
+	}
+
+	srv.setKeys(ecJWK("old", &oldKey.PublicKey), ecJWK("new", &newKey.PublicKey))
+
+	// unknown keys reload the key set at most once a minute.
+	if _, err := store.PublicKey("new"); err != ErrKeyNotFound {
+		t.Fatalf("expected key not found, got %v", err)
+	}
+	now = now.Add(jwksMinRefreshInterval)
+	if _, err := store.PublicKey("new"); err != nil {
+		t.Fatal(err)
+	}
+
+	// removed keys are dropped once the refresh interval passed.
+	srv.setKeys(ecJWK("new", &newKey.PublicKey))
+	now = now.Add(10 * time.Minute)
+	if _, err := store.PublicKey("old"); err != ErrKeyNotFound {
+		t.Fatalf("expected key not found, got %v", err)
+	}
+
+	// cached keys are used while the source is unavailable.
+	srv.Close()
+	now = now.Add(10 * time.Minute)
+	if _, err := store.PublicKey("new"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_JWKSKeyStore_FetchWithoutLock(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := newJWKSServer(ecJWK("known", &key.PublicKey))
+	defer srv.Close()
+
+	now := time.Now()
+	store := NewJWKSKeyStore(srv.URL)
+	store.now = func() time.Time { return now }
+	if _, err := store.PublicKey("known"); err != nil {
+		t.Fatal(err)
+	}
+
+	// an unknown key reloads the key set, which is held by the server.
+	hold := make(chan struct{})
+	srv.mu.Lock()
+	srv.hold = hold
+	srv.mu.Unlock()
+	now = now.Add(jwksMinRefreshInterval)
+	done := make(chan error, 1)
+	go func() {
+		_, err := store.PublicKey("unknown")
+		done <- err
+	}()
+	<-hold
+
+	// cached keys are served while the key set is fetched.
+	if _, err := store.PublicKey("known"); err != nil {
+		t.Fatal(err)
+	}
+	close(hold)
+	if err := <-done; err != ErrKeyNotFound {
+		t.Fatalf("expected key not found, got %v", err)
+	}
+}
+
+func Test_JWKSKeyStore_File(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "jwks.json")
+	b, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			rsaJWK("rsa-1", &key.PublicKey),
+			{"kty": "RSA", "kid": "enc-1", "use": "enc"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewJWKSKeyStore(path)
+	if err := store.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := store.PublicKey("rsa-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.(*rsa.PublicKey).N.Cmp(key.N) != 0 {
+		t.Fatal("unexpected public key")
+	}
+	if _, err := store.PublicKey("enc-1"); err != ErrKeyNotFound {
+		t.Fatalf("expected encryption keys to be skipped, got %v", err)
+	}
+	if _, err := store.Key("rsa-1"); err != ErrKeyNotFound {
+		t.Fatalf("expected public keys not to be used as secrets, got %v", err)
+	}
+
+	if err := NewJWKSKeyStore(filepath.Join(dir, "missing.json")).Load(); err == nil {
+		t.Fatal("expected loading a missing file to fail")
+	}
+}
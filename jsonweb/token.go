@@ -2,6 +2,7 @@ package jsonweb
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/influxdata/influxdb/v2"
@@ -36,44 +37,117 @@ func (k KeyStoreFunc) Key(v string) ([]byte, error) { return k(v) }
 type TokenParser struct {
 	keyStore KeyStore
 	parser   *jwt.Parser
+	issuer   string
+	audience string
+}
+
+// TokenParserOption configures a TokenParser.
+type TokenParserOption func(*TokenParser)
+
+// WithIssuer makes the parser reject tokens not issued by iss.
+func WithIssuer(iss string) TokenParserOption {
+	return func(t *TokenParser) {
+		t.issuer = iss
+	}
+}
+
+// WithAudience makes the parser reject tokens not intended for aud.
+func WithAudience(aud string) TokenParserOption {
+	return func(t *TokenParser) {
+		t.audience = aud
+	}
 }
 
 // NewTokenParser returns a configured token parser used to
-// parse Token types from strings
-func NewTokenParser(keyStore KeyStore) *TokenParser {
-	return &TokenParser{
+// parse Token types from strings.
+// Tokens are signed with HS256, or when the key store is a
+// PublicKeyStore, with RS256 or ES256 as well.
+func NewTokenParser(keyStore KeyStore, opts ...TokenParserOption) *TokenParser {
+	methods := []string{jwt.SigningMethodHS256.Alg()}
+	if _, ok := keyStore.(PublicKeyStore); ok {
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	t := &TokenParser{
 		keyStore: keyStore,
 		parser: &jwt.Parser{
-			ValidMethods: []string{jwt.SigningMethodHS256.Alg()},
+			ValidMethods: methods,
 		},
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
 // Parse takes a string then parses and validates it as a jwt based on
 // the key described within the token
 func (t *TokenParser) Parse(v string) (*Token, error) {
-	jwt, err := t.parser.ParseWithClaims(v, &Token{}, func(jwt *jwt.Token) (interface{}, error) {
-		token, ok := jwt.Claims.(*Token)
-		if !ok {
-			return nil, errors.New("missing kid in token claims")
-		}
-
-		// fetch key for "kid" from key store
-		return t.keyStore.Key(token.KeyID)
-	})
-
+	parsed, err := t.parser.ParseWithClaims(v, &Token{}, t.key)
 	if err != nil {
 		return nil, err
 	}
 
-	token, ok := jwt.Claims.(*Token)
+	token, ok := parsed.Claims.(*Token)
 	if !ok {
 		return nil, errors.New("token is unexpected type")
 	}
 
+	if t.issuer != "" && !token.VerifyIssuer(t.issuer, true) {
+		return nil, &jwt.ValidationError{
+			Inner:  errors.New("token has an unexpected issuer"),
+			Errors: jwt.ValidationErrorIssuer,
+		}
+	}
+
+	if t.audience != "" && !token.VerifyAudience(t.audience, true) {
+		return nil, &jwt.ValidationError{
+			Inner:  errors.New("token has an unexpected audience"),
+			Errors: jwt.ValidationErrorAudience,
+		}
+	}
+
+	// permissions may also be granted by a space separated
+	// list of permission strings in the "scope" claim.
+	for _, s := range strings.Fields(token.Scope) {
+		p, err := influxdb.ParsePermission(s)
+		if err != nil {
+			return nil, &jwt.ValidationError{
+				Inner:  err,
+				Errors: jwt.ValidationErrorClaimsInvalid,
+			}
+		}
+		token.Permissions = append(token.Permissions, *p)
+	}
+
 	return token, nil
 }
 
+// key returns the key a token is verified with, found by the "kid"
+// header, or the "kid" claim, and the signing method of the token.
+func (t *TokenParser) key(parsed *jwt.Token) (interface{}, error) {
+	kid, _ := parsed.Header["kid"].(string)
+	if kid == "" {
+		token, ok := parsed.Claims.(*Token)
+		if !ok {
+			return nil, errors.New("missing kid in token claims")
+		}
+		kid = token.KeyID
+	}
+
+	switch parsed.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		pks, ok := t.keyStore.(PublicKeyStore)
+		if !ok {
+			return nil, ErrKeyNotFound
+		}
+		return pks.PublicKey(kid)
+	}
+
+	// fetch key for "kid" from key store
+	return t.keyStore.Key(kid)
+}
+
 // IsMalformedError returns true if the error returned represents
 // a jwt malformed token error
 func IsMalformedError(err error) bool {
@@ -91,6 +165,9 @@ type Token struct {
 	Permissions []influxdb.Permission `json:"permissions"`
 	// UserID for the token
 	UserID string `json:"uid,omitempty"`
+	// Scope is a space separated list of permissions, such as
+	// "read:orgs/0000000000000001/buckets", added to Permissions
+	Scope string `json:"scope,omitempty"`
 }
 
 // Allowed returns whether or not a permission is allowed based
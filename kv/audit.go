@@ -0,0 +1,189 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var (
+	auditLogBucket = []byte("auditlogv1")
+)
+
+var _ influxdb.AuditLogService = (*Service)(nil)
+
+// createAuditLogMigration creates the bucket of the audit log.
+func createAuditLogMigration() MigrationSpec {
+	return NewAnonymousMigration(
+		"create audit log bucket",
+		func(ctx context.Context, store Store) error {
+			return store.Update(ctx, func(tx Tx) error {
+				_, err := tx.Bucket(auditLogBucket)
+				return err
+			})
+		},
+		// down is a noop, the bucket is left in place
+		func(context.Context, Store) error {
+			return nil
+		},
+	)
+}
+
+// auditEventKey orders events by the time they were recorded, the id
+// distinguishes events recorded at the same time.
+func auditEventKey(e *influxdb.AuditEvent) ([]byte, error) {
+	id, err := e.ID.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return append(auditTimeKey(e.Time), id...), nil
+}
+
+func auditTimeKey(t time.Time) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(t.UnixNano()))
+	return k
+}
+
+// RecordAuditEvent appends the event to the audit log, setting its ID and time.
+// Events cannot be changed or removed once recorded.
+func (s *Service) RecordAuditEvent(ctx context.Context, e *influxdb.AuditEvent) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		e.ID = s.IDGenerator.ID()
+		e.Time = s.Now().UTC()
+
+		k, err := auditEventKey(e)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   influxdb.OpRecordAuditEvent,
+				Err:  err,
+			}
+		}
+
+		v, err := json.Marshal(e)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   influxdb.OpRecordAuditEvent,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(auditLogBucket)
+		if err != nil {
+			return err
+		}
+		if err := b.Put(k, v); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   influxdb.OpRecordAuditEvent,
+				Err:  err,
+			}
+		}
+		return nil
+	})
+}
+
+// FindAuditEvents returns the events of the audit log matching the filter.
+func (s *Service) FindAuditEvents(ctx context.Context, filter influxdb.AuditEventFilter, opt ...influxdb.FindOptions) ([]*influxdb.AuditEvent, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var o influxdb.FindOptions
+	if len(opt) > 0 {
+		o = opt[0]
+	}
+
+	events := []*influxdb.AuditEvent{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(auditLogBucket)
+		if err != nil {
+			return err
+		}
+
+		var (
+			seek []byte
+			opts []CursorOption
+		)
+		if o.Descending {
+			opts = append(opts, WithCursorDirection(CursorDescending))
+		} else if filter.Since != nil {
+			seek = auditTimeKey(*filter.Since)
+		}
+
+		cur, err := b.ForwardCursor(seek, opts...)
+		if err != nil {
+			return err
+		}
+		defer cur.Close()
+
+		var seen int
+		for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+			e := &influxdb.AuditEvent{}
+			if err := json.Unmarshal(v, e); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Op:   influxdb.OpFindAuditEvents,
+					Err:  err,
+				}
+			}
+
+			if filter.Since != nil && e.Time.Before(*filter.Since) {
+				if o.Descending {
+					break
+				}
+				continue
+			}
+			if filter.Until != nil && !e.Time.Before(*filter.Until) {
+				if o.Descending {
+					continue
+				}
+				break
+			}
+			if !auditEventMatches(filter, e) {
+				continue
+			}
+
+			seen++
+			if seen <= o.Offset {
+				continue
+			}
+			events = append(events, e)
+			if o.Limit > 0 && len(events) >= o.Limit {
+				break
+			}
+		}
+		return cur.Err()
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, len(events), nil
+}
+
+func auditEventMatches(filter influxdb.AuditEventFilter, e *influxdb.AuditEvent) bool {
+	if filter.OrgID != nil && e.OrgID != *filter.OrgID {
+		return false
+	}
+	if filter.UserID != nil && e.UserID != *filter.UserID {
+		return false
+	}
+	if filter.ResourceType != nil && e.ResourceType != *filter.ResourceType {
+		return false
+	}
+	if filter.ResourceID != nil && e.ResourceID != *filter.ResourceID {
+		return false
+	}
+	if filter.Action != nil && e.Action != *filter.Action {
+		return false
+	}
+	return true
+}
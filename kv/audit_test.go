@@ -0,0 +1,100 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// stepTimeGenerator returns a time a second later than the last on each call.
+type stepTimeGenerator struct {
+	now time.Time
+}
+
+func (g *stepTimeGenerator) Now() time.Time {
+	g.now = g.now.Add(time.Second)
+	return g.now
+}
+
+func TestService_AuditLog(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	require.NoError(t, err)
+	defer closeStore()
+
+	start := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+	svc.TimeGenerator = &stepTimeGenerator{now: start}
+
+	ctx := context.Background()
+	require.NoError(t, svc.Initialize(ctx))
+
+	orgID, otherOrgID := influxdb.ID(1), influxdb.ID(2)
+	events := []*influxdb.AuditEvent{
+		{Action: influxdb.AuditActionCreate, ResourceType: influxdb.BucketsResourceType, ResourceID: 10, OrgID: orgID, After: []byte(`{"name":"b1"}`)},
+		{Action: influxdb.AuditActionUpdate, ResourceType: influxdb.BucketsResourceType, ResourceID: 10, OrgID: orgID, Before: []byte(`{"name":"b1"}`), After: []byte(`{"name":"b2"}`)},
+		{Action: influxdb.AuditActionCreate, ResourceType: influxdb.DashboardsResourceType, ResourceID: 20, OrgID: otherOrgID},
+		{Action: influxdb.AuditActionDelete, ResourceType: influxdb.BucketsResourceType, ResourceID: 10, OrgID: orgID, Before: []byte(`{"name":"b2"}`)},
+	}
+	for _, e := range events {
+		require.NoError(t, svc.RecordAuditEvent(ctx, e))
+		require.True(t, e.ID.Valid())
+	}
+
+	ids := func(es []*influxdb.AuditEvent) []influxdb.ID {
+		var out []influxdb.ID
+		for _, e := range es {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+
+	t.Run("all events in the order recorded", func(t *testing.T) {
+		found, n, err := svc.FindAuditEvents(ctx, influxdb.AuditEventFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, 4, n)
+		assert.Equal(t, ids(events), ids(found))
+		assert.Equal(t, events[1].Before, found[1].Before)
+		assert.Equal(t, events[1].After, found[1].After)
+	})
+
+	t.Run("descending with offset and limit", func(t *testing.T) {
+		found, _, err := svc.FindAuditEvents(ctx, influxdb.AuditEventFilter{}, influxdb.FindOptions{
+			Descending: true,
+			Offset:     1,
+			Limit:      2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []influxdb.ID{events[2].ID, events[1].ID}, ids(found))
+	})
+
+	t.Run("by org and resource", func(t *testing.T) {
+		rt := influxdb.BucketsResourceType
+		found, _, err := svc.FindAuditEvents(ctx, influxdb.AuditEventFilter{OrgID: &orgID, ResourceType: &rt})
+		require.NoError(t, err)
+		assert.Equal(t, []influxdb.ID{events[0].ID, events[1].ID, events[3].ID}, ids(found))
+
+		action := influxdb.AuditActionDelete
+		found, _, err = svc.FindAuditEvents(ctx, influxdb.AuditEventFilter{Action: &action})
+		require.NoError(t, err)
+		assert.Equal(t, []influxdb.ID{events[3].ID}, ids(found))
+	})
+
+	t.Run("within a time range", func(t *testing.T) {
+		since, until := start.Add(2*time.Second), start.Add(4*time.Second)
+		filter := influxdb.AuditEventFilter{Since: &since, Until: &until}
+
+		found, _, err := svc.FindAuditEvents(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, []influxdb.ID{events[1].ID, events[2].ID}, ids(found))
+
+		found, _, err = svc.FindAuditEvents(ctx, filter, influxdb.FindOptions{Descending: true})
+		require.NoError(t, err)
+		assert.Equal(t, []influxdb.ID{events[2].ID, events[1].ID}, ids(found))
+	})
+}
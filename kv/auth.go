@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
-	"github.com/buger/jsonparser"
 	influxdb "github.com/influxdata/influxdb/v2"
 	jsonp "github.com/influxdata/influxdb/v2/pkg/jsonparser"
 )
@@ -15,7 +15,10 @@ var (
 	authIndex  = []byte("authorizationindexv1")
 )
 
-var _ influxdb.AuthorizationService = (*Service)(nil)
+var (
+	_ influxdb.AuthorizationService       = (*Service)(nil)
+	_ influxdb.AuthorizationUsageRecorder = (*Service)(nil)
+)
 
 func (s *Service) initializeAuths(ctx context.Context, tx Tx) error {
 	if _, err := tx.Bucket(authBucket); err != nil {
@@ -48,6 +51,14 @@ func (s *Service) FindAuthorizationByID(ctx context.Context, id influxdb.ID) (*i
 }
 
 func (s *Service) findAuthorizationByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Authorization, error) {
+	sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	return sa.Authorization, nil
+}
+
+func (s *Service) findStoredAuthorizationByID(ctx context.Context, tx Tx, id influxdb.ID) (*storedAuthorization, error) {
 	encodedID, err := id.Encode()
 	if err != nil {
 		return nil, &influxdb.Error{
@@ -73,15 +84,18 @@ func (s *Service) findAuthorizationByID(ctx context.Context, tx Tx, id influxdb.
 		return nil, err
 	}
 
-	a := &influxdb.Authorization{}
-	if err := decodeAuthorization(v, a); err != nil {
+	sa := &storedAuthorization{Authorization: &influxdb.Authorization{}}
+	if err := decodeStoredAuthorization(v, sa); err != nil {
 		return nil, &influxdb.Error{
 			Code: influxdb.EInvalid,
 			Err:  err,
 		}
 	}
+	if err := s.resolveRolePermissions(ctx, tx, sa.Authorization); err != nil {
+		return nil, err
+	}
 
-	return a, nil
+	return sa, nil
 }
 
 // FindAuthorizationByToken returns a authorization by token for a particular authorization.
@@ -106,27 +120,34 @@ func (s *Service) FindAuthorizationByToken(ctx context.Context, n string) (*infl
 }
 
 func (s *Service) findAuthorizationByToken(ctx context.Context, tx Tx, n string) (*influxdb.Authorization, error) {
-	idx, err := authIndexBucket(tx)
+	var auth *influxdb.Authorization
+	err := s.findAuthorizationCandidates(ctx, tx, n, func(sa *storedAuthorization) bool {
+		switch {
+		case authTokenMatches(sa.TokenHash, n):
+			sa.Token = n
+		case sa.PreviousTokenHash != "" && authTokenMatches(sa.PreviousTokenHash, n):
+			// the index still holds the token replaced by a rotation until the authorization is rotated again.
+			if sa.PreviousTokenExpiresAt == nil || !s.TimeGenerator.Now().Before(*sa.PreviousTokenExpiresAt) {
+				return true
+			}
+		default:
+			return true
+		}
+		auth = sa.Authorization
+		return false
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	a, err := idx.Get(authIndexKey(n))
-	if IsNotFound(err) {
+	if auth == nil {
 		return nil, &influxdb.Error{
 			Code: influxdb.ENotFound,
 			Msg:  "authorization not found",
 		}
 	}
 
-	var id influxdb.ID
-	if err := id.Decode(a); err != nil {
-		return nil, &influxdb.Error{
-			Code: influxdb.EInvalid,
-			Err:  err,
-		}
-	}
-	return s.findAuthorizationByID(ctx, tx, id)
+	return auth, nil
 }
 
 func authorizationsPredicateFn(f influxdb.AuthorizationFilter) CursorPredicateFunc {
@@ -144,18 +165,6 @@ func authorizationsPredicateFn(f influxdb.AuthorizationFilter) CursorPredicateFu
 		}
 	}
 
-	if f.Token != nil {
-		exp := *f.Token
-		return func(_, value []byte) bool {
-			// it is assumed that token never has escaped string data
-			got, _, _, err := jsonparser.Get(value, "token")
-			if err != nil {
-				return true
-			}
-			return string(got) == exp
-		}
-	}
-
 	var pred CursorPredicateFunc
 	if f.OrgID != nil {
 		exp := *f.OrgID
@@ -189,12 +198,6 @@ func filterAuthorizationsFn(filter influxdb.AuthorizationFilter) func(a *influxd
 		}
 	}
 
-	if filter.Token != nil {
-		return func(a *influxdb.Authorization) bool {
-			return a.Token == *filter.Token
-		}
-	}
-
 	// Filter by org and user
 	if filter.OrgID != nil && filter.UserID != nil {
 		return func(a *influxdb.Authorization) bool {
@@ -323,7 +326,7 @@ func (s *Service) createAuthorization(ctx context.Context, tx Tx, a *influxdb.Au
 		return influxdb.ErrUnableToCreateToken
 	}
 
-	if err := s.uniqueAuthToken(ctx, tx, a); err != nil {
+	if err := s.validAuthorizationRoles(ctx, tx, a); err != nil {
 		return err
 	}
 
@@ -337,6 +340,10 @@ func (s *Service) createAuthorization(ctx context.Context, tx Tx, a *influxdb.Au
 		a.Token = token
 	}
 
+	if err := s.uniqueAuthToken(ctx, tx, a.Token); err != nil {
+		return err
+	}
+
 	a.ID = s.IDGenerator.ID()
 
 	now := s.TimeGenerator.Now()
@@ -357,7 +364,9 @@ func (s *Service) PutAuthorization(ctx context.Context, a *influxdb.Authorizatio
 	})
 }
 
-func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
+// encodeAuthorization encodes the authorization for storage, without its token.
+func encodeAuthorization(sa *storedAuthorization) ([]byte, error) {
+	a := sa.Authorization
 	switch a.Status {
 	case influxdb.Active, influxdb.Inactive:
 	case "":
@@ -369,11 +378,47 @@ func encodeAuthorization(a *influxdb.Authorization) ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(a)
+	stored := *sa
+	stored.LegacyToken = ""
+	return json.Marshal(stored)
 }
 
+// putAuthorization stores a with a hash of its token, replacing the token of a previously stored authorization.
 func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
-	v, err := encodeAuthorization(a)
+	if a.Token == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "authorization token is required",
+		}
+	}
+
+	prev, err := s.findStoredAuthorizationByID(ctx, tx, a.ID)
+	if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return err
+	}
+	if prev != nil {
+		if err := deleteAuthIndexKeys(tx, prev); err != nil {
+			return err
+		}
+	}
+
+	hash, err := hashAuthToken(a.Token)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	a.TokenPrefix = authTokenPrefix(a.Token)
+	a.PreviousTokenExpiresAt = nil
+
+	return s.putStoredAuthorization(ctx, tx, &storedAuthorization{
+		Authorization: a,
+		TokenHash:     hash,
+	})
+}
+
+func (s *Service) putStoredAuthorization(ctx context.Context, tx Tx, sa *storedAuthorization) error {
+	v, err := encodeAuthorization(sa)
 	if err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -381,7 +426,7 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 		}
 	}
 
-	encodedID, err := a.ID.Encode()
+	encodedID, err := sa.ID.Encode()
 	if err != nil {
 		return &influxdb.Error{
 			Code: influxdb.ENotFound,
@@ -394,12 +439,20 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 		return err
 	}
 
-	if err := idx.Put(authIndexKey(a.Token), encodedID); err != nil {
+	if err := idx.Put(authIndexKey(sa.TokenPrefix, encodedID), encodedID); err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInternal,
 			Err:  err,
 		}
 	}
+	if sa.PreviousTokenHash != "" {
+		if err := idx.Put(authIndexKey(sa.PreviousTokenPrefix, encodedID), encodedID); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+	}
 
 	b, err := tx.Bucket(authBucket)
 	if err != nil {
@@ -415,8 +468,33 @@ func (s *Service) putAuthorization(ctx context.Context, tx Tx, a *influxdb.Autho
 	return nil
 }
 
-func authIndexKey(n string) []byte {
-	return []byte(n)
+// deleteAuthIndexKeys removes the current and previous token of sa from the index.
+func deleteAuthIndexKeys(tx Tx, sa *storedAuthorization) error {
+	encodedID, err := sa.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	idx, err := authIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.Delete(authIndexKey(sa.TokenPrefix, encodedID)); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	if sa.PreviousTokenHash != "" {
+		if err := idx.Delete(authIndexKey(sa.PreviousTokenPrefix, encodedID)); err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
+	}
+	return nil
 }
 
 func decodeAuthorization(b []byte, a *influxdb.Authorization) error {
@@ -429,6 +507,16 @@ func decodeAuthorization(b []byte, a *influxdb.Authorization) error {
 	return nil
 }
 
+func decodeStoredAuthorization(b []byte, sa *storedAuthorization) error {
+	if err := json.Unmarshal(b, sa); err != nil {
+		return err
+	}
+	if sa.Status == "" {
+		sa.Status = influxdb.Active
+	}
+	return nil
+}
+
 // forEachAuthorization will iterate through all authorizations while fn returns true.
 func (s *Service) forEachAuthorization(ctx context.Context, tx Tx, pred CursorPredicateFunc, fn func(*influxdb.Authorization) bool) error {
 	b, err := tx.Bucket(authBucket)
@@ -455,6 +543,9 @@ func (s *Service) forEachAuthorization(ctx context.Context, tx Tx, pred CursorPr
 		if err := decodeAuthorization(v, a); err != nil {
 			return err
 		}
+		if err := s.resolveRolePermissions(ctx, tx, a); err != nil {
+			return err
+		}
 		if !fn(a) {
 			break
 		}
@@ -471,21 +562,15 @@ func (s *Service) DeleteAuthorization(ctx context.Context, id influxdb.ID) error
 }
 
 func (s *Service) deleteAuthorization(ctx context.Context, tx Tx, id influxdb.ID) error {
-	a, err := s.findAuthorizationByID(ctx, tx, id)
+	sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
 	if err != nil {
 		return err
 	}
 
-	idx, err := authIndexBucket(tx)
-	if err != nil {
+	if err := deleteAuthIndexKeys(tx, sa); err != nil {
 		return err
 	}
 
-	if err := idx.Delete(authIndexKey(a.Token)); err != nil {
-		return &influxdb.Error{
-			Err: err,
-		}
-	}
 	encodedID, err := id.Encode()
 	if err != nil {
 		return &influxdb.Error{
@@ -503,6 +588,42 @@ func (s *Service) deleteAuthorization(ctx context.Context, tx Tx, id influxdb.ID
 			Err: err,
 		}
 	}
+	return s.deleteAuthorizationCertificateMappings(ctx, tx, id)
+}
+
+// removeRoleFromAuthorizations removes a deleted role from the authorizations granted it.
+func (s *Service) removeRoleFromAuthorizations(ctx context.Context, tx Tx, roleID influxdb.ID) error {
+	var ids []influxdb.ID
+	err := s.forEachAuthorization(ctx, tx, nil, func(a *influxdb.Authorization) bool {
+		for _, id := range a.Roles {
+			if id == roleID {
+				ids = append(ids, a.ID)
+				break
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		roles := sa.Roles[:0]
+		for _, id := range sa.Roles {
+			if id != roleID {
+				roles = append(roles, id)
+			}
+		}
+		sa.Roles = roles
+		if err := s.putStoredAuthorization(ctx, tx, sa); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -518,10 +639,11 @@ func (s *Service) UpdateAuthorization(ctx context.Context, id influxdb.ID, upd *
 }
 
 func (s *Service) updateAuthorization(ctx context.Context, tx Tx, id influxdb.ID, upd *influxdb.AuthorizationUpdate) (*influxdb.Authorization, error) {
-	a, err := s.findAuthorizationByID(ctx, tx, id)
+	sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
 	if err != nil {
 		return nil, err
 	}
+	a := sa.Authorization
 
 	if upd.Status != nil {
 		a.Status = *upd.Status
@@ -529,17 +651,113 @@ func (s *Service) updateAuthorization(ctx context.Context, tx Tx, id influxdb.ID
 	if upd.Description != nil {
 		a.Description = *upd.Description
 	}
+	if upd.ExpiresAt != nil {
+		a.ExpiresAt = upd.ExpiresAt
+	}
+	if upd.Roles != nil {
+		a.Roles = *upd.Roles
+		if err := s.validAuthorizationRoles(ctx, tx, a); err != nil {
+			return nil, err
+		}
+		if err := s.resolveRolePermissions(ctx, tx, a); err != nil {
+			return nil, err
+		}
+	}
 
 	now := s.TimeGenerator.Now()
 	a.SetUpdatedAt(now)
 
-	if err := s.putAuthorization(ctx, tx, a); err != nil {
+	if err := s.putStoredAuthorization(ctx, tx, sa); err != nil {
 		return nil, err
 	}
 
 	return a, nil
 }
 
+// RotateAuthorization issues a new token for the authorization, the replaced token is accepted for gracePeriod.
+func (s *Service) RotateAuthorization(ctx context.Context, id influxdb.ID, gracePeriod time.Duration) (*influxdb.Authorization, error) {
+	var a *influxdb.Authorization
+	var err error
+	err = s.kv.Update(ctx, func(tx Tx) error {
+		a, err = s.rotateAuthorization(ctx, tx, id, gracePeriod)
+		return err
+	})
+	return a, err
+}
+
+func (s *Service) rotateAuthorization(ctx context.Context, tx Tx, id influxdb.ID, gracePeriod time.Duration) (*influxdb.Authorization, error) {
+	if gracePeriod < 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "grace period must not be negative",
+		}
+	}
+
+	sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := deleteAuthIndexKeys(tx, sa); err != nil {
+		return nil, err
+	}
+
+	// only the token replaced by the latest rotation is kept around.
+	now := s.TimeGenerator.Now()
+	if gracePeriod > 0 {
+		expiresAt := now.Add(gracePeriod)
+		sa.PreviousTokenPrefix = sa.TokenPrefix
+		sa.PreviousTokenHash = sa.TokenHash
+		sa.PreviousTokenExpiresAt = &expiresAt
+	} else {
+		sa.PreviousTokenPrefix = ""
+		sa.PreviousTokenHash = ""
+		sa.PreviousTokenExpiresAt = nil
+	}
+
+	token, err := s.TokenGenerator.Token()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+	if err := s.uniqueAuthToken(ctx, tx, token); err != nil {
+		return nil, err
+	}
+	hash, err := hashAuthToken(token)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+	sa.Token = token
+	sa.TokenPrefix = authTokenPrefix(token)
+	sa.TokenHash = hash
+
+	sa.SetUpdatedAt(now)
+
+	if err := s.putStoredAuthorization(ctx, tx, sa); err != nil {
+		return nil, err
+	}
+
+	return sa.Authorization, nil
+}
+
+// RecordAuthorizationUse sets the last time, and address, the authorization was used at.
+func (s *Service) RecordAuthorizationUse(ctx context.Context, id influxdb.ID, at time.Time, ip string) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		sa.LastUsedAt = &at
+		sa.LastUsedIP = ip
+
+		return s.putStoredAuthorization(ctx, tx, sa)
+	})
+}
+
 func authIndexBucket(tx Tx) (Bucket, error) {
 	b, err := tx.Bucket([]byte(authIndex))
 	if err != nil {
@@ -558,14 +776,22 @@ func UnexpectedAuthIndexError(err error) *influxdb.Error {
 	}
 }
 
-func (s *Service) uniqueAuthToken(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
-	err := s.unique(ctx, tx, authIndex, authIndexKey(a.Token))
-	if err == NotUniqueError {
+func (s *Service) uniqueAuthToken(ctx context.Context, tx Tx, token string) error {
+	unique := true
+	err := s.findAuthorizationCandidates(ctx, tx, token, func(sa *storedAuthorization) bool {
+		unique = !authTokenMatches(sa.TokenHash, token) &&
+			(sa.PreviousTokenHash == "" || !authTokenMatches(sa.PreviousTokenHash, token))
+		return unique
+	})
+	if err != nil {
+		// this is some sort of internal server error and we
+		// should provide some debugging information.
+		return err
+	}
+	if !unique {
 		// by returning a generic error we are trying to hide when
 		// a token is non-unique.
 		return influxdb.ErrUnableToCreateToken
 	}
-	// otherwise, this is some sort of internal server error and we
-	// should provide some debugging information.
-	return err
+	return nil
 }
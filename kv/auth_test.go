@@ -1,6 +1,7 @@
 package kv_test
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
@@ -76,3 +77,81 @@ func initAuthorizationService(s kv.Store, f influxdbtesting.AuthorizationFields,
 		}
 	}
 }
+
+func TestService_HashAuthTokensMigration(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeStore()
+
+	ctx := context.Background()
+	id := influxdb.ID(1)
+	encodedID, err := id.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an authorization written before tokens were hashed, indexed by its token.
+	legacy := []byte(`{"id":"0000000000000001","token":"legacy-token","status":"active","orgID":"0000000000000002","userID":"0000000000000003","permissions":[]}`)
+	err = s.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("authorizationsv1"))
+		if err != nil {
+			return err
+		}
+		if err := b.Put(encodedID, legacy); err != nil {
+			return err
+		}
+		idx, err := tx.Bucket([]byte("authorizationindexv1"))
+		if err != nil {
+			return err
+		}
+		return idx.Put([]byte("legacy-token"), encodedID)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := kv.NewService(zaptest.NewLogger(t), s)
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := svc.FindAuthorizationByToken(ctx, "legacy-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != id || a.Token != "legacy-token" || a.TokenPrefix != "legacy" {
+		t.Fatalf("unexpected authorization found by token: %+v", a)
+	}
+
+	err = s.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("authorizationsv1"))
+		if err != nil {
+			return err
+		}
+		v, err := b.Get(encodedID)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(v, []byte("legacy-token")) || bytes.Contains(v, []byte(`"token"`)) {
+			t.Errorf("expected the token not to be stored, got %s", v)
+		}
+
+		idx, err := tx.Bucket([]byte("authorizationindexv1"))
+		if err != nil {
+			return err
+		}
+		if _, err := idx.Get([]byte("legacy-token")); !kv.IsNotFound(err) {
+			t.Errorf("expected the token to be removed from the index, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.FindAuthorizationByToken(ctx, "legacy-tokeN"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected a token sharing the prefix not to be found, got %v", err)
+	}
+}
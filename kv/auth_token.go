@@ -0,0 +1,222 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	influxdb "github.com/influxdata/influxdb/v2"
+)
+
+// authTokenSaltLen is the length of the random salt prepended to a token before hashing it.
+const authTokenSaltLen = 16
+
+// authTokenPrefixLen is the length of the token prefix authorizations are indexed by.
+const authTokenPrefixLen = 8
+
+// storedAuthorization is an authorization as stored in the kv store,
+// which holds salted hashes of its tokens rather than the tokens themselves.
+type storedAuthorization struct {
+	*influxdb.Authorization
+
+	// LegacyToken is the token authorizations were stored with in clear before tokens were hashed.
+	// It hides the token of the authorization, which is never stored.
+	LegacyToken string `json:"token,omitempty"`
+
+	TokenHash string `json:"tokenHash,omitempty"`
+	// PreviousTokenPrefix and PreviousTokenHash identify the token replaced by the last rotation.
+	PreviousTokenPrefix string `json:"previousTokenPrefix,omitempty"`
+	PreviousTokenHash   string `json:"previousTokenHash,omitempty"`
+}
+
+// authTokenPrefix returns the part of the token that is stored in clear to index it.
+// Short tokens only reveal half of their length.
+func authTokenPrefix(token string) string {
+	n := len(token) / 2
+	if n > authTokenPrefixLen {
+		n = authTokenPrefixLen
+	}
+	return token[:n]
+}
+
+// hashAuthToken returns the hex encoded salt and sha256 hash of token, separated by a colon.
+func hashAuthToken(token string) (string, error) {
+	salt := make([]byte, authTokenSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(salt) + ":" + hex.EncodeToString(saltedAuthTokenSum(salt, token)), nil
+}
+
+// authTokenMatches returns true if hash is the hash of token.
+func authTokenMatches(hash, token string) bool {
+	i := strings.IndexByte(hash, ':')
+	if i < 0 {
+		return false
+	}
+	salt, err := hex.DecodeString(hash[:i])
+	if err != nil {
+		return false
+	}
+	sum, err := hex.DecodeString(hash[i+1:])
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(sum, saltedAuthTokenSum(salt, token)) == 1
+}
+
+func saltedAuthTokenSum(salt []byte, token string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(token))
+	return h.Sum(nil)
+}
+
+// authIndexKey returns the index key of an authorization with a token starting with prefix.
+// Tokens sharing a prefix are told apart by the id of their authorization.
+func authIndexKey(prefix string, encodedID []byte) []byte {
+	return append(authIndexPrefix(prefix), encodedID...)
+}
+
+func authIndexPrefix(prefix string) []byte {
+	return append([]byte(prefix), 0)
+}
+
+// findAuthorizationCandidates calls fn with the stored authorizations whose current, or previous,
+// token starts with the prefix of token, until fn returns false.
+func (s *Service) findAuthorizationCandidates(ctx context.Context, tx Tx, token string, fn func(*storedAuthorization) bool) error {
+	idx, err := authIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	prefix := authIndexPrefix(authTokenPrefix(token))
+	cur, err := idx.ForwardCursor(prefix, WithCursorPrefix(prefix))
+	if err != nil {
+		return err
+	}
+	defer cur.Close()
+
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		if !bytes.HasPrefix(k, prefix) {
+			break
+		}
+
+		var id influxdb.ID
+		if err := id.Decode(v); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+		sa, err := s.findStoredAuthorizationByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !fn(sa) {
+			break
+		}
+	}
+	return cur.Err()
+}
+
+// hashAuthTokensMigration replaces the tokens stored in clear by authorizations,
+// and the index of those tokens, with hashes of the tokens.
+func hashAuthTokensMigration() MigrationSpec {
+	return NewAnonymousMigration(
+		"hash authorization tokens",
+		func(ctx context.Context, store Store) error {
+			return store.Update(ctx, hashAuthTokens)
+		},
+		// down fails, the tokens cannot be recovered from their hashes
+		func(context.Context, Store) error {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  "authorization tokens cannot be restored from their hashes",
+			}
+		},
+	)
+}
+
+func hashAuthTokens(tx Tx) error {
+	b, err := tx.Bucket(authBucket)
+	if err != nil {
+		return err
+	}
+	idx, err := authIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.ForwardCursor(nil)
+	if err != nil {
+		return err
+	}
+
+	type entry struct {
+		k, v []byte
+	}
+	var entries []entry
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		// the entries are rewritten once the cursor is closed, which may reuse k and v.
+		entries = append(entries, entry{k: append([]byte(nil), k...), v: append([]byte(nil), v...)})
+	}
+	if err := cur.Err(); err != nil {
+		return err
+	}
+	if err := cur.Close(); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		sa := &storedAuthorization{Authorization: &influxdb.Authorization{}}
+		if err := json.Unmarshal(e.v, sa); err != nil {
+			return err
+		}
+		if sa.TokenHash != "" || sa.LegacyToken == "" {
+			continue
+		}
+
+		if err := idx.Delete([]byte(sa.LegacyToken)); err != nil {
+			return err
+		}
+		if sa.TokenHash, err = hashAuthToken(sa.LegacyToken); err != nil {
+			return err
+		}
+		sa.TokenPrefix = authTokenPrefix(sa.LegacyToken)
+		sa.LegacyToken = ""
+		if err := idx.Put(authIndexKey(sa.TokenPrefix, e.k), e.k); err != nil {
+			return err
+		}
+
+		// authorizations rotated with a grace period kept the replaced token in clear.
+		if prev, err := jsonparser.GetString(e.v, "previousToken"); err == nil && prev != "" {
+			if err := idx.Delete([]byte(prev)); err != nil {
+				return err
+			}
+			if sa.PreviousTokenHash, err = hashAuthToken(prev); err != nil {
+				return err
+			}
+			sa.PreviousTokenPrefix = authTokenPrefix(prev)
+			if err := idx.Put(authIndexKey(sa.PreviousTokenPrefix, e.k), e.k); err != nil {
+				return err
+			}
+		}
+
+		v, err := json.Marshal(sa)
+		if err != nil {
+			return fmt.Errorf("failed to encode authorization %s: %v", e.k, err)
+		}
+		if err := b.Put(e.k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
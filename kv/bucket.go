@@ -477,6 +477,18 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) (
 		return err
 	}
 
+	if err := influxdb.ValidBucketStringCompression(b.StringCompression); err != nil {
+		return err
+	}
+
+	if err := influxdb.ValidBucketIndexedFields(b.IndexedFields); err != nil {
+		return err
+	}
+
+	if err := influxdb.ValidBucketWALDurability(b.WALDurability); err != nil {
+		return err
+	}
+
 	if b.ID, err = s.generateBucketID(ctx, tx); err != nil {
 		return err
 	}
@@ -691,6 +703,27 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.Description = *upd.Description
 	}
 
+	if upd.StringCompression != nil {
+		if err := influxdb.ValidBucketStringCompression(*upd.StringCompression); err != nil {
+			return nil, err
+		}
+		b.StringCompression = *upd.StringCompression
+	}
+
+	if upd.IndexedFields != nil {
+		if err := influxdb.ValidBucketIndexedFields(*upd.IndexedFields); err != nil {
+			return nil, err
+		}
+		b.IndexedFields = *upd.IndexedFields
+	}
+
+	if upd.WALDurability != nil {
+		if err := influxdb.ValidBucketWALDurability(*upd.WALDurability); err != nil {
+			return nil, err
+		}
+		b.WALDurability = *upd.WALDurability
+	}
+
 	if upd.Name != nil {
 		b0, err := s.findBucketByName(ctx, tx, b.OrgID, *upd.Name)
 		if err == nil && b0.ID != id {
@@ -0,0 +1,254 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.CertificateMappingService = (*Service)(nil)
+
+func newCertificateMappingStore() *IndexStore {
+	const resource = "certificate mapping"
+
+	var decMappingEntFn DecodeBucketValFn = func(key, val []byte) ([]byte, interface{}, error) {
+		var m influxdb.CertificateMapping
+		return key, &m, json.Unmarshal(val, &m)
+	}
+
+	var decValToEntFn ConvertValToEntFn = func(_ []byte, v interface{}) (Entity, error) {
+		m, ok := v.(*influxdb.CertificateMapping)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		return certificateMappingEntity(m), nil
+	}
+
+	var decIndexValToEntFn ConvertValToEntFn = func(k []byte, v interface{}) (Entity, error) {
+		id, ok := v.(influxdb.ID)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		ent := Entity{PK: EncID(id)}
+		if len(k) > 0 {
+			ent.UniqueKey = EncBytes(k)
+		}
+		return ent, nil
+	}
+
+	return &IndexStore{
+		Resource:   resource,
+		EntStore:   NewStoreBase(resource, []byte("certificatemappingsv1"), EncIDKey, EncBodyJSON, decMappingEntFn, decValToEntFn),
+		IndexStore: NewStoreBase(resource, []byte("certificatemappingsindexv1"), EncUniqKey, EncIDKey, DecIndexID, decIndexValToEntFn),
+	}
+}
+
+func certificateMappingEntity(m *influxdb.CertificateMapping) Entity {
+	return Entity{
+		PK:        EncID(m.ID),
+		UniqueKey: certificateMappingKey(m.Subject, m.SAN),
+		Body:      m,
+	}
+}
+
+// certificateMappingKey is the unique key of the mapping of a subject or SAN,
+// a certificate subject and SAN with the same value are mapped separately.
+func certificateMappingKey(subject, san string) EncodeFn {
+	if subject != "" {
+		return EncString("subject:" + subject)
+	}
+	return EncString("san:" + san)
+}
+
+// createCertificateMappingStoreMigration creates the buckets of the certificate mapping store.
+func (s *Service) createCertificateMappingStoreMigration() MigrationSpec {
+	return NewAnonymousMigration(
+		"create certificate mappings buckets",
+		func(ctx context.Context, store Store) error {
+			return store.Update(ctx, func(tx Tx) error {
+				return s.certMapStore.Init(ctx, tx)
+			})
+		},
+		// down is a noop, the buckets are left in place
+		func(context.Context, Store) error {
+			return nil
+		},
+	)
+}
+
+// FindCertificateMappingByID retrieves a certificate mapping by id.
+func (s *Service) FindCertificateMappingByID(ctx context.Context, id influxdb.ID) (*influxdb.CertificateMapping, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var m *influxdb.CertificateMapping
+	err := s.kv.View(ctx, func(tx Tx) error {
+		mapping, err := s.findCertificateMapping(ctx, tx, Entity{PK: EncID(id)})
+		if err != nil {
+			return err
+		}
+		m = mapping
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *Service) findCertificateMapping(ctx context.Context, tx Tx, ent Entity) (*influxdb.CertificateMapping, error) {
+	body, err := s.certMapStore.FindEnt(ctx, tx, ent)
+	if err != nil {
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return nil, &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Op:   influxdb.OpFindCertificateMappingByID,
+				Msg:  influxdb.ErrCertificateMappingNotFound,
+			}
+		}
+		return nil, err
+	}
+
+	m, ok := body.(*influxdb.CertificateMapping)
+	return m, IsErrUnexpectedDecodeVal(ok)
+}
+
+// FindCertificateMappings retrieves all certificate mappings that match the filter.
+// Filtering by subject or SAN looks the mapping up by its index.
+func (s *Service) FindCertificateMappings(ctx context.Context, filter influxdb.CertificateMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.CertificateMapping, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var (
+		lookup Entity
+		byKey  bool
+	)
+	switch {
+	case filter.ID != nil:
+		lookup, byKey = Entity{PK: EncID(*filter.ID)}, true
+	case filter.Subject != nil:
+		lookup, byKey = Entity{UniqueKey: certificateMappingKey(*filter.Subject, "")}, true
+	case filter.SAN != nil:
+		lookup, byKey = Entity{UniqueKey: certificateMappingKey("", *filter.SAN)}, true
+	}
+
+	var o influxdb.FindOptions
+	if len(opt) > 0 {
+		o = opt[0]
+	}
+
+	mappings := []*influxdb.CertificateMapping{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if byKey {
+			m, err := s.findCertificateMapping(ctx, tx, lookup)
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if filterCertificateMappingsFn(filter)(nil, m) {
+				mappings = append(mappings, m)
+			}
+			return nil
+		}
+
+		return s.certMapStore.Find(ctx, tx, FindOpts{
+			Descending:  o.Descending,
+			Offset:      o.Offset,
+			Limit:       o.Limit,
+			FilterEntFn: filterCertificateMappingsFn(filter),
+			CaptureFn: func(key []byte, decodedVal interface{}) error {
+				m, ok := decodedVal.(*influxdb.CertificateMapping)
+				if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+					return err
+				}
+				mappings = append(mappings, m)
+				return nil
+			},
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return mappings, len(mappings), nil
+}
+
+func filterCertificateMappingsFn(filter influxdb.CertificateMappingFilter) FilterFn {
+	return func(key []byte, val interface{}) bool {
+		m, ok := val.(*influxdb.CertificateMapping)
+		if !ok {
+			return false
+		}
+		if filter.ID != nil && m.ID != *filter.ID {
+			return false
+		}
+		if filter.OrgID != nil && m.OrgID != *filter.OrgID {
+			return false
+		}
+		if filter.AuthorizationID != nil && m.AuthorizationID != *filter.AuthorizationID {
+			return false
+		}
+		if filter.Subject != nil && m.Subject != *filter.Subject {
+			return false
+		}
+		if filter.SAN != nil && m.SAN != *filter.SAN {
+			return false
+		}
+		return true
+	}
+}
+
+// CreateCertificateMapping creates a certificate mapping and sets its ID.
+// The mapping is in the organization of its authorization.
+func (s *Service) CreateCertificateMapping(ctx context.Context, m *influxdb.CertificateMapping) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := m.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateCertificateMapping,
+			Err: err,
+		}
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		a, err := s.findAuthorizationByID(ctx, tx, m.AuthorizationID)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Op:   influxdb.OpCreateCertificateMapping,
+				Err:  err,
+			}
+		}
+
+		m.ID = s.IDGenerator.ID()
+		m.OrgID = a.OrgID
+		now := s.Now()
+		m.SetCreatedAt(now)
+		m.SetUpdatedAt(now)
+		return s.certMapStore.Put(ctx, tx, certificateMappingEntity(m), PutNew())
+	})
+}
+
+// DeleteCertificateMapping removes a certificate mapping.
+func (s *Service) DeleteCertificateMapping(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findCertificateMapping(ctx, tx, Entity{PK: EncID(id)}); err != nil {
+			return err
+		}
+		return s.certMapStore.DeleteEnt(ctx, tx, Entity{PK: EncID(id)})
+	})
+}
+
+// deleteAuthorizationCertificateMappings removes the mappings of certificates to a deleted authorization.
+func (s *Service) deleteAuthorizationCertificateMappings(ctx context.Context, tx Tx, authID influxdb.ID) error {
+	return s.certMapStore.Delete(ctx, tx, DeleteOpts{
+		FilterFn: filterCertificateMappingsFn(influxdb.CertificateMappingFilter{AuthorizationID: &authID}),
+	})
+}
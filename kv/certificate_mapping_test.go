@@ -0,0 +1,88 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_CertificateMappings(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	require.NoError(t, err)
+	defer closeStore()
+
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ctx := context.Background()
+	require.NoError(t, svc.Initialize(ctx))
+
+	org := &influxdb.Organization{Name: "org"}
+	require.NoError(t, svc.CreateOrganization(ctx, org))
+	user := &influxdb.User{Name: "user"}
+	require.NoError(t, svc.CreateUser(ctx, user))
+	auth := &influxdb.Authorization{OrgID: org.ID, UserID: user.ID}
+	require.NoError(t, svc.CreateAuthorization(ctx, auth))
+
+	bySAN := &influxdb.CertificateMapping{SAN: "telegraf.default.svc", AuthorizationID: auth.ID}
+	require.NoError(t, svc.CreateCertificateMapping(ctx, bySAN))
+	assert.Equal(t, org.ID, bySAN.OrgID)
+	bySubject := &influxdb.CertificateMapping{Subject: "CN=telegraf", AuthorizationID: auth.ID}
+	require.NoError(t, svc.CreateCertificateMapping(ctx, bySubject))
+
+	t.Run("finds mappings by subject and san", func(t *testing.T) {
+		san := "telegraf.default.svc"
+		ms, _, err := svc.FindCertificateMappings(ctx, influxdb.CertificateMappingFilter{SAN: &san})
+		require.NoError(t, err)
+		assert.Equal(t, []*influxdb.CertificateMapping{bySAN}, ms)
+
+		subject := "CN=telegraf"
+		ms, _, err = svc.FindCertificateMappings(ctx, influxdb.CertificateMappingFilter{Subject: &subject})
+		require.NoError(t, err)
+		assert.Equal(t, []*influxdb.CertificateMapping{bySubject}, ms)
+
+		// a subject is not matched as a san
+		ms, _, err = svc.FindCertificateMappings(ctx, influxdb.CertificateMappingFilter{SAN: &subject})
+		require.NoError(t, err)
+		assert.Empty(t, ms)
+
+		ms, _, err = svc.FindCertificateMappings(ctx, influxdb.CertificateMappingFilter{OrgID: &org.ID})
+		require.NoError(t, err)
+		assert.Len(t, ms, 2)
+	})
+
+	t.Run("rejects invalid mappings", func(t *testing.T) {
+		err := svc.CreateCertificateMapping(ctx, &influxdb.CertificateMapping{SAN: "other", Subject: "CN=other", AuthorizationID: auth.ID})
+		assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+		err = svc.CreateCertificateMapping(ctx, &influxdb.CertificateMapping{SAN: "telegraf.default.svc", AuthorizationID: auth.ID})
+		assert.Equal(t, influxdb.EConflict, influxdb.ErrorCode(err))
+
+		err = svc.CreateCertificateMapping(ctx, &influxdb.CertificateMapping{SAN: "other", AuthorizationID: influxdb.ID(100)})
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	})
+
+	t.Run("deletes a mapping", func(t *testing.T) {
+		require.NoError(t, svc.DeleteCertificateMapping(ctx, bySubject.ID))
+
+		_, err := svc.FindCertificateMappingByID(ctx, bySubject.ID)
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+
+		// the subject can be mapped again once its mapping is deleted
+		require.NoError(t, svc.CreateCertificateMapping(ctx, &influxdb.CertificateMapping{Subject: "CN=telegraf", AuthorizationID: auth.ID}))
+	})
+
+	t.Run("deleting the authorization deletes its mappings", func(t *testing.T) {
+		require.NoError(t, svc.DeleteAuthorization(ctx, auth.ID))
+
+		ms, _, err := svc.FindCertificateMappings(ctx, influxdb.CertificateMappingFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, ms)
+	})
+}
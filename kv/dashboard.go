@@ -891,6 +891,10 @@ func (s *Service) deleteDashboard(ctx context.Context, tx Tx, id influxdb.ID) er
 		}
 	}
 
+	if err := s.deleteDashboardShares(ctx, tx, id); err != nil {
+		return err
+	}
+
 	if err := s.appendDashboardEventToLog(ctx, tx, d.ID, dashboardRemovedEvent); err != nil {
 		return &influxdb.Error{
 			Err: err,
@@ -0,0 +1,407 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var (
+	dashboardShareBucket      = []byte("dashboardsharesv1")
+	dashboardShareIndexBucket = []byte("dashboardsharesindexv1")
+)
+
+var _ influxdb.DashboardShareService = (*Service)(nil)
+
+// createDashboardSharesMigration creates the buckets of the dashboard share links.
+func createDashboardSharesMigration() MigrationSpec {
+	return NewAnonymousMigration(
+		"create dashboard share link buckets",
+		func(ctx context.Context, store Store) error {
+			return store.Update(ctx, func(tx Tx) error {
+				if _, err := tx.Bucket(dashboardShareBucket); err != nil {
+					return err
+				}
+				_, err := tx.Bucket(dashboardShareIndexBucket)
+				return err
+			})
+		},
+		// down is a noop, the buckets are left in place
+		func(context.Context, Store) error {
+			return nil
+		},
+	)
+}
+
+// storedDashboardShare is a share link as it is stored, with the salted hash of
+// its token in place of the token. Share links are indexed by the prefix of
+// their token, as authorizations are.
+type storedDashboardShare struct {
+	influxdb.DashboardShare
+	TokenPrefix string `json:"tokenPrefix"`
+	TokenHash   string `json:"tokenHash"`
+}
+
+// FindDashboardShareByID retrieves a share link by id.
+func (s *Service) FindDashboardShareByID(ctx context.Context, id influxdb.ID) (*influxdb.DashboardShare, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var ds *influxdb.DashboardShare
+	err := s.kv.View(ctx, func(tx Tx) error {
+		sds, err := s.findDashboardShareByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		ds = &sds.DashboardShare
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+func (s *Service) findDashboardShareByID(ctx context.Context, tx Tx, id influxdb.ID) (*storedDashboardShare, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpFindDashboardShareByID,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(dashboardShareBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Op:   influxdb.OpFindDashboardShareByID,
+			Msg:  influxdb.ErrDashboardShareNotFound,
+		}
+	}
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpFindDashboardShareByID,
+			Err:  err,
+		}
+	}
+
+	sds := &storedDashboardShare{}
+	if err := json.Unmarshal(v, sds); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpFindDashboardShareByID,
+			Err:  err,
+		}
+	}
+	return sds, nil
+}
+
+// FindDashboardShareByToken retrieves a share link by its token.
+func (s *Service) FindDashboardShareByToken(ctx context.Context, token string) (*influxdb.DashboardShare, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	notFound := &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Op:   influxdb.OpFindDashboardShareByToken,
+		Msg:  influxdb.ErrDashboardShareNotFound,
+	}
+
+	var ds *influxdb.DashboardShare
+	err := s.kv.View(ctx, func(tx Tx) error {
+		idx, err := tx.Bucket(dashboardShareIndexBucket)
+		if err != nil {
+			return err
+		}
+
+		prefix := authIndexPrefix(authTokenPrefix(token))
+		cur, err := idx.ForwardCursor(prefix, WithCursorPrefix(prefix))
+		if err != nil {
+			return err
+		}
+		defer cur.Close()
+
+		for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+			if !bytes.HasPrefix(k, prefix) {
+				break
+			}
+
+			var id influxdb.ID
+			if err := id.Decode(v); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Op:   influxdb.OpFindDashboardShareByToken,
+					Err:  err,
+				}
+			}
+
+			sds, err := s.findDashboardShareByID(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if authTokenMatches(sds.TokenHash, token) {
+				ds = &sds.DashboardShare
+				break
+			}
+		}
+		if err := cur.Err(); err != nil {
+			return err
+		}
+		if ds == nil {
+			return notFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+// FindDashboardShares retrieves the share links matching the filter.
+func (s *Service) FindDashboardShares(ctx context.Context, filter influxdb.DashboardShareFilter) ([]*influxdb.DashboardShare, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	shares := []*influxdb.DashboardShare{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		return s.forEachDashboardShare(ctx, tx, func(sds *storedDashboardShare) bool {
+			if filterDashboardShare(filter, &sds.DashboardShare) {
+				shares = append(shares, &sds.DashboardShare)
+			}
+			return true
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return shares, len(shares), nil
+}
+
+func filterDashboardShare(filter influxdb.DashboardShareFilter, ds *influxdb.DashboardShare) bool {
+	if filter.ID != nil && ds.ID != *filter.ID {
+		return false
+	}
+	if filter.DashboardID != nil && ds.DashboardID != *filter.DashboardID {
+		return false
+	}
+	if filter.OrgID != nil && ds.OrgID != *filter.OrgID {
+		return false
+	}
+	return true
+}
+
+func (s *Service) forEachDashboardShare(ctx context.Context, tx Tx, fn func(*storedDashboardShare) bool) error {
+	b, err := tx.Bucket(dashboardShareBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.ForwardCursor(nil)
+	if err != nil {
+		return err
+	}
+	defer cur.Close()
+
+	for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+		sds := &storedDashboardShare{}
+		if err := json.Unmarshal(v, sds); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   influxdb.OpFindDashboardShares,
+				Err:  err,
+			}
+		}
+		if !fn(sds) {
+			break
+		}
+	}
+	return cur.Err()
+}
+
+// CreateDashboardShare creates a share link of a dashboard, setting its ID,
+// organization and token. The token is not stored, it is only known to the caller.
+func (s *Service) CreateDashboardShare(ctx context.Context, ds *influxdb.DashboardShare) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := ds.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateDashboardShare,
+			Err: err,
+		}
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		d, err := s.findDashboardByID(ctx, tx, ds.DashboardID)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Op:   influxdb.OpCreateDashboardShare,
+				Err:  err,
+			}
+		}
+
+		token, err := s.TokenGenerator.Token()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   influxdb.OpCreateDashboardShare,
+				Err:  err,
+			}
+		}
+
+		ds.ID = s.IDGenerator.ID()
+		ds.OrgID = d.OrganizationID
+		now := s.Now()
+		ds.SetCreatedAt(now)
+		ds.SetUpdatedAt(now)
+
+		hash, err := hashAuthToken(token)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   influxdb.OpCreateDashboardShare,
+				Err:  err,
+			}
+		}
+		sds := &storedDashboardShare{
+			DashboardShare: *ds,
+			TokenPrefix:    authTokenPrefix(token),
+			TokenHash:      hash,
+		}
+		if err := s.putDashboardShare(ctx, tx, sds); err != nil {
+			return err
+		}
+
+		ds.Token = token
+		return nil
+	})
+}
+
+func (s *Service) putDashboardShare(ctx context.Context, tx Tx, sds *storedDashboardShare) error {
+	encodedID, err := sds.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpCreateDashboardShare,
+			Err:  err,
+		}
+	}
+
+	v, err := json.Marshal(sds)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpCreateDashboardShare,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(dashboardShareBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Put(encodedID, v); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpCreateDashboardShare,
+			Err:  err,
+		}
+	}
+
+	idx, err := tx.Bucket(dashboardShareIndexBucket)
+	if err != nil {
+		return err
+	}
+	if err := idx.Put(authIndexKey(sds.TokenPrefix, encodedID), encodedID); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpCreateDashboardShare,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// DeleteDashboardShare revokes a share link.
+func (s *Service) DeleteDashboardShare(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		sds, err := s.findDashboardShareByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		return s.deleteDashboardShare(ctx, tx, sds)
+	})
+}
+
+func (s *Service) deleteDashboardShare(ctx context.Context, tx Tx, sds *storedDashboardShare) error {
+	encodedID, err := sds.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpDeleteDashboardShare,
+			Err:  err,
+		}
+	}
+
+	idx, err := tx.Bucket(dashboardShareIndexBucket)
+	if err != nil {
+		return err
+	}
+	if err := idx.Delete(authIndexKey(sds.TokenPrefix, encodedID)); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpDeleteDashboardShare,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(dashboardShareBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(encodedID); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpDeleteDashboardShare,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// deleteDashboardShares revokes the share links of a deleted dashboard.
+func (s *Service) deleteDashboardShares(ctx context.Context, tx Tx, dashboardID influxdb.ID) error {
+	var shares []*storedDashboardShare
+	err := s.forEachDashboardShare(ctx, tx, func(sds *storedDashboardShare) bool {
+		if sds.DashboardID == dashboardID {
+			shares = append(shares, sds)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, sds := range shares {
+		if err := s.deleteDashboardShare(ctx, tx, sds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
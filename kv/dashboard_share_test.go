@@ -0,0 +1,79 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_DashboardShares(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	require.NoError(t, err)
+	defer closeStore()
+
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	svc.TokenGenerator = mock.NewTokenGenerator("share-token", nil)
+	ctx := context.Background()
+	require.NoError(t, svc.Initialize(ctx))
+
+	org := &influxdb.Organization{Name: "org"}
+	require.NoError(t, svc.CreateOrganization(ctx, org))
+	d := &influxdb.Dashboard{Name: "dashboard", OrganizationID: org.ID}
+	require.NoError(t, svc.CreateDashboard(ctx, d))
+
+	share := &influxdb.DashboardShare{DashboardID: d.ID, Description: "wall screen"}
+	require.NoError(t, svc.CreateDashboardShare(ctx, share))
+	assert.Equal(t, org.ID, share.OrgID)
+	assert.Equal(t, "share-token", share.Token)
+
+	t.Run("finds share links by token without returning the token", func(t *testing.T) {
+		found, err := svc.FindDashboardShareByToken(ctx, "share-token")
+		require.NoError(t, err)
+		assert.Equal(t, share.ID, found.ID)
+		assert.Empty(t, found.Token)
+
+		_, err = svc.FindDashboardShareByToken(ctx, "other-token")
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+
+		// tokens are indexed by their prefix, a token sharing it is not found.
+		_, err = svc.FindDashboardShareByToken(ctx, "share-tokeN")
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	})
+
+	t.Run("finds share links by dashboard", func(t *testing.T) {
+		shares, n, err := svc.FindDashboardShares(ctx, influxdb.DashboardShareFilter{DashboardID: &d.ID})
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, share.ID, shares[0].ID)
+	})
+
+	t.Run("rejects share links of missing dashboards", func(t *testing.T) {
+		err := svc.CreateDashboardShare(ctx, &influxdb.DashboardShare{DashboardID: influxdb.ID(1)})
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	})
+
+	t.Run("revokes share links", func(t *testing.T) {
+		other := &influxdb.DashboardShare{DashboardID: d.ID}
+		svc.TokenGenerator = mock.NewTokenGenerator("other-token", nil)
+		require.NoError(t, svc.CreateDashboardShare(ctx, other))
+		require.NoError(t, svc.DeleteDashboardShare(ctx, other.ID))
+
+		_, err := svc.FindDashboardShareByToken(ctx, "other-token")
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	})
+
+	t.Run("revokes share links of deleted dashboards", func(t *testing.T) {
+		require.NoError(t, svc.DeleteDashboard(ctx, d.ID))
+
+		_, err := svc.FindDashboardShareByToken(ctx, "share-token")
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	})
+}
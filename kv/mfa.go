@@ -0,0 +1,273 @@
+package kv
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/pkg/totp"
+)
+
+const (
+	// mfaIssuer names the accounts of users in authenticator apps.
+	mfaIssuer = "InfluxDB"
+	// mfaRecoveryCodes is the number of recovery codes generated on enrollment.
+	mfaRecoveryCodes = 10
+)
+
+var _ influxdb.MFAService = (*Service)(nil)
+
+// userMFA is the second factor of a user. Recovery codes are stored as
+// hashes, and are removed once used.
+type userMFA struct {
+	Secret        string   `json:"secret,omitempty"`
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+	// LastStep is the time step of the last code used, codes of it or of
+	// earlier steps can not be used again.
+	LastStep int64 `json:"lastStep,omitempty"`
+
+	// Pending is the second factor being enrolled, which replaces this once confirmed.
+	Pending *userMFA `json:"pending,omitempty"`
+}
+
+func (m *userMFA) enabled() bool {
+	return m.Secret != ""
+}
+
+// MFAEnabled returns whether the user signs in with a second factor.
+func (s *Service) MFAEnabled(ctx context.Context, userID influxdb.ID) (bool, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var enabled bool
+	err := s.kv.View(ctx, func(tx Tx) error {
+		m, err := s.findUserMFA(ctx, tx, userID, influxdb.OpMFAEnabled)
+		if err != nil {
+			return err
+		}
+		enabled = m.enabled()
+		return nil
+	})
+	return enabled, err
+}
+
+// EnrollMFA generates a new secret and recovery codes for the user, which
+// replace any second factor the user has once confirmed. The code must be a
+// code of the second factor the user has, if any.
+func (s *Service) EnrollMFA(ctx context.Context, userID influxdb.ID, code string) (*influxdb.MFAEnrollment, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var e *influxdb.MFAEnrollment
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		u, err := s.findUserByID(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		m, err := s.findUserMFA(ctx, tx, userID, influxdb.OpEnrollMFA)
+		if err != nil {
+			return err
+		}
+		if m.enabled() {
+			if err := s.verifyMFA(ctx, tx, userID, m, code, influxdb.OpEnrollMFA); err != nil {
+				return err
+			}
+		}
+
+		secret, err := totp.GenerateSecret()
+		if err != nil {
+			return mfaInternalError(influxdb.OpEnrollMFA, err)
+		}
+		codes, err := generateRecoveryCodes()
+		if err != nil {
+			return mfaInternalError(influxdb.OpEnrollMFA, err)
+		}
+
+		m.Pending = &userMFA{Secret: secret}
+		for _, c := range codes {
+			hash, err := hashAuthToken(normalizeRecoveryCode(c))
+			if err != nil {
+				return mfaInternalError(influxdb.OpEnrollMFA, err)
+			}
+			m.Pending.RecoveryCodes = append(m.Pending.RecoveryCodes, hash)
+		}
+		if err := s.putUserMFA(ctx, tx, userID, m, influxdb.OpEnrollMFA); err != nil {
+			return err
+		}
+
+		e = &influxdb.MFAEnrollment{
+			Secret:        secret,
+			URL:           totp.KeyURI(mfaIssuer, u.Name, secret),
+			RecoveryCodes: codes,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ConfirmMFA enables the second factor being enrolled by the user if the code
+// is one of its one-time codes.
+func (s *Service) ConfirmMFA(ctx context.Context, userID influxdb.ID, code string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		m, err := s.findUserMFA(ctx, tx, userID, influxdb.OpConfirmMFA)
+		if err != nil {
+			return err
+		}
+		if m.Pending == nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   influxdb.OpConfirmMFA,
+				Msg:  "no second factor is being enrolled",
+			}
+		}
+
+		step, ok, err := totp.Validate(m.Pending.Secret, code, s.Now())
+		if err != nil {
+			return mfaInternalError(influxdb.OpConfirmMFA, err)
+		}
+		if !ok {
+			return &influxdb.Error{
+				Code: influxdb.EForbidden,
+				Op:   influxdb.OpConfirmMFA,
+				Msg:  influxdb.ErrIncorrectMFACode,
+			}
+		}
+
+		confirmed := m.Pending
+		confirmed.LastStep = step
+		return s.putUserMFA(ctx, tx, userID, confirmed, influxdb.OpConfirmMFA)
+	})
+}
+
+// VerifyMFA checks the code against the second factor of the user, accepting
+// either a one-time code of a later time step than the last used, or an unused
+// recovery code.
+func (s *Service) VerifyMFA(ctx context.Context, userID influxdb.ID, code string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		m, err := s.findUserMFA(ctx, tx, userID, influxdb.OpVerifyMFA)
+		if err != nil {
+			return err
+		}
+		if !m.enabled() {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   influxdb.OpVerifyMFA,
+				Msg:  "the user has no second factor",
+			}
+		}
+		return s.verifyMFA(ctx, tx, userID, m, code, influxdb.OpVerifyMFA)
+	})
+}
+
+// verifyMFA checks the code against the enabled second factor m of the user,
+// using the code up.
+func (s *Service) verifyMFA(ctx context.Context, tx Tx, userID influxdb.ID, m *userMFA, code, op string) error {
+	incorrect := &influxdb.Error{
+		Code: influxdb.EForbidden,
+		Op:   op,
+		Msg:  influxdb.ErrIncorrectMFACode,
+	}
+
+	step, ok, err := totp.Validate(m.Secret, code, s.Now())
+	if err != nil {
+		return mfaInternalError(op, err)
+	}
+	if ok {
+		if step <= m.LastStep {
+			return incorrect
+		}
+		m.LastStep = step
+		return s.putUserMFA(ctx, tx, userID, m, op)
+	}
+
+	code = normalizeRecoveryCode(code)
+	for i, c := range m.RecoveryCodes {
+		if authTokenMatches(c, code) {
+			m.RecoveryCodes = append(m.RecoveryCodes[:i], m.RecoveryCodes[i+1:]...)
+			return s.putUserMFA(ctx, tx, userID, m, op)
+		}
+	}
+	return incorrect
+}
+
+// DisableMFA removes the second factor of the user, and any being enrolled.
+// The code must be a code of the second factor the user has, if any.
+func (s *Service) DisableMFA(ctx context.Context, userID influxdb.ID, code string) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		m, err := s.findUserMFA(ctx, tx, userID, influxdb.OpDisableMFA)
+		if err != nil {
+			return err
+		}
+		if m.enabled() {
+			if err := s.verifyMFA(ctx, tx, userID, m, code, influxdb.OpDisableMFA); err != nil {
+				return err
+			}
+		}
+		return s.deleteUserKey(tx, userMFABucket, userID, influxdb.OpDisableMFA)
+	})
+}
+
+func (s *Service) findUserMFA(ctx context.Context, tx Tx, userID influxdb.ID, op string) (*userMFA, error) {
+	m := &userMFA{}
+	v, err := s.getUserKey(tx, userMFABucket, userID, op)
+	if err != nil || v == nil {
+		return m, err
+	}
+	if err := json.Unmarshal(v, m); err != nil {
+		return nil, mfaInternalError(op, err)
+	}
+	return m, nil
+}
+
+func (s *Service) putUserMFA(ctx context.Context, tx Tx, userID influxdb.ID, m *userMFA, op string) error {
+	v, err := json.Marshal(m)
+	if err != nil {
+		return mfaInternalError(op, err)
+	}
+	return s.putUserKey(tx, userMFABucket, userID, v, op)
+}
+
+func mfaInternalError(op string, err error) *influxdb.Error {
+	return &influxdb.Error{
+		Code: influxdb.EInternal,
+		Op:   op,
+		Err:  err,
+	}
+}
+
+// generateRecoveryCodes returns random codes of 10 base32 characters,
+// formatted in two groups of five, such as "ABCDE-FGHIJ".
+func generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, mfaRecoveryCodes)
+	b := make([]byte, 5*mfaRecoveryCodes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	for i := 0; i < mfaRecoveryCodes; i++ {
+		c := base32.StdEncoding.EncodeToString(b[i*5 : (i+1)*5])
+		codes = append(codes, c[:5]+"-"+c[5:])
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode returns the code ignoring case and dashes.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.Replace(code, "-", "", -1))
+}
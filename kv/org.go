@@ -752,6 +752,12 @@ func (s *Service) FindResourceOrganizationID(ctx context.Context, rt influxdb.Re
 			return influxdb.InvalidID(), err
 		}
 		return r.GetOrgID(), nil
+	case influxdb.RolesResourceType:
+		r, err := s.FindRoleByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrgID, nil
 	}
 
 	return influxdb.InvalidID(), &influxdb.Error{
@@ -0,0 +1,202 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var (
+	rateLimitBucket = []byte("ratelimitsv1")
+)
+
+var _ influxdb.RateLimitService = (*Service)(nil)
+
+// createRateLimitsMigration creates the bucket of the rate limits.
+func createRateLimitsMigration() MigrationSpec {
+	return NewAnonymousMigration(
+		"create rate limits bucket",
+		func(ctx context.Context, store Store) error {
+			return store.Update(ctx, func(tx Tx) error {
+				_, err := tx.Bucket(rateLimitBucket)
+				return err
+			})
+		},
+		// down is a noop, the bucket is left in place
+		func(context.Context, Store) error {
+			return nil
+		},
+	)
+}
+
+// rateLimitKey is the id of the organization followed by the id of the
+// token when the limit is restricted to one, so that the limits of an
+// organization share its id as a prefix.
+func rateLimitKey(orgID, authID influxdb.ID) ([]byte, error) {
+	k, err := orgID.Encode()
+	if err != nil {
+		return nil, err
+	}
+	if !authID.Valid() {
+		return k, nil
+	}
+	a, err := authID.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return append(k, a...), nil
+}
+
+// FindRateLimits returns the rate limits matching the filter.
+func (s *Service) FindRateLimits(ctx context.Context, filter influxdb.RateLimitFilter) ([]*influxdb.RateLimit, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	limits := []*influxdb.RateLimit{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(rateLimitBucket)
+		if err != nil {
+			return err
+		}
+
+		var (
+			seek []byte
+			opts []CursorOption
+		)
+		if filter.OrgID != nil {
+			prefix, err := filter.OrgID.Encode()
+			if err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Op:   influxdb.OpFindRateLimits,
+					Err:  err,
+				}
+			}
+			seek = prefix
+			opts = append(opts, WithCursorPrefix(prefix))
+		}
+
+		cur, err := b.ForwardCursor(seek, opts...)
+		if err != nil {
+			return err
+		}
+		defer cur.Close()
+
+		for k, v := cur.Next(); k != nil; k, v = cur.Next() {
+			l := &influxdb.RateLimit{}
+			if err := json.Unmarshal(v, l); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Op:   influxdb.OpFindRateLimits,
+					Err:  err,
+				}
+			}
+			if filter.AuthorizationID != nil && l.AuthorizationID != *filter.AuthorizationID {
+				continue
+			}
+			limits = append(limits, l)
+		}
+		return cur.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// SetRateLimit creates or replaces the rate limit of an organization or
+// of one of its tokens.
+func (s *Service) SetRateLimit(ctx context.Context, l *influxdb.RateLimit) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := l.Valid(); err != nil {
+		return err
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, l.OrgID); err != nil {
+			return err
+		}
+		if l.AuthorizationID.Valid() {
+			a, err := s.findAuthorizationByID(ctx, tx, l.AuthorizationID)
+			if err != nil {
+				return err
+			}
+			if a.OrgID != l.OrgID {
+				return &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Op:   influxdb.OpSetRateLimit,
+					Msg:  "authorization does not belong to the organization of the rate limit",
+				}
+			}
+		}
+
+		k, err := rateLimitKey(l.OrgID, l.AuthorizationID)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Op:   influxdb.OpSetRateLimit,
+				Err:  err,
+			}
+		}
+
+		v, err := json.Marshal(l)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   influxdb.OpSetRateLimit,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(rateLimitBucket)
+		if err != nil {
+			return err
+		}
+		if err := b.Put(k, v); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Op:   influxdb.OpSetRateLimit,
+				Err:  err,
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteRateLimit removes the rate limit of an organization, or of one of
+// its tokens when authID is valid.
+func (s *Service) DeleteRateLimit(ctx context.Context, orgID, authID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	k, err := rateLimitKey(orgID, authID)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Op:   influxdb.OpDeleteRateLimit,
+			Err:  err,
+		}
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(rateLimitBucket)
+		if err != nil {
+			return err
+		}
+		if _, err := b.Get(k); err != nil {
+			if IsNotFound(err) {
+				return &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Op:   influxdb.OpDeleteRateLimit,
+					Msg:  "rate limit not found",
+				}
+			}
+			return err
+		}
+		return b.Delete(k)
+	})
+}
@@ -0,0 +1,85 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_RateLimits(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	require.NoError(t, err)
+	defer closeStore()
+
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+	ctx := context.Background()
+	require.NoError(t, svc.Initialize(ctx))
+
+	org := &influxdb.Organization{Name: "org"}
+	require.NoError(t, svc.CreateOrganization(ctx, org))
+	other := &influxdb.Organization{Name: "other"}
+	require.NoError(t, svc.CreateOrganization(ctx, other))
+	user := &influxdb.User{Name: "user"}
+	require.NoError(t, svc.CreateUser(ctx, user))
+	auth := &influxdb.Authorization{OrgID: org.ID, UserID: user.ID}
+	require.NoError(t, svc.CreateAuthorization(ctx, auth))
+
+	orgLimit := &influxdb.RateLimit{OrgID: org.ID, RequestsPerSecond: 10}
+	tokenLimit := &influxdb.RateLimit{OrgID: org.ID, AuthorizationID: auth.ID, QueryConcurrency: 2}
+	otherLimit := &influxdb.RateLimit{OrgID: other.ID, WriteBytesPerSecond: 1024}
+	for _, l := range []*influxdb.RateLimit{orgLimit, tokenLimit, otherLimit} {
+		require.NoError(t, svc.SetRateLimit(ctx, l))
+	}
+
+	t.Run("finds the limits of an organization", func(t *testing.T) {
+		limits, err := svc.FindRateLimits(ctx, influxdb.RateLimitFilter{OrgID: &org.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []*influxdb.RateLimit{orgLimit, tokenLimit}, limits)
+
+		limits, err = svc.FindRateLimits(ctx, influxdb.RateLimitFilter{AuthorizationID: &auth.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []*influxdb.RateLimit{tokenLimit}, limits)
+
+		limits, err = svc.FindRateLimits(ctx, influxdb.RateLimitFilter{})
+		require.NoError(t, err)
+		assert.Len(t, limits, 3)
+	})
+
+	t.Run("replaces a limit", func(t *testing.T) {
+		updated := &influxdb.RateLimit{OrgID: org.ID, RequestsPerSecond: 20}
+		require.NoError(t, svc.SetRateLimit(ctx, updated))
+
+		var none influxdb.ID
+		limits, err := svc.FindRateLimits(ctx, influxdb.RateLimitFilter{OrgID: &org.ID, AuthorizationID: &none})
+		require.NoError(t, err)
+		assert.Equal(t, []*influxdb.RateLimit{updated}, limits)
+	})
+
+	t.Run("rejects invalid limits", func(t *testing.T) {
+		err := svc.SetRateLimit(ctx, &influxdb.RateLimit{OrgID: org.ID, RequestsPerSecond: -1})
+		assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+		err = svc.SetRateLimit(ctx, &influxdb.RateLimit{OrgID: other.ID, AuthorizationID: auth.ID, RequestsPerSecond: 1})
+		assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+
+		err = svc.SetRateLimit(ctx, &influxdb.RateLimit{OrgID: influxdb.ID(100), RequestsPerSecond: 1})
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	})
+
+	t.Run("deletes a limit", func(t *testing.T) {
+		require.NoError(t, svc.DeleteRateLimit(ctx, org.ID, auth.ID))
+
+		limits, err := svc.FindRateLimits(ctx, influxdb.RateLimitFilter{OrgID: &org.ID})
+		require.NoError(t, err)
+		require.Len(t, limits, 1)
+		assert.False(t, limits[0].AuthorizationID.Valid())
+
+		err = svc.DeleteRateLimit(ctx, org.ID, auth.ID)
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	})
+}
@@ -0,0 +1,310 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var _ influxdb.RoleService = (*Service)(nil)
+
+func newRoleStore() *IndexStore {
+	const resource = "role"
+
+	var decRoleEntFn DecodeBucketValFn = func(key, val []byte) ([]byte, interface{}, error) {
+		var r influxdb.Role
+		return key, &r, json.Unmarshal(val, &r)
+	}
+
+	var decValToEntFn ConvertValToEntFn = func(_ []byte, v interface{}) (Entity, error) {
+		r, ok := v.(*influxdb.Role)
+		if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+			return Entity{}, err
+		}
+		return roleEntity(r), nil
+	}
+
+	return &IndexStore{
+		Resource:   resource,
+		EntStore:   NewStoreBase(resource, []byte("rolesv1"), EncIDKey, EncBodyJSON, decRoleEntFn, decValToEntFn),
+		IndexStore: NewOrgNameKeyStore(resource, []byte("rolesindexv1"), true),
+	}
+}
+
+func roleEntity(r *influxdb.Role) Entity {
+	return Entity{
+		PK:        EncID(r.ID),
+		UniqueKey: Encode(EncID(r.OrgID), EncString(r.Name)),
+		Body:      r,
+	}
+}
+
+// createRoleStoreMigration creates the buckets of the role store.
+func (s *Service) createRoleStoreMigration() MigrationSpec {
+	return NewAnonymousMigration(
+		"create roles buckets",
+		func(ctx context.Context, store Store) error {
+			return store.Update(ctx, func(tx Tx) error {
+				return s.roleStore.Init(ctx, tx)
+			})
+		},
+		// down is a noop, the buckets are left in place
+		func(context.Context, Store) error {
+			return nil
+		},
+	)
+}
+
+// FindRoleByID retrieves a role by id.
+func (s *Service) FindRoleByID(ctx context.Context, id influxdb.ID) (*influxdb.Role, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var r *influxdb.Role
+	err := s.kv.View(ctx, func(tx Tx) error {
+		role, err := s.findRoleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		r = role
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *Service) findRoleByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Role, error) {
+	body, err := s.roleStore.FindEnt(ctx, tx, Entity{PK: EncID(id)})
+	if err != nil {
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return nil, &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Op:   influxdb.OpFindRoleByID,
+				Msg:  influxdb.ErrRoleNotFound,
+			}
+		}
+		return nil, err
+	}
+
+	r, ok := body.(*influxdb.Role)
+	return r, IsErrUnexpectedDecodeVal(ok)
+}
+
+// FindRoles retrieves all roles that match the filter.
+func (s *Service) FindRoles(ctx context.Context, filter influxdb.RoleFilter, opt ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if filter.ID != nil {
+		r, err := s.FindRoleByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*influxdb.Role{r}, 1, nil
+	}
+
+	var o influxdb.FindOptions
+	if len(opt) > 0 {
+		o = opt[0]
+	}
+
+	roles := []*influxdb.Role{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		return s.roleStore.Find(ctx, tx, FindOpts{
+			Descending:  o.Descending,
+			Offset:      o.Offset,
+			Limit:       o.Limit,
+			FilterEntFn: filterRolesFn(filter),
+			CaptureFn: func(key []byte, decodedVal interface{}) error {
+				r, ok := decodedVal.(*influxdb.Role)
+				if err := IsErrUnexpectedDecodeVal(ok); err != nil {
+					return err
+				}
+				roles = append(roles, r)
+				return nil
+			},
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return roles, len(roles), nil
+}
+
+func filterRolesFn(filter influxdb.RoleFilter) FilterFn {
+	return func(key []byte, val interface{}) bool {
+		r, ok := val.(*influxdb.Role)
+		if !ok {
+			return false
+		}
+		if filter.OrgID != nil && r.OrgID != *filter.OrgID {
+			return false
+		}
+		if filter.Name != nil && r.Name != *filter.Name {
+			return false
+		}
+		return true
+	}
+}
+
+// findRolesByIDs returns the roles with the ids, skipping roles that no longer exist.
+func (s *Service) findRolesByIDs(ctx context.Context, tx Tx, ids []influxdb.ID) ([]*influxdb.Role, error) {
+	roles := make([]*influxdb.Role, 0, len(ids))
+	for _, id := range ids {
+		r, err := s.findRoleByID(ctx, tx, id)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, r)
+	}
+	return roles, nil
+}
+
+// resolveRolePermissions sets the permissions of the roles of a on a.
+func (s *Service) resolveRolePermissions(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
+	roles, err := s.findRolesByIDs(ctx, tx, a.Roles)
+	if err != nil {
+		return err
+	}
+	a.RolePermissions = influxdb.RolePermissions(roles)
+	return nil
+}
+
+// CreateRole creates a role and sets its ID.
+func (s *Service) CreateRole(ctx context.Context, r *influxdb.Role) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r.Name = strings.TrimSpace(r.Name)
+	if err := r.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateRole,
+			Err: err,
+		}
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, r.OrgID); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Op:   influxdb.OpCreateRole,
+				Err:  err,
+			}
+		}
+
+		r.ID = s.IDGenerator.ID()
+		now := s.Now()
+		r.SetCreatedAt(now)
+		r.SetUpdatedAt(now)
+		return s.roleStore.Put(ctx, tx, roleEntity(r), PutNew())
+	})
+}
+
+// UpdateRole updates the name, description or permissions of a role.
+func (s *Service) UpdateRole(ctx context.Context, id influxdb.ID, upd influxdb.RoleUpdate) (*influxdb.Role, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var r *influxdb.Role
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		role, err := s.findRoleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		oldName := role.Name
+
+		if err := upd.Apply(role); err != nil {
+			return &influxdb.Error{
+				Op:  influxdb.OpUpdateRole,
+				Err: err,
+			}
+		}
+		role.SetUpdatedAt(s.Now())
+
+		if err := s.roleStore.Put(ctx, tx, roleEntity(role), PutUpdate()); err != nil {
+			return err
+		}
+		if role.Name != oldName {
+			oldEnt := roleEntity(&influxdb.Role{ID: role.ID, OrgID: role.OrgID, Name: oldName})
+			if err := s.roleStore.IndexStore.DeleteEnt(ctx, tx, oldEnt); err != nil {
+				return err
+			}
+		}
+		r = role
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DeleteRole removes a role, the mappings of users to it, and the role from authorizations.
+func (s *Service) DeleteRole(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findRoleByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		if err := s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+			ResourceID:   id,
+			ResourceType: influxdb.RolesResourceType,
+		}); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+
+		if err := s.removeRoleFromAuthorizations(ctx, tx, id); err != nil {
+			return err
+		}
+
+		return s.roleStore.DeleteEnt(ctx, tx, Entity{PK: EncID(id)})
+	})
+}
+
+// validAuthorizationRoles ensures the roles of an authorization exist within its organization.
+func (s *Service) validAuthorizationRoles(ctx context.Context, tx Tx, a *influxdb.Authorization) error {
+	for _, id := range a.Roles {
+		r, err := s.findRoleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if r.OrgID != a.OrgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("role %s is not for org id %s", id, a.OrgID),
+			}
+		}
+	}
+	return nil
+}
+
+// userRolePermissions returns the permissions of the roles mapped to a user.
+func (s *Service) userRolePermissions(ctx context.Context, tx Tx, mappings []*influxdb.UserResourceMapping) ([]influxdb.Permission, error) {
+	var ids []influxdb.ID
+	for _, m := range mappings {
+		if m.ResourceType == influxdb.RolesResourceType {
+			ids = append(ids, m.ResourceID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	roles, err := s.findRolesByIDs(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return influxdb.RolePermissions(roles), nil
+}
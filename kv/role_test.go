@@ -0,0 +1,173 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestRoleService(t *testing.T) (*kv.Service, *influxdb.Organization, func()) {
+	t.Helper()
+
+	store, closeStore, err := NewTestInmemStore(t)
+	require.NoError(t, err)
+
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+	ctx := context.Background()
+	require.NoError(t, svc.Initialize(ctx))
+
+	org := &influxdb.Organization{Name: "org1"}
+	require.NoError(t, svc.CreateOrganization(ctx, org))
+
+	return svc, org, closeStore
+}
+
+func TestService_Roles(t *testing.T) {
+	t.Run("create, update and delete a role", func(t *testing.T) {
+		svc, org, done := newTestRoleService(t)
+		defer done()
+		ctx := context.Background()
+
+		bktID := influxdb.ID(3)
+		role := &influxdb.Role{
+			OrgID: org.ID,
+			Name:  "writer",
+			Permissions: []influxdb.Permission{{
+				Action:   influxdb.WriteAction,
+				Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &org.ID, ID: &bktID},
+			}},
+		}
+		require.NoError(t, svc.CreateRole(ctx, role))
+		require.True(t, role.ID.Valid())
+
+		dup := &influxdb.Role{OrgID: org.ID, Name: "writer", Permissions: role.Permissions}
+		require.Error(t, svc.CreateRole(ctx, dup))
+
+		name := "bucket-writer"
+		updated, err := svc.UpdateRole(ctx, role.ID, influxdb.RoleUpdate{Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, name, updated.Name)
+
+		roles, _, err := svc.FindRoles(ctx, influxdb.RoleFilter{OrgID: &org.ID, Name: &name})
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		assert.Equal(t, role.ID, roles[0].ID)
+
+		// the old name is free to be used again once renamed
+		reuse := &influxdb.Role{OrgID: org.ID, Name: "writer", Permissions: role.Permissions}
+		require.NoError(t, svc.CreateRole(ctx, reuse))
+
+		require.NoError(t, svc.DeleteRole(ctx, role.ID))
+		_, err = svc.FindRoleByID(ctx, role.ID)
+		assert.Equal(t, influxdb.ENotFound, influxdb.ErrorCode(err))
+	})
+
+	t.Run("authorizations are granted the permissions of their roles", func(t *testing.T) {
+		svc, org, done := newTestRoleService(t)
+		defer done()
+		ctx := context.Background()
+
+		user := &influxdb.User{Name: "user1"}
+		require.NoError(t, svc.CreateUser(ctx, user))
+
+		perm := influxdb.Permission{
+			Action:   influxdb.ReadAction,
+			Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &org.ID},
+		}
+		role := &influxdb.Role{OrgID: org.ID, Name: "viewer", Permissions: []influxdb.Permission{perm}}
+		require.NoError(t, svc.CreateRole(ctx, role))
+
+		auth := &influxdb.Authorization{
+			OrgID:  org.ID,
+			UserID: user.ID,
+			Roles:  []influxdb.ID{role.ID},
+		}
+		require.NoError(t, svc.CreateAuthorization(ctx, auth))
+
+		found, err := svc.FindAuthorizationByToken(ctx, auth.Token)
+		require.NoError(t, err)
+		assert.Equal(t, []influxdb.Permission{perm}, found.RolePermissions)
+		assert.True(t, found.Allowed(perm))
+
+		found, err = svc.FindAuthorizationByID(ctx, auth.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []influxdb.Permission{perm}, found.RolePermissions)
+
+		auths, _, err := svc.FindAuthorizations(ctx, influxdb.AuthorizationFilter{UserID: &user.ID})
+		require.NoError(t, err)
+		require.Len(t, auths, 1)
+		assert.Equal(t, []influxdb.Permission{perm}, auths[0].RolePermissions)
+
+		require.NoError(t, svc.DeleteRole(ctx, role.ID))
+
+		found, err = svc.FindAuthorizationByToken(ctx, auth.Token)
+		require.NoError(t, err)
+		assert.Empty(t, found.Roles)
+		assert.False(t, found.Allowed(perm))
+	})
+
+	t.Run("sessions are granted the permissions of the roles of their user", func(t *testing.T) {
+		svc, org, done := newTestRoleService(t)
+		defer done()
+		ctx := context.Background()
+
+		user := &influxdb.User{Name: "user1"}
+		require.NoError(t, svc.CreateUser(ctx, user))
+
+		perm := influxdb.Permission{
+			Action:   influxdb.WriteAction,
+			Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &org.ID},
+		}
+		role := &influxdb.Role{OrgID: org.ID, Name: "task-editor", Permissions: []influxdb.Permission{perm}}
+		require.NoError(t, svc.CreateRole(ctx, role))
+
+		require.NoError(t, svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+			UserID:       user.ID,
+			UserType:     influxdb.Member,
+			ResourceType: influxdb.RolesResourceType,
+			ResourceID:   role.ID,
+		}))
+
+		sess, err := svc.CreateSession(ctx, user.Name)
+		require.NoError(t, err)
+
+		sess, err = svc.FindSession(ctx, sess.Key)
+		require.NoError(t, err)
+		assert.True(t, sess.Allowed(perm))
+	})
+
+	t.Run("roles from another org cannot be granted to an authorization", func(t *testing.T) {
+		svc, org, done := newTestRoleService(t)
+		defer done()
+		ctx := context.Background()
+
+		otherOrg := &influxdb.Organization{Name: "org2"}
+		require.NoError(t, svc.CreateOrganization(ctx, otherOrg))
+
+		user := &influxdb.User{Name: "user1"}
+		require.NoError(t, svc.CreateUser(ctx, user))
+
+		role := &influxdb.Role{
+			OrgID: otherOrg.ID,
+			Name:  "viewer",
+			Permissions: []influxdb.Permission{{
+				Action:   influxdb.ReadAction,
+				Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &otherOrg.ID},
+			}},
+		}
+		require.NoError(t, svc.CreateRole(ctx, role))
+
+		err := svc.CreateAuthorization(ctx, &influxdb.Authorization{
+			OrgID:  org.ID,
+			UserID: user.ID,
+			Roles:  []influxdb.ID{role.ID},
+		})
+		require.Error(t, err)
+		assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+	})
+}
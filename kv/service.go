@@ -43,12 +43,20 @@ type Service struct {
 	checkStore    *IndexStore
 	endpointStore *IndexStore
 	variableStore *IndexStore
+	roleStore     *IndexStore
+	certMapStore  *IndexStore
 
 	Migrator *Migrator
 
 	urmByUserIndex *Index
 
 	disableAuthorizationsForMaxPermissions func(context.Context) bool
+
+	// passwordPolicy, signinMaxFailures and signinLockoutDuration are the
+	// settings of Config, or their defaults when unset.
+	passwordPolicy        influxdb.PasswordPolicy
+	signinMaxFailures     int
+	signinLockoutDuration time.Duration
 }
 
 // NewService returns an instance of a Service.
@@ -66,6 +74,8 @@ func NewService(log *zap.Logger, kv Store, configs ...ServiceConfig) *Service {
 		checkStore:     newCheckStore(),
 		endpointStore:  newEndpointStore(),
 		variableStore:  newVariableStore(),
+		roleStore:      newRoleStore(),
+		certMapStore:   newCertificateMappingStore(),
 		Migrator:       NewMigrator(log),
 		urmByUserIndex: NewIndex(NewIndexMapping(
 			urmBucket,
@@ -99,6 +109,34 @@ func NewService(log *zap.Logger, kv Store, configs ...ServiceConfig) *Service {
 		),
 		// add index user resource mappings by user id
 		s.urmByUserIndex.Migration(),
+		// add bucket for the leases of tasks shared between nodes
+		NewAnonymousMigration(
+			"create task leases bucket",
+			func(ctx context.Context, store Store) error {
+				return store.Update(ctx, func(tx Tx) error {
+					_, err := tx.Bucket(taskLeaseBucket)
+					return err
+				})
+			},
+			// down is a noop, the bucket is left in place
+			func(context.Context, Store) error {
+				return nil
+			},
+		),
+		// replace the tokens of authorizations with salted hashes
+		hashAuthTokensMigration(),
+		// add buckets for roles
+		s.createRoleStoreMigration(),
+		// add bucket for the audit log
+		createAuditLogMigration(),
+		// add rate limits bucket
+		createRateLimitsMigration(),
+		// add buckets for sign in lockout and second factors
+		createSigninMigration(),
+		// add buckets for certificate mappings
+		s.createCertificateMappingStoreMigration(),
+		// add buckets for dashboard share links
+		createDashboardSharesMigration(),
 		// and new migrations below here (and move this comment down):
 	)
 
@@ -110,6 +148,21 @@ func NewService(log *zap.Logger, kv Store, configs ...ServiceConfig) *Service {
 		s.Config.SessionLength = influxdb.DefaultSessionLength
 	}
 
+	s.passwordPolicy = s.Config.PasswordPolicy
+	if s.passwordPolicy.MinLength == 0 {
+		s.passwordPolicy.MinLength = MinPasswordLength
+	}
+
+	s.signinMaxFailures = s.Config.SigninMaxFailures
+	if s.signinMaxFailures == 0 {
+		s.signinMaxFailures = influxdb.DefaultSigninMaxFailures
+	}
+
+	s.signinLockoutDuration = s.Config.SigninLockoutDuration
+	if s.signinLockoutDuration == 0 {
+		s.signinLockoutDuration = influxdb.DefaultSigninLockoutDuration
+	}
+
 	s.clock = s.Config.Clock
 	if s.clock == nil {
 		s.clock = clock.New()
@@ -127,6 +180,15 @@ type ServiceConfig struct {
 	SessionLength                 time.Duration
 	Clock                         clock.Clock
 	URMByUserIndexReadPathEnabled bool
+
+	// PasswordPolicy is the requirements of new passwords. Passwords
+	// must be at least MinPasswordLength long when MinLength is unset.
+	PasswordPolicy influxdb.PasswordPolicy
+	// SigninMaxFailures is the number of failed sign in attempts after
+	// which a user is locked out for SigninLockoutDuration, doubling with
+	// each further failure.
+	SigninMaxFailures     int
+	SigninLockoutDuration time.Duration
 }
 
 // AutoMigrationStore is a Store which also describes whether or not
@@ -124,6 +124,12 @@ func (s *Service) maxPermissions(ctx context.Context, tx Tx, userID influxdb.ID)
 	}
 	ps = append(ps, influxdb.MePermissions(userID)...)
 
+	rps, err := s.userRolePermissions(ctx, tx, mappings)
+	if err != nil {
+		return nil, err
+	}
+	ps = append(ps, rps...)
+
 	if !s.disableAuthorizationsForMaxPermissions(ctx) {
 		// TODO(desa): this is super expensive, we should keep a list of a users maximal privileges somewhere
 		// we did this so that the oper token would be used in a users permissions.
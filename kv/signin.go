@@ -0,0 +1,199 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+var (
+	signinFailuresBucket = []byte("signinfailuresv1")
+	userMFABucket        = []byte("usersmfav1")
+)
+
+var _ influxdb.SigninLockoutService = (*Service)(nil)
+
+// createSigninMigration creates the buckets of the failed sign in attempts
+// and of the second factors of users.
+func createSigninMigration() MigrationSpec {
+	return NewAnonymousMigration(
+		"create sign in lockout and mfa buckets",
+		func(ctx context.Context, store Store) error {
+			return store.Update(ctx, func(tx Tx) error {
+				if _, err := tx.Bucket(signinFailuresBucket); err != nil {
+					return err
+				}
+				_, err := tx.Bucket(userMFABucket)
+				return err
+			})
+		},
+		// down is a noop, the buckets are left in place
+		func(context.Context, Store) error {
+			return nil
+		},
+	)
+}
+
+// signinFailures are the failed sign in attempts of a user since they last signed in.
+type signinFailures struct {
+	Count       int       `json:"count"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+// SigninLockedUntil returns when the user may sign in again, or the zero time
+// if they are not locked out.
+func (s *Service) SigninLockedUntil(ctx context.Context, userID influxdb.ID) (time.Time, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var until time.Time
+	err := s.kv.View(ctx, func(tx Tx) error {
+		f, err := s.findSigninFailures(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		if f.LockedUntil.After(s.Now()) {
+			until = f.LockedUntil
+		}
+		return nil
+	})
+	return until, err
+}
+
+// RecordSigninFailure counts a failed sign in attempt of the user. Once the user
+// has failed SigninMaxFailures times they are locked out for SigninLockoutDuration,
+// doubling with each further failure up to MaxSigninLockoutDuration.
+func (s *Service) RecordSigninFailure(ctx context.Context, userID influxdb.ID) (time.Time, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var until time.Time
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		f, err := s.findSigninFailures(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		f.Count++
+		if over := f.Count - s.signinMaxFailures; over >= 0 {
+			f.LockedUntil = s.Now().Add(signinLockoutDuration(s.signinLockoutDuration, over))
+			until = f.LockedUntil
+		}
+		return s.putSigninFailures(ctx, tx, userID, f)
+	})
+	return until, err
+}
+
+// signinLockoutDuration doubles the lockout for each failure over the maximum.
+func signinLockoutDuration(d time.Duration, over int) time.Duration {
+	for i := 0; i < over && d < influxdb.MaxSigninLockoutDuration; i++ {
+		d *= 2
+	}
+	if d > influxdb.MaxSigninLockoutDuration {
+		d = influxdb.MaxSigninLockoutDuration
+	}
+	return d
+}
+
+// ResetSigninFailures forgets the failed sign in attempts of the user.
+func (s *Service) ResetSigninFailures(ctx context.Context, userID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteUserKey(tx, signinFailuresBucket, userID, influxdb.OpResetSigninFailures)
+	})
+}
+
+func (s *Service) findSigninFailures(ctx context.Context, tx Tx, userID influxdb.ID) (*signinFailures, error) {
+	f := &signinFailures{}
+	v, err := s.getUserKey(tx, signinFailuresBucket, userID, influxdb.OpSigninLockedUntil)
+	if err != nil || v == nil {
+		return f, err
+	}
+	if err := json.Unmarshal(v, f); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpSigninLockedUntil,
+			Err:  err,
+		}
+	}
+	return f, nil
+}
+
+func (s *Service) putSigninFailures(ctx context.Context, tx Tx, userID influxdb.ID, f *signinFailures) error {
+	v, err := json.Marshal(f)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpRecordSigninFailure,
+			Err:  err,
+		}
+	}
+	return s.putUserKey(tx, signinFailuresBucket, userID, v, influxdb.OpRecordSigninFailure)
+}
+
+// getUserKey returns the value of the user in the bucket, or nil if it has none.
+func (s *Service) getUserKey(tx Tx, bucket []byte, userID influxdb.ID, op string) ([]byte, error) {
+	k, err := userID.Encode()
+	if err != nil {
+		return nil, InvalidUserIDError(err)
+	}
+	b, err := tx.Bucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   op,
+			Err:  err,
+		}
+	}
+	return v, nil
+}
+
+func (s *Service) putUserKey(tx Tx, bucket []byte, userID influxdb.ID, v []byte, op string) error {
+	k, err := userID.Encode()
+	if err != nil {
+		return InvalidUserIDError(err)
+	}
+	b, err := tx.Bucket(bucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Put(k, v); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   op,
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteUserKey(tx Tx, bucket []byte, userID influxdb.ID, op string) error {
+	k, err := userID.Encode()
+	if err != nil {
+		return InvalidUserIDError(err)
+	}
+	b, err := tx.Bucket(bucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(k); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   op,
+			Err:  err,
+		}
+	}
+	return nil
+}
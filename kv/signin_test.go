@@ -0,0 +1,137 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestService_SigninLockout(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	require.NoError(t, err)
+	defer closeStore()
+
+	svc := kv.NewService(zaptest.NewLogger(t), store, kv.ServiceConfig{
+		SigninMaxFailures:     3,
+		SigninLockoutDuration: time.Minute,
+	})
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+	ctx := context.Background()
+	require.NoError(t, svc.Initialize(ctx))
+
+	user := &influxdb.User{Name: "user"}
+	require.NoError(t, svc.CreateUser(ctx, user))
+
+	for i := 0; i < 2; i++ {
+		until, err := svc.RecordSigninFailure(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, until.IsZero(), "locked out after %d failures", i+1)
+	}
+
+	until, err := svc.RecordSigninFailure(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), until)
+
+	locked, err := svc.SigninLockedUntil(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, until, locked)
+
+	// every further failure doubles the lockout
+	until, err = svc.RecordSigninFailure(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(2*time.Minute), until)
+
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now.Add(3 * time.Minute)}
+	locked, err = svc.SigninLockedUntil(ctx, user.ID)
+	require.NoError(t, err)
+	assert.True(t, locked.IsZero())
+
+	require.NoError(t, svc.ResetSigninFailures(ctx, user.ID))
+	until, err = svc.RecordSigninFailure(ctx, user.ID)
+	require.NoError(t, err)
+	assert.True(t, until.IsZero())
+}
+
+func TestService_MFA(t *testing.T) {
+	store, closeStore, err := NewTestInmemStore(t)
+	require.NoError(t, err)
+	defer closeStore()
+
+	svc := kv.NewService(zaptest.NewLogger(t), store)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: now}
+	ctx := context.Background()
+	require.NoError(t, svc.Initialize(ctx))
+
+	user := &influxdb.User{Name: "user"}
+	require.NoError(t, svc.CreateUser(ctx, user))
+
+	e, err := svc.EnrollMFA(ctx, user.ID, "")
+	require.NoError(t, err)
+	assert.Len(t, e.RecoveryCodes, 10)
+
+	enabled, err := svc.MFAEnabled(ctx, user.ID)
+	require.NoError(t, err)
+	assert.False(t, enabled, "enabled before being confirmed")
+
+	err = svc.ConfirmMFA(ctx, user.ID, "000000")
+	assert.Equal(t, influxdb.EForbidden, influxdb.ErrorCode(err))
+
+	code, err := totp.Code(e.Secret, totp.Step(now))
+	require.NoError(t, err)
+	require.NoError(t, svc.ConfirmMFA(ctx, user.ID, code))
+
+	enabled, err = svc.MFAEnabled(ctx, user.ID)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+
+	t.Run("codes can not be used twice", func(t *testing.T) {
+		err := svc.VerifyMFA(ctx, user.ID, code)
+		assert.Equal(t, influxdb.EForbidden, influxdb.ErrorCode(err))
+
+		svc.TimeGenerator = mock.TimeGenerator{FakeValue: now.Add(totp.Period)}
+		next, err := totp.Code(e.Secret, totp.Step(now.Add(totp.Period)))
+		require.NoError(t, err)
+		assert.NoError(t, svc.VerifyMFA(ctx, user.ID, next))
+	})
+
+	t.Run("recovery codes can be used once", func(t *testing.T) {
+		assert.NoError(t, svc.VerifyMFA(ctx, user.ID, e.RecoveryCodes[0]))
+		err := svc.VerifyMFA(ctx, user.ID, e.RecoveryCodes[0])
+		assert.Equal(t, influxdb.EForbidden, influxdb.ErrorCode(err))
+	})
+
+	t.Run("requires a code to replace the second factor", func(t *testing.T) {
+		_, err := svc.EnrollMFA(ctx, user.ID, "")
+		assert.Equal(t, influxdb.EForbidden, influxdb.ErrorCode(err))
+
+		_, err = svc.EnrollMFA(ctx, user.ID, e.RecoveryCodes[1])
+		require.NoError(t, err)
+	})
+
+	t.Run("disables the second factor", func(t *testing.T) {
+		err := svc.DisableMFA(ctx, user.ID, "000000")
+		assert.Equal(t, influxdb.EForbidden, influxdb.ErrorCode(err))
+		enabled, err := svc.MFAEnabled(ctx, user.ID)
+		require.NoError(t, err)
+		assert.True(t, enabled, "disabled with an incorrect code")
+
+		require.NoError(t, svc.DisableMFA(ctx, user.ID, e.RecoveryCodes[2]))
+
+		enabled, err = svc.MFAEnabled(ctx, user.ID)
+		require.NoError(t, err)
+		assert.False(t, enabled)
+
+		err = svc.VerifyMFA(ctx, user.ID, e.RecoveryCodes[3])
+		assert.Equal(t, influxdb.EInvalid, influxdb.ErrorCode(err))
+	})
+}
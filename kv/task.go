@@ -45,14 +45,21 @@ type kvTask struct {
 	Flux            string                 `json:"flux"`
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
+	Location        string                 `json:"location,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
 	LastRunError    string                 `json:"lastRunError,omitempty"`
+	Alert           *influxdb.TaskAlert    `json:"alert,omitempty"`
 	Offset          influxdb.Duration      `json:"offset,omitempty"`
 	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
 	CreatedAt       time.Time              `json:"createdAt,omitempty"`
 	UpdatedAt       time.Time              `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+
+	// ConsecutiveFailures is the number of runs that failed since the last successful run.
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+	// TemplateID is the task the task was created from, if any.
+	TemplateID influxdb.ID `json:"templateID,omitempty"`
 }
 
 func kvToInfluxTask(k *kvTask) *influxdb.Task {
@@ -68,14 +75,19 @@ func kvToInfluxTask(k *kvTask) *influxdb.Task {
 		Flux:            k.Flux,
 		Every:           k.Every,
 		Cron:            k.Cron,
+		Location:        k.Location,
 		LastRunStatus:   k.LastRunStatus,
 		LastRunError:    k.LastRunError,
+		Alert:           k.Alert,
 		Offset:          k.Offset.Duration,
 		LatestCompleted: k.LatestCompleted,
 		LatestScheduled: k.LatestScheduled,
 		CreatedAt:       k.CreatedAt,
 		UpdatedAt:       k.UpdatedAt,
 		Metadata:        k.Metadata,
+
+		ConsecutiveFailures: k.ConsecutiveFailures,
+		TemplateID:          k.TemplateID,
 	}
 }
 
@@ -593,6 +605,27 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 	// 	return nil, influxdb.ErrInvalidOwnerID
 	// }
 
+	if tc.TemplateID.Valid() {
+		template, err := s.findTaskByID(ctx, tx, tc.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		if template.OrganizationID != org.ID {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "task template must belong to the task's organization",
+			}
+		}
+		tc.Flux = template.Flux
+	}
+
+	if len(tc.Params) > 0 {
+		tc.Flux, err = options.BindParams(tc.Flux, tc.Params)
+		if err != nil {
+			return nil, influxdb.ErrTaskOptionParse(err)
+		}
+	}
+
 	opt, err := options.FromScript(tc.Flux)
 	if err != nil {
 		return nil, influxdb.ErrTaskOptionParse(err)
@@ -602,6 +635,12 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		tc.Status = string(influxdb.TaskActive)
 	}
 
+	if tc.Alert != nil {
+		if err := s.validateTaskAlert(ctx, tx, org.ID, tc.Alert); err != nil {
+			return nil, err
+		}
+	}
+
 	createdAt := s.clock.Now().Truncate(time.Second).UTC()
 	task := &influxdb.Task{
 		ID:              s.IDGenerator.ID(),
@@ -616,6 +655,9 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		Flux:            tc.Flux,
 		Every:           opt.Every.String(),
 		Cron:            opt.Cron,
+		Location:        opt.Location,
+		Alert:           tc.Alert,
+		TemplateID:      tc.TemplateID,
 		CreatedAt:       createdAt,
 		LatestCompleted: createdAt,
 		LatestScheduled: createdAt,
@@ -697,6 +739,28 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 	return task, nil
 }
 
+// validateTaskAlert confirms the alert's notification endpoint exists in the task's organization.
+func (s *Service) validateTaskAlert(ctx context.Context, tx Tx, orgID influxdb.ID, alert *influxdb.TaskAlert) error {
+	if err := alert.Valid(); err != nil {
+		return err
+	}
+	edp, err := s.findNotificationEndpointByID(ctx, tx, alert.EndpointID)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "alert endpoint not found",
+			Err:  err,
+		}
+	}
+	if edp.GetOrgID() != orgID {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "alert endpoint must belong to the task's organization",
+		}
+	}
+	return nil
+}
+
 func (s *Service) createTaskURM(ctx context.Context, tx Tx, t *influxdb.Task) error {
 	// TODO(jsteenb2): should not be getting authorizer inside the store, should terminate at the
 	//  transport layer then pass user id everywhere else.
@@ -754,6 +818,7 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		task.Name = options.Name
 		task.Every = options.Every.String()
 		task.Cron = options.Cron
+		task.Location = options.Location
 
 		var off time.Duration
 		if options.Offset != nil {
@@ -788,6 +853,18 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		task.UpdatedAt = updatedAt
 	}
 
+	if upd.Alert != nil {
+		if upd.Alert.EndpointID.Valid() {
+			if err := s.validateTaskAlert(ctx, tx, task.OrganizationID, upd.Alert); err != nil {
+				return nil, err
+			}
+			task.Alert = upd.Alert
+		} else {
+			task.Alert = nil
+		}
+		task.UpdatedAt = updatedAt
+	}
+
 	if upd.LatestCompleted != nil {
 		// make sure we only update latest completed one way
 		tlc := task.LatestCompleted
@@ -812,6 +889,12 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		} else {
 			task.LastRunError = ""
 		}
+		switch *upd.LastRunStatus {
+		case "failed":
+			task.ConsecutiveFailures++
+		case "success":
+			task.ConsecutiveFailures = 0
+		}
 	}
 
 	// save the updated task
@@ -934,6 +1017,14 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
+	leaseBucket, err := tx.Bucket(taskLeaseBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	if err := leaseBucket.Delete(key); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
 	if err := s.deleteUserResourceMapping(ctx, tx, influxdb.UserResourceMappingFilter{
 		ResourceID: task.ID,
 	}); err != nil {
@@ -1614,15 +1705,45 @@ func (s *Service) AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when
 }
 
 func (s *Service) addRunLog(ctx context.Context, tx Tx, taskID, runID influxdb.ID, when time.Time, log string) error {
+	return s.addRunLogEntry(ctx, tx, taskID, runID, when, influxdb.Log{Message: log})
+}
+
+// AddRunLogEntry adds a structured log entry to the run.
+func (s *Service) AddRunLogEntry(ctx context.Context, taskID, runID influxdb.ID, when time.Time, entry influxdb.Log) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		return s.addRunLogEntry(ctx, tx, taskID, runID, when, entry)
+	})
+}
+
+func (s *Service) addRunLogEntry(ctx context.Context, tx Tx, taskID, runID influxdb.ID, when time.Time, entry influxdb.Log) error {
 	// find run
 	run, err := s.findRunByID(ctx, tx, taskID, runID)
 	if err != nil {
 		return err
 	}
 	// update log
-	l := influxdb.Log{RunID: runID, Time: when.Format(time.RFC3339Nano), Message: log}
-	run.Log = append(run.Log, l)
-	// save run
+	entry.RunID = runID
+	entry.Time = when.Format(time.RFC3339Nano)
+	run.Log = append(run.Log, entry)
+
+	return s.putRun(tx, taskID, run)
+}
+
+// SetRunResult records the captured output and the query statistics of the run.
+func (s *Service) SetRunResult(ctx context.Context, taskID, runID influxdb.ID, output []influxdb.RunOutput, stats *influxdb.RunStatistics) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		run, err := s.findRunByID(ctx, tx, taskID, runID)
+		if err != nil {
+			return err
+		}
+		run.Output = output
+		run.Statistics = stats
+
+		return s.putRun(tx, taskID, run)
+	})
+}
+
+func (s *Service) putRun(tx Tx, taskID influxdb.ID, run *influxdb.Run) error {
 	b, err := tx.Bucket(taskRunBucket)
 	if err != nil {
 		return influxdb.ErrUnexpectedTaskBucketErr(err)
@@ -0,0 +1,100 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var taskLeaseBucket = []byte("taskLeasesv1")
+
+// taskLease records the node that owns the execution of a task until the lease expires.
+type taskLease struct {
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// AcquireTaskLease acquires the lease of a task for owner, or renews it if owner already holds it,
+// so that it expires ttl from now. It returns false when another owner holds an unexpired lease.
+func (s *Service) AcquireTaskLease(ctx context.Context, taskID influxdb.ID, owner string, ttl time.Duration) (bool, error) {
+	var acquired bool
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		var err error
+		acquired, err = s.acquireTaskLease(ctx, tx, taskID, owner, ttl)
+		return err
+	})
+	return acquired, err
+}
+
+func (s *Service) acquireTaskLease(ctx context.Context, tx Tx, taskID influxdb.ID, owner string, ttl time.Duration) (bool, error) {
+	b, err := tx.Bucket(taskLeaseBucket)
+	if err != nil {
+		return false, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	key, err := taskKey(taskID)
+	if err != nil {
+		return false, err
+	}
+
+	now := s.clock.Now().UTC()
+	lease, err := findTaskLease(b, key)
+	if err != nil {
+		return false, err
+	}
+	if lease != nil && lease.Owner != owner && now.Before(lease.ExpiresAt) {
+		return false, nil
+	}
+
+	v, err := json.Marshal(taskLease{Owner: owner, ExpiresAt: now.Add(ttl)})
+	if err != nil {
+		return false, influxdb.ErrInternalTaskServiceError(err)
+	}
+	if err := b.Put(key, v); err != nil {
+		return false, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	return true, nil
+}
+
+// ReleaseTaskLease releases the lease of a task if it is held by owner.
+func (s *Service) ReleaseTaskLease(ctx context.Context, taskID influxdb.ID, owner string) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(taskLeaseBucket)
+		if err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+		key, err := taskKey(taskID)
+		if err != nil {
+			return err
+		}
+
+		lease, err := findTaskLease(b, key)
+		if err != nil {
+			return err
+		}
+		if lease == nil || lease.Owner != owner {
+			return nil
+		}
+		if err := b.Delete(key); err != nil {
+			return influxdb.ErrUnexpectedTaskBucketErr(err)
+		}
+		return nil
+	})
+}
+
+// findTaskLease returns the lease stored at key, or nil if there is none.
+func findTaskLease(b Bucket, key []byte) (*taskLease, error) {
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	lease := &taskLease{}
+	if err := json.Unmarshal(v, lease); err != nil {
+		return nil, influxdb.ErrInternalTaskServiceError(err)
+	}
+	return lease, nil
+}
@@ -13,6 +13,7 @@ import (
 	icontext "github.com/influxdata/influxdb/v2/context"
 	"github.com/influxdata/influxdb/v2/kv"
 	_ "github.com/influxdata/influxdb/v2/query/builtin"
+	"github.com/influxdata/influxdb/v2/task/options"
 	"github.com/influxdata/influxdb/v2/task/servicetest"
 	"go.uber.org/zap/zaptest"
 )
@@ -238,6 +239,125 @@ func TestService_UpdateTask_InactiveToActive(t *testing.T) {
 	}
 }
 
+func TestService_TaskLease(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	c := clock.NewMock()
+	c.Set(time.Unix(1000, 0))
+
+	ts := newService(t, ctx, c)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	task, err := ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	if err != nil {
+		t.Fatal("CreateTask", err)
+	}
+
+	acquire := func(owner string, exp bool) {
+		t.Helper()
+		ok, err := ts.Service.AcquireTaskLease(ctx, task.ID, owner, 10*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != exp {
+			t.Fatalf("expected %s acquiring the lease to be %v, got %v", owner, exp, ok)
+		}
+	}
+
+	acquire("a", true)
+	acquire("b", false)
+
+	// renewing the lease extends it past the original expiry.
+	c.Add(5 * time.Second)
+	acquire("a", true)
+	c.Add(9 * time.Second)
+	acquire("b", false)
+
+	// b takes over once the lease expired.
+	c.Add(2 * time.Second)
+	acquire("b", true)
+	acquire("a", false)
+
+	// only the owner can release the lease.
+	if err := ts.Service.ReleaseTaskLease(ctx, task.ID, "a"); err != nil {
+		t.Fatal(err)
+	}
+	acquire("a", false)
+	if err := ts.Service.ReleaseTaskLease(ctx, task.ID, "b"); err != nil {
+		t.Fatal(err)
+	}
+	acquire("a", true)
+
+	// deleting the task removes its lease.
+	if err := ts.Service.DeleteTask(ctx, task.ID); err != nil {
+		t.Fatal(err)
+	}
+	acquire("b", true)
+}
+
+func TestService_CreateTaskFromTemplate(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	defer ts.Close()
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	template, err := ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+		Flux: `option params = {bucket: "telegraf", limit: 10}
+option task = {name: "a template", every: 1h}
+
+from(bucket: params.bucket) |> range(start: -1h) |> limit(n: params.limit)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	if err != nil {
+		t.Fatal("CreateTask", err)
+	}
+
+	task, err := ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+		TemplateID:     template.ID,
+		Params:         map[string]interface{}{"bucket": "other"},
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	if err != nil {
+		t.Fatal("CreateTask", err)
+	}
+	if task.TemplateID != template.ID {
+		t.Fatalf("expected task to be created from template %s, got %s", template.ID, task.TemplateID)
+	}
+
+	params, err := options.ParamsFromScript(task.Flux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params["bucket"].Default; got != `"other"` {
+		t.Fatalf("expected bucket to be bound to \"other\", got %s", got)
+	}
+	if got := params["limit"].Default; got != "10" {
+		t.Fatalf("expected limit to keep its default, got %s", got)
+	}
+
+	_, err = ts.Service.CreateTask(ctx, influxdb.TaskCreate{
+		TemplateID:     template.ID,
+		Params:         map[string]interface{}{"limit": "ten"},
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	if err == nil {
+		t.Fatal("expected error binding an invalid parameter value")
+	}
+}
+
 func TestTaskRunCancellation(t *testing.T) {
 	store, close, err := NewTestBoltStore(t)
 	if err != nil {
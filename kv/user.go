@@ -451,7 +451,10 @@ func (s *Service) deleteUser(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return err
 	}
 
-	return nil
+	if err := s.deleteUserKey(tx, userMFABucket, id, influxdb.OpDisableMFA); err != nil {
+		return err
+	}
+	return s.deleteUserKey(tx, signinFailuresBucket, id, influxdb.OpResetSigninFailures)
 }
 
 func (s *Service) deleteUsersAuthorizations(ctx context.Context, tx Tx, id influxdb.ID) error {
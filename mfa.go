@@ -0,0 +1,53 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// ops for multi-factor authentication errors.
+const (
+	OpMFAEnabled = "MFAEnabled"
+	OpEnrollMFA  = "EnrollMFA"
+	OpConfirmMFA = "ConfirmMFA"
+	OpVerifyMFA  = "VerifyMFA"
+	OpDisableMFA = "DisableMFA"
+)
+
+// ErrIncorrectMFACode is the error message for one-time codes that do not match.
+const ErrIncorrectMFACode = "the one-time code is incorrect"
+
+// MFAEnrollment is a time-based one-time password (TOTP) second factor being
+// enrolled by a user. It is only returned on enrollment, neither the secret nor
+// the recovery codes can be read again.
+type MFAEnrollment struct {
+	// Secret is the base32 encoded key of the one-time codes.
+	Secret string `json:"secret"`
+	// URL is the otpauth key URI of the secret, for authenticator apps to scan.
+	URL string `json:"url"`
+	// RecoveryCodes may each be used once in place of a one-time code.
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// MFAService manages the second factors users sign in with, in addition to
+// their passwords.
+type MFAService interface {
+	// MFAEnabled returns whether the user signs in with a second factor.
+	MFAEnabled(ctx context.Context, userID ID) (bool, error)
+
+	// EnrollMFA generates a new secret and recovery codes for the user. They take
+	// effect once confirmed, replacing any second factor the user had. A user
+	// with a second factor must provide one of its codes.
+	EnrollMFA(ctx context.Context, userID ID, code string) (*MFAEnrollment, error)
+
+	// ConfirmMFA enables the second factor being enrolled by the user once the
+	// code is one of its one-time codes.
+	ConfirmMFA(ctx context.Context, userID ID, code string) error
+
+	// VerifyMFA checks that the code is a one-time code, or a recovery code, of the
+	// second factor of the user. Each code can be used once.
+	VerifyMFA(ctx context.Context, userID ID, code string) error
+
+	// DisableMFA removes the second factor of the user, once the code is one of
+	// its codes.
+	DisableMFA(ctx context.Context, userID ID, code string) error
+}
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	platform "github.com/influxdata/influxdb/v2"
 )
@@ -20,6 +21,7 @@ type AuthorizationService struct {
 	CreateAuthorizationFn      func(context.Context, *platform.Authorization) error
 	DeleteAuthorizationFn      func(context.Context, platform.ID) error
 	UpdateAuthorizationFn      func(context.Context, platform.ID, *platform.AuthorizationUpdate) (*platform.Authorization, error)
+	RotateAuthorizationFn      func(context.Context, platform.ID, time.Duration) (*platform.Authorization, error)
 }
 
 // NewAuthorizationService returns a mock AuthorizationService where its methods will return
@@ -36,6 +38,9 @@ func NewAuthorizationService() *AuthorizationService {
 		UpdateAuthorizationFn: func(context.Context, platform.ID, *platform.AuthorizationUpdate) (*platform.Authorization, error) {
 			return nil, nil
 		},
+		RotateAuthorizationFn: func(context.Context, platform.ID, time.Duration) (*platform.Authorization, error) {
+			return nil, nil
+		},
 	}
 }
 
@@ -67,3 +72,8 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platf
 func (s *AuthorizationService) UpdateAuthorization(ctx context.Context, id platform.ID, upd *platform.AuthorizationUpdate) (*platform.Authorization, error) {
 	return s.UpdateAuthorizationFn(ctx, id, upd)
 }
+
+// RotateAuthorization issues a new token for the authorization.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, error) {
+	return s.RotateAuthorizationFn(ctx, id, gracePeriod)
+}
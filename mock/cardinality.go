@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+var _ influxdb.CardinalityService = &CardinalityService{}
+
+// CardinalityService is a mock cardinality service.
+type CardinalityService struct {
+	FindBucketCardinalityF func(ctx context.Context, orgID, bucketID influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error)
+}
+
+// NewCardinalityService returns a mock CardinalityService where its methods
+// will return zero values.
+func NewCardinalityService() *CardinalityService {
+	return &CardinalityService{
+		FindBucketCardinalityF: func(ctx context.Context, orgID, bucketID influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error) {
+			return &influxdb.BucketCardinality{BucketID: bucketID}, nil
+		},
+	}
+}
+
+// FindBucketCardinality calls FindBucketCardinalityF.
+func (s *CardinalityService) FindBucketCardinality(ctx context.Context, orgID, bucketID influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error) {
+	return s.FindBucketCardinalityF(ctx, orgID, bucketID, opts)
+}
@@ -0,0 +1,70 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb/v2"
+)
+
+var _ platform.RoleService = (*RoleService)(nil)
+
+// RoleService is a mock implementation of a role service.
+type RoleService struct {
+	FindRoleByIDF     func(ctx context.Context, id platform.ID) (*platform.Role, error)
+	FindRoleByIDCalls SafeCount
+	FindRolesF        func(ctx context.Context, filter platform.RoleFilter, opt ...platform.FindOptions) ([]*platform.Role, int, error)
+	FindRolesCalls    SafeCount
+	CreateRoleF       func(ctx context.Context, r *platform.Role) error
+	CreateRoleCalls   SafeCount
+	UpdateRoleF       func(ctx context.Context, id platform.ID, upd platform.RoleUpdate) (*platform.Role, error)
+	UpdateRoleCalls   SafeCount
+	DeleteRoleF       func(ctx context.Context, id platform.ID) error
+	DeleteRoleCalls   SafeCount
+}
+
+// NewRoleService returns a mock of RoleService where its methods will return zero values.
+func NewRoleService() *RoleService {
+	return &RoleService{
+		FindRoleByIDF: func(ctx context.Context, id platform.ID) (*platform.Role, error) {
+			return nil, nil
+		},
+		FindRolesF: func(ctx context.Context, filter platform.RoleFilter, opt ...platform.FindOptions) ([]*platform.Role, int, error) {
+			return nil, 0, nil
+		},
+		CreateRoleF: func(ctx context.Context, r *platform.Role) error { return nil },
+		UpdateRoleF: func(ctx context.Context, id platform.ID, upd platform.RoleUpdate) (*platform.Role, error) {
+			return nil, nil
+		},
+		DeleteRoleF: func(ctx context.Context, id platform.ID) error { return nil },
+	}
+}
+
+// FindRoleByID returns a single role by ID.
+func (s *RoleService) FindRoleByID(ctx context.Context, id platform.ID) (*platform.Role, error) {
+	defer s.FindRoleByIDCalls.IncrFn()()
+	return s.FindRoleByIDF(ctx, id)
+}
+
+// FindRoles returns a list of roles that match filter and the total count of matching roles.
+func (s *RoleService) FindRoles(ctx context.Context, filter platform.RoleFilter, opt ...platform.FindOptions) ([]*platform.Role, int, error) {
+	defer s.FindRolesCalls.IncrFn()()
+	return s.FindRolesF(ctx, filter, opt...)
+}
+
+// CreateRole creates a new role and sets r.ID with the new identifier.
+func (s *RoleService) CreateRole(ctx context.Context, r *platform.Role) error {
+	defer s.CreateRoleCalls.IncrFn()()
+	return s.CreateRoleF(ctx, r)
+}
+
+// UpdateRole updates a single role with a changeset.
+func (s *RoleService) UpdateRole(ctx context.Context, id platform.ID, upd platform.RoleUpdate) (*platform.Role, error) {
+	defer s.UpdateRoleCalls.IncrFn()()
+	return s.UpdateRoleF(ctx, id, upd)
+}
+
+// DeleteRole removes a role by ID.
+func (s *RoleService) DeleteRole(ctx context.Context, id platform.ID) error {
+	defer s.DeleteRoleCalls.IncrFn()()
+	return s.DeleteRoleF(ctx, id)
+}
@@ -137,6 +137,8 @@ type TaskControlService struct {
 	FinishRunFn        func(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error)
 	UpdateRunStateFn   func(ctx context.Context, taskID, runID influxdb.ID, when time.Time, state influxdb.RunStatus) error
 	AddRunLogFn        func(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error
+	AddRunLogEntryFn   func(ctx context.Context, taskID, runID influxdb.ID, when time.Time, entry influxdb.Log) error
+	SetRunResultFn     func(ctx context.Context, taskID, runID influxdb.ID, output []influxdb.RunOutput, stats *influxdb.RunStatistics) error
 }
 
 func (tcs *TaskControlService) CreateRun(ctx context.Context, taskID influxdb.ID, scheduledFor time.Time, runAt time.Time) (*influxdb.Run, error) {
@@ -160,3 +162,9 @@ func (tcs *TaskControlService) UpdateRunState(ctx context.Context, taskID, runID
 func (tcs *TaskControlService) AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error {
 	return tcs.AddRunLogFn(ctx, taskID, runID, when, log)
 }
+func (tcs *TaskControlService) AddRunLogEntry(ctx context.Context, taskID, runID influxdb.ID, when time.Time, entry influxdb.Log) error {
+	return tcs.AddRunLogEntryFn(ctx, taskID, runID, when, entry)
+}
+func (tcs *TaskControlService) SetRunResult(ctx context.Context, taskID, runID influxdb.ID, output []influxdb.RunOutput, stats *influxdb.RunStatistics) error {
+	return tcs.SetRunResultFn(ctx, taskID, runID, output, stats)
+}
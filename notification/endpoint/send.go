@@ -0,0 +1,204 @@
+package endpoint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+// DefaultPagerDutyURL is the PagerDuty events API messages are posted to.
+const DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+// Message is a notification sent to an endpoint directly from influxdb,
+// rather than from the flux task of a notification rule.
+type Message struct {
+	// Key identifies the incident the message is about,
+	// messages with the same key refer to the same incident.
+	Key string `json:"key"`
+	// Text is the human readable content of the message.
+	Text string `json:"text"`
+	// Resolved marks the message as the recovery of the incident.
+	Resolved bool `json:"resolved"`
+}
+
+// Sender delivers messages to notification endpoints.
+type Sender struct {
+	Client  *http.Client
+	Secrets influxdb.SecretService
+
+	// PagerDutyURL is the events API messages to PagerDuty endpoints are posted to.
+	PagerDutyURL string
+}
+
+// NewSender returns a Sender that resolves the secret fields of endpoints with ss.
+func NewSender(ss influxdb.SecretService) *Sender {
+	return &Sender{
+		Client:       &http.Client{Timeout: 30 * time.Second},
+		Secrets:      ss,
+		PagerDutyURL: DefaultPagerDutyURL,
+	}
+}
+
+// Send delivers msg to the notification endpoint edp.
+func (s *Sender) Send(ctx context.Context, edp influxdb.NotificationEndpoint, msg Message) error {
+	switch e := edp.(type) {
+	case *HTTP:
+		return s.sendHTTP(ctx, e, msg)
+	case *Slack:
+		return s.sendSlack(ctx, e, msg)
+	case *PagerDuty:
+		return s.sendPagerDuty(ctx, e, msg)
+	default:
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("unable to send to notification endpoint type %s", edp.Type()),
+		}
+	}
+}
+
+func (s *Sender) sendHTTP(ctx context.Context, e *HTTP, msg Message) error {
+	var body []byte
+	if e.Method != http.MethodGet {
+		b, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		body = b
+	}
+	req, err := http.NewRequest(e.Method, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+	switch e.AuthMethod {
+	case "basic":
+		username, err := s.secret(ctx, e.GetOrgID(), e.Username)
+		if err != nil {
+			return err
+		}
+		password, err := s.secret(ctx, e.GetOrgID(), e.Password)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(username, password)
+	case "bearer":
+		token, err := s.secret(ctx, e.GetOrgID(), e.Token)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return s.do(ctx, req)
+}
+
+func (s *Sender) sendSlack(ctx context.Context, e *Slack, msg Message) error {
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{Text: msg.Text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.Token.Key != "" {
+		token, err := s.secret(ctx, e.GetOrgID(), e.Token)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return s.do(ctx, req)
+}
+
+func (s *Sender) sendPagerDuty(ctx context.Context, e *PagerDuty, msg Message) error {
+	routingKey, err := s.secret(ctx, e.GetOrgID(), e.RoutingKey)
+	if err != nil {
+		return err
+	}
+	action := "trigger"
+	if msg.Resolved {
+		action = "resolve"
+	}
+	type payload struct {
+		Summary  string `json:"summary"`
+		Source   string `json:"source"`
+		Severity string `json:"severity"`
+	}
+	body, err := json.Marshal(struct {
+		RoutingKey  string  `json:"routing_key"`
+		EventAction string  `json:"event_action"`
+		DedupKey    string  `json:"dedup_key"`
+		ClientURL   string  `json:"client_url,omitempty"`
+		Payload     payload `json:"payload"`
+	}{
+		RoutingKey:  routingKey,
+		EventAction: action,
+		DedupKey:    msg.Key,
+		ClientURL:   e.ClientURL,
+		Payload: payload{
+			Summary:  msg.Text,
+			Source:   "influxdb",
+			Severity: "error",
+		},
+	})
+	if err != nil {
+		return err
+	}
+	u := s.PagerDutyURL
+	if u == "" {
+		u = DefaultPagerDutyURL
+	}
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return s.do(ctx, req)
+}
+
+func (s *Sender) secret(ctx context.Context, orgID influxdb.ID, f influxdb.SecretField) (string, error) {
+	if f.Value != nil {
+		return *f.Value, nil
+	}
+	if s.Secrets == nil {
+		return "", &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  "unable to load notification endpoint secret without a secret service",
+		}
+	}
+	return s.Secrets.LoadSecret(ctx, orgID, f.Key)
+}
+
+func (s *Sender) do(ctx context.Context, req *http.Request) error {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Msg:  fmt.Sprintf("notification endpoint responded with status %d: %s", resp.StatusCode, body),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package endpoint_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+)
+
+func TestSender_Send(t *testing.T) {
+	type request struct {
+		Auth string
+		Body map[string]interface{}
+	}
+	var got request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = request{Auth: r.Header.Get("Authorization")}
+		if err := json.NewDecoder(r.Body).Decode(&got.Body); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	secrets := mock.NewSecretService()
+	secrets.LoadSecretFn = func(ctx context.Context, orgID influxdb.ID, k string) (string, error) {
+		return k + "-value", nil
+	}
+	sender := endpoint.NewSender(secrets)
+	sender.PagerDutyURL = srv.URL
+
+	msg := endpoint.Message{Key: "task-1", Text: "failed", Resolved: true}
+	tests := []struct {
+		name string
+		edp  influxdb.NotificationEndpoint
+		want request
+	}{
+		{
+			name: "http bearer",
+			edp: &endpoint.HTTP{
+				Base:       goodBase,
+				URL:        srv.URL,
+				Method:     http.MethodPost,
+				AuthMethod: "bearer",
+				Token:      influxdb.SecretField{Key: "token"},
+			},
+			want: request{
+				Auth: "Bearer token-value",
+				Body: map[string]interface{}{"key": "task-1", "text": "failed", "resolved": true},
+			},
+		},
+		{
+			name: "slack",
+			edp: &endpoint.Slack{
+				Base: goodBase,
+				URL:  srv.URL,
+			},
+			want: request{
+				Body: map[string]interface{}{"text": "failed"},
+			},
+		},
+		{
+			name: "pagerduty",
+			edp: &endpoint.PagerDuty{
+				Base:       goodBase,
+				ClientURL:  "http://localhost:8086",
+				RoutingKey: influxdb.SecretField{Key: "routing"},
+			},
+			want: request{
+				Body: map[string]interface{}{
+					"routing_key":  "routing-value",
+					"event_action": "resolve",
+					"dedup_key":    "task-1",
+					"client_url":   "http://localhost:8086",
+					"payload": map[string]interface{}{
+						"summary":  "failed",
+						"source":   "influxdb",
+						"severity": "error",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = request{}
+			if err := sender.Send(context.Background(), tt.edp, msg); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatalf("unexpected request -want/+got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSender_SendError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	edp := &endpoint.HTTP{
+		Base:       goodBase,
+		URL:        srv.URL,
+		Method:     http.MethodPost,
+		AuthMethod: "none",
+	}
+	if err := endpoint.NewSender(nil).Send(context.Background(), edp, endpoint.Message{Text: "failed"}); err == nil {
+		t.Fatal("expected an error for a failed response")
+	}
+}
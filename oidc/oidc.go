@@ -0,0 +1,254 @@
+// Package oidc signs users in with an OpenID Connect provider,
+// using the authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"golang.org/x/oauth2"
+)
+
+// Default claims users are mapped from.
+const (
+	DefaultUsernameClaim = "preferred_username"
+	DefaultOrgsClaim     = "groups"
+)
+
+// Config configures the OpenID Connect provider users sign in with.
+type Config struct {
+	// Issuer is the URL of the provider, its configuration is discovered
+	// from Issuer + "/.well-known/openid-configuration".
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the URL the provider sends users back to once they signed in,
+	// it must route to the sign in callback of influxd.
+	RedirectURL string
+	// Scopes are requested in addition to the openid scope.
+	Scopes []string
+
+	// UsernameClaim is the claim of the ID token holding the name of the user.
+	UsernameClaim string
+	// OrgsClaim is the claim of the ID token holding the names of the organizations the user is a member of.
+	OrgsClaim string
+}
+
+// Identity is a user, as identified by the provider.
+type Identity struct {
+	// Subject uniquely identifies the user at the provider.
+	Subject  string
+	Username string
+	Orgs     []string
+}
+
+// Provider signs users in with an OpenID Connect provider.
+type Provider struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+
+	mu sync.Mutex
+	// oauth2 is set once the configuration of the provider was discovered.
+	oauth2 *oauth2.Config
+}
+
+// NewProvider returns a Provider for c, which talks to the provider with client.
+// The configuration of the provider is discovered on first use.
+func NewProvider(c Config, client *http.Client) (*Provider, error) {
+	if c.Issuer == "" || c.ClientID == "" || c.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc issuer, client id and redirect url are required")
+	}
+	if c.UsernameClaim == "" {
+		c.UsernameClaim = DefaultUsernameClaim
+	}
+	if c.OrgsClaim == "" {
+		c.OrgsClaim = DefaultOrgsClaim
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Provider{
+		config: c,
+		client: client,
+		now:    time.Now,
+	}, nil
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// oauth2Config returns the oauth2 configuration of the provider, discovering it if needed.
+func (p *Provider) oauth2Config(ctx context.Context) (*oauth2.Config, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.oauth2 != nil {
+		return p.oauth2, nil
+	}
+
+	u := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to discover oidc provider: unexpected status %d", resp.StatusCode)
+	}
+
+	var d discovery
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, fmt.Errorf("failed to decode oidc provider configuration: %v", err)
+	}
+	if d.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("oidc provider issuer %q does not match %q", d.Issuer, p.config.Issuer)
+	}
+
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       append([]string{"openid"}, p.config.Scopes...),
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  d.AuthorizationEndpoint,
+			TokenURL: d.TokenEndpoint,
+		},
+	}
+	return p.oauth2, nil
+}
+
+// NewSecret returns a random string, to be used as state, nonce or PKCE verifier of a sign in.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge returns the S256 PKCE challenge of verifier.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the URL of the provider users are sent to, to sign in.
+// The provider sends them back to the redirect url with state and an authorization code
+// that is exchanged, together with verifier, for their identity.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	c, err := p.oauth2Config(ctx)
+	if err != nil {
+		return "", err
+	}
+	return c.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("nonce", nonce),
+		oauth2.SetAuthURLParam("code_challenge", CodeChallenge(verifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
+
+// Exchange exchanges the authorization code for the identity of the user that signed in,
+// nonce and verifier must be the ones the sign in was started with.
+func (p *Provider) Exchange(ctx context.Context, code, nonce, verifier string) (*Identity, error) {
+	c, err := p.oauth2Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tok, err := c.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.client), code,
+		oauth2.SetAuthURLParam("code_verifier", verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %v", err)
+	}
+	raw, ok := tok.Extra("id_token").(string)
+	if !ok || raw == "" {
+		return nil, fmt.Errorf("token response has no id token")
+	}
+
+	// The id token is received directly from the token endpoint of the provider,
+	// so the TLS connection authenticates it in place of its signature (OpenID Connect Core 3.1.3.7).
+	claims := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(raw, claims); err != nil {
+		return nil, fmt.Errorf("failed to parse id token: %v", err)
+	}
+	if err := p.validate(claims, nonce); err != nil {
+		return nil, err
+	}
+	return p.identity(claims)
+}
+
+func (p *Provider) validate(claims jwt.MapClaims, nonce string) error {
+	if !claims.VerifyIssuer(p.config.Issuer, true) {
+		return fmt.Errorf("id token was not issued by %s", p.config.Issuer)
+	}
+	if !audienceContains(claims["aud"], p.config.ClientID) {
+		return fmt.Errorf("id token is not intended for client %s", p.config.ClientID)
+	}
+	if !claims.VerifyExpiresAt(p.now().Unix(), true) {
+		return fmt.Errorf("id token expired")
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return fmt.Errorf("id token nonce does not match the sign in")
+	}
+	return nil
+}
+
+// audienceContains returns true if the aud claim, either a string or an array of strings, contains clientID.
+func audienceContains(aud interface{}, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, a := range aud {
+			if a == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *Provider) identity(claims jwt.MapClaims) (*Identity, error) {
+	id := &Identity{}
+	id.Subject, _ = claims["sub"].(string)
+	if id.Subject == "" {
+		return nil, fmt.Errorf("id token has no subject")
+	}
+	id.Username, _ = claims[p.config.UsernameClaim].(string)
+	if id.Username == "" {
+		return nil, fmt.Errorf("id token has no %s claim", p.config.UsernameClaim)
+	}
+
+	// providers send groups either as an array or as a comma separated string.
+	switch orgs := claims[p.config.OrgsClaim].(type) {
+	case []interface{}:
+		for _, o := range orgs {
+			if s, ok := o.(string); ok && s != "" {
+				id.Orgs = append(id.Orgs, s)
+			}
+		}
+	case string:
+		for _, o := range strings.Split(orgs, ",") {
+			if o = strings.TrimSpace(o); o != "" {
+				id.Orgs = append(id.Orgs, o)
+			}
+		}
+	}
+	return id, nil
+}
@@ -0,0 +1,139 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2/oidc"
+	"github.com/influxdata/influxdb/v2/oidc/oidctest"
+)
+
+func newProvider(t *testing.T, idp *oidctest.Server) *oidc.Provider {
+	t.Helper()
+	p, err := oidc.NewProvider(oidc.Config{
+		Issuer:       idp.Issuer(),
+		ClientID:     idp.ClientID,
+		ClientSecret: idp.ClientSecret,
+		RedirectURL:  "http://influxd.test/api/v2/signin/oidc/callback",
+	}, idp.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	idp := oidctest.NewServer("influxd", "secret")
+	defer idp.Close()
+	p := newProvider(t, idp)
+	ctx := context.Background()
+
+	signIn := func(t *testing.T, nonce, verifier string, claims map[string]interface{}) string {
+		t.Helper()
+		authURL, err := p.AuthCodeURL(ctx, "state", nonce, verifier)
+		if err != nil {
+			t.Fatal(err)
+		}
+		callback, err := idp.SignIn(authURL, claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := url.Parse(callback)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := u.Query().Get("state"); got != "state" {
+			t.Fatalf("unexpected state %q", got)
+		}
+		return u.Query().Get("code")
+	}
+
+	t.Run("signs in", func(t *testing.T) {
+		code := signIn(t, "nonce", "verifier", map[string]interface{}{
+			"sub":                "123",
+			"preferred_username": "jane",
+			"groups":             []string{"ops", "dev"},
+		})
+		id, err := p.Exchange(ctx, code, "nonce", "verifier")
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := &oidc.Identity{Subject: "123", Username: "jane", Orgs: []string{"ops", "dev"}}
+		if !reflect.DeepEqual(id, exp) {
+			t.Fatalf("unexpected identity -got/+exp\n%+v\n%+v", id, exp)
+		}
+	})
+
+	t.Run("comma separated groups", func(t *testing.T) {
+		code := signIn(t, "nonce", "verifier", map[string]interface{}{
+			"sub":                "123",
+			"preferred_username": "jane",
+			"groups":             "ops, dev",
+		})
+		id, err := p.Exchange(ctx, code, "nonce", "verifier")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := []string{"ops", "dev"}; !reflect.DeepEqual(id.Orgs, exp) {
+			t.Fatalf("unexpected orgs %v, expected %v", id.Orgs, exp)
+		}
+	})
+
+	t.Run("wrong verifier", func(t *testing.T) {
+		code := signIn(t, "nonce", "verifier", map[string]interface{}{"sub": "123", "preferred_username": "jane"})
+		if _, err := p.Exchange(ctx, code, "nonce", "other"); err == nil {
+			t.Fatal("expected the exchange to fail")
+		}
+	})
+
+	t.Run("wrong nonce", func(t *testing.T) {
+		code := signIn(t, "nonce", "verifier", map[string]interface{}{"sub": "123", "preferred_username": "jane"})
+		if _, err := p.Exchange(ctx, code, "other", "verifier"); err == nil {
+			t.Fatal("expected the exchange to fail")
+		}
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		code := signIn(t, "nonce", "verifier", map[string]interface{}{"sub": "123", "preferred_username": "jane", "aud": "other"})
+		if _, err := p.Exchange(ctx, code, "nonce", "verifier"); err == nil {
+			t.Fatal("expected the exchange to fail")
+		}
+	})
+
+	t.Run("missing username", func(t *testing.T) {
+		code := signIn(t, "nonce", "verifier", map[string]interface{}{"sub": "123"})
+		if _, err := p.Exchange(ctx, code, "nonce", "verifier"); err == nil {
+			t.Fatal("expected the exchange to fail")
+		}
+	})
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	idp := oidctest.NewServer("influxd", "secret")
+	defer idp.Close()
+	p := newProvider(t, idp)
+
+	authURL, err := p.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	for k, exp := range map[string]string{
+		"client_id":             "influxd",
+		"scope":                 "openid",
+		"state":                 "state",
+		"nonce":                 "nonce",
+		"code_challenge":        oidc.CodeChallenge("verifier"),
+		"code_challenge_method": "S256",
+	} {
+		if got := q.Get(k); got != exp {
+			t.Errorf("unexpected %s %q, expected %q", k, got, exp)
+		}
+	}
+}
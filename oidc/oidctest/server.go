@@ -0,0 +1,164 @@
+// Package oidctest provides a stub OpenID Connect provider to test sign ins against.
+package oidctest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/influxdata/influxdb/v2/oidc"
+)
+
+// Server is an OpenID Connect provider serving the discovery and token endpoints,
+// users sign in at it with SignIn rather than through its authorization endpoint.
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	mu     sync.Mutex
+	grants map[string]grant
+	nextID int
+}
+
+type grant struct {
+	redirectURI string
+	challenge   string
+	nonce       string
+	claims      map[string]interface{}
+}
+
+// NewServer starts a provider that accepts the client identified by clientID and clientSecret.
+// Callers must Close it once done.
+func NewServer(clientID, clientSecret string) *Server {
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		grants:       make(map[string]grant),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("/token", s.handleToken)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Issuer is the issuer the provider is configured with.
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// SignIn signs a user with the given id token claims in at the authorization URL the client sent them to,
+// and returns the URL the provider sends the user back to.
+// The claims must at least hold the subject of the user.
+func (s *Server) SignIn(authURL string, claims map[string]interface{}) (string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	switch {
+	case q.Get("client_id") != s.ClientID:
+		return "", fmt.Errorf("unknown client %q", q.Get("client_id"))
+	case q.Get("response_type") != "code":
+		return "", fmt.Errorf("unsupported response type %q", q.Get("response_type"))
+	case q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "":
+		return "", fmt.Errorf("missing S256 code challenge")
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	code := fmt.Sprintf("code-%d", s.nextID)
+	s.grants[code] = grant{
+		redirectURI: q.Get("redirect_uri"),
+		challenge:   q.Get("code_challenge"),
+		nonce:       q.Get("nonce"),
+		claims:      claims,
+	}
+	s.mu.Unlock()
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		return "", err
+	}
+	rq := redirect.Query()
+	rq.Set("code", code)
+	rq.Set("state", q.Get("state"))
+	redirect.RawQuery = rq.Encode()
+	return redirect.String(), nil
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"issuer":                 s.Issuer(),
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+	})
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != s.ClientID || clientSecret != s.ClientSecret {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	s.mu.Lock()
+	g, ok := s.grants[code]
+	delete(s.grants, code)
+	s.mu.Unlock()
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("redirect_uri") != g.redirectURI ||
+		oidc.CodeChallenge(r.PostForm.Get("code_verifier")) != g.challenge {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss": s.Issuer(),
+		"aud": s.ClientID,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	if g.nonce != "" {
+		claims["nonce"] = g.nonce
+	}
+	for k, v := range g.claims {
+		claims[k] = v
+	}
+	idToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.ClientSecret))
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "access-" + code,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func tokenError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
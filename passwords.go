@@ -1,6 +1,11 @@
 package influxdb
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
 
 // PasswordsService is the service for managing basic auth passwords.
 type PasswordsService interface {
@@ -13,3 +18,60 @@ type PasswordsService interface {
 	// updates to the new password.
 	CompareAndSetPassword(ctx context.Context, userID ID, old, new string) error
 }
+
+// DefaultPasswordPolicy only requires passwords to be 8 characters long.
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: 8}
+
+// PasswordPolicy is the requirements the passwords of users must meet.
+type PasswordPolicy struct {
+	MinLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	// RequireSymbol requires a character that is neither a letter nor a digit.
+	RequireSymbol bool
+}
+
+// Validate returns an error describing the first requirement of the policy
+// the password does not meet.
+func (p PasswordPolicy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("passwords must be at least %d characters long", p.MinLength),
+		}
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r):
+			symbol = true
+		}
+	}
+
+	requirements := []struct {
+		required, met bool
+		msg           string
+	}{
+		{p.RequireUppercase, upper, "passwords must contain an uppercase letter"},
+		{p.RequireLowercase, lower, "passwords must contain a lowercase letter"},
+		{p.RequireDigit, digit, "passwords must contain a digit"},
+		{p.RequireSymbol, symbol, "passwords must contain a symbol"},
+	}
+	for _, req := range requirements {
+		if req.required && !req.met {
+			return &Error{
+				Code: EInvalid,
+				Msg:  req.msg,
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := influxdb.PasswordPolicy{
+		MinLength:        8,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+	}
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "valid password",
+			password: "Passw0rd!",
+		},
+		{
+			name:     "length is counted in characters",
+			password: "Pässwö1!",
+		},
+		{
+			name:     "password too short",
+			password: "Pa0!",
+			wantErr:  true,
+		},
+		{
+			name:     "password requires an uppercase letter",
+			password: "passw0rd!",
+			wantErr:  true,
+		},
+		{
+			name:     "password requires a lowercase letter",
+			password: "PASSW0RD!",
+			wantErr:  true,
+		},
+		{
+			name:     "password requires a digit",
+			password: "Password!",
+			wantErr:  true,
+		},
+		{
+			name:     "password requires a symbol",
+			password: "Passw0rd1",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("Validate() error code = %s, want %s", influxdb.ErrorCode(err), influxdb.EInvalid)
+			}
+		})
+	}
+}
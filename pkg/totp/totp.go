@@ -0,0 +1,101 @@
+// Package totp implements the time-based one-time passwords of RFC 6238, with
+// the parameters authenticator apps expect: HMAC-SHA1, 6 digits and 30 second steps.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Digits is the length of a code.
+	Digits = 6
+	// Period is how long each code is valid for.
+	Period = 30 * time.Second
+	// Skew is the number of steps either side of the current one whose codes
+	// are accepted, allowing for clock drift and slow typing.
+	Skew = 1
+
+	secretSize = 20
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// Step returns the time step of t.
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period/time.Second)
+}
+
+// Code returns the code of the secret for the time step.
+func Code(secret string, step int64) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid secret: %v", err)
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0xf
+	v := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, v%1000000), nil
+}
+
+// Validate returns the time step of the code if it is a code of the secret at
+// t, or within Skew steps of it.
+func Validate(secret, code string, t time.Time) (int64, bool, error) {
+	if len(code) != Digits {
+		return 0, false, nil
+	}
+
+	now := Step(t)
+	for step := now - Skew; step <= now+Skew; step++ {
+		c, err := Code(secret, step)
+		if err != nil {
+			return 0, false, err
+		}
+		if subtle.ConstantTimeCompare([]byte(c), []byte(code)) == 1 {
+			return step, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// KeyURI returns the otpauth URI of the secret for the account at the issuer,
+// which authenticator apps add when scanned from a QR code.
+func KeyURI(issuer, account, secret string) string {
+	u := url.URL{
+		Scheme: "otpauth",
+		Host:   "totp",
+		Path:   "/" + issuer + ":" + account,
+	}
+	qp := url.Values{}
+	qp.Set("secret", secret)
+	qp.Set("issuer", issuer)
+	qp.Set("algorithm", "SHA1")
+	qp.Set("digits", fmt.Sprint(Digits))
+	qp.Set("period", fmt.Sprint(int(Period/time.Second)))
+	u.RawQuery = qp.Encode()
+	return u.String()
+}
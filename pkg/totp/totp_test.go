@@ -0,0 +1,69 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/pkg/totp"
+)
+
+// rfcSecret is the SHA1 key of the test vectors of RFC 6238, appendix B.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+	for _, tt := range tests {
+		code, err := totp.Code(rfcSecret, totp.Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != tt.code {
+			t.Errorf("code at %d = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := totp.Step(now)
+	code, err := totp.Code(rfcSecret, step-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok, err := totp.Validate(rfcSecret, code, now); err != nil || !ok || got != step-1 {
+		t.Errorf("Validate of the previous code = %d, %v, %v; want %d, true, nil", got, ok, err, step-1)
+	}
+	if _, ok, _ := totp.Validate(rfcSecret, code, now.Add(2*totp.Period)); ok {
+		t.Error("Validate accepted a code outside of the skew")
+	}
+	if _, ok, _ := totp.Validate(rfcSecret, "12345", now); ok {
+		t.Error("Validate accepted a short code")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	s, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := totp.Code(s, 1); err != nil {
+		t.Errorf("generated secret is invalid: %v", err)
+	}
+
+	uri := totp.KeyURI("InfluxDB", "user", s)
+	if !strings.HasPrefix(uri, "otpauth://totp/InfluxDB:user?") || !strings.Contains(uri, "secret="+s) {
+		t.Errorf("unexpected key uri %s", uri)
+	}
+}
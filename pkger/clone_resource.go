@@ -57,6 +57,7 @@ var kindPriorities = map[Kind]int{
 	KindVariable:                      12,
 	KindDashboard:                     13,
 	KindTelegraf:                      14,
+	KindRole:                          15,
 }
 
 type exportKey struct {
@@ -84,6 +85,7 @@ type resourceExporter struct {
 	labelSVC    influxdb.LabelService
 	endpointSVC influxdb.NotificationEndpointService
 	ruleSVC     influxdb.NotificationRuleStore
+	roleSVC     influxdb.RoleService
 	taskSVC     influxdb.TaskService
 	teleSVC     influxdb.TelegrafConfigStore
 	varSVC      influxdb.VariableService
@@ -101,6 +103,7 @@ func newResourceExporter(svc *Service) *resourceExporter {
 		labelSVC:    svc.labelSVC,
 		endpointSVC: svc.endpointSVC,
 		ruleSVC:     svc.ruleSVC,
+		roleSVC:     svc.roleSVC,
 		taskSVC:     svc.taskSVC,
 		teleSVC:     svc.teleSVC,
 		varSVC:      svc.varSVC,
@@ -248,6 +251,36 @@ func (ex *resourceExporter) resourceCloneToKind(ctx context.Context, r ResourceT
 		endpointObjectName := object.Name()
 
 		mapResource(rule.GetOrgID(), rule.GetID(), KindNotificationRule, NotificationRuleToObject(r.Name, endpointObjectName, rule))
+	case r.Kind.is(KindRole):
+		role, err := ex.roleSVC.FindRoleByID(ctx, r.ID)
+		if err != nil {
+			return err
+		}
+
+		bucketPkgNames := make(map[influxdb.ID]string)
+		for _, p := range role.Permissions {
+			if p.Resource.ID == nil {
+				continue
+			}
+			if p.Resource.Type != influxdb.BucketsResourceType {
+				return fmt.Errorf("role permission %s is restricted to a resource other than a bucket", p)
+			}
+
+			bkt, err := ex.bucketSVC.FindBucketByID(ctx, *p.Resource.ID)
+			if err != nil {
+				return err
+			}
+
+			bucketKey := newExportKey(bkt.OrgID, uniqByNameResID, KindBucket, bkt.Name)
+			object, ok := ex.mObjects[bucketKey]
+			if !ok {
+				mapResource(bkt.OrgID, uniqByNameResID, KindBucket, BucketToObject("", *bkt))
+				object = ex.mObjects[bucketKey]
+			}
+			bucketPkgNames[bkt.ID] = object.Name()
+		}
+
+		mapResource(role.OrgID, uniqByNameResID, KindRole, RoleToObject(r.Name, bucketPkgNames, *role))
 	case r.Kind.is(KindTask):
 		t, err := ex.taskSVC.FindTaskByID(ctx, r.ID)
 		if err != nil {
@@ -927,6 +960,32 @@ func taskToObject(t influxdb.Task, name string) Object {
 	return o
 }
 
+// RoleToObject converts an influxdb.Role into an Object. The bucket pkg names
+// are the metadata.name of the buckets the permissions are restricted to.
+func RoleToObject(name string, bucketPkgNames map[influxdb.ID]string, r influxdb.Role) Object {
+	if name == "" {
+		name = r.Name
+	}
+
+	o := newObject(KindRole, name)
+	assignNonZeroStrings(o.Spec, map[string]string{fieldDescription: r.Description})
+
+	perms := make([]Resource, 0, len(r.Permissions))
+	for _, p := range r.Permissions {
+		res := Resource{fieldType: string(p.Resource.Type)}
+		if p.Resource.ID != nil {
+			res[fieldName] = bucketPkgNames[*p.Resource.ID]
+		}
+		perms = append(perms, Resource{
+			fieldRolePermissionAction:   string(p.Action),
+			fieldRolePermissionResource: res,
+		})
+	}
+	o.Spec[fieldRolePermissions] = perms
+
+	return o
+}
+
 func telegrafToObject(t influxdb.TelegrafConfig, name string) Object {
 	if name == "" {
 		name = t.Name
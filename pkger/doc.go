@@ -48,7 +48,7 @@ You may use the following to dry run a package within your organization:
 
 The diff provided here is a diff of the existing state of the platform for
 your organization and the concluding the state after the application of a
-package. All buckets, labels, and variables, when given a name that already
+package. All buckets, labels, roles, and variables, when given a name that already
 exists, will not create a new resource, but rather, will edit the existing
 resource. If this is not a desired result, then rename your bucket to something
 else to avoid the imposed changes applying this package would incur. The summary
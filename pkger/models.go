@@ -17,6 +17,7 @@ import (
 	icheck "github.com/influxdata/influxdb/v2/notification/check"
 	"github.com/influxdata/influxdb/v2/notification/endpoint"
 	"github.com/influxdata/influxdb/v2/notification/rule"
+	"github.com/influxdata/influxdb/v2/task/options"
 )
 
 // Package kind types.
@@ -34,6 +35,7 @@ const (
 	KindNotificationEndpointSlack     Kind = "NotificationEndpointSlack"
 	KindNotificationRule              Kind = "NotificationRule"
 	KindPackage                       Kind = "Package"
+	KindRole                          Kind = "Role"
 	KindTask                          Kind = "Task"
 	KindTelegraf                      Kind = "Telegraf"
 	KindVariable                      Kind = "Variable"
@@ -51,6 +53,7 @@ var kinds = map[Kind]bool{
 	KindNotificationEndpointPagerDuty: true,
 	KindNotificationEndpointSlack:     true,
 	KindNotificationRule:              true,
+	KindRole:                          true,
 	KindTask:                          true,
 	KindTelegraf:                      true,
 	KindVariable:                      true,
@@ -99,6 +102,8 @@ func (k Kind) ResourceType() influxdb.ResourceType {
 		return influxdb.NotificationEndpointResourceType
 	case KindNotificationRule:
 		return influxdb.NotificationRuleResourceType
+	case KindRole:
+		return influxdb.RolesResourceType
 	case KindTask:
 		return influxdb.TasksResourceType
 	case KindTelegraf:
@@ -158,6 +163,7 @@ type Diff struct {
 	LabelMappings         []DiffLabelMapping         `json:"labelMappings"`
 	NotificationEndpoints []DiffNotificationEndpoint `json:"notificationEndpoints"`
 	NotificationRules     []DiffNotificationRule     `json:"notificationRules"`
+	Roles                 []DiffRole                 `json:"roles"`
 	Tasks                 []DiffTask                 `json:"tasks"`
 	Telegrafs             []DiffTelegraf             `json:"telegrafConfigs"`
 	Variables             []DiffVariable             `json:"variables"`
@@ -178,6 +184,12 @@ func (d Diff) HasConflicts() bool {
 		}
 	}
 
+	for _, r := range d.Roles {
+		if r.hasConflict() {
+			return true
+		}
+	}
+
 	for _, v := range d.Variables {
 		if v.hasConflict() {
 			return true
@@ -565,6 +577,56 @@ func newDiffNotificationRule(r *notificationRule, iEndpoint influxdb.Notificatio
 	return sum
 }
 
+type (
+	// DiffRole is a diff of an individual role.
+	DiffRole struct {
+		DiffIdentifier
+
+		New DiffRoleValues  `json:"new"`
+		Old *DiffRoleValues `json:"old,omitempty"` // using omitempty here to signal there was no prev state with a nil
+	}
+
+	// DiffRoleValues are the varying values for a role.
+	DiffRoleValues struct {
+		Name        string                  `json:"name"`
+		Description string                  `json:"description"`
+		Permissions []SummaryRolePermission `json:"permissions"`
+	}
+)
+
+func newDiffRole(r *role, ir *influxdb.Role) DiffRole {
+	diff := DiffRole{
+		DiffIdentifier: DiffIdentifier{
+			ID:      SafeID(r.ID()),
+			Remove:  r.shouldRemove,
+			PkgName: r.PkgName(),
+		},
+		New: DiffRoleValues{
+			Name:        r.Name(),
+			Description: r.Description,
+			Permissions: r.summarizePermissions(),
+		},
+	}
+	if ir != nil {
+		diff.ID = SafeID(ir.ID)
+		diff.Old = &DiffRoleValues{
+			Name:        ir.Name,
+			Description: ir.Description,
+			Permissions: toSummaryRolePermissions(ir.Permissions),
+		}
+	}
+	return diff
+}
+
+func (d DiffRole) hasConflict() bool {
+	if d.IsNew() || d.Old == nil {
+		return false
+	}
+	return d.Old.Name != d.New.Name ||
+		d.Old.Description != d.New.Description ||
+		!reflect.DeepEqual(withoutResourceNames(d.Old.Permissions), withoutResourceNames(d.New.Permissions))
+}
+
 type (
 	// DiffTask is a diff of an individual task.
 	DiffTask struct {
@@ -699,6 +761,7 @@ type Summary struct {
 	LabelMappings         []SummaryLabelMapping         `json:"labelMappings"`
 	MissingEnvs           []string                      `json:"missingEnvRefs"`
 	MissingSecrets        []string                      `json:"missingSecrets"`
+	Roles                 []SummaryRole                 `json:"roles"`
 	Tasks                 []SummaryTask                 `json:"summaryTask"`
 	TelegrafConfigs       []SummaryTelegraf             `json:"telegrafConfigs"`
 	Variables             []SummaryVariable             `json:"variables"`
@@ -929,10 +992,59 @@ type SummaryTask struct {
 	Offset      string          `json:"offset"`
 	Query       string          `json:"query"`
 	Status      influxdb.Status `json:"status"`
+	// Params are the values bound to the parameters the query declares, an environment
+	// reference without a value is shown as its $key.
+	Params map[string]interface{} `json:"params,omitempty"`
 
 	LabelAssociations []SummaryLabel `json:"labelAssociations"`
 }
 
+// SummaryRole provides a summary of a pkg role.
+type SummaryRole struct {
+	ID          SafeID                  `json:"id,omitempty"`
+	OrgID       SafeID                  `json:"orgID,omitempty"`
+	PkgName     string                  `json:"pkgName"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Permissions []SummaryRolePermission `json:"permissions"`
+}
+
+// SummaryRolePermission provides a summary of a permission a role grants within
+// its organization. The resource name is the name of the bucket the permission
+// is restricted to, if any.
+type SummaryRolePermission struct {
+	Action       influxdb.Action       `json:"action"`
+	ResourceType influxdb.ResourceType `json:"resourceType"`
+	ResourceID   SafeID                `json:"resourceID,omitempty"`
+	ResourceName string                `json:"resourceName,omitempty"`
+}
+
+func toSummaryRolePermissions(ps []influxdb.Permission) []SummaryRolePermission {
+	perms := make([]SummaryRolePermission, 0, len(ps))
+	for _, p := range ps {
+		perm := SummaryRolePermission{
+			Action:       p.Action,
+			ResourceType: p.Resource.Type,
+		}
+		if p.Resource.ID != nil {
+			perm.ResourceID = SafeID(*p.Resource.ID)
+		}
+		perms = append(perms, perm)
+	}
+	return perms
+}
+
+// withoutResourceNames drops the names of the resources, which are not known for
+// the permissions of an existing role, so permissions can be compared by id.
+func withoutResourceNames(perms []SummaryRolePermission) []SummaryRolePermission {
+	out := make([]SummaryRolePermission, 0, len(perms))
+	for _, p := range perms {
+		p.ResourceName = ""
+		out = append(out, p)
+	}
+	return out
+}
+
 // SummaryTelegraf provides a summary of a pkg telegraf config.
 type SummaryTelegraf struct {
 	TelegrafConfig    influxdb.TelegrafConfig `json:"telegrafConfig"`
@@ -2051,7 +2163,8 @@ func (r mapperNotificationRules) Len() int {
 }
 
 const (
-	fieldTaskCron = "cron"
+	fieldTaskCron   = "cron"
+	fieldTaskParams = "params"
 )
 
 type task struct {
@@ -2065,6 +2178,7 @@ type task struct {
 	offset      time.Duration
 	query       string
 	status      string
+	params      map[string]*references
 
 	labels sortedLabels
 
@@ -2140,11 +2254,42 @@ func (t *task) summarize() SummaryTask {
 		Offset:      durToStr(t.offset),
 		Query:       t.query,
 		Status:      t.Status(),
+		Params:      t.summarizeParams(),
 
 		LabelAssociations: toSummaryLabels(t.labels...),
 	}
 }
 
+// paramValues returns the values bound to the parameters of the task,
+// leaving out environment references that have no value.
+func (t *task) paramValues() map[string]interface{} {
+	if len(t.params) == 0 {
+		return nil
+	}
+	values := make(map[string]interface{}, len(t.params))
+	for name, ref := range t.params {
+		if ref.val != nil {
+			values[name] = ref.val
+		}
+	}
+	return values
+}
+
+func (t *task) summarizeParams() map[string]interface{} {
+	if len(t.params) == 0 {
+		return nil
+	}
+	params := make(map[string]interface{}, len(t.params))
+	for name, ref := range t.params {
+		if ref.val != nil {
+			params[name] = ref.val
+			continue
+		}
+		params[name] = ref.String()
+	}
+	return params
+}
+
 func (t *task) valid() []validationErr {
 	var vErrs []validationErr
 	if t.cron == "" && t.every == 0 {
@@ -2174,6 +2319,15 @@ func (t *task) valid() []validationErr {
 		})
 	}
 
+	if t.query != "" {
+		if _, err := options.BindParams(t.flux(), t.paramValues()); err != nil {
+			vErrs = append(vErrs, validationErr{
+				Field: fieldTaskParams,
+				Msg:   err.Error(),
+			})
+		}
+	}
+
 	if len(vErrs) > 0 {
 		return []validationErr{
 			objectValidationErr(fieldSpec, vErrs...),
@@ -2399,6 +2553,159 @@ func (m mapperVariables) Len() int {
 	return len(m)
 }
 
+const (
+	fieldRolePermissionAction   = "action"
+	fieldRolePermissionResource = "resource"
+	fieldRolePermissions        = "permissions"
+)
+
+type rolePermission struct {
+	action  influxdb.Action
+	resType influxdb.ResourceType
+	// resName is the name of the bucket the permission is restricted to. The
+	// bucket is either one from the pkg, matched by its metadata.name, or an
+	// existing bucket in the organization.
+	resName *references
+	resID   influxdb.ID
+}
+
+type role struct {
+	identity
+
+	id          influxdb.ID
+	OrgID       influxdb.ID
+	Description string
+	permissions []*rolePermission
+
+	existing *influxdb.Role
+}
+
+func (r *role) ID() influxdb.ID {
+	if r.existing != nil {
+		return r.existing.ID
+	}
+	return r.id
+}
+
+func (r *role) shouldApply() bool {
+	return r.shouldRemove ||
+		r.existing == nil ||
+		r.existing.Name != r.Name() ||
+		r.existing.Description != r.Description ||
+		!reflect.DeepEqual(r.existing.Permissions, r.influxPermissions())
+}
+
+func (r *role) summarize() SummaryRole {
+	return SummaryRole{
+		ID:          SafeID(r.ID()),
+		OrgID:       SafeID(r.OrgID),
+		PkgName:     r.PkgName(),
+		Name:        r.Name(),
+		Description: r.Description,
+		Permissions: r.summarizePermissions(),
+	}
+}
+
+func (r *role) summarizePermissions() []SummaryRolePermission {
+	perms := make([]SummaryRolePermission, 0, len(r.permissions))
+	for _, p := range r.permissions {
+		perms = append(perms, SummaryRolePermission{
+			Action:       p.action,
+			ResourceType: p.resType,
+			ResourceID:   SafeID(p.resID),
+			ResourceName: p.resName.String(),
+		})
+	}
+	return perms
+}
+
+// influxPermissions returns the permissions of the role within its organization.
+// The ids of the named buckets must have been resolved beforehand.
+func (r *role) influxPermissions() []influxdb.Permission {
+	perms := make([]influxdb.Permission, 0, len(r.permissions))
+	for _, p := range r.permissions {
+		orgID := r.OrgID
+		perm := influxdb.Permission{
+			Action: p.action,
+			Resource: influxdb.Resource{
+				Type:  p.resType,
+				OrgID: &orgID,
+			},
+		}
+		if p.resName.hasValue() {
+			id := p.resID
+			perm.Resource.ID = &id
+		}
+		perms = append(perms, perm)
+	}
+	return perms
+}
+
+func (r *role) valid() []validationErr {
+	var vErrs []validationErr
+	if len(r.permissions) == 0 {
+		vErrs = append(vErrs, validationErr{
+			Field: fieldRolePermissions,
+			Msg:   "must provide at least 1",
+		})
+	}
+
+	for i, p := range r.permissions {
+		var permErrs []validationErr
+		if p.action != influxdb.ReadAction && p.action != influxdb.WriteAction {
+			permErrs = append(permErrs, validationErr{
+				Field: fieldRolePermissionAction,
+				Msg:   fmt.Sprintf("must be 1 in [read, write]; got=%q", p.action),
+			})
+		}
+
+		var resErrs []validationErr
+		if !isOrgResourceType(p.resType) {
+			resErrs = append(resErrs, validationErr{
+				Field: fieldType,
+				Msg:   fmt.Sprintf("must be a resource type of an organization; got=%q", p.resType),
+			})
+		}
+		if p.resName.hasValue() && p.resType != influxdb.BucketsResourceType {
+			resErrs = append(resErrs, validationErr{
+				Field: fieldName,
+				Msg:   fmt.Sprintf("may only be provided for %s; got type=%q", influxdb.BucketsResourceType, p.resType),
+			})
+		}
+		if len(resErrs) > 0 {
+			permErrs = append(permErrs, validationErr{
+				Field:  fieldRolePermissionResource,
+				Nested: resErrs,
+			})
+		}
+
+		if len(permErrs) > 0 {
+			vErrs = append(vErrs, validationErr{
+				Field:  fieldRolePermissions,
+				Index:  intPtr(i),
+				Nested: permErrs,
+			})
+		}
+	}
+
+	if len(vErrs) > 0 {
+		return []validationErr{
+			objectValidationErr(fieldSpec, vErrs...),
+		}
+	}
+
+	return nil
+}
+
+func isOrgResourceType(t influxdb.ResourceType) bool {
+	for _, rt := range influxdb.OrgResourceTypes {
+		if rt == t {
+			return true
+		}
+	}
+	return false
+}
+
 const (
 	fieldDashCharts = "charts"
 )
@@ -249,6 +249,7 @@ type Pkg struct {
 	mDashboards            map[string]*dashboard
 	mNotificationEndpoints map[string]*notificationEndpoint
 	mNotificationRules     map[string]*notificationRule
+	mRoles                 map[string]*role
 	mTasks                 map[string]*task
 	mTelegrafs             map[string]*telegraf
 	mVariables             map[string]*variable
@@ -303,6 +304,7 @@ func (p *Pkg) Summary() Summary {
 		Labels:                []SummaryLabel{},
 		MissingEnvs:           p.missingEnvRefs(),
 		MissingSecrets:        []string{},
+		Roles:                 []SummaryRole{},
 		Tasks:                 []SummaryTask{},
 		TelegrafConfigs:       []SummaryTelegraf{},
 		Variables:             []SummaryVariable{},
@@ -351,6 +353,13 @@ func (p *Pkg) Summary() Summary {
 		sum.NotificationRules = append(sum.NotificationRules, r.summarize())
 	}
 
+	for _, r := range p.roles() {
+		if r.shouldRemove {
+			continue
+		}
+		sum.Roles = append(sum.Roles, r.summarize())
+	}
+
 	for _, t := range p.tasks() {
 		sum.Tasks = append(sum.Tasks, t.summarize())
 	}
@@ -451,6 +460,11 @@ func (p *Pkg) addObjectForRemoval(k Kind, pkgName string, id influxdb.ID) {
 			identity: newIdentity,
 			id:       id,
 		}
+	case KindRole:
+		p.mRoles[pkgName] = &role{
+			identity: newIdentity,
+			id:       id,
+		}
 	case KindTask:
 		p.mTasks[pkgName] = &task{
 			identity: newIdentity,
@@ -504,6 +518,11 @@ func (p *Pkg) getObjectIDSetter(k Kind, pkgName string) (func(influxdb.ID), bool
 		return func(id influxdb.ID) {
 			r.id = id
 		}, ok
+	case KindRole:
+		r, ok := p.mRoles[pkgName]
+		return func(id influxdb.ID) {
+			r.id = id
+		}, ok
 	case KindTask:
 		t, ok := p.mTasks[pkgName]
 		return func(id influxdb.ID) {
@@ -683,6 +702,17 @@ func (p *Pkg) missingSecrets() []string {
 	return secrets
 }
 
+func (p *Pkg) roles() []*role {
+	roles := make([]*role, 0, len(p.mRoles))
+	for _, r := range p.mRoles {
+		roles = append(roles, r)
+	}
+
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name() < roles[j].Name() })
+
+	return roles
+}
+
 func (p *Pkg) tasks() []*task {
 	tasks := make([]*task, 0, len(p.mTasks))
 	for _, t := range p.mTasks {
@@ -779,6 +809,7 @@ func (p *Pkg) graphResources() error {
 		p.graphDashboards,
 		p.graphNotificationEndpoints,
 		p.graphNotificationRules,
+		p.graphRoles,
 		p.graphTasks,
 		p.graphTelegrafs,
 	}
@@ -1094,6 +1125,39 @@ func (p *Pkg) graphNotificationRules() *parseErr {
 	})
 }
 
+func (p *Pkg) graphRoles() *parseErr {
+	p.mRoles = make(map[string]*role)
+	tracker := p.trackNames(true)
+	return p.eachResource(KindRole, 1, func(o Object) []validationErr {
+		ident, errs := tracker(o)
+		if len(errs) > 0 {
+			return errs
+		}
+
+		r := &role{
+			identity:    ident,
+			Description: o.Spec.stringShort(fieldDescription),
+		}
+
+		refs := []*references{r.name, r.displayName}
+		for _, perm := range o.Spec.slcResource(fieldRolePermissions) {
+			res, _ := ifaceToResource(perm[fieldRolePermissionResource])
+			rp := &rolePermission{
+				action:  influxdb.Action(normStr(perm.stringShort(fieldRolePermissionAction))),
+				resType: influxdb.ResourceType(normStr(res.stringShort(fieldType))),
+				resName: p.getRefWithKnownEnvs(res, fieldName),
+			}
+			r.permissions = append(r.permissions, rp)
+			refs = append(refs, rp.resName)
+		}
+
+		p.mRoles[r.PkgName()] = r
+		p.setRefs(refs...)
+
+		return r.valid()
+	})
+}
+
 func (p *Pkg) graphTasks() *parseErr {
 	p.mTasks = make(map[string]*task)
 	tracker := p.trackNames(false)
@@ -1113,6 +1177,15 @@ func (p *Pkg) graphTasks() *parseErr {
 			status:      normStr(o.Spec.stringShort(fieldStatus)),
 		}
 
+		if params, ok := ifaceToResource(o.Spec[fieldTaskParams]); ok {
+			t.params = make(map[string]*references, len(params))
+			for name := range params {
+				ref := p.getRefWithKnownEnvs(params, name)
+				t.params[name] = ref
+				p.setRefs(ref)
+			}
+		}
+
 		failures := p.parseNestedLabels(o.Spec, func(l *label) error {
 			t.labels = append(t.labels, l)
 			p.mLabels[l.PkgName()].setMapping(t, false)
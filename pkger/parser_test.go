@@ -3325,6 +3325,145 @@ spec:
 		})
 	})
 
+	t.Run("pkg with roles", func(t *testing.T) {
+		t.Run("with valid fields should produce summary", func(t *testing.T) {
+			testfileRunner(t, "testdata/roles", func(t *testing.T, pkg *Pkg) {
+				sum := pkg.Summary()
+
+				require.Len(t, sum.Roles, 2)
+
+				r := sum.Roles[0]
+				assert.Equal(t, "bucket-writer", r.PkgName)
+				assert.Equal(t, "bucket-writer:prod", r.Name)
+				assert.Equal(t, "writes to the prod bucket", r.Description)
+				expectedPerms := []SummaryRolePermission{
+					{
+						Action:       influxdb.ReadAction,
+						ResourceType: influxdb.BucketsResourceType,
+						ResourceName: "prod",
+					},
+					{
+						Action:       influxdb.WriteAction,
+						ResourceType: influxdb.BucketsResourceType,
+						ResourceName: "prod",
+					},
+				}
+				assert.Equal(t, expectedPerms, r.Permissions)
+
+				r = sum.Roles[1]
+				assert.Equal(t, "dashboard-editor", r.Name)
+				expectedPerms = []SummaryRolePermission{
+					{
+						Action:       influxdb.ReadAction,
+						ResourceType: influxdb.DashboardsResourceType,
+					},
+					{
+						Action:       influxdb.WriteAction,
+						ResourceType: influxdb.DashboardsResourceType,
+					},
+				}
+				assert.Equal(t, expectedPerms, r.Permissions)
+			})
+		})
+
+		t.Run("handles bad config", func(t *testing.T) {
+			tests := []testPkgResourceError{
+				{
+					name:           "missing permissions",
+					validationErrs: 1,
+					valFields:      []string{fieldSpec, fieldRolePermissions},
+					pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: Role
+metadata:
+  name: role_0
+spec:
+  description: no permissions
+`,
+				},
+				{
+					name:           "invalid action",
+					validationErrs: 1,
+					valFields:      []string{"spec.permissions[0].action"},
+					pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: Role
+metadata:
+  name: role_0
+spec:
+  permissions:
+    - action: delete
+      resource:
+        type: buckets
+`,
+				},
+				{
+					name:           "resource type outside of an org",
+					validationErrs: 1,
+					valFields:      []string{"spec.permissions[0].resource.type"},
+					pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: Role
+metadata:
+  name: role_0
+spec:
+  permissions:
+    - action: read
+      resource:
+        type: orgs
+`,
+				},
+				{
+					name:           "resource name for a type other than buckets",
+					validationErrs: 1,
+					valFields:      []string{"spec.permissions[1].resource.name"},
+					pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: Role
+metadata:
+  name: role_0
+spec:
+  permissions:
+    - action: read
+      resource:
+        type: buckets
+    - action: read
+      resource:
+        type: dashboards
+        name: dash_1
+`,
+				},
+				{
+					name:           "duplicate names",
+					validationErrs: 1,
+					valFields:      []string{fieldSpec, fieldName},
+					pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: Role
+metadata:
+  name: role_0
+spec:
+  name: editor
+  permissions:
+    - action: read
+      resource:
+        type: dashboards
+---
+apiVersion: influxdata.com/v2alpha1
+kind: Role
+metadata:
+  name: role_1
+spec:
+  name: editor
+  permissions:
+    - action: read
+      resource:
+        type: dashboards
+`,
+				},
+			}
+
+			for _, tt := range tests {
+				testPkgErrors(t, KindRole, tt)
+			}
+		})
+	})
+
 	t.Run("pkg with tasks", func(t *testing.T) {
 		t.Run("happy path", func(t *testing.T) {
 			testfileRunner(t, "testdata/tasks", func(t *testing.T, pkg *Pkg) {
@@ -3359,6 +3498,48 @@ spec:
 			})
 		})
 
+		t.Run("with params", func(t *testing.T) {
+			pkgStr := `apiVersion: influxdata.com/v2alpha1
+kind: Task
+metadata:
+  name: task_0
+spec:
+  every: 10m
+  query:  >
+    option params = {bucket: "telegraf", threshold: 0.9}
+
+    from(bucket: params.bucket)
+      |> range(start: -1h)
+      |> filter(fn: (r) => r._value > params.threshold)
+  params:
+    threshold: "0.5"
+    bucket:
+      envRef:
+        key: task-bucket-ref
+`
+			pkg, err := Parse(EncodingYAML, FromString(pkgStr))
+			require.NoError(t, err)
+
+			sum := pkg.Summary()
+			require.Len(t, sum.Tasks, 1)
+			assert.Equal(t, map[string]interface{}{
+				"bucket":    "$task-bucket-ref",
+				"threshold": "0.5",
+			}, sum.Tasks[0].Params)
+			_, ok := pkg.mEnv["task-bucket-ref"]
+			assert.True(t, ok)
+
+			err = pkg.applyEnvRefs(map[string]string{"task-bucket-ref": "rucket_1"})
+			require.NoError(t, err)
+
+			sum = pkg.Summary()
+			require.Len(t, sum.Tasks, 1)
+			assert.Equal(t, map[string]interface{}{
+				"bucket":    "rucket_1",
+				"threshold": "0.5",
+			}, sum.Tasks[0].Params)
+		})
+
 		t.Run("handles bad config", func(t *testing.T) {
 			tests := []struct {
 				kind   Kind
@@ -3429,6 +3610,27 @@ metadata:
 spec:
   description: desc_0
   offset: 15s
+`,
+					},
+				},
+				{
+					kind: KindTask,
+					resErr: testPkgResourceError{
+						name:           "unknown param",
+						validationErrs: 1,
+						valFields:      []string{fieldSpec, fieldTaskParams},
+						pkgStr: `apiVersion: influxdata.com/v2alpha1
+kind: Task
+metadata:
+  name: task_0
+spec:
+  every: 10m
+  query:  >
+    option params = {bucket: "telegraf"}
+
+    from(bucket: params.bucket) |> yield(name: "mean")
+  params:
+    rucket: rucket_1
 `,
 					},
 				},
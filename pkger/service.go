@@ -73,6 +73,7 @@ type serviceOpt struct {
 	endpointSVC influxdb.NotificationEndpointService
 	orgSVC      influxdb.OrganizationService
 	ruleSVC     influxdb.NotificationRuleStore
+	roleSVC     influxdb.RoleService
 	secretSVC   influxdb.SecretService
 	taskSVC     influxdb.TaskService
 	teleSVC     influxdb.TelegrafConfigStore
@@ -159,6 +160,13 @@ func WithLabelSVC(labelSVC influxdb.LabelService) ServiceSetterFn {
 	}
 }
 
+// WithRoleSVC sets the role service.
+func WithRoleSVC(roleSVC influxdb.RoleService) ServiceSetterFn {
+	return func(opt *serviceOpt) {
+		opt.roleSVC = roleSVC
+	}
+}
+
 // WithSecretSVC sets the secret service.
 func WithSecretSVC(secretSVC influxdb.SecretService) ServiceSetterFn {
 	return func(opt *serviceOpt) {
@@ -214,6 +222,7 @@ type Service struct {
 	endpointSVC influxdb.NotificationEndpointService
 	orgSVC      influxdb.OrganizationService
 	ruleSVC     influxdb.NotificationRuleStore
+	roleSVC     influxdb.RoleService
 	secretSVC   influxdb.SecretService
 	taskSVC     influxdb.TaskService
 	teleSVC     influxdb.TelegrafConfigStore
@@ -249,6 +258,7 @@ func NewService(opts ...ServiceSetterFn) *Service {
 		endpointSVC: opt.endpointSVC,
 		orgSVC:      opt.orgSVC,
 		ruleSVC:     opt.ruleSVC,
+		roleSVC:     opt.roleSVC,
 		secretSVC:   opt.secretSVC,
 		taskSVC:     opt.taskSVC,
 		teleSVC:     opt.teleSVC,
@@ -542,6 +552,22 @@ func (s *Service) cloneOrgTasks(ctx context.Context, orgID influxdb.ID) ([]Resou
 	return resources, nil
 }
 
+func (s *Service) cloneOrgRoles(ctx context.Context, orgID influxdb.ID) ([]ResourceToClone, error) {
+	roles, _, err := s.roleSVC.FindRoles(ctx, influxdb.RoleFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]ResourceToClone, 0, len(roles))
+	for _, r := range roles {
+		resources = append(resources, ResourceToClone{
+			Kind: KindRole,
+			ID:   r.ID,
+		})
+	}
+	return resources, nil
+}
+
 func (s *Service) cloneOrgTelegrafs(ctx context.Context, orgID influxdb.ID) ([]ResourceToClone, error) {
 	teles, _, err := s.teleSVC.FindTelegrafConfigs(ctx, influxdb.TelegrafConfigFilter{OrgID: &orgID})
 	if err != nil {
@@ -590,6 +616,7 @@ func (s *Service) filterOrgResourceKinds(resourceKindFilters []Kind) []struct {
 		KindLabel:                s.cloneOrgLabels,
 		KindNotificationEndpoint: s.cloneOrgNotificationEndpoints,
 		KindNotificationRule:     s.cloneOrgNotificationRules,
+		KindRole:                 s.cloneOrgRoles,
 		KindTask:                 s.cloneOrgTasks,
 		KindTelegraf:             s.cloneOrgTelegrafs,
 		KindVariable:             s.cloneOrgVariables,
@@ -691,6 +718,12 @@ func (s *Service) DryRun(ctx context.Context, orgID, userID influxdb.ID, pkg *Pk
 	}
 	diff.NotificationRules = diffRules
 
+	diffRoles, err := s.dryRunRoles(ctx, orgID, pkg)
+	if err != nil {
+		return Summary{}, Diff{}, err
+	}
+	diff.Roles = diffRoles
+
 	diffLabelMappings, err := s.dryRunLabelMappings(ctx, pkg)
 	if err != nil {
 		return Summary{}, Diff{}, err
@@ -888,6 +921,78 @@ func (s *Service) dryRunNotificationRules(ctx context.Context, orgID influxdb.ID
 	return diffs, nil
 }
 
+func (s *Service) dryRunRoles(ctx context.Context, orgID influxdb.ID, pkg *Pkg) ([]DiffRole, error) {
+	roles := pkg.roles()
+	if len(roles) == 0 {
+		return []DiffRole{}, nil
+	}
+
+	existingRoles, _, err := s.roleSVC.FindRoles(ctx, influxdb.RoleFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, internalErr(err)
+	}
+
+	mIDs := make(map[influxdb.ID]*influxdb.Role)
+	mNames := make(map[string]*influxdb.Role)
+	for _, r := range existingRoles {
+		mIDs[r.ID] = r
+		mNames[r.Name] = r
+	}
+
+	diffs := make([]DiffRole, 0, len(roles))
+	for _, r := range roles {
+		r.OrgID = orgID
+
+		var existing *influxdb.Role
+		if r.ID() != 0 {
+			existing = mIDs[r.ID()]
+		} else {
+			existing = mNames[r.Name()]
+		}
+		r.existing = existing
+
+		if err := s.resolveRoleBuckets(ctx, orgID, pkg, r); err != nil {
+			return nil, err
+		}
+
+		diffs = append(diffs, newDiffRole(r, existing))
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].PkgName < diffs[j].PkgName
+	})
+
+	return diffs, nil
+}
+
+// resolveRoleBuckets sets the ids of the buckets the permissions of a role are
+// restricted to. A bucket from the pkg is matched by its metadata.name, otherwise
+// an existing bucket of the organization by its name. The ids of buckets that are
+// yet to be created are zero.
+func (s *Service) resolveRoleBuckets(ctx context.Context, orgID influxdb.ID, pkg *Pkg, r *role) error {
+	for _, p := range r.permissions {
+		if !p.resName.hasValue() {
+			continue
+		}
+
+		name := p.resName.String()
+		if b, ok := pkg.mBuckets[name]; ok && !b.shouldRemove {
+			p.resID = b.ID()
+			continue
+		}
+
+		b, err := s.bucketSVC.FindBucketByName(ctx, orgID, name)
+		if err != nil {
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				err := fmt.Errorf("failed to find bucket %q dependency for role %q", name, r.Name())
+				return &influxdb.Error{Code: influxdb.EUnprocessableEntity, Err: err}
+			}
+			return internalErr(err)
+		}
+		p.resID = b.ID
+	}
+	return nil
+}
+
 func (s *Service) dryRunSecrets(ctx context.Context, orgID influxdb.ID, pkg *Pkg) error {
 	pkgSecrets := pkg.mSecrets
 	if len(pkgSecrets) == 0 {
@@ -1224,7 +1329,14 @@ func (s *Service) apply(ctx context.Context, coordinator *rollbackCoordinator, o
 	if err != nil {
 		return Summary{}, err
 	}
-	if err := coordinator.runTilEnd(ctx, orgID, userID, app); err != nil {
+
+	// roles are run after the primary resources as well, their permissions
+	// may be restricted to buckets that were just applied.
+	rolesApp, err := s.applyRolesGenerator(ctx, orgID, pkg)
+	if err != nil {
+		return Summary{}, err
+	}
+	if err := coordinator.runTilEnd(ctx, orgID, userID, app, rolesApp); err != nil {
 		return Summary{}, err
 	}
 
@@ -1959,6 +2071,7 @@ func (s *Service) applyTasks(tasks []*task) applier {
 			Description:    t.description,
 			Status:         string(t.Status()),
 			OrganizationID: t.orgID,
+			Params:         t.paramValues(),
 		})
 		if err != nil {
 			return &applyErrBody{name: t.Name(), msg: err.Error()}
@@ -2141,6 +2254,140 @@ func (s *Service) applyVariable(ctx context.Context, v variable) (influxdb.Varia
 	return influxVar, nil
 }
 
+func (s *Service) applyRolesGenerator(ctx context.Context, orgID influxdb.ID, pkg *Pkg) (applier, error) {
+	roles := pkg.roles()
+
+	var errs applyErrs
+	for _, r := range roles {
+		if r.shouldRemove {
+			continue
+		}
+		if err := s.resolveRoleBuckets(ctx, orgID, pkg, r); err != nil {
+			errs = append(errs, &applyErrBody{
+				name: r.Name(),
+				msg:  err.Error(),
+			})
+		}
+	}
+
+	if err := errs.toError("role", "failed to find dependency"); err != nil {
+		return applier{}, err
+	}
+
+	return s.applyRoles(roles), nil
+}
+
+func (s *Service) applyRoles(roles []*role) applier {
+	const resource = "role"
+
+	mutex := new(doMutex)
+	rollbackRoles := make([]*role, 0, len(roles))
+
+	createFn := func(ctx context.Context, i int, orgID, userID influxdb.ID) *applyErrBody {
+		var r role
+		mutex.Do(func() {
+			roles[i].OrgID = orgID
+			r = *roles[i]
+		})
+		if !r.shouldApply() {
+			return nil
+		}
+
+		influxRole, err := s.applyRole(ctx, r)
+		if err != nil {
+			return &applyErrBody{
+				name: r.Name(),
+				msg:  err.Error(),
+			}
+		}
+
+		mutex.Do(func() {
+			roles[i].id = influxRole.ID
+			rollbackRoles = append(rollbackRoles, roles[i])
+		})
+		return nil
+	}
+
+	return applier{
+		creater: creater{
+			entries: len(roles),
+			fn:      createFn,
+		},
+		rollbacker: rollbacker{
+			resource: resource,
+			fn:       func(_ influxdb.ID) error { return s.rollbackRoles(rollbackRoles) },
+		},
+	}
+}
+
+func (s *Service) rollbackRoles(roles []*role) error {
+	ctx := context.Background()
+	rollbackFn := func(r *role) error {
+		var err error
+		switch {
+		case r.shouldRemove:
+			err = s.roleSVC.CreateRole(ctx, r.existing)
+		case r.existing == nil:
+			err = s.roleSVC.DeleteRole(ctx, r.ID())
+		default:
+			_, err = s.roleSVC.UpdateRole(ctx, r.ID(), influxdb.RoleUpdate{
+				Name:        &r.existing.Name,
+				Description: &r.existing.Description,
+				Permissions: &r.existing.Permissions,
+			})
+		}
+		return err
+	}
+
+	var errs []string
+	for _, r := range roles {
+		if err := rollbackFn(r); err != nil {
+			errs = append(errs, fmt.Sprintf("error for role[%q]: %s", r.ID(), err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (s *Service) applyRole(ctx context.Context, r role) (influxdb.Role, error) {
+	if r.shouldRemove {
+		if err := s.roleSVC.DeleteRole(ctx, r.ID()); err != nil {
+			return influxdb.Role{}, fmt.Errorf("failed to delete role[%q]: %w", r.ID(), err)
+		}
+		return *r.existing, nil
+	}
+
+	name := r.Name()
+	perms := r.influxPermissions()
+	if r.existing != nil {
+		updatedRole, err := s.roleSVC.UpdateRole(ctx, r.ID(), influxdb.RoleUpdate{
+			Name:        &name,
+			Description: &r.Description,
+			Permissions: &perms,
+		})
+		if err != nil {
+			return influxdb.Role{}, fmt.Errorf("failed to update role[%q]: %w", r.ID(), err)
+		}
+		return *updatedRole, nil
+	}
+
+	influxRole := influxdb.Role{
+		OrgID:       r.OrgID,
+		Name:        name,
+		Description: r.Description,
+		Permissions: perms,
+	}
+	if err := s.roleSVC.CreateRole(ctx, &influxRole); err != nil {
+		return influxdb.Role{}, fmt.Errorf("failed to create role[%q]: %w", name, err)
+	}
+
+	return influxRole, nil
+}
+
 func (s *Service) applyLabelMappings(labelMappings []SummaryLabelMapping) applier {
 	const resource = "label_mapping"
 
@@ -2279,6 +2526,17 @@ func (s *Service) updateStackAfterSuccess(ctx context.Context, stackID influxdb.
 			Name:       l.PkgName(),
 		})
 	}
+	for _, r := range pkg.roles() {
+		if r.shouldRemove {
+			continue
+		}
+		stackResources = append(stackResources, StackResource{
+			APIVersion: APIVersion,
+			ID:         r.ID(),
+			Kind:       KindRole,
+			Name:       r.PkgName(),
+		})
+	}
 	for _, v := range pkg.variables() {
 		if v.shouldRemove {
 			continue
@@ -2357,6 +2615,15 @@ func (s *Service) updateStackAfterRollback(ctx context.Context, stackID influxdb
 				}
 			}
 		}
+		for _, r := range pkg.roles() {
+			if r.shouldRemove {
+				res := existingResources[newKey(KindRole, r.PkgName())]
+				if res.ID != r.ID() {
+					hasChanges = true
+					res.ID = r.existing.ID
+				}
+			}
+		}
 		for _, v := range pkg.variables() {
 			if v.shouldRemove {
 				res := existingResources[newKey(KindVariable, v.PkgName())]
@@ -32,6 +32,7 @@ func TestService(t *testing.T) {
 			endpointSVC: mock.NewNotificationEndpointService(),
 			orgSVC:      mock.NewOrganizationService(),
 			ruleSVC:     mock.NewNotificationRuleStore(),
+			roleSVC:     mock.NewRoleService(),
 			taskSVC:     mock.NewTaskService(),
 			teleSVC:     mock.NewTelegrafConfigStore(),
 			varSVC:      mock.NewVariableService(),
@@ -51,6 +52,7 @@ func TestService(t *testing.T) {
 			WithNotificationEndpointSVC(opt.endpointSVC),
 			WithNotificationRuleSVC(opt.ruleSVC),
 			WithOrganizationService(opt.orgSVC),
+			WithRoleSVC(opt.roleSVC),
 			WithSecretSVC(opt.secretSVC),
 			WithTaskSVC(opt.taskSVC),
 			WithTelegrafSVC(opt.teleSVC),
@@ -380,6 +382,114 @@ func TestService(t *testing.T) {
 			})
 		})
 
+		t.Run("roles", func(t *testing.T) {
+			testfileRunner(t, "testdata/roles", func(t *testing.T, pkg *Pkg) {
+				orgID := influxdb.ID(100)
+
+				fakeBktSVC := mock.NewBucketService()
+				fakeBktSVC.FindBucketByNameFn = func(_ context.Context, orgID influxdb.ID, name string) (*influxdb.Bucket, error) {
+					return &influxdb.Bucket{ID: 3, OrgID: orgID, Name: name}, nil
+				}
+				fakeRoleSVC := mock.NewRoleService()
+				fakeRoleSVC.FindRolesF = func(_ context.Context, f influxdb.RoleFilter, _ ...influxdb.FindOptions) ([]*influxdb.Role, int, error) {
+					return []*influxdb.Role{
+						{
+							ID:          1,
+							OrgID:       orgID,
+							Name:        "dashboard-editor",
+							Description: "old desc",
+							Permissions: []influxdb.Permission{
+								{
+									Action:   influxdb.ReadAction,
+									Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID},
+								},
+							},
+						},
+					}, 1, nil
+				}
+				svc := newTestService(WithBucketSVC(fakeBktSVC), WithRoleSVC(fakeRoleSVC))
+
+				_, diff, err := svc.DryRun(context.TODO(), orgID, 0, pkg)
+				require.NoError(t, err)
+
+				require.Len(t, diff.Roles, 2)
+
+				expected := DiffRole{
+					DiffIdentifier: DiffIdentifier{
+						// no ID here since this one would be new
+						PkgName: "bucket-writer",
+					},
+					New: DiffRoleValues{
+						Name:        "bucket-writer:prod",
+						Description: "writes to the prod bucket",
+						Permissions: []SummaryRolePermission{
+							{
+								Action:       influxdb.ReadAction,
+								ResourceType: influxdb.BucketsResourceType,
+								ResourceID:   3,
+								ResourceName: "prod",
+							},
+							{
+								Action:       influxdb.WriteAction,
+								ResourceType: influxdb.BucketsResourceType,
+								ResourceID:   3,
+								ResourceName: "prod",
+							},
+						},
+					},
+				}
+				assert.Equal(t, expected, diff.Roles[0])
+
+				expected = DiffRole{
+					DiffIdentifier: DiffIdentifier{
+						ID:      1,
+						PkgName: "dashboard-editor",
+					},
+					Old: &DiffRoleValues{
+						Name:        "dashboard-editor",
+						Description: "old desc",
+						Permissions: []SummaryRolePermission{
+							{Action: influxdb.ReadAction, ResourceType: influxdb.DashboardsResourceType},
+						},
+					},
+					New: DiffRoleValues{
+						Name: "dashboard-editor",
+						Permissions: []SummaryRolePermission{
+							{Action: influxdb.ReadAction, ResourceType: influxdb.DashboardsResourceType},
+							{Action: influxdb.WriteAction, ResourceType: influxdb.DashboardsResourceType},
+						},
+					},
+				}
+				assert.Equal(t, expected, diff.Roles[1])
+				assert.True(t, diff.HasConflicts())
+			})
+
+			t.Run("should error if bucket name is not in pkg or in platform", func(t *testing.T) {
+				pkg, err := Parse(EncodingYAML, FromString(`apiVersion: influxdata.com/v2alpha1
+kind: Role
+metadata:
+  name: bucket-writer
+spec:
+  permissions:
+    - action: write
+      resource:
+        type: buckets
+        name: prod
+`))
+				require.NoError(t, err)
+
+				fakeBktSVC := mock.NewBucketService()
+				fakeBktSVC.FindBucketByNameFn = func(context.Context, influxdb.ID, string) (*influxdb.Bucket, error) {
+					return nil, &influxdb.Error{Code: influxdb.ENotFound}
+				}
+				svc := newTestService(WithBucketSVC(fakeBktSVC))
+
+				_, _, err = svc.DryRun(context.TODO(), influxdb.ID(100), 0, pkg)
+				require.Error(t, err)
+				assert.Equal(t, influxdb.EUnprocessableEntity, influxdb.ErrorCode(err))
+			})
+		})
+
 		t.Run("secrets not returns missing secrets", func(t *testing.T) {
 			testfileRunner(t, "testdata/notification_endpoint_secrets.yml", func(t *testing.T, pkg *Pkg) {
 				fakeSecretSVC := mock.NewSecretService()
@@ -1248,6 +1358,87 @@ func TestService(t *testing.T) {
 			})
 		})
 
+		t.Run("roles", func(t *testing.T) {
+			newFakeBktSVC := func() *mock.BucketService {
+				fakeBktSVC := mock.NewBucketService()
+				fakeBktSVC.FindBucketByNameFn = func(_ context.Context, _ influxdb.ID, name string) (*influxdb.Bucket, error) {
+					if fakeBktSVC.CreateBucketCalls.Count() > 0 {
+						return &influxdb.Bucket{ID: 3, Name: name}, nil
+					}
+					return nil, &influxdb.Error{Code: influxdb.ENotFound}
+				}
+				fakeBktSVC.CreateBucketFn = func(_ context.Context, b *influxdb.Bucket) error {
+					b.ID = 3
+					return nil
+				}
+				return fakeBktSVC
+			}
+
+			t.Run("successfully creates pkg of roles", func(t *testing.T) {
+				testfileRunner(t, "testdata/roles.yml", func(t *testing.T, pkg *Pkg) {
+					orgID := influxdb.ID(9000)
+
+					var created []influxdb.Role
+					fakeRoleSVC := mock.NewRoleService()
+					fakeRoleSVC.CreateRoleF = func(_ context.Context, r *influxdb.Role) error {
+						r.ID = influxdb.ID(fakeRoleSVC.CreateRoleCalls.Count() + 1)
+						created = append(created, *r)
+						return nil
+					}
+
+					svc := newTestService(WithBucketSVC(newFakeBktSVC()), WithRoleSVC(fakeRoleSVC))
+
+					sum, err := svc.Apply(context.TODO(), orgID, 0, pkg)
+					require.NoError(t, err)
+
+					require.Len(t, sum.Roles, 2)
+					for _, actual := range sum.Roles {
+						assert.Containsf(t, []SafeID{1, 2}, actual.ID, "actual role: %+v", actual)
+						assert.Equal(t, SafeID(orgID), actual.OrgID)
+					}
+
+					require.Len(t, created, 2)
+					var writer influxdb.Role
+					for _, r := range created {
+						if r.Name == "bucket-writer:prod" {
+							writer = r
+						}
+					}
+					bktID := influxdb.ID(3)
+					expectedPerms := []influxdb.Permission{
+						{
+							Action:   influxdb.ReadAction,
+							Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &bktID},
+						},
+						{
+							Action:   influxdb.WriteAction,
+							Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &bktID},
+						},
+					}
+					assert.Equal(t, expectedPerms, writer.Permissions)
+				})
+			})
+
+			t.Run("rolls back all created roles on an error", func(t *testing.T) {
+				testfileRunner(t, "testdata/roles.yml", func(t *testing.T, pkg *Pkg) {
+					fakeRoleSVC := mock.NewRoleService()
+					fakeRoleSVC.CreateRoleF = func(_ context.Context, r *influxdb.Role) error {
+						if fakeRoleSVC.CreateRoleCalls.Count() == 1 {
+							return errors.New("blowed up ")
+						}
+						return nil
+					}
+
+					svc := newTestService(WithBucketSVC(newFakeBktSVC()), WithRoleSVC(fakeRoleSVC))
+
+					_, err := svc.Apply(context.TODO(), influxdb.ID(9000), 0, pkg)
+					require.Error(t, err)
+
+					assert.GreaterOrEqual(t, fakeRoleSVC.DeleteRoleCalls.Count(), 1)
+				})
+			})
+		})
+
 		t.Run("tasks", func(t *testing.T) {
 			t.Run("successfuly creates", func(t *testing.T) {
 				testfileRunner(t, "testdata/tasks.yml", func(t *testing.T, pkg *Pkg) {
@@ -2586,6 +2777,72 @@ func TestService(t *testing.T) {
 				})
 			})
 
+			t.Run("role", func(t *testing.T) {
+				orgID, bktID := influxdb.ID(9000), influxdb.ID(3)
+				expectedRole := influxdb.Role{
+					ID:          1,
+					OrgID:       orgID,
+					Name:        "bucket-writer",
+					Description: "desc",
+					Permissions: []influxdb.Permission{
+						{
+							Action:   influxdb.WriteAction,
+							Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &orgID, ID: &bktID},
+						},
+						{
+							Action:   influxdb.ReadAction,
+							Resource: influxdb.Resource{Type: influxdb.DashboardsResourceType, OrgID: &orgID},
+						},
+					},
+				}
+
+				roleSVC := mock.NewRoleService()
+				roleSVC.FindRoleByIDF = func(_ context.Context, id influxdb.ID) (*influxdb.Role, error) {
+					if id != expectedRole.ID {
+						return nil, errors.New("uh ohhh, wrong id here: " + id.String())
+					}
+					return &expectedRole, nil
+				}
+				bktSVC := mock.NewBucketService()
+				bktSVC.FindBucketByIDFn = func(_ context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+					if id != bktID {
+						return nil, errors.New("uh ohhh, wrong id here: " + id.String())
+					}
+					return &influxdb.Bucket{ID: id, OrgID: orgID, Name: "prod"}, nil
+				}
+
+				svc := newTestService(WithRoleSVC(roleSVC), WithBucketSVC(bktSVC), WithLabelSVC(mock.NewLabelService()))
+
+				pkg, err := svc.CreatePkg(context.TODO(), CreateWithExistingResources(ResourceToClone{
+					Kind: KindRole,
+					ID:   expectedRole.ID,
+				}))
+				require.NoError(t, err)
+
+				newPkg := encodeAndDecode(t, pkg)
+
+				sum := newPkg.Summary()
+				require.Len(t, sum.Buckets, 1)
+				assert.Equal(t, "prod", sum.Buckets[0].Name)
+
+				require.Len(t, sum.Roles, 1)
+				actual := sum.Roles[0]
+				assert.Equal(t, expectedRole.Name, actual.Name)
+				assert.Equal(t, expectedRole.Description, actual.Description)
+				expectedPerms := []SummaryRolePermission{
+					{
+						Action:       influxdb.WriteAction,
+						ResourceType: influxdb.BucketsResourceType,
+						ResourceName: sum.Buckets[0].PkgName,
+					},
+					{
+						Action:       influxdb.ReadAction,
+						ResourceType: influxdb.DashboardsResourceType,
+					},
+				}
+				assert.Equal(t, expectedPerms, actual.Permissions)
+			})
+
 			t.Run("telegraf configs", func(t *testing.T) {
 				t.Run("allows for duplicate telegraf names to be exported", func(t *testing.T) {
 					teleStore := mock.NewTelegrafConfigStore()
@@ -123,6 +123,20 @@ func (s *AuthorizationService) UpdateAuthorization(ctx context.Context, id platf
 	return s.AuthorizationService.UpdateAuthorization(ctx, id, upd)
 }
 
+// RotateAuthorization issues a new token for the authorization.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (a *platform.Authorization, err error) {
+	defer func(start time.Time) {
+		labels := prometheus.Labels{
+			"method": "RotateAuthorization",
+			"error":  fmt.Sprint(err != nil),
+		}
+		s.requestCount.With(labels).Add(1)
+		s.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+	}(time.Now())
+
+	return s.AuthorizationService.RotateAuthorization(ctx, id, gracePeriod)
+}
+
 // PrometheusCollectors returns all authorization service prometheus collectors.
 func (s *AuthorizationService) PrometheusCollectors() []prometheus.Collector {
 	return []prometheus.Collector{
@@ -278,6 +278,7 @@ type ToTransformation struct {
 	implicitTagColumns bool
 	deps               ToDependencies
 	buf                *storage.BufferedPointsWriter
+	stats              *query.WriteStatistics
 }
 
 // RetractTable retracts the table for the transformation for the `to` flux function.
@@ -353,6 +354,7 @@ func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.T
 		implicitTagColumns: spec.TagColumns == nil,
 		deps:               deps,
 		buf:                storage.NewBufferedPointsWriter(DefaultBufferSize, deps.PointsWriter),
+		stats:              query.WriteStatisticsFromContext(ctx),
 	}, nil
 }
 
@@ -676,7 +678,11 @@ func writeTable(ctx context.Context, t *ToTransformation, tbl flux.Table) (err e
 			}
 		}
 
-		return t.buf.WritePoints(ctx, points)
+		if err := t.buf.WritePoints(ctx, points); err != nil {
+			return err
+		}
+		t.stats.AddPointsWritten(len(points))
+		return nil
 	})
 }
 
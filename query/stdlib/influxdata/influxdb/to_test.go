@@ -18,6 +18,7 @@ import (
 	platform "github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/mock"
 	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/query"
 	_ "github.com/influxdata/influxdb/v2/query/builtin"
 	pquerytest "github.com/influxdata/influxdb/v2/query/querytest"
 	"github.com/influxdata/influxdb/v2/query/stdlib/influxdata/influxdb"
@@ -906,13 +907,14 @@ m,tag1=c _value=4 41`),
 					ToDeps: mockDependencies(),
 				},
 			}
+			stats := &query.WriteStatistics{}
 			executetest.ProcessTestHelper(
 				t,
 				tc.data,
 				tc.want.tables,
 				nil,
 				func(d execute.Dataset, c execute.TableBuilderCache) execute.Transformation {
-					ctx := deps.Inject(context.Background())
+					ctx := query.ContextWithWriteStatistics(deps.Inject(context.Background()), stats)
 					newT, err := influxdb.NewToTransformation(ctx, d, c, tc.spec, deps.StorageDeps.ToDeps)
 					if err != nil {
 						t.Error(err)
@@ -924,6 +926,9 @@ m,tag1=c _value=4 41`),
 			if len(pw.Points) != len(tc.want.result.Points) {
 				t.Errorf("Expected result values to have length of %d but got %d", len(tc.want.result.Points), len(pw.Points))
 			}
+			if got, exp := stats.PointsWritten(), int64(len(pw.Points)); got != exp {
+				t.Errorf("Expected %d points written in statistics but got %d", exp, got)
+			}
 
 			gotStr := pointsToStr(pw.Points)
 			wantStr := pointsToStr(tc.want.result.Points)
@@ -0,0 +1,43 @@
+package query
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// WriteStatistics counts the points a query writes with to().
+type WriteStatistics struct {
+	pointsWritten int64
+}
+
+// AddPointsWritten adds n to the points written. It is safe to call on a nil
+// *WriteStatistics and from several goroutines.
+func (s *WriteStatistics) AddPointsWritten(n int) {
+	if s == nil {
+		return
+	}
+	atomic.AddInt64(&s.pointsWritten, int64(n))
+}
+
+// PointsWritten returns the number of points written.
+func (s *WriteStatistics) PointsWritten() int64 {
+	if s == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&s.pointsWritten)
+}
+
+type writeStatisticsContextKey struct{}
+
+// ContextWithWriteStatistics returns a new context counting the points the
+// queries run with it write in s.
+func ContextWithWriteStatistics(ctx context.Context, s *WriteStatistics) context.Context {
+	return context.WithValue(ctx, writeStatisticsContextKey{}, s)
+}
+
+// WriteStatisticsFromContext retrieves the *WriteStatistics of a context.
+// If none exists on the context nil is returned.
+func WriteStatisticsFromContext(ctx context.Context) *WriteStatistics {
+	s, _ := ctx.Value(writeStatisticsContextKey{}).(*WriteStatistics)
+	return s
+}
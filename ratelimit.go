@@ -0,0 +1,68 @@
+package influxdb
+
+import (
+	"context"
+)
+
+// ops for rate limit errors.
+const (
+	OpFindRateLimits  = "FindRateLimits"
+	OpSetRateLimit    = "SetRateLimit"
+	OpDeleteRateLimit = "DeleteRateLimit"
+)
+
+// RateLimit limits the requests made to the write and query APIs with the
+// tokens of an organization. A limit of zero is no limit.
+type RateLimit struct {
+	OrgID ID `json:"orgID"`
+	// AuthorizationID restricts the limit to a single token of the organization.
+	// When unset, the limit applies to the requests made with all of the tokens
+	// of the organization together.
+	AuthorizationID ID `json:"authorizationID,omitempty"`
+
+	// RequestsPerSecond limits the write and query requests made each second.
+	RequestsPerSecond float64 `json:"requestsPerSecond,omitempty"`
+	// WriteBytesPerSecond limits the bytes of the bodies of write requests each second.
+	WriteBytesPerSecond float64 `json:"writeBytesPerSecond,omitempty"`
+	// QueryConcurrency limits the queries executing at the same time.
+	QueryConcurrency int `json:"queryConcurrency,omitempty"`
+}
+
+// Valid returns an error if the limit is missing its organization or a
+// limit is negative.
+func (l *RateLimit) Valid() error {
+	if !l.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "rate limit must have an orgID",
+		}
+	}
+	if l.RequestsPerSecond < 0 || l.WriteBytesPerSecond < 0 || l.QueryConcurrency < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "rate limits must not be negative",
+		}
+	}
+	return nil
+}
+
+// RateLimitFilter represents a set of filters that restrict the returned rate limits.
+type RateLimitFilter struct {
+	OrgID           *ID
+	AuthorizationID *ID
+}
+
+// RateLimitService represents a service for managing the rate limits of
+// organizations and tokens.
+type RateLimitService interface {
+	// FindRateLimits returns a list of rate limits that match filter.
+	FindRateLimits(ctx context.Context, filter RateLimitFilter) ([]*RateLimit, error)
+
+	// SetRateLimit creates the limit, or replaces the existing limit of
+	// the same organization and token.
+	SetRateLimit(ctx context.Context, l *RateLimit) error
+
+	// DeleteRateLimit removes the limit of the organization, or of a token
+	// of it when authID is valid.
+	DeleteRateLimit(ctx context.Context, orgID, authID ID) error
+}
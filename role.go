@@ -0,0 +1,122 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ErrRoleNotFound is the error msg for a missing role.
+const ErrRoleNotFound = "role not found"
+
+// ops for role error.
+const (
+	OpFindRoleByID = "FindRoleByID"
+	OpFindRoles    = "FindRoles"
+	OpCreateRole   = "CreateRole"
+	OpUpdateRole   = "UpdateRole"
+	OpDeleteRole   = "DeleteRole"
+)
+
+// RoleService describes a service for managing roles.
+type RoleService interface {
+	// FindRoleByID finds a single role by its ID.
+	FindRoleByID(ctx context.Context, id ID) (*Role, error)
+
+	// FindRoles returns the roles matching the filter.
+	FindRoles(ctx context.Context, filter RoleFilter, opt ...FindOptions) ([]*Role, int, error)
+
+	// CreateRole creates a new role and assigns it an ID.
+	CreateRole(ctx context.Context, r *Role) error
+
+	// UpdateRole updates a single role with a changeset.
+	UpdateRole(ctx context.Context, id ID, upd RoleUpdate) (*Role, error)
+
+	// DeleteRole removes a role, and its assignments to users and authorizations.
+	DeleteRole(ctx context.Context, id ID) error
+}
+
+// Role is a named set of permissions within an organization.
+// Users are assigned a role by a user resource mapping to it,
+// authorizations by listing it in their Roles.
+type Role struct {
+	ID          ID           `json:"id,omitempty"`
+	OrgID       ID           `json:"orgID"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Permissions []Permission `json:"permissions"`
+	CRUDLog
+}
+
+// Valid returns an error if the role is missing a name, or grants a
+// permission outside of its organization.
+func (r *Role) Valid() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role name is required",
+		}
+	}
+	if !r.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role org id is required",
+		}
+	}
+	return validRolePermissions(r.OrgID, r.Permissions)
+}
+
+func validRolePermissions(orgID ID, ps []Permission) error {
+	for _, p := range ps {
+		if err := p.Valid(); err != nil {
+			return err
+		}
+
+		inOrg := p.Resource.OrgID != nil && *p.Resource.OrgID == orgID
+		isOrg := p.Resource.Type == OrgsResourceType && p.Resource.ID != nil && *p.Resource.ID == orgID
+		if !inOrg && !isOrg {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("permission %s is not for org id %s", p, orgID),
+			}
+		}
+	}
+	return nil
+}
+
+// RoleFilter represents a set of filters that restrict the returned roles.
+type RoleFilter struct {
+	ID    *ID
+	OrgID *ID
+	Name  *string
+}
+
+// RoleUpdate describes a set of changes that can be applied to a role.
+type RoleUpdate struct {
+	Name        *string       `json:"name,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Permissions *[]Permission `json:"permissions,omitempty"`
+}
+
+// Apply applies the non-nil fields of the update to the role.
+func (u RoleUpdate) Apply(r *Role) error {
+	if u.Name != nil {
+		r.Name = strings.TrimSpace(*u.Name)
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.Permissions != nil {
+		r.Permissions = *u.Permissions
+	}
+	return r.Valid()
+}
+
+// RolePermissions returns the permissions granted by the roles.
+func RolePermissions(roles []*Role) []Permission {
+	var ps []Permission
+	for _, r := range roles {
+		ps = append(ps, r.Permissions...)
+	}
+	return ps
+}
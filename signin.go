@@ -0,0 +1,39 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultSigninMaxFailures is the number of failed sign in attempts after
+// which a user is locked out by default.
+const DefaultSigninMaxFailures = 5
+
+// DefaultSigninLockoutDuration is how long a user is first locked out for by
+// default. Each further failure doubles the time the user is locked out for.
+const DefaultSigninLockoutDuration = time.Minute
+
+// MaxSigninLockoutDuration is the longest a user is locked out for.
+const MaxSigninLockoutDuration = 24 * time.Hour
+
+// ops for sign in lockout errors.
+const (
+	OpSigninLockedUntil   = "SigninLockedUntil"
+	OpRecordSigninFailure = "RecordSigninFailure"
+	OpResetSigninFailures = "ResetSigninFailures"
+)
+
+// SigninLockoutService counts the failed sign in attempts of users, locking
+// them out for an increasing time once they have failed too often.
+type SigninLockoutService interface {
+	// SigninLockedUntil returns when the user may sign in again, or the zero
+	// time if they are not locked out.
+	SigninLockedUntil(ctx context.Context, userID ID) (time.Time, error)
+
+	// RecordSigninFailure counts a failed sign in attempt of the user and
+	// returns when they may sign in again, as SigninLockedUntil.
+	RecordSigninFailure(ctx context.Context, userID ID) (time.Time, error)
+
+	// ResetSigninFailures forgets the failed attempts of a user once they sign in.
+	ResetSigninFailures(ctx context.Context, userID ID) error
+}
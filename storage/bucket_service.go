@@ -13,11 +13,18 @@ type BucketDeleter interface {
 	DeleteBucket(context.Context, influxdb.ID, influxdb.ID) error
 }
 
+// BucketSettingsUpdater defines the behaviour of applying the storage settings
+// of a bucket as soon as it is created or updated.
+type BucketSettingsUpdater interface {
+	UpdateBucketSettings(*influxdb.Bucket)
+}
+
 // BucketService wraps an existing influxdb.BucketService implementation.
 //
 // BucketService ensures that when a bucket is deleted, all stored data
 // associated with the bucket is either removed, or marked to be removed via a
-// future compaction.
+// future compaction. When the engine is a BucketSettingsUpdater, the settings
+// of the buckets created or updated are applied to it straight away.
 type BucketService struct {
 	inner  influxdb.BucketService
 	engine BucketDeleter
@@ -85,7 +92,11 @@ func (s *BucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) er
 	if s.inner == nil || s.engine == nil {
 		return errors.New("nil inner BucketService or Engine")
 	}
-	return s.inner.CreateBucket(ctx, b)
+	if err := s.inner.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+	s.updateBucketSettings(b)
+	return nil
 }
 
 // UpdateBucket updates a single bucket with changeset.
@@ -97,7 +108,20 @@ func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd in
 	if s.inner == nil || s.engine == nil {
 		return nil, errors.New("nil inner BucketService or Engine")
 	}
-	return s.inner.UpdateBucket(ctx, id, upd)
+	b, err := s.inner.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.updateBucketSettings(b)
+	return b, nil
+}
+
+// updateBucketSettings applies the storage settings of b to the engine, if it
+// supports it.
+func (s *BucketService) updateBucketSettings(b *influxdb.Bucket) {
+	if u, ok := s.engine.(BucketSettingsUpdater); ok {
+		u.UpdateBucketSettings(b)
+	}
 }
 
 // DeleteBucket removes a bucket by ID.
@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/storage/wal"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+	"go.uber.org/zap"
+)
+
+// bucketSettingsRefreshInterval is how often the settings of all buckets are
+// looked up again, catching the changes not made through the BucketService of
+// the engine.
+const bucketSettingsRefreshInterval = time.Minute
+
+// bucketSetting holds the storage settings of a bucket.
+type bucketSetting struct {
+	compression   tsm1.StringCompression
+	indexedFields map[string]struct{}
+
+	// walDurability is the WAL durability of the writes to the bucket, if
+	// hasWALDurability is set.
+	walDurability    wal.Durability
+	hasWALDurability bool
+}
+
+// bucketSettings chooses the compression of the string blocks, whether the
+// values of the blocks are indexed and the WAL durability of the writes for
+// series keys from the settings of their buckets. The settings of all buckets
+// are looked up at once in the background, as compactions and writes ask for
+// every key they handle and must not wait on the bucket service.
+type bucketSettings struct {
+	BucketService BucketFinder
+
+	logger *zap.Logger
+
+	mu       sync.RWMutex
+	settings map[influxdb.ID]bucketSetting
+
+	// updated holds the settings updated while a refresh is looking up the
+	// buckets, which may not see them yet.
+	updated map[influxdb.ID]bucketSetting
+}
+
+func newBucketSettings(bucketService BucketFinder) *bucketSettings {
+	return &bucketSettings{
+		BucketService: bucketService,
+		logger:        zap.NewNop(),
+	}
+}
+
+// WithLogger sets the logger l on the service.
+func (c *bucketSettings) WithLogger(l *zap.Logger) {
+	c.logger = l.With(zap.String("component", "bucket_settings"))
+}
+
+// StringCompression returns the string compression of the bucket of key. It
+// is a tsm1.StringCompressionFunc.
+func (c *bucketSettings) StringCompression(key []byte) tsm1.StringCompression {
+	if s, ok := c.setting(key); ok {
+		return s.compression
+	}
+	return tsm1.StringCompressionSnappy
+}
+
+// IndexValues reports whether the field of key is an indexed field of its
+// bucket. It is a tsm1.ValueIndexFunc.
+func (c *bucketSettings) IndexValues(key []byte) bool {
+	s, ok := c.setting(key)
+	if !ok || len(s.indexedFields) == 0 {
+		return false
+	}
+	_, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	_, ok = s.indexedFields[string(field)]
+	return ok
+}
+
+// WALDurability returns the WAL durability of the writes to the bucket of key.
+// ok is false if the bucket has no durability of its own.
+func (c *bucketSettings) WALDurability(key []byte) (d wal.Durability, ok bool) {
+	s, ok := c.setting(key)
+	if !ok || !s.hasWALDurability {
+		return wal.DurabilityGroup, false
+	}
+	return s.walDurability, true
+}
+
+// setting returns the settings of the bucket of key.
+func (c *bucketSettings) setting(key []byte) (bucketSetting, bool) {
+	// Keys start with the 16 byte name of their organization and bucket.
+	if len(key) < 16 {
+		return bucketSetting{}, false
+	}
+	_, bucketID := tsdb.DecodeNameSlice(key)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.settings[bucketID]
+	return s, ok
+}
+
+// update sets the settings of bucket b, as created or updated.
+func (c *bucketSettings) update(b *influxdb.Bucket) {
+	s := c.newBucketSetting(b)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The settings are copied on write, so that a refresh can replace them
+	// as a whole.
+	settings := make(map[influxdb.ID]bucketSetting, len(c.settings)+1)
+	for id, s := range c.settings {
+		settings[id] = s
+	}
+	settings[b.ID] = s
+	c.settings = settings
+
+	if c.updated != nil {
+		c.updated[b.ID] = s
+	}
+}
+
+// refresh looks up the settings of all buckets.
+func (c *bucketSettings) refresh(ctx context.Context) error {
+	c.mu.Lock()
+	c.updated = make(map[influxdb.ID]bucketSetting)
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, bucketAPITimeout)
+	defer cancel()
+
+	buckets, _, err := c.BucketService.FindBuckets(ctx, influxdb.BucketFilter{})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	updated := c.updated
+	c.updated = nil
+	if err != nil {
+		return err
+	}
+
+	settings := make(map[influxdb.ID]bucketSetting, len(buckets))
+	for _, b := range buckets {
+		settings[b.ID] = c.newBucketSetting(b)
+	}
+	for id, s := range updated {
+		settings[id] = s
+	}
+	c.settings = settings
+	return nil
+}
+
+// newBucketSetting returns the settings of bucket b, ignoring the invalid ones.
+func (c *bucketSettings) newBucketSetting(b *influxdb.Bucket) bucketSetting {
+	sc, err := tsm1.ParseStringCompression(b.StringCompression)
+	if err != nil {
+		c.logger.Warn("Ignoring invalid bucket compression", zap.Stringer("bucket_id", b.ID), zap.Error(err))
+		sc = tsm1.StringCompressionSnappy
+	}
+
+	s := bucketSetting{compression: sc}
+	if len(b.IndexedFields) > 0 {
+		s.indexedFields = make(map[string]struct{}, len(b.IndexedFields))
+		for _, f := range b.IndexedFields {
+			s.indexedFields[f] = struct{}{}
+		}
+	}
+	if b.WALDurability != "" {
+		d, err := wal.ParseDurability(b.WALDurability)
+		if err != nil {
+			c.logger.Warn("Ignoring invalid bucket WAL durability", zap.Stringer("bucket_id", b.ID), zap.Error(err))
+		} else {
+			s.walDurability, s.hasWALDurability = d, true
+		}
+	}
+	return s
+}
+
+// UpdateBucketSettings applies the storage settings of bucket b, as created or
+// updated, without waiting for the next look up of the buckets.
+func (e *Engine) UpdateBucketSettings(b *influxdb.Bucket) {
+	if e.bucketSettings != nil {
+		e.bucketSettings.update(b)
+	}
+}
+
+// refreshBucketSettings looks up the settings of all buckets.
+func (e *Engine) refreshBucketSettings(ctx context.Context) {
+	if err := e.bucketSettings.refresh(ctx); err != nil {
+		// Keep the last known settings until the next look up.
+		e.bucketSettings.logger.Error("Unable to look up bucket settings", zap.Error(err))
+	}
+}
+
+// runBucketSettingsRefresher keeps looking up the settings of all buckets in
+// the background until the engine closes.
+func (e *Engine) runBucketSettingsRefresher() {
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+
+		ticker := time.NewTicker(bucketSettingsRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			// It's safe to read closing without a lock because it's never
+			// modified if this goroutine is active.
+			select {
+			case <-e.closing:
+				return
+			case <-ticker.C:
+				e.refreshBucketSettings(context.Background())
+			}
+		}
+	}()
+}
@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/storage/wal"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+)
+
+func TestBucketSettings_StringCompression(t *testing.T) {
+	orgID, logsID, metricsID := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+
+	var calls int
+	var findErr error
+	buckets := []*influxdb.Bucket{
+		{ID: logsID, OrgID: orgID, StringCompression: influxdb.BucketStringCompressionZstd},
+		{ID: metricsID, OrgID: orgID},
+	}
+	finder := NewTestBucketFinder()
+	finder.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		calls++
+		return buckets, len(buckets), findErr
+	}
+
+	ctx := context.Background()
+	c := newBucketSettings(finder)
+	if err := c.refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	key := func(bucketID influxdb.ID) []byte {
+		return append(tsdb.EncodeNameSlice(orgID, bucketID), ",host=A#!~#msg"...)
+	}
+
+	for _, tc := range []struct {
+		key []byte
+		exp tsm1.StringCompression
+	}{
+		{key(logsID), tsm1.StringCompressionZstd},
+		{key(metricsID), tsm1.StringCompressionSnappy},
+		{key(influxdb.ID(4)), tsm1.StringCompressionSnappy},
+		{[]byte("short"), tsm1.StringCompressionSnappy},
+	} {
+		if got := c.StringCompression(tc.key); got != tc.exp {
+			t.Fatalf("unexpected compression of %q: got %v, exp %v", tc.key, got, tc.exp)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("unexpected bucket look ups: got %d, exp 1", calls)
+	}
+
+	// A change of compression is seen once the buckets are looked up again.
+	buckets[0].StringCompression = influxdb.BucketStringCompressionSnappy
+	if got := c.StringCompression(key(logsID)); got != tsm1.StringCompressionZstd {
+		t.Fatalf("unexpected compression before refresh: got %v", got)
+	}
+	if err := c.refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.StringCompression(key(logsID)); got != tsm1.StringCompressionSnappy {
+		t.Fatalf("unexpected compression after refresh: got %v", got)
+	}
+
+	// Failed look ups keep the last known compressions.
+	buckets[0].StringCompression = influxdb.BucketStringCompressionZstd
+	findErr = errors.New("unavailable")
+	if err := c.refresh(ctx); err != findErr {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := c.StringCompression(key(logsID)); got != tsm1.StringCompressionSnappy {
+		t.Fatalf("unexpected compression after failed look up: got %v", got)
+	}
+	if calls != 3 {
+		t.Fatalf("unexpected bucket look ups: got %d, exp 3", calls)
+	}
+}
+
+func TestBucketSettings_IndexValues(t *testing.T) {
+	orgID, logsID, metricsID := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+
+	buckets := []*influxdb.Bucket{
+		{ID: logsID, OrgID: orgID, IndexedFields: []string{"status", "code"}},
+		{ID: metricsID, OrgID: orgID},
+	}
+	finder := NewTestBucketFinder()
+	finder.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		return buckets, len(buckets), nil
+	}
+
+	c := newBucketSettings(finder)
+	if err := c.refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	key := func(bucketID influxdb.ID, field string) []byte {
+		return append(tsdb.EncodeNameSlice(orgID, bucketID), ",host=A#!~#"+field...)
+	}
+
+	for _, tc := range []struct {
+		key []byte
+		exp bool
+	}{
+		{key(logsID, "status"), true},
+		{key(logsID, "code"), true},
+		{key(logsID, "msg"), false},
+		{key(metricsID, "status"), false},
+		{key(influxdb.ID(4), "status"), false},
+		{[]byte("short"), false},
+	} {
+		if got := c.IndexValues(tc.key); got != tc.exp {
+			t.Fatalf("unexpected indexing of %q: got %v, exp %v", tc.key, got, tc.exp)
+		}
+	}
+
+	// A new indexed field is seen as soon as the bucket is updated.
+	c.update(&influxdb.Bucket{ID: metricsID, OrgID: orgID, IndexedFields: []string{"status"}})
+	if !c.IndexValues(key(metricsID, "status")) {
+		t.Fatal("expected field to be indexed after update")
+	}
+}
+
+func TestBucketSettings_WALDurability(t *testing.T) {
+	orgID, logsID, metricsID := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+
+	buckets := []*influxdb.Bucket{
+		{ID: logsID, OrgID: orgID, WALDurability: influxdb.BucketWALDurabilityAsync},
+		{ID: metricsID, OrgID: orgID},
+	}
+	finder := NewTestBucketFinder()
+	finder.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		return buckets, len(buckets), nil
+	}
+
+	c := newBucketSettings(finder)
+	if err := c.refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name []byte
+		exp  wal.Durability
+		ok   bool
+	}{
+		{tsdb.EncodeNameSlice(orgID, logsID), wal.DurabilityAsync, true},
+		{tsdb.EncodeNameSlice(orgID, metricsID), wal.DurabilityGroup, false},
+		{tsdb.EncodeNameSlice(orgID, influxdb.ID(4)), wal.DurabilityGroup, false},
+		{[]byte("short"), wal.DurabilityGroup, false},
+	} {
+		if got, ok := c.WALDurability(tc.name); got != tc.exp || ok != tc.ok {
+			t.Fatalf("unexpected durability of %q: got %v, %v, exp %v, %v", tc.name, got, ok, tc.exp, tc.ok)
+		}
+	}
+}
+
+func TestBucketSettings_UpdateDuringRefresh(t *testing.T) {
+	orgID, logsID := influxdb.ID(1), influxdb.ID(2)
+
+	c := newBucketSettings(nil)
+	finder := NewTestBucketFinder()
+	finder.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		// The bucket is updated once the look up has read it.
+		c.update(&influxdb.Bucket{ID: logsID, OrgID: orgID, WALDurability: influxdb.BucketWALDurabilityAsync})
+		return []*influxdb.Bucket{{ID: logsID, OrgID: orgID}}, 1, nil
+	}
+	c.BucketService = finder
+
+	if err := c.refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := c.WALDurability(tsdb.EncodeNameSlice(orgID, logsID)); got != wal.DurabilityAsync || !ok {
+		t.Fatalf("unexpected durability after refresh: got %v, %v", got, ok)
+	}
+}
@@ -0,0 +1,310 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/tsi1"
+	"go.uber.org/zap"
+)
+
+const (
+	// cardinalityMaxTagKeys is the maximum number of tag keys of a bucket whose
+	// values are counted.
+	cardinalityMaxTagKeys = 1000
+
+	// cardinalityMaxTagValues is the maximum number of values of a tag key
+	// counted.
+	cardinalityMaxTagValues = 10000
+
+	// cardinalitySampleInterval is how often the number of series of the
+	// buckets is sampled.
+	cardinalitySampleInterval = 10 * time.Minute
+
+	// cardinalitySampleN is the number of samples kept, a day of them.
+	cardinalitySampleN = 144
+)
+
+// FindBucketCardinality returns the series cardinality of the bucket.
+//
+// The number of series of the bucket is taken from the cardinality stats of
+// the index and the numbers of series of its measurements are estimated from
+// the sketches of the index. The values of each tag key are counted from the
+// index, up to a limit, and the growth of the bucket is taken from the samples
+// of the engine.
+func (e *Engine) FindBucketCardinality(ctx context.Context, orgID, bucketID influxdb.ID, opts influxdb.CardinalityOptions) (*influxdb.BucketCardinality, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	// Only the index of the bucket is needed for the scan, which holds a
+	// reference to it rather than e.mu.
+	e.mu.RLock()
+	if e.closing == nil {
+		e.mu.RUnlock()
+		return nil, ErrEngineClosed
+	}
+	index := e.shard(bucketID).index
+	ref, err := index.Acquire()
+	e.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	defer ref.Release()
+
+	name := tsdb.EncodeNameSlice(orgID, bucketID)
+	stats, err := index.MeasurementCardinalityStats()
+	if err != nil {
+		return nil, err
+	}
+	c := &influxdb.BucketCardinality{
+		BucketID: bucketID,
+		Series:   int64(stats[string(name)]),
+	}
+
+	estimates, err := index.MeasurementCardinalityEstimates(name)
+	if err != nil {
+		return nil, err
+	}
+	c.Measurements = make([]influxdb.MeasurementCardinality, 0, len(estimates))
+	for m, n := range estimates {
+		c.Measurements = append(c.Measurements, influxdb.MeasurementCardinality{Name: m, Series: n})
+	}
+	sort.Slice(c.Measurements, func(i, j int) bool {
+		a, b := c.Measurements[i], c.Measurements[j]
+		return a.Series > b.Series || (a.Series == b.Series && a.Name < b.Name)
+	})
+
+	if c.TagKeys, err = tagKeyCardinalities(ctx, index, name); err != nil {
+		return nil, err
+	}
+
+	if opts.Limit > 0 {
+		if len(c.Measurements) > opts.Limit {
+			c.Measurements = c.Measurements[:opts.Limit]
+		}
+		if len(c.TagKeys) > opts.Limit {
+			c.TagKeys = c.TagKeys[:opts.Limit]
+		}
+	}
+
+	c.Growth = append(e.cardinalitySamples.Series(name), influxdb.CardinalitySample{
+		Time:   time.Now().UTC(),
+		Series: c.Series,
+	})
+	return c, nil
+}
+
+// tagKeyCardinalities counts the values of the tag keys of name in index,
+// returning the keys with the most values first.
+func tagKeyCardinalities(ctx context.Context, index *tsi1.Index, name []byte) ([]influxdb.TagKeyCardinality, error) {
+	kitr, err := index.TagKeyIterator(name)
+	if err != nil {
+		return nil, err
+	} else if kitr == nil {
+		return []influxdb.TagKeyCardinality{}, nil
+	}
+	defer kitr.Close()
+
+	keys := make([]influxdb.TagKeyCardinality, 0)
+	for len(keys) < cardinalityMaxTagKeys {
+		key, err := kitr.Next()
+		if err != nil {
+			return nil, err
+		} else if key == nil {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		kc := influxdb.TagKeyCardinality{Key: string(key)}
+		switch {
+		case bytes.Equal(key, models.MeasurementTagKeyBytes):
+			kc.Key = datatypes.MeasurementKey
+		case bytes.Equal(key, models.FieldKeyTagKeyBytes):
+			kc.Key = datatypes.FieldKey
+		}
+		if kc.Values, kc.Truncated, err = countTagValues(index, name, key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, kc)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		return a.Values > b.Values || (a.Values == b.Values && a.Key < b.Key)
+	})
+	return keys, nil
+}
+
+// countTagValues counts the values of key in index up to
+// cardinalityMaxTagValues, returning whether there are more.
+func countTagValues(index *tsi1.Index, name, key []byte) (int64, bool, error) {
+	vitr, err := index.TagValueIterator(name, key)
+	if err != nil {
+		return 0, false, err
+	} else if vitr == nil {
+		return 0, false, nil
+	}
+	defer vitr.Close()
+
+	var n int64
+	for {
+		value, err := vitr.Next()
+		if err != nil {
+			return 0, false, err
+		} else if value == nil {
+			return n, false, nil
+		} else if n == cardinalityMaxTagValues {
+			return n, true, nil
+		}
+		n++
+	}
+}
+
+// runCardinalitySampler samples the number of series of the buckets when the
+// engine is opened and then every cardinalitySampleInterval. It must be called
+// with e.mu held.
+func (e *Engine) runCardinalitySampler() {
+	// The shared shard is not closed before the sampler stops and the shards
+	// of the buckets are not closed while sampling, so they can be used without
+	// holding e.mu.
+	sample := func() {
+		stats, err := e.measurementCardinalityStats()
+		if err != nil {
+			e.logger.Error("Unable to sample series cardinality", zap.Error(err))
+			return
+		}
+		e.cardinalitySamples.Add(time.Now().UTC(), stats)
+	}
+	sample()
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+
+		ticker := time.NewTicker(cardinalitySampleInterval)
+		defer ticker.Stop()
+
+		for {
+			// It's safe to read closing without a lock because it's never
+			// modified if this goroutine is active.
+			select {
+			case <-e.closing:
+				return
+			case <-ticker.C:
+				sample()
+			}
+		}
+	}()
+}
+
+// cardinalitySamples keeps the last cardinalitySampleN samples of the number
+// of series of each bucket.
+type cardinalitySamples struct {
+	mu      sync.Mutex
+	times   []time.Time
+	samples []tsi1.MeasurementCardinalityStats
+}
+
+// Add adds the number of series of the buckets at time t, dropping the oldest
+// sample if there are too many.
+func (s *cardinalitySamples) Add(t time.Time, stats tsi1.MeasurementCardinalityStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.samples) == cardinalitySampleN {
+		s.times, s.samples = s.times[1:], s.samples[1:]
+	}
+	s.times = append(s.times, t)
+	s.samples = append(s.samples, stats)
+}
+
+// Series returns the samples of the number of series of name, oldest first.
+func (s *cardinalitySamples) Series(name []byte) []influxdb.CardinalitySample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := make([]influxdb.CardinalitySample, 0, len(s.samples)+1)
+	for i, stats := range s.samples {
+		a = append(a, influxdb.CardinalitySample{Time: s.times[i], Series: int64(stats[string(name)])})
+	}
+	return a
+}
+
+// limitSeries drops the points of collection that would create more series in
+// a bucket or in a measurement than the configured limits allow, calling drop
+// with the reason for each of them. The number of series is estimated from the
+// sketches of the index of s, so the limits are approximate. It must be called
+// with e.mu held.
+func (e *Engine) limitSeries(s *shard, collection *tsdb.SeriesCollection, drop func(key []byte, reason string)) error {
+	maxBucket, maxMeasurement := int64(e.config.MaxSeriesPerBucket), int64(e.config.MaxSeriesPerMeasurement)
+	if maxBucket <= 0 && maxMeasurement <= 0 {
+		return nil
+	}
+
+	var (
+		created      = make(map[string]struct{})
+		buckets      = make(map[string]int64)
+		measurements = make(map[string]int64)
+		j            int
+	)
+	for iter := collection.Iterator(); iter.Next(); {
+		name, tags := iter.Name(), iter.Tags()
+
+		// Points of existing series are always written.
+		if _, ok := created[string(iter.Key())]; ok || s.sfile.HasSeries(name, tags, nil) {
+			collection.Copy(j, iter.Index())
+			j++
+			continue
+		}
+
+		// Measurements are the value of the first tag, as validated by WritePoints.
+		m := tags[0].Value
+		mkey := string(name) + string(m)
+
+		bucketN, ok := buckets[string(name)]
+		if !ok && maxBucket > 0 {
+			n, err := s.index.SeriesCardinalityEstimate(name)
+			if err != nil {
+				return err
+			}
+			bucketN = n
+		}
+		buckets[string(name)] = bucketN
+
+		measurementN, ok := measurements[mkey]
+		if !ok && maxMeasurement > 0 {
+			n, err := s.index.MeasurementCardinalityEstimate(name, m)
+			if err != nil {
+				return err
+			}
+			measurementN = n
+		}
+		measurements[mkey] = measurementN
+
+		if maxBucket > 0 && bucketN >= maxBucket {
+			drop(iter.Key(), fmt.Sprintf("max-series-per-bucket limit exceeded: measurement %q: (%d/%d)", m, bucketN, maxBucket))
+			continue
+		}
+		if maxMeasurement > 0 && measurementN >= maxMeasurement {
+			drop(iter.Key(), fmt.Sprintf("max-series-per-measurement limit exceeded: measurement %q: (%d/%d)", m, measurementN, maxMeasurement))
+			continue
+		}
+
+		created[string(iter.Key())] = struct{}{}
+		buckets[string(name)] = bucketN + 1
+		measurements[mkey] = measurementN + 1
+		collection.Copy(j, iter.Index())
+		j++
+	}
+	collection.Truncate(j)
+	return nil
+}
@@ -17,6 +17,7 @@ const (
 	DefaultIndexDirectoryName      = "index"
 	DefaultWALDirectoryName        = "wal"
 	DefaultEngineDirectoryName     = "data"
+	DefaultBucketsDirectoryName    = "buckets"
 )
 
 // Config holds the configuration for an Engine.
@@ -24,6 +25,19 @@ type Config struct {
 	// Frequency of retention in seconds.
 	RetentionInterval toml.Duration `toml:"retention-interval"`
 
+	// Maximum number of series of each bucket and of each measurement.
+	// Writes creating more series are dropped. Zero disables the limit.
+	MaxSeriesPerBucket      int `toml:"max-series-per-bucket"`
+	MaxSeriesPerMeasurement int `toml:"max-series-per-measurement"`
+
+	// Store each bucket in its own series file, index, WAL and TSM files
+	// under the buckets directory, so that a bucket is dropped, compacted and
+	// backed up on its own. The buckets written before it was enabled stay in
+	// the shared files. Every shard has caches of its own, so the cache memory
+	// limits apply to each bucket and the memory used grows with the number of
+	// buckets.
+	BucketShards bool `toml:"bucket-shards"`
+
 	// Series file config.
 	SeriesFilePath string `toml:"series-file-path"` // Overrides the default path.
 
@@ -85,3 +99,8 @@ func (c Config) GetEnginePath(base string) string {
 	}
 	return filepath.Join(base, DefaultEngineDirectoryName)
 }
+
+// GetBucketsPath returns the path to the directory of the bucket shards.
+func (c Config) GetBucketsPath(base string) string {
+	return filepath.Join(base, DefaultBucketsDirectoryName)
+}
@@ -8,7 +8,10 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,7 +26,6 @@ import (
 	"github.com/influxdata/influxdb/v2/tsdb/seriesfile"
 	"github.com/influxdata/influxdb/v2/tsdb/tsi1"
 	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
-	"github.com/influxdata/influxdb/v2/tsdb/value"
 	"github.com/influxdata/influxql"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -34,6 +36,14 @@ import (
 // Static objects to prevent small allocs.
 var timeBytes = []byte("time")
 
+// nameLen is the length of the measurement names of the points, which encode
+// the organization and bucket IDs.
+const nameLen = 16
+
+// droppedShardSuffix is appended to the directory of the shard of a dropped
+// bucket until its files are removed.
+const droppedShardSuffix = ".dropped"
+
 // ErrEngineClosed is returned when a caller attempts to use the engine while
 // it's closed.
 var ErrEngineClosed = errors.New("engine is closed")
@@ -54,14 +64,32 @@ type Engine struct {
 
 	mu      sync.RWMutex
 	closing chan struct{} // closing returns the zero value when the engine is shutting down.
-	index   *tsi1.Index
-	sfile   *seriesfile.SeriesFile
-	engine  *tsm1.Engine
-	wal     *wal.WAL
+
+	// shared stores the buckets without a shard of their own.
+	shared *shard
+
+	// shards are the shards of the buckets stored on their own, by bucket ID.
+	// dropping are the buckets whose shards are being removed, which no shard
+	// is opened for until their files are gone.
+	shardsMu sync.RWMutex
+	shards   map[influxdb.ID]*shard
+	dropping map[influxdb.ID]struct{}
+
+	// shardOptions are applied to the TSM engine of every shard.
+	shardOptions      []func(*tsm1.Engine)
+	compactionLimiter limiter.Fixed
+
+	// backups are the snapshots of the bucket shards taken with each backup
+	// of the shared shard, by backup ID, until their files are fetched.
+	backupsMu sync.Mutex
+	backups   map[int]map[influxdb.ID]*bucketBackup
 
 	retentionEnforcer        runner
 	retentionEnforcerLimiter runnable
 
+	bucketSettings     *bucketSettings
+	cardinalitySamples cardinalitySamples
+
 	defaultMetricLabels prometheus.Labels
 
 	// Tracks all goroutines started by the Engine.
@@ -73,19 +101,26 @@ type Engine struct {
 // Option provides a set
 type Option func(*Engine)
 
+// withShardEngine applies fn to the TSM engine of every shard.
+func withShardEngine(fn func(*tsm1.Engine)) Option {
+	return func(e *Engine) {
+		e.shardOptions = append(e.shardOptions, fn)
+	}
+}
+
 // WithTSMFilenameFormatter sets a function on the underlying tsm1.Engine to specify
 // how TSM files are named.
 func WithTSMFilenameFormatter(fn tsm1.FormatFileNameFunc) Option {
-	return func(e *Engine) {
-		e.engine.WithFormatFileNameFunc(fn)
-	}
+	return withShardEngine(func(engine *tsm1.Engine) {
+		engine.WithFormatFileNameFunc(fn)
+	})
 }
 
 // WithCurrentGenerationFunc sets a function for obtaining the current generation.
 func WithCurrentGenerationFunc(fn func() int) Option {
-	return func(e *Engine) {
-		e.engine.WithCurrentGenerationFunc(fn)
-	}
+	return withShardEngine(func(engine *tsm1.Engine) {
+		engine.WithCurrentGenerationFunc(fn)
+	})
 }
 
 // WithEngineID sets an engine id, which can be useful for logging when multiple
@@ -111,7 +146,7 @@ func WithNodeID(id int) Option {
 // metrics are labelled correctly.
 func WithRetentionEnforcer(finder BucketFinder) Option {
 	return func(e *Engine) {
-		e.retentionEnforcer = newRetentionEnforcer(e, e.engine, finder)
+		e.retentionEnforcer = newRetentionEnforcer(e, e, finder)
 	}
 }
 
@@ -125,18 +160,32 @@ func WithRetentionEnforcerLimiter(f runnable) Option {
 	}
 }
 
-// WithFileStoreObserver makes the engine have the provided file store observer.
-func WithFileStoreObserver(obs tsm1.FileStoreObserver) Option {
+// WithBucketSettings makes the engine compress the string values and index
+// the field values of each bucket as set on the bucket, looking up the buckets
+// with finder. Compactions re-encode the string values of the buckets whose
+// compression changed, and index the values of the fields indexed since.
+func WithBucketSettings(finder BucketFinder) Option {
 	return func(e *Engine) {
-		e.engine.WithFileStoreObserver(obs)
+		e.bucketSettings = newBucketSettings(finder)
+		withShardEngine(func(engine *tsm1.Engine) {
+			engine.WithStringCompressionFunc(e.bucketSettings.StringCompression)
+			engine.WithValueIndexFunc(e.bucketSettings.IndexValues)
+		})(e)
 	}
 }
 
+// WithFileStoreObserver makes the engine have the provided file store observer.
+func WithFileStoreObserver(obs tsm1.FileStoreObserver) Option {
+	return withShardEngine(func(engine *tsm1.Engine) {
+		engine.WithFileStoreObserver(obs)
+	})
+}
+
 // WithCompactionPlanner makes the engine have the provided compaction planner.
 func WithCompactionPlanner(planner tsm1.CompactionPlanner) Option {
-	return func(e *Engine) {
-		e.engine.WithCompactionPlanner(planner)
-	}
+	return withShardEngine(func(engine *tsm1.Engine) {
+		engine.WithCompactionPlanner(planner)
+	})
 }
 
 // WithCompactionLimiter allows the caller to set the limiter that a storage
@@ -144,54 +193,45 @@ func WithCompactionPlanner(planner tsm1.CompactionPlanner) Option {
 // share the same limiter.
 func WithCompactionLimiter(limiter limiter.Fixed) Option {
 	return func(e *Engine) {
-		e.engine.WithCompactionLimiter(limiter)
+		e.compactionLimiter = limiter
 	}
 }
 
 // WithCompactionSemaphore sets the semaphore used to coordinate full compactions
 // across multiple storage engines.
 func WithCompactionSemaphore(s influxdb.Semaphore) Option {
-	return func(e *Engine) {
-		e.engine.SetSemaphore(s)
-	}
+	return withShardEngine(func(engine *tsm1.Engine) {
+		engine.SetSemaphore(s)
+	})
 }
 
 // NewEngine initialises a new storage engine, including a series file, index and
-// TSM engine.
+// TSM engine shared by the buckets, and the shards of the buckets stored on
+// their own.
 func NewEngine(path string, c Config, options ...Option) *Engine {
 	e := &Engine{
 		config:              c,
 		path:                path,
+		shards:              make(map[influxdb.ID]*shard),
+		dropping:            make(map[influxdb.ID]struct{}),
+		backups:             make(map[int]map[influxdb.ID]*bucketBackup),
 		defaultMetricLabels: prometheus.Labels{},
 		logger:              zap.NewNop(),
 	}
 
-	// Initialize series file.
-	e.sfile = seriesfile.NewSeriesFile(c.GetSeriesFilePath(path))
-	e.sfile.LargeWriteThreshold = c.SeriesFile.LargeSeriesWriteThreshold
-
-	// Initialise index.
-	e.index = tsi1.NewIndex(e.sfile, c.Index,
-		tsi1.WithPath(c.GetIndexPath(path)))
-
-	// Initialize WAL
-	e.wal = wal.NewWAL(c.GetWALPath(path))
-	e.wal.WithFsyncDelay(time.Duration(c.WAL.FsyncDelay))
-	e.wal.SetEnabled(c.WAL.Enabled)
-
-	// Initialise Engine
-	e.engine = tsm1.NewEngine(c.GetEnginePath(path), e.index, c.Engine, tsm1.WithSnapshotter(e))
-
 	// Apply options.
 	for _, option := range options {
 		option(e)
 	}
 
+	e.shared = e.initShard(newShard(path, c), "")
+
+	// The compactions of all the shards are limited together.
+	if e.compactionLimiter == nil {
+		e.compactionLimiter = e.shared.engine.CompactionLimiter()
+	}
+
 	// Set default metrics labels.
-	e.engine.SetDefaultMetricLabels(e.defaultMetricLabels)
-	e.sfile.SetDefaultMetricLabels(e.defaultMetricLabels)
-	e.index.SetDefaultMetricLabels(e.defaultMetricLabels)
-	e.wal.SetDefaultMetricLabels(e.defaultMetricLabels)
 	if r, ok := e.retentionEnforcer.(*retentionEnforcer); ok {
 		r.SetDefaultMetricLabels(e.defaultMetricLabels)
 	}
@@ -199,6 +239,49 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	return e
 }
 
+// initShard applies the options of the engine to s, and labels its metrics
+// with bucketID. The shared shard has an empty bucket ID, since every shard
+// must have the same set of metric labels.
+func (e *Engine) initShard(s *shard, bucketID string) *shard {
+	for _, option := range e.shardOptions {
+		option(s.engine)
+	}
+	if e.compactionLimiter != nil {
+		s.engine.WithCompactionLimiter(e.compactionLimiter)
+	}
+	labels := prometheus.Labels{"bucket_id": bucketID}
+	for k, v := range e.defaultMetricLabels {
+		labels[k] = v
+	}
+	s.SetDefaultMetricLabels(labels)
+	return s
+}
+
+// newBucketShard returns a new shard storing the bucket on its own.
+func (e *Engine) newBucketShard(bucketID influxdb.ID) *shard {
+	s := e.initShard(newShard(e.bucketShardPath(bucketID), e.bucketShardConfig(bucketID)), bucketID.String())
+	s.WithLogger(e.logger.With(zap.Stringer("bucket_id", bucketID)))
+	return s
+}
+
+// bucketShardPath returns the directory of the shard of the bucket.
+func (e *Engine) bucketShardPath(bucketID influxdb.ID) string {
+	return filepath.Join(e.config.GetBucketsPath(e.path), bucketID.String())
+}
+
+// bucketShardConfig returns the configuration of the shard of the bucket.
+func (e *Engine) bucketShardConfig(bucketID influxdb.ID) Config {
+	// The components of a bucket shard are always in their default paths. The
+	// cache sizes are not divided among the shards, see Config.BucketShards.
+	c := e.config
+	c.SeriesFilePath, c.IndexPath, c.WALPath, c.EnginePath = "", "", "", ""
+
+	// The TSM files of each shard are numbered on their own, their objects are
+	// kept apart in the storage tier.
+	c.Engine.Tier = c.Engine.Tier.WithPrefix(bucketID.String())
+	return c
+}
+
 // WithLogger sets the logger on the Store. It must be called before Open.
 func (e *Engine) WithLogger(log *zap.Logger) {
 	fields := []zap.Field{}
@@ -212,13 +295,13 @@ func (e *Engine) WithLogger(log *zap.Logger) {
 	fields = append(fields, zap.String("service", "storage-engine"))
 
 	e.logger = log.With(fields...)
-	e.sfile.WithLogger(e.logger)
-	e.index.WithLogger(e.logger)
-	e.engine.WithLogger(e.logger)
-	e.wal.WithLogger(e.logger)
+	e.shared.WithLogger(e.logger)
 	if r, ok := e.retentionEnforcer.(*retentionEnforcer); ok {
 		r.WithLogger(e.logger)
 	}
+	if e.bucketSettings != nil {
+		e.bucketSettings.WithLogger(e.logger)
+	}
 }
 
 // PrometheusCollectors returns all the prometheus collectors associated with
@@ -246,17 +329,20 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
-	// Open the services in order and clean up if any fail.
-	var oh openHelper
-	oh.Open(ctx, e.sfile)
-	oh.Open(ctx, e.index)
-	oh.Open(ctx, e.wal)
-	oh.Open(ctx, e.engine)
-	if err := oh.Done(); err != nil {
+	// The settings of the buckets are known before the shards compact or
+	// write anything.
+	if e.bucketSettings != nil {
+		e.refreshBucketSettings(ctx)
+	}
+
+	if err := e.shared.Open(ctx); err != nil {
 		return err
 	}
 
-	if err := e.replayWAL(); err != nil {
+	// The buckets stored on their own stay so even once the bucket shards are
+	// disabled.
+	if err := e.openBucketShards(ctx); err != nil {
+		e.shared.Close()
 		return err
 	}
 
@@ -268,64 +354,66 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 	if e.retentionEnforcer != nil {
 		e.runRetentionEnforcer()
 	}
+	if e.bucketSettings != nil {
+		e.runBucketSettingsRefresher()
+	}
+	e.runCardinalitySampler()
 
 	return nil
 }
 
-// replayWAL reads the WAL segment files and replays them.
-func (e *Engine) replayWAL() error {
-	if !e.config.WAL.Enabled {
+// openBucketShards opens the shards of the buckets stored on their own,
+// removing the files of the shards left over by bucket drops.
+func (e *Engine) openBucketShards(ctx context.Context) error {
+	dir := e.config.GetBucketsPath(e.path)
+	fis, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
 		return nil
-	}
-	now := time.Now()
-
-	walPaths, err := wal.SegmentFileNames(e.wal.Path())
-	if err != nil {
+	} else if err != nil {
 		return err
 	}
 
-	// TODO(jeff): we should just do snapshots and wait for them so that we don't hit
-	// OOM situations when reloading huge WALs.
-
-	// Disable the max size during loading
-	limit := e.engine.Cache.MaxSize()
-	defer func() { e.engine.Cache.SetMaxSize(limit) }()
-	e.engine.Cache.SetMaxSize(0)
-
-	// Execute all the entries in the WAL again
-	reader := wal.NewWALReader(walPaths)
-	reader.WithLogger(e.logger)
-	err = reader.Read(func(entry wal.WALEntry) error {
-		switch en := entry.(type) {
-		case *wal.WriteWALEntry:
-			points := tsm1.ValuesToPoints(en.Values)
-			err := e.writePointsLocked(context.Background(), tsdb.NewSeriesCollection(points), en.Values)
-			if _, ok := err.(tsdb.PartialWriteError); ok {
-				err = nil
+	for _, fi := range fis {
+		if name := fi.Name(); strings.HasSuffix(name, droppedShardSuffix) {
+			bucketID, err := influxdb.IDFromString(strings.TrimSuffix(name, droppedShardSuffix))
+			if err != nil {
+				e.logger.Info("Skipping unexpected file in buckets directory", zap.String("path", filepath.Join(dir, name)))
+				continue
 			}
-			return err
-
-		case *wal.DeleteBucketRangeWALEntry:
-			var pred tsm1.Predicate
-			if len(en.Predicate) > 0 {
-				pred, err = tsm1.UnmarshalPredicate(en.Predicate)
-				if err != nil {
-					return err
-				}
+			if err := e.removeDroppedShard(ctx, *bucketID); err != nil {
+				return err
 			}
+			continue
+		}
 
-			return e.deleteBucketRangeLocked(context.Background(), en.OrgID, en.BucketID, en.Min, en.Max, pred)
+		bucketID, err := influxdb.IDFromString(fi.Name())
+		if err != nil || !fi.IsDir() {
+			e.logger.Info("Skipping unexpected file in buckets directory", zap.String("path", filepath.Join(dir, fi.Name())))
+			continue
 		}
 
-		return nil
-	})
+		s := e.newBucketShard(*bucketID)
+		if err := s.Open(ctx); err != nil {
+			e.closeBucketShards()
+			return err
+		}
+		e.shards[*bucketID] = s
+	}
+	return nil
+}
 
-	e.logger.Info("Reloaded WAL",
-		zap.String("path", e.wal.Path()),
-		zap.Duration("duration", time.Since(now)),
-		zap.Error(err))
+// closeBucketShards closes the shards of the buckets stored on their own,
+// returning the first error.
+func (e *Engine) closeBucketShards() error {
+	e.shardsMu.Lock()
+	defer e.shardsMu.Unlock()
 
-	return err
+	var ch closeHelper
+	for bucketID, s := range e.shards {
+		ch.Close(s)
+		delete(e.shards, bucketID)
+	}
+	return ch.Done()
 }
 
 // runRetentionEnforcer runs the retention enforcer in a separate goroutine.
@@ -415,12 +503,80 @@ func (e *Engine) Close() error {
 	defer e.mu.Unlock()
 	e.closing = nil
 
-	var ch closeHelper
-	ch.Close(e.engine)
-	ch.Close(e.wal)
-	ch.Close(e.index)
-	ch.Close(e.sfile)
-	return ch.Done()
+	err := e.closeBucketShards()
+	if serr := e.shared.Close(); err == nil {
+		err = serr
+	}
+	return err
+}
+
+// shard returns the shard storing the bucket.
+func (e *Engine) shard(bucketID influxdb.ID) *shard {
+	e.shardsMu.RLock()
+	defer e.shardsMu.RUnlock()
+	if s, ok := e.shards[bucketID]; ok {
+		return s
+	}
+	return e.shared
+}
+
+// writeShard returns the shard the points of the bucket are written to. With
+// the bucket shards enabled, a shard is created for each bucket not already in
+// the shared shard. It must be called with e.mu held.
+func (e *Engine) writeShard(ctx context.Context, orgID, bucketID influxdb.ID) (*shard, error) {
+	if s := e.shard(bucketID); s != e.shared || !e.config.BucketShards {
+		return s, nil
+	}
+
+	if ok, err := e.shared.hasBucket(orgID, bucketID); err != nil {
+		return nil, err
+	} else if ok {
+		return e.shared, nil
+	}
+
+	e.shardsMu.Lock()
+	defer e.shardsMu.Unlock()
+	if s, ok := e.shards[bucketID]; ok {
+		return s, nil
+	}
+	if _, ok := e.dropping[bucketID]; ok {
+		return nil, ErrBucketDropping
+	}
+
+	s := e.newBucketShard(bucketID)
+	if err := s.Open(ctx); err != nil {
+		return nil, err
+	}
+	e.shards[bucketID] = s
+	return s, nil
+}
+
+// bucketShards returns the IDs of the buckets stored on their own, in order,
+// and their shards.
+func (e *Engine) bucketShards() ([]influxdb.ID, map[influxdb.ID]*shard) {
+	e.shardsMu.RLock()
+	defer e.shardsMu.RUnlock()
+
+	ids := make([]influxdb.ID, 0, len(e.shards))
+	shards := make(map[influxdb.ID]*shard, len(e.shards))
+	for bucketID, s := range e.shards {
+		ids = append(ids, bucketID)
+		shards[bucketID] = s
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, shards
+}
+
+// allShards returns the shared shard and the shards of the buckets stored on
+// their own.
+func (e *Engine) allShards() []*shard {
+	ids, shards := e.bucketShards()
+	all := make([]*shard, 0, len(ids)+1)
+	all = append(all, e.shared)
+	for _, bucketID := range ids {
+		all = append(all, shards[bucketID])
+	}
+	return all
 }
 
 // CreateSeriesCursor creates a SeriesCursor for usage with the read service.
@@ -431,7 +587,8 @@ func (e *Engine) CreateSeriesCursor(ctx context.Context, orgID, bucketID influxd
 		return nil, ErrEngineClosed
 	}
 
-	return newSeriesCursor(orgID, bucketID, e.index, e.sfile, cond)
+	s := e.shard(bucketID)
+	return newSeriesCursor(orgID, bucketID, s.index, s.sfile, cond)
 }
 
 // CreateCursorIterator creates a CursorIterator for usage with the read service.
@@ -441,7 +598,7 @@ func (e *Engine) CreateCursorIterator(ctx context.Context) (cursors.CursorIterat
 	if e.closing == nil {
 		return nil, ErrEngineClosed
 	}
-	return e.engine.CreateCursorIterator(ctx)
+	return &shardCursorIterator{e: e, itrs: make(map[*shard]cursors.CursorIterator)}, nil
 }
 
 // WritePoints writes the provided points to the engine.
@@ -521,6 +678,101 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 		return ErrEngineClosed
 	}
 
+	// Find the shards of the buckets of the points.
+	var (
+		shards = make(map[string]*shard)
+		order  []*shard
+	)
+	durability := wal.DurabilityNone
+	for _, name := range collection.Names {
+		if _, ok := shards[string(name)]; ok {
+			continue
+		}
+		durability = durability.Max(e.walDurability(name))
+
+		s := e.shared
+		if len(name) >= nameLen {
+			var err error
+			orgID, bucketID := tsdb.DecodeNameSlice(name)
+			if s, err = e.writeShard(ctx, orgID, bucketID); err != nil {
+				return err
+			}
+		}
+		shards[string(name)] = s
+		if !containsShard(order, s) {
+			order = append(order, s)
+		}
+	}
+
+	if len(order) == 0 {
+		durability = e.walDurability(nil)
+	}
+	if r := writeReportFromContext(ctx); r != nil {
+		r.Reported, r.Durability = true, durability
+	}
+
+	if len(order) == 0 {
+		return e.writeShardPoints(ctx, e.shared, collection, durability, dropPoint)
+	} else if len(order) == 1 {
+		return e.writeShardPoints(ctx, order[0], collection, durability, dropPoint)
+	}
+
+	// Write the points of each shard on their own.
+	groups := make(map[*shard][]models.Point, len(order))
+	for i, name := range collection.Names {
+		s := shards[string(name)]
+		groups[s] = append(groups[s], collection.Points[i])
+	}
+	for _, s := range order {
+		err := e.writeShardPoints(ctx, s, tsdb.NewSeriesCollection(groups[s]), durability, dropPoint)
+		if perr, ok := err.(tsdb.PartialWriteError); ok {
+			for _, key := range perr.DroppedKeys {
+				dropPoint(key, perr.Reason)
+			}
+		} else if err != nil {
+			return err
+		}
+	}
+	return collection.PartialWriteError()
+}
+
+// containsShard returns true if s is one of shards.
+func containsShard(shards []*shard, s *shard) bool {
+	for _, other := range shards {
+		if other == s {
+			return true
+		}
+	}
+	return false
+}
+
+// walDurability returns the WAL durability of the writes of the points named
+// name: the durability of their bucket if it has one, or else the durability
+// of the engine.
+func (e *Engine) walDurability(name []byte) wal.Durability {
+	d := e.shared.wal.Durability()
+	if d == wal.DurabilityNone || e.bucketSettings == nil {
+		return d
+	}
+	if bd, ok := e.bucketSettings.WALDurability(name); ok {
+		return bd
+	}
+	return d
+}
+
+// writeShardPoints writes the points of collection to s, returning once they
+// are as durable in its WAL as d. It must be called with e.mu held.
+func (e *Engine) writeShardPoints(ctx context.Context, s *shard, collection *tsdb.SeriesCollection, d wal.Durability, dropPoint func(key []byte, reason string)) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Drop the points older than the max lateness, and the points of new series
+	// beyond the cardinality limits, before they reach the WAL and the series file.
+	e.limitLateness(s, collection, dropPoint)
+	if err := e.limitSeries(s, collection, dropPoint); err != nil {
+		return err
+	}
+
 	// Convert the collection to values for adding to the WAL/Cache.
 	values, err := tsm1.CollectionToValues(collection)
 	if err != nil {
@@ -528,84 +780,99 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 	}
 
 	// Add the write to the WAL to be replayed if there is a crash or shutdown.
-	if _, err := e.wal.WriteMulti(ctx, values); err != nil {
+	if _, err := s.wal.WriteMultiDurability(ctx, values, d); err != nil {
 		return err
 	}
 
-	return e.writePointsLocked(ctx, collection, values)
+	return s.writePointsLocked(ctx, collection, values)
 }
 
-// writePointsLocked does the work of writing points and must be called under some sort of lock.
-func (e *Engine) writePointsLocked(ctx context.Context, collection *tsdb.SeriesCollection, values map[string][]value.Value) error {
-	span, _ := tracing.StartSpanFromContext(ctx)
-	defer span.Finish()
-
-	// TODO(jeff): keep track of the values in the collection so that partial write
-	// errors get tracked all the way. Right now, the engine doesn't drop any values
-	// but if it ever did, the errors could end up missing some data.
-
-	// Add new series to the index and series file.
-	if err := e.index.CreateSeriesListIfNotExists(collection); err != nil {
-		return err
+// limitLateness drops the points of collection older than the max lateness of
+// the cache, calling drop with the reason for each of them. It must be called
+// with e.mu held.
+func (e *Engine) limitLateness(s *shard, collection *tsdb.SeriesCollection, drop func(key []byte, reason string)) {
+	maxLateness := time.Duration(e.config.Engine.Cache.MaxLateness)
+	if maxLateness <= 0 {
+		return
 	}
 
-	// If there was a PartialWriteError, that means the passed in values may contain
-	// more than the points so we need to recreate them.
-	if collection.PartialWriteError() != nil {
-		var err error
-		values, err = tsm1.CollectionToValues(collection)
-		if err != nil {
-			return err
+	cutoff, j, dropped := time.Now().Add(-maxLateness).UnixNano(), 0, 0
+	for iter := collection.Iterator(); iter.Next(); {
+		if iter.Point().UnixNano() < cutoff {
+			drop(iter.Key(), fmt.Sprintf("max-lateness exceeded: point older than %s", maxLateness))
+			dropped++
+			continue
 		}
+		collection.Copy(j, iter.Index())
+		j++
 	}
+	collection.Truncate(j)
+	s.engine.Cache.AddLateValuesDropped(dropped)
+}
 
-	// Write the values to the engine.
-	if err := e.engine.WriteValues(values); err != nil {
-		return err
+// WriteSnapshot snapshots the caches of all the shards to TSM files.
+func (e *Engine) WriteSnapshot(ctx context.Context, status tsm1.CacheStatus) error {
+	for _, s := range e.allShards() {
+		if err := s.engine.WriteSnapshot(ctx, status); err != nil {
+			return err
+		}
 	}
-
-	return collection.PartialWriteError()
+	return nil
 }
 
-// AcquireSegments closes the current WAL segment, gets the set of all the currently closed
-// segments, and calls the callback. It does all of this under the lock on the engine.
-func (e *Engine) AcquireSegments(ctx context.Context, fn func(segs []string) error) error {
-	span, _ := tracing.StartSpanFromContext(ctx)
+// DeleteBucket deletes an entire bucket from the storage engine. The files of
+// a bucket stored on its own are removed.
+func (e *Engine) DeleteBucket(ctx context.Context, orgID, bucketID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
-	e.mu.Lock()
-	defer e.mu.Unlock()
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
 
-	if err := e.wal.CloseSegment(); err != nil {
-		return err
+	e.shardsMu.Lock()
+	s, ok := e.shards[bucketID]
+	if ok {
+		delete(e.shards, bucketID)
+		e.dropping[bucketID] = struct{}{}
 	}
+	e.shardsMu.Unlock()
 
-	segments, err := e.wal.ClosedSegments()
-	if err != nil {
-		return err
+	if !ok {
+		return e.shared.deleteBucketRange(ctx, orgID, bucketID, math.MinInt64, math.MaxInt64, nil)
 	}
 
-	return fn(segments)
+	defer func() {
+		e.shardsMu.Lock()
+		delete(e.dropping, bucketID)
+		e.shardsMu.Unlock()
+	}()
+	return e.dropShard(ctx, bucketID, s)
 }
 
-// CommitSegments calls the callback and if that does not return an error, removes the segment
-// files from the WAL. It does all of this under the lock on the engine.
-func (e *Engine) CommitSegments(ctx context.Context, segs []string, fn func() error) error {
-	e.mu.Lock()
-	defer e.mu.Unlock()
+// dropShard closes s and removes its files. The directory of the shard is
+// renamed before its files are removed so that it is not opened again if
+// they are not all removed, they are removed when the engine is opened.
+func (e *Engine) dropShard(ctx context.Context, bucketID influxdb.ID, s *shard) error {
+	err := s.Close()
 
-	if err := fn(); err != nil {
-		return err
+	if rerr := os.Rename(s.path, s.path+droppedShardSuffix); rerr != nil {
+		return multierr.Append(err, rerr)
 	}
-
-	return e.wal.Remove(ctx, segs)
+	return multierr.Append(err, e.removeDroppedShard(ctx, bucketID))
 }
 
-// DeleteBucket deletes an entire bucket from the storage engine.
-func (e *Engine) DeleteBucket(ctx context.Context, orgID, bucketID influxdb.ID) error {
-	span, ctx := tracing.StartSpanFromContext(ctx)
-	defer span.Finish()
-	return e.DeleteBucketRange(ctx, orgID, bucketID, math.MinInt64, math.MaxInt64)
+// removeDroppedShard removes the files of the dropped shard of the bucket,
+// starting with the objects of its files in the storage tier.
+func (e *Engine) removeDroppedShard(ctx context.Context, bucketID influxdb.ID) error {
+	dropped := e.bucketShardPath(bucketID) + droppedShardSuffix
+	c := e.bucketShardConfig(bucketID)
+	if err := tsm1.DeleteTierObjects(ctx, c.Engine.Tier, c.GetEnginePath(dropped)); err != nil {
+		return err
+	}
+	return os.RemoveAll(dropped)
 }
 
 // DeleteBucketRange deletes an entire bucket from the storage engine.
@@ -619,12 +886,7 @@ func (e *Engine) DeleteBucketRange(ctx context.Context, orgID, bucketID influxdb
 		return ErrEngineClosed
 	}
 
-	// Add the delete to the WAL to be replayed if there is a crash or shutdown.
-	if _, err := e.wal.DeleteBucketRange(orgID, bucketID, min, max, nil); err != nil {
-		return err
-	}
-
-	return e.deleteBucketRangeLocked(ctx, orgID, bucketID, min, max, nil)
+	return e.shard(bucketID).deleteBucketRange(ctx, orgID, bucketID, min, max, nil)
 }
 
 // DeleteBucketRangePredicate deletes data within a bucket from the storage engine. Any data
@@ -639,63 +901,56 @@ func (e *Engine) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID
 		return ErrEngineClosed
 	}
 
-	var predData []byte
-	var err error
-	if pred != nil {
-		// Marshal the predicate to add it to the WAL.
-		predData, err = pred.Marshal()
-		if err != nil {
-			return err
-		}
-	}
-
-	// Add the delete to the WAL to be replayed if there is a crash or shutdown.
-	if _, err := e.wal.DeleteBucketRange(orgID, bucketID, min, max, predData); err != nil {
-		return err
-	}
-
-	return e.deleteBucketRangeLocked(ctx, orgID, bucketID, min, max, pred)
-}
-
-// deleteBucketRangeLocked does the work of deleting a bucket range and must be called under
-// some sort of lock.
-func (e *Engine) deleteBucketRangeLocked(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred tsm1.Predicate) error {
-	// TODO(edd): we need to clean up how we're encoding the prefix so that we
-	// don't have to remember to get it right everywhere we need to touch TSM data.
-	encoded := tsdb.EncodeName(orgID, bucketID)
-	name := models.EscapeMeasurement(encoded[:])
-
-	return e.engine.DeletePrefixRange(ctx, name, min, max, pred)
+	return e.shard(bucketID).deleteBucketRange(ctx, orgID, bucketID, min, max, pred)
 }
 
 // CreateBackup creates a "snapshot" of all TSM data in the Engine.
-//   1) Snapshot the cache to ensure the backup includes all data written before now.
-//   2) Create hard links to all TSM files, in a new directory within the engine root directory.
-//   3) Return a unique backup ID (invalid after the process terminates) and list of files.
+//  1. Snapshot the cache to ensure the backup includes all data written before now.
+//  2. Create hard links to all TSM files, in a new directory within the engine root directory.
+//  3. Return a unique backup ID (invalid after the process terminates) and list of files.
+//
+// The files of the buckets stored on their own are listed in the buckets
+// directory, in a directory named after each bucket.
 func (e *Engine) CreateBackup(ctx context.Context) (int, []string, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	if e.closing == nil {
 		return 0, nil, ErrEngineClosed
 	}
 
-	if err := e.engine.WriteSnapshot(ctx, tsm1.CacheStatusBackup); err != nil {
+	id, filenames, err := e.shared.createBackup(ctx)
+	if err != nil {
 		return 0, nil, err
 	}
 
-	id, snapshotPath, err := e.engine.FileStore.CreateSnapshot(ctx)
-	if err != nil {
-		return 0, nil, err
+	ids, shards := e.bucketShards()
+	if len(ids) == 0 {
+		return id, filenames, nil
 	}
 
-	fileInfos, err := ioutil.ReadDir(snapshotPath)
-	if err != nil {
-		return 0, nil, err
+	snapshots := make(map[influxdb.ID]*bucketBackup, len(ids))
+	for _, bucketID := range ids {
+		snapshotID, bucketFilenames, err := shards[bucketID].createBackup(ctx)
+		if err != nil {
+			return 0, nil, err
+		} else if len(bucketFilenames) == 0 {
+			continue
+		}
+		snapshot := &bucketBackup{snapshotID: snapshotID, files: make(map[string]struct{}, len(bucketFilenames))}
+		for _, filename := range bucketFilenames {
+			snapshot.files[filename] = struct{}{}
+			filenames = append(filenames, path.Join(DefaultBucketsDirectoryName, bucketID.String(), filename))
+		}
+		snapshots[bucketID] = snapshot
 	}
-	filenames := make([]string, len(fileInfos))
-	for i, fi := range fileInfos {
-		filenames[i] = fi.Name()
+
+	if len(snapshots) > 0 {
+		e.backupsMu.Lock()
+		e.backups[id] = snapshots
+		e.backupsMu.Unlock()
 	}
 
 	return id, filenames, nil
@@ -713,53 +968,51 @@ func (e *Engine) FetchBackupFile(ctx context.Context, backupID int, backupFile s
 		return ErrEngineClosed
 	}
 
-	if err := e.fetchBackup(ctx, backupID, backupFile, w); err != nil {
-		e.logger.Error("Failed to fetch file for backup", zap.Error(err), zap.Int("backup_id", backupID), zap.String("backup_file", backupFile))
-		return err
-	}
-
-	backupPath := e.engine.FileStore.InternalBackupPath(backupID)
-	backupFileFullPath := filepath.Join(backupPath, backupFile)
-	if err := os.Remove(backupFileFullPath); err != nil {
-		e.logger.Info("Failed to remove backup file after fetch", zap.Error(err), zap.Int("backup_id", backupID), zap.String("backup_file", backupFile))
+	parts := strings.Split(backupFile, "/")
+	if len(parts) != 3 || parts[0] != DefaultBucketsDirectoryName {
+		return e.shared.fetchBackupFile(ctx, backupID, backupFile, w)
 	}
 
-	return nil
-}
-
-func (e *Engine) fetchBackup(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
-	backupPath := e.engine.FileStore.InternalBackupPath(backupID)
-	if fi, err := os.Stat(backupPath); err != nil {
-		if os.IsNotExist(err) {
-			return errors.Errorf("backup %d not found", backupID)
+	var (
+		s        *shard
+		snapshot *bucketBackup
+	)
+	bucketID, err := influxdb.IDFromString(parts[1])
+	if err == nil {
+		e.backupsMu.Lock()
+		snapshot = e.backups[backupID][*bucketID]
+		e.backupsMu.Unlock()
+		if snapshot != nil {
+			s = e.shard(*bucketID)
 		}
-		return errors.WithMessagef(err, "failed to locate backup %d", backupID)
-	} else if !fi.IsDir() {
-		return errors.Errorf("error in filesystem path of backup %d", backupID)
 	}
-
-	backupFileFullPath := filepath.Join(backupPath, backupFile)
-	file, err := os.Open(backupFileFullPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return errors.Errorf("backup file %d/%s not found", backupID, backupFile)
-		}
-		return errors.WithMessagef(err, "failed to open backup file %d/%s", backupID, backupFile)
+	if s == nil || s == e.shared {
+		return errors.Errorf("backup file %d/%s not found", backupID, backupFile)
 	}
-	defer file.Close()
-
-	if _, err = io.Copy(w, file); err != nil {
-		err = multierr.Append(err, file.Close())
-		return errors.WithMessagef(err, "failed to copy backup file %d/%s to writer", backupID, backupFile)
+	if err := s.fetchBackupFile(ctx, snapshot.snapshotID, parts[2], w); err != nil {
+		return err
 	}
 
-	if err = file.Close(); err != nil {
-		return errors.WithMessagef(err, "failed to close backup file %d/%s", backupID, backupFile)
+	// Forget the snapshots of the backup once all their files are fetched.
+	e.backupsMu.Lock()
+	defer e.backupsMu.Unlock()
+	delete(snapshot.files, parts[2])
+	if len(snapshot.files) == 0 {
+		delete(e.backups[backupID], *bucketID)
+		if len(e.backups[backupID]) == 0 {
+			delete(e.backups, backupID)
+		}
 	}
-
 	return nil
 }
 
+// bucketBackup is the snapshot of a bucket shard taken with a backup, and the
+// files of it not fetched yet.
+type bucketBackup struct {
+	snapshotID int
+	files      map[string]struct{}
+}
+
 // InternalBackupPath provides the internal, full path directory name of the backup.
 // This should not be exposed via API.
 func (e *Engine) InternalBackupPath(backupID int) string {
@@ -768,7 +1021,60 @@ func (e *Engine) InternalBackupPath(backupID int) string {
 	if e.closing == nil {
 		return ""
 	}
-	return e.engine.FileStore.InternalBackupPath(backupID)
+	return e.shared.engine.FileStore.InternalBackupPath(backupID)
+}
+
+// ErrBucketDropping is returned when writing to a bucket whose shard is being
+// removed.
+var ErrBucketDropping = &influxdb.Error{
+	Code: influxdb.EConflict,
+	Msg:  "bucket is being deleted",
+}
+
+// ErrBucketNotSharded is returned when backing up a bucket not stored on its
+// own.
+var ErrBucketNotSharded = &influxdb.Error{
+	Code: influxdb.EInvalid,
+	Msg:  "bucket is not stored in a shard of its own",
+}
+
+// CreateBucketBackup creates a "snapshot" of the TSM data of a bucket stored
+// on its own, as CreateBackup does for the whole engine. The backup ID is only
+// valid for the bucket.
+func (e *Engine) CreateBucketBackup(ctx context.Context, bucketID influxdb.ID) (int, []string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return 0, nil, ErrEngineClosed
+	}
+
+	s := e.shard(bucketID)
+	if s == e.shared {
+		return 0, nil, ErrBucketNotSharded
+	}
+	return s.createBackup(ctx)
+}
+
+// FetchBucketBackupFile writes a given file of a backup of the bucket to the
+// provided writer. After a successful write, the internal copy is removed.
+func (e *Engine) FetchBucketBackupFile(ctx context.Context, bucketID influxdb.ID, backupID int, backupFile string, w io.Writer) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return ErrEngineClosed
+	}
+
+	s := e.shard(bucketID)
+	if s == e.shared {
+		return ErrBucketNotSharded
+	}
+	return s.fetchBackupFile(ctx, backupID, backupFile, w)
 }
 
 // SeriesCardinality returns the number of series in the engine.
@@ -778,7 +1084,11 @@ func (e *Engine) SeriesCardinality() int64 {
 	if e.closing == nil {
 		return 0
 	}
-	return e.index.SeriesN()
+	var n int64
+	for _, s := range e.allShards() {
+		n += s.index.SeriesN()
+	}
+	return n
 }
 
 // Path returns the path of the engine's base directory.
@@ -793,7 +1103,27 @@ func (e *Engine) MeasurementCardinalityStats() (tsi1.MeasurementCardinalityStats
 	if e.closing == nil {
 		return nil, ErrEngineClosed
 	}
-	return e.index.MeasurementCardinalityStats()
+	return e.measurementCardinalityStats()
+}
+
+// measurementCardinalityStats returns the cardinality stats of all the
+// measurements of all the shards.
+func (e *Engine) measurementCardinalityStats() (tsi1.MeasurementCardinalityStats, error) {
+	e.shardsMu.RLock()
+	defer e.shardsMu.RUnlock()
+
+	stats, err := e.shared.index.MeasurementCardinalityStats()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range e.shards {
+		other, err := s.index.MeasurementCardinalityStats()
+		if err != nil {
+			return nil, err
+		}
+		stats.Add(other)
+	}
+	return stats, nil
 }
 
 // MeasurementStats returns the current measurement stats for the engine.
@@ -803,5 +1133,13 @@ func (e *Engine) MeasurementStats() (tsm1.MeasurementStats, error) {
 	if e.closing == nil {
 		return nil, ErrEngineClosed
 	}
-	return e.engine.MeasurementStats()
+	stats := tsm1.NewMeasurementStats()
+	for _, s := range e.allShards() {
+		other, err := s.engine.MeasurementStats()
+		if err != nil {
+			return nil, err
+		}
+		stats.Add(other)
+	}
+	return stats, nil
 }
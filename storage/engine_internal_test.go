@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/tsdb"
+)
+
+func TestEngine_WriteDroppingBucket(t *testing.T) {
+	path, err := ioutil.TempDir("", "storage_engine_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	c := NewConfig()
+	c.BucketShards = true
+	e := NewEngine(path, c, WithEngineID(rand.Int()), WithNodeID(rand.Int()))
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(2)
+	points := []models.Point{models.MustNewPoint(
+		tsdb.EncodeNameString(orgID, bucketID),
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)}
+
+	// A write racing with the drop of the shard of its bucket does not open a
+	// shard where the dropped one is being removed.
+	e.shardsMu.Lock()
+	e.dropping[bucketID] = struct{}{}
+	e.shardsMu.Unlock()
+	if err := e.WritePoints(context.Background(), points); err != ErrBucketDropping {
+		t.Fatalf("unexpected error: got %v, exp %v", err, ErrBucketDropping)
+	}
+	if _, err := os.Stat(e.bucketShardPath(bucketID)); !os.IsNotExist(err) {
+		t.Fatalf("unexpected shard of dropping bucket: %v", err)
+	}
+
+	// Once dropped, the bucket can be written again.
+	e.shardsMu.Lock()
+	delete(e.dropping, bucketID)
+	e.shardsMu.Unlock()
+	if err := e.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.DeleteBucket(context.Background(), orgID, bucketID); err != nil {
+		t.Fatal(err)
+	}
+	if len(e.dropping) != 0 {
+		t.Fatalf("unexpected dropping buckets: %v", e.dropping)
+	}
+	if err := e.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEngine_FetchBackupFile_ForgetsFetchedBackups(t *testing.T) {
+	path, err := ioutil.TempDir("", "storage_engine_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(path)
+
+	c := NewConfig()
+	c.BucketShards = true
+	e := NewEngine(path, c, WithEngineID(rand.Int()), WithNodeID(rand.Int()))
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	orgID, bucketID := influxdb.ID(1), influxdb.ID(2)
+	points := []models.Point{models.MustNewPoint(
+		tsdb.EncodeNameString(orgID, bucketID),
+		models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)}
+	if err := e.WritePoints(context.Background(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	id, files, err := e.CreateBackup(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	} else if len(e.backups[id]) != 1 {
+		t.Fatalf("unexpected bucket snapshots: %v", e.backups[id])
+	}
+	for _, file := range files {
+		if err := e.FetchBackupFile(context.Background(), id, file, ioutil.Discard); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(e.backups) != 0 {
+		t.Fatalf("unexpected backups after fetching their files: %v", e.backups)
+	}
+}
@@ -22,7 +22,7 @@ func (e *Engine) MeasurementNames(ctx context.Context, orgID, bucketID influxdb.
 		return cursors.EmptyStringIterator, nil
 	}
 
-	return e.engine.MeasurementNames(ctx, orgID, bucketID, start, end)
+	return e.shard(bucketID).engine.MeasurementNames(ctx, orgID, bucketID, start, end)
 }
 
 // MeasurementTagValues returns an iterator which enumerates the tag values for the given
@@ -40,7 +40,7 @@ func (e *Engine) MeasurementTagValues(ctx context.Context, orgID, bucketID influ
 		return cursors.EmptyStringIterator, nil
 	}
 
-	return e.engine.MeasurementTagValues(ctx, orgID, bucketID, measurement, tagKey, start, end, predicate)
+	return e.shard(bucketID).engine.MeasurementTagValues(ctx, orgID, bucketID, measurement, tagKey, start, end, predicate)
 }
 
 // MeasurementTagKeys returns an iterator which enumerates the tag keys for the given
@@ -58,5 +58,5 @@ func (e *Engine) MeasurementTagKeys(ctx context.Context, orgID, bucketID influxd
 		return cursors.EmptyStringIterator, nil
 	}
 
-	return e.engine.MeasurementTagKeys(ctx, orgID, bucketID, measurement, start, end, predicate)
+	return e.shard(bucketID).engine.MeasurementTagKeys(ctx, orgID, bucketID, measurement, start, end, predicate)
 }
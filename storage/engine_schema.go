@@ -19,7 +19,7 @@ func (e *Engine) TagKeys(ctx context.Context, orgID, bucketID influxdb.ID, start
 		return cursors.EmptyStringIterator, nil
 	}
 
-	return e.engine.TagKeys(ctx, orgID, bucketID, start, end, predicate)
+	return e.shard(bucketID).engine.TagKeys(ctx, orgID, bucketID, start, end, predicate)
 }
 
 // TagValues returns an iterator which enumerates the values for the specific
@@ -34,5 +34,5 @@ func (e *Engine) TagValues(ctx context.Context, orgID, bucketID influxdb.ID, tag
 		return cursors.EmptyStringIterator, nil
 	}
 
-	return e.engine.TagValues(ctx, orgID, bucketID, tagKey, start, end, predicate)
+	return e.shard(bucketID).engine.TagValues(ctx, orgID, bucketID, tagKey, start, end, predicate)
 }
@@ -1,21 +1,28 @@
 package storage_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
 	"math"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/prom/promtest"
+	"github.com/influxdata/influxdb/v2/mock"
 	"github.com/influxdata/influxdb/v2/models"
 	"github.com/influxdata/influxdb/v2/storage"
 	"github.com/influxdata/influxdb/v2/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/v2/toml"
 	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/cursors"
 	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -384,6 +391,7 @@ func TestEngine_InitializeMetrics(t *testing.T) {
 	files := promtest.MustFindMetric(t, mfs, "storage_tsm_files_total", prometheus.Labels{
 		"node_id":   fmt.Sprint(engine.nodeID),
 		"engine_id": fmt.Sprint(engine.engineID),
+		"bucket_id": "",
 		"level":     "1",
 	})
 	if m, got, exp := files, files.GetGauge().GetValue(), 0.0; got != exp {
@@ -393,6 +401,7 @@ func TestEngine_InitializeMetrics(t *testing.T) {
 	bytes := promtest.MustFindMetric(t, mfs, "storage_tsm_files_disk_bytes", prometheus.Labels{
 		"node_id":   fmt.Sprint(engine.nodeID),
 		"engine_id": fmt.Sprint(engine.engineID),
+		"bucket_id": "",
 		"level":     "1",
 	})
 	if m, got, exp := bytes, bytes.GetGauge().GetValue(), 0.0; got != exp {
@@ -459,9 +468,122 @@ func TestEngine_WALDisabled(t *testing.T) {
 		time.Unix(1, 2),
 	)
 
-	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{pt}); err != nil {
+	var report storage.WriteReport
+	if err := engine.Engine.WritePoints(storage.WithWriteReport(context.TODO(), &report), []models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := report.Durability.String(), "none"; got != exp {
+		t.Fatalf("unexpected durability: got %s, exp %s", got, exp)
+	}
+}
+
+func TestEngine_WALDurability(t *testing.T) {
+	org, logs, metrics := influxdb.ID(1), influxdb.ID(2), influxdb.ID(3)
+
+	var lookups int
+	buckets := mock.NewBucketService()
+	buckets.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		lookups++
+		return []*influxdb.Bucket{
+			{ID: logs, OrgID: org, WALDurability: influxdb.BucketWALDurabilityAsync},
+			{ID: metrics, OrgID: org},
+		}, 2, nil
+	}
+
+	config := storage.NewConfig()
+	config.WAL.Durability = "sync"
+
+	path, _ := ioutil.TempDir("", "storage_engine_test")
+	defer os.RemoveAll(path)
+	engine := storage.NewEngine(path, config,
+		storage.WithEngineID(rand.Int()), storage.WithNodeID(rand.Int()),
+		storage.WithBucketSettings(buckets))
+	if err := engine.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+
+	point := func(bucket influxdb.ID, v float64) models.Point {
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(org, bucket),
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": "server"}),
+			map[string]interface{}{"value": v},
+			time.Unix(1, 2),
+		)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		points []models.Point
+		exp    string
+	}{
+		{name: "bucket durability", points: []models.Point{point(logs, 1)}, exp: "async"},
+		{name: "engine durability", points: []models.Point{point(metrics, 1)}, exp: "sync"},
+		{name: "most durable", points: []models.Point{point(logs, 2), point(metrics, 2)}, exp: "sync"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var report storage.WriteReport
+			if err := engine.WritePoints(storage.WithWriteReport(context.Background(), &report), tc.points); err != nil {
+				t.Fatal(err)
+			}
+			if !report.Reported {
+				t.Fatal("expected write to be reported")
+			}
+			if got := report.Durability.String(); got != tc.exp {
+				t.Fatalf("unexpected durability: got %s, exp %s", got, tc.exp)
+			}
+		})
+	}
+
+	// The buckets are looked up when the engine opens, never on writes.
+	if lookups != 1 {
+		t.Fatalf("unexpected bucket look ups: got %d, exp 1", lookups)
+	}
+
+	// An update of the bucket through the bucket service of the engine applies
+	// to the next write.
+	buckets.UpdateBucketFn = func(_ context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: id, OrgID: org, WALDurability: *upd.WALDurability}, nil
+	}
+	durability := influxdb.BucketWALDurabilityAsync
+	if _, err := storage.NewBucketService(buckets, engine).UpdateBucket(context.Background(), metrics, influxdb.BucketUpdate{WALDurability: &durability}); err != nil {
+		t.Fatal(err)
+	}
+	var report storage.WriteReport
+	if err := engine.WritePoints(storage.WithWriteReport(context.Background(), &report), []models.Point{point(metrics, 3)}); err != nil {
 		t.Fatal(err)
 	}
+	if got, exp := report.Durability.String(), "async"; got != exp {
+		t.Fatalf("unexpected durability after update: got %s, exp %s", got, exp)
+	}
+
+	// Writes made with any durability are replayed when the engine is opened.
+	if err := engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, bucket := range []influxdb.ID{logs, metrics} {
+		cur, err := engine.CreateSeriesCursor(context.Background(), org, bucket, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		for {
+			row, err := cur.Next()
+			if err != nil {
+				t.Fatal(err)
+			} else if row == nil {
+				break
+			}
+			n++
+		}
+		cur.Close()
+		if n != 1 {
+			t.Fatalf("unexpected series in bucket %s: got %d, exp 1", bucket, n)
+		}
+	}
 }
 
 func TestEngine_WriteConflictingBatch(t *testing.T) {
@@ -490,6 +612,252 @@ func TestEngine_WriteConflictingBatch(t *testing.T) {
 	}
 }
 
+func TestEngine_SeriesLimits(t *testing.T) {
+	config := storage.NewConfig()
+	config.MaxSeriesPerBucket = 3
+	config.MaxSeriesPerMeasurement = 2
+
+	engine := NewEngine(config, rand.Int(), rand.Int())
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	point := func(m, host string) models.Point {
+		return models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: m, "host": host}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		)
+	}
+
+	// The third series of cpu is dropped, naming the measurement.
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("cpu", "a"), point("cpu", "b"), point("cpu", "c"), point("cpu", "a")})
+	if pwe, ok := err.(tsdb.PartialWriteError); !ok {
+		t.Fatal("expected partial write error. got:", err)
+	} else if pwe.Dropped != 1 || !strings.Contains(pwe.Reason, `max-series-per-measurement limit exceeded: measurement "cpu"`) {
+		t.Fatalf("unexpected partial write error: %v", pwe)
+	}
+
+	// The fourth series of the bucket is dropped, and points of existing
+	// series are still written.
+	err = engine.Engine.WritePoints(context.TODO(), []models.Point{point("mem", "a"), point("disk", "a"), point("cpu", "b")})
+	if pwe, ok := err.(tsdb.PartialWriteError); !ok {
+		t.Fatal("expected partial write error. got:", err)
+	} else if pwe.Dropped != 1 || !strings.Contains(pwe.Reason, `max-series-per-bucket limit exceeded: measurement "disk"`) {
+		t.Fatalf("unexpected partial write error: %v", pwe)
+	}
+
+	if got, exp := engine.SeriesCardinality(), int64(3); got != exp {
+		t.Fatalf("got %v series, exp %v series in index", got, exp)
+	}
+
+}
+
+func TestEngine_MaxLateness(t *testing.T) {
+	config := storage.NewConfig()
+	config.Engine.Cache.MaxLateness = toml.Duration(time.Hour)
+
+	engine := NewEngine(config, rand.Int(), rand.Int())
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	point := func(host string, t time.Time) models.Point {
+		return models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": host}),
+			map[string]interface{}{"value": 1.0},
+			t,
+		)
+	}
+
+	// The late point is dropped, and reported as a partial write.
+	err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("late", time.Unix(1, 2)), point("a", time.Now())})
+	if pwe, ok := err.(tsdb.PartialWriteError); !ok {
+		t.Fatal("expected partial write error. got:", err)
+	} else if pwe.Dropped != 1 || !strings.Contains(pwe.Reason, "max-lateness exceeded") {
+		t.Fatalf("unexpected partial write error: %v", pwe)
+	}
+
+	// The late point is not written to the WAL, so it is not replayed either.
+	for i := 0; i < 2; i++ {
+		if got, exp := engine.SeriesCardinality(), int64(1); got != exp {
+			t.Fatalf("got %v series, exp %v series in index", got, exp)
+		}
+		if err := engine.Engine.Close(); err != nil {
+			t.Fatal(err)
+		}
+		engine.Engine = storage.NewEngine(engine.path, config, storage.WithEngineID(engine.engineID), storage.WithNodeID(engine.nodeID))
+		engine.MustOpen()
+	}
+}
+
+func TestEngine_FindBucketCardinality(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	name := tsdb.EncodeNameString(engine.org, engine.bucket)
+	point := func(m, host string) models.Point {
+		return models.MustNewPoint(
+			name,
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: m, "host": host}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		)
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("cpu", "a"), point("cpu", "b"), point("mem", "a")}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := engine.FindBucketCardinality(context.Background(), engine.org, engine.bucket, influxdb.CardinalityOptions{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := c.Series, int64(3); got != exp {
+		t.Fatalf("unexpected series: got %d, exp %d", got, exp)
+	}
+	if got, exp := c.Measurements, []influxdb.MeasurementCardinality{{Name: "cpu", Series: 2}, {Name: "mem", Series: 1}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected measurements: got %+v, exp %+v", got, exp)
+	}
+	if got, exp := c.TagKeys, []influxdb.TagKeyCardinality{{Key: "_measurement", Values: 2}, {Key: "host", Values: 2}}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected tag keys: got %+v, exp %+v", got, exp)
+	}
+
+	// The growth holds the sample taken when the engine was opened, before
+	// the writes, and the current number of series.
+	if got := len(c.Growth); got != 2 {
+		t.Fatalf("unexpected growth samples: got %d, exp 2", got)
+	}
+	if got, exp := c.Growth[1].Series, int64(3); got != exp {
+		t.Fatalf("unexpected growth: got %d, exp %d", got, exp)
+	}
+}
+
+func TestEngine_BucketShards(t *testing.T) {
+	c := storage.NewConfig()
+	c.BucketShards = true
+	engine := NewEngine(c, rand.Int(), rand.Int())
+	defer engine.Close()
+
+	other, _ := influxdb.IDFromString("8888888888888888")
+
+	// Write the bucket of the engine while the bucket shards are disabled, so
+	// it is stored in the shared shard.
+	engine.Engine = storage.NewEngine(engine.path, storage.NewConfig(), storage.WithEngineID(engine.engineID), storage.WithNodeID(engine.nodeID))
+	engine.MustOpen()
+	point := func(bucketID influxdb.ID, host string) models.Point {
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, bucketID),
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "value", models.MeasurementTagKey: "cpu", "host": host}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		)
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point(engine.bucket, "a")}); err != nil {
+		t.Fatal(err)
+	}
+	engine.Engine.Close()
+
+	engine.Engine = storage.NewEngine(engine.path, c, storage.WithEngineID(engine.engineID), storage.WithNodeID(engine.nodeID))
+	engine.MustOpen()
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		point(engine.bucket, "b"),
+		point(*other, "a"),
+		point(*other, "b"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The metrics of each shard are labelled with its bucket.
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(engine.PrometheusCollectors()...)
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bucketID := range []string{"", other.String()} {
+		m := promtest.MustFindMetric(t, mfs, "storage_cache_inuse_bytes", prometheus.Labels{
+			"node_id":   fmt.Sprint(engine.nodeID),
+			"engine_id": fmt.Sprint(engine.engineID),
+			"bucket_id": bucketID,
+		})
+		if m.GetGauge().GetValue() == 0 {
+			t.Errorf("expected cache size of shard of bucket %q", bucketID)
+		}
+	}
+
+	bucketsPath := c.GetBucketsPath(engine.path)
+	if _, err := os.Stat(filepath.Join(bucketsPath, engine.bucket.String())); !os.IsNotExist(err) {
+		t.Fatalf("unexpected shard of bucket in shared shard: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(bucketsPath, other.String(), storage.DefaultEngineDirectoryName)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ensure the shards are loaded again after closing and opening the engine.
+	engine.Engine.Close()
+	engine.MustOpen()
+
+	if got, exp := engine.SeriesCardinality(), int64(4); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+	for _, bucketID := range []influxdb.ID{engine.bucket, *other} {
+		itr, err := engine.TagValues(context.Background(), engine.org, bucketID, "host", math.MinInt64, math.MaxInt64, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, exp := cursors.StringIteratorToSlice(itr), []string{"a", "b"}; !reflect.DeepEqual(got, exp) {
+			t.Fatalf("unexpected hosts of bucket %s: got %v, exp %v", bucketID, got, exp)
+		}
+	}
+
+	// Only the buckets stored on their own can be backed up on their own.
+	if _, _, err := engine.CreateBucketBackup(context.Background(), engine.bucket); err != storage.ErrBucketNotSharded {
+		t.Fatalf("unexpected error backing up bucket in shared shard: %v", err)
+	}
+	if _, files, err := engine.CreateBucketBackup(context.Background(), *other); err != nil {
+		t.Fatal(err)
+	} else if len(files) == 0 {
+		t.Fatal("expected TSM files in backup of bucket")
+	}
+
+	// The backup of the engine holds the files of both shards.
+	id, files, err := engine.CreateBackup(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bucketFiles []string
+	for _, file := range files {
+		if strings.HasPrefix(file, storage.DefaultBucketsDirectoryName+"/"+other.String()+"/") {
+			bucketFiles = append(bucketFiles, file)
+		}
+	}
+	if len(bucketFiles) == 0 || len(bucketFiles) == len(files) {
+		t.Fatalf("unexpected files in backup: %v", files)
+	}
+	var buf bytes.Buffer
+	if err := engine.FetchBackupFile(context.Background(), id, bucketFiles[0], &buf); err != nil {
+		t.Fatal(err)
+	} else if buf.Len() == 0 {
+		t.Fatalf("expected contents of backup file %s", bucketFiles[0])
+	}
+
+	// Dropping the bucket removes its shard.
+	if err := engine.DeleteBucket(context.Background(), engine.org, *other); err != nil {
+		t.Fatal(err)
+	}
+	if fis, err := ioutil.ReadDir(bucketsPath); err != nil {
+		t.Fatal(err)
+	} else if len(fis) != 0 {
+		t.Fatalf("unexpected files in buckets directory: %v", fis)
+	}
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+}
+
 // BenchmarkWritePoints_100K demonstrates the impact that batch size has on
 // writing a fixed number of points into storage. In this case 100K points are
 // written according to varying batch sizes.
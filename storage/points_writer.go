@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage/wal"
 )
 
 // PointsWriter describes the ability to write points into a storage engine.
@@ -11,6 +12,30 @@ type PointsWriter interface {
 	WritePoints(context.Context, []models.Point) error
 }
 
+// A WriteReport reports how the points of a write were stored.
+type WriteReport struct {
+	// Reported is set once the engine filled the report.
+	Reported bool
+
+	// Durability is the WAL durability of the write.
+	Durability wal.Durability
+}
+
+type writeReportKey struct{}
+
+// WithWriteReport returns a context making the engine report how the points
+// written with it were stored in r.
+func WithWriteReport(ctx context.Context, r *WriteReport) context.Context {
+	return context.WithValue(ctx, writeReportKey{}, r)
+}
+
+// writeReportFromContext returns the report of the writes made with ctx, nil
+// if there is none.
+func writeReportFromContext(ctx context.Context) *WriteReport {
+	r, _ := ctx.Value(writeReportKey{}).(*WriteReport)
+	return r
+}
+
 type BufferedPointsWriter struct {
 	buf []models.Point
 	n   int
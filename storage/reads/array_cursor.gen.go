@@ -127,6 +127,25 @@ func (c *floatArrayCursor) Next() *cursors.FloatArray {
 	}
 }
 
+// Aggregate returns the count, sum, min and max of the remaining values of the
+// cursor, answering whole blocks from their statistics when the underlying
+// cursor supports it.
+func (c *floatArrayCursor) Aggregate() cursors.FloatArrayAggregate {
+	var agg cursors.FloatArrayAggregate
+	for {
+		if cur, ok := c.FloatArrayCursor.(cursors.FloatArrayAggregator); ok {
+			agg.Merge(cur.Aggregate())
+		} else {
+			for a := c.FloatArrayCursor.Next(); a.Len() > 0; a = c.FloatArrayCursor.Next() {
+				agg.AddArray(a)
+			}
+		}
+		if !c.nextArrayCursor() {
+			return agg
+		}
+	}
+}
+
 func (c *floatArrayCursor) nextArrayCursor() bool {
 	if c.cursorIterator == nil {
 		return false
@@ -177,6 +196,18 @@ func newFloatArraySumCursor(cur cursors.FloatArrayCursor) *floatArraySumCursor {
 func (c floatArraySumCursor) Stats() cursors.CursorStats { return c.FloatArrayCursor.Stats() }
 
 func (c floatArraySumCursor) Next() *cursors.FloatArray {
+	if cur, ok := c.FloatArrayCursor.(cursors.FloatArrayAggregator); ok {
+		agg := cur.Aggregate()
+		if agg.Count == 0 {
+			return &cursors.FloatArray{}
+		}
+		c.ts[0] = agg.Timestamp
+		c.vs[0] = agg.Sum
+		c.res.Timestamps = c.ts[:]
+		c.res.Values = c.vs[:]
+		return c.res
+	}
+
 	a := c.FloatArrayCursor.Next()
 	if len(a.Timestamps) == 0 {
 		return a
@@ -209,6 +240,17 @@ func (c *integerFloatCountArrayCursor) Stats() cursors.CursorStats {
 }
 
 func (c *integerFloatCountArrayCursor) Next() *cursors.IntegerArray {
+	if cur, ok := c.FloatArrayCursor.(cursors.FloatArrayAggregator); ok {
+		agg := cur.Aggregate()
+		if agg.Count == 0 {
+			return &cursors.IntegerArray{}
+		}
+		res := cursors.NewIntegerArrayLen(1)
+		res.Timestamps[0] = agg.Timestamp
+		res.Values[0] = agg.Count
+		return res
+	}
+
 	a := c.FloatArrayCursor.Next()
 	if len(a.Timestamps) == 0 {
 		return &cursors.IntegerArray{}
@@ -347,6 +389,25 @@ func (c *integerArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+// Aggregate returns the count, sum, min and max of the remaining values of the
+// cursor, answering whole blocks from their statistics when the underlying
+// cursor supports it.
+func (c *integerArrayCursor) Aggregate() cursors.IntegerArrayAggregate {
+	var agg cursors.IntegerArrayAggregate
+	for {
+		if cur, ok := c.IntegerArrayCursor.(cursors.IntegerArrayAggregator); ok {
+			agg.Merge(cur.Aggregate())
+		} else {
+			for a := c.IntegerArrayCursor.Next(); a.Len() > 0; a = c.IntegerArrayCursor.Next() {
+				agg.AddArray(a)
+			}
+		}
+		if !c.nextArrayCursor() {
+			return agg
+		}
+	}
+}
+
 func (c *integerArrayCursor) nextArrayCursor() bool {
 	if c.cursorIterator == nil {
 		return false
@@ -397,6 +458,18 @@ func newIntegerArraySumCursor(cur cursors.IntegerArrayCursor) *integerArraySumCu
 func (c integerArraySumCursor) Stats() cursors.CursorStats { return c.IntegerArrayCursor.Stats() }
 
 func (c integerArraySumCursor) Next() *cursors.IntegerArray {
+	if cur, ok := c.IntegerArrayCursor.(cursors.IntegerArrayAggregator); ok {
+		agg := cur.Aggregate()
+		if agg.Count == 0 {
+			return &cursors.IntegerArray{}
+		}
+		c.ts[0] = agg.Timestamp
+		c.vs[0] = agg.Sum
+		c.res.Timestamps = c.ts[:]
+		c.res.Values = c.vs[:]
+		return c.res
+	}
+
 	a := c.IntegerArrayCursor.Next()
 	if len(a.Timestamps) == 0 {
 		return a
@@ -429,6 +502,17 @@ func (c *integerIntegerCountArrayCursor) Stats() cursors.CursorStats {
 }
 
 func (c *integerIntegerCountArrayCursor) Next() *cursors.IntegerArray {
+	if cur, ok := c.IntegerArrayCursor.(cursors.IntegerArrayAggregator); ok {
+		agg := cur.Aggregate()
+		if agg.Count == 0 {
+			return &cursors.IntegerArray{}
+		}
+		res := cursors.NewIntegerArrayLen(1)
+		res.Timestamps[0] = agg.Timestamp
+		res.Values[0] = agg.Count
+		return res
+	}
+
 	a := c.IntegerArrayCursor.Next()
 	if len(a.Timestamps) == 0 {
 		return &cursors.IntegerArray{}
@@ -567,6 +651,25 @@ func (c *unsignedArrayCursor) Next() *cursors.UnsignedArray {
 	}
 }
 
+// Aggregate returns the count, sum, min and max of the remaining values of the
+// cursor, answering whole blocks from their statistics when the underlying
+// cursor supports it.
+func (c *unsignedArrayCursor) Aggregate() cursors.UnsignedArrayAggregate {
+	var agg cursors.UnsignedArrayAggregate
+	for {
+		if cur, ok := c.UnsignedArrayCursor.(cursors.UnsignedArrayAggregator); ok {
+			agg.Merge(cur.Aggregate())
+		} else {
+			for a := c.UnsignedArrayCursor.Next(); a.Len() > 0; a = c.UnsignedArrayCursor.Next() {
+				agg.AddArray(a)
+			}
+		}
+		if !c.nextArrayCursor() {
+			return agg
+		}
+	}
+}
+
 func (c *unsignedArrayCursor) nextArrayCursor() bool {
 	if c.cursorIterator == nil {
 		return false
@@ -617,6 +720,18 @@ func newUnsignedArraySumCursor(cur cursors.UnsignedArrayCursor) *unsignedArraySu
 func (c unsignedArraySumCursor) Stats() cursors.CursorStats { return c.UnsignedArrayCursor.Stats() }
 
 func (c unsignedArraySumCursor) Next() *cursors.UnsignedArray {
+	if cur, ok := c.UnsignedArrayCursor.(cursors.UnsignedArrayAggregator); ok {
+		agg := cur.Aggregate()
+		if agg.Count == 0 {
+			return &cursors.UnsignedArray{}
+		}
+		c.ts[0] = agg.Timestamp
+		c.vs[0] = agg.Sum
+		c.res.Timestamps = c.ts[:]
+		c.res.Values = c.vs[:]
+		return c.res
+	}
+
 	a := c.UnsignedArrayCursor.Next()
 	if len(a.Timestamps) == 0 {
 		return a
@@ -649,6 +764,17 @@ func (c *integerUnsignedCountArrayCursor) Stats() cursors.CursorStats {
 }
 
 func (c *integerUnsignedCountArrayCursor) Next() *cursors.IntegerArray {
+	if cur, ok := c.UnsignedArrayCursor.(cursors.UnsignedArrayAggregator); ok {
+		agg := cur.Aggregate()
+		if agg.Count == 0 {
+			return &cursors.IntegerArray{}
+		}
+		res := cursors.NewIntegerArrayLen(1)
+		res.Timestamps[0] = agg.Timestamp
+		res.Values[0] = agg.Count
+		return res
+	}
+
 	a := c.UnsignedArrayCursor.Next()
 	if len(a.Timestamps) == 0 {
 		return &cursors.IntegerArray{}
@@ -113,8 +113,10 @@ func (m *arrayCursors) createCursor(seriesRow SeriesRow) cursors.Cursor {
 	m.req.Field = seriesRow.Field
 
 	var cond expression
+	m.req.ValueFilter = nil
 	if seriesRow.ValueCond != nil {
 		cond = &astExpr{seriesRow.ValueCond}
+		m.req.ValueFilter = &blockFilter{seriesRow.ValueCond}
 	}
 
 	if seriesRow.Query == nil {
@@ -0,0 +1,157 @@
+package reads
+
+import (
+	"math"
+
+	"github.com/influxdata/influxdb/v2/tsdb/cursors"
+	"github.com/influxdata/influxql"
+)
+
+// maxExactFloat is the magnitude up to which every integer has an exact
+// float64 representation.
+const maxExactFloat = 1 << 53
+
+// blockFilter implements cursors.BlockFilter for a value condition. It
+// decides whether the values of a block may match the condition following the
+// semantics of EvalExprBool.
+type blockFilter struct {
+	expr influxql.Expr
+}
+
+// MayMatch returns false if no value of the block summarized by s can match
+// the condition.
+func (f *blockFilter) MayMatch(s *cursors.BlockSummary) bool {
+	return mayMatchExpr(f.expr, s)
+}
+
+func mayMatchExpr(expr influxql.Expr, s *cursors.BlockSummary) bool {
+	switch expr := expr.(type) {
+	case *influxql.ParenExpr:
+		return mayMatchExpr(expr.Expr, s)
+	case *influxql.BooleanLiteral:
+		return expr.Val
+	case *influxql.BinaryExpr:
+		switch expr.Op {
+		case influxql.AND:
+			return mayMatchExpr(expr.LHS, s) && mayMatchExpr(expr.RHS, s)
+		case influxql.OR:
+			return mayMatchExpr(expr.LHS, s) || mayMatchExpr(expr.RHS, s)
+		}
+
+		ref, ok := expr.LHS.(*influxql.VarRef)
+		if !ok || ref.Val != fieldRef {
+			return true
+		}
+		switch s.Type {
+		case cursors.Float:
+			return mayMatchFloat(expr.Op, expr.RHS, s)
+		case cursors.Integer:
+			return mayMatchInteger(expr.Op, expr.RHS, s)
+		case cursors.String:
+			if lit, ok := expr.RHS.(*influxql.StringLiteral); ok && expr.Op == influxql.EQ {
+				return s.Contains == nil || s.Contains(lit.Val)
+			}
+		case cursors.Boolean:
+			if lit, ok := expr.RHS.(*influxql.BooleanLiteral); ok && expr.Op == influxql.EQ {
+				return s.Contains == nil || s.Contains(lit.Val)
+			}
+		}
+	}
+	return true
+}
+
+func mayMatchFloat(op influxql.Token, rhs influxql.Expr, s *cursors.BlockSummary) bool {
+	var v float64
+	switch lit := rhs.(type) {
+	case *influxql.NumberLiteral:
+		v = lit.Val
+	case *influxql.IntegerLiteral:
+		v = float64(lit.Val)
+	default:
+		return true
+	}
+
+	// NaN values are not accounted for by min and max, but never equal v.
+	if op == influxql.NEQ {
+		return true
+	}
+	if op == influxql.EQ && s.Contains != nil && !s.Contains(v) {
+		return false
+	}
+
+	// A NaN min or max says nothing about the range of the values.
+	min, _ := s.Min.(float64)
+	max, _ := s.Max.(float64)
+	if s.Min == nil || s.Max == nil || math.IsNaN(min) || math.IsNaN(max) {
+		return true
+	}
+	return mayMatchRange(op, min, max, v)
+}
+
+func mayMatchInteger(op influxql.Token, rhs influxql.Expr, s *cursors.BlockSummary) bool {
+	var min, max int64
+	hasRange := s.Min != nil && s.Max != nil
+	if hasRange {
+		min, _ = s.Min.(int64)
+		max, _ = s.Max.(int64)
+	}
+
+	switch lit := rhs.(type) {
+	case *influxql.IntegerLiteral:
+		if op == influxql.EQ && s.Contains != nil && !s.Contains(lit.Val) {
+			return false
+		}
+		if !hasRange {
+			return true
+		}
+		switch op {
+		case influxql.EQ:
+			return min <= lit.Val && lit.Val <= max
+		case influxql.NEQ:
+			return min != lit.Val || max != lit.Val
+		case influxql.LT:
+			return min < lit.Val
+		case influxql.LTE:
+			return min <= lit.Val
+		case influxql.GT:
+			return max > lit.Val
+		case influxql.GTE:
+			return max >= lit.Val
+		}
+	case *influxql.NumberLiteral:
+		// Integer values are compared as floats with float literals.
+		v := lit.Val
+		if op == influxql.EQ {
+			if v != math.Trunc(v) {
+				return false
+			} else if s.Contains != nil && math.Abs(v) < maxExactFloat && !s.Contains(int64(v)) {
+				return false
+			}
+		}
+		if !hasRange {
+			return true
+		}
+		return mayMatchRange(op, float64(min), float64(max), v)
+	}
+	return true
+}
+
+// mayMatchRange reports whether a value within [min, max] may satisfy the
+// comparison op with v.
+func mayMatchRange(op influxql.Token, min, max, v float64) bool {
+	switch op {
+	case influxql.EQ:
+		return min <= v && v <= max
+	case influxql.NEQ:
+		return min != v || max != v
+	case influxql.LT:
+		return min < v
+	case influxql.LTE:
+		return min <= v
+	case influxql.GT:
+		return max > v
+	case influxql.GTE:
+		return max >= v
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package reads
+
+import (
+	"math"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2/tsdb/cursors"
+	"github.com/influxdata/influxql"
+)
+
+func TestBlockFilter_MayMatch(t *testing.T) {
+	contains := func(values ...interface{}) func(v interface{}) bool {
+		return func(v interface{}) bool {
+			for _, value := range values {
+				if v == value {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	integers := &cursors.BlockSummary{Type: cursors.Integer, Min: int64(10), Max: int64(20), Contains: contains(int64(10), int64(15), int64(20))}
+	floats := &cursors.BlockSummary{Type: cursors.Float, Min: float64(-1.5), Max: float64(2.5), Contains: contains(-1.5, 0.0, 2.5)}
+	nanFloats := &cursors.BlockSummary{Type: cursors.Float, Min: math.NaN(), Max: math.NaN()}
+	strings := &cursors.BlockSummary{Type: cursors.String, Contains: contains("ok", "warn")}
+	booleans := &cursors.BlockSummary{Type: cursors.Boolean, Contains: contains(true)}
+	unindexed := &cursors.BlockSummary{Type: cursors.Integer, Min: int64(10), Max: int64(20)}
+
+	for _, tc := range []struct {
+		expr string
+		s    *cursors.BlockSummary
+		exp  bool
+	}{
+		{`"$" = 15`, integers, true},
+		{`"$" = 12`, integers, false},
+		{`"$" = 12`, unindexed, true},
+		{`"$" = 25`, unindexed, false},
+		{`"$" = 15.0`, integers, true},
+		{`"$" = 12.0`, integers, false},
+		{`"$" = 15.5`, integers, false},
+		{`"$" != 15`, integers, true},
+		{`"$" < 10`, integers, false},
+		{`"$" <= 10`, integers, true},
+		{`"$" > 20`, integers, false},
+		{`"$" >= 19.5`, integers, true},
+		{`"$" > 20.5`, integers, false},
+		{`"$" = 0`, floats, true},
+		{`"$" = 1.0`, floats, false},
+		{`"$" < -1.5`, floats, false},
+		{`"$" > 2`, floats, true},
+		{`"$" != 0`, floats, true},
+		{`"$" > 100`, nanFloats, true},
+		{`"$" = 'ok'`, strings, true},
+		{`"$" = 'error'`, strings, false},
+		{`"$" != 'error'`, strings, true},
+		{`"$" = true`, booleans, true},
+		{`"$" = false`, booleans, false},
+		{`"$" = 12 OR "$" = 15`, integers, true},
+		{`"$" = 12 OR "$" = 13`, integers, false},
+		{`"$" = 15 AND "$" > 20`, integers, false},
+		{`("$" = 15 AND "$" >= 10)`, integers, true},
+		{`"$" = 12 OR host = 'a'`, integers, true},
+		{`false`, integers, false},
+	} {
+		f := &blockFilter{influxql.MustParseExpr(tc.expr)}
+		if got := f.MayMatch(tc.s); got != tc.exp {
+			t.Errorf("unexpected match of %s: got %v, exp %v", tc.expr, got, tc.exp)
+		}
+	}
+}
@@ -29,6 +29,7 @@ type seriesCursor struct {
 	indexref     *lifecycle.Reference
 	sfile        *seriesfile.SeriesFile
 	sfileref     *lifecycle.Reference
+	release      func() // releases the pin on the series file keeping keys valid
 	orgID        influxdb.ID
 	encodedOrgID []byte
 	bucketID     influxdb.ID
@@ -90,6 +91,9 @@ func newSeriesCursor(orgID, bucketID influxdb.ID, index *tsi1.Index, sfile *seri
 func (cur *seriesCursor) Close() {
 	cur.sfileref.Release()
 	cur.indexref.Release()
+	if cur.release != nil {
+		cur.release()
+	}
 }
 
 // Next emits the next point in the iterator.
@@ -128,6 +132,9 @@ func (cur *seriesCursor) readSeriesKeys() error {
 	}
 	defer sitr.Close()
 
+	// The keys are read until the cursor is closed.
+	cur.release = cur.sfile.Pin()
+
 	for {
 		elem, err := sitr.Next()
 		if err != nil {
@@ -0,0 +1,425 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage/wal"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	"github.com/influxdata/influxdb/v2/tsdb/cursors"
+	"github.com/influxdata/influxdb/v2/tsdb/seriesfile"
+	"github.com/influxdata/influxdb/v2/tsdb/tsi1"
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+	"github.com/influxdata/influxdb/v2/tsdb/value"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// A shard is a series file, an index, a WAL and a TSM engine storing the data
+// of one or more buckets.
+type shard struct {
+	config Config
+	path   string
+
+	// mu is held exclusively while the WAL segments are acquired and committed
+	// by snapshots, and shared while writing and deleting.
+	mu     sync.RWMutex
+	sfile  *seriesfile.SeriesFile
+	index  *tsi1.Index
+	wal    *wal.WAL
+	engine *tsm1.Engine
+
+	logger *zap.Logger
+}
+
+// newShard returns a new shard in path, with the components in the paths set
+// by c.
+func newShard(path string, c Config) *shard {
+	s := &shard{
+		config: c,
+		path:   path,
+		logger: zap.NewNop(),
+	}
+
+	// Initialize series file.
+	s.sfile = seriesfile.NewSeriesFile(c.GetSeriesFilePath(path))
+	s.sfile.LargeWriteThreshold = c.SeriesFile.LargeSeriesWriteThreshold
+	s.sfile.SegmentCompactThreshold = c.SeriesFile.SegmentCompactThreshold
+
+	// Initialise index.
+	s.index = tsi1.NewIndex(s.sfile, c.Index,
+		tsi1.WithPath(c.GetIndexPath(path)))
+
+	// Initialize WAL
+	s.wal = wal.NewWAL(c.GetWALPath(path))
+	s.wal.WithFsyncDelay(time.Duration(c.WAL.FsyncDelay))
+	s.wal.SetEnabled(c.WAL.Enabled)
+
+	// Initialise Engine. Late points are dropped by the storage engine before
+	// they are written to the WAL, the cache accepts all the points it is given.
+	ec := c.Engine
+	ec.Cache.MaxLateness = 0
+	s.engine = tsm1.NewEngine(c.GetEnginePath(path), s.index, ec, tsm1.WithSnapshotter(s))
+
+	return s
+}
+
+// SetDefaultMetricLabels sets the default labels of the metrics of the
+// components of the shard.
+func (s *shard) SetDefaultMetricLabels(labels prometheus.Labels) {
+	s.engine.SetDefaultMetricLabels(labels)
+	s.sfile.SetDefaultMetricLabels(labels)
+	s.index.SetDefaultMetricLabels(labels)
+	s.wal.SetDefaultMetricLabels(labels)
+}
+
+// WithLogger sets the logger of the shard and of its components.
+func (s *shard) WithLogger(log *zap.Logger) {
+	s.logger = log
+	s.sfile.WithLogger(log)
+	s.index.WithLogger(log)
+	s.engine.WithLogger(log)
+	s.wal.WithLogger(log)
+}
+
+// Open opens the components of the shard and replays the WAL.
+func (s *shard) Open(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := wal.ParseDurability(s.config.WAL.Durability)
+	if err != nil {
+		return err
+	}
+	s.wal.WithDurability(d)
+
+	// Open the services in order and clean up if any fail.
+	var oh openHelper
+	oh.Open(ctx, s.sfile)
+	oh.Open(ctx, s.index)
+	oh.Open(ctx, s.engine)
+	if err := oh.Done(); err != nil {
+		return err
+	}
+
+	// The WAL is opened once replayed, as the replay truncates the torn entry
+	// at the end of the segment the WAL appends to.
+	if err := s.replayWAL(); err != nil {
+		s.closeEngine()
+		return err
+	}
+	if err := s.wal.Open(ctx); err != nil {
+		s.closeEngine()
+		return err
+	}
+	return nil
+}
+
+// closeEngine closes the TSM engine, the index and the series file of the
+// shard when it fails to open.
+func (s *shard) closeEngine() {
+	var ch closeHelper
+	ch.Close(s.engine)
+	ch.Close(s.index)
+	ch.Close(s.sfile)
+}
+
+// Close closes the components of the shard, waiting for the writes and
+// deletes in progress.
+func (s *shard) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ch closeHelper
+	ch.Close(s.engine)
+	ch.Close(s.wal)
+	ch.Close(s.index)
+	ch.Close(s.sfile)
+	return ch.Done()
+}
+
+// replayWAL reads the WAL segment files and replays them.
+func (s *shard) replayWAL() error {
+	if !s.config.WAL.Enabled {
+		return nil
+	}
+	now := time.Now()
+
+	walPaths, err := wal.SegmentFileNames(s.wal.Path())
+	if err != nil {
+		return err
+	}
+
+	// TODO(jeff): we should just do snapshots and wait for them so that we don't hit
+	// OOM situations when reloading huge WALs.
+
+	// Disable the max size during loading
+	limit := s.engine.Cache.MaxSize()
+	defer func() { s.engine.Cache.SetMaxSize(limit) }()
+	s.engine.Cache.SetMaxSize(0)
+
+	// Execute all the entries in the WAL again
+	reader := wal.NewWALReader(walPaths)
+	reader.WithLogger(s.logger)
+	err = reader.Read(func(entry wal.WALEntry) error {
+		switch en := entry.(type) {
+		case *wal.WriteWALEntry:
+			points := tsm1.ValuesToPoints(en.Values)
+			err := s.writePointsLocked(context.Background(), tsdb.NewSeriesCollection(points), en.Values)
+			if _, ok := err.(tsdb.PartialWriteError); ok {
+				err = nil
+			}
+			return err
+
+		case *wal.DeleteBucketRangeWALEntry:
+			var pred tsm1.Predicate
+			if len(en.Predicate) > 0 {
+				pred, err = tsm1.UnmarshalPredicate(en.Predicate)
+				if err != nil {
+					return err
+				}
+			}
+
+			return s.deleteBucketRangeLocked(context.Background(), en.OrgID, en.BucketID, en.Min, en.Max, pred)
+		}
+
+		return nil
+	})
+
+	s.logger.Info("Reloaded WAL",
+		zap.String("path", s.wal.Path()),
+		zap.Duration("duration", time.Since(now)),
+		zap.Error(err))
+
+	return err
+}
+
+// writePointsLocked does the work of writing points and must be called under some sort of lock.
+func (s *shard) writePointsLocked(ctx context.Context, collection *tsdb.SeriesCollection, values map[string][]value.Value) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	// TODO(jeff): keep track of the values in the collection so that partial write
+	// errors get tracked all the way. Right now, the engine doesn't drop any values
+	// but if it ever did, the errors could end up missing some data.
+
+	// Add new series to the index and series file.
+	if err := s.index.CreateSeriesListIfNotExists(collection); err != nil {
+		return err
+	}
+
+	// If there was a PartialWriteError, that means the passed in values may contain
+	// more than the points so we need to recreate them.
+	if collection.PartialWriteError() != nil {
+		var err error
+		values, err = tsm1.CollectionToValues(collection)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Write the values to the engine.
+	if err := s.engine.WriteValues(values); err != nil {
+		return err
+	}
+
+	return collection.PartialWriteError()
+}
+
+// AcquireSegments closes the current WAL segment, gets the set of all the currently closed
+// segments, and calls the callback. It does all of this under the lock on the shard.
+func (s *shard) AcquireSegments(ctx context.Context, fn func(segs []string) error) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.wal.CloseSegment(); err != nil {
+		return err
+	}
+
+	segments, err := s.wal.ClosedSegments()
+	if err != nil {
+		return err
+	}
+
+	return fn(segments)
+}
+
+// CommitSegments calls the callback and if that does not return an error, removes the segment
+// files from the WAL. It does all of this under the lock on the shard.
+func (s *shard) CommitSegments(ctx context.Context, segs []string, fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	return s.wal.Remove(ctx, segs)
+}
+
+// deleteBucketRange deletes the data of the bucket in [min, max] whose keys
+// match pred, if provided, adding the delete to the WAL first.
+func (s *shard) deleteBucketRange(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred influxdb.Predicate) error {
+	var predData []byte
+	if pred != nil {
+		// Marshal the predicate to add it to the WAL.
+		var err error
+		if predData, err = pred.Marshal(); err != nil {
+			return err
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Add the delete to the WAL to be replayed if there is a crash or shutdown.
+	if _, err := s.wal.DeleteBucketRange(orgID, bucketID, min, max, predData); err != nil {
+		return err
+	}
+
+	return s.deleteBucketRangeLocked(ctx, orgID, bucketID, min, max, pred)
+}
+
+// deleteBucketRangeLocked does the work of deleting a bucket range and must be called under
+// some sort of lock.
+func (s *shard) deleteBucketRangeLocked(ctx context.Context, orgID, bucketID influxdb.ID, min, max int64, pred tsm1.Predicate) error {
+	// TODO(edd): we need to clean up how we're encoding the prefix so that we
+	// don't have to remember to get it right everywhere we need to touch TSM data.
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	name := models.EscapeMeasurement(encoded[:])
+
+	return s.engine.DeletePrefixRange(ctx, name, min, max, pred)
+}
+
+// hasBucket returns true if the index of the shard has series of the bucket.
+func (s *shard) hasBucket(orgID, bucketID influxdb.ID) (bool, error) {
+	return s.index.MeasurementExists(tsdb.EncodeNameSlice(orgID, bucketID))
+}
+
+// createBackup snapshots the cache and hard links the TSM files of the shard
+// in a new backup directory, returning the ID of the backup and the names of
+// its files.
+func (s *shard) createBackup(ctx context.Context) (int, []string, error) {
+	if err := s.engine.WriteSnapshot(ctx, tsm1.CacheStatusBackup); err != nil {
+		return 0, nil, err
+	}
+
+	id, snapshotPath, err := s.engine.FileStore.CreateSnapshot(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	fileInfos, err := ioutil.ReadDir(snapshotPath)
+	if err != nil {
+		return 0, nil, err
+	}
+	filenames := make([]string, len(fileInfos))
+	for i, fi := range fileInfos {
+		filenames[i] = fi.Name()
+	}
+
+	return id, filenames, nil
+}
+
+// fetchBackupFile writes a given backup file to the provided writer, removing
+// the file once written.
+func (s *shard) fetchBackupFile(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	if err := s.fetchBackup(ctx, backupID, backupFile, w); err != nil {
+		s.logger.Error("Failed to fetch file for backup", zap.Error(err), zap.Int("backup_id", backupID), zap.String("backup_file", backupFile))
+		return err
+	}
+
+	backupPath := s.engine.FileStore.InternalBackupPath(backupID)
+	backupFileFullPath := filepath.Join(backupPath, backupFile)
+	if err := os.Remove(backupFileFullPath); err != nil {
+		s.logger.Info("Failed to remove backup file after fetch", zap.Error(err), zap.Int("backup_id", backupID), zap.String("backup_file", backupFile))
+	}
+
+	return nil
+}
+
+func (s *shard) fetchBackup(ctx context.Context, backupID int, backupFile string, w io.Writer) error {
+	backupPath := s.engine.FileStore.InternalBackupPath(backupID)
+	if fi, err := os.Stat(backupPath); err != nil {
+		if os.IsNotExist(err) {
+			return errors.Errorf("backup %d not found", backupID)
+		}
+		return errors.WithMessagef(err, "failed to locate backup %d", backupID)
+	} else if !fi.IsDir() {
+		return errors.Errorf("error in filesystem path of backup %d", backupID)
+	}
+
+	// The files of a shard backup are all in its directory.
+	if backupFile != filepath.Base(backupFile) || backupFile == ".." {
+		return errors.Errorf("backup file %d/%s not found", backupID, backupFile)
+	}
+
+	backupFileFullPath := filepath.Join(backupPath, backupFile)
+	file, err := os.Open(backupFileFullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return errors.Errorf("backup file %d/%s not found", backupID, backupFile)
+		}
+		return errors.WithMessagef(err, "failed to open backup file %d/%s", backupID, backupFile)
+	}
+	defer file.Close()
+
+	if _, err = io.Copy(w, file); err != nil {
+		err = multierr.Append(err, file.Close())
+		return errors.WithMessagef(err, "failed to copy backup file %d/%s to writer", backupID, backupFile)
+	}
+
+	if err = file.Close(); err != nil {
+		return errors.WithMessagef(err, "failed to close backup file %d/%s", backupID, backupFile)
+	}
+
+	return nil
+}
+
+// shardCursorIterator creates the cursors of each request from the shard of
+// its bucket.
+type shardCursorIterator struct {
+	e    *Engine
+	itrs map[*shard]cursors.CursorIterator
+}
+
+// Next returns a cursor over the values requested by r.
+func (i *shardCursorIterator) Next(ctx context.Context, r *cursors.CursorRequest) (cursors.Cursor, error) {
+	s := i.e.shared
+	if len(r.Name) >= nameLen {
+		_, bucketID := tsdb.DecodeNameSlice(r.Name)
+		s = i.e.shard(bucketID)
+	}
+
+	itr, ok := i.itrs[s]
+	if !ok {
+		var err error
+		if itr, err = s.engine.CreateCursorIterator(ctx); err != nil {
+			return nil, err
+		}
+		i.itrs[s] = itr
+	}
+	return itr.Next(ctx, r)
+}
+
+// Stats returns the stats of the cursors of all the shards.
+func (i *shardCursorIterator) Stats() cursors.CursorStats {
+	var stats cursors.CursorStats
+	for _, itr := range i.itrs {
+		stats.Add(itr.Stats())
+	}
+	return stats
+}
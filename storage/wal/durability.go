@@ -0,0 +1,83 @@
+package wal
+
+import "fmt"
+
+// Durability is how durable an entry is when a write to the WAL returns.
+type Durability int
+
+const (
+	// DurabilityGroup returns once the entry is fsynced, sharing the fsync with
+	// the writes made until the fsync delay has passed. It is the default.
+	DurabilityGroup Durability = iota
+
+	// DurabilitySync fsyncs the entry on its own before returning.
+	DurabilitySync
+
+	// DurabilityAsync returns once the entry is written to the segment file,
+	// which is fsynced in the background after the fsync delay. Entries are
+	// kept on process crashes but the entries written since the last fsync are
+	// lost on system crashes.
+	DurabilityAsync
+
+	// DurabilityNone is the durability of writes when the WAL is disabled.
+	DurabilityNone
+)
+
+// Names of the durabilities.
+const (
+	DurabilityGroupName = "group"
+	DurabilitySyncName  = "sync"
+	DurabilityAsyncName = "async"
+	DurabilityNoneName  = "none"
+)
+
+// ParseDurability returns the durability named s. An empty name is the
+// default group durability.
+func ParseDurability(s string) (Durability, error) {
+	switch s {
+	case "", DurabilityGroupName:
+		return DurabilityGroup, nil
+	case DurabilitySyncName:
+		return DurabilitySync, nil
+	case DurabilityAsyncName:
+		return DurabilityAsync, nil
+	}
+	return DurabilityGroup, fmt.Errorf("unknown WAL durability %q, expected %q, %q or %q",
+		s, DurabilitySyncName, DurabilityGroupName, DurabilityAsyncName)
+}
+
+// String returns the name of the durability.
+func (d Durability) String() string {
+	switch d {
+	case DurabilityGroup:
+		return DurabilityGroupName
+	case DurabilitySync:
+		return DurabilitySyncName
+	case DurabilityAsync:
+		return DurabilityAsyncName
+	case DurabilityNone:
+		return DurabilityNoneName
+	}
+	return fmt.Sprintf("Durability(%d)", int(d))
+}
+
+// Max returns the more durable of d and other.
+func (d Durability) Max(other Durability) Durability {
+	if d.rank() >= other.rank() {
+		return d
+	}
+	return other
+}
+
+// rank orders the durabilities from the least durable.
+func (d Durability) rank() int {
+	switch d {
+	case DurabilitySync:
+		return 3
+	case DurabilityGroup:
+		return 2
+	case DurabilityAsync:
+		return 1
+	}
+	return 0
+}
@@ -103,6 +103,13 @@ type WAL struct {
 	// is opened if a non-default value is required.
 	syncDelay time.Duration
 
+	// durability is the durability of the writes made with WriteMulti.
+	durability Durability
+
+	// unsynced is set when entries were written without waiting for an fsync
+	// since the last one.
+	unsynced bool
+
 	// WALOutput is the writer used by the logger.
 	logger *zap.Logger // Logger to be used for important messages
 
@@ -136,6 +143,20 @@ func (l *WAL) WithFsyncDelay(delay time.Duration) {
 	l.syncDelay = delay
 }
 
+// WithDurability sets the durability of the writes made with WriteMulti and
+// should be called before the WAL is opened.
+func (l *WAL) WithDurability(d Durability) {
+	l.durability = d
+}
+
+// Durability returns the durability of the writes made with WriteMulti.
+func (l *WAL) Durability() Durability {
+	if !l.enabled {
+		return DurabilityNone
+	}
+	return l.durability
+}
+
 // SetEnabled sets if the WAL is enabled and should be called before the WAL is opened.
 func (l *WAL) SetEnabled(enabled bool) {
 	l.enabled = enabled
@@ -280,7 +301,7 @@ func (l *WAL) scheduleSync() {
 			select {
 			case <-timerCh:
 				l.mu.Lock()
-				if len(l.syncWaiters) == 0 {
+				if len(l.syncWaiters) == 0 && !l.unsynced {
 					atomic.StoreUint64(&l.syncCount, 0)
 					l.mu.Unlock()
 					return
@@ -300,6 +321,7 @@ func (l *WAL) scheduleSync() {
 // a write lock on the WAL is obtained before calling sync.
 func (l *WAL) sync() {
 	err := l.currentSegmentWriter.sync()
+	l.unsynced = false
 	for len(l.syncWaiters) > 0 {
 		errC := <-l.syncWaiters
 		errC <- err
@@ -310,6 +332,12 @@ func (l *WAL) sync() {
 // which the points were written. If an error is returned the segment ID should
 // be ignored. If the WAL is disabled, -1 and nil is returned.
 func (l *WAL) WriteMulti(ctx context.Context, values map[string][]value.Value) (int, error) {
+	return l.WriteMultiDurability(ctx, values, l.durability)
+}
+
+// WriteMultiDurability writes the given values to the WAL as WriteMulti does,
+// returning once they are as durable as d.
+func (l *WAL) WriteMultiDurability(ctx context.Context, values map[string][]value.Value, d Durability) (int, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -321,7 +349,7 @@ func (l *WAL) WriteMulti(ctx context.Context, values map[string][]value.Value) (
 		Values: values,
 	}
 
-	id, err := l.writeToLog(entry)
+	id, err := l.writeToLog(entry, d)
 	if err != nil {
 		l.tracker.IncWritesErr()
 		return -1, err
@@ -417,7 +445,7 @@ func (l *WAL) DiskSizeBytes() int64 {
 	return int64(l.tracker.OldSegmentSize() + l.tracker.CurrentSegmentSize())
 }
 
-func (l *WAL) writeToLog(entry WALEntry) (int, error) {
+func (l *WAL) writeToLog(entry WALEntry, d Durability) (int, error) {
 	// limit how many concurrent encodings can be in flight.  Since we can only
 	// write one at a time to disk, a slow disk can cause the allocations below
 	// to increase quickly.  If we're backed up, wait until others have completed.
@@ -434,7 +462,10 @@ func (l *WAL) writeToLog(entry WALEntry) (int, error) {
 	compressed := snappy.Encode(encBuf, b)
 	bytesPool.Put(bytes)
 
-	syncErr := make(chan error)
+	var syncErr chan error
+	if d == DurabilityGroup {
+		syncErr = make(chan error)
+	}
 
 	segID, err := func() (int, error) {
 		l.mu.Lock()
@@ -457,12 +488,29 @@ func (l *WAL) writeToLog(entry WALEntry) (int, error) {
 			return -1, fmt.Errorf("error writing WAL entry: %v", err)
 		}
 
-		select {
-		case l.syncWaiters <- syncErr:
+		switch d {
+		case DurabilitySync:
+			// The entry is fsynced on its own, with any unsynced entry before it.
+			if err := l.currentSegmentWriter.sync(); err != nil {
+				return -1, fmt.Errorf("error syncing WAL entry: %v", err)
+			}
+			l.unsynced = false
+		case DurabilityAsync:
+			// Flush the entry to the segment file so that it survives a process
+			// crash, and leave the fsync to the background.
+			if err := l.currentSegmentWriter.Flush(); err != nil {
+				return -1, fmt.Errorf("error writing WAL entry: %v", err)
+			}
+			l.unsynced = true
+			l.scheduleSync()
 		default:
-			return -1, fmt.Errorf("error syncing wal")
+			select {
+			case l.syncWaiters <- syncErr:
+			default:
+				return -1, fmt.Errorf("error syncing wal")
+			}
+			l.scheduleSync()
 		}
-		l.scheduleSync()
 
 		// Update stats for current segment size
 		l.tracker.SetCurrentSegmentSize(uint64(l.currentSegmentWriter.size))
@@ -474,7 +522,7 @@ func (l *WAL) writeToLog(entry WALEntry) (int, error) {
 
 	bytesPool.Put(encBuf)
 
-	if err != nil {
+	if err != nil || syncErr == nil {
 		return segID, err
 	}
 
@@ -532,7 +580,8 @@ func (l *WAL) DeleteBucketRange(orgID, bucketID influxdb.ID, min, max int64, pre
 		Predicate: pred,
 	}
 
-	id, err := l.writeToLog(entry)
+	// Deletes are at least as durable as the default durability.
+	id, err := l.writeToLog(entry, l.durability.Max(DurabilityGroup))
 	if err != nil {
 		return -1, err
 	}
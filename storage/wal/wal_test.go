@@ -8,6 +8,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/snappy"
 
@@ -308,6 +309,82 @@ func TestWAL_ClosedSegments(t *testing.T) {
 	}
 }
 
+func TestWAL_WriteMultiDurability(t *testing.T) {
+	for _, d := range []Durability{DurabilitySync, DurabilityGroup, DurabilityAsync} {
+		t.Run(d.String(), func(t *testing.T) {
+			dir := MustTempDir()
+			defer os.RemoveAll(dir)
+
+			w := NewWAL(dir)
+			w.WithFsyncDelay(10 * time.Millisecond)
+			if err := w.Open(context.Background()); err != nil {
+				t.Fatalf("error opening WAL: %v", err)
+			}
+
+			for i := 0; i < 10; i++ {
+				if _, err := w.WriteMultiDurability(context.Background(), map[string][]value.Value{
+					"cpu,host=A#!~#value": []value.Value{
+						value.NewValue(int64(i), 1.1),
+					},
+				}, d); err != nil {
+					t.Fatalf("error writing points: %v", err)
+				}
+			}
+
+			// The entries are in the segment file before the WAL is closed.
+			files, err := SegmentFileNames(dir)
+			if err != nil {
+				t.Fatalf("error getting segments: %v", err)
+			}
+			var n int
+			if err := NewWALReader(files).Read(func(entry WALEntry) error {
+				n += len(entry.(*WriteWALEntry).Values["cpu,host=A#!~#value"])
+				return nil
+			}); err != nil {
+				t.Fatalf("error reading WAL: %v", err)
+			}
+			if got, exp := n, 10; got != exp {
+				t.Fatalf("values mismatch: got %v, exp %v", got, exp)
+			}
+
+			if err := w.Close(); err != nil {
+				t.Fatalf("error closing wal: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseDurability(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		exp  Durability
+	}{
+		{name: "", exp: DurabilityGroup},
+		{name: "group", exp: DurabilityGroup},
+		{name: "sync", exp: DurabilitySync},
+		{name: "async", exp: DurabilityAsync},
+	} {
+		if got, err := ParseDurability(tc.name); err != nil {
+			t.Fatalf("error parsing %q: %v", tc.name, err)
+		} else if got != tc.exp {
+			t.Fatalf("durability mismatch for %q: got %v, exp %v", tc.name, got, tc.exp)
+		}
+	}
+	if _, err := ParseDurability("none"); err == nil {
+		t.Fatal("expected error parsing none")
+	}
+
+	if got, exp := DurabilityAsync.Max(DurabilitySync), DurabilitySync; got != exp {
+		t.Fatalf("max mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := DurabilityGroup.Max(DurabilityAsync), DurabilityGroup; got != exp {
+		t.Fatalf("max mismatch: got %v, exp %v", got, exp)
+	}
+	if got, exp := DurabilityNone.Max(DurabilityAsync), DurabilityAsync; got != exp {
+		t.Fatalf("max mismatch: got %v, exp %v", got, exp)
+	}
+}
+
 func TestWALWriter_Corrupt(t *testing.T) {
 	dir := MustTempDir()
 	defer os.RemoveAll(dir)
@@ -527,6 +604,40 @@ func BenchmarkWALSegmentWriter(b *testing.B) {
 	}
 }
 
+func BenchmarkWAL_WriteMultiDurability(b *testing.B) {
+	for _, d := range []Durability{DurabilitySync, DurabilityGroup, DurabilityAsync} {
+		for _, delay := range []time.Duration{0, time.Millisecond} {
+			b.Run(fmt.Sprintf("%s/fsync-delay=%s", d, delay), func(b *testing.B) {
+				points := map[string][]value.Value{}
+				for i := 0; i < 100; i++ {
+					k := "cpu,host=A#!~#value"
+					points[k] = append(points[k], value.NewValue(int64(i), 1.1))
+				}
+
+				dir := MustTempDir()
+				defer os.RemoveAll(dir)
+
+				w := NewWAL(dir)
+				w.WithFsyncDelay(delay)
+				if err := w.Open(context.Background()); err != nil {
+					b.Fatalf("error opening WAL: %v", err)
+				}
+				defer w.Close()
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						if _, err := w.WriteMultiDurability(context.Background(), points, d); err != nil {
+							b.Fatalf("unexpected error writing entry: %v", err)
+						}
+					}
+				})
+			})
+		}
+	}
+}
+
 func BenchmarkWALSegmentReader(b *testing.B) {
 	points := map[string][]value.Value{}
 	for i := 0; i < 5000; i++ {
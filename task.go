@@ -56,14 +56,21 @@ type Task struct {
 	Flux            string                 `json:"flux"`
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
+	Location        string                 `json:"location,omitempty"`
 	Offset          time.Duration          `json:"offset,omitempty"`
 	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
 	LastRunError    string                 `json:"lastRunError,omitempty"`
+	Alert           *TaskAlert             `json:"alert,omitempty"`
 	CreatedAt       time.Time              `json:"createdAt,omitempty"`
 	UpdatedAt       time.Time              `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+
+	// ConsecutiveFailures is the number of runs that failed since the last successful run.
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+	// TemplateID is the task the task was created from, if any.
+	TemplateID ID `json:"templateID,omitempty"`
 }
 
 // EffectiveCron returns the effective cron string of the options.
@@ -81,6 +88,44 @@ func (t *Task) EffectiveCron() string {
 	return ""
 }
 
+// TaskAlert is the failure policy of a task, it notifies a notification endpoint when runs of the task fail.
+type TaskAlert struct {
+	// EndpointID is the notification endpoint the notifications are sent to.
+	EndpointID ID `json:"endpointID"`
+	// Failures is the number of consecutive failed runs that trigger a notification,
+	// 1 notifies on every failure. A notification is sent again each time another Failures runs fail.
+	Failures int `json:"failures"`
+	// OnRecovery sends a notification when a run succeeds after failures were notified.
+	OnRecovery bool `json:"onRecovery,omitempty"`
+}
+
+// Valid returns an error if the alert is not a valid failure policy.
+func (a *TaskAlert) Valid() error {
+	if !a.EndpointID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "alert endpointID is invalid",
+		}
+	}
+	if a.Failures < 1 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "alert failures must be at least 1",
+		}
+	}
+	return nil
+}
+
+// Notify reports whether a run should send a notification given the number of
+// consecutive failures before it, and whether the run failed.
+// The returned recovered is true when the notification is a recovery notification.
+func (a *TaskAlert) Notify(failuresBefore int, failed bool) (notify, recovered bool) {
+	if failed {
+		return (failuresBefore+1)%a.Failures == 0, false
+	}
+	return a.OnRecovery && failuresBefore >= a.Failures, true
+}
+
 // Run is a record createId when a run of a task is scheduled.
 type Run struct {
 	ID           ID        `json:"id,omitempty"`
@@ -92,13 +137,77 @@ type Run struct {
 	FinishedAt   time.Time `json:"finishedAt,omitempty"`  // FinishedAt is the time the executor finishes running the task
 	RequestedAt  time.Time `json:"requestedAt,omitempty"` // RequestedAt is the time the coordinator told the scheduler to schedule the task
 	Log          []Log     `json:"log,omitempty"`
+
+	// Output holds the first rows of each result the run's query yielded, it is only captured
+	// when the task sets the captureRows option.
+	Output []RunOutput `json:"output,omitempty"`
+	// Statistics are the statistics of the query executed by the run.
+	Statistics *RunStatistics `json:"statistics,omitempty"`
+}
+
+// RunOutput is the captured output of a single result yielded by a run's query.
+type RunOutput struct {
+	// Name is the name of the yield that produced the result.
+	Name   string           `json:"name"`
+	Tables []RunOutputTable `json:"tables,omitempty"`
+	// Truncated is true when the result had more rows than were captured.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// RunOutputTable holds the captured rows of one table of a result.
+type RunOutputTable struct {
+	Columns []RunOutputColumn `json:"columns"`
+	Rows    [][]interface{}   `json:"rows"`
 }
 
+// RunOutputColumn describes a column of a captured table.
+type RunOutputColumn struct {
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Group bool   `json:"group,omitempty"`
+}
+
+// RunStatistics are the statistics of the query executed by a run, as reported by flux.
+type RunStatistics struct {
+	// PointsRead is the number of values the storage engine scanned for the query.
+	PointsRead int64 `json:"pointsRead"`
+	// BytesRead is the number of bytes the storage engine scanned for the query.
+	BytesRead int64 `json:"bytesRead"`
+	// PointsWritten is the number of points the query wrote with to().
+	PointsWritten int64 `json:"pointsWritten"`
+
+	CompileDuration time.Duration `json:"compileDuration"`
+	QueueDuration   time.Duration `json:"queueDuration"`
+	PlanDuration    time.Duration `json:"planDuration"`
+	ExecuteDuration time.Duration `json:"executeDuration"`
+	TotalDuration   time.Duration `json:"totalDuration"`
+
+	// MaxAllocated is the maximum number of bytes the query allocated.
+	MaxAllocated int64 `json:"maxAllocated"`
+	// RuntimeErrors are the errors that happened during the execution of the query.
+	RuntimeErrors []string `json:"runtimeErrors,omitempty"`
+}
+
+// LogLevel is the severity of a run log entry.
+type LogLevel string
+
+// The levels of run log entries.
+const (
+	LogLevelInfo  LogLevel = "info"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelError LogLevel = "error"
+)
+
 // Log represents a link to a log resource
 type Log struct {
 	RunID   ID     `json:"runID,omitempty"`
 	Time    string `json:"time"`
 	Message string `json:"message"`
+
+	// Level is the severity of the entry, an empty level is treated as info.
+	Level LogLevel `json:"level,omitempty"`
+	// Source is the position in the task's Flux script the entry refers to, i.e.: "3:5-3:20".
+	Source string `json:"source,omitempty"`
 }
 
 func (l Log) String() string {
@@ -153,13 +262,26 @@ type TaskCreate struct {
 	OrganizationID ID                     `json:"orgID,omitempty"`
 	Organization   string                 `json:"org,omitempty"`
 	OwnerID        ID                     `json:"-"`
+	Alert          *TaskAlert             `json:"alert,omitempty"`
 	Metadata       map[string]interface{} `json:"-"` // not to be set through a web request but rather used by a http service using tasks backend.
+
+	// TemplateID is the task whose Flux the task is created from, in place of Flux.
+	TemplateID ID `json:"templateID,omitempty"`
+	// Params are the values bound to the parameters the Flux declares with the params option, by name.
+	Params map[string]interface{} `json:"params,omitempty"`
 }
 
 func (t TaskCreate) Validate() error {
+	if t.Alert != nil {
+		if err := t.Alert.Valid(); err != nil {
+			return err
+		}
+	}
 	switch {
-	case t.Flux == "":
-		return errors.New("missing flux")
+	case t.Flux == "" && !t.TemplateID.Valid():
+		return errors.New("missing flux or templateID")
+	case t.Flux != "" && t.TemplateID.Valid():
+		return errors.New("cannot specify both flux and templateID")
 	case !t.OrganizationID.Valid() && t.Organization == "":
 		return errors.New("missing orgID and org")
 	case t.Status != "" && t.Status != TaskStatusActive && t.Status != TaskStatusInactive:
@@ -174,6 +296,9 @@ type TaskUpdate struct {
 	Status      *string `json:"status,omitempty"`
 	Description *string `json:"description,omitempty"`
 
+	// Alert replaces the failure policy of the task, an alert without an endpointID removes it.
+	Alert *TaskAlert `json:"alert,omitempty"`
+
 	// LatestCompleted us to set latest completed on startup to skip task catchup
 	LatestCompleted *time.Time             `json:"-"`
 	LatestScheduled *time.Time             `json:"-"`
@@ -196,6 +321,9 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
 
+		// Location is the time zone Cron is evaluated in, i.e.: "Europe/Berlin".
+		Location string `json:"location,omitempty"`
+
 		// Every represents a fixed period to repeat execution.
 		// It gets marshalled from a string duration, i.e.: "10s" is 10 seconds
 		Every options.Duration `json:"every,omitempty"`
@@ -207,6 +335,8 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		Concurrency *int64 `json:"concurrency,omitempty"`
 
 		Retry *int64 `json:"retry,omitempty"`
+
+		Alert *TaskAlert `json:"alert,omitempty"`
 	}{}
 
 	if err := json.Unmarshal(data, &jo); err != nil {
@@ -215,6 +345,7 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	t.Options.Name = jo.Name
 	t.Description = jo.Description
 	t.Options.Cron = jo.Cron
+	t.Options.Location = jo.Location
 	t.Options.Every = jo.Every
 	if jo.Offset != nil {
 		offset := *jo.Offset
@@ -224,6 +355,7 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	t.Options.Retry = jo.Retry
 	t.Flux = jo.Flux
 	t.Status = jo.Status
+	t.Alert = jo.Alert
 	return nil
 }
 
@@ -237,6 +369,9 @@ func (t *TaskUpdate) MarshalJSON() ([]byte, error) {
 		// Cron is a cron style time schedule that can be used in place of Every.
 		Cron string `json:"cron,omitempty"`
 
+		// Location is the time zone Cron is evaluated in, i.e.: "Europe/Berlin".
+		Location string `json:"location,omitempty"`
+
 		// Every represents a fixed period to repeat execution.
 		Every options.Duration `json:"every,omitempty"`
 
@@ -246,9 +381,12 @@ func (t *TaskUpdate) MarshalJSON() ([]byte, error) {
 		Concurrency *int64 `json:"concurrency,omitempty"`
 
 		Retry *int64 `json:"retry,omitempty"`
+
+		Alert *TaskAlert `json:"alert,omitempty"`
 	}{}
 	jo.Name = t.Options.Name
 	jo.Cron = t.Options.Cron
+	jo.Location = t.Options.Location
 	jo.Every = t.Options.Every
 	jo.Description = t.Description
 	if t.Options.Offset != nil {
@@ -259,13 +397,26 @@ func (t *TaskUpdate) MarshalJSON() ([]byte, error) {
 	jo.Retry = t.Options.Retry
 	jo.Flux = t.Flux
 	jo.Status = t.Status
+	jo.Alert = t.Alert
 	return json.Marshal(jo)
 }
 
 func (t *TaskUpdate) Validate() error {
+	if t.Alert != nil && t.Alert.EndpointID.Valid() {
+		if err := t.Alert.Valid(); err != nil {
+			return err
+		}
+	}
+	if t.Options.Location != "" {
+		if _, err := options.LoadLocation(t.Options.Location); err != nil {
+			return fmt.Errorf("location: %s is invalid", err)
+		}
+	}
 	switch {
 	case !t.Options.Every.IsZero() && t.Options.Cron != "":
 		return errors.New("cannot specify both every and cron")
+	case !t.Options.Every.IsZero() && t.Options.Location != "":
+		return errors.New("cannot specify location with every")
 	case !t.Options.Every.IsZero():
 		if _, err := parser.ParseSignedDuration(t.Options.Every.String()); err != nil {
 			return fmt.Errorf("every: %s is invalid", err)
@@ -274,7 +425,7 @@ func (t *TaskUpdate) Validate() error {
 		if _, err := time.ParseDuration(t.Options.Offset.String()); err != nil {
 			return fmt.Errorf("offset: %s, %s is invalid, the largest unit supported is h", t.Options.Offset.String(), err)
 		}
-	case t.Flux == nil && t.Status == nil && t.Options.IsZero():
+	case t.Flux == nil && t.Status == nil && t.Alert == nil && t.Options.IsZero():
 		return errors.New("cannot update task without content")
 	case t.Status != nil && *t.Status != TaskStatusActive && *t.Status != TaskStatusInactive:
 		return fmt.Errorf("invalid task status: %q", *t.Status)
@@ -328,6 +479,12 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) (err error) {
 	if t.Options.Cron != "" {
 		op["cron"] = &ast.StringLiteral{Value: t.Options.Cron}
 	}
+	if t.Options.Location != "" {
+		op["location"] = &ast.StringLiteral{Value: t.Options.Location}
+	} else if !t.Options.Every.IsZero() {
+		// a location only applies to cron, so drop it when switching to every
+		toDelete["location"] = struct{}{}
+	}
 	if t.Options.Offset != nil {
 		if !t.Options.Offset.IsZero() {
 			op["offset"] = &t.Options.Offset.Node
@@ -345,18 +502,28 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) (err error) {
 			if !ok {
 				return nil, fmt.Errorf("value is is %s, not an object expression", a.Init.Type())
 			}
+			// remove the keys that are to be deleted from the ast
+			props := obj.Properties[:0]
+			for _, p := range obj.Properties {
+				if _, ok := toDelete[p.Key.Key()]; !ok {
+					props = append(props, p)
+				}
+			}
+			obj.Properties = props
 			// modify in the keys and values that already are in the ast
-			for i, p := range obj.Properties {
+			for _, p := range obj.Properties {
 				k := p.Key.Key()
-				if _, ok := toDelete[k]; ok {
-					obj.Properties = append(obj.Properties[:i], obj.Properties[i+1:]...)
-				}
 				switch k {
 				case "name":
 					if name, ok := op["name"]; ok && t.Options.Name != "" {
 						delete(op, "name")
 						p.Value = name
 					}
+				case "location":
+					if location, ok := op["location"]; ok {
+						delete(op, "location")
+						p.Value = location
+					}
 				case "offset":
 					if offset, ok := op["offset"]; ok && t.Options.Offset != nil {
 						delete(op, "offset")
@@ -22,6 +22,8 @@ const (
 	finishedAtField   = "finishedAt"
 	requestedAtField  = "requestedAt"
 	logField          = "logs"
+	outputField       = "output"
+	statisticsField   = "statistics"
 
 	taskIDTag = "taskID"
 	statusTag = "status"
@@ -407,6 +409,24 @@ func (re *runReader) readRuns(cr flux.ColReader) error {
 						re.log.Info("Failed to parse log data", zap.Error(err), zap.ByteString("log_bytes", logBytes))
 					}
 				}
+			case outputField:
+				outputBytes := bytes.TrimSpace(cr.Strings(j).Value(i))
+				if len(outputBytes) != 0 {
+					err := json.Unmarshal(outputBytes, &r.Output)
+					if err != nil {
+						re.log.Info("Failed to parse output data", zap.Error(err), zap.ByteString("output_bytes", outputBytes))
+					}
+				}
+			case statisticsField:
+				statsBytes := bytes.TrimSpace(cr.Strings(j).Value(i))
+				if len(statsBytes) != 0 {
+					r.Statistics = &influxdb.RunStatistics{}
+					err := json.Unmarshal(statsBytes, r.Statistics)
+					if err != nil {
+						re.log.Info("Failed to parse statistics data", zap.Error(err), zap.ByteString("statistics_bytes", statsBytes))
+						r.Statistics = nil
+					}
+				}
 			}
 		}
 
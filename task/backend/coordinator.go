@@ -40,6 +40,14 @@ func NotifyCoordinatorOfExisting(ctx context.Context, log *zap.Logger, ts TaskSe
 				continue
 			}
 
+			// Tasks owned by another node keep the schedule that node checkpointed.
+			if owned, err := acquireTask(ctx, coord, task.ID); err != nil {
+				log.Error("Failed to acquire task", zap.String("taskID", task.ID.String()), zap.Error(err))
+				continue
+			} else if !owned {
+				continue
+			}
+
 			task, err := ts.UpdateTask(context.Background(), task.ID, influxdb.TaskUpdate{
 				LatestCompleted: &latestCompleted,
 				LatestScheduled: &latestCompleted,
@@ -63,6 +71,25 @@ func NotifyCoordinatorOfExisting(ctx context.Context, log *zap.Logger, ts TaskSe
 	return nil
 }
 
+// TaskOwner is implemented by coordinators that share the execution of tasks with other nodes.
+type TaskOwner interface {
+	// OwnsTask reports whether this node owns the execution of the task.
+	OwnsTask(id influxdb.ID) bool
+	// AcquireTask takes the execution of the task for this node if no other node owns it,
+	// reporting whether this node owns it.
+	AcquireTask(ctx context.Context, id influxdb.ID) (bool, error)
+}
+
+// acquireTask reports whether this node executes the task, taking it for coordinators
+// that share the execution of tasks with other nodes.
+func acquireTask(ctx context.Context, coord Coordinator, id influxdb.ID) (bool, error) {
+	o, ok := coord.(TaskOwner)
+	if !ok {
+		return true, nil
+	}
+	return o.AcquireTask(ctx, id)
+}
+
 type TaskResumer func(ctx context.Context, id influxdb.ID, runID influxdb.ID) error
 
 // TaskNotifyCoordinatorOfExisting lists all tasks by the provided task service and for
@@ -82,6 +109,14 @@ func TaskNotifyCoordinatorOfExisting(ctx context.Context, ts TaskService, tcs Ta
 				continue
 			}
 
+			// Tasks owned by another node keep the schedule that node checkpointed.
+			if owned, err := acquireTask(ctx, coord, task.ID); err != nil {
+				log.Error("Failed to acquire task", zap.String("taskID", task.ID.String()), zap.Error(err))
+				continue
+			} else if !owned {
+				continue
+			}
+
 			task, err := ts.UpdateTask(context.Background(), task.ID, influxdb.TaskUpdate{
 				LatestCompleted: &latestCompleted,
 				LatestScheduled: &latestCompleted,
@@ -92,6 +127,10 @@ func TaskNotifyCoordinatorOfExisting(ctx context.Context, ts TaskService, tcs Ta
 			}
 
 			coord.TaskCreated(ctx, task)
+			if o, ok := coord.(TaskOwner); ok && !o.OwnsTask(task.ID) {
+				// the runs of the task are resumed by the node that owns it.
+				continue
+			}
 			runs, err := tcs.CurrentlyRunning(ctx, task.ID)
 			if err != nil {
 				return err
@@ -9,6 +9,7 @@ import (
 	"github.com/influxdata/influxdb/v2/task/backend/executor"
 	"github.com/influxdata/influxdb/v2/task/backend/middleware"
 	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"github.com/influxdata/influxdb/v2/task/options"
 	"go.uber.org/zap"
 )
 
@@ -31,6 +32,9 @@ type Coordinator struct {
 	ex  Executor
 
 	limit int
+
+	// leases is nil unless the execution of tasks is shared with other nodes.
+	leases *Leases
 }
 
 type CoordinatorOption func(*Coordinator)
@@ -81,9 +85,13 @@ func NewSchedulableTask(task *influxdb.Task) (SchedulableTask, error) {
 		ts = task.LatestScheduled
 	}
 
+	loc, err := options.LoadLocation(task.Location)
+	if err != nil {
+		return SchedulableTask{}, err
+	}
+
 	var sch scheduler.Schedule
-	var err error
-	sch, ts, err = scheduler.NewSchedule(effCron, ts)
+	sch, ts, err = scheduler.NewScheduleInLocation(effCron, loc, ts)
 	if err != nil {
 		return SchedulableTask{}, err
 	}
@@ -107,6 +115,10 @@ func NewCoordinator(log *zap.Logger, scheduler scheduler.Scheduler, executor Exe
 
 // TaskCreated asks the Scheduler to schedule the newly created task
 func (c *Coordinator) TaskCreated(ctx context.Context, task *influxdb.Task) error {
+	if c.leases != nil {
+		return c.scheduleLeased(ctx, task)
+	}
+
 	t, err := NewSchedulableTask(task)
 
 	if err != nil {
@@ -123,6 +135,13 @@ func (c *Coordinator) TaskCreated(ctx context.Context, task *influxdb.Task) erro
 
 // TaskUpdated releases the task if it is being disabled, and schedules it otherwise
 func (c *Coordinator) TaskUpdated(ctx context.Context, from, to *influxdb.Task) error {
+	if c.leases != nil {
+		if to.Status == string(influxdb.TaskInactive) {
+			return c.releaseLeased(ctx, to.ID)
+		}
+		return c.scheduleLeased(ctx, to)
+	}
+
 	sid := scheduler.ID(to.ID)
 	t, err := NewSchedulableTask(to)
 	if err != nil {
@@ -145,6 +164,11 @@ func (c *Coordinator) TaskUpdated(ctx context.Context, from, to *influxdb.Task)
 
 //TaskDeleted asks the Scheduler to release the deleted task
 func (c *Coordinator) TaskDeleted(ctx context.Context, id influxdb.ID) error {
+	if c.leases != nil {
+		// the lease of a deleted task is removed along with it.
+		return c.releaseLocal(id)
+	}
+
 	tid := scheduler.ID(id)
 	if err := c.sch.Release(tid); err != nil && err != influxdb.ErrTaskNotClaimed {
 		return err
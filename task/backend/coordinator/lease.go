@@ -0,0 +1,247 @@
+package coordinator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/task/backend"
+	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"go.uber.org/zap"
+)
+
+var _ backend.TaskOwner = (*Coordinator)(nil)
+
+// MinLeaseTTL is the shortest ttl of task leases, which are renewed three
+// times per ttl.
+const MinLeaseTTL = time.Second
+
+// Leases shares the execution of tasks between several nodes using the same store.
+// A node only schedules the tasks it holds the lease of, renews those leases with heartbeats,
+// and takes over the tasks of other nodes once their leases expire.
+type Leases struct {
+	ls    backend.TaskLeaseService
+	owner string
+	ttl   time.Duration
+
+	mu sync.Mutex
+	// scheduled holds the tasks scheduled on this node, with the time they were last updated.
+	scheduled map[influxdb.ID]time.Time
+}
+
+// NewLeases returns the Leases of the node identified by owner, each lease expires after ttl
+// unless it is renewed. The ttl must be at least MinLeaseTTL.
+func NewLeases(ls backend.TaskLeaseService, owner string, ttl time.Duration) *Leases {
+	return &Leases{
+		ls:        ls,
+		owner:     owner,
+		ttl:       ttl,
+		scheduled: make(map[influxdb.ID]time.Time),
+	}
+}
+
+// WithLeases makes the Coordinator only schedule the tasks whose lease it holds.
+// Every Coordinator sharing a scheduler must be given the same Leases.
+func WithLeases(l *Leases) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.leases = l
+	}
+}
+
+// Executor wraps ex so that a scheduled run is only executed while this node holds the lease of its task,
+// guarding against two nodes executing the same run while the ownership of a task changes.
+func (l *Leases) Executor(ex scheduler.Executor) scheduler.Executor {
+	return &leasedExecutor{Executor: ex, l: l}
+}
+
+type leasedExecutor struct {
+	scheduler.Executor
+	l *Leases
+}
+
+func (e *leasedExecutor) Execute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
+	ok, err := e.l.ls.AcquireTaskLease(ctx, influxdb.ID(id), e.l.owner, e.l.ttl)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// the task is owned by another node, which checkpoints its runs. The next
+		// heartbeat releases it from this node's scheduler.
+		return scheduler.ErrSkipRun
+	}
+	return e.Executor.Execute(ctx, id, scheduledFor, runAt)
+}
+
+func (l *Leases) isScheduled(id influxdb.ID) (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	updatedAt, ok := l.scheduled[id]
+	return updatedAt, ok
+}
+
+func (l *Leases) setScheduled(id influxdb.ID, updatedAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.scheduled[id] = updatedAt
+}
+
+func (l *Leases) unsetScheduled(id influxdb.ID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.scheduled, id)
+}
+
+func (l *Leases) scheduledIDs() []influxdb.ID {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ids := make([]influxdb.ID, 0, len(l.scheduled))
+	for id := range l.scheduled {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// OwnsTask reports whether this node owns the execution of the task,
+// which is always the case when the Coordinator does not use leases.
+func (c *Coordinator) OwnsTask(id influxdb.ID) bool {
+	if c.leases == nil {
+		return true
+	}
+	_, ok := c.leases.isScheduled(id)
+	return ok
+}
+
+// AcquireTask acquires the lease of the task for this node, reporting whether this node owns
+// its execution, which is always the case when the Coordinator does not use leases.
+func (c *Coordinator) AcquireTask(ctx context.Context, id influxdb.ID) (bool, error) {
+	if c.leases == nil {
+		return true, nil
+	}
+	return c.leases.ls.AcquireTaskLease(ctx, id, c.leases.owner, c.leases.ttl)
+}
+
+// scheduleLeased schedules task if this node holds, or can acquire, its lease,
+// and releases it from the scheduler otherwise.
+func (c *Coordinator) scheduleLeased(ctx context.Context, task *influxdb.Task) error {
+	ok, err := c.leases.ls.AcquireTaskLease(ctx, task.ID, c.leases.owner, c.leases.ttl)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return c.releaseLocal(task.ID)
+	}
+
+	t, err := NewSchedulableTask(task)
+	if err != nil {
+		return err
+	}
+	if err := c.sch.Schedule(t); err != nil {
+		return err
+	}
+	c.leases.setScheduled(task.ID, task.UpdatedAt)
+	return nil
+}
+
+// releaseLeased releases task from the scheduler and gives up its lease.
+func (c *Coordinator) releaseLeased(ctx context.Context, id influxdb.ID) error {
+	if err := c.releaseLocal(id); err != nil {
+		return err
+	}
+	return c.leases.ls.ReleaseTaskLease(ctx, id, c.leases.owner)
+}
+
+// releaseLocal releases task from this node's scheduler, without touching its lease.
+func (c *Coordinator) releaseLocal(id influxdb.ID) error {
+	if _, ok := c.leases.isScheduled(id); !ok {
+		return nil
+	}
+	if err := c.sch.Release(scheduler.ID(id)); err != nil && err != influxdb.ErrTaskNotClaimed {
+		return err
+	}
+	c.leases.unsetScheduled(id)
+	return nil
+}
+
+// Heartbeat renews the leases this node holds and takes over the active tasks whose lease expired.
+// Tasks this node lost the lease of, and tasks that were disabled or deleted through another node,
+// are released from the scheduler.
+func (c *Coordinator) Heartbeat(ctx context.Context, ts backend.TaskService) error {
+	if c.leases == nil {
+		return nil
+	}
+
+	seen := make(map[influxdb.ID]bool)
+	tasks, _, err := ts.FindTasks(ctx, influxdb.TaskFilter{})
+	if err != nil {
+		return err
+	}
+	for len(tasks) > 0 {
+		for _, task := range tasks {
+			seen[task.ID] = true
+			if err := c.heartbeatTask(ctx, task); err != nil {
+				c.log.Info("Failed to renew task lease", zap.String("taskID", task.ID.String()), zap.Error(err))
+			}
+		}
+
+		tasks, _, err = ts.FindTasks(ctx, influxdb.TaskFilter{
+			After: &tasks[len(tasks)-1].ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range c.leases.scheduledIDs() {
+		if !seen[id] {
+			if err := c.releaseLocal(id); err != nil {
+				c.log.Info("Failed to release deleted task", zap.String("taskID", id.String()), zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Coordinator) heartbeatTask(ctx context.Context, task *influxdb.Task) error {
+	updatedAt, scheduled := c.leases.isScheduled(task.ID)
+	if task.Status != string(influxdb.TaskActive) {
+		if !scheduled {
+			return nil
+		}
+		return c.releaseLeased(ctx, task.ID)
+	}
+
+	if !scheduled || !updatedAt.Equal(task.UpdatedAt) {
+		// the task is new to this node, or was updated through another node.
+		return c.scheduleLeased(ctx, task)
+	}
+
+	ok, err := c.leases.ls.AcquireTaskLease(ctx, task.ID, c.leases.owner, c.leases.ttl)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return c.releaseLocal(task.ID)
+	}
+	return nil
+}
+
+// RunHeartbeats calls Heartbeat three times per lease ttl, until ctx is done.
+func (c *Coordinator) RunHeartbeats(ctx context.Context, ts backend.TaskService) {
+	if c.leases == nil {
+		return
+	}
+
+	ticker := time.NewTicker(c.leases.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Heartbeat(ctx, ts); err != nil {
+				c.log.Error("Failed to heartbeat task leases", zap.Error(err))
+			}
+		}
+	}
+}
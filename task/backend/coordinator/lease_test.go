@@ -0,0 +1,219 @@
+package coordinator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kv"
+	_ "github.com/influxdata/influxdb/v2/query/builtin"
+	"github.com/influxdata/influxdb/v2/task/backend"
+	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"go.uber.org/zap/zaptest"
+)
+
+// countingExecutor counts the runs it executes, and reports the result of every run the
+// scheduler hands to the leased executor wrapping it.
+type countingExecutor struct {
+	mu       sync.Mutex
+	executed int
+
+	leased  scheduler.Executor
+	results chan error
+}
+
+func (e *countingExecutor) Execute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.executed++
+	return nil
+}
+
+func (e *countingExecutor) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.executed
+}
+
+// scheduledExecutor is the executor of the scheduler of a node.
+type scheduledExecutor struct {
+	*countingExecutor
+}
+
+func (e scheduledExecutor) Execute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) error {
+	err := e.leased.Execute(ctx, id, scheduledFor, runAt)
+	select {
+	case e.results <- err:
+	default:
+	}
+	return err
+}
+
+// countingCheckpointer counts the runs the scheduler of a node checkpoints.
+type countingCheckpointer struct {
+	scheduler.SchedulableService
+
+	mu          sync.Mutex
+	checkpoints int
+}
+
+func (c *countingCheckpointer) UpdateLastScheduled(ctx context.Context, id scheduler.ID, t time.Time) error {
+	c.mu.Lock()
+	c.checkpoints++
+	c.mu.Unlock()
+	return c.SchedulableService.UpdateLastScheduled(ctx, id, t)
+}
+
+func (c *countingCheckpointer) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.checkpoints
+}
+
+type leasedNode struct {
+	coord       *Coordinator
+	sch         *scheduler.TreeScheduler
+	exec        *countingExecutor
+	checkpoints *countingCheckpointer
+}
+
+func newLeasedNode(t *testing.T, store *kv.Service, owner string, ttl time.Duration) *leasedNode {
+	leases := NewLeases(store, owner, ttl)
+	n := &leasedNode{
+		exec:        &countingExecutor{results: make(chan error, 100)},
+		checkpoints: &countingCheckpointer{SchedulableService: backend.NewSchedulableTaskService(store)},
+	}
+	n.exec.leased = leases.Executor(n.exec)
+
+	// The schedulers use the real clock, as the timers of the mock clock cannot be
+	// reset concurrently with the clock being moved.
+	sch, _, err := scheduler.NewScheduler(scheduledExecutor{n.exec}, n.checkpoints)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.sch = sch
+	n.coord = NewCoordinator(zaptest.NewLogger(t), sch, &executorE{}, WithLeases(leases))
+	return n
+}
+
+// waitRun waits for the scheduler of n to hand it a run, returning the result of the run.
+func (n *leasedNode) waitRun(t *testing.T) error {
+	t.Helper()
+	select {
+	case err := <-n.exec.results:
+		return err
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for a scheduled run")
+		return nil
+	}
+}
+
+func Test_Coordinator_Leases(t *testing.T) {
+	ctx := context.Background()
+	c := clock.NewMock()
+	c.Set(time.Now())
+
+	store := kv.NewService(zaptest.NewLogger(t), inmem.NewKVStore(), kv.ServiceConfig{Clock: c})
+	if err := store.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	user := &influxdb.User{Name: "user"}
+	if err := store.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "org"}
+	if err := store.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	authCtx := icontext.SetAuthorizer(ctx, &influxdb.Authorization{UserID: user.ID, OrgID: org.ID})
+	task, err := store.CreateTask(authCtx, influxdb.TaskCreate{
+		Flux:           `option task = {name: "a task", every: 1s} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: org.ID,
+		OwnerID:        user.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const ttl = 30 * time.Second
+	a := newLeasedNode(t, store, "a", ttl)
+	b := newLeasedNode(t, store, "b", ttl)
+	stop := func() {
+		for _, n := range []*leasedNode{a, b} {
+			if n.sch != nil {
+				n.sch.Stop()
+				n.sch = nil
+			}
+		}
+	}
+	defer stop()
+
+	// the task is created through node a, which takes its lease.
+	if err := a.coord.TaskCreated(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.coord.Heartbeat(ctx, store); err != nil {
+		t.Fatal(err)
+	}
+	if !a.coord.OwnsTask(task.ID) || b.coord.OwnsTask(task.ID) {
+		t.Fatal("expected node a to own the task")
+	}
+
+	// node b still has the task scheduled from before it lost its lease to node a,
+	// until its next heartbeat.
+	st, err := NewSchedulableTask(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.sch.Schedule(st); err != nil {
+		t.Fatal(err)
+	}
+
+	// both schedulers fire the runs, only node a executes and checkpoints them.
+	for i := 0; i < 2; i++ {
+		if err := a.waitRun(t); err != nil {
+			t.Fatalf("unexpected run error on node a: %v", err)
+		}
+		if err := b.waitRun(t); err != scheduler.ErrSkipRun {
+			t.Fatalf("expected node b to skip the run, got %v", err)
+		}
+	}
+	// stopping the schedulers waits for their checkpoints.
+	stop()
+
+	if a.exec.count() < 2 || b.exec.count() != 0 {
+		t.Fatalf("expected only node a to execute runs, got a: %d b: %d", a.exec.count(), b.exec.count())
+	}
+	if a.checkpoints.count() < 2 || b.checkpoints.count() != 0 {
+		t.Fatalf("expected only node a to checkpoint runs, got a: %d b: %d", a.checkpoints.count(), b.checkpoints.count())
+	}
+
+	// node b restarts, and leaves the schedule of the task node a owns as is.
+	task, err = store.FindTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latestScheduled := task.LatestScheduled
+	resumed := func(ctx context.Context, id influxdb.ID, runID influxdb.ID) error {
+		t.Fatalf("unexpected resumed run %s of task %s", runID, id)
+		return nil
+	}
+	if err := backend.TaskNotifyCoordinatorOfExisting(ctx, store, store, b.coord, resumed, zaptest.NewLogger(t)); err != nil {
+		t.Fatal(err)
+	}
+	task, err = store.FindTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !task.LatestScheduled.Equal(latestScheduled) {
+		t.Fatalf("expected the schedule of the task to be kept, got latest scheduled %v, exp %v", task.LatestScheduled, latestScheduled)
+	}
+	if b.coord.OwnsTask(task.ID) {
+		t.Fatal("expected node b not to take the task")
+	}
+}
@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
+)
+
+// AlertSender delivers the failure alerts of tasks to notification endpoints.
+type AlertSender interface {
+	Send(ctx context.Context, edp influxdb.NotificationEndpoint, msg endpoint.Message) error
+}
+
+// WithNotificationEndpoints lets the Executor send the failure alerts of tasks
+// to the notification endpoints found in ns.
+func WithNotificationEndpoints(ns influxdb.NotificationEndpointService, sender AlertSender) executorOption {
+	return func(o *executorConfig) {
+		o.endpoints = ns
+		o.alertSender = sender
+	}
+}
+
+// alert sends the notification the failure policy of the promise's task asks for, if any.
+// It must be called before the run is finished, so the outcome can be added to the run log.
+func (w *worker) alert(p *promise, rs influxdb.RunStatus, runErr error) {
+	if w.e.endpoints == nil || w.e.alertSender == nil || p.task.Alert == nil {
+		return
+	}
+	if rs != influxdb.RunSuccess && rs != influxdb.RunFail {
+		return
+	}
+
+	// the task of the promise was read when the run was created, fetch the current failure count.
+	t, err := w.e.ts.FindTaskByID(p.ctx, p.task.ID)
+	if err != nil {
+		w.alertLog(p, influxdb.LogLevelWarn, fmt.Sprintf("Failed to look up task for alert: %v", err))
+		return
+	}
+	if t.Alert == nil {
+		return
+	}
+	notify, recovered := t.Alert.Notify(t.ConsecutiveFailures, rs == influxdb.RunFail)
+	if !notify {
+		return
+	}
+
+	edp, err := w.e.endpoints.FindNotificationEndpointByID(p.ctx, t.Alert.EndpointID)
+	if err != nil {
+		w.alertLog(p, influxdb.LogLevelWarn, fmt.Sprintf("Failed to find alert endpoint %s: %v", t.Alert.EndpointID, err))
+		return
+	}
+	if edp.GetOrgID() != t.OrganizationID || edp.GetStatus() != influxdb.Active {
+		return
+	}
+
+	if err := w.e.alertSender.Send(p.ctx, edp, alertMessage(t, p.run, recovered, runErr)); err != nil {
+		w.alertLog(p, influxdb.LogLevelWarn, fmt.Sprintf("Failed to send alert to endpoint %q: %v", edp.GetName(), err))
+		return
+	}
+	w.alertLog(p, influxdb.LogLevelInfo, fmt.Sprintf("Sent alert to endpoint %q", edp.GetName()))
+}
+
+func (w *worker) alertLog(p *promise, level influxdb.LogLevel, msg string) {
+	w.e.tcs.AddRunLogEntry(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), influxdb.Log{Level: level, Message: msg})
+}
+
+// alertMessage builds the notification of a failed, or recovered, run of t.
+func alertMessage(t *influxdb.Task, run *influxdb.Run, recovered bool, runErr error) endpoint.Message {
+	msg := endpoint.Message{
+		Key:      "task-" + t.ID.String(),
+		Resolved: recovered,
+	}
+	if recovered {
+		msg.Text = fmt.Sprintf("Task %q (%s) recovered: run %s scheduled for %s succeeded after %d failed runs.",
+			t.Name, t.ID, run.ID, run.ScheduledFor.UTC().Format(time.RFC3339), t.ConsecutiveFailures)
+		return msg
+	}
+	errMsg := "unknown error"
+	if runErr != nil {
+		errMsg = runErr.Error()
+	}
+	msg.Text = fmt.Sprintf("Task %q (%s) failed: run %s scheduled for %s failed %d times in a row: %s",
+		t.Name, t.ID, run.ID, run.ScheduledFor.UTC().Format(time.RFC3339), t.ConsecutiveFailures+1, errMsg)
+	return msg
+}
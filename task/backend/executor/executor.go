@@ -14,6 +14,7 @@ import (
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/task/backend"
 	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
+	"github.com/influxdata/influxdb/v2/task/options"
 	"go.uber.org/zap"
 )
 
@@ -47,7 +48,9 @@ func MultiLimit(limits ...LimitFunc) LimitFunc {
 type LimitFunc func(*influxdb.Task, *influxdb.Run) error
 
 type executorConfig struct {
-	maxWorkers int
+	maxWorkers  int
+	endpoints   influxdb.NotificationEndpointService
+	alertSender AlertSender
 }
 
 type executorOption func(*executorConfig)
@@ -75,6 +78,9 @@ func NewExecutor(log *zap.Logger, qs query.QueryService, as influxdb.Authorizati
 		qs:  qs,
 		as:  as,
 
+		endpoints:   cfg.endpoints,
+		alertSender: cfg.alertSender,
+
 		currentPromises: sync.Map{},
 		promiseQueue:    make(chan *promise, maxPromises),
 		workerLimit:     make(chan struct{}, cfg.maxWorkers),
@@ -100,6 +106,10 @@ type Executor struct {
 	qs query.QueryService
 	as influxdb.AuthorizationService
 
+	// endpoints and alertSender deliver the failure alerts of tasks, alerting is disabled when they are nil.
+	endpoints   influxdb.NotificationEndpointService
+	alertSender AlertSender
+
 	metrics *ExecutorMetrics
 
 	// currentPromises are all the promises we are made that have not been fulfilled
@@ -300,7 +310,10 @@ func (w *worker) work() {
 			}
 
 			// add to the run log
-			w.e.tcs.AddRunLog(prom.ctx, prom.task.ID, prom.run.ID, time.Now().UTC(), fmt.Sprintf("Task limit reached: %s", err.Error()))
+			w.e.tcs.AddRunLogEntry(prom.ctx, prom.task.ID, prom.run.ID, time.Now().UTC(), influxdb.Log{
+				Level:   influxdb.LogLevelWarn,
+				Message: fmt.Sprintf("Task limit reached: %s", err.Error()),
+			})
 
 			// sleep
 			select {
@@ -358,7 +371,7 @@ func (w *worker) finish(p *promise, rs influxdb.RunStatus, err error) {
 
 	// log error
 	if err != nil {
-		w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), err.Error())
+		w.e.tcs.AddRunLogEntry(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), influxdb.Log{Level: influxdb.LogLevelError, Message: err.Error()})
 		w.e.log.Debug("Execution failed", zap.Error(err), zap.String("taskID", p.task.ID.String()))
 		w.e.metrics.LogError(p.task.Type, err)
 
@@ -369,7 +382,10 @@ func (w *worker) finish(p *promise, rs influxdb.RunStatus, err error) {
 			// w.te.ts.UpdateTask(p.ctx, p.task.ID, influxdb.TaskUpdate{Status: &inactive})
 
 			// and add to run logs
-			w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Task encountered unrecoverable error, requires admin action: %v", err.Error()))
+			w.e.tcs.AddRunLogEntry(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), influxdb.Log{
+				Level:   influxdb.LogLevelError,
+				Message: fmt.Sprintf("Task encountered unrecoverable error, requires admin action: %v", err.Error()),
+			})
 			// add to metrics
 			w.e.metrics.LogUnrecoverableError(p.task.ID, err)
 		}
@@ -379,6 +395,8 @@ func (w *worker) finish(p *promise, rs influxdb.RunStatus, err error) {
 		w.e.log.Debug("Completed successfully", zap.String("taskID", p.task.ID.String()))
 	}
 
+	w.alert(p, rs, err)
+
 	if _, err := w.e.tcs.FinishRun(p.ctx, p.task.ID, p.run.ID); err != nil {
 		w.e.log.Error("Failed to finish run", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
 	}
@@ -393,10 +411,18 @@ func (w *worker) executeQuery(p *promise) {
 
 	pkg, err := flux.Parse(p.task.Flux)
 	if err != nil {
+		for _, l := range parseErrorLogs(p.task.Flux) {
+			w.e.tcs.AddRunLogEntry(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), l)
+		}
 		w.finish(p, influxdb.RunFail, influxdb.ErrFluxParseError(err))
 		return
 	}
 
+	var captureRows int
+	if opts, err := options.FromScript(p.task.Flux); err == nil && opts.CaptureRows != nil {
+		captureRows = int(*opts.CaptureRows)
+	}
+
 	sf := p.run.ScheduledFor
 
 	req := &query.Request{
@@ -409,6 +435,8 @@ func (w *worker) executeQuery(p *promise) {
 	}
 	req.WithReturnNoContent(true)
 	ctx = icontext.SetAuthorizer(ctx, p.task.Authorization)
+	writes := &query.WriteStatistics{}
+	ctx = query.ContextWithWriteStatistics(ctx, writes)
 	it, err := w.e.qs.Query(ctx, req)
 	if err != nil {
 		// Assume the error should not be part of the runResult.
@@ -416,18 +444,40 @@ func (w *worker) executeQuery(p *promise) {
 		return
 	}
 
-	var runErr error
+	var (
+		runErr error
+		output []influxdb.RunOutput
+	)
 	// Drain the result iterator.
 	for it.More() {
 		// Consume the full iterator so that we don't leak outstanding iterators.
 		res := it.Next()
-		if runErr = w.exhaustResultIterators(res); runErr != nil {
+		if captureRows > 0 {
+			var out influxdb.RunOutput
+			out, runErr = captureResult(res, captureRows)
+			output = append(output, out)
+		} else {
+			runErr = w.exhaustResultIterators(res)
+		}
+		if runErr != nil {
 			w.e.log.Info("Error exhausting result iterator", zap.Error(runErr), zap.String("name", res.Name()))
+			w.e.tcs.AddRunLogEntry(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), influxdb.Log{
+				Level:   influxdb.LogLevelError,
+				Message: fmt.Sprintf("Error reading result %q: %v", res.Name(), runErr),
+			})
 		}
 	}
 
 	it.Release()
 
+	stats := runStatistics(it.Statistics(), writes)
+	for _, msg := range stats.RuntimeErrors {
+		w.e.tcs.AddRunLogEntry(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), influxdb.Log{Level: influxdb.LogLevelWarn, Message: msg})
+	}
+	if err := w.e.tcs.SetRunResult(p.ctx, p.task.ID, p.run.ID, output, stats); err != nil {
+		w.e.log.Error("Failed to record run result", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
+	}
+
 	// log the trace id and whether or not it was sampled into the run log
 	if traceID, isSampled, ok := tracing.InfoFromSpan(span); ok {
 		msg := fmt.Sprintf("trace_id=%s is_sampled=%t", traceID, isSampled)
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/influxdb/v2"
 	icontext "github.com/influxdata/influxdb/v2/context"
@@ -18,6 +19,7 @@ import (
 	"github.com/influxdata/influxdb/v2/kit/prom/promtest"
 	tracetest "github.com/influxdata/influxdb/v2/kit/tracing/testing"
 	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/notification/endpoint"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/task/backend"
 	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
@@ -76,6 +78,8 @@ func TestTaskExecutor(t *testing.T) {
 	t.Run("Metrics", testMetrics)
 	t.Run("IteratorFailure", testIteratorFailure)
 	t.Run("ErrorHandling", testErrorHandling)
+	t.Run("CaptureOutput", testCaptureOutput)
+	t.Run("Alert", testAlert)
 }
 
 func testQuerySuccess(t *testing.T) {
@@ -527,6 +531,160 @@ func testErrorHandling(t *testing.T) {
 	*/
 }
 
+func testCaptureOutput(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+
+	script := fmt.Sprintf(`
+option task = {
+			name: %q,
+			every: 1m,
+			captureRows: 5,
+}
+from(bucket: "one") |> to(bucket: "two", orgID: "0000000000000000")`, t.Name())
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tes.svc.WaitForQueryLive(t, script)
+	tes.svc.SucceedQuery(script)
+
+	<-promise.Done()
+
+	if got := promise.Error(); got != nil {
+		t.Fatal(got)
+	}
+
+	run := tes.tcs.run
+	if run == nil {
+		t.Fatal("expected run returned by FinishRun to not be nil")
+	}
+	if run.Statistics == nil {
+		t.Fatal("expected run statistics to be recorded")
+	}
+	if got, exp := run.Statistics.PointsWritten, int64(1); got != exp {
+		t.Fatalf("unexpected points written: got %d, exp %d", got, exp)
+	}
+
+	exp := []influxdb.RunOutput{{
+		Name: "res",
+		Tables: []influxdb.RunOutputTable{{
+			Columns: []influxdb.RunOutputColumn{{Label: "x", Type: "int", Group: true}},
+			// the run is persisted as json, so numbers come back as float64
+			Rows: [][]interface{}{{float64(1)}},
+		}},
+	}}
+	if diff := cmp.Diff(exp, run.Output); diff != "" {
+		t.Fatalf("unexpected run output -want/+got:\n%s", diff)
+	}
+}
+
+type alertSender struct {
+	mu   sync.Mutex
+	msgs []endpoint.Message
+}
+
+func (s *alertSender) Send(ctx context.Context, edp influxdb.NotificationEndpoint, msg endpoint.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = append(s.msgs, msg)
+	return nil
+}
+
+func (s *alertSender) messages() []endpoint.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]endpoint.Message(nil), s.msgs...)
+}
+
+func testAlert(t *testing.T) {
+	t.Parallel()
+	tes := taskExecutorSystem(t)
+	sender := &alertSender{}
+	ex, _ := NewExecutor(zaptest.NewLogger(t), query.QueryServiceBridge{AsyncQueryService: tes.svc}, tes.i, tes.i, tes.tcs, WithNotificationEndpoints(tes.i, sender))
+
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	edp := &endpoint.HTTP{
+		Base: endpoint.Base{
+			Name:   "alerts",
+			OrgID:  &tes.tc.OrgID,
+			Status: influxdb.Active,
+		},
+		URL:        "http://localhost:7777",
+		AuthMethod: "none",
+		Method:     "POST",
+	}
+	if err := tes.i.CreateNotificationEndpoint(ctx, edp, tes.tc.Auth.GetUserID()); err != nil {
+		t.Fatal(err)
+	}
+
+	script := fmt.Sprintf(fmtTestScript, t.Name())
+	task, err := tes.i.CreateTask(ctx, influxdb.TaskCreate{
+		OrganizationID: tes.tc.OrgID,
+		OwnerID:        tes.tc.Auth.GetUserID(),
+		Flux:           script,
+		Alert:          &influxdb.TaskAlert{EndpointID: edp.GetID(), Failures: 2, OnRecovery: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the fake query service only knows queries scheduled for 123.
+	execute := func(runAt int64, fail bool) {
+		t.Helper()
+		promise, err := ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(runAt, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tes.svc.WaitForQueryLive(t, script)
+		if fail {
+			tes.svc.FailQuery(script, errors.New("blargyblargblarg"))
+		} else {
+			tes.svc.SucceedQuery(script)
+		}
+		<-promise.Done()
+	}
+
+	// the first failure is below the policy's threshold.
+	execute(126, true)
+	if msgs := sender.messages(); len(msgs) != 0 {
+		t.Fatalf("expected no alert after the first failure, got %v", msgs)
+	}
+
+	execute(127, true)
+	msgs := sender.messages()
+	if len(msgs) != 1 {
+		t.Fatalf("expected an alert after the second failure, got %v", msgs)
+	}
+	if msgs[0].Resolved || !strings.Contains(msgs[0].Text, "blargyblargblarg") {
+		t.Fatalf("expected a failure alert with the run error, got %+v", msgs[0])
+	}
+
+	execute(128, false)
+	msgs = sender.messages()
+	if len(msgs) != 2 {
+		t.Fatalf("expected a recovery alert, got %v", msgs)
+	}
+	if !msgs[1].Resolved || msgs[1].Key != msgs[0].Key {
+		t.Fatalf("expected the recovery to resolve the failure alert, got %+v", msgs[1])
+	}
+
+	task, err = tes.i.FindTaskByID(ctx, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.ConsecutiveFailures != 0 {
+		t.Fatalf("expected consecutive failures to be reset, got %d", task.ConsecutiveFailures)
+	}
+}
+
 type taskControlService struct {
 	backend.TaskControlService
 
@@ -0,0 +1,137 @@
+package executor
+
+import (
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/query"
+)
+
+const (
+	// metadata keys the storage source reports its scan statistics with.
+	scannedValuesKey = "influxdb/scanned-values"
+	scannedBytesKey  = "influxdb/scanned-bytes"
+)
+
+// captureResult drains all the tables of a flux query Result, recording at most limit rows of them.
+func captureResult(res flux.Result, limit int) (influxdb.RunOutput, error) {
+	out := influxdb.RunOutput{Name: res.Name()}
+	rows := 0
+	err := res.Tables().Do(func(tbl flux.Table) error {
+		var t *influxdb.RunOutputTable
+		return tbl.Do(func(cr flux.ColReader) error {
+			for i := 0; i < cr.Len(); i++ {
+				if rows >= limit {
+					out.Truncated = true
+					return nil
+				}
+				if t == nil {
+					out.Tables = append(out.Tables, newRunOutputTable(tbl))
+					t = &out.Tables[len(out.Tables)-1]
+				}
+				t.Rows = append(t.Rows, rowValues(cr, i))
+				rows++
+			}
+			return nil
+		})
+	})
+	return out, err
+}
+
+func newRunOutputTable(tbl flux.Table) influxdb.RunOutputTable {
+	key := tbl.Key()
+	cols := tbl.Cols()
+	t := influxdb.RunOutputTable{Columns: make([]influxdb.RunOutputColumn, len(cols))}
+	for j, col := range cols {
+		t.Columns[j] = influxdb.RunOutputColumn{
+			Label: col.Label,
+			Type:  col.Type.String(),
+			Group: key.HasCol(col.Label),
+		}
+	}
+	return t
+}
+
+// rowValues returns the values of row i of cr, with null values as nil.
+func rowValues(cr flux.ColReader, i int) []interface{} {
+	row := make([]interface{}, len(cr.Cols()))
+	for j, col := range cr.Cols() {
+		switch col.Type {
+		case flux.TBool:
+			if vs := cr.Bools(j); vs.IsValid(i) {
+				row[j] = vs.Value(i)
+			}
+		case flux.TInt:
+			if vs := cr.Ints(j); vs.IsValid(i) {
+				row[j] = vs.Value(i)
+			}
+		case flux.TUInt:
+			if vs := cr.UInts(j); vs.IsValid(i) {
+				row[j] = vs.Value(i)
+			}
+		case flux.TFloat:
+			if vs := cr.Floats(j); vs.IsValid(i) {
+				row[j] = vs.Value(i)
+			}
+		case flux.TString:
+			if vs := cr.Strings(j); vs.IsValid(i) {
+				row[j] = vs.ValueString(i)
+			}
+		case flux.TTime:
+			if vs := cr.Times(j); vs.IsValid(i) {
+				row[j] = values.Time(vs.Value(i)).Time().UTC().Format(time.RFC3339Nano)
+			}
+		}
+	}
+	return row
+}
+
+// runStatistics converts the statistics of a flux query and of its writes to
+// the statistics of a run.
+func runStatistics(stats flux.Statistics, writes *query.WriteStatistics) *influxdb.RunStatistics {
+	return &influxdb.RunStatistics{
+		PointsRead:      sumMetadata(stats.Metadata, scannedValuesKey),
+		BytesRead:       sumMetadata(stats.Metadata, scannedBytesKey),
+		PointsWritten:   writes.PointsWritten(),
+		CompileDuration: stats.CompileDuration,
+		QueueDuration:   stats.QueueDuration,
+		PlanDuration:    stats.PlanDuration,
+		ExecuteDuration: stats.ExecuteDuration,
+		TotalDuration:   stats.TotalDuration,
+		MaxAllocated:    stats.MaxAllocated,
+		RuntimeErrors:   stats.RuntimeErrors,
+	}
+}
+
+// sumMetadata sums the integer values every source reported for key.
+func sumMetadata(md flux.Metadata, key string) int64 {
+	var n int64
+	for _, v := range md[key] {
+		switch v := v.(type) {
+		case int64:
+			n += v
+		case int:
+			n += int64(v)
+		}
+	}
+	return n
+}
+
+// parseErrorLogs returns a log entry, positioned at the offending node, for each error in a Flux script.
+func parseErrorLogs(script string) []influxdb.Log {
+	var logs []influxdb.Log
+	ast.Walk(ast.CreateVisitor(func(node ast.Node) {
+		for _, err := range node.Errs() {
+			logs = append(logs, influxdb.Log{
+				Level:   influxdb.LogLevelError,
+				Message: err.Error(),
+				Source:  node.Location().String(),
+			})
+		}
+	}), parser.ParseSource(script))
+	return logs
+}
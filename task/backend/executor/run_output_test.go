@@ -0,0 +1,36 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+)
+
+func TestCaptureResult_Truncated(t *testing.T) {
+	res := newFakeResult()
+	out, err := captureResult(res, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Truncated {
+		t.Error("expected output to be truncated")
+	}
+	if len(out.Tables) != 0 {
+		t.Errorf("expected no tables to be captured, got %d", len(out.Tables))
+	}
+}
+
+func TestParseErrorLogs(t *testing.T) {
+	logs := parseErrorLogs("from(bucket: \"b\")\n  |> range(start: -1h")
+	if len(logs) == 0 {
+		t.Fatal("expected parse errors to be logged")
+	}
+	for _, l := range logs {
+		if l.Level != influxdb.LogLevelError {
+			t.Errorf("expected level %q, got %q", influxdb.LogLevelError, l.Level)
+		}
+		if l.Source == "" {
+			t.Errorf("expected a source position for %q", l.Message)
+		}
+	}
+}
@@ -197,6 +197,8 @@ func (q *fakeQuery) run(ctx context.Context) {
 	}
 
 	if q.forcedError == nil {
+		// Count the row of the fake result as written, as to() does.
+		query.WriteStatisticsFromContext(ctx).AddPointsWritten(1)
 		res := newFakeResult()
 		q.results <- res
 	}
@@ -61,6 +61,22 @@ func (s *StoragePointsWriterRecorder) Record(ctx context.Context, orgID influxdb
 	}
 	fields[logField] = string(logBytes)
 
+	if len(run.Output) > 0 {
+		outputBytes, err := json.Marshal(run.Output)
+		if err != nil {
+			return err
+		}
+		fields[outputField] = string(outputBytes)
+	}
+
+	if run.Statistics != nil {
+		statsBytes, err := json.Marshal(run.Statistics)
+		if err != nil {
+			return err
+		}
+		fields[statisticsField] = string(statsBytes)
+	}
+
 	point, err := models.NewPoint("runs", tags, fields, startedAt)
 	if err != nil {
 		return err
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 
@@ -24,6 +25,10 @@ type Executor interface {
 	Execute(ctx context.Context, id ID, scheduledFor time.Time, runAt time.Time) error
 }
 
+// ErrSkipRun is returned by an Executor that did not execute a run, such as a
+// run of a task another node owns. The run is not checkpointed.
+var ErrSkipRun = errors.New("run skipped")
+
 // Schedulable is the interface that encapsulates work that
 // is to be executed on a specified schedule.
 type Schedulable interface {
@@ -52,7 +57,16 @@ type SchedulableService interface {
 	UpdateLastScheduled(ctx context.Context, id ID, t time.Time) error
 }
 
+// NewSchedule returns a Schedule for the unparsed cron string evaluated in UTC,
+// along with lastScheduledAt aligned to the schedule.
 func NewSchedule(unparsed string, lastScheduledAt time.Time) (Schedule, time.Time, error) {
+	return NewScheduleInLocation(unparsed, time.UTC, lastScheduledAt)
+}
+
+// NewScheduleInLocation returns a Schedule for the unparsed cron string whose wall clock
+// fields are evaluated in loc, along with lastScheduledAt aligned to the schedule.
+// The location is ignored for "@every" schedules, as they are fixed periods.
+func NewScheduleInLocation(unparsed string, loc *time.Location, lastScheduledAt time.Time) (Schedule, time.Time, error) {
 	lastScheduledAt = lastScheduledAt.UTC().Truncate(time.Second)
 	c, err := cron.ParseUTC(unparsed)
 	if err != nil {
@@ -68,31 +82,51 @@ func NewSchedule(unparsed string, lastScheduledAt time.Time) (Schedule, time.Tim
 		err := every.Parse(everyString)
 		if err != nil {
 			// We cannot align a invalid time
-			return Schedule{c}, lastScheduledAt, nil
+			return Schedule{cron: c}, lastScheduledAt, nil
 		}
 
 		// drop nanoseconds
 		lastScheduledAt = time.Unix(lastScheduledAt.UTC().Unix(), 0).UTC()
 		everyDur, err := every.DurationFrom(lastScheduledAt)
 		if err != nil {
-			return Schedule{c}, lastScheduledAt, nil
+			return Schedule{cron: c}, lastScheduledAt, nil
 		}
 
 		// and align
 		lastScheduledAt = lastScheduledAt.Truncate(everyDur).Truncate(time.Second)
+		return Schedule{cron: c}, lastScheduledAt, nil
 	}
 
-	return Schedule{c}, lastScheduledAt, err
+	if loc == time.UTC {
+		loc = nil
+	}
+	return Schedule{cron: c, loc: loc}, lastScheduledAt, err
 }
 
 // Schedule is an object a valid schedule of runs
 type Schedule struct {
 	cron cron.Parsed
+	// loc is the location the cron is evaluated in, nil means UTC.
+	loc *time.Location
 }
 
 // Next returns the next time after from that a schedule should trigger on.
+//
+// When the schedule has a location, the cron fields match the wall clock in that location.
+// A wall clock time that falls into a DST gap is moved forward by the length of the gap,
+// and a wall clock time that occurs twice in a DST overlap triggers only once.
 func (s Schedule) Next(from time.Time) (time.Time, error) {
-	return cron.Parsed(s.cron).Next(from)
+	if s.loc == nil {
+		return cron.Parsed(s.cron).Next(from)
+	}
+	next, err := cron.Parsed(s.cron).Next(from.In(s.loc))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !next.After(from) {
+		return time.Time{}, errors.New("next time must be later than from time")
+	}
+	return next.UTC(), nil
 }
 
 // ValidSchedule returns an error if the cron string is invalid.
@@ -666,3 +666,74 @@ func TestNewSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestSchedule_NextInLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		unparsed string
+		from     time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "wall clock follows summer time",
+			unparsed: "0 8 * * 1-5",
+			from:     time.Date(2020, 3, 26, 12, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2020, 3, 27, 7, 0, 0, 0, time.UTC), // 08:00 CET
+				time.Date(2020, 3, 30, 6, 0, 0, 0, time.UTC), // 08:00 CEST
+			},
+		},
+		{
+			name:     "gap moves the run forward",
+			unparsed: "30 2 * * *",
+			from:     time.Date(2020, 3, 28, 12, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2020, 3, 29, 1, 30, 0, 0, time.UTC), // 02:30 does not exist, runs at 03:30 CEST
+				time.Date(2020, 3, 30, 0, 30, 0, 0, time.UTC), // 02:30 CEST
+			},
+		},
+		{
+			name:     "overlap runs once",
+			unparsed: "30 2 * * *",
+			from:     time.Date(2020, 10, 24, 12, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2020, 10, 25, 1, 30, 0, 0, time.UTC), // 02:30 CET
+				time.Date(2020, 10, 26, 1, 30, 0, 0, time.UTC), // 02:30 CET
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sch, _, err := NewScheduleInLocation(tt.unparsed, berlin, tt.from)
+			if err != nil {
+				t.Fatal(err)
+			}
+			from := tt.from
+			for _, want := range tt.want {
+				got, err := sch.Next(from)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !got.Equal(want) {
+					t.Fatalf("Next(%v) got %v, want %v", from, got, want)
+				}
+				from = got
+			}
+		})
+	}
+
+	t.Run("every ignores location", func(t *testing.T) {
+		got, _, err := NewScheduleInLocation("@every 1h", berlin, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := mustCron("@every 1h"); !reflect.DeepEqual(got, want) {
+			t.Errorf("NewScheduleInLocation() got = %v, want %v", got, want)
+		}
+	})
+}
@@ -322,11 +322,16 @@ func (s *TreeScheduler) work(ctx context.Context, ch chan Item) {
 			preExec := time.Now()
 			// execute
 			err = s.executor.Execute(ctx, it.id, t, it.When())
+			if err == ErrSkipRun {
+				return err
+			}
 			// report how long execution took
 			s.sm.reportExecution(err, time.Since(preExec))
 			return err
 		}()
-		if err != nil {
+		if err == ErrSkipRun {
+			continue
+		} else if err != nil {
 			s.onErr(ctx, it.id, it.Next(), err)
 		}
 		// TODO(docmerlin): we can increase performance by making the call to UpdateLastScheduled async
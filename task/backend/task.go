@@ -28,4 +28,21 @@ type TaskControlService interface {
 
 	// AddRunLog adds a log line to the run.
 	AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error
+
+	// AddRunLogEntry adds a structured log entry, with a level and an optional source position, to the run.
+	AddRunLogEntry(ctx context.Context, taskID, runID influxdb.ID, when time.Time, entry influxdb.Log) error
+
+	// SetRunResult records the captured output and the query statistics of the run.
+	SetRunResult(ctx context.Context, taskID, runID influxdb.ID, output []influxdb.RunOutput, stats *influxdb.RunStatistics) error
+}
+
+// TaskLeaseService grants nodes sharing a store exclusive, expiring ownership of tasks,
+// so that only the owner of a task schedules and executes its runs.
+type TaskLeaseService interface {
+	// AcquireTaskLease acquires the lease of a task for owner, or renews it if owner already holds it,
+	// so that it expires ttl from now. It returns false when another owner holds an unexpired lease.
+	AcquireTaskLease(ctx context.Context, taskID influxdb.ID, owner string, ttl time.Duration) (bool, error)
+
+	// ReleaseTaskLease releases the lease of a task if it is held by owner.
+	ReleaseTaskLease(ctx context.Context, taskID influxdb.ID, owner string) error
 }
@@ -165,6 +165,35 @@ func (d *TaskControlService) AddRunLog(ctx context.Context, taskID, runID influx
 	return nil
 }
 
+// AddRunLogEntry adds a structured log entry to the run.
+func (d *TaskControlService) AddRunLogEntry(ctx context.Context, taskID, runID influxdb.ID, when time.Time, entry influxdb.Log) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	run := d.runs[taskID][runID]
+	if run == nil {
+		panic("cannot add a log to a non existent run")
+	}
+	entry.RunID = runID
+	entry.Time = when.Format(time.RFC3339Nano)
+	run.Log = append(run.Log, entry)
+	return nil
+}
+
+// SetRunResult records the captured output and the query statistics of the run.
+func (d *TaskControlService) SetRunResult(ctx context.Context, taskID, runID influxdb.ID, output []influxdb.RunOutput, stats *influxdb.RunStatistics) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	run := d.runs[taskID][runID]
+	if run == nil {
+		panic("cannot set the result of a non existent run")
+	}
+	run.Output = output
+	run.Statistics = stats
+	return nil
+}
+
 func (d *TaskControlService) CreatedFor(taskID influxdb.ID) []*influxdb.Run {
 	d.mu.Lock()
 	defer d.mu.Unlock()
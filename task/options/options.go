@@ -18,6 +18,7 @@ import (
 
 const maxConcurrency = 100
 const maxRetry = 10
+const maxCaptureRows = 100
 
 // Options are the task-related options that can be specified in a Flux script.
 type Options struct {
@@ -27,6 +28,10 @@ type Options struct {
 	// Cron is a cron style time schedule that can be used in place of Every.
 	Cron string `json:"cron,omitempty"`
 
+	// Location is the IANA time zone name, i.e.: "Europe/Berlin", that Cron is evaluated in.
+	// If it is empty, Cron is evaluated in UTC.
+	Location string `json:"location,omitempty"`
+
 	// Every represents a fixed period to repeat execution.
 	// this can be unmarshaled from json as a string i.e.: "1d" will unmarshal as 1 day
 	Every Duration `json:"every,omitempty"`
@@ -38,6 +43,12 @@ type Options struct {
 	Concurrency *int64 `json:"concurrency,omitempty"`
 
 	Retry *int64 `json:"retry,omitempty"`
+
+	// CaptureRows is the number of rows of each yielded result that a run records in its output.
+	CaptureRows *int64 `json:"captureRows,omitempty"`
+
+	// Params are the parameters the script declares with the params option, by name.
+	Params map[string]Param `json:"params,omitempty"`
 }
 
 // Duration is a time span that supports the same units as the flux parser's time duration, as well as negative length time spans.
@@ -123,30 +134,37 @@ func (a *Duration) Add(t time.Time) (time.Time, error) {
 func (o *Options) Clear() {
 	o.Name = ""
 	o.Cron = ""
+	o.Location = ""
 	o.Every = Duration{}
 	o.Offset = nil
 	o.Concurrency = nil
 	o.Retry = nil
+	o.CaptureRows = nil
+	o.Params = nil
 }
 
 // IsZero tells us if the options has been zeroed out.
 func (o *Options) IsZero() bool {
 	return o.Name == "" &&
 		o.Cron == "" &&
+		o.Location == "" &&
 		o.Every.IsZero() &&
 		(o.Offset == nil || o.Offset.IsZero()) &&
 		o.Concurrency == nil &&
-		o.Retry == nil
+		o.Retry == nil &&
+		o.CaptureRows == nil
 }
 
 // All the task option names we accept.
 const (
 	optName        = "name"
 	optCron        = "cron"
+	optLocation    = "location"
 	optEvery       = "every"
 	optOffset      = "offset"
 	optConcurrency = "concurrency"
 	optRetry       = "retry"
+	optCaptureRows = "captureRows"
 )
 
 // contains is a helper function to see if an array of strings contains a string
@@ -258,6 +276,13 @@ func FromScript(script string) (Options, error) {
 		opt.Cron = crVal.Str()
 	}
 
+	if locVal, ok := optObject.Get(optLocation); ok {
+		if err := checkNature(locVal.PolyType().Nature(), semantic.String); err != nil {
+			return opt, err
+		}
+		opt.Location = locVal.Str()
+	}
+
 	if everyOK {
 		if err := checkNature(everyVal.PolyType().Nature(), semantic.Duration); err != nil {
 			return opt, err
@@ -313,6 +338,17 @@ func FromScript(script string) (Options, error) {
 		opt.Retry = pointer.Int64(retryVal.Int())
 	}
 
+	if captureVal, ok := optObject.Get(optCaptureRows); ok {
+		if err := checkNature(captureVal.PolyType().Nature(), semantic.Int); err != nil {
+			return opt, err
+		}
+		opt.CaptureRows = pointer.Int64(captureVal.Int())
+	}
+
+	if opt.Params, err = ParamsFromScript(script); err != nil {
+		return opt, err
+	}
+
 	if err := opt.Validate(); err != nil {
 		return opt, err
 	}
@@ -339,6 +375,9 @@ func (o *Options) Validate() error {
 			errs = append(errs, "cron invalid: "+err.Error())
 		}
 	} else if everyPresent {
+		if o.Location != "" {
+			errs = append(errs, "location may only be specified with cron")
+		}
 		every, err := o.Every.DurationFrom(now)
 		if err != nil {
 			return err
@@ -349,6 +388,11 @@ func (o *Options) Validate() error {
 			errs = append(errs, "every option must be expressible as whole seconds")
 		}
 	}
+	if o.Location != "" {
+		if _, err := o.LoadLocation(); err != nil {
+			errs = append(errs, "location invalid: "+err.Error())
+		}
+	}
 	if o.Offset != nil {
 		offset, err := o.Offset.DurationFrom(now)
 		if err != nil {
@@ -373,6 +417,13 @@ func (o *Options) Validate() error {
 			errs = append(errs, fmt.Sprintf("retry exceeded max of %d", maxRetry))
 		}
 	}
+	if o.CaptureRows != nil {
+		if *o.CaptureRows < 0 {
+			errs = append(errs, "captureRows must not be negative")
+		} else if *o.CaptureRows > maxCaptureRows {
+			errs = append(errs, fmt.Sprintf("captureRows exceeded max of %d", maxCaptureRows))
+		}
+	}
 
 	if len(errs) == 0 {
 		return nil
@@ -400,6 +451,24 @@ func (o *Options) EffectiveCronString() string {
 	return ""
 }
 
+// LoadLocation returns the time.Location the cron option is evaluated in.
+// An empty location is treated as UTC.
+func (o *Options) LoadLocation() (*time.Location, error) {
+	return LoadLocation(o.Location)
+}
+
+// LoadLocation returns the time.Location for the IANA time zone name loc.
+// An empty name is treated as UTC, "Local" is rejected because it depends on the host running influxd.
+func LoadLocation(loc string) (*time.Location, error) {
+	switch loc {
+	case "", "UTC":
+		return time.UTC, nil
+	case "Local":
+		return nil, fmt.Errorf("location %q is not allowed, use an IANA time zone name", loc)
+	}
+	return time.LoadLocation(loc)
+}
+
 // checkNature returns a clean error of got and expected dont match.
 func checkNature(got, exp semantic.Nature) error {
 	if got != exp {
@@ -414,7 +483,7 @@ func validateOptionNames(o values.Object) error {
 	var unexpected []string
 	o.Range(func(name string, _ values.Value) {
 		switch name {
-		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry:
+		case optName, optCron, optLocation, optEvery, optOffset, optConcurrency, optRetry, optCaptureRows:
 			// Known option. Nothing to do.
 		default:
 			unexpected = append(unexpected, name)
@@ -423,7 +492,7 @@ func validateOptionNames(o values.Object) error {
 
 	if len(unexpected) > 0 {
 		u := strings.Join(unexpected, ", ")
-		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry}, ", ")
+		v := strings.Join([]string{optName, optCron, optLocation, optEvery, optOffset, optConcurrency, optRetry, optCaptureRows}, ", ")
 		return fmt.Errorf("unknown task option(s): %s. valid options are %s", u, v)
 	}
 
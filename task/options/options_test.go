@@ -21,6 +21,9 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Cron != "" {
 		taskData = fmt.Sprintf("%s  cron: %q,\n", taskData, opt.Cron)
 	}
+	if opt.Location != "" {
+		taskData = fmt.Sprintf("%s  location: %q,\n", taskData, opt.Location)
+	}
 	if !opt.Every.IsZero() {
 		taskData = fmt.Sprintf("%s  every: %s,\n", taskData, opt.Every.String())
 	}
@@ -33,6 +36,9 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Retry != nil && *opt.Retry != 0 {
 		taskData = fmt.Sprintf("%s  retry: %d,\n", taskData, *opt.Retry)
 	}
+	if opt.CaptureRows != nil {
+		taskData = fmt.Sprintf("%s  captureRows: %d,\n", taskData, *opt.CaptureRows)
+	}
 	if body == "" {
 		body = `from(bucket: "test")
     |> range(start:-1h)`
@@ -99,6 +105,13 @@ func TestFromScript(t *testing.T) {
 		`,
 			exp: options.Options{Name: "name11", Every: *(options.MustParseDuration("1m")), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1), Offset: options.MustParseDuration("1d")},
 		},
+		{script: scriptGenerator(options.Options{Name: "name12", Cron: "0 8 * * 1-5", Location: "Europe/Berlin"}, ""),
+			exp: options.Options{Name: "name12", Cron: "0 8 * * 1-5", Location: "Europe/Berlin", Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name13", Cron: "0 8 * * 1-5", Location: "Not/AZone"}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name14", Every: *(options.MustParseDuration("1h")), Location: "Europe/Berlin"}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name15", Every: *(options.MustParseDuration("1h")), CaptureRows: pointer.Int64(10)}, ""),
+			exp: options.Options{Name: "name15", Every: *(options.MustParseDuration("1h")), CaptureRows: pointer.Int64(10), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name16", Every: *(options.MustParseDuration("1h")), CaptureRows: pointer.Int64(1000)}, ""), shouldErr: true},
 		{script: "option task = {name:\"test_task_smoke_name\", every:30s} from(bucket:\"test_tasks_smoke_bucket_source\") |> range(start: -1h) |> map(fn: (r) => ({r with _time: r._time, _value:r._value, t : \"quality_rocks\"}))|> to(bucket:\"test_tasks_smoke_bucket_dest\", orgID:\"3e73e749495d37d5\")",
 			exp: options.Options{Name: "test_task_smoke_name", Every: *(options.MustParseDuration("30s")), Retry: pointer.Int64(1), Concurrency: pointer.Int64(1)}, shouldErr: false}, // TODO(docmerlin): remove this once tasks fully supports all flux duration units.
 
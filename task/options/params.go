@@ -0,0 +1,260 @@
+package options
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+)
+
+// The types of task parameters, inferred from the literal a parameter is declared with.
+const (
+	ParamString   = "string"
+	ParamInt      = "int"
+	ParamFloat    = "float"
+	ParamBool     = "bool"
+	ParamDuration = "duration"
+	ParamTime     = "time"
+)
+
+// optParams is the name of the option that declares the parameters of a task, i.e.:
+//   option params = {bucket: "telegraf", threshold: 0.9}
+// The script refers to them as params.bucket and params.threshold.
+const optParams = "params"
+
+// Param is a typed parameter declared by the params option of a task.
+type Param struct {
+	Type string `json:"type"`
+	// Default is the value the parameter is declared with, formatted as a Flux literal.
+	Default string `json:"default"`
+}
+
+// ParamsFromScript returns the parameters a Flux script declares, by name.
+// A script without a params option has no parameters.
+func ParamsFromScript(script string) (map[string]Param, error) {
+	obj, err := findParamsObject(parser.ParseSource(script))
+	if err != nil || obj == nil {
+		return nil, err
+	}
+
+	params := make(map[string]Param, len(obj.Properties))
+	for _, prop := range obj.Properties {
+		typ, err := paramType(prop.Value)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %v", prop.Key.Key(), err)
+		}
+		params[prop.Key.Key()] = Param{Type: typ, Default: ast.Format(prop.Value)}
+	}
+	return params, nil
+}
+
+// BindParams returns script with the parameters named in values bound to them,
+// parameters without a value keep their default.
+// A value must either be a string, which is parsed according to the type of its parameter,
+// or a Go value of that type, where integral float64 values, as decoded from JSON, are accepted as ints.
+func BindParams(script string, values map[string]interface{}) (string, error) {
+	if len(values) == 0 {
+		return script, nil
+	}
+
+	pkg := parser.ParseSource(script)
+	if ast.Check(pkg) > 0 {
+		return "", ast.GetError(pkg)
+	}
+	obj, err := findParamsObject(pkg)
+	if err != nil {
+		return "", err
+	}
+
+	props := make(map[string]*ast.Property)
+	if obj != nil {
+		for _, prop := range obj.Properties {
+			props[prop.Key.Key()] = prop
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := props[name]
+		if !ok {
+			return "", fmt.Errorf("unknown task parameter %q", name)
+		}
+		typ, err := paramType(prop.Value)
+		if err != nil {
+			return "", fmt.Errorf("parameter %q: %v", name, err)
+		}
+		lit, err := paramLiteral(typ, values[name])
+		if err != nil {
+			return "", fmt.Errorf("parameter %q: %v", name, err)
+		}
+		prop.Value = lit
+	}
+
+	return ast.Format(pkg.Files[0]), nil
+}
+
+// findParamsObject returns the object expression of the params option, or nil if there is none.
+func findParamsObject(pkg *ast.Package) (*ast.ObjectExpression, error) {
+	for _, file := range pkg.Files {
+		for _, stmt := range file.Body {
+			opt, ok := stmt.(*ast.OptionStatement)
+			if !ok {
+				continue
+			}
+			asmt, ok := opt.Assignment.(*ast.VariableAssignment)
+			if !ok || asmt.ID.Name != optParams {
+				continue
+			}
+			obj, ok := asmt.Init.(*ast.ObjectExpression)
+			if !ok {
+				return nil, fmt.Errorf("option %s must be a record of literals", optParams)
+			}
+			return obj, nil
+		}
+	}
+	return nil, nil
+}
+
+// paramType returns the type of the literal a parameter is declared with.
+func paramType(e ast.Expression) (string, error) {
+	switch e := e.(type) {
+	case *ast.StringLiteral:
+		return ParamString, nil
+	case *ast.IntegerLiteral:
+		return ParamInt, nil
+	case *ast.FloatLiteral:
+		return ParamFloat, nil
+	case *ast.BooleanLiteral:
+		return ParamBool, nil
+	case *ast.Identifier:
+		if e.Name == "true" || e.Name == "false" {
+			return ParamBool, nil
+		}
+	case *ast.DurationLiteral:
+		return ParamDuration, nil
+	case *ast.DateTimeLiteral:
+		return ParamTime, nil
+	case *ast.UnaryExpression:
+		if e.Operator == ast.SubtractionOperator {
+			switch e.Argument.(type) {
+			case *ast.IntegerLiteral:
+				return ParamInt, nil
+			case *ast.FloatLiteral:
+				return ParamFloat, nil
+			case *ast.DurationLiteral:
+				return ParamDuration, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("must be declared with a string, int, float, bool, duration or time literal")
+}
+
+// paramLiteral returns the Flux literal of value for a parameter of type typ.
+func paramLiteral(typ string, value interface{}) (ast.Expression, error) {
+	if s, ok := value.(string); ok && typ != ParamString {
+		return parseParamLiteral(typ, s)
+	}
+
+	switch typ {
+	case ParamString:
+		if s, ok := value.(string); ok {
+			return &ast.StringLiteral{Value: s}, nil
+		}
+	case ParamInt:
+		switch v := value.(type) {
+		case int:
+			return intLiteral(int64(v))
+		case int64:
+			return intLiteral(v)
+		case float64:
+			if v == float64(int64(v)) {
+				return intLiteral(int64(v))
+			}
+		}
+	case ParamFloat:
+		switch v := value.(type) {
+		case float64:
+			return floatLiteral(v), nil
+		case int:
+			return floatLiteral(float64(v)), nil
+		case int64:
+			return floatLiteral(float64(v)), nil
+		}
+	case ParamBool:
+		if b, ok := value.(bool); ok {
+			return &ast.BooleanLiteral{Value: b}, nil
+		}
+	case ParamDuration:
+		if d, ok := value.(time.Duration); ok {
+			return parseParamLiteral(typ, d.String())
+		}
+	case ParamTime:
+		if t, ok := value.(time.Time); ok {
+			return &ast.DateTimeLiteral{Value: t}, nil
+		}
+	}
+	return nil, fmt.Errorf("value %v does not match parameter type %s", value, typ)
+}
+
+// parseParamLiteral parses the string s as the Flux literal of a parameter of type typ.
+func parseParamLiteral(typ string, s string) (ast.Expression, error) {
+	switch typ {
+	case ParamInt:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not an int", s)
+		}
+		return intLiteral(v)
+	case ParamFloat:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not a float", s)
+		}
+		return floatLiteral(v), nil
+	case ParamBool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not a bool", s)
+		}
+		return &ast.BooleanLiteral{Value: v}, nil
+	case ParamDuration:
+		d, err := parseSignedDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not a duration", s)
+		}
+		return d, nil
+	case ParamTime:
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not an RFC3339 time", s)
+		}
+		return &ast.DateTimeLiteral{Value: t}, nil
+	}
+	return nil, fmt.Errorf("value %q does not match parameter type %s", s, typ)
+}
+
+func intLiteral(v int64) (ast.Expression, error) {
+	if v == math.MinInt64 {
+		// The literal of the smallest int overflows before it is negated.
+		return nil, fmt.Errorf("value %d is out of the range of int parameters", v)
+	} else if v < 0 {
+		return &ast.UnaryExpression{Operator: ast.SubtractionOperator, Argument: &ast.IntegerLiteral{Value: -v}}, nil
+	}
+	return &ast.IntegerLiteral{Value: v}, nil
+}
+
+func floatLiteral(v float64) ast.Expression {
+	if v < 0 {
+		return &ast.UnaryExpression{Operator: ast.SubtractionOperator, Argument: &ast.FloatLiteral{Value: -v}}
+	}
+	return &ast.FloatLiteral{Value: v}
+}
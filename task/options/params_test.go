@@ -0,0 +1,117 @@
+package options_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2/task/options"
+)
+
+const paramsScript = `option params = {bucket: "telegraf", threshold: 0.9, limit: 10, enabled: true, window: 5m}
+option task = {name: "params", every: 1h}
+
+from(bucket: params.bucket)
+	|> range(start: -params.window)
+	|> filter(fn: (r) => r._value > params.threshold)
+	|> limit(n: params.limit)`
+
+func TestParamsFromScript(t *testing.T) {
+	params, err := options.ParamsFromScript(paramsScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]options.Param{
+		"bucket":    {Type: options.ParamString, Default: `"telegraf"`},
+		"threshold": {Type: options.ParamFloat, Default: "0.9"},
+		"limit":     {Type: options.ParamInt, Default: "10"},
+		"enabled":   {Type: options.ParamBool, Default: "true"},
+		"window":    {Type: options.ParamDuration, Default: "5m"},
+	}
+	if diff := cmp.Diff(exp, params); diff != "" {
+		t.Fatalf("unexpected params: -want/+got\n%s", diff)
+	}
+
+	opts, err := options.FromScript(paramsScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(exp, opts.Params); diff != "" {
+		t.Fatalf("unexpected option params: -want/+got\n%s", diff)
+	}
+
+	if _, err := options.ParamsFromScript(`option params = {fn: (r) => r}` + "\n" + `from(bucket: "b")`); err == nil {
+		t.Fatal("expected error for a parameter that is not a literal")
+	}
+}
+
+func TestBindParams(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		values map[string]interface{}
+		exp    map[string]string
+		expErr string
+	}{
+		{
+			name:   "no values",
+			values: nil,
+			exp:    map[string]string{"bucket": `"telegraf"`, "limit": "10"},
+		},
+		{
+			name:   "string values",
+			values: map[string]interface{}{"bucket": "other", "threshold": "-1.5", "limit": "20", "enabled": "false", "window": "1h"},
+			exp:    map[string]string{"bucket": `"other"`, "threshold": "-1.5", "limit": "20", "enabled": "false", "window": "1h"},
+		},
+		{
+			name:   "json values",
+			values: map[string]interface{}{"threshold": float64(2), "limit": float64(30), "enabled": false},
+			exp:    map[string]string{"bucket": `"telegraf"`, "threshold": "2.0", "limit": "30", "enabled": "false"},
+		},
+		{
+			name:   "unknown parameter",
+			values: map[string]interface{}{"nope": "x"},
+			expErr: `unknown task parameter "nope"`,
+		},
+		{
+			name:   "type mismatch",
+			values: map[string]interface{}{"limit": "ten"},
+			expErr: `parameter "limit": value "ten" is not an int`,
+		},
+		{
+			name:   "fractional int",
+			values: map[string]interface{}{"limit": 1.5},
+			expErr: `parameter "limit": value 1.5 does not match parameter type int`,
+		},
+		{
+			name:   "smallest int",
+			values: map[string]interface{}{"limit": "-9223372036854775808"},
+			expErr: `parameter "limit": value -9223372036854775808 is out of the range of int parameters`,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			script, err := options.BindParams(paramsScript, c.values)
+			if c.expErr != "" {
+				if err == nil || !strings.Contains(err.Error(), c.expErr) {
+					t.Fatalf("expected error %q, got %v", c.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			params, err := options.ParamsFromScript(script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, exp := range c.exp {
+				if got := params[name].Default; got != exp {
+					t.Errorf("expected parameter %q to be bound to %s, got %s", name, exp, got)
+				}
+			}
+			if _, err := options.FromScript(script); err != nil {
+				t.Fatalf("bound script has invalid options: %v", err)
+			}
+		})
+	}
+}
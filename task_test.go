@@ -154,7 +154,37 @@ from(bucket: "x")
 			t.Fatalf(cmp.Diff(*tu.Flux, expscript))
 		}
 	})
-
+	t.Run("add location", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		tu.Options.Location = "Europe/Berlin"
+		if err := tu.UpdateFlux(`option task = {cron: "0 8 * * *", name: "foo"} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(*tu.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.Location != "Europe/Berlin" {
+			t.Fatalf("expected Location to be \"Europe/Berlin\" but was %s", op.Location)
+		}
+	})
+	t.Run("switching from cron with location to every", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		tu.Options.Every = *(options.MustParseDuration("10s"))
+		if err := tu.UpdateFlux(`option task = {cron: "0 8 * * *", location: "Europe/Berlin", name: "foo", offset: 10s} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(*tu.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.Location != "" {
+			t.Fatalf("expected Location to be \"\" but was %s", op.Location)
+		}
+		if op.Offset == nil || op.Offset.String() != "10s" {
+			t.Fatalf("expected offset to be kept but was %v", op.Offset)
+		}
+	})
 }
 
 func TestParseRequestStillQueuedError(t *testing.T) {
@@ -165,3 +195,47 @@ func TestParseRequestStillQueuedError(t *testing.T) {
 		t.Fatalf("%q should have parsed to %v, but got %v", validMsg, e, err)
 	}
 }
+
+func TestTaskAlert_Notify(t *testing.T) {
+	alert := &platform.TaskAlert{EndpointID: 1, Failures: 3, OnRecovery: true}
+	for _, tt := range []struct {
+		name           string
+		failuresBefore int
+		failed         bool
+		notify         bool
+		recovered      bool
+	}{
+		{name: "below threshold", failuresBefore: 1, failed: true},
+		{name: "reaches threshold", failuresBefore: 2, failed: true, notify: true},
+		{name: "past threshold", failuresBefore: 3, failed: true},
+		{name: "reaches threshold again", failuresBefore: 5, failed: true, notify: true},
+		{name: "success without notified failures", failuresBefore: 2, recovered: true},
+		{name: "recovery", failuresBefore: 4, notify: true, recovered: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			notify, recovered := alert.Notify(tt.failuresBefore, tt.failed)
+			if notify != tt.notify || recovered != tt.recovered {
+				t.Fatalf("expected notify=%v recovered=%v, got notify=%v recovered=%v", tt.notify, tt.recovered, notify, recovered)
+			}
+		})
+	}
+}
+
+func TestTaskUpdate_Alert(t *testing.T) {
+	tu := &platform.TaskUpdate{}
+	if err := json.Unmarshal([]byte(`{"alert":{"endpointID":"020f755c3c082000","failures":0}}`), tu); err != nil {
+		t.Fatal(err)
+	}
+	if err := tu.Validate(); err == nil {
+		t.Fatal("expected an alert without failures to be invalid")
+	}
+
+	// an alert without endpoint removes the failure policy of the task.
+	tu = &platform.TaskUpdate{}
+	if err := json.Unmarshal([]byte(`{"alert":{}}`), tu); err != nil {
+		t.Fatal(err)
+	}
+	if err := tu.Validate(); err != nil {
+		t.Fatalf("expected removing the alert to be valid: %s", err)
+	}
+}
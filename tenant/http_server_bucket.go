@@ -76,6 +76,9 @@ type bucket struct {
 	Name                string          `json:"name"`
 	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
 	RetentionRules      []retentionRule `json:"retentionRules"`
+	StringCompression   string          `json:"stringCompression,omitempty"`
+	IndexedFields       []string        `json:"indexedFields,omitempty"`
+	WALDurability       string          `json:"walDurability,omitempty"`
 	influxdb.CRUDLog
 }
 
@@ -123,6 +126,9 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		StringCompression:   b.StringCompression,
+		IndexedFields:       b.IndexedFields,
+		WALDurability:       b.WALDurability,
 		CRUDLog:             b.CRUDLog,
 	}, nil
 }
@@ -149,15 +155,21 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 		Description:         pb.Description,
 		RetentionPolicyName: pb.RetentionPolicyName,
 		RetentionRules:      rules,
+		StringCompression:   pb.StringCompression,
+		IndexedFields:       pb.IndexedFields,
+		WALDurability:       pb.WALDurability,
 		CRUDLog:             pb.CRUDLog,
 	}
 }
 
 // bucketUpdate is used for serialization/deserialization with retention rules.
 type bucketUpdate struct {
-	Name           *string         `json:"name,omitempty"`
-	Description    *string         `json:"description,omitempty"`
-	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+	Name              *string         `json:"name,omitempty"`
+	Description       *string         `json:"description,omitempty"`
+	RetentionRules    []retentionRule `json:"retentionRules,omitempty"`
+	StringCompression *string         `json:"stringCompression,omitempty"`
+	IndexedFields     *[]string       `json:"indexedFields,omitempty"`
+	WALDurability     *string         `json:"walDurability,omitempty"`
 }
 
 func (b *bucketUpdate) OK() error {
@@ -167,6 +179,21 @@ func (b *bucketUpdate) OK() error {
 			return err
 		}
 	}
+	if b.StringCompression != nil {
+		if err := influxdb.ValidBucketStringCompression(*b.StringCompression); err != nil {
+			return err
+		}
+	}
+	if b.IndexedFields != nil {
+		if err := influxdb.ValidBucketIndexedFields(*b.IndexedFields); err != nil {
+			return err
+		}
+	}
+	if b.WALDurability != nil {
+		if err := influxdb.ValidBucketWALDurability(*b.WALDurability); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -182,9 +209,12 @@ func (b *bucketUpdate) toInfluxDB() *influxdb.BucketUpdate {
 	}
 
 	return &influxdb.BucketUpdate{
-		Name:            b.Name,
-		Description:     b.Description,
-		RetentionPeriod: &d,
+		Name:              b.Name,
+		Description:       b.Description,
+		RetentionPeriod:   &d,
+		StringCompression: b.StringCompression,
+		IndexedFields:     b.IndexedFields,
+		WALDurability:     b.WALDurability,
 	}
 }
 
@@ -194,9 +224,12 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 	}
 
 	up := &bucketUpdate{
-		Name:           pb.Name,
-		Description:    pb.Description,
-		RetentionRules: []retentionRule{},
+		Name:              pb.Name,
+		Description:       pb.Description,
+		RetentionRules:    []retentionRule{},
+		StringCompression: pb.StringCompression,
+		IndexedFields:     pb.IndexedFields,
+		WALDurability:     pb.WALDurability,
 	}
 
 	if pb.RetentionPeriod != nil {
@@ -280,6 +313,9 @@ type postBucketRequest struct {
 	Description         string          `json:"description"`
 	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
 	RetentionRules      []retentionRule `json:"retentionRules"`
+	StringCompression   string          `json:"stringCompression,omitempty"`
+	IndexedFields       []string        `json:"indexedFields,omitempty"`
+	WALDurability       string          `json:"walDurability,omitempty"`
 }
 
 func (b *postBucketRequest) OK() error {
@@ -308,6 +344,27 @@ func (b *postBucketRequest) OK() error {
 		}
 	}
 
+	if err := influxdb.ValidBucketStringCompression(b.StringCompression); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EUnprocessableEntity,
+			Msg:  err.Error(),
+		}
+	}
+
+	if err := influxdb.ValidBucketIndexedFields(b.IndexedFields); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EUnprocessableEntity,
+			Msg:  err.Error(),
+		}
+	}
+
+	if err := influxdb.ValidBucketWALDurability(b.WALDurability); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EUnprocessableEntity,
+			Msg:  err.Error(),
+		}
+	}
+
 	return nil
 }
 
@@ -325,6 +382,9 @@ func (b postBucketRequest) toInfluxDB() *influxdb.Bucket {
 		Type:                influxdb.BucketTypeUser,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     dur,
+		StringCompression:   b.StringCompression,
+		IndexedFields:       b.IndexedFields,
+		WALDurability:       b.WALDurability,
 	}
 }
 
@@ -3,11 +3,31 @@ package tenant
 import "github.com/influxdata/influxdb/v2"
 
 type Service struct {
-	store *Store
+	store          *Store
+	passwordPolicy influxdb.PasswordPolicy
 }
 
-func NewService(st *Store) influxdb.TenantService {
-	return &Service{
-		store: st,
+// ServiceOption configures a Service.
+type ServiceOption func(*Service)
+
+// WithPasswordPolicy sets the requirements new passwords must meet.
+// Passwords are only required to be 8 characters long by default.
+func WithPasswordPolicy(p influxdb.PasswordPolicy) ServiceOption {
+	return func(s *Service) {
+		s.passwordPolicy = p
+	}
+}
+
+func NewService(st *Store, opts ...ServiceOption) influxdb.TenantService {
+	s := &Service{
+		store:          st,
+		passwordPolicy: influxdb.DefaultPasswordPolicy,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.passwordPolicy.MinLength == 0 {
+		s.passwordPolicy.MinLength = influxdb.DefaultPasswordPolicy.MinLength
 	}
+	return s
 }
@@ -150,6 +150,18 @@ func (s *Service) CreateBucket(ctx context.Context, b *influxdb.Bucket) error {
 		return ErrOrgNotFound
 	}
 
+	if err := influxdb.ValidBucketStringCompression(b.StringCompression); err != nil {
+		return err
+	}
+
+	if err := influxdb.ValidBucketIndexedFields(b.IndexedFields); err != nil {
+		return err
+	}
+
+	if err := influxdb.ValidBucketWALDurability(b.WALDurability); err != nil {
+		return err
+	}
+
 	return s.store.Update(ctx, func(tx kv.Tx) error {
 		// make sure the org exists
 		if _, err := s.store.GetOrg(ctx, tx, b.OrgID); err != nil {
@@ -163,6 +175,22 @@ func (s *Service) CreateBucket(ctx context.Context, b *influxdb.Bucket) error {
 // UpdateBucket updates a single bucket with changeset.
 // Returns the new bucket state after update.
 func (s *Service) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+	if upd.StringCompression != nil {
+		if err := influxdb.ValidBucketStringCompression(*upd.StringCompression); err != nil {
+			return nil, err
+		}
+	}
+	if upd.IndexedFields != nil {
+		if err := influxdb.ValidBucketIndexedFields(*upd.IndexedFields); err != nil {
+			return nil, err
+		}
+	}
+	if upd.WALDurability != nil {
+		if err := influxdb.ValidBucketWALDurability(*upd.WALDurability); err != nil {
+			return nil, err
+		}
+	}
+
 	var bucket *influxdb.Bucket
 	err := s.store.Update(ctx, func(tx kv.Tx) error {
 		b, err := s.store.UpdateBucket(ctx, tx, id, upd)
@@ -133,8 +133,8 @@ func (s *Service) DeleteUser(ctx context.Context, id influxdb.ID) error {
 
 // SetPassword overrides the password of a known user.
 func (s *Service) SetPassword(ctx context.Context, userID influxdb.ID, password string) error {
-	if len(password) < 8 {
-		return EShortPassword
+	if err := s.passwordPolicy.Validate(password); err != nil {
+		return err
 	}
 	passHash, err := encryptPassword(password)
 	if err != nil {
@@ -93,3 +93,35 @@ func initPasswordsService(s kv.Store, f influxdbtesting.PasswordFields, t *testi
 		}
 	}
 }
+
+func TestService_SetPasswordPolicy(t *testing.T) {
+	s, closeBolt, err := NewTestBoltStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	storage, err := tenant.NewStore(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := tenant.NewService(storage, tenant.WithPasswordPolicy(influxdb.PasswordPolicy{
+		MinLength:    10,
+		RequireDigit: true,
+	}))
+
+	ctx := context.Background()
+	u := &influxdb.User{Name: "user1"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, password := range []string{"password1", "longpassword"} {
+		if err := svc.SetPassword(ctx, u.ID, password); influxdb.ErrorCode(err) != influxdb.EInvalid {
+			t.Errorf("expected password %q to be rejected, got %v", password, err)
+		}
+	}
+	if err := svc.SetPassword(ctx, u.ID, "longpassword1"); err != nil {
+		t.Errorf("expected password to meet the policy, got %v", err)
+	}
+}
@@ -381,6 +381,18 @@ func (s *Store) UpdateBucket(ctx context.Context, tx kv.Tx, id influxdb.ID, upd
 		bucket.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.StringCompression != nil {
+		bucket.StringCompression = *upd.StringCompression
+	}
+
+	if upd.IndexedFields != nil {
+		bucket.IndexedFields = *upd.IndexedFields
+	}
+
+	if upd.WALDurability != nil {
+		bucket.WALDurability = *upd.WALDurability
+	}
+
 	v, err := marshalBucket(bucket)
 	if err != nil {
 		return nil, err
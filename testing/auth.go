@@ -67,6 +67,10 @@ func AuthorizationService(
 			name: "UpdateAuthorization",
 			fn:   UpdateAuthorization,
 		},
+		{
+			name: "RotateAuthorization",
+			fn:   RotateAuthorization,
+		},
 		{
 			name: "FindAuthorizations",
 			fn:   FindAuthorizations,
@@ -152,7 +156,7 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
+						TokenPrefix: "super",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 						Description: "already existing auth",
 					},
@@ -160,7 +164,7 @@ func CreateAuthorization(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand",
+						TokenPrefix: "ra",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 						Description: "new auth",
@@ -221,14 +225,14 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
+						TokenPrefix: "super",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 					},
 					{
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand",
+						TokenPrefix: "ra",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 						CRUDLog: platform.CRUDLog{
@@ -289,7 +293,7 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
+						TokenPrefix: "super",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 						Description: "already existing auth",
 					},
@@ -347,7 +351,7 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
+						TokenPrefix: "super",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 						Description: "already existing auth",
 					},
@@ -446,7 +450,7 @@ func FindAuthorizationByID(
 					UserID:      MustIDBase16(userTwoID),
 					OrgID:       MustIDBase16(orgOneID),
 					Status:      platform.Active,
-					Token:       "rand2",
+					TokenPrefix: "ra",
 					Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 				},
 			},
@@ -562,7 +566,7 @@ func UpdateAuthorization(
 					ID:          MustIDBase16(authTwoID),
 					UserID:      MustIDBase16(userTwoID),
 					OrgID:       MustIDBase16(orgOneID),
-					Token:       "rand2",
+					TokenPrefix: "ra",
 					Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					Status:      platform.Inactive,
 					Description: "desc1",
@@ -732,6 +736,159 @@ func UpdateAuthorization(
 	}
 }
 
+// RotateAuthorization testing
+func RotateAuthorization(
+	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
+	t *testing.T,
+) {
+	now := time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC)
+	fields := func() AuthorizationFields {
+		return AuthorizationFields{
+			TimeGenerator: &mock.TimeGenerator{
+				FakeValue: now,
+			},
+			TokenGenerator: &mock.TokenGenerator{
+				TokenFn: func() (string, error) {
+					return "rand4", nil
+				},
+			},
+			Users: []*platform.User{
+				{
+					Name: "cooluser",
+					ID:   MustIDBase16(userOneID),
+				},
+			},
+			Orgs: []*platform.Organization{
+				{
+					Name: "o1",
+					ID:   MustIDBase16(orgOneID),
+				},
+			},
+			Authorizations: []*platform.Authorization{
+				{
+					ID:          MustIDBase16(authOneID),
+					UserID:      MustIDBase16(userOneID),
+					OrgID:       MustIDBase16(orgOneID),
+					Token:       "rand1",
+					Status:      platform.Active,
+					Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+				},
+			},
+		}
+	}
+
+	type args struct {
+		id          platform.ID
+		gracePeriod time.Duration
+	}
+	type wants struct {
+		err           error
+		authorization *platform.Authorization
+		// previousToken reports whether the replaced token is still accepted.
+		previousToken bool
+	}
+	graceEnd := now.Add(time.Hour)
+	tests := []struct {
+		name   string
+		fields AuthorizationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "rotate with grace period",
+			fields: fields(),
+			args: args{
+				id:          MustIDBase16(authOneID),
+				gracePeriod: time.Hour,
+			},
+			wants: wants{
+				authorization: &platform.Authorization{
+					ID:                     MustIDBase16(authOneID),
+					UserID:                 MustIDBase16(userOneID),
+					OrgID:                  MustIDBase16(orgOneID),
+					Token:                  "rand4",
+					TokenPrefix:            "ra",
+					Status:                 platform.Active,
+					Permissions:            allUsersPermission(MustIDBase16(orgOneID)),
+					PreviousTokenExpiresAt: &graceEnd,
+					CRUDLog: platform.CRUDLog{
+						UpdatedAt: now,
+					},
+				},
+				previousToken: true,
+			},
+		},
+		{
+			name:   "rotate without grace period",
+			fields: fields(),
+			args: args{
+				id: MustIDBase16(authOneID),
+			},
+			wants: wants{
+				authorization: &platform.Authorization{
+					ID:          MustIDBase16(authOneID),
+					UserID:      MustIDBase16(userOneID),
+					OrgID:       MustIDBase16(orgOneID),
+					Token:       "rand4",
+					TokenPrefix: "ra",
+					Status:      platform.Active,
+					Permissions: allUsersPermission(MustIDBase16(orgOneID)),
+					CRUDLog: platform.CRUDLog{
+						UpdatedAt: now,
+					},
+				},
+			},
+		},
+		{
+			name:   "rotate with id not found",
+			fields: fields(),
+			args: args{
+				id: MustIDBase16(authTwoID),
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpRotateAuthorization,
+					Msg:  "authorization not found",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			rotated, err := s.RotateAuthorization(ctx, tt.args.id, tt.args.gracePeriod)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+			if tt.wants.err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(rotated, tt.wants.authorization, authorizationCmpOptions...); diff != "" {
+				t.Errorf("authorization is different -got/+want\ndiff %s", diff)
+			}
+
+			authorization, err := s.FindAuthorizationByToken(ctx, "rand4")
+			if err != nil {
+				t.Fatalf("expected to find authorization by its new token: %v", err)
+			}
+			if authorization.ID != tt.args.id {
+				t.Errorf("expected new token to belong to authorization %s, got %s", tt.args.id, authorization.ID)
+			}
+
+			_, err = s.FindAuthorizationByToken(ctx, "rand1")
+			if tt.wants.previousToken && err != nil {
+				t.Errorf("expected replaced token to be accepted during the grace period: %v", err)
+			}
+			if !tt.wants.previousToken && platform.ErrorCode(err) != platform.ENotFound {
+				t.Errorf("expected replaced token to not be found, got %v", err)
+			}
+		})
+	}
+}
+
 // FindAuthorizationByToken testing
 func FindAuthorizationByToken(
 	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
@@ -816,6 +973,7 @@ func FindAuthorizationByToken(
 					OrgID:       MustIDBase16(orgTwoID),
 					Status:      platform.Inactive,
 					Token:       "rand1",
+					TokenPrefix: "ra",
 					Permissions: allUsersPermission(MustIDBase16(orgTwoID)),
 				},
 			},
@@ -903,7 +1061,7 @@ func FindAuthorizations(
 						ID:          MustIDBase16(authOneID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand1",
+						TokenPrefix: "ra",
 						Status:      platform.Active,
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 					},
@@ -911,7 +1069,7 @@ func FindAuthorizations(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand2",
+						TokenPrefix: "ra",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
@@ -972,7 +1130,7 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand1",
+						TokenPrefix: "ra",
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
 					},
 					{
@@ -980,7 +1138,7 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand3",
+						TokenPrefix: "ra",
 						Permissions: deleteUsersPermission(MustIDBase16(orgOneID)),
 					},
 				},
@@ -1042,7 +1200,7 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand1",
+						TokenPrefix: "ra",
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
 					{
@@ -1050,7 +1208,7 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand2",
+						TokenPrefix: "ra",
 						Permissions: deleteUsersPermission(MustIDBase16(orgOneID)),
 					},
 				},
@@ -1125,7 +1283,7 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgTwoID),
 						Status:      platform.Active,
-						Token:       "rand2",
+						TokenPrefix: "ra",
 						Permissions: allUsersPermission(MustIDBase16(orgTwoID)),
 					},
 				},
@@ -1191,6 +1349,7 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
 						Token:       "rand2",
+						TokenPrefix: "ra",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
@@ -1293,7 +1452,7 @@ func DeleteAuthorization(
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand2",
+						TokenPrefix: "ra",
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
 				},
@@ -1348,7 +1507,7 @@ func DeleteAuthorization(
 					{
 						ID:          MustIDBase16(authOneID),
 						UserID:      MustIDBase16(userOneID),
-						Token:       "rand1",
+						TokenPrefix: "ra",
 						Status:      platform.Active,
 						OrgID:       MustIDBase16(orgOneID),
 						Permissions: allUsersPermission(MustIDBase16(orgOneID)),
@@ -1357,7 +1516,7 @@ func DeleteAuthorization(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand2",
+						TokenPrefix: "ra",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(MustIDBase16(orgOneID)),
 					},
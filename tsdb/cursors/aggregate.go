@@ -0,0 +1,133 @@
+package cursors
+
+// FloatArrayAggregate holds the count, sum, min and max of a set of float values.
+type FloatArrayAggregate struct {
+	Timestamp int64 // timestamp of the first value, in the order of the cursor
+	Count     int64
+	Sum       float64
+	Min       float64
+	Max       float64
+}
+
+// AddArray adds the values of a to the aggregate.
+func (agg *FloatArrayAggregate) AddArray(a *FloatArray) {
+	for i, v := range a.Values {
+		agg.add(a.Timestamps[i], 1, v, v, v)
+	}
+}
+
+// Merge adds the values aggregated by other to the aggregate.
+func (agg *FloatArrayAggregate) Merge(other FloatArrayAggregate) {
+	if other.Count > 0 {
+		agg.add(other.Timestamp, other.Count, other.Sum, other.Min, other.Max)
+	}
+}
+
+func (agg *FloatArrayAggregate) add(ts, count int64, sum, min, max float64) {
+	if agg.Count == 0 {
+		agg.Timestamp, agg.Min, agg.Max = ts, min, max
+	}
+	if min < agg.Min {
+		agg.Min = min
+	}
+	if max > agg.Max {
+		agg.Max = max
+	}
+	agg.Count += count
+	agg.Sum += sum
+}
+
+// FloatArrayAggregator is implemented by float array cursors that can
+// aggregate their remaining values without decoding every block.
+type FloatArrayAggregator interface {
+	FloatArrayCursor
+	Aggregate() FloatArrayAggregate
+}
+
+// IntegerArrayAggregate holds the count, sum, min and max of a set of integer values.
+type IntegerArrayAggregate struct {
+	Timestamp int64 // timestamp of the first value, in the order of the cursor
+	Count     int64
+	Sum       int64
+	Min       int64
+	Max       int64
+}
+
+// AddArray adds the values of a to the aggregate.
+func (agg *IntegerArrayAggregate) AddArray(a *IntegerArray) {
+	for i, v := range a.Values {
+		agg.add(a.Timestamps[i], 1, v, v, v)
+	}
+}
+
+// Merge adds the values aggregated by other to the aggregate.
+func (agg *IntegerArrayAggregate) Merge(other IntegerArrayAggregate) {
+	if other.Count > 0 {
+		agg.add(other.Timestamp, other.Count, other.Sum, other.Min, other.Max)
+	}
+}
+
+func (agg *IntegerArrayAggregate) add(ts, count int64, sum, min, max int64) {
+	if agg.Count == 0 {
+		agg.Timestamp, agg.Min, agg.Max = ts, min, max
+	}
+	if min < agg.Min {
+		agg.Min = min
+	}
+	if max > agg.Max {
+		agg.Max = max
+	}
+	agg.Count += count
+	agg.Sum += sum
+}
+
+// IntegerArrayAggregator is implemented by integer array cursors that can
+// aggregate their remaining values without decoding every block.
+type IntegerArrayAggregator interface {
+	IntegerArrayCursor
+	Aggregate() IntegerArrayAggregate
+}
+
+// UnsignedArrayAggregate holds the count, sum, min and max of a set of unsigned values.
+type UnsignedArrayAggregate struct {
+	Timestamp int64 // timestamp of the first value, in the order of the cursor
+	Count     int64
+	Sum       uint64
+	Min       uint64
+	Max       uint64
+}
+
+// AddArray adds the values of a to the aggregate.
+func (agg *UnsignedArrayAggregate) AddArray(a *UnsignedArray) {
+	for i, v := range a.Values {
+		agg.add(a.Timestamps[i], 1, v, v, v)
+	}
+}
+
+// Merge adds the values aggregated by other to the aggregate.
+func (agg *UnsignedArrayAggregate) Merge(other UnsignedArrayAggregate) {
+	if other.Count > 0 {
+		agg.add(other.Timestamp, other.Count, other.Sum, other.Min, other.Max)
+	}
+}
+
+func (agg *UnsignedArrayAggregate) add(ts, count int64, sum, min, max uint64) {
+	if agg.Count == 0 {
+		agg.Timestamp, agg.Min, agg.Max = ts, min, max
+	}
+	if min < agg.Min {
+		agg.Min = min
+	}
+	if max > agg.Max {
+		agg.Max = max
+	}
+	agg.Count += count
+	agg.Sum += sum
+}
+
+// UnsignedArrayAggregator is implemented by unsigned array cursors that can
+// aggregate their remaining values without decoding every block.
+type UnsignedArrayAggregator interface {
+	UnsignedArrayCursor
+	Aggregate() UnsignedArrayAggregate
+}
@@ -46,6 +46,30 @@ type CursorRequest struct {
 	Ascending bool
 	StartTime int64
 	EndTime   int64
+
+	// ValueFilter, if set, allows the cursor to skip the blocks whose values
+	// cannot match the condition of the request.
+	ValueFilter BlockFilter
+}
+
+// BlockSummary describes the values of a block without decoding it.
+type BlockSummary struct {
+	Type FieldType
+
+	// Min and Max are the smallest and largest float64, int64 or uint64
+	// values of the block, or nil if they are unknown.
+	Min, Max interface{}
+
+	// Contains reports whether the block may hold the float64, int64, uint64,
+	// string or bool value v. It is nil if the values of the block are not indexed.
+	Contains func(v interface{}) bool
+}
+
+// BlockFilter decides whether a block may hold values matching a condition.
+type BlockFilter interface {
+	// MayMatch returns false if no value of the block summarized by s can
+	// match the condition.
+	MayMatch(s *BlockSummary) bool
 }
 
 type CursorIterator interface {
@@ -4,6 +4,10 @@ const (
 	// DefaultLargeSeriesWriteThreshold is the number of series per write
 	// that requires the series index be pregrown before insert.
 	DefaultLargeSeriesWriteThreshold = 10000
+
+	// DefaultSegmentCompactThreshold is the number of series deleted from a
+	// series file partition before its segments are compacted.
+	DefaultSegmentCompactThreshold = 1 << 16 // 64K
 )
 
 // Config contains all of the configuration related to tsdb.
@@ -11,11 +15,17 @@ type Config struct {
 	// LargeSeriesWriteThreshold is the threshold before a write requires
 	// preallocation to improve throughput. Currently used in the series file.
 	LargeSeriesWriteThreshold int `toml:"large-series-write-threshold"`
+
+	// SegmentCompactThreshold is the number of series deleted from a partition
+	// of the series file before its segments are rewritten without them. Zero
+	// disables segment compactions.
+	SegmentCompactThreshold int `toml:"segment-compact-threshold"`
 }
 
 // NewConfig return a new instance of config with default settings.
 func NewConfig() Config {
 	return Config{
 		LargeSeriesWriteThreshold: DefaultLargeSeriesWriteThreshold,
+		SegmentCompactThreshold:   DefaultSegmentCompactThreshold,
 	}
 }
@@ -50,7 +50,8 @@ type SeriesFile struct {
 	defaultMetricLabels prometheus.Labels
 	metricsEnabled      bool
 
-	LargeWriteThreshold int
+	LargeWriteThreshold     int
+	SegmentCompactThreshold int
 
 	Logger *zap.Logger
 }
@@ -62,7 +63,8 @@ func NewSeriesFile(path string) *SeriesFile {
 		metricsEnabled: true,
 		Logger:         zap.NewNop(),
 
-		LargeWriteThreshold: DefaultLargeSeriesWriteThreshold,
+		LargeWriteThreshold:     DefaultLargeSeriesWriteThreshold,
+		SegmentCompactThreshold: DefaultSegmentCompactThreshold,
 	}
 }
 
@@ -128,6 +130,7 @@ func (f *SeriesFile) Open(ctx context.Context) error {
 		// TODO(edd): These partition initialisation should be moved up to NewSeriesFile.
 		p := NewSeriesPartition(i, f.SeriesPartitionPath(i))
 		p.LargeWriteThreshold = f.LargeWriteThreshold
+		p.SegmentCompactThreshold = f.SegmentCompactThreshold
 		p.Logger = f.Logger.With(zap.Int("partition", p.ID()))
 
 		// For each series file index, rhh trackers are used to track the RHH Hashmap.
@@ -275,7 +278,27 @@ func (f *SeriesFile) IsDeleted(id tsdb.SeriesID) bool {
 	return p.IsDeleted(id)
 }
 
-// SeriesKey returns the series key for a given id.
+// Pin keeps the series keys read from the series file valid until release is
+// called. The keys returned by SeriesKey, SeriesKeys and Series point into the
+// segments of the series file, which may be replaced by compactions.
+func (f *SeriesFile) Pin() (release func()) {
+	epochs := make([]uint64, len(f.partitions))
+	for i, p := range f.partitions {
+		epochs[i] = p.Pin()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			for i, p := range f.partitions {
+				p.Unpin(epochs[i])
+			}
+		})
+	}
+}
+
+// SeriesKey returns the series key for a given id. The key is only valid while
+// the series file is pinned.
 func (f *SeriesFile) SeriesKey(id tsdb.SeriesID) []byte {
 	if id.IsZero() {
 		return nil
@@ -287,6 +310,19 @@ func (f *SeriesFile) SeriesKey(id tsdb.SeriesID) []byte {
 	return p.SeriesKey(id)
 }
 
+// AppendSeriesKey appends the series key for a given id to dst. Unlike the key
+// returned by SeriesKey, it stays valid when the series file isn't pinned.
+func (f *SeriesFile) AppendSeriesKey(dst []byte, id tsdb.SeriesID) []byte {
+	if id.IsZero() {
+		return dst
+	}
+	p := f.SeriesIDPartition(id)
+	if p == nil {
+		return dst
+	}
+	return p.AppendSeriesKey(dst, id)
+}
+
 // SeriesKeyName returns the measurement name for a series id.
 func (f *SeriesFile) SeriesKeyName(id tsdb.SeriesID) []byte {
 	if id.IsZero() {
@@ -47,25 +47,44 @@ type SeriesPartition struct {
 
 	compacting          bool
 	compactionsDisabled int
+	deletedN            uint64 // series deleted since the segments were compacted
 
-	CompactThreshold    int
-	LargeWriteThreshold int
+	// Keys read from the segments are only valid while the reader holds a pin.
+	// The epoch is advanced whenever segments are replaced by a compaction, and
+	// the replaced segments are retired in the epoch they were replaced in. They
+	// are unmapped once the pins taken in that epoch, or before, are released.
+	pinMu   sync.Mutex
+	epoch   uint64
+	pins    map[uint64]int // pin count by epoch
+	retired []retiredSeriesSegment
+
+	CompactThreshold        int
+	SegmentCompactThreshold int
+	LargeWriteThreshold     int
 
 	tracker *seriesPartitionTracker
 	Logger  *zap.Logger
 }
 
+// retiredSeriesSegment is a segment replaced by a compaction in an epoch.
+type retiredSeriesSegment struct {
+	epoch   uint64
+	segment *SeriesSegment
+}
+
 // NewSeriesPartition returns a new instance of SeriesPartition.
 func NewSeriesPartition(id int, path string) *SeriesPartition {
 	p := &SeriesPartition{
-		id:                  id,
-		path:                path,
-		closing:             make(chan struct{}),
-		CompactThreshold:    DefaultSeriesPartitionCompactThreshold,
-		LargeWriteThreshold: DefaultLargeSeriesWriteThreshold,
-		tracker:             newSeriesPartitionTracker(newSeriesFileMetrics(nil), prometheus.Labels{"series_file_partition": fmt.Sprint(id)}),
-		Logger:              zap.NewNop(),
-		seq:                 uint64(id) + 1,
+		id:                      id,
+		path:                    path,
+		closing:                 make(chan struct{}),
+		CompactThreshold:        DefaultSeriesPartitionCompactThreshold,
+		SegmentCompactThreshold: DefaultSegmentCompactThreshold,
+		LargeWriteThreshold:     DefaultLargeSeriesWriteThreshold,
+		tracker:                 newSeriesPartitionTracker(newSeriesFileMetrics(nil), prometheus.Labels{"series_file_partition": fmt.Sprint(id)}),
+		Logger:                  zap.NewNop(),
+		seq:                     uint64(id) + 1,
+		pins:                    make(map[uint64]int),
 	}
 	p.index = NewSeriesIndex(p.IndexPath())
 	return p
@@ -166,6 +185,15 @@ func (p *SeriesPartition) Close() (err error) {
 	}
 	p.segments = nil
 
+	p.pinMu.Lock()
+	for _, s := range p.retired {
+		if e := s.segment.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	p.retired = nil
+	p.pinMu.Unlock()
+
 	if p.index != nil {
 		if e := p.index.Close(); e != nil && err == nil {
 			err = e
@@ -331,7 +359,7 @@ func (p *SeriesPartition) CreateSeriesListIfNotExists(collection *tsdb.SeriesCol
 		log, logEnd := logger.NewOperation(ctx, p.Logger, "Series partition compaction", "series_partition_compaction", zap.String("path", p.path))
 
 		p.wg.Add(1)
-		p.tracker.IncCompactionsActive()
+		p.tracker.IncCompactionsActive("index")
 		go func() {
 			defer p.wg.Done()
 
@@ -342,7 +370,7 @@ func (p *SeriesPartition) CreateSeriesListIfNotExists(collection *tsdb.SeriesCol
 				p.tracker.IncCompactionErr()
 				log.Error("Series partition compaction failed", zap.Error(err))
 			} else {
-				p.tracker.IncCompactionOK(duration)
+				p.tracker.IncCompactionOK("index", duration)
 			}
 
 			logEnd()
@@ -351,7 +379,7 @@ func (p *SeriesPartition) CreateSeriesListIfNotExists(collection *tsdb.SeriesCol
 			p.mu.Lock()
 			p.compacting = false
 			p.mu.Unlock()
-			p.tracker.DecCompactionsActive()
+			p.tracker.DecCompactionsActive("index")
 
 			// Disk size may have changed due to compaction.
 			p.tracker.SetDiskSize(p.DiskSize())
@@ -406,9 +434,52 @@ func (p *SeriesPartition) DeleteSeriesIDs(ids []tsdb.SeriesID) error {
 	}
 	p.tracker.SubSeries(n)
 
+	// Check if enough series were deleted to compact the segments.
+	p.deletedN += n
+	p.compactSegmentsIfNeeded()
+
 	return nil
 }
 
+// compactSegmentsIfNeeded starts a compaction of the segments in the background
+// if enough series were deleted since they were last compacted. It must be
+// called with p.mu held.
+func (p *SeriesPartition) compactSegmentsIfNeeded() {
+	if !p.compactionsEnabled() || p.compacting || p.SegmentCompactThreshold == 0 || p.deletedN < uint64(p.SegmentCompactThreshold) {
+		return
+	}
+
+	p.compacting = true
+	log, logEnd := logger.NewOperation(context.TODO(), p.Logger, "Series partition segment compaction", "series_partition_segment_compaction", zap.String("path", p.path))
+
+	p.wg.Add(1)
+	p.tracker.IncCompactionsActive("segments")
+	go func() {
+		defer p.wg.Done()
+
+		compactor := NewSeriesPartitionCompactor()
+		compactor.cancel = p.closing
+		duration, err := compactor.CompactSegments(p)
+		if err != nil {
+			p.tracker.IncCompactionErr()
+			log.Error("Series partition segment compaction failed", zap.Error(err))
+		} else {
+			p.tracker.IncCompactionOK("segments", duration)
+		}
+
+		logEnd()
+
+		// Clear compaction flag.
+		p.mu.Lock()
+		p.compacting = false
+		p.mu.Unlock()
+		p.tracker.DecCompactionsActive("segments")
+
+		// Disk size may have changed due to compaction.
+		p.tracker.SetDiskSize(p.DiskSize())
+	}()
+}
+
 // IsDeleted returns true if the ID has been deleted before.
 func (p *SeriesPartition) IsDeleted(id tsdb.SeriesID) bool {
 	p.mu.RLock()
@@ -421,7 +492,63 @@ func (p *SeriesPartition) IsDeleted(id tsdb.SeriesID) bool {
 	return v
 }
 
-// SeriesKey returns the series key for a given id.
+// Pin keeps the keys read from the partition valid until Unpin is called with
+// the returned epoch.
+func (p *SeriesPartition) Pin() uint64 {
+	p.pinMu.Lock()
+	defer p.pinMu.Unlock()
+	p.pins[p.epoch]++
+	return p.epoch
+}
+
+// Unpin releases a pin taken with Pin and unmaps the segments that were only
+// kept for it.
+func (p *SeriesPartition) Unpin(epoch uint64) {
+	p.pinMu.Lock()
+	defer p.pinMu.Unlock()
+	if p.pins[epoch]--; p.pins[epoch] <= 0 {
+		delete(p.pins, epoch)
+	}
+	p.releaseRetired()
+}
+
+// retire retires the segments replaced by a compaction and advances the epoch.
+func (p *SeriesPartition) retire(segments []*SeriesSegment) {
+	p.pinMu.Lock()
+	defer p.pinMu.Unlock()
+	for _, segment := range segments {
+		p.retired = append(p.retired, retiredSeriesSegment{epoch: p.epoch, segment: segment})
+	}
+	p.epoch++
+	p.releaseRetired()
+}
+
+// releaseRetired unmaps the retired segments no pin can read keys from. It
+// must be called with p.pinMu held.
+func (p *SeriesPartition) releaseRetired() {
+	oldest := p.epoch
+	for epoch := range p.pins {
+		if epoch < oldest {
+			oldest = epoch
+		}
+	}
+
+	retired := p.retired[:0]
+	for _, s := range p.retired {
+		if s.epoch >= oldest {
+			retired = append(retired, s)
+			continue
+		}
+		if err := s.segment.Close(); err != nil {
+			p.Logger.Error("Unable to close retired series segment", zap.String("path", s.segment.Path()), zap.Error(err))
+		}
+	}
+	p.retired = retired
+}
+
+// SeriesKey returns the series key for a given id. The key is only valid while
+// the partition is pinned, as the segment it is read from may be replaced by a
+// compaction.
 func (p *SeriesPartition) SeriesKey(id tsdb.SeriesID) []byte {
 	if id.IsZero() {
 		return nil
@@ -436,6 +563,19 @@ func (p *SeriesPartition) SeriesKey(id tsdb.SeriesID) []byte {
 	return key
 }
 
+// AppendSeriesKey appends the series key for a given id to dst.
+func (p *SeriesPartition) AppendSeriesKey(dst []byte, id tsdb.SeriesID) []byte {
+	if id.IsZero() {
+		return dst
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return dst
+	}
+	return append(dst, p.seriesKeyByOffset(p.index.FindOffsetByID(id))...)
+}
+
 // Series returns the parsed series name and tags for an offset.
 func (p *SeriesPartition) Series(id tsdb.SeriesID) ([]byte, models.Tags) {
 	key := p.SeriesKey(id)
@@ -503,6 +643,12 @@ func (p *SeriesPartition) EnableCompactions() {
 		return
 	}
 	p.compactionsDisabled--
+
+	// Deletes usually disable compactions, so check if the segments
+	// can be compacted now that they are done.
+	if !p.closed {
+		p.compactSegmentsIfNeeded()
+	}
 }
 
 func (p *SeriesPartition) compactionsEnabled() bool {
@@ -682,39 +828,39 @@ func (t *seriesPartitionTracker) SetSegments(n uint64) {
 }
 
 // IncCompactionsActive increments the number of active compactions for the
-// components of a partition (index and segments).
-func (t *seriesPartitionTracker) IncCompactionsActive() {
+// component of a partition (index or segments).
+func (t *seriesPartitionTracker) IncCompactionsActive(component string) {
 	if !t.enabled {
 		return
 	}
 
 	labels := t.Labels()
-	labels["component"] = "index" // TODO(edd): when we add segment compactions we will add a new label value.
+	labels["component"] = component
 	t.metrics.CompactionsActive.With(labels).Inc()
 }
 
 // DecCompactionsActive decrements the number of active compactions for the
-// components of a partition (index and segments).
-func (t *seriesPartitionTracker) DecCompactionsActive() {
+// component of a partition (index or segments).
+func (t *seriesPartitionTracker) DecCompactionsActive(component string) {
 	if !t.enabled {
 		return
 	}
 
 	labels := t.Labels()
-	labels["component"] = "index" // TODO(edd): when we add segment compactions we will add a new label value.
+	labels["component"] = component
 	t.metrics.CompactionsActive.With(labels).Dec()
 }
 
 // incCompactions increments the number of compactions for the partition.
 // Callers should use IncCompactionOK and IncCompactionErr.
-func (t *seriesPartitionTracker) incCompactions(status string, duration time.Duration) {
+func (t *seriesPartitionTracker) incCompactions(status, component string, duration time.Duration) {
 	if !t.enabled {
 		return
 	}
 
 	if duration > 0 {
 		labels := t.Labels()
-		labels["component"] = "index"
+		labels["component"] = component
 		t.metrics.CompactionDuration.With(labels).Observe(duration.Seconds())
 	}
 
@@ -723,13 +869,14 @@ func (t *seriesPartitionTracker) incCompactions(status string, duration time.Dur
 	t.metrics.Compactions.With(labels).Inc()
 }
 
-// IncCompactionOK increments the number of successful compactions for the partition.
-func (t *seriesPartitionTracker) IncCompactionOK(duration time.Duration) {
-	t.incCompactions("ok", duration)
+// IncCompactionOK increments the number of successful compactions of the
+// component of the partition.
+func (t *seriesPartitionTracker) IncCompactionOK(component string, duration time.Duration) {
+	t.incCompactions("ok", component, duration)
 }
 
 // IncCompactionErr increments the number of failed compactions for the partition.
-func (t *seriesPartitionTracker) IncCompactionErr() { t.incCompactions("error", 0) }
+func (t *seriesPartitionTracker) IncCompactionErr() { t.incCompactions("error", "", 0) }
 
 // SeriesPartitionCompactor represents an object reindexes a series partition and optionally compacts segments.
 type SeriesPartitionCompactor struct {
@@ -785,6 +932,201 @@ func (c *SeriesPartitionCompactor) Compact(p *SeriesPartition) (time.Duration, e
 	return duration, nil
 }
 
+// CompactSegments rewrites the segments of the series partition, other than the
+// active segment, without the entries of deleted series and rebuilds the index
+// over the new segments. Writes are only blocked while the new segments and
+// index are swapped in.
+//
+// Keys read from the replaced segments may still be in use, so the replaced
+// segments stay mapped until the pins taken before they were replaced are
+// released.
+func (c *SeriesPartitionCompactor) CompactSegments(p *SeriesPartition) (time.Duration, error) {
+	// Snapshot the segments and index. The segments other than the active one
+	// are never written to, and the entries written to the active one while
+	// compacting are replayed at the end under lock.
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return 0, ErrSeriesPartitionClosed
+	}
+	segments := CloneSeriesSegments(p.segments)
+	index := p.index.Clone()
+	seriesN := p.index.Count()
+	deletedN := p.deletedN
+	p.mu.RUnlock()
+
+	now := time.Now()
+
+	// Rewrite the segments with deleted entries to temporary locations.
+	compacted := make(map[uint16]*SeriesSegment)
+	defer func() {
+		for _, segment := range compacted {
+			segment.Close()
+			os.Remove(segment.Path())
+		}
+	}()
+	for i, segment := range segments[:len(segments)-1] {
+		dst, err := c.compactSegmentTo(segment, index, segment.Path()+".compacting")
+		if err != nil {
+			return 0, err
+		} else if dst == nil {
+			continue
+		}
+		compacted[segment.ID()] = dst
+		segments[i] = dst
+	}
+
+	// Compact index over the new segments to a temporary location.
+	indexPath := index.path + ".compacting"
+	if len(compacted) > 0 {
+		if err := os.Remove(indexPath); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		} else if err := c.compactIndexTo(index, seriesN, segments, indexPath); err != nil {
+			return 0, err
+		}
+	}
+	duration := time.Since(now)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return 0, ErrSeriesPartitionClosed
+	}
+	p.deletedN -= deletedN
+	if len(compacted) == 0 {
+		return duration, nil
+	}
+
+	var retired []*SeriesSegment
+
+	// Remove the index first, so the index is rebuilt from the segments if the
+	// new segments are only partially swapped in. Segments are swapped in order,
+	// so tombstones are never removed before the entries they delete.
+	if err := p.index.Close(); err != nil {
+		return 0, err
+	} else if err := os.Remove(index.path); err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	for i, segment := range p.segments {
+		dst := compacted[segment.ID()]
+		if dst == nil {
+			continue
+		}
+		if err := fs.RenameFileWithReplacement(dst.Path(), segment.Path()); err != nil {
+			p.retire(retired)
+			return 0, err
+		}
+		dst.path = segment.Path()
+		delete(compacted, segment.ID())
+
+		p.segments[i] = dst
+		retired = append(retired, segment)
+	}
+	p.retire(retired)
+
+	// Reopen index with new file and replay new entries.
+	if err := fs.RenameFile(indexPath, index.path); err != nil {
+		return 0, err
+	} else if err := p.index.Open(); err != nil {
+		return 0, err
+	} else if err := p.index.Recover(p.segments); err != nil {
+		return 0, err
+	}
+	return duration, nil
+}
+
+// compactSegmentTo rewrites segment to path without the entries of the series
+// deleted from index, returning the new segment or nil if it has no entries to
+// remove. If the largest series id of the segment was deleted its entries are
+// kept, so the id isn't reused after reopening the partition.
+func (c *SeriesPartitionCompactor) compactSegmentTo(segment *SeriesSegment, index *SeriesIndex, path string) (_ *SeriesSegment, err error) {
+	maxID := segment.MaxSeriesID()
+
+	// Count the entries to remove. A deleted largest id needs a rewrite if its
+	// tombstone isn't in the segment.
+	var removeN int
+	maxDeleted, maxTombstoned := !maxID.IsZero() && index.IsDeleted(maxID), false
+	segment.ForEachEntry(func(flag uint8, id tsdb.SeriesIDTyped, _ int64, _ []byte) error {
+		untypedID := id.SeriesID()
+		if untypedID == maxID {
+			maxTombstoned = maxTombstoned || flag == SeriesEntryTombstoneFlag
+		} else if flag == SeriesEntryTombstoneFlag || index.IsDeleted(untypedID) {
+			removeN++
+		}
+		return nil
+	})
+	if maxDeleted && !maxTombstoned {
+		removeN++
+	}
+	if removeN == 0 {
+		return nil, nil
+	}
+
+	// Remove leftovers of an interrupted compaction.
+	for _, path := range []string{path, path + ".initializing"} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	dst, err := CreateSeriesSegment(segment.ID(), path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			dst.Close()
+			os.Remove(path)
+		}
+	}()
+
+	if err := dst.InitForWrite(); err != nil {
+		return nil, err
+	}
+
+	var buf []byte
+	var entryN int
+	if err := segment.ForEachEntry(func(flag uint8, id tsdb.SeriesIDTyped, _ int64, key []byte) error {
+		// Check for cancellation periodically.
+		if entryN++; entryN%1000 == 0 {
+			select {
+			case <-c.cancel:
+				return ErrSeriesPartitionCompactionCancelled
+			default:
+			}
+		}
+
+		// Tombstones are only needed for the entries that are kept.
+		untypedID := id.SeriesID()
+		if flag == SeriesEntryTombstoneFlag {
+			return nil
+		} else if index.IsDeleted(untypedID) {
+			if untypedID != maxID {
+				return nil
+			}
+			buf = AppendSeriesEntry(buf[:0], flag, id, key)
+			buf = AppendSeriesEntry(buf, SeriesEntryTombstoneFlag, untypedID.WithType(models.Empty), nil)
+		} else {
+			buf = AppendSeriesEntry(buf[:0], flag, id, key)
+		}
+		_, err := dst.WriteLogEntry(buf)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	// Sync the new segment, keeping it mapped for the new index.
+	if err := dst.Flush(); err != nil {
+		return nil, err
+	} else if err := dst.file.Sync(); err != nil {
+		return nil, err
+	} else if err := dst.CloseForWrite(); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
 func (c *SeriesPartitionCompactor) compactIndexTo(index *SeriesIndex, seriesN uint64, segments []*SeriesSegment, path string) error {
 	hdr := NewSeriesIndexHeader()
 	hdr.Count = seriesN
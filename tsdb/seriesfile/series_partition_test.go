@@ -1,6 +1,7 @@
 package seriesfile_test
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -13,6 +14,162 @@ import (
 	"github.com/influxdata/influxdb/v2/tsdb/seriesfile"
 )
 
+func TestSeriesPartitionCompactor_CompactSegments(t *testing.T) {
+	p := MustOpenSeriesPartition()
+	defer p.Close()
+	p.SegmentCompactThreshold = 0
+
+	// Create enough series to fill the first segment.
+	const n = 5000
+	var collection tsdb.SeriesCollection
+	for i := 0; i < n; i++ {
+		collection.Names = append(collection.Names, []byte("cpu"))
+		collection.Tags = append(collection.Tags, models.Tags{
+			{Key: []byte("host"), Value: []byte(fmt.Sprintf("%d", i))},
+			{Key: []byte("padding"), Value: bytes.Repeat([]byte("x"), 1000)},
+		})
+		collection.Types = append(collection.Types, models.Integer)
+	}
+	collection.SeriesKeys = seriesfile.GenerateSeriesKeys(collection.Names, collection.Tags)
+	collection.SeriesIDs = make([]tsdb.SeriesID, len(collection.SeriesKeys))
+	if err := p.CreateSeriesListIfNotExists(&collection, make([]int, n)); err != nil {
+		t.Fatal(err)
+	} else if got := len(p.Segments()); got < 2 {
+		t.Fatalf("got %d segments, want at least 2", got)
+	}
+	ids := append([]tsdb.SeriesID(nil), collection.SeriesIDs...)
+
+	// Delete every other series and the last series of the first segment.
+	var deleted []tsdb.SeriesID
+	maxID := p.Segments()[0].MaxSeriesID()
+	for i, id := range ids {
+		if i%2 == 0 || id == maxID {
+			deleted = append(deleted, id)
+		}
+	}
+	if err := p.DeleteSeriesIDs(deleted); err != nil {
+		t.Fatal(err)
+	}
+
+	size := p.Segments()[0].Size()
+	if _, err := seriesfile.NewSeriesPartitionCompactor().CompactSegments(p.SeriesPartition); err != nil {
+		t.Fatal(err)
+	}
+
+	verify := func(p *seriesfile.SeriesPartition) {
+		t.Helper()
+		for i, id := range ids {
+			key := collection.SeriesKeys[i]
+			if p.IsDeleted(id) != (i%2 == 0 || id == maxID) {
+				t.Fatalf("IsDeleted(%d)=%v", id, p.IsDeleted(id))
+			} else if p.IsDeleted(id) {
+				if got := p.FindIDBySeriesKey(key); !got.IsZero() {
+					t.Fatalf("found deleted series %d", got)
+				}
+				continue
+			}
+			if got := p.FindIDBySeriesKey(key); got != id {
+				t.Fatalf("FindIDBySeriesKey()=%d, want %d", got, id)
+			} else if got := p.SeriesKey(id); !bytes.Equal(got, key) {
+				t.Fatalf("SeriesKey(%d)=%q, want %q", id, got, key)
+			}
+		}
+		if got, want := p.SeriesCount(), uint64(n-len(deleted)); got != want {
+			t.Fatalf("SeriesCount()=%d, want %d", got, want)
+		}
+	}
+	verify(p.SeriesPartition)
+
+	// Reopen the partition, whose segments must be smaller and whose ids must
+	// not be reused.
+	if err := p.SeriesPartition.Close(); err != nil {
+		t.Fatal(err)
+	}
+	p.SeriesPartition = seriesfile.NewSeriesPartition(0, p.Path())
+	if err := p.Open(); err != nil {
+		t.Fatal(err)
+	}
+	verify(p.SeriesPartition)
+
+	if err := p.Segments()[0].InitForWrite(); err != nil {
+		t.Fatal(err)
+	} else if got := p.Segments()[0].Size(); got >= size {
+		t.Fatalf("segment size %d, want less than %d", got, size)
+	}
+	p.Segments()[0].CloseForWrite()
+
+	collection = tsdb.SeriesCollection{
+		Names: [][]byte{[]byte("cpu")},
+		Tags:  []models.Tags{{{Key: []byte("host"), Value: []byte("new")}}},
+		Types: []models.FieldType{models.Integer},
+	}
+	collection.SeriesKeys = seriesfile.GenerateSeriesKeys(collection.Names, collection.Tags)
+	collection.SeriesIDs = make([]tsdb.SeriesID, 1)
+	if err := p.CreateSeriesListIfNotExists(&collection, []int{0}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range ids {
+		if !collection.SeriesIDs[0].Greater(id) {
+			t.Fatalf("new series id %d reuses or precedes %d", collection.SeriesIDs[0], id)
+		}
+	}
+}
+
+func TestSeriesPartitionCompactor_CompactSegments_Pinned(t *testing.T) {
+	p := MustOpenSeriesPartition()
+	defer p.Close()
+	p.SegmentCompactThreshold = 0
+
+	// Create enough series to fill the first segment and delete every other one.
+	const n = 5000
+	var collection tsdb.SeriesCollection
+	for i := 0; i < n; i++ {
+		collection.Names = append(collection.Names, []byte("cpu"))
+		collection.Tags = append(collection.Tags, models.Tags{
+			{Key: []byte("host"), Value: []byte(fmt.Sprintf("%d", i))},
+			{Key: []byte("padding"), Value: bytes.Repeat([]byte("x"), 1000)},
+		})
+		collection.Types = append(collection.Types, models.Integer)
+	}
+	collection.SeriesKeys = seriesfile.GenerateSeriesKeys(collection.Names, collection.Tags)
+	collection.SeriesIDs = make([]tsdb.SeriesID, len(collection.SeriesKeys))
+	if err := p.CreateSeriesListIfNotExists(&collection, make([]int, n)); err != nil {
+		t.Fatal(err)
+	}
+	var deleted []tsdb.SeriesID
+	for i, id := range collection.SeriesIDs {
+		if i%2 == 0 {
+			deleted = append(deleted, id)
+		}
+	}
+	if err := p.DeleteSeriesIDs(deleted); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys read while pinned stay valid after their segment is replaced.
+	epoch := p.Pin()
+	id := collection.SeriesIDs[1]
+	key := p.SeriesKey(id)
+	segment := p.Segments()[0]
+	if _, err := seriesfile.NewSeriesPartitionCompactor().CompactSegments(p.SeriesPartition); err != nil {
+		t.Fatal(err)
+	} else if p.Segments()[0] == segment {
+		t.Fatal("expected first segment to be replaced")
+	} else if segment.Data() == nil {
+		t.Fatal("expected replaced segment to be mapped while pinned")
+	} else if !bytes.Equal(key, collection.SeriesKeys[1]) {
+		t.Fatalf("SeriesKey(%d)=%q, want %q", id, key, collection.SeriesKeys[1])
+	}
+
+	// Pins taken after the compaction don't keep the segment mapped.
+	later := p.Pin()
+	p.Unpin(epoch)
+	if segment.Data() != nil {
+		t.Fatal("expected replaced segment to be unmapped once unpinned")
+	}
+	p.Unpin(later)
+}
+
 func BenchmarkSeriesPartition_CreateSeriesListIfNotExists(b *testing.B) {
 	for _, n := range []int{1000, 10000, 100000, 1000000} {
 		b.Run(strconv.Itoa(n), func(b *testing.B) {
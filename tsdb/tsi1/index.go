@@ -111,6 +111,7 @@ type Index struct {
 	defaultLabels prometheus.Labels
 
 	tagValueCache    *TagValueSeriesIDCache
+	sketches         *seriesSketches
 	partitionMetrics *partitionMetrics // Maintain a single set of partition metrics to be shared by partition.
 	metricsEnabled   bool
 
@@ -144,6 +145,7 @@ func (i *Index) UniqueReferenceID() uintptr {
 func NewIndex(sfile *seriesfile.SeriesFile, c Config, options ...IndexOption) *Index {
 	idx := &Index{
 		tagValueCache:    NewTagValueSeriesIDCache(c.SeriesIDSetCacheSize),
+		sketches:         newSeriesSketches(),
 		partitionMetrics: newPartitionMetrics(nil),
 		metricsEnabled:   true,
 		maxLogFileSize:   int64(c.MaxIndexLogFileSize),
@@ -362,6 +364,7 @@ func (i *Index) Close() error {
 			return err
 		}
 	}
+	i.sketches.Reset()
 
 	return nil
 }
@@ -615,8 +618,9 @@ func (i *Index) DropMeasurement(name []byte) error {
 		}()
 	}
 
-	// Remove any cached bitmaps for the measurement.
+	// Remove any cached bitmaps and sketches for the measurement.
 	i.tagValueCache.DeleteMeasurement(name)
+	i.sketches.DropName(name)
 
 	// Check for error
 	for i := 0; i < cap(errC); i++ {
@@ -677,6 +681,9 @@ func (i *Index) CreateSeriesListIfNotExists(collection *tsdb.SeriesCollection) e
 					continue
 				}
 
+				// The sketches of the names of new series need to be updated.
+				i.sketches.AddSeries(pCollections[idx].Names, pCollections[idx].Tags, ids)
+
 				// Some cached bitset results may need to be updated.
 				i.tagValueCache.RLock()
 				for j, id := range ids {
@@ -747,6 +754,7 @@ func (i *Index) DropSeries(items []DropSeriesItem, cascade bool) error {
 	if err := g.Wait(); err != nil {
 		return err
 	}
+	i.sketches.DropSeries(items)
 
 	if !cascade {
 		return nil
@@ -1020,6 +1028,10 @@ func (i *Index) TagSets(name []byte, opt query.IteratorOptions) ([]*query.TagSet
 	// measurementSeriesByExprIterator filters deleted series IDs; no need to
 	// do so here.
 
+	// The series keys are parsed in place.
+	release := i.sfile.Pin()
+	defer release()
+
 	var dims []string
 	if len(opt.Dimensions) > 0 {
 		dims = make([]string, len(opt.Dimensions))
@@ -499,6 +499,61 @@ func TestIndex_MeasurementCardinalityStats(t *testing.T) {
 }
 
 // Ensure index keeps the correct set of series even with concurrent compactions.
+func TestIndex_CardinalityEstimates(t *testing.T) {
+	idx := MustOpenIndex(1, tsi1.NewConfig())
+	defer idx.Close()
+
+	series := func(m, host string) Series {
+		return Series{Name: []byte("bucket"), Tags: models.NewTags(map[string]string{"\x00": m, "host": host})}
+	}
+	if err := idx.CreateSeriesSliceIfNotExists([]Series{series("cpu", "a"), series("cpu", "b"), series("mem", "a")}); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(t *testing.T, n int64, exp map[string]int64) {
+		t.Helper()
+		if got, err := idx.SeriesCardinalityEstimate([]byte("bucket")); err != nil {
+			t.Fatal(err)
+		} else if got != n {
+			t.Fatalf("unexpected series estimate: got %d, exp %d", got, n)
+		}
+		if got, err := idx.MeasurementCardinalityEstimates([]byte("bucket")); err != nil {
+			t.Fatal(err)
+		} else if diff := cmp.Diff(got, exp); diff != "" {
+			t.Fatal(diff)
+		}
+		for m, n := range exp {
+			if got, err := idx.MeasurementCardinalityEstimate([]byte("bucket"), []byte(m)); err != nil {
+				t.Fatal(err)
+			} else if got != n {
+				t.Fatalf("unexpected estimate of %s: got %d, exp %d", m, got, n)
+			}
+		}
+	}
+
+	// The sketches are built from the index.
+	check(t, 3, map[string]int64{"cpu": 2, "mem": 1})
+
+	// The sketches follow new and dropped series.
+	if err := idx.CreateSeriesSliceIfNotExists([]Series{series("cpu", "a"), series("disk", "a")}); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 4, map[string]int64{"cpu": 2, "disk": 1, "mem": 1})
+
+	s := series("mem", "a")
+	id := idx.SeriesFile.SeriesID(s.Name, s.Tags, nil)
+	if err := idx.DropSeries([]tsi1.DropSeriesItem{{SeriesID: id, Key: models.MakeKey(s.Name, s.Tags)}}, false); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 3, map[string]int64{"cpu": 2, "disk": 1})
+
+	// The sketches are rebuilt after the index is reopened.
+	if err := idx.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	check(t, 3, map[string]int64{"cpu": 2, "disk": 1})
+}
+
 func TestIndex_CompactionConsistency(t *testing.T) {
 	t.Skip("TODO: flaky test: https://github.com/influxdata/influxdb/issues/13755")
 	t.Parallel()
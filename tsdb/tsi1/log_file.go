@@ -760,7 +760,8 @@ func (f *LogFile) execSeriesEntry(e *LogEntry) {
 		}
 		seriesKey = seriesfile.AppendSeriesKey(f.keyBuf[:0], e.name, e.tags)
 	} else {
-		seriesKey = f.sfile.SeriesKey(e.SeriesID)
+		// The names and tags of the key are kept by the log file.
+		seriesKey = f.sfile.AppendSeriesKey(nil, e.SeriesID)
 	}
 
 	// Series keys can be removed if the series has been deleted from
@@ -0,0 +1,206 @@
+package tsi1
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/pkg/hll"
+	"github.com/influxdata/influxdb/v2/tsdb"
+)
+
+// measurementSketchPrecision is the precision of the sketches of the values of
+// the measurement tag. It is lower than that of the sketches of names, as a
+// name may have many measurements.
+const measurementSketchPrecision = 12
+
+// seriesSketches estimates the number of series of each measurement name, and
+// of each value of the measurement tag within the name, with HyperLogLog
+// sketches of the ids of the created and of the dropped series.
+//
+// The sketches of a name are built from the index the first time they are
+// needed and are kept up to date as series are created and dropped. Adding to
+// a sketch is idempotent, so series created while the sketches are built are
+// never missed.
+type seriesSketches struct {
+	mu    sync.Mutex
+	names map[string]*nameSketches
+}
+
+// nameSketches are the sketches of a measurement name and of each value of its
+// measurement tag.
+type nameSketches struct {
+	sketchPair
+	measurements map[string]*sketchPair
+}
+
+// sketchPair holds a sketch of the created series and one of the dropped series.
+type sketchPair struct {
+	series, tombstones *hll.Plus
+}
+
+func newSketchPair(p uint8) sketchPair {
+	series, _ := hll.NewPlus(p)
+	tombstones, _ := hll.NewPlus(p)
+	return sketchPair{series: series, tombstones: tombstones}
+}
+
+// count returns the estimated number of series that were created and not dropped.
+func (s *sketchPair) count() int64 {
+	n := int64(s.series.Count()) - int64(s.tombstones.Count())
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+func newSeriesSketches() *seriesSketches {
+	return &seriesSketches{names: make(map[string]*nameSketches)}
+}
+
+// measurement returns the sketches of the measurement m, creating them if needed.
+func (s *nameSketches) measurement(m []byte) *sketchPair {
+	p := s.measurements[string(m)]
+	if p == nil {
+		pair := newSketchPair(measurementSketchPrecision)
+		p = &pair
+		s.measurements[string(m)] = p
+	}
+	return p
+}
+
+// add adds the series id to the sketches of its name and measurement, and to
+// the tombstone sketches if the series was dropped.
+func (s *nameSketches) add(tags models.Tags, id tsdb.SeriesID, dropped bool) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], id.RawID())
+
+	m := s.measurement(tags.Get(models.MeasurementTagKeyBytes))
+	if dropped {
+		s.tombstones.Add(buf[:])
+		m.tombstones.Add(buf[:])
+		return
+	}
+	s.series.Add(buf[:])
+	m.series.Add(buf[:])
+}
+
+// AddSeries adds the new series with the given names, tags and ids to the
+// sketches already built. Zero ids are ignored.
+func (s *seriesSketches) AddSeries(names [][]byte, tags []models.Tags, ids []tsdb.SeriesID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for j, id := range ids {
+		if id.IsZero() {
+			continue
+		}
+		if ns := s.names[string(names[j])]; ns != nil {
+			ns.add(tags[j], id, false)
+		}
+	}
+}
+
+// DropSeries adds the dropped series to the tombstone sketches already built.
+func (s *seriesSketches) DropSeries(items []DropSeriesItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range items {
+		name, tags := models.ParseKeyBytes(item.Key)
+		if ns := s.names[string(name)]; ns != nil {
+			ns.add(tags, item.SeriesID, true)
+		}
+	}
+}
+
+// DropName removes the sketches of name.
+func (s *seriesSketches) DropName(name []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.names, string(name))
+}
+
+// Reset removes the sketches of all names.
+func (s *seriesSketches) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = make(map[string]*nameSketches)
+}
+
+// sketches returns the sketches of name, building them from the index if
+// needed. It must be called with s.mu held.
+func (s *seriesSketches) sketches(i *Index, name []byte) (*nameSketches, error) {
+	if ns := s.names[string(name)]; ns != nil {
+		return ns, nil
+	}
+
+	ns := &nameSketches{sketchPair: newSketchPair(hll.DefaultPrecision), measurements: make(map[string]*sketchPair)}
+	itr, err := i.MeasurementSeriesIDIterator(name)
+	if err != nil {
+		return nil, err
+	} else if itr != nil {
+		defer itr.Close()
+		release := i.sfile.Pin()
+		defer release()
+		for {
+			elem, err := itr.Next()
+			if err != nil {
+				return nil, err
+			} else if elem.SeriesID.IsZero() {
+				break
+			}
+			_, tags := i.sfile.Series(elem.SeriesID)
+			ns.add(tags, elem.SeriesID, false)
+		}
+	}
+	s.names[string(name)] = ns
+	return ns, nil
+}
+
+// SeriesCardinalityEstimate returns an estimate of the number of series of the
+// measurement name.
+func (i *Index) SeriesCardinalityEstimate(name []byte) (int64, error) {
+	i.sketches.mu.Lock()
+	defer i.sketches.mu.Unlock()
+
+	ns, err := i.sketches.sketches(i, name)
+	if err != nil {
+		return 0, err
+	}
+	return ns.count(), nil
+}
+
+// MeasurementCardinalityEstimate returns an estimate of the number of series
+// of the measurement name whose measurement tag is m.
+func (i *Index) MeasurementCardinalityEstimate(name, m []byte) (int64, error) {
+	i.sketches.mu.Lock()
+	defer i.sketches.mu.Unlock()
+
+	ns, err := i.sketches.sketches(i, name)
+	if err != nil {
+		return 0, err
+	}
+	if p := ns.measurements[string(m)]; p != nil {
+		return p.count(), nil
+	}
+	return 0, nil
+}
+
+// MeasurementCardinalityEstimates returns an estimate of the number of series
+// of the measurement name for each value of its measurement tag. Values without
+// series are left out.
+func (i *Index) MeasurementCardinalityEstimates(name []byte) (map[string]int64, error) {
+	i.sketches.mu.Lock()
+	defer i.sketches.mu.Unlock()
+
+	ns, err := i.sketches.sketches(i, name)
+	if err != nil {
+		return nil, err
+	}
+	estimates := make(map[string]int64, len(ns.measurements))
+	for m, p := range ns.measurements {
+		if n := p.count(); n > 0 {
+			estimates[m] = n
+		}
+	}
+	return estimates, nil
+}
@@ -7,6 +7,7 @@
 package tsm1
 
 import (
+	"math"
 	"sort"
 
 	"github.com/influxdata/influxdb/v2/tsdb/cursors"
@@ -30,6 +31,9 @@ type floatArrayAscendingCursor struct {
 	end   int64
 	res   *cursors.FloatArray
 	stats cursors.CursorStats
+
+	// agg accumulates the blocks answered from their statistics by Aggregate.
+	agg *cursors.FloatArrayAggregate
 }
 
 func newFloatArrayAscendingCursor() *floatArrayAscendingCursor {
@@ -154,11 +158,58 @@ func (c *floatArrayAscendingCursor) Next() *cursors.FloatArray {
 
 func (c *floatArrayAscendingCursor) nextTSM() *cursors.FloatArray {
 	c.tsm.keyCursor.Next()
+	if c.agg != nil {
+		c.aggregateBlocks()
+	}
 	c.tsm.values = c.readArrayBlock()
 	c.tsm.pos = 0
 	return c.tsm.values
 }
 
+// Aggregate returns the count, sum, min and max of the remaining values of the
+// cursor. Blocks that lie entirely within the range of the cursor and overlap
+// no other block or cached value are answered from their statistics without
+// being decoded.
+func (c *floatArrayAscendingCursor) Aggregate() cursors.FloatArrayAggregate {
+	var blocks, values cursors.FloatArrayAggregate
+	if c.end != math.MinInt64 {
+		c.agg = &blocks
+	}
+	for a := c.Next(); a.Len() > 0; a = c.Next() {
+		values.AddArray(a)
+	}
+	c.agg = nil
+
+	if values.Count == 0 || (blocks.Count > 0 && blocks.Timestamp < values.Timestamp) {
+		blocks.Merge(values)
+		return blocks
+	}
+	values.Merge(blocks)
+	return values
+}
+
+// aggregateBlocks adds the statistics of the next blocks to c.agg for as long
+// as they can be answered without being decoded.
+func (c *floatArrayAscendingCursor) aggregateBlocks() {
+	for {
+		tr, s, ok := c.tsm.keyCursor.NextBlockStats(math.MinInt64, c.end-1, c.cache.values)
+		if !ok {
+			return
+		}
+
+		ts := c.agg.Timestamp
+		if c.agg.Count == 0 || tr.Min < ts {
+			ts = tr.Min
+		}
+		min, max, sum := s.FloatValues()
+		c.agg.Merge(cursors.FloatArrayAggregate{Count: s.Count, Sum: sum, Min: min, Max: max})
+		c.agg.Timestamp = ts
+
+		c.stats.ScannedValues += int(s.Count)
+		c.stats.ScannedBytes += int(s.Count) * 8
+	}
+}
+
 func (c *floatArrayAscendingCursor) readArrayBlock() *cursors.FloatArray {
 	values, _ := c.tsm.keyCursor.ReadFloatArrayBlock(c.tsm.buf)
 	return values
@@ -180,6 +231,9 @@ type floatArrayDescendingCursor struct {
 	end   int64
 	res   *cursors.FloatArray
 	stats cursors.CursorStats
+
+	// agg accumulates the blocks answered from their statistics by Aggregate.
+	agg *cursors.FloatArrayAggregate
 }
 
 func newFloatArrayDescendingCursor() *floatArrayDescendingCursor {
@@ -309,11 +363,58 @@ func (c *floatArrayDescendingCursor) Next() *cursors.FloatArray {
 
 func (c *floatArrayDescendingCursor) nextTSM() *cursors.FloatArray {
 	c.tsm.keyCursor.Next()
+	if c.agg != nil {
+		c.aggregateBlocks()
+	}
 	c.tsm.values = c.readArrayBlock()
 	c.tsm.pos = len(c.tsm.values.Timestamps) - 1
 	return c.tsm.values
 }
 
+// Aggregate returns the count, sum, min and max of the remaining values of the
+// cursor. Blocks that lie entirely within the range of the cursor and overlap
+// no other block or cached value are answered from their statistics without
+// being decoded.
+func (c *floatArrayDescendingCursor) Aggregate() cursors.FloatArrayAggregate {
+	var blocks, values cursors.FloatArrayAggregate
+	if c.end != math.MaxInt64 {
+		c.agg = &blocks
+	}
+	for a := c.Next(); a.Len() > 0; a = c.Next() {
+		values.AddArray(a)
+	}
+	c.agg = nil
+
+	if values.Count == 0 || (blocks.Count > 0 && blocks.Timestamp > values.Timestamp) {
+		blocks.Merge(values)
+		return blocks
+	}
+	values.Merge(blocks)
+	return values
+}
+
+// aggregateBlocks adds the statistics of the next blocks to c.agg for as long
+// as they can be answered without being decoded.
+func (c *floatArrayDescendingCursor) aggregateBlocks() {
+	for {
+		tr, s, ok := c.tsm.keyCursor.NextBlockStats(c.end+1, math.MaxInt64, c.cache.values)
+		if !ok {
+			return
+		}
+
+		ts := c.agg.Timestamp
+		if c.agg.Count == 0 || tr.Max > ts {
+			ts = tr.Max
+		}
+		min, max, sum := s.FloatValues()
+		c.agg.Merge(cursors.FloatArrayAggregate{Count: s.Count, Sum: sum, Min: min, Max: max})
+		c.agg.Timestamp = ts
+
+		c.stats.ScannedValues += int(s.Count)
+		c.stats.ScannedBytes += int(s.Count) * 8
+	}
+}
+
 func (c *floatArrayDescendingCursor) readArrayBlock() *cursors.FloatArray {
 	values, _ := c.tsm.keyCursor.ReadFloatArrayBlock(c.tsm.buf)
 
@@ -340,6 +441,9 @@ type integerArrayAscendingCursor struct {
 	end   int64
 	res   *cursors.IntegerArray
 	stats cursors.CursorStats
+
+	// agg accumulates the blocks answered from their statistics by Aggregate.
+	agg *cursors.IntegerArrayAggregate
 }
 
 func newIntegerArrayAscendingCursor() *integerArrayAscendingCursor {
@@ -464,11 +568,58 @@ func (c *integerArrayAscendingCursor) Next() *cursors.IntegerArray {
 
 func (c *integerArrayAscendingCursor) nextTSM() *cursors.IntegerArray {
 	c.tsm.keyCursor.Next()
+	if c.agg != nil {
+		c.aggregateBlocks()
+	}
 	c.tsm.values = c.readArrayBlock()
 	c.tsm.pos = 0
 	return c.tsm.values
 }
 
+// Aggregate returns the count, sum, min and max of the remaining values of the
+// cursor. Blocks that lie entirely within the range of the cursor and overlap
+// no other block or cached value are answered from their statistics without
+// being decoded.
+func (c *integerArrayAscendingCursor) Aggregate() cursors.IntegerArrayAggregate {
+	var blocks, values cursors.IntegerArrayAggregate
+	if c.end != math.MinInt64 {
+		c.agg = &blocks
+	}
+	for a := c.Next(); a.Len() > 0; a = c.Next() {
+		values.AddArray(a)
+	}
+	c.agg = nil
+
+	if values.Count == 0 || (blocks.Count > 0 && blocks.Timestamp < values.Timestamp) {
+		blocks.Merge(values)
+		return blocks
+	}
+	values.Merge(blocks)
+	return values
+}
+
+// aggregateBlocks adds the statistics of the next blocks to c.agg for as long
+// as they can be answered without being decoded.
+func (c *integerArrayAscendingCursor) aggregateBlocks() {
+	for {
+		tr, s, ok := c.tsm.keyCursor.NextBlockStats(math.MinInt64, c.end-1, c.cache.values)
+		if !ok {
+			return
+		}
+
+		ts := c.agg.Timestamp
+		if c.agg.Count == 0 || tr.Min < ts {
+			ts = tr.Min
+		}
+		min, max, sum := s.IntegerValues()
+		c.agg.Merge(cursors.IntegerArrayAggregate{Count: s.Count, Sum: sum, Min: min, Max: max})
+		c.agg.Timestamp = ts
+
+		c.stats.ScannedValues += int(s.Count)
+		c.stats.ScannedBytes += int(s.Count) * 8
+	}
+}
+
 func (c *integerArrayAscendingCursor) readArrayBlock() *cursors.IntegerArray {
 	values, _ := c.tsm.keyCursor.ReadIntegerArrayBlock(c.tsm.buf)
 	return values
@@ -490,6 +641,9 @@ type integerArrayDescendingCursor struct {
 	end   int64
 	res   *cursors.IntegerArray
 	stats cursors.CursorStats
+
+	// agg accumulates the blocks answered from their statistics by Aggregate.
+	agg *cursors.IntegerArrayAggregate
 }
 
 func newIntegerArrayDescendingCursor() *integerArrayDescendingCursor {
@@ -619,11 +773,58 @@ func (c *integerArrayDescendingCursor) Next() *cursors.IntegerArray {
 
 func (c *integerArrayDescendingCursor) nextTSM() *cursors.IntegerArray {
 	c.tsm.keyCursor.Next()
+	if c.agg != nil {
+		c.aggregateBlocks()
+	}
 	c.tsm.values = c.readArrayBlock()
 	c.tsm.pos = len(c.tsm.values.Timestamps) - 1
 	return c.tsm.values
 }
 
+// Aggregate returns the count, sum, min and max of the remaining values of the
+// cursor. Blocks that lie entirely within the range of the cursor and overlap
+// no other block or cached value are answered from their statistics without
+// being decoded.
+func (c *integerArrayDescendingCursor) Aggregate() cursors.IntegerArrayAggregate {
+	var blocks, values cursors.IntegerArrayAggregate
+	if c.end != math.MaxInt64 {
+		c.agg = &blocks
+	}
+	for a := c.Next(); a.Len() > 0; a = c.Next() {
+		values.AddArray(a)
+	}
+	c.agg = nil
+
+	if values.Count == 0 || (blocks.Count > 0 && blocks.Timestamp > values.Timestamp) {
+		blocks.Merge(values)
+		return blocks
+	}
+	values.Merge(blocks)
+	return values
+}
+
+// aggregateBlocks adds the statistics of the next blocks to c.agg for as long
+// as they can be answered without being decoded.
+func (c *integerArrayDescendingCursor) aggregateBlocks() {
+	for {
+		tr, s, ok := c.tsm.keyCursor.NextBlockStats(c.end+1, math.MaxInt64, c.cache.values)
+		if !ok {
+			return
+		}
+
+		ts := c.agg.Timestamp
+		if c.agg.Count == 0 || tr.Max > ts {
+			ts = tr.Max
+		}
+		min, max, sum := s.IntegerValues()
+		c.agg.Merge(cursors.IntegerArrayAggregate{Count: s.Count, Sum: sum, Min: min, Max: max})
+		c.agg.Timestamp = ts
+
+		c.stats.ScannedValues += int(s.Count)
+		c.stats.ScannedBytes += int(s.Count) * 8
+	}
+}
+
 func (c *integerArrayDescendingCursor) readArrayBlock() *cursors.IntegerArray {
 	values, _ := c.tsm.keyCursor.ReadIntegerArrayBlock(c.tsm.buf)
 
@@ -650,6 +851,9 @@ type unsignedArrayAscendingCursor struct {
 	end   int64
 	res   *cursors.UnsignedArray
 	stats cursors.CursorStats
+
+	// agg accumulates the blocks answered from their statistics by Aggregate.
+	agg *cursors.UnsignedArrayAggregate
 }
 
 func newUnsignedArrayAscendingCursor() *unsignedArrayAscendingCursor {
@@ -774,11 +978,58 @@ func (c *unsignedArrayAscendingCursor) Next() *cursors.UnsignedArray {
 
 func (c *unsignedArrayAscendingCursor) nextTSM() *cursors.UnsignedArray {
 	c.tsm.keyCursor.Next()
+	if c.agg != nil {
+		c.aggregateBlocks()
+	}
 	c.tsm.values = c.readArrayBlock()
 	c.tsm.pos = 0
 	return c.tsm.values
 }
 
+// Aggregate returns the count, sum, min and max of the remaining values of the
+// cursor. Blocks that lie entirely within the range of the cursor and overlap
+// no other block or cached value are answered from their statistics without
+// being decoded.
+func (c *unsignedArrayAscendingCursor) Aggregate() cursors.UnsignedArrayAggregate {
+	var blocks, values cursors.UnsignedArrayAggregate
+	if c.end != math.MinInt64 {
+		c.agg = &blocks
+	}
+	for a := c.Next(); a.Len() > 0; a = c.Next() {
+		values.AddArray(a)
+	}
+	c.agg = nil
+
+	if values.Count == 0 || (blocks.Count > 0 && blocks.Timestamp < values.Timestamp) {
+		blocks.Merge(values)
+		return blocks
+	}
+	values.Merge(blocks)
+	return values
+}
+
+// aggregateBlocks adds the statistics of the next blocks to c.agg for as long
+// as they can be answered without being decoded.
+func (c *unsignedArrayAscendingCursor) aggregateBlocks() {
+	for {
+		tr, s, ok := c.tsm.keyCursor.NextBlockStats(math.MinInt64, c.end-1, c.cache.values)
+		if !ok {
+			return
+		}
+
+		ts := c.agg.Timestamp
+		if c.agg.Count == 0 || tr.Min < ts {
+			ts = tr.Min
+		}
+		min, max, sum := s.UnsignedValues()
+		c.agg.Merge(cursors.UnsignedArrayAggregate{Count: s.Count, Sum: sum, Min: min, Max: max})
+		c.agg.Timestamp = ts
+
+		c.stats.ScannedValues += int(s.Count)
+		c.stats.ScannedBytes += int(s.Count) * 8
+	}
+}
+
 func (c *unsignedArrayAscendingCursor) readArrayBlock() *cursors.UnsignedArray {
 	values, _ := c.tsm.keyCursor.ReadUnsignedArrayBlock(c.tsm.buf)
 	return values
@@ -800,6 +1051,9 @@ type unsignedArrayDescendingCursor struct {
 	end   int64
 	res   *cursors.UnsignedArray
 	stats cursors.CursorStats
+
+	// agg accumulates the blocks answered from their statistics by Aggregate.
+	agg *cursors.UnsignedArrayAggregate
 }
 
 func newUnsignedArrayDescendingCursor() *unsignedArrayDescendingCursor {
@@ -929,11 +1183,58 @@ func (c *unsignedArrayDescendingCursor) Next() *cursors.UnsignedArray {
 
 func (c *unsignedArrayDescendingCursor) nextTSM() *cursors.UnsignedArray {
 	c.tsm.keyCursor.Next()
+	if c.agg != nil {
+		c.aggregateBlocks()
+	}
 	c.tsm.values = c.readArrayBlock()
 	c.tsm.pos = len(c.tsm.values.Timestamps) - 1
 	return c.tsm.values
 }
 
+// Aggregate returns the count, sum, min and max of the remaining values of the
+// cursor. Blocks that lie entirely within the range of the cursor and overlap
+// no other block or cached value are answered from their statistics without
+// being decoded.
+func (c *unsignedArrayDescendingCursor) Aggregate() cursors.UnsignedArrayAggregate {
+	var blocks, values cursors.UnsignedArrayAggregate
+	if c.end != math.MaxInt64 {
+		c.agg = &blocks
+	}
+	for a := c.Next(); a.Len() > 0; a = c.Next() {
+		values.AddArray(a)
+	}
+	c.agg = nil
+
+	if values.Count == 0 || (blocks.Count > 0 && blocks.Timestamp > values.Timestamp) {
+		blocks.Merge(values)
+		return blocks
+	}
+	values.Merge(blocks)
+	return values
+}
+
+// aggregateBlocks adds the statistics of the next blocks to c.agg for as long
+// as they can be answered without being decoded.
+func (c *unsignedArrayDescendingCursor) aggregateBlocks() {
+	for {
+		tr, s, ok := c.tsm.keyCursor.NextBlockStats(c.end+1, math.MaxInt64, c.cache.values)
+		if !ok {
+			return
+		}
+
+		ts := c.agg.Timestamp
+		if c.agg.Count == 0 || tr.Max > ts {
+			ts = tr.Max
+		}
+		min, max, sum := s.UnsignedValues()
+		c.agg.Merge(cursors.UnsignedArrayAggregate{Count: s.Count, Sum: sum, Min: min, Max: max})
+		c.agg.Timestamp = ts
+
+		c.stats.ScannedValues += int(s.Count)
+		c.stats.ScannedBytes += int(s.Count) * 8
+	}
+}
+
 func (c *unsignedArrayDescendingCursor) readArrayBlock() *cursors.UnsignedArray {
 	values, _ := c.tsm.keyCursor.ReadUnsignedArrayBlock(c.tsm.buf)
 
@@ -19,6 +19,7 @@ func (q *arrayCursorIterator) buildFloatArrayCursor(ctx context.Context, name []
 	key := q.seriesFieldKeyBytes(name, tags, field)
 	cacheValues := q.e.Cache.Values(key)
 	keyCursor := q.e.KeyCursor(ctx, key, opt.SeekTime(), opt.Ascending)
+	keyCursor.filter = q.filter
 
 	q.e.readTracker.AddSeeks(uint64(keyCursor.seekN()))
 
@@ -42,6 +43,7 @@ func (q *arrayCursorIterator) buildIntegerArrayCursor(ctx context.Context, name
 	key := q.seriesFieldKeyBytes(name, tags, field)
 	cacheValues := q.e.Cache.Values(key)
 	keyCursor := q.e.KeyCursor(ctx, key, opt.SeekTime(), opt.Ascending)
+	keyCursor.filter = q.filter
 
 	q.e.readTracker.AddSeeks(uint64(keyCursor.seekN()))
 
@@ -65,6 +67,7 @@ func (q *arrayCursorIterator) buildUnsignedArrayCursor(ctx context.Context, name
 	key := q.seriesFieldKeyBytes(name, tags, field)
 	cacheValues := q.e.Cache.Values(key)
 	keyCursor := q.e.KeyCursor(ctx, key, opt.SeekTime(), opt.Ascending)
+	keyCursor.filter = q.filter
 
 	q.e.readTracker.AddSeeks(uint64(keyCursor.seekN()))
 
@@ -88,6 +91,7 @@ func (q *arrayCursorIterator) buildStringArrayCursor(ctx context.Context, name [
 	key := q.seriesFieldKeyBytes(name, tags, field)
 	cacheValues := q.e.Cache.Values(key)
 	keyCursor := q.e.KeyCursor(ctx, key, opt.SeekTime(), opt.Ascending)
+	keyCursor.filter = q.filter
 
 	q.e.readTracker.AddSeeks(uint64(keyCursor.seekN()))
 
@@ -111,6 +115,7 @@ func (q *arrayCursorIterator) buildBooleanArrayCursor(ctx context.Context, name
 	key := q.seriesFieldKeyBytes(name, tags, field)
 	cacheValues := q.e.Cache.Values(key)
 	keyCursor := q.e.KeyCursor(ctx, key, opt.SeekTime(), opt.Ascending)
+	keyCursor.filter = q.filter
 
 	q.e.readTracker.AddSeeks(uint64(keyCursor.seekN()))
 
@@ -15,6 +15,10 @@ type arrayCursorIterator struct {
 	e   *Engine
 	key []byte
 
+	// filter allows the cursors to skip the blocks that cannot match the
+	// value condition of the request.
+	filter cursors.BlockFilter
+
 	asc struct {
 		Float    *floatArrayAscendingCursor
 		Integer  *integerArrayAscendingCursor
@@ -49,6 +53,7 @@ func (q *arrayCursorIterator) Next(ctx context.Context, r *cursors.CursorRequest
 	opt.Ascending = r.Ascending
 	opt.StartTime = r.StartTime
 	opt.EndTime = r.EndTime
+	q.filter = r.ValueFilter
 
 	// Return appropriate cursor based on type.
 	switch typ := id.Type(); typ {
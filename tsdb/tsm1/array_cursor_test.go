@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb/v2/pkg/fs"
+	"github.com/influxdata/influxdb/v2/pkg/metrics"
 	"github.com/influxdata/influxdb/v2/tsdb/cursors"
 	"github.com/stretchr/testify/assert"
 )
@@ -37,15 +38,25 @@ func MustTempFile(dir string) *os.File {
 }
 
 func newFiles(dir string, values ...keyValues) ([]string, error) {
+	return newIndexedFiles(dir, nil, values...)
+}
+
+// newIndexedFiles writes a file for each of values, indexing the values of
+// the keys for which indexValues returns true.
+func newIndexedFiles(dir string, indexValues ValueIndexFunc, values ...keyValues) ([]string, error) {
 	var files []string
 
 	id := 1
 	for _, v := range values {
-		f := MustTempFile(dir)
+		f, err := os.Create(filepath.Join(dir, DefaultFormatFileName(id, 1)+".tsm.tmp"))
+		if err != nil {
+			return nil, err
+		}
 		w, err := NewTSMWriter(f)
 		if err != nil {
 			return nil, err
 		}
+		w.(*tsmWriter).indexValues = indexValues
 
 		if err := w.Write([]byte(v.key), v.values); err != nil {
 			return nil, err
@@ -350,3 +361,190 @@ func TestFileStore_MergeBlocksLargerThat1000_MultipleBlocksInEachFile(t *testing
 		assert.Equal(t, got.Values, exp.Values)
 	})
 }
+
+func TestFileStore_Aggregate(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	fs := NewFileStore(dir)
+
+	makeVals := func(min, max, v int64) []Value {
+		var vals []Value
+		for ts := min; ts <= max; ts++ {
+			vals = append(vals, NewIntegerValue(ts, v*ts))
+		}
+		return vals
+	}
+
+	key := []byte("m,_field=v#!~#v")
+	data := []keyValues{
+		{string(key), makeVals(10, 19, 1)},
+		{string(key), makeVals(30, 39, 1)}, // overwritten by the next block
+		{string(key), makeVals(35, 36, -1)},
+		{string(key), makeVals(50, 59, 1)}, // partially deleted
+		{string(key), makeVals(60, 69, 1)}, // overlaps the cache
+		{string(key), makeVals(70, 79, 1)}, // answered from stats
+		{string(key), makeVals(80, 89, 1)},
+	}
+
+	files, err := newFiles(dir, data...)
+	if err != nil {
+		t.Fatalf("unexpected error creating files: %v", err)
+	}
+	_ = fs.Replace(nil, files)
+
+	if err := fs.DeleteRange([][]byte{key}, 55, 55); err != nil {
+		t.Fatal(err)
+	}
+	cache := Values{NewIntegerValue(65, 1000), NewIntegerValue(95, 1000)}
+
+	aggregate := func(t *testing.T, ascending bool, start, end int64) (cursors.IntegerArrayAggregate, int64) {
+		grp := metrics.NewGroup(tsmGroup)
+		kc := fs.KeyCursor(metrics.NewContextWithGroup(context.Background(), grp), key, start, ascending)
+		defer kc.Close()
+
+		var agg cursors.IntegerArrayAggregate
+		if ascending {
+			cur := newIntegerArrayAscendingCursor()
+			cur.reset(start, end, cache, kc)
+			agg = cur.Aggregate()
+		} else {
+			cur := newIntegerArrayDescendingCursor()
+			cur.reset(start, end, cache, kc)
+			agg = cur.Aggregate()
+		}
+		return agg, grp.GetCounter(blocksStatsCounter).Value()
+	}
+
+	scan := func(t *testing.T, ascending bool, start, end int64) cursors.IntegerArrayAggregate {
+		kc := fs.KeyCursor(context.Background(), key, start, ascending)
+		defer kc.Close()
+
+		var cur cursors.IntegerArrayCursor
+		if ascending {
+			c := newIntegerArrayAscendingCursor()
+			c.reset(start, end, cache, kc)
+			cur = c
+		} else {
+			c := newIntegerArrayDescendingCursor()
+			c.reset(start, end, cache, kc)
+			cur = c
+		}
+
+		var agg cursors.IntegerArrayAggregate
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			agg.AddArray(a)
+		}
+		return agg
+	}
+
+	t.Run("ascending", func(t *testing.T) {
+		const START, END = 0, 85
+		got, n := aggregate(t, true, START, END)
+		if exp := scan(t, true, START, END); !cmp.Equal(got, exp) {
+			t.Errorf("unexpected aggregate; -got/+exp\n%s", cmp.Diff(got, exp))
+		}
+		if n != 1 {
+			t.Errorf("unexpected number of blocks answered from stats: %d", n)
+		}
+	})
+
+	t.Run("descending", func(t *testing.T) {
+		const START, END = 100, 15
+		got, n := aggregate(t, false, START, END)
+		if exp := scan(t, false, START, END); !cmp.Equal(got, exp) {
+			t.Errorf("unexpected aggregate; -got/+exp\n%s", cmp.Diff(got, exp))
+		}
+		if n != 1 {
+			t.Errorf("unexpected number of blocks answered from stats: %d", n)
+		}
+	})
+}
+
+type blockFilterFunc func(s *cursors.BlockSummary) bool
+
+func (fn blockFilterFunc) MayMatch(s *cursors.BlockSummary) bool { return fn(s) }
+
+func TestFileStore_SkipUnmatchedBlocks(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	fs := NewFileStore(dir)
+
+	makeVals := func(min, max, v int64) []Value {
+		var vals []Value
+		for ts := min; ts <= max; ts++ {
+			vals = append(vals, NewIntegerValue(ts, v))
+		}
+		return vals
+	}
+
+	key := []byte("m,_field=v#!~#v")
+	data := []keyValues{
+		{string(key), makeVals(10, 19, 1)}, // skipped
+		{string(key), makeVals(30, 39, 2)}, // overwritten by the next block
+		{string(key), makeVals(35, 36, 3)}, // read, as it overlaps the previous block
+		{string(key), makeVals(50, 59, 4)},
+	}
+
+	files, err := newIndexedFiles(dir, func([]byte) bool { return true }, data...)
+	if err != nil {
+		t.Fatalf("unexpected error creating files: %v", err)
+	}
+	_ = fs.Replace(nil, files)
+
+	// The filter matches values 2 and 4. The block of value 3 is read, since the
+	// values it overwrites would be returned otherwise.
+	filter := blockFilterFunc(func(s *cursors.BlockSummary) bool {
+		return s.Type != cursors.Integer || s.Contains == nil || s.Contains(int64(2)) || s.Contains(int64(4))
+	})
+
+	read := func(t *testing.T, ascending bool, start, end int64, filter cursors.BlockFilter) ([]int64, int64) {
+		grp := metrics.NewGroup(tsmGroup)
+		kc := fs.KeyCursor(metrics.NewContextWithGroup(context.Background(), grp), key, start, ascending)
+		kc.filter = filter
+		defer kc.Close()
+
+		var cur cursors.IntegerArrayCursor
+		if ascending {
+			c := newIntegerArrayAscendingCursor()
+			c.reset(start, end, nil, kc)
+			cur = c
+		} else {
+			c := newIntegerArrayDescendingCursor()
+			c.reset(start, end, nil, kc)
+			cur = c
+		}
+
+		var ts []int64
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			ts = append(ts, a.Timestamps...)
+		}
+		return ts, grp.GetCounter(blocksSkippedCounter).Value()
+	}
+
+	for _, tc := range []struct {
+		name       string
+		ascending  bool
+		start, end int64
+	}{
+		{"ascending", true, 0, 100},
+		{"descending", false, 100, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			all, _ := read(t, tc.ascending, tc.start, tc.end, nil)
+			var exp []int64
+			for _, ts := range all {
+				if ts >= 20 {
+					exp = append(exp, ts)
+				}
+			}
+
+			got, n := read(t, tc.ascending, tc.start, tc.end, filter)
+			if !cmp.Equal(got, exp) {
+				t.Errorf("unexpected timestamps; -got/+exp\n%s", cmp.Diff(got, exp))
+			}
+			if n != 1 {
+				t.Errorf("unexpected number of skipped blocks: %d", n)
+			}
+		})
+	}
+}
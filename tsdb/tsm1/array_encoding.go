@@ -111,6 +111,45 @@ func DecodeStringArrayBlock(block []byte, a *cursors.StringArray) error {
 	return err
 }
 
+// EncodeStringArrayBlockUsing encodes a into a string block, compressing the
+// string values with c.
+func EncodeStringArrayBlockUsing(a *cursors.StringArray, b []byte, c StringCompression) ([]byte, error) {
+	if a.Len() == 0 {
+		return nil, nil
+	}
+
+	vb, err := StringArrayEncodeAllUsing(a.Values, nil, c)
+	if err != nil {
+		return nil, err
+	}
+
+	tb, err := TimeArrayEncodeAll(a.Timestamps, nil)
+	if err != nil {
+		return nil, err
+	}
+	return packBlock(b, BlockString, tb, vb), nil
+}
+
+// BlockStringCompression returns the compression of the values of the string
+// block.
+func BlockStringCompression(block []byte) (StringCompression, error) {
+	if len(block) == 0 {
+		return 0, fmt.Errorf("BlockStringCompression: empty block")
+	}
+	if blockType := block[0]; blockType != BlockString {
+		return 0, fmt.Errorf("invalid block type: exp %d, got %d", BlockString, blockType)
+	}
+
+	_, vb, err := unpackBlock(block[1:])
+	if err != nil {
+		return 0, err
+	}
+	if len(vb) == 0 {
+		return StringCompressionSnappy, nil
+	}
+	return StringCompression(vb[0] >> 4), nil
+}
+
 // DecodeTimestampArrayBlock decodes the timestamps from the specified
 // block, ignoring the block type and the values.
 func DecodeTimestampArrayBlock(block []byte, a *cursors.TimestampArray) error {
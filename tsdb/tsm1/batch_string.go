@@ -23,10 +23,7 @@ var (
 //
 // Currently only the string compression scheme used snappy.
 func StringArrayEncodeAll(src []string, b []byte) ([]byte, error) {
-	srcSz := 2 + len(src)*binary.MaxVarintLen32 // strings should't be longer than 64kb
-	for i := range src {
-		srcSz += len(src[i])
-	}
+	srcSz := 2 + packedStringsSize(src)
 
 	// determine the maximum possible length needed for the buffer, which
 	// includes the compressed size
@@ -56,12 +53,7 @@ func StringArrayEncodeAll(src []string, b []byte) ([]byte, error) {
 	// compression. The compressed data is at the start of the allocated buffer,
 	// ensuring the entire capacity is returned and available for subsequent use.
 	dta := b[compressedSz:]
-	n := 0
-	for i := range src {
-		n += binary.PutUvarint(dta[n:], uint64(len(src[i])))
-		n += copy(dta[n:], src[i])
-	}
-	dta = dta[:n]
+	dta = dta[:packStrings(dta, src)]
 
 	dst := b[:compressedSz]
 	dst[0] = stringCompressedSnappy << 4
@@ -69,15 +61,55 @@ func StringArrayEncodeAll(src []string, b []byte) ([]byte, error) {
 	return dst[:len(res)+1], nil
 }
 
+// StringArrayEncodeAllUsing encodes src into b using the compression c,
+// returning b and any error encountered. The returned slice may be of a
+// different length and capactity to b.
+func StringArrayEncodeAllUsing(src []string, b []byte, c StringCompression) ([]byte, error) {
+	if c != StringCompressionZstd || len(src) == 0 {
+		return StringArrayEncodeAll(src, b)
+	}
+
+	enc, err := sharedZstdEncoder()
+	if err != nil {
+		return nil, err
+	}
+
+	dta := make([]byte, packedStringsSize(src))
+	dta = dta[:packStrings(dta, src)]
+
+	b = append(b[:0], stringCompressedZstd<<4)
+	return enc.EncodeAll(dta, b), nil
+}
+
+// packedStringsSize returns the maximum size of src packed by packStrings.
+func packedStringsSize(src []string) int {
+	sz := len(src) * binary.MaxVarintLen32 // strings should't be longer than 64kb
+	for i := range src {
+		sz += len(src[i])
+	}
+	return sz
+}
+
+// packStrings writes each string of src to dst prefixed by its varint encoded
+// length, returning the number of bytes written. dst must be at least
+// packedStringsSize(src) long.
+func packStrings(dst []byte, src []string) int {
+	n := 0
+	for i := range src {
+		n += binary.PutUvarint(dst[n:], uint64(len(src[i])))
+		n += copy(dst[n:], src[i])
+	}
+	return n
+}
+
 func StringArrayDecodeAll(b []byte, dst []string) ([]string, error) {
-	// First byte stores the encoding type, only have snappy format
-	// currently so ignore for now.
+	// First byte stores the encoding type.
 	if len(b) > 0 {
 		var err error
-		// it is important that to note that `snappy.Decode` always returns
+		// it is important that to note that decompressStrings always returns
 		// a newly allocated slice as the final strings reference this slice
 		// directly.
-		b, err = snappy.Decode(nil, b[1:])
+		b, err = decompressStrings(b)
 		if err != nil {
 			return []string{}, fmt.Errorf("failed to decode string block: %v", err.Error())
 		}
@@ -182,6 +182,68 @@ func TestStringArrayEncodeAll_Quick(t *testing.T) {
 	}, nil)
 }
 
+func TestStringArrayEncodeAllUsing_Zstd_Quick(t *testing.T) {
+	var base []byte
+	quick.Check(func(values []string) bool {
+		src := values
+		if values == nil {
+			src = []string{}
+		}
+
+		// Retrieve encoded bytes from encoder.
+		buf, err := StringArrayEncodeAllUsing(src, base, StringCompressionZstd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(src) > 0 && buf[0]>>4 != stringCompressedZstd {
+			t.Fatalf("unexpected header: got %v, exp %v", buf[0]>>4, stringCompressedZstd)
+		}
+
+		// Read values out of decoder.
+		got, err := StringArrayDecodeAll(buf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Verify that input and output values match.
+		if !reflect.DeepEqual(src, got) {
+			t.Fatalf("mismatch:\n\nexp=%#v\n\ngot=%#v\n\n", src, got)
+		}
+
+		return true
+	}, nil)
+}
+
+func TestStringArrayEncodeAllUsing_Zstd_Compressed(t *testing.T) {
+	src := make([]string, 1000)
+	for i := range src {
+		src[i] = fmt.Sprintf("level=info msg=\"request served\" path=/api/v2/write status=204 req=%d", i)
+	}
+
+	snappy, err := StringArrayEncodeAllUsing(src, nil, StringCompressionSnappy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zstd, err := StringArrayEncodeAllUsing(src, nil, StringCompressionZstd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zstd) >= len(snappy) {
+		t.Fatalf("zstd did not compress better than snappy: got %d bytes, snappy %d bytes", len(zstd), len(snappy))
+	}
+
+	// Both encodings decode to the same values.
+	for _, b := range [][]byte{snappy, zstd} {
+		got, err := StringArrayDecodeAll(b, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(got, src) {
+			t.Fatalf("unexpected values: -got/+exp\n%s", cmp.Diff(got, src))
+		}
+	}
+}
+
 func TestStringArrayDecodeAll_NoValues(t *testing.T) {
 	enc := NewStringEncoder(1024)
 	b, err := enc.Bytes()
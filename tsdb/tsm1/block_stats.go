@@ -0,0 +1,270 @@
+package tsm1
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2/pkg/bloom"
+	"github.com/influxdata/influxdb/v2/tsdb/cursors"
+)
+
+const (
+	// BlockStatsMagicNumber is written as the first 4 bytes of a block stats file
+	// to identify the file as a tsm1 block stats file.
+	BlockStatsMagicNumber string = "TSB1"
+
+	// BlockStatsVersion indicates the version of the TSB1 file format. Readers
+	// ignore block stats files with a different version and decode the blocks
+	// instead, so the version can be bumped without breaking older readers.
+	BlockStatsVersion byte = 1
+
+	// blockStatsEntrySize is the size of an encoded BlockStats entry.
+	blockStatsEntrySize = 5 * 8
+)
+
+// BlockStats holds statistics about the values of a single float, integer or
+// unsigned block. Min, Max and Sum hold the bits of float64, int64 or uint64
+// values depending on the type of the block.
+type BlockStats struct {
+	Offset int64 // offset of the block within the TSM file
+	Count  int64
+	Min    uint64
+	Max    uint64
+	Sum    uint64
+}
+
+// FloatValues returns the min, max and sum of a float block.
+func (s BlockStats) FloatValues() (min, max, sum float64) {
+	return math.Float64frombits(s.Min), math.Float64frombits(s.Max), math.Float64frombits(s.Sum)
+}
+
+// IntegerValues returns the min, max and sum of an integer block.
+func (s BlockStats) IntegerValues() (min, max, sum int64) {
+	return int64(s.Min), int64(s.Max), int64(s.Sum)
+}
+
+// UnsignedValues returns the min, max and sum of an unsigned block.
+func (s BlockStats) UnsignedValues() (min, max, sum uint64) {
+	return s.Min, s.Max, s.Sum
+}
+
+func newFloatBlockStats(offset int64, values []float64) BlockStats {
+	min, max, sum := values[0], values[0], float64(0)
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+		sum += v
+	}
+	return BlockStats{
+		Offset: offset,
+		Count:  int64(len(values)),
+		Min:    math.Float64bits(min),
+		Max:    math.Float64bits(max),
+		Sum:    math.Float64bits(sum),
+	}
+}
+
+func newIntegerBlockStats(offset int64, values []int64) BlockStats {
+	min, max, sum := values[0], values[0], int64(0)
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+		sum += v
+	}
+	return BlockStats{Offset: offset, Count: int64(len(values)), Min: uint64(min), Max: uint64(max), Sum: uint64(sum)}
+}
+
+func newUnsignedBlockStats(offset int64, values []uint64) BlockStats {
+	min, max, sum := values[0], values[0], uint64(0)
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+		sum += v
+	}
+	return BlockStats{Offset: offset, Count: int64(len(values)), Min: min, Max: max, Sum: sum}
+}
+
+// valuesBlockStats returns the stats of a block at offset holding values.
+// It returns false if values are not of a numeric type.
+func valuesBlockStats(offset int64, values Values) (BlockStats, bool) {
+	switch values[0].(type) {
+	case FloatValue:
+		a := make([]float64, len(values))
+		for i, v := range values {
+			a[i] = v.(FloatValue).RawValue()
+		}
+		return newFloatBlockStats(offset, a), true
+	case IntegerValue:
+		a := make([]int64, len(values))
+		for i, v := range values {
+			a[i] = v.(IntegerValue).RawValue()
+		}
+		return newIntegerBlockStats(offset, a), true
+	case UnsignedValue:
+		a := make([]uint64, len(values))
+		for i, v := range values {
+			a[i] = v.(UnsignedValue).RawValue()
+		}
+		return newUnsignedBlockStats(offset, a), true
+	default:
+		return BlockStats{}, false
+	}
+}
+
+// encodedBlockStats decodes block and returns the stats of its values. It
+// returns false if the block is not of a numeric type or cannot be decoded.
+func encodedBlockStats(offset int64, blockType byte, block []byte) (BlockStats, bool) {
+	switch blockType {
+	case BlockFloat64:
+		var a cursors.FloatArray
+		if err := DecodeFloatArrayBlock(block, &a); err != nil || a.Len() == 0 {
+			return BlockStats{}, false
+		}
+		return newFloatBlockStats(offset, a.Values), true
+	case BlockInteger:
+		var a cursors.IntegerArray
+		if err := DecodeIntegerArrayBlock(block, &a); err != nil || a.Len() == 0 {
+			return BlockStats{}, false
+		}
+		return newIntegerBlockStats(offset, a.Values), true
+	case BlockUnsigned:
+		var a cursors.UnsignedArray
+		if err := DecodeUnsignedArrayBlock(block, &a); err != nil || a.Len() == 0 {
+			return BlockStats{}, false
+		}
+		return newUnsignedBlockStats(offset, a.Values), true
+	default:
+		return BlockStats{}, false
+	}
+}
+
+// blockMeta holds the value statistics and filter of a block read from a TSM
+// file, so they are kept when the block is copied to another file.
+type blockMeta struct {
+	stats    BlockStats
+	hasStats bool
+	filter   *bloom.Filter
+}
+
+// BlockStatsIndex is the set of block stats of a TSM file, sorted by offset.
+type BlockStatsIndex []BlockStats
+
+// Find returns the stats of the block at offset, if available.
+func (a BlockStatsIndex) Find(offset int64) (BlockStats, bool) {
+	i := sort.Search(len(a), func(i int) bool { return a[i].Offset >= offset })
+	if i < len(a) && a[i].Offset == offset {
+		return a[i], true
+	}
+	return BlockStats{}, false
+}
+
+// ReadFrom reads block stats from r in a binary format.
+func (a *BlockStatsIndex) ReadFrom(r io.Reader) (n int64, err error) {
+	data, err := ioutil.ReadAll(r)
+	if n = int64(len(data)); err != nil {
+		return n, fmt.Errorf("tsm1.BlockStatsIndex.ReadFrom: cannot read block stats: %s", err)
+	}
+
+	// Verify magic & version.
+	if len(data) < 9 || string(data[:4]) != BlockStatsMagicNumber {
+		return n, fmt.Errorf("tsm1.BlockStatsIndex.ReadFrom: invalid tsm1 block stats file")
+	} else if data[4] != BlockStatsVersion {
+		return n, fmt.Errorf("tsm1.BlockStatsIndex.ReadFrom: incompatible tsm1 block stats version: %d", data[4])
+	}
+
+	// Verify checksum.
+	checksum, data := binary.BigEndian.Uint32(data[5:9]), data[9:]
+	if crc32.ChecksumIEEE(data) != checksum {
+		return n, fmt.Errorf("tsm1.BlockStatsIndex.ReadFrom: block stats checksum mismatch")
+	}
+
+	// Read entry count.
+	entryN, sz := binary.Uvarint(data)
+	if sz <= 0 {
+		return n, fmt.Errorf("tsm1.BlockStatsIndex.ReadFrom: cannot read block stats entry count")
+	}
+	data = data[sz:]
+	if uint64(len(data)) != entryN*blockStatsEntrySize {
+		return n, fmt.Errorf("tsm1.BlockStatsIndex.ReadFrom: unexpected block stats size")
+	}
+
+	// Read entries.
+	entries := make(BlockStatsIndex, entryN)
+	for i := range entries {
+		b := data[i*blockStatsEntrySize:]
+		entries[i] = BlockStats{
+			Offset: int64(binary.BigEndian.Uint64(b[0:8])),
+			Count:  int64(binary.BigEndian.Uint64(b[8:16])),
+			Min:    binary.BigEndian.Uint64(b[16:24]),
+			Max:    binary.BigEndian.Uint64(b[24:32]),
+			Sum:    binary.BigEndian.Uint64(b[32:40]),
+		}
+	}
+	*a = entries
+
+	return n, nil
+}
+
+// WriteTo writes block stats to w in a binary format.
+func (a BlockStatsIndex) WriteTo(w io.Writer) (n int64, err error) {
+	// Write magic & version.
+	nn, err := io.WriteString(w, BlockStatsMagicNumber)
+	if n += int64(nn); err != nil {
+		return n, err
+	}
+	nn, err = w.Write([]byte{BlockStatsVersion})
+	if n += int64(nn); err != nil {
+		return n, err
+	}
+
+	// Write entry count & entries.
+	var buf bytes.Buffer
+	b := make([]byte, blockStatsEntrySize)
+	buf.Write(b[:binary.PutUvarint(b, uint64(len(a)))])
+	for _, s := range a {
+		binary.BigEndian.PutUint64(b[0:8], uint64(s.Offset))
+		binary.BigEndian.PutUint64(b[8:16], uint64(s.Count))
+		binary.BigEndian.PutUint64(b[16:24], s.Min)
+		binary.BigEndian.PutUint64(b[24:32], s.Max)
+		binary.BigEndian.PutUint64(b[32:40], s.Sum)
+		buf.Write(b)
+	}
+	data := buf.Bytes()
+
+	// Compute & write checksum.
+	if err := binary.Write(w, binary.BigEndian, crc32.ChecksumIEEE(data)); err != nil {
+		return n, err
+	}
+	n += 4
+
+	// Write buffer.
+	nn, err = w.Write(data)
+	n += int64(nn)
+	return n, err
+}
+
+// BlockStatsFilename returns the path to the block stats file for a given TSM file path.
+func BlockStatsFilename(tsmPath string) string {
+	tsmPath = strings.TrimSuffix(tsmPath, "."+TmpTSMFileExtension)
+	tsmPath = strings.TrimSuffix(tsmPath, "."+TSMFileExtension)
+	return tsmPath + "." + TSBFileExtension
+}
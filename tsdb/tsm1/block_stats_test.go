@@ -0,0 +1,113 @@
+package tsm1_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+)
+
+func TestBlockStatsIndex_WriteTo(t *testing.T) {
+	stats := tsm1.BlockStatsIndex{
+		{Offset: 5, Count: 3, Min: math.Float64bits(-1.5), Max: math.Float64bits(2), Sum: math.Float64bits(1)},
+		{Offset: 40, Count: 1000, Min: 1, Max: 1000, Sum: 500500},
+	}
+
+	var buf bytes.Buffer
+	var other tsm1.BlockStatsIndex
+	if wn, err := stats.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	} else if rn, err := other.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	} else if wn != rn {
+		t.Fatalf("byte count mismatch: w=%d r=%d", wn, rn)
+	} else if diff := cmp.Diff(stats, other); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if s, ok := other.Find(40); !ok || s.Sum != 500500 {
+		t.Fatalf("unexpected stats: %v, %v", s, ok)
+	} else if _, ok := other.Find(41); ok {
+		t.Fatal("expected no stats for offset 41")
+	}
+	if min, max, sum := other[0].FloatValues(); min != -1.5 || max != 2 || sum != 1 {
+		t.Fatalf("unexpected float values: %v, %v, %v", min, max, sum)
+	}
+}
+
+func TestBlockStatsIndex_ReadFrom_UnknownVersion(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := (tsm1.BlockStatsIndex{{Offset: 5, Count: 1}}).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	b[len(tsm1.BlockStatsMagicNumber)] = tsm1.BlockStatsVersion + 1
+
+	var stats tsm1.BlockStatsIndex
+	if _, err := stats.ReadFrom(bytes.NewReader(b)); err == nil {
+		t.Fatal("expected error")
+	} else if len(stats) != 0 {
+		t.Fatalf("unexpected stats: %v", stats)
+	}
+}
+
+func TestCompactor_CompactFull_KeepsBlockStats(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	f1 := MustWriteTSM(dir, 1, map[string][]tsm1.Value{"cpu,host=A#!~#value": {tsm1.NewValue(1, 1.5)}})
+	f2 := MustWriteTSM(dir, 2, map[string][]tsm1.Value{"cpu,host=B#!~#value": {tsm1.NewValue(1, 2.5)}})
+
+	// Mark the stats of the first file, so the stats of its block are known to
+	// be copied rather than computed again.
+	var stats tsm1.BlockStatsIndex
+	if b, err := ioutil.ReadFile(tsm1.BlockStatsFilename(f1)); err != nil {
+		t.Fatal(err)
+	} else if _, err := stats.ReadFrom(bytes.NewReader(b)); err != nil {
+		t.Fatal(err)
+	} else if len(stats) != 1 {
+		t.Fatalf("unexpected stats: %v", stats)
+	}
+	stats[0].Sum = math.Float64bits(42)
+	var buf bytes.Buffer
+	if _, err := stats.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	} else if err := ioutil.WriteFile(tsm1.BlockStatsFilename(f1), buf.Bytes(), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := &fakeFileStore{}
+	defer fs.Close()
+	compactor := tsm1.NewCompactor()
+	compactor.Dir = dir
+	compactor.FileStore = fs
+	compactor.Open()
+
+	files, err := compactor.CompactFull([]string{f1, f2})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(files) != 1 {
+		t.Fatalf("unexpected files: %v", files)
+	}
+
+	r := MustOpenTSMReader(files[0])
+	defer r.Close()
+	for key, exp := range map[string]float64{"cpu,host=A#!~#value": 42, "cpu,host=B#!~#value": 2.5} {
+		entries, err := r.ReadEntries([]byte(key), nil)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(entries) != 1 {
+			t.Fatalf("unexpected entries of %s: %v", key, entries)
+		}
+		s, ok := r.BlockStats(&entries[0])
+		if !ok {
+			t.Fatalf("no stats for %s", key)
+		} else if _, _, sum := s.FloatValues(); sum != exp {
+			t.Fatalf("unexpected sum of %s: got %v, exp %v", key, sum, exp)
+		}
+	}
+}
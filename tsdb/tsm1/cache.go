@@ -40,12 +40,44 @@ func ErrCacheMemorySizeLimitExceeded(n, limit uint64) error {
 	return CacheMemorySizeLimitExceededError{Size: n, Limit: limit}
 }
 
+// MaxLatenessExceededError is the type of error returned from the cache when
+// values of a write are older than its max lateness, and were dropped.
+type MaxLatenessExceededError struct {
+	Dropped     int
+	MaxLateness time.Duration
+}
+
+func (e MaxLatenessExceededError) Error() string {
+	return fmt.Sprintf("max-lateness exceeded: (%d values older than %s dropped)", e.Dropped, e.MaxLateness)
+}
+
+// ErrMaxLatenessExceeded returns an error indicating n values could not be
+// written due to being older than the max-lateness setting.
+func ErrMaxLatenessExceeded(n int, maxLateness time.Duration) error {
+	return MaxLatenessExceededError{Dropped: n, MaxLateness: maxLateness}
+}
+
 // Cache maintains an in-memory store of Values for a set of keys.
 type Cache struct {
 	mu      sync.RWMutex
 	store   *ring
 	maxSize uint64
 
+	// late is the segment of the cache staging the out of order values, which are
+	// older than the watermark. It is snapshotted to TSM files of their own, so
+	// backfills don't overlap the files of the values written in order.
+	late *ring
+
+	// watermark is the time before which written values are out of order. Each
+	// snapshot raises it to the newest value written in order since the last
+	// one, maxTime, capped at the current time.
+	watermark int64
+	maxTime   int64
+
+	// maxLateness is how old values can be before writes drop them. Zero
+	// accepts values of any age.
+	maxLateness time.Duration
+
 	// snapshots are the cache objects that are currently being written to tsm files
 	// they're kept in memory while flushing so they can be queried along with the cache.
 	// they are read only and should never be modified
@@ -63,6 +95,9 @@ func NewCache(maxSize uint64) *Cache {
 	return &Cache{
 		maxSize:      maxSize,
 		store:        newRing(),
+		late:         newRing(),
+		watermark:    math.MinInt64,
+		maxTime:      math.MinInt64,
 		lastSnapshot: time.Now(),
 		tracker:      newCacheTracker(newCacheMetrics(nil), nil),
 	}
@@ -71,35 +106,7 @@ func NewCache(maxSize uint64) *Cache {
 // Write writes the set of values for the key to the cache. This function is goroutine-safe.
 // It returns an error if the cache will exceed its max size by adding the new values.
 func (c *Cache) Write(key []byte, values []Value) error {
-	addedSize := uint64(Values(values).Size())
-
-	// Enough room in the cache?
-	limit := c.maxSize
-	n := c.Size() + addedSize
-
-	if limit > 0 && n > limit {
-		c.tracker.IncWritesErr()
-		c.tracker.AddWrittenBytesDrop(uint64(addedSize))
-		return ErrCacheMemorySizeLimitExceeded(n, limit)
-	}
-
-	newKey, err := c.store.write(key, values)
-	if err != nil {
-		c.tracker.IncWritesErr()
-		c.tracker.AddWrittenBytesErr(uint64(addedSize))
-		return err
-	}
-
-	if newKey {
-		addedSize += uint64(len(key))
-	}
-	// Update the cache size and the memory size stat.
-	c.tracker.IncCacheSize(addedSize)
-	c.tracker.AddMemBytes(addedSize)
-	c.tracker.AddWrittenBytesOK(uint64(addedSize))
-	c.tracker.IncWritesOK()
-
-	return nil
+	return c.WriteMulti(map[string][]Value{string(key): values})
 }
 
 // WriteMulti writes the map of keys and associated values to the cache. This
@@ -107,6 +114,9 @@ func (c *Cache) Write(key []byte, values []Value) error {
 // its max size by adding the new values.  The write attempts to write as many
 // values as possible.  If one key fails, the others can still succeed and an
 // error will be returned.
+//
+// Values older than the watermark are staged in the late segment of the cache,
+// and values older than the max lateness are dropped.
 func (c *Cache) WriteMulti(values map[string][]Value) error {
 	var addedSize uint64
 	for _, v := range values {
@@ -124,19 +134,52 @@ func (c *Cache) WriteMulti(values map[string][]Value) error {
 
 	var werr error
 	c.mu.RLock()
-	store := c.store
+	store, late := c.store, c.late
+	watermark, cutoff := c.watermark, c.latenessCutoff()
 	c.mu.RUnlock()
 
-	var bytesWrittenErr uint64
+	var bytesWrittenErr, bytesDropped, lateSize uint64
+	var lateValues, droppedValues int
+	maxTime := int64(math.MinInt64)
 
 	// We'll optimistically set size here, and then decrement it for write errors.
 	for k, v := range values {
-		newKey, err := store.write([]byte(k), v)
+		inOrder, outOfOrder, dropped, max := partitionLate(v, watermark, cutoff)
+		if len(dropped) > 0 {
+			droppedValues += len(dropped)
+			addedSize -= uint64(dropped.Size())
+			bytesDropped += uint64(dropped.Size())
+		}
+
+		if len(outOfOrder) > 0 {
+			newKey, err := late.write([]byte(k), outOfOrder)
+			if err != nil {
+				werr = err
+				addedSize -= uint64(outOfOrder.Size())
+				bytesWrittenErr += uint64(outOfOrder.Size())
+			} else {
+				lateValues += len(outOfOrder)
+				lateSize += uint64(outOfOrder.Size())
+			}
+
+			if newKey {
+				addedSize += uint64(len(k))
+				lateSize += uint64(len(k))
+			}
+		}
+
+		if len(inOrder) == 0 {
+			continue
+		}
+
+		newKey, err := store.write([]byte(k), inOrder)
 		if err != nil {
 			// The write failed, hold onto the error and adjust the size delta.
 			werr = err
-			addedSize -= uint64(Values(v).Size())
-			bytesWrittenErr += uint64(Values(v).Size())
+			addedSize -= uint64(inOrder.Size())
+			bytesWrittenErr += uint64(inOrder.Size())
+		} else if max > maxTime {
+			maxTime = max
 		}
 
 		if newKey {
@@ -152,19 +195,79 @@ func (c *Cache) WriteMulti(values map[string][]Value) error {
 		c.tracker.AddWrittenBytesErr(bytesWrittenErr)
 	}
 
+	// Some values were older than the max lateness.
+	if droppedValues > 0 {
+		c.tracker.AddWrittenBytesDrop(bytesDropped)
+		c.tracker.AddLateValuesDrop(droppedValues)
+	}
+
 	// Update the memory size stat
 	c.tracker.IncCacheSize(addedSize)
 	c.tracker.AddMemBytes(addedSize)
 	c.tracker.IncWritesOK()
 	c.tracker.AddWrittenBytesOK(addedSize)
+	if lateValues > 0 {
+		c.tracker.IncLateSize(lateSize)
+		c.tracker.AddLateValuesOK(lateValues)
+	}
 
 	c.mu.Lock()
 	c.lastWriteTime = time.Now()
+	if maxTime > c.maxTime {
+		c.maxTime = maxTime
+	}
+	maxLateness := c.maxLateness
 	c.mu.Unlock()
 
+	if werr == nil && droppedValues > 0 {
+		werr = ErrMaxLatenessExceeded(droppedValues, maxLateness)
+	}
 	return werr
 }
 
+// partitionLate partitions values into the values in order, at or after the
+// watermark, and the out of order values before it. Values before cutoff are
+// dropped. It also returns the newest of the values in order.
+func partitionLate(values Values, watermark, cutoff int64) (inOrder, late, dropped Values, max int64) {
+	min, max := int64(math.MaxInt64), int64(math.MinInt64)
+	for _, v := range values {
+		ts := v.UnixNano()
+		if ts < min {
+			min = ts
+		}
+		if ts > max {
+			max = ts
+		}
+	}
+	if min >= watermark && min >= cutoff {
+		return values, nil, nil, max
+	}
+
+	max = math.MinInt64
+	for _, v := range values {
+		switch ts := v.UnixNano(); {
+		case ts < cutoff:
+			dropped = append(dropped, v)
+		case ts < watermark:
+			late = append(late, v)
+		default:
+			inOrder = append(inOrder, v)
+			if ts > max {
+				max = ts
+			}
+		}
+	}
+	return inOrder, late, dropped, max
+}
+
+// latenessCutoff returns the time before which writes drop values. c.mu must be held.
+func (c *Cache) latenessCutoff() int64 {
+	if c.maxLateness <= 0 {
+		return math.MinInt64
+	}
+	return time.Now().Add(-c.maxLateness).UnixNano()
+}
+
 // Snapshot takes a snapshot of the current cache, adds it to the slice of caches that
 // are being flushed, and resets the current cache with new values.
 func (c *Cache) Snapshot() (*Cache, error) {
@@ -182,6 +285,7 @@ func (c *Cache) Snapshot() (*Cache, error) {
 	if c.snapshot == nil {
 		c.snapshot = &Cache{
 			store:   newRing(),
+			late:    newRing(),
 			tracker: newCacheTracker(c.tracker.metrics, c.tracker.labels),
 		}
 	}
@@ -193,6 +297,7 @@ func (c *Cache) Snapshot() (*Cache, error) {
 	}
 
 	c.snapshot.store, c.store = c.store, c.snapshot.store
+	c.snapshot.late, c.late = c.late, c.snapshot.late
 	snapshotSize := c.Size()
 
 	c.snapshot.tracker.SetSnapshotSize(snapshotSize) // Save the size of the snapshot on the snapshot cache
@@ -200,9 +305,15 @@ func (c *Cache) Snapshot() (*Cache, error) {
 
 	// Reset the cache's store.
 	c.store.reset()
+	c.late.reset()
 	c.tracker.SetCacheSize(0)
+	c.tracker.SetLateSize(0)
 	c.lastSnapshot = time.Now()
 
+	// Values older than those snapshotted are out of order from now on.
+	c.raiseWatermark(c.maxTime)
+	c.maxTime = math.MinInt64
+
 	c.tracker.AddSnapshottedBytes(snapshotSize) // increment the number of bytes added to the snapshot
 	c.tracker.SetDiskBytes(0)
 	c.tracker.SetSnapshotsActive(0)
@@ -214,24 +325,26 @@ func (c *Cache) Snapshot() (*Cache, error) {
 // coming in while it writes will need the values sorted.
 func (c *Cache) Deduplicate() {
 	c.mu.RLock()
-	store := c.store
+	store, late := c.store, c.late
 	c.mu.RUnlock()
 
 	// Apply a function that simply calls deduplicate on each entry in the ring.
 	// apply cannot return an error in this invocation.
 	_ = store.apply(func(_ []byte, e *entry) error { e.deduplicate(); return nil })
+	_ = late.apply(func(_ []byte, e *entry) error { e.deduplicate(); return nil })
 }
 
 // ClearSnapshot removes the snapshot cache from the list of flushing caches and
 // adjusts the size.
 func (c *Cache) ClearSnapshot(success bool) {
 	c.mu.RLock()
-	snapStore := c.snapshot.store
+	snapStore, snapLate := c.snapshot.store, c.snapshot.late
 	c.mu.RUnlock()
 
 	// reset the snapshot store outside of the write lock
 	if success {
 		snapStore.reset()
+		snapLate.reset()
 	}
 
 	c.mu.Lock()
@@ -247,6 +360,7 @@ func (c *Cache) ClearSnapshot(success bool) {
 		// Reset the snapshot to a fresh Cache.
 		c.snapshot = &Cache{
 			store:   c.snapshot.store,
+			late:    c.snapshot.late,
 			tracker: newCacheTracker(c.tracker.metrics, c.tracker.labels),
 		}
 
@@ -266,6 +380,76 @@ func (c *Cache) MaxSize() uint64 {
 	return c.maxSize
 }
 
+// LateSize returns the number of point-calculated bytes of the out of order values
+// staged in the late segment of the cache, not including the snapshot.
+func (c *Cache) LateSize() uint64 {
+	return c.tracker.LateSize()
+}
+
+// Watermark returns the time before which written values are out of order.
+func (c *Cache) Watermark() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.watermark
+}
+
+// SetWatermark raises the watermark to t, capped at the current time. It is
+// set to the newest point on disk when the cache is loaded.
+func (c *Cache) SetWatermark(t int64) {
+	c.mu.Lock()
+	c.raiseWatermark(t)
+	c.mu.Unlock()
+}
+
+// raiseWatermark raises the watermark to t, capped at the current time, so that
+// points far in the future don't make every following write out of order.
+// c.mu must be held.
+func (c *Cache) raiseWatermark(t int64) {
+	if now := time.Now().UnixNano(); t > now {
+		t = now
+	}
+	if t > c.watermark {
+		c.watermark = t
+	}
+}
+
+// MaxLateness returns how old values can be before writes drop them.
+func (c *Cache) MaxLateness() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.maxLateness
+}
+
+// AddLateValuesDropped counts n values dropped by the writer of the cache,
+// before writing them, for being older than the max lateness.
+func (c *Cache) AddLateValuesDropped(n int) {
+	if n > 0 {
+		c.tracker.AddLateValuesDrop(n)
+	}
+}
+
+// SetMaxLateness updates how old values can be before writes drop them. Zero
+// accepts values of any age.
+func (c *Cache) SetMaxLateness(d time.Duration) {
+	c.mu.Lock()
+	c.maxLateness = d
+	c.mu.Unlock()
+}
+
+// lateSegment returns the late segment of a snapshot as a cache of its own, or
+// nil if no values were out of order. Keys, Count, Split and values of a
+// snapshot only cover the values in order.
+func (c *Cache) lateSegment() *Cache {
+	c.mu.RLock()
+	late := c.late
+	c.mu.RUnlock()
+
+	if late == nil || late.count() == 0 {
+		return nil
+	}
+	return &Cache{store: late}
+}
+
 func (c *Cache) Count() int {
 	c.mu.RLock()
 	n := c.store.count()
@@ -288,9 +472,11 @@ func (c *Cache) Split(n int) []*Cache {
 
 	caches := make([]*Cache, n)
 	storers := c.store.split(n)
+	lates := c.late.split(n)
 	for i := 0; i < n; i++ {
 		caches[i] = &Cache{
 			store: storers[i],
+			late:  lates[i],
 		}
 	}
 	return caches
@@ -299,10 +485,7 @@ func (c *Cache) Split(n int) []*Cache {
 // Type returns the series type for a key.
 func (c *Cache) Type(key []byte) (models.FieldType, error) {
 	c.mu.RLock()
-	e := c.store.entry(key)
-	if e == nil && c.snapshot != nil {
-		e = c.snapshot.store.entry(key)
-	}
+	e := c.entry(key)
 	c.mu.RUnlock()
 
 	if e != nil {
@@ -334,10 +517,7 @@ func (c *Cache) Type(key []byte) (models.FieldType, error) {
 // values for the key.
 func (c *Cache) BlockType(key []byte) byte {
 	c.mu.RLock()
-	e := c.store.entry(key)
-	if e == nil && c.snapshot != nil {
-		e = c.snapshot.store.entry(key)
-	}
+	e := c.entry(key)
 	c.mu.RUnlock()
 
 	if e != nil {
@@ -347,61 +527,66 @@ func (c *Cache) BlockType(key []byte) byte {
 	return BlockUndefined
 }
 
+// entry returns the entry for key of the values in order, or else the out of
+// order values, of the cache or its snapshot. c.mu must be held.
+func (c *Cache) entry(key []byte) *entry {
+	for _, store := range []*ring{c.store, c.late} {
+		if e := store.entry(key); e != nil {
+			return e
+		}
+	}
+	if c.snapshot != nil {
+		return c.snapshot.entry(key)
+	}
+	return nil
+}
+
+// entries returns the entries for key of the snapshot and the cache, in the
+// order they were written. The entries of the values in order and out of order
+// don't share timestamps, as they are on either side of the watermark.
+// c.mu must be held.
+func (c *Cache) entries(key []byte) []*entry {
+	var entries []*entry
+	if c.snapshot != nil {
+		entries = c.snapshot.entries(key)
+	}
+	for _, store := range []*ring{c.late, c.store} {
+		if e := store.entry(key); e != nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
 // AppendTimestamps appends ts with the timestamps for the specified key.
 // It is the responsibility of the caller to sort and or deduplicate the slice.
 func (c *Cache) AppendTimestamps(key []byte, ts []int64) []int64 {
-	var snapshotEntries *entry
-
 	c.mu.RLock()
-	e := c.store.entry(key)
-	if c.snapshot != nil {
-		snapshotEntries = c.snapshot.store.entry(key)
-	}
+	entries := c.entries(key)
 	c.mu.RUnlock()
 
-	if e != nil {
+	for _, e := range entries {
 		ts = e.AppendTimestamps(ts)
 	}
-	if snapshotEntries != nil {
-		ts = snapshotEntries.AppendTimestamps(ts)
-	}
 
 	return ts
 }
 
 // Values returns a copy of all values, deduped and sorted, for the given key.
 func (c *Cache) Values(key []byte) Values {
-	var snapshotEntries *entry
-
 	c.mu.RLock()
-	e := c.store.entry(key)
-	if c.snapshot != nil {
-		snapshotEntries = c.snapshot.store.entry(key)
-	}
+	entries := c.entries(key)
 	c.mu.RUnlock()
 
-	if e == nil {
-		if snapshotEntries == nil {
-			// No values in hot cache or snapshots.
-			return nil
-		}
-	} else {
-		e.deduplicate()
+	if len(entries) == 0 {
+		// No values in hot cache or snapshots.
+		return nil
 	}
 
-	// Build the sequence of entries that will be returned, in the correct order.
 	// Calculate the required size of the destination buffer.
-	var entries []*entry
 	sz := 0
-
-	if snapshotEntries != nil {
-		snapshotEntries.deduplicate() // guarantee we are deduplicated
-		entries = append(entries, snapshotEntries)
-		sz += snapshotEntries.count()
-	}
-
-	if e != nil {
-		entries = append(entries, e)
+	for _, e := range entries {
+		e.deduplicate() // guarantee we are deduplicated
 		sz += e.count()
 	}
 
@@ -437,11 +622,22 @@ func (c *Cache) DeleteBucketRange(ctx context.Context, name string, min, max int
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	total := deleteBucketRange(c.store, name, min, max, pred)
+	lateTotal := deleteBucketRange(c.late, name, min, max, pred)
+
+	c.tracker.DecCacheSize(total + lateTotal)
+	c.tracker.DecLateSize(lateTotal)
+	c.tracker.SetMemBytes(uint64(c.Size()))
+}
+
+// deleteBucketRange removes the values of store as described by DeleteBucketRange,
+// and returns the number of bytes removed.
+func deleteBucketRange(store *ring, name string, min, max int64, pred Predicate) uint64 {
 	var toDelete []string
 	var total uint64
 
 	// applySerial only errors if the closure returns an error.
-	_ = c.store.applySerial(func(k string, e *entry) error {
+	_ = store.applySerial(func(k string, e *entry) error {
 		if !strings.HasPrefix(k, name) {
 			return nil
 		}
@@ -474,11 +670,9 @@ func (c *Cache) DeleteBucketRange(ctx context.Context, name string, min, max int
 	for _, k := range toDelete {
 		total += uint64(len(k))
 		// TODO(edd): either use unsafe conversion to []byte or add a removeString method.
-		c.store.remove([]byte(k))
+		store.remove([]byte(k))
 	}
-
-	c.tracker.DecCacheSize(total)
-	c.tracker.SetMemBytes(uint64(c.Size()))
+	return total
 }
 
 // SetMaxSize updates the memory limit of the cache.
@@ -504,12 +698,16 @@ func (c *Cache) values(key []byte) Values {
 
 // ApplyEntryFn applies the function f to each entry in the Cache.
 // ApplyEntryFn calls f on each entry in turn, within the same goroutine.
+// A key with values both in order and out of order has an entry for each.
 // It is safe for use by multiple goroutines.
 func (c *Cache) ApplyEntryFn(f func(key string, entry *entry) error) error {
 	c.mu.RLock()
-	store := c.store
+	store, late := c.store, c.late
 	c.mu.RUnlock()
-	return store.applySerial(f)
+	if err := store.applySerial(f); err != nil {
+		return err
+	}
+	return late.applySerial(f)
 }
 
 // CacheLoader processes a set of WAL segment files, and loads a cache with the data
@@ -596,6 +794,7 @@ type cacheTracker struct {
 	snapshotsActive uint64
 	snapshotSize    uint64
 	cacheSize       uint64
+	lateSize        uint64
 
 	// Used in testing.
 	memSizeBytes     uint64
@@ -730,6 +929,50 @@ func (t *cacheTracker) DecCacheSize(sz uint64) { atomic.AddUint64(&t.cacheSize,
 // SetCacheSize sets the live cache size to sz.
 func (t *cacheTracker) SetCacheSize(sz uint64) { atomic.StoreUint64(&t.cacheSize, sz) }
 
+// LateSize returns the size of the live late segment, which is included in the
+// live cache size.
+func (t *cacheTracker) LateSize() uint64 { return atomic.LoadUint64(&t.lateSize) }
+
+// IncLateSize increases the live late segment size by sz bytes.
+func (t *cacheTracker) IncLateSize(sz uint64) {
+	n := atomic.AddUint64(&t.lateSize, sz)
+
+	labels := t.labels
+	t.metrics.LateSize.With(labels).Set(float64(n))
+}
+
+// DecLateSize decreases the live late segment size by sz bytes.
+func (t *cacheTracker) DecLateSize(sz uint64) {
+	n := atomic.AddUint64(&t.lateSize, ^(sz - 1))
+
+	labels := t.labels
+	t.metrics.LateSize.With(labels).Set(float64(n))
+}
+
+// SetLateSize sets the live late segment size to sz.
+func (t *cacheTracker) SetLateSize(sz uint64) {
+	atomic.StoreUint64(&t.lateSize, sz)
+
+	labels := t.labels
+	t.metrics.LateSize.With(labels).Set(float64(sz))
+}
+
+// AddLateValues increases the number of out of order values written to the
+// cache, with a required status.
+func (t *cacheTracker) AddLateValues(status string, n int) {
+	labels := t.Labels()
+	labels["status"] = status
+	t.metrics.LateValues.With(labels).Add(float64(n))
+}
+
+// AddLateValuesOK increments the number of out of order values staged in the
+// late segment.
+func (t *cacheTracker) AddLateValuesOK(n int) { t.AddLateValues("ok", n) }
+
+// AddLateValuesDrop increments the number of values dropped for being older
+// than the max lateness.
+func (t *cacheTracker) AddLateValuesDrop(n int) { t.AddLateValues("dropped", n) }
+
 // SetSnapshotSize sets the last successful snapshot size.
 func (t *cacheTracker) SetSnapshotSize(sz uint64) { atomic.StoreUint64(&t.snapshotSize, sz) }
 
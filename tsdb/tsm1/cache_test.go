@@ -14,6 +14,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/golang/snappy"
 	"github.com/influxdata/influxdb/v2"
@@ -466,6 +467,113 @@ func TestCache_CacheSnapshot(t *testing.T) {
 }
 
 // Tests that Snapshot updates statistics correctly.
+func TestCache_Late(t *testing.T) {
+	c := NewCache(0)
+	c.SetWatermark(10)
+
+	// Values older than the watermark are staged in the late segment.
+	if err := c.WriteMulti(map[string][]Value{"foo": {NewValue(5, 5.0), NewValue(15, 15.0)}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := c.LateSize(), uint64(16)+3; got != exp {
+		t.Fatalf("late size incorrect: got %v, exp %v", got, exp)
+	}
+	if got, exp := c.Size(), uint64(2*16)+2*3; got != exp {
+		t.Fatalf("cache size incorrect: got %v, exp %v", got, exp)
+	}
+
+	exp := Values{NewValue(5, 5.0), NewValue(15, 15.0)}
+	if got := c.Values([]byte("foo")); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values incorrect: got %v, exp %v", got, exp)
+	}
+
+	// The snapshot raises the watermark to the newest value in order.
+	snapshot, err := c.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := c.Watermark(), int64(15); got != exp {
+		t.Fatalf("watermark incorrect: got %v, exp %v", got, exp)
+	}
+	if got := c.LateSize(); got != 0 {
+		t.Fatalf("late size incorrect after snapshot: got %v, exp 0", got)
+	}
+	if got, exp := snapshot.values([]byte("foo")), (Values{NewValue(15, 15.0)}); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("snapshot values incorrect: got %v, exp %v", got, exp)
+	}
+	if got, exp := snapshot.lateSegment().values([]byte("foo")), (Values{NewValue(5, 5.0)}); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("snapshot late values incorrect: got %v, exp %v", got, exp)
+	}
+
+	// Reads merge the late segments of the cache and the snapshot.
+	if err := c.WriteMulti(map[string][]Value{"foo": {NewValue(12, 12.0), NewValue(20, 20.0)}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := c.LateSize(), uint64(16)+3; got != exp {
+		t.Fatalf("late size incorrect: got %v, exp %v", got, exp)
+	}
+	exp = Values{NewValue(5, 5.0), NewValue(12, 12.0), NewValue(15, 15.0), NewValue(20, 20.0)}
+	if got := c.Values([]byte("foo")); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values incorrect: got %v, exp %v", got, exp)
+	}
+
+	c.ClearSnapshot(true)
+	if got := snapshot.lateSegment(); got != nil {
+		t.Fatalf("snapshot late segment not cleared: %v", got.Keys())
+	}
+
+	// Deletes remove late values too.
+	c.DeleteBucketRange(context.Background(), "foo", 0, 12, nil)
+	if got := c.LateSize(); got != 0 {
+		t.Fatalf("late size incorrect after delete: got %v, exp 0", got)
+	}
+	exp = Values{NewValue(20, 20.0)}
+	if got := c.Values([]byte("foo")); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values incorrect after delete: got %v, exp %v", got, exp)
+	}
+}
+
+func TestCache_Late_WatermarkCapped(t *testing.T) {
+	c := NewCache(0)
+
+	// A point in the future doesn't make every write out of order.
+	future := time.Now().Add(time.Hour).UnixNano()
+	if err := c.WriteMulti(map[string][]Value{"foo": {NewValue(future, 1.0)}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Watermark(); got >= future {
+		t.Fatalf("watermark not capped: got %v", got)
+	}
+}
+
+func TestCache_MaxLateness(t *testing.T) {
+	c := NewCache(0)
+	c.SetMaxLateness(time.Hour)
+
+	now := time.Now().UnixNano()
+	old := time.Now().Add(-2 * time.Hour).UnixNano()
+	err := c.WriteMulti(map[string][]Value{
+		"foo": {NewValue(now, 1.0), NewValue(old, 2.0)},
+		"bar": {NewValue(old, 3.0)},
+	})
+	if exp := (MaxLatenessExceededError{Dropped: 2, MaxLateness: time.Hour}); err != exp {
+		t.Fatalf("unexpected error: got %v, exp %v", err, exp)
+	}
+
+	if got, exp := c.Values([]byte("foo")), (Values{NewValue(now, 1.0)}); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("values incorrect: got %v, exp %v", got, exp)
+	}
+	if got := c.Values([]byte("bar")); got != nil {
+		t.Fatalf("values incorrect: got %v, exp none", got)
+	}
+	if got, exp := c.Size(), uint64(16)+3; got != exp {
+		t.Fatalf("cache size incorrect: got %v, exp %v", got, exp)
+	}
+}
+
 func TestCache_Snapshot_Stats(t *testing.T) {
 	limit := uint64(16)
 	c := NewCache(limit)
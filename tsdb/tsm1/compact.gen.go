@@ -1688,6 +1688,11 @@ func (k *tsmBatchKeyIterator) mergeString() {
 
 	}
 
+	// Blocks of another compression than the one of the key are re-encoded
+	if !dedup {
+		dedup = k.recompressStringBlocks()
+	}
+
 	k.merged = k.combineString(dedup)
 }
 
@@ -1845,7 +1850,7 @@ func (k *tsmBatchKeyIterator) chunkString(dst blocks) blocks {
 		minTime, maxTime := values.Timestamps[0], values.Timestamps[len(values.Timestamps)-1]
 		values.Values = k.mergedStringValues.Values[:k.size]
 
-		cb, err := EncodeStringArrayBlock(&values, nil) // TODO(edd): pool this buffer
+		cb, err := EncodeStringArrayBlockUsing(&values, nil, k.keyStringCompression()) // TODO(edd): pool this buffer
 		if err != nil {
 			k.err = err
 			return nil
@@ -1865,7 +1870,7 @@ func (k *tsmBatchKeyIterator) chunkString(dst blocks) blocks {
 	// Re-encode the remaining values into the last block
 	if k.mergedStringValues.Len() > 0 {
 		minTime, maxTime := k.mergedStringValues.Timestamps[0], k.mergedStringValues.Timestamps[len(k.mergedStringValues.Timestamps)-1]
-		cb, err := EncodeStringArrayBlock(k.mergedStringValues, nil) // TODO(edd): pool this buffer
+		cb, err := EncodeStringArrayBlockUsing(k.mergedStringValues, nil, k.keyStringCompression()) // TODO(edd): pool this buffer
 		if err != nil {
 			k.err = err
 			return nil
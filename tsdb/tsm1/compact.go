@@ -42,6 +42,12 @@ const (
 
 	// TSSFileExtension is the extension used for TSM stats files.
 	TSSFileExtension = "tss"
+
+	// TSBFileExtension is the extension used for TSM block stats files.
+	TSBFileExtension = "tsb"
+
+	// TSVFileExtension is the extension used for TSM value index files.
+	TSVFileExtension = "tsv"
 )
 
 var (
@@ -81,7 +87,15 @@ type CompactionGroup []string
 type CompactionPlanner interface {
 	Plan(lastWrite time.Time) []CompactionGroup
 	PlanLevel(level int) []CompactionGroup
+
+	// PlanLate returns the groups of out of order generations to compact for a
+	// specific level. They are compacted among themselves, so backfills don't
+	// overlap and repeatedly recompact the generations written in order, until
+	// a full compaction.
+	PlanLate(level int) []CompactionGroup
+
 	PlanOptimize() []CompactionGroup
+	PlanTier(before int64) []CompactionGroup
 	Release(group []CompactionGroup)
 	FullyCompacted() bool
 
@@ -179,6 +193,17 @@ func (t *tsmGeneration) level() int {
 	return 4
 }
 
+// maxTime returns the newest point of the files in the generation.
+func (t *tsmGeneration) maxTime() int64 {
+	max := int64(math.MinInt64)
+	for _, f := range t.files {
+		if f.MaxTime > max {
+			max = f.MaxTime
+		}
+	}
+	return max
+}
+
 // count returns the number of files in the generation.
 func (t *tsmGeneration) count() int {
 	return len(t.files)
@@ -219,6 +244,18 @@ func (c *DefaultPlanner) ForceFull() {
 
 // PlanLevel returns a set of TSM files to rewrite for a specific level.
 func (c *DefaultPlanner) PlanLevel(level int) []CompactionGroup {
+	return c.planLevel(level, false)
+}
+
+// PlanLate returns a set of TSM files of out of order generations to rewrite
+// for a specific level.
+func (c *DefaultPlanner) PlanLate(level int) []CompactionGroup {
+	return c.planLevel(level, true)
+}
+
+// planLevel returns a set of TSM files to rewrite for a specific level, of
+// either the generations in order or the out of order generations.
+func (c *DefaultPlanner) planLevel(level int, late bool) []CompactionGroup {
 	// If a full plan has been requested, don't plan any levels which will prevent
 	// the full plan from acquiring them.
 	c.mu.RLock()
@@ -239,6 +276,17 @@ func (c *DefaultPlanner) PlanLevel(level int) []CompactionGroup {
 		return nil
 	}
 
+	// Out of order generations are only compacted with each other. The
+	// generations in use still count when telling which are out of order.
+	outOfOrder := c.findGenerations(false).outOfOrder()
+	var planned tsmGenerations
+	for _, g := range generations {
+		if _, ok := outOfOrder[g.id]; ok == late {
+			planned = append(planned, g)
+		}
+	}
+	generations = planned
+
 	// Group each generation by level such that two adjacent generations in the same
 	// level become part of the same group.
 	var currentGen tsmGenerations
@@ -601,6 +649,43 @@ func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 	return tsmFiles
 }
 
+// PlanTier returns the fully compacted generations whose points are all older
+// than before, to be moved to the storage tier. The files of the returned groups
+// are not planned for compactions until released.
+func (c *DefaultPlanner) PlanTier(before int64) []CompactionGroup {
+	// A full plan has been requested, leave the files to it.
+	c.mu.RLock()
+	if c.forceFull {
+		c.mu.RUnlock()
+		return nil
+	}
+	c.mu.RUnlock()
+
+	var groups []CompactionGroup
+	for _, g := range c.findGenerations(true) {
+		if g.level() < 4 || g.hasTombstones() {
+			continue
+		}
+
+		var group CompactionGroup
+		for _, f := range g.files {
+			if f.Tiered || f.MaxTime >= before {
+				group = nil
+				break
+			}
+			group = append(group, f.Path)
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+
+	if !c.acquire(groups) {
+		return nil
+	}
+	return groups
+}
+
 // findGenerations groups all the TSM files by generation based
 // on their filename, then returns the generations in descending order (newest first).
 // If skipInUse is true, tsm files that are part of an existing compaction plan
@@ -627,6 +712,12 @@ func (c *DefaultPlanner) findGenerations(skipInUse bool) tsmGenerations {
 			continue
 		}
 
+		// Files in the storage tier are not compacted again, unless there are
+		// deletes to apply.
+		if f.Tiered && !f.HasTombstone {
+			continue
+		}
+
 		group := generations[gen]
 		if group == nil {
 			group = newTsmGeneration(gen, c.ParseFileName)
@@ -698,6 +789,16 @@ type Compactor struct {
 	// RateLimit is the limit for disk writes for all concurrent compactions.
 	RateLimit limiter.Rate
 
+	// StringCompression returns the compression of the string blocks of a
+	// series key. Compactions re-encode the string blocks compressed otherwise.
+	// If nil, string blocks are compressed with snappy.
+	StringCompression StringCompressionFunc
+
+	// IndexValues reports whether the values of a series key are indexed in
+	// the value index files written next to the TSM files. If nil, no values
+	// are indexed.
+	IndexValues ValueIndexFunc
+
 	formatFileName FormatFileNameFunc
 	parseFileName  ParseFileNameFunc
 
@@ -844,15 +945,21 @@ func (c *Compactor) WriteSnapshot(ctx context.Context, cache *Cache) ([]string,
 
 	splits := cache.Split(concurrency)
 
+	// The out of order values are written to a generation of their own, so the
+	// files of the values in order don't overlap the time ranges of earlier ones.
+	if late := cache.lateSegment(); late != nil {
+		splits = append(splits, late)
+	}
+
 	type res struct {
 		files []string
 		err   error
 	}
 
-	resC := make(chan res, concurrency)
-	for i := 0; i < concurrency; i++ {
+	resC := make(chan res, len(splits))
+	for i := range splits {
 		go func(sp *Cache) {
-			iter := NewCacheKeyIterator(sp, MaxPointsPerBlock, intC)
+			iter := newCacheKeyIterator(sp, MaxPointsPerBlock, c.StringCompression, intC)
 			files, err := c.writeNewFiles(c.FileStore.NextGeneration(), 0, nil, iter, throttle)
 			resC <- res{files: files, err: err}
 
@@ -860,8 +967,8 @@ func (c *Compactor) WriteSnapshot(ctx context.Context, cache *Cache) ([]string,
 	}
 
 	var err error
-	files := make([]string, 0, concurrency)
-	for i := 0; i < concurrency; i++ {
+	files := make([]string, 0, len(splits))
+	for range splits {
 		result := <-resC
 		if result.err != nil {
 			err = result.err
@@ -941,7 +1048,7 @@ func (c *Compactor) compact(fast bool, tsmFiles []string) ([]string, error) {
 		return nil, nil
 	}
 
-	tsm, err := NewTSMBatchKeyIterator(size, fast, intC, trs...)
+	tsm, err := newTSMBatchKeyIterator(size, fast, c.StringCompression, intC, trs...)
 	if err != nil {
 		return nil, err
 	}
@@ -1036,7 +1143,6 @@ func (c *Compactor) writeNewFiles(generation, sequence int, src []string, iter K
 
 		// New TSM files are written to a temp file and renamed when fully completed.
 		fileName := filepath.Join(c.Dir, c.formatFileName(generation, sequence)+"."+TSMFileExtension+"."+TmpTSMFileExtension)
-		statsFileName := StatsFilename(fileName)
 
 		// Write as much as possible to this file
 		err := c.write(fileName, iter, throttle)
@@ -1051,7 +1157,7 @@ func (c *Compactor) writeNewFiles(generation, sequence int, src []string, iter K
 			// file that we can drop.
 			if err := os.RemoveAll(fileName); err != nil {
 				return nil, err
-			} else if err := os.RemoveAll(statsFileName); err != nil && !os.IsNotExist(err) {
+			} else if err := removeSidecarFiles(fileName); err != nil {
 				return nil, err
 			}
 			break
@@ -1064,14 +1170,14 @@ func (c *Compactor) writeNewFiles(generation, sequence int, src []string, iter K
 			for _, f := range files {
 				if err := os.RemoveAll(f); err != nil {
 					return nil, err
-				} else if err := os.RemoveAll(StatsFilename(f)); err != nil && !os.IsNotExist(err) {
+				} else if err := removeSidecarFiles(f); err != nil {
 					return nil, err
 				}
 			}
 			// We hit an error and didn't finish the compaction.  Remove the temp file and abort.
 			if err := os.RemoveAll(fileName); err != nil {
 				return nil, err
-			} else if err := os.RemoveAll(statsFileName); err != nil && !os.IsNotExist(err) {
+			} else if err := removeSidecarFiles(fileName); err != nil {
 				return nil, err
 			}
 			return nil, err
@@ -1121,6 +1227,7 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 			return err
 		}
 	}
+	w.(*tsmWriter).indexValues = c.IndexValues
 
 	defer func() {
 		closeErr := w.Close()
@@ -1161,8 +1268,14 @@ func (c *Compactor) write(path string, iter KeyIterator, throttle bool) (err err
 			return fmt.Errorf("invalid index entry for block. min=%d, max=%d", minTime, maxTime)
 		}
 
+		// Blocks copied unchanged keep their value statistics and filter.
+		var meta blockMeta
+		if iter, ok := iter.(blockMetaIterator); ok {
+			meta = iter.readBlockMeta()
+		}
+
 		// Write the key and value
-		if err := w.WriteBlock(key, minTime, maxTime, block); err == ErrMaxBlocksExceeded {
+		if err := w.(*tsmWriter).writeBlock(key, minTime, maxTime, block, meta); err == ErrMaxBlocksExceeded {
 			if err := w.WriteIndex(); err != nil {
 				return err
 			}
@@ -1240,6 +1353,14 @@ type KeyIterator interface {
 	EstimatedIndexSize() int
 }
 
+// blockMetaIterator is a KeyIterator that knows the value statistics and filter
+// of the blocks it copies unchanged from TSM files.
+type blockMetaIterator interface {
+	// readBlockMeta returns the value statistics and filter of the block
+	// returned by Read.
+	readBlockMeta() blockMeta
+}
+
 // tsmKeyIterator implements the KeyIterator for set of TSMReaders.  Iteration produces
 // keys in sorted order and the values between the keys sorted and deduped.  If any of
 // the readers have associated tombstone entries, they are returned as part of iteration.
@@ -1298,6 +1419,10 @@ type block struct {
 	b                []byte
 	tombstones       []TimeRange
 
+	// meta is the value statistics and filter of the block in the TSM file it
+	// is read from. Blocks encoded while merging have none.
+	meta blockMeta
+
 	// readMin, readMax are the timestamps range of values have been
 	// read and encoded from this block.
 	readMin, readMax int64
@@ -1439,6 +1564,7 @@ RETRY:
 				// This block may have ranges of time removed from it that would
 				// reduce the block min and max time.
 				blk.tombstones = iter.r.TombstoneRange(key, blk.tombstones[:0])
+				blk.meta = iter.blockMeta()
 
 				blockKey := key
 				for bytes.Equal(iter.PeekNext(), blockKey) {
@@ -1469,6 +1595,7 @@ RETRY:
 					blk.readMin = math.MaxInt64
 					blk.readMax = math.MinInt64
 					blk.tombstones = iter.r.TombstoneRange(key, blk.tombstones[:0])
+					blk.meta = iter.blockMeta()
 				}
 			}
 
@@ -1556,6 +1683,13 @@ func (k *tsmKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
 	return block.key, block.minTime, block.maxTime, block.b, k.err
 }
 
+func (k *tsmKeyIterator) readBlockMeta() blockMeta {
+	if len(k.merged) == 0 {
+		return blockMeta{}
+	}
+	return k.merged[0].meta
+}
+
 func (k *tsmKeyIterator) Close() error {
 	k.values = nil
 	k.pos = nil
@@ -1606,6 +1740,9 @@ type tsmBatchKeyIterator struct {
 	key []byte
 	typ byte
 
+	// stringCompression chooses the compression of the string blocks of each key.
+	stringCompression StringCompressionFunc
+
 	iterators []*BlockIterator
 	blocks    blocks
 
@@ -1627,6 +1764,13 @@ type tsmBatchKeyIterator struct {
 // NewTSMBatchKeyIterator returns a new TSM key iterator from readers.
 // size indicates the maximum number of values to encode in a single block.
 func NewTSMBatchKeyIterator(size int, fast bool, interrupt chan struct{}, readers ...*TSMReader) (KeyIterator, error) {
+	return newTSMBatchKeyIterator(size, fast, nil, interrupt, readers...)
+}
+
+// newTSMBatchKeyIterator returns a new TSM key iterator from readers, which
+// compresses the string blocks it encodes or re-encodes with the compression
+// returned by stringCompression.
+func newTSMBatchKeyIterator(size int, fast bool, stringCompression StringCompressionFunc, interrupt chan struct{}, readers ...*TSMReader) (KeyIterator, error) {
 	var iter []*BlockIterator
 	for _, r := range readers {
 		iter = append(iter, r.BlockIterator())
@@ -1639,6 +1783,7 @@ func NewTSMBatchKeyIterator(size int, fast bool, interrupt chan struct{}, reader
 		size:                 size,
 		iterators:            iter,
 		fast:                 fast,
+		stringCompression:    stringCompression,
 		buf:                  make([]blocks, len(iter)),
 		mergedFloatValues:    &cursors.FloatArray{},
 		mergedIntegerValues:  &cursors.IntegerArray{},
@@ -1649,6 +1794,25 @@ func NewTSMBatchKeyIterator(size int, fast bool, interrupt chan struct{}, reader
 	}, nil
 }
 
+// keyStringCompression returns the compression of the string blocks of the
+// current key.
+func (k *tsmBatchKeyIterator) keyStringCompression() StringCompression {
+	return stringCompressionOf(k.stringCompression, k.key)
+}
+
+// recompressStringBlocks returns true if any of the string blocks of the
+// current key is not compressed with the compression of the key.
+func (k *tsmBatchKeyIterator) recompressStringBlocks() bool {
+	c := k.keyStringCompression()
+	for _, b := range k.blocks {
+		if bc, err := BlockStringCompression(b.b); err != nil || bc != c {
+			// Blocks failing to parse are decoded, which reports the error.
+			return true
+		}
+	}
+	return false
+}
+
 func (k *tsmBatchKeyIterator) hasMergedValues() bool {
 	return k.mergedFloatValues.Len() > 0 ||
 		k.mergedIntegerValues.Len() > 0 ||
@@ -1728,6 +1892,7 @@ RETRY:
 			// This block may have ranges of time removed from it that would
 			// reduce the block min and max time.
 			blk.tombstones = iter.r.TombstoneRange(key, blk.tombstones[:0])
+			blk.meta = iter.blockMeta()
 
 			blockKey := key
 			for bytes.Equal(iter.PeekNext(), blockKey) {
@@ -1758,6 +1923,7 @@ RETRY:
 				blk.readMin = math.MaxInt64
 				blk.readMax = math.MinInt64
 				blk.tombstones = iter.r.TombstoneRange(key, blk.tombstones[:0])
+				blk.meta = iter.blockMeta()
 			}
 		}
 
@@ -1844,6 +2010,13 @@ func (k *tsmBatchKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
 	return block.key, block.minTime, block.maxTime, block.b, k.err
 }
 
+func (k *tsmBatchKeyIterator) readBlockMeta() blockMeta {
+	if len(k.merged) == 0 {
+		return blockMeta{}
+	}
+	return k.merged[0].meta
+}
+
 func (k *tsmBatchKeyIterator) Close() error {
 	k.values = nil
 	k.pos = nil
@@ -1866,6 +2039,9 @@ type cacheKeyIterator struct {
 	size  int
 	order [][]byte
 
+	// stringCompression chooses the compression of the string blocks of each key.
+	stringCompression StringCompressionFunc
+
 	i         int
 	blocks    [][]cacheBlock
 	ready     []chan struct{}
@@ -1882,6 +2058,12 @@ type cacheBlock struct {
 
 // NewCacheKeyIterator returns a new KeyIterator from a Cache.
 func NewCacheKeyIterator(cache *Cache, size int, interrupt chan struct{}) KeyIterator {
+	return newCacheKeyIterator(cache, size, nil, interrupt)
+}
+
+// newCacheKeyIterator returns a new KeyIterator from a Cache, which compresses
+// string blocks with the compression returned by stringCompression.
+func newCacheKeyIterator(cache *Cache, size int, stringCompression StringCompressionFunc, interrupt chan struct{}) KeyIterator {
 	keys := cache.Keys()
 
 	chans := make([]chan struct{}, len(keys))
@@ -1890,13 +2072,14 @@ func NewCacheKeyIterator(cache *Cache, size int, interrupt chan struct{}) KeyIte
 	}
 
 	cki := &cacheKeyIterator{
-		i:         -1,
-		size:      size,
-		cache:     cache,
-		order:     keys,
-		ready:     chans,
-		blocks:    make([][]cacheBlock, len(keys)),
-		interrupt: interrupt,
+		i:                 -1,
+		size:              size,
+		cache:             cache,
+		order:             keys,
+		stringCompression: stringCompression,
+		ready:             chans,
+		blocks:            make([][]cacheBlock, len(keys)),
+		interrupt:         interrupt,
 	}
 	go cki.encode()
 	return cki
@@ -1966,6 +2149,7 @@ func (c *cacheKeyIterator) encode() {
 					case BooleanValue:
 						b, err = encodeBooleanBlockUsing(nil, values[:end], tenc, benc)
 					case StringValue:
+						senc.SetCompression(stringCompressionOf(c.stringCompression, key))
 						b, err = encodeStringBlockUsing(nil, values[:end], tenc, senc)
 					default:
 						b, err = Values(values[:end]).Encode(nil)
@@ -2055,6 +2239,23 @@ func (a tsmGenerations) level() int {
 	return level
 }
 
+// outOfOrder returns the ids of the out of order generations, of the generations
+// ordered by id. A generation is out of order when its newest point is older
+// than the newest point of an older generation, as is the case for the late
+// segments of cache snapshots.
+func (a tsmGenerations) outOfOrder() map[int]struct{} {
+	late := make(map[int]struct{})
+	maxTime := int64(math.MinInt64)
+	for _, g := range a {
+		if max := g.maxTime(); max < maxTime {
+			late[g.id] = struct{}{}
+		} else {
+			maxTime = max
+		}
+	}
+	return late
+}
+
 func (a tsmGenerations) chunk(size int) []tsmGenerations {
 	var chunks []tsmGenerations
 	for len(a) > 0 {
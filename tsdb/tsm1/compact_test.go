@@ -2,12 +2,15 @@ package tsm1_test
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -262,6 +265,199 @@ func TestCompactor_CompactFull(t *testing.T) {
 	}
 }
 
+// Ensures that compactions re-encode string blocks with the compression of
+// their key.
+func TestCompactor_CompactFull_StringCompression(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	// Write the blocks of both keys with snappy, in non-overlapping files so
+	// that the blocks would otherwise be combined without decoding them.
+	a1 := tsm1.NewValue(1, "a1")
+	b1 := tsm1.NewValue(1, "b1")
+	f1 := MustWriteTSM(dir, 1, map[string][]tsm1.Value{
+		"log,host=A#!~#msg": {a1},
+		"log,host=B#!~#msg": {b1},
+	})
+
+	a2 := tsm1.NewValue(2, "a2")
+	b2 := tsm1.NewValue(2, "b2")
+	f2 := MustWriteTSM(dir, 2, map[string][]tsm1.Value{
+		"log,host=A#!~#msg": {a2},
+		"log,host=B#!~#msg": {b2},
+	})
+
+	fs := &fakeFileStore{}
+	defer fs.Close()
+	compactor := tsm1.NewCompactor()
+	compactor.Dir = dir
+	compactor.FileStore = fs
+	compactor.StringCompression = func(key []byte) tsm1.StringCompression {
+		if bytes.HasPrefix(key, []byte("log,host=A")) {
+			return tsm1.StringCompressionZstd
+		}
+		return tsm1.StringCompressionSnappy
+	}
+	compactor.Open()
+
+	files, err := compactor.CompactFast([]string{f1, f2})
+	if err != nil {
+		t.Fatalf("unexpected error compacting: %v", err)
+	}
+
+	if got, exp := len(files), 1; got != exp {
+		t.Fatalf("files length mismatch: got %v, exp %v", got, exp)
+	}
+
+	r := MustOpenTSMReader(files[0])
+	defer r.Close()
+
+	var data = []struct {
+		key         string
+		points      []tsm1.Value
+		compression tsm1.StringCompression
+	}{
+		{"log,host=A#!~#msg", []tsm1.Value{a1, a2}, tsm1.StringCompressionZstd},
+		{"log,host=B#!~#msg", []tsm1.Value{b1, b2}, tsm1.StringCompressionSnappy},
+	}
+
+	for _, p := range data {
+		values, err := r.ReadAll([]byte(p.key))
+		if err != nil {
+			t.Fatalf("unexpected error reading: %v", err)
+		}
+
+		if got, exp := len(values), len(p.points); got != exp {
+			t.Fatalf("values length mismatch %s: got %v, exp %v", p.key, got, exp)
+		}
+
+		for i, point := range p.points {
+			assertValueEqual(t, values[i], point)
+		}
+
+		entries, err := r.ReadEntries([]byte(p.key), nil)
+		if err != nil {
+			t.Fatalf("unexpected error reading entries: %v", err)
+		}
+		for _, e := range entries {
+			_, b, err := r.ReadBytes(&e, nil)
+			if err != nil {
+				t.Fatalf("unexpected error reading block: %v", err)
+			}
+			if got, err := tsm1.BlockStringCompression(b); err != nil {
+				t.Fatalf("unexpected error reading block compression: %v", err)
+			} else if got != p.compression {
+				t.Fatalf("compression mismatch %s: got %v, exp %v", p.key, got, p.compression)
+			}
+		}
+	}
+}
+
+// Ensures that a snapshot encodes string blocks with the compression of their
+// key.
+func TestCompactor_Snapshot_StringCompression(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	v1 := tsm1.NewValue(1, "v1")
+	v2 := tsm1.NewValue(2, "v2")
+
+	c := tsm1.NewCache(0)
+	if err := c.Write([]byte("log,host=A#!~#msg"), []tsm1.Value{v1, v2}); err != nil {
+		t.Fatalf("failed to write key to cache: %v", err)
+	}
+
+	compactor := tsm1.NewCompactor()
+	compactor.Dir = dir
+	compactor.FileStore = &fakeFileStore{}
+	compactor.StringCompression = func([]byte) tsm1.StringCompression {
+		return tsm1.StringCompressionZstd
+	}
+	compactor.Open()
+
+	files, err := compactor.WriteSnapshot(context.Background(), c)
+	if err != nil {
+		t.Fatalf("unexpected error writing snapshot: %v", err)
+	}
+
+	if got, exp := len(files), 1; got != exp {
+		t.Fatalf("files length mismatch: got %v, exp %v", got, exp)
+	}
+
+	r := MustOpenTSMReader(files[0])
+	defer r.Close()
+
+	values, err := r.ReadAll([]byte("log,host=A#!~#msg"))
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if got, exp := len(values), 2; got != exp {
+		t.Fatalf("values length mismatch: got %v, exp %v", got, exp)
+	}
+	assertValueEqual(t, values[0], v1)
+	assertValueEqual(t, values[1], v2)
+
+	entries, err := r.ReadEntries([]byte("log,host=A#!~#msg"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error reading entries: %v", err)
+	}
+	_, b, err := r.ReadBytes(&entries[0], nil)
+	if err != nil {
+		t.Fatalf("unexpected error reading block: %v", err)
+	}
+	if got, err := tsm1.BlockStringCompression(b); err != nil {
+		t.Fatalf("unexpected error reading block compression: %v", err)
+	} else if got != tsm1.StringCompressionZstd {
+		t.Fatalf("compression mismatch: got %v, exp %v", got, tsm1.StringCompressionZstd)
+	}
+}
+
+// Ensures that the out of order values of a snapshot are written to a generation of their own.
+func TestCompactor_Snapshot_Late(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	v1 := tsm1.NewValue(5, 1.1)
+	v2 := tsm1.NewValue(15, 2.1)
+
+	c := tsm1.NewCache(0)
+	c.SetWatermark(10)
+	if err := c.Write([]byte("cpu,host=A#!~#value"), []tsm1.Value{v1, v2}); err != nil {
+		t.Fatalf("failed to write key to cache: %v", err)
+	}
+
+	snapshot, err := c.Snapshot()
+	if err != nil {
+		t.Fatalf("unexpected error taking snapshot: %v", err)
+	}
+
+	compactor := tsm1.NewCompactor()
+	compactor.Dir = dir
+	compactor.FileStore = &generationFileStore{}
+	compactor.Open()
+
+	files, err := compactor.WriteSnapshot(context.Background(), snapshot)
+	if err != nil {
+		t.Fatalf("unexpected error writing snapshot: %v", err)
+	}
+
+	if got, exp := len(files), 2; got != exp {
+		t.Fatalf("files length mismatch: got %v, exp %v", got, exp)
+	}
+
+	var ranges [][2]int64
+	for _, f := range files {
+		r := MustOpenTSMReader(f)
+		min, max := r.TimeRange()
+		r.Close()
+		ranges = append(ranges, [2]int64{min, max})
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	if exp := [][2]int64{{5, 5}, {15, 15}}; !reflect.DeepEqual(ranges, exp) {
+		t.Fatalf("time ranges mismatch: got %v, exp %v", ranges, exp)
+	}
+}
+
 // Ensures that a compaction will properly merge multiple TSM files
 func TestCompactor_Compact_OverlappingBlocks(t *testing.T) {
 	dir := MustTempDir()
@@ -1767,6 +1963,82 @@ func TestDefaultPlanner_PlanLevel_SmallestCompactionStep(t *testing.T) {
 	}
 }
 
+func TestDefaultPlanner_PlanLate(t *testing.T) {
+	data := []tsm1.FileStat{
+		{Path: "01-02.tsm1", MinTime: 0, MaxTime: 10},
+		{Path: "02-02.tsm1", MinTime: 10, MaxTime: 20},
+		// Out of order, older than the newest point of generation 2.
+		{Path: "03-02.tsm1", MinTime: 1, MaxTime: 5},
+		{Path: "04-02.tsm1", MinTime: 20, MaxTime: 30},
+		{Path: "05-02.tsm1", MinTime: 30, MaxTime: 40},
+		{Path: "06-02.tsm1", MinTime: 2, MaxTime: 8},
+		{Path: "07-02.tsm1", MinTime: 12, MaxTime: 18},
+		{Path: "08-02.tsm1", MinTime: 0, MaxTime: 39},
+	}
+
+	cp := tsm1.NewDefaultPlanner(
+		&fakeFileStore{
+			PathsFn: func() []tsm1.FileStat {
+				return data
+			},
+		}, tsm1.DefaultCompactFullWriteColdDuration,
+	)
+
+	// The generations in order are compacted without the out of order ones.
+	groups := cp.PlanLevel(2)
+	exp := []tsm1.CompactionGroup{{"01-02.tsm1", "02-02.tsm1", "04-02.tsm1", "05-02.tsm1"}}
+	if diff := cmp.Diff(exp, groups); diff != "" {
+		t.Fatalf("unexpected level groups: -exp/+got\n%s", diff)
+	}
+
+	groups = cp.PlanLate(2)
+	exp = []tsm1.CompactionGroup{{"03-02.tsm1", "06-02.tsm1", "07-02.tsm1", "08-02.tsm1"}}
+	if diff := cmp.Diff(exp, groups); diff != "" {
+		t.Fatalf("unexpected late groups: -exp/+got\n%s", diff)
+	}
+
+	if groups := cp.PlanLate(1); len(groups) != 0 {
+		t.Fatalf("unexpected late groups for level 1: got %v, exp none", groups)
+	}
+}
+
+func TestDefaultPlanner_PlanTier(t *testing.T) {
+	data := []tsm1.FileStat{
+		// Fully compacted and cold.
+		{Path: "01-04.tsm1", MaxTime: 10},
+		{Path: "01-05.tsm1", MaxTime: 20},
+		// Fully compacted, with a point too recent.
+		{Path: "02-04.tsm1", MaxTime: 50},
+		{Path: "02-05.tsm1", MaxTime: 150},
+		// Already tiered.
+		{Path: "03-04.tsm1", MaxTime: 10, Tiered: true},
+		// Deletes to compact first.
+		{Path: "04-04.tsm1", MaxTime: 10, HasTombstone: true},
+		// Not fully compacted.
+		{Path: "05-02.tsm1", MaxTime: 10},
+	}
+
+	cp := tsm1.NewDefaultPlanner(
+		&fakeFileStore{
+			PathsFn: func() []tsm1.FileStat {
+				return data
+			},
+		}, tsm1.DefaultCompactFullWriteColdDuration,
+	)
+
+	groups := cp.PlanTier(100)
+	exp := []tsm1.CompactionGroup{{"01-04.tsm1", "01-05.tsm1"}}
+	if diff := cmp.Diff(exp, groups); diff != "" {
+		t.Fatalf("unexpected groups: -exp/+got\n%s", diff)
+	}
+
+	// The files are not planned again until released.
+	if groups := cp.PlanTier(100); len(groups) != 0 {
+		t.Fatalf("unexpected groups: got %v, exp none", groups)
+	}
+	cp.Release(groups)
+}
+
 func TestDefaultPlanner_PlanLevel_SplitFile(t *testing.T) {
 	data := []tsm1.FileStat{
 		{
@@ -3021,3 +3293,13 @@ func (w *fakeFileStore) Close() {
 func (w *fakeFileStore) ParseFileName(path string) (int, int, error) {
 	return tsm1.DefaultParseFileName(path)
 }
+
+// generationFileStore is a fakeFileStore that returns a new generation each time.
+type generationFileStore struct {
+	fakeFileStore
+	generation int64
+}
+
+func (w *generationFileStore) NextGeneration() int {
+	return int(atomic.AddInt64(&w.generation, 1))
+}
@@ -1,6 +1,8 @@
 package tsm1
 
 import (
+	"path"
+	"path/filepath"
 	"runtime"
 	"time"
 
@@ -35,6 +37,7 @@ type Config struct {
 
 	Compaction CompactionConfig `toml:"compaction"`
 	Cache      CacheConfig      `toml:"cache"`
+	Tier       TierConfig       `toml:"tier"`
 }
 
 // NewConfig constructs a Config with the default values.
@@ -45,6 +48,7 @@ func NewConfig() Config {
 		LargeSeriesWriteThreshold: DefaultLargeSeriesWriteThreshold,
 
 		Cache: NewCacheConfig(),
+		Tier:  NewTierConfig(),
 		Compaction: CompactionConfig{
 			FullWriteColdDuration: toml.Duration(DefaultCompactFullWriteColdDuration),
 			Throughput:            toml.Size(DefaultCompactThroughput),
@@ -90,6 +94,8 @@ const (
 	DefaultCacheSnapshotMemorySize        = toml.Size(25 << 20)             // 25MB
 	DefaultCacheSnapshotAgeDuration       = toml.Duration(0)                // Defaults to off.
 	DefaultCacheSnapshotWriteColdDuration = toml.Duration(10 * time.Minute) // Ten minutes
+	DefaultCacheLateSnapshotMemorySize    = toml.Size(8 << 20)              // 8MB
+	DefaultCacheMaxLateness               = toml.Duration(0)                // Defaults to off.
 )
 
 // CacheConfig holds all of the configuration for the in memory cache of values that
@@ -113,6 +119,15 @@ type CacheConfig struct {
 	//
 	// SnapshotWriteColdDuration should not be larger than SnapshotAgeDuration
 	SnapshotWriteColdDuration toml.Duration `toml:"snapshot-write-cold-duration"`
+
+	// LateSnapshotMemorySize is the size of the out of order values, older than
+	// the newest point written to TSM files, at which the engine will snapshot the
+	// cache. The out of order values are written to TSM files of their own.
+	LateSnapshotMemorySize toml.Size `toml:"late-snapshot-memory-size"`
+
+	// MaxLateness, when set, is how old points can be before writes of them are
+	// rejected.
+	MaxLateness toml.Duration `toml:"max-lateness"`
 }
 
 // NewCacheConfig initialises a new CacheConfig with default values.
@@ -122,13 +137,84 @@ func NewCacheConfig() CacheConfig {
 		SnapshotMemorySize:        DefaultCacheSnapshotMemorySize,
 		SnapshotAgeDuration:       DefaultCacheSnapshotAgeDuration,
 		SnapshotWriteColdDuration: DefaultCacheSnapshotWriteColdDuration,
+		LateSnapshotMemorySize:    DefaultCacheLateSnapshotMemorySize,
+		MaxLateness:               DefaultCacheMaxLateness,
 	}
 }
 
+// Default storage tier configuration values.
+const (
+	DefaultTierAge                = toml.Duration(30 * 24 * time.Hour) // 30 days
+	DefaultTierCheckInterval      = toml.Duration(time.Hour)
+	DefaultTierCacheMaxMemorySize = toml.Size(64 << 20) // 64MB
+	DefaultTierS3Region           = "us-east-1"
+)
+
+// TierConfig holds the configuration of the storage tier that cold TSM files are
+// moved to. Tiering is enabled by setting either a directory or an S3 bucket.
+type TierConfig struct {
+	// Age is how old the newest point of a fully compacted TSM file must be
+	// before the file is moved to the tier.
+	Age toml.Duration `toml:"age"`
+
+	// CheckInterval is the interval at which the engine looks for files to move.
+	CheckInterval toml.Duration `toml:"check-interval"`
+
+	// CacheMaxMemorySize is the maximum size of the blocks read from the tier
+	// that are cached in memory.
+	CacheMaxMemorySize toml.Size `toml:"cache-max-memory-size"`
+
+	// Dir moves the files to a directory, typically on a cheaper disk.
+	Dir string `toml:"dir"`
+
+	// S3 moves the files to a bucket of an S3 compatible object store.
+	S3 S3TierConfig `toml:"s3"`
+}
+
+// NewTierConfig initialises a new TierConfig with default values.
+func NewTierConfig() TierConfig {
+	return TierConfig{
+		Age:                DefaultTierAge,
+		CheckInterval:      DefaultTierCheckInterval,
+		CacheMaxMemorySize: DefaultTierCacheMaxMemorySize,
+		S3: S3TierConfig{
+			Region: DefaultTierS3Region,
+		},
+	}
+}
+
+// WithPrefix returns the configuration of the same storage tier, keeping the
+// objects under prefix: in a subdirectory of Dir, or under a longer S3 prefix.
+func (c TierConfig) WithPrefix(prefix string) TierConfig {
+	if c.Dir != "" {
+		c.Dir = filepath.Join(c.Dir, prefix)
+	}
+	if c.S3.Bucket != "" {
+		c.S3.Prefix = path.Join(c.S3.Prefix, prefix)
+	}
+	return c
+}
+
+// S3TierConfig holds the configuration of an S3 compatible storage tier.
+type S3TierConfig struct {
+	// Endpoint is the base URL of the object store, such as https://s3.amazonaws.com.
+	Endpoint string `toml:"endpoint"`
+
+	Region string `toml:"region"`
+	Bucket string `toml:"bucket"`
+
+	// Prefix is prepended to the names of the objects in the bucket.
+	Prefix string `toml:"prefix"`
+
+	AccessKeyID     string `toml:"access-key-id"`
+	SecretAccessKey string `toml:"secret-access-key"`
+}
+
 // Default WAL configuration values.
 const (
 	DefaultWALEnabled    = true
 	DefaultWALFsyncDelay = time.Duration(0)
+	DefaultWALDurability = "group"
 )
 
 // WALConfig holds all of the configuration about the WAL.
@@ -141,11 +227,18 @@ type WALConfig struct {
 	// useful for slower disks or when WAL write contention is seen.  A value of 0 fsyncs
 	// every write to the WAL.
 	FsyncDelay toml.Duration `toml:"fsync-delay"`
+
+	// Durability is the default durability of the writes to the WAL: "sync"
+	// fsyncs every write on its own, "group" shares the fsyncs of the writes
+	// made within the fsync delay and "async" returns before the fsync.
+	// Buckets may set their own durability.
+	Durability string `toml:"durability"`
 }
 
 func NewWALConfig() WALConfig {
 	return WALConfig{
 		Enabled:    DefaultWALEnabled,
 		FsyncDelay: toml.Duration(DefaultWALFsyncDelay),
+		Durability: DefaultWALDurability,
 	}
 }
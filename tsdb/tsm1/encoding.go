@@ -855,6 +855,7 @@ func putFloatEncoder(enc *FloatEncoder) { floatEncoderPool.Put(enc) }
 func getStringEncoder(sz int) StringEncoder {
 	x := stringEncoderPool.Get(sz).(StringEncoder)
 	x.Reset()
+	x.SetCompression(StringCompressionSnappy)
 	return x
 }
 func putStringEncoder(enc StringEncoder) { stringEncoderPool.Put(enc) }
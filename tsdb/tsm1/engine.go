@@ -146,6 +146,11 @@ type Engine struct {
 	// the cache when the engine should write a snapshot to a TSM file
 	CacheFlushMemorySizeThreshold uint64
 
+	// CacheFlushLateSizeThreshold specifies the minimum size threshold for the
+	// out of order values staged in the cache when the engine should write a
+	// snapshot to TSM files
+	CacheFlushLateSizeThreshold uint64
+
 	// CacheFlushAgeDurationThreshold specified the maximum age a cache can reach
 	// before it is snapshotted, regardless of its size.
 	CacheFlushAgeDurationThreshold time.Duration
@@ -174,6 +179,9 @@ type Engine struct {
 
 	scheduler   *scheduler
 	snapshotter Snapshotter
+
+	// The storage tier that cold TSM files are moved to.
+	tierConfig TierConfig
 }
 
 // NewEngine returns a new instance of Engine.
@@ -183,6 +191,7 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 	fs.tsmMMAPWillNeed = config.MADVWillNeed
 
 	cache := NewCache(uint64(config.Cache.MaxMemorySize))
+	cache.SetMaxLateness(time.Duration(config.Cache.MaxLateness))
 
 	c := NewCompactor()
 	c.Dir = path
@@ -226,6 +235,7 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 			time.Duration(config.Compaction.FullWriteColdDuration)),
 
 		CacheFlushMemorySizeThreshold:  uint64(config.Cache.SnapshotMemorySize),
+		CacheFlushLateSizeThreshold:    uint64(config.Cache.LateSnapshotMemorySize),
 		CacheFlushWriteColdDuration:    time.Duration(config.Cache.SnapshotWriteColdDuration),
 		CacheFlushAgeDurationThreshold: time.Duration(config.Cache.SnapshotAgeDuration),
 		enableCompactionsOnOpen:        true,
@@ -234,6 +244,7 @@ func NewEngine(path string, idx *tsi1.Index, config Config, options ...EngineOpt
 		fullCompactionSemaphore:        influxdb.NopSemaphore,
 		scheduler:                      newScheduler(maxCompactions),
 		snapshotter:                    new(noSnapshotter),
+		tierConfig:                     config.Tier,
 	}
 
 	for _, option := range options {
@@ -255,6 +266,11 @@ func (e *Engine) WithCompactionLimiter(limiter limiter.Fixed) {
 	e.compactionLimiter = limiter
 }
 
+// CompactionLimiter returns the compaction limiter of the engine.
+func (e *Engine) CompactionLimiter() limiter.Fixed {
+	return e.compactionLimiter
+}
+
 func (e *Engine) WithFormatFileNameFunc(formatFileNameFunc FormatFileNameFunc) {
 	e.Compactor.WithFormatFileNameFunc(formatFileNameFunc)
 	e.formatFileName = formatFileNameFunc
@@ -278,6 +294,18 @@ func (e *Engine) WithCompactionPlanner(planner CompactionPlanner) {
 	e.CompactionPlan = planner
 }
 
+// WithStringCompressionFunc sets the function choosing the compression of the
+// string blocks of each series key. It must be called before the Engine is opened.
+func (e *Engine) WithStringCompressionFunc(fn StringCompressionFunc) {
+	e.Compactor.StringCompression = fn
+}
+
+// WithValueIndexFunc sets the function reporting whether the values of each
+// series key are indexed. It must be called before the Engine is opened.
+func (e *Engine) WithValueIndexFunc(fn ValueIndexFunc) {
+	e.Compactor.IndexValues = fn
+}
+
 // SetDefaultMetricLabels sets the default labels for metrics on the engine.
 // It must be called before the Engine is opened.
 func (e *Engine) SetDefaultMetricLabels(labels prometheus.Labels) {
@@ -336,6 +364,11 @@ func (e *Engine) enableLevelCompactions(wait bool) {
 	e.mu.Unlock()
 
 	go func() { defer wg.Done(); e.compact(wg) }()
+
+	if e.FileStore.tierStore != nil {
+		wg.Add(1)
+		go func() { defer wg.Done(); e.tier() }()
+	}
 }
 
 // disableLevelCompactions will stop level compactions before returning.
@@ -520,6 +553,7 @@ func (e *Engine) initTrackers() {
 	e.FileStore.tracker = newFileTracker(bms.fileMetrics, e.defaultMetricLabels)
 	e.Cache.tracker = newCacheTracker(bms.cacheMetrics, e.defaultMetricLabels)
 	e.readTracker = newReadTracker(bms.readMetrics, e.defaultMetricLabels)
+	e.FileStore.tierTracker = newTierTracker(bms.tierMetrics, e.defaultMetricLabels)
 
 	e.scheduler.setCompactionTracker(e.compactionTracker)
 }
@@ -551,6 +585,15 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 		return err
 	}
 
+	if e.FileStore.tierStore == nil {
+		store, err := NewTierStore(e.tierConfig)
+		if err != nil {
+			return err
+		} else if store != nil {
+			e.FileStore.WithTierStore(store, uint64(e.tierConfig.CacheMaxMemorySize))
+		}
+	}
+
 	if err := e.cleanup(); err != nil {
 		return err
 	}
@@ -559,6 +602,11 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 		return err
 	}
 
+	// Writes older than the newest point on disk are out of order.
+	for _, f := range e.FileStore.Stats() {
+		e.Cache.SetWatermark(f.MaxTime)
+	}
+
 	e.Compactor.Open()
 
 	if e.enableCompactionsOnOpen {
@@ -933,19 +981,21 @@ type CacheStatus int
 
 // Possible types of Cache status
 const (
-	CacheStatusOkay           CacheStatus = iota // Cache is Okay - do not snapshot.
-	CacheStatusSizeExceeded                      // The cache is large enough to be snapshotted.
-	CacheStatusAgeExceeded                       // The cache is past the age threshold to be snapshotted.
-	CacheStatusColdNoWrites                      // The cache has not been written to for long enough that it should be snapshotted.
-	CacheStatusRetention                         // The cache was snapshotted before running retention.
-	CacheStatusFullCompaction                    // The cache was snapshotted as part of a full compaction.
-	CacheStatusBackup                            // The cache was snapshotted before running backup.
+	CacheStatusOkay             CacheStatus = iota // Cache is Okay - do not snapshot.
+	CacheStatusSizeExceeded                        // The cache is large enough to be snapshotted.
+	CacheStatusAgeExceeded                         // The cache is past the age threshold to be snapshotted.
+	CacheStatusColdNoWrites                        // The cache has not been written to for long enough that it should be snapshotted.
+	CacheStatusRetention                           // The cache was snapshotted before running retention.
+	CacheStatusFullCompaction                      // The cache was snapshotted as part of a full compaction.
+	CacheStatusBackup                              // The cache was snapshotted before running backup.
+	CacheStatusLateSizeExceeded                    // The out of order values staged in the cache are large enough to be snapshotted.
 )
 
 // ShouldCompactCache returns a status indicating if the Cache should be
 // snapshotted. There are three situations when the cache should be snapshotted:
 //
 // - the Cache size is over its flush size threshold;
+// - the out of order values staged in the Cache are over their flush size threshold;
 // - the Cache has not been snapshotted for longer than its flush time threshold; or
 // - the Cache has not been written since the write cold threshold.
 //
@@ -960,6 +1010,11 @@ func (e *Engine) ShouldCompactCache(t time.Time) CacheStatus {
 		return CacheStatusSizeExceeded
 	}
 
+	// Out of order values are snapshotted early to bound their cost in the cache.
+	if e.CacheFlushLateSizeThreshold > 0 && e.Cache.LateSize() > e.CacheFlushLateSizeThreshold {
+		return CacheStatusLateSizeExceeded
+	}
+
 	// Cache is now old enough to snapshot, regardless of last write or age.
 	if e.CacheFlushAgeDurationThreshold > 0 && e.Cache.Age() > e.CacheFlushAgeDurationThreshold {
 		return CacheStatusAgeExceeded
@@ -1000,10 +1055,11 @@ func (e *Engine) compact(wg *sync.WaitGroup) {
 
 			span, ctx := tracing.StartSpanFromContext(context.Background())
 
-			// Find our compaction plans
-			level1Groups := e.CompactionPlan.PlanLevel(1)
-			level2Groups := e.CompactionPlan.PlanLevel(2)
-			level3Groups := e.CompactionPlan.PlanLevel(3)
+			// Find our compaction plans. The out of order generations are
+			// compacted among themselves at each level.
+			level1Groups := append(e.CompactionPlan.PlanLevel(1), e.CompactionPlan.PlanLate(1)...)
+			level2Groups := append(e.CompactionPlan.PlanLevel(2), e.CompactionPlan.PlanLate(2)...)
+			level3Groups := append(e.CompactionPlan.PlanLevel(3), e.CompactionPlan.PlanLate(3)...)
 			level4Groups := e.CompactionPlan.Plan(e.lastModified())
 			e.compactionTracker.SetOptimiseQueue(uint64(len(level4Groups)))
 
@@ -1061,6 +1117,58 @@ func (e *Engine) compact(wg *sync.WaitGroup) {
 	}
 }
 
+// tier periodically moves the cold, fully compacted TSM files to the storage tier.
+func (e *Engine) tier() {
+	interval := time.Duration(e.tierConfig.CheckInterval)
+	if interval <= 0 {
+		interval = time.Duration(DefaultTierCheckInterval)
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	e.mu.RLock()
+	quit := e.done
+	e.mu.RUnlock()
+
+	// Abort moves in progress when compactions are disabled.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case <-quit:
+			return
+
+		case <-t.C:
+			before := time.Now().Add(-time.Duration(e.tierConfig.Age)).UnixNano()
+			if err := e.tierColdFiles(ctx, before); err != nil && ctx.Err() == nil {
+				e.logger.Warn("Error moving files to the storage tier", zap.Error(err))
+			}
+		}
+	}
+}
+
+// tierColdFiles moves the fully compacted TSM files whose points are all older
+// than before to the storage tier.
+func (e *Engine) tierColdFiles(ctx context.Context, before int64) error {
+	groups := e.CompactionPlan.PlanTier(before)
+	defer e.CompactionPlan.Release(groups)
+
+	for _, group := range groups {
+		if err := e.FileStore.Tier(ctx, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // compactHiPriorityLevel kicks off compactions using the high priority policy. It returns
 // true if the compaction was started
 func (e *Engine) compactHiPriorityLevel(ctx context.Context, grp CompactionGroup, level compactionLevel, fast bool, wg *sync.WaitGroup) bool {
@@ -1356,6 +1464,18 @@ func (e *Engine) cleanupTempTSMFiles() error {
 			return fmt.Errorf("error removing temp compaction files: %v", err)
 		}
 	}
+
+	// Tiered files being removed are renamed like local files.
+	markers, err := filepath.Glob(filepath.Join(e.path, fmt.Sprintf("*.%s.%s", CompactionTempExtension, TierTSMFileExtension)))
+	if err != nil {
+		return fmt.Errorf("error getting temp tier markers: %s", err.Error())
+	}
+
+	for _, f := range markers {
+		if err := e.FileStore.removeTierMarker(f); err != nil {
+			return fmt.Errorf("error removing temp tier markers: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -269,14 +269,14 @@ func (e *Engine) DeletePrefixRange(rootCtx context.Context, name []byte, min, ma
 		batch := make([]tsi1.DropSeriesItem, 0, batchSize)
 		ids := make([]tsdb.SeriesID, 0, batchSize)
 		for i := 0; i < len(possiblyDeadKeysSlice); i += batchSize {
-			isLastBatch := i+batchSize > len(possiblyDeadKeysSlice)
+			isLastBatch := i+batchSize >= len(possiblyDeadKeysSlice)
 			batch, ids = batch[:0], ids[:0]
 
-			for j := 0; (i*batchSize)+j < len(possiblyDeadKeysSlice) && j < batchSize; j++ {
+			for j := 0; i+j < len(possiblyDeadKeysSlice) && j < batchSize; j++ {
 				var item tsi1.DropSeriesItem
 
 				// TODO(jeff): ugh reduce copies here
-				key := possiblyDeadKeysSlice[(i*batchSize)+j]
+				key := possiblyDeadKeysSlice[i+j]
 				item.Key = []byte(key)
 				item.Key, _ = SeriesAndFieldFromCompositeKey(item.Key)
 
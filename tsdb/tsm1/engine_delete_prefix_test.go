@@ -121,6 +121,51 @@ func TestEngine_DeletePrefix(t *testing.T) {
 	}
 }
 
+func TestEngine_DeletePrefix_ManySeries(t *testing.T) {
+	e, err := NewEngine(tsm1.NewConfig(), t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	// Write more series than are dropped from the index in a single batch.
+	const seriesN = 2500
+	points := make([]models.Point, 0, seriesN)
+	for i := 0; i < seriesN; i++ {
+		points = append(points, MustParsePointString(fmt.Sprintf("cpu,host=%d value=1 %d", i, i%5+1), "mm0"))
+	}
+	if err := e.writePoints(points...); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+	if err := e.WriteSnapshot(context.Background(), tsm1.CacheStatusColdNoWrites); err != nil {
+		t.Fatalf("failed to snapshot: %s", err.Error())
+	}
+
+	if err := e.DeletePrefixRange(context.Background(), []byte("mm0"), 0, 9, nil); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+
+	// All series should be dropped from the index and the series file.
+	iter, err := e.index.MeasurementSeriesIDIterator([]byte("mm0"))
+	if err != nil {
+		t.Fatalf("iterator error: %v", err)
+	}
+	if iter != nil {
+		defer iter.Close()
+		if elem, err := iter.Next(); err != nil {
+			t.Fatal(err)
+		} else if !elem.SeriesID.IsZero() {
+			t.Fatalf("got an undeleted series id, but series should be dropped from index")
+		}
+	}
+	if got := e.sfile.SeriesCount(); got != 0 {
+		t.Fatalf("series file count mismatch: exp 0, got %d", got)
+	}
+}
+
 func BenchmarkEngine_DeletePrefixRange(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		b.StopTimer()
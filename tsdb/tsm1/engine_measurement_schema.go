@@ -181,6 +181,8 @@ func (e *Engine) fieldsPredicate(ctx context.Context, orgID influxdb.ID, bucketI
 
 	orgBucket := tsdb.EncodeName(orgID, bucketID)
 
+	release := e.sfile.Pin()
+	defer release()
 	keys, err := e.findCandidateKeys(ctx, orgBucket[:], predicate)
 	if err != nil {
 		return cursors.EmptyMeasurementFieldsIterator, err
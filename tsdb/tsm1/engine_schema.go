@@ -155,6 +155,8 @@ func (e *Engine) tagValuesPredicate(ctx context.Context, orgID, bucketID influxd
 
 	orgBucket := tsdb.EncodeName(orgID, bucketID)
 
+	release := e.sfile.Pin()
+	defer release()
 	keys, err := e.findCandidateKeys(ctx, orgBucket[:], predicate)
 	if err != nil {
 		return cursors.EmptyStringIterator, err
@@ -281,6 +283,8 @@ func (e *Engine) tagValuesPredicate(ctx context.Context, orgID, bucketID influxd
 	return cursors.NewStringSliceIteratorWithStats(vals, stats), err
 }
 
+// findCandidateKeys returns the keys of the series matching predicate. The keys
+// are only valid while the series file is pinned.
 func (e *Engine) findCandidateKeys(ctx context.Context, orgBucket []byte, predicate influxql.Expr) ([][]byte, error) {
 	// determine candidate series keys
 	sitr, err := e.index.MeasurementSeriesByExprIterator(orgBucket, predicate)
@@ -437,6 +441,8 @@ func (e *Engine) tagKeysPredicate(ctx context.Context, orgID, bucketID influxdb.
 
 	orgBucket := tsdb.EncodeName(orgID, bucketID)
 
+	release := e.sfile.Pin()
+	defer release()
 	keys, err := e.findCandidateKeys(ctx, orgBucket[:], predicate)
 	if err != nil {
 		return cursors.EmptyStringIterator, err
@@ -7,7 +7,9 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -175,6 +177,47 @@ func TestEngine_ShouldCompactCache(t *testing.T) {
 	}
 }
 
+// Ensures that out of order writes are staged in the cache, and snapshotted to files of their own.
+func TestEngine_Late(t *testing.T) {
+	e := MustOpenEngine(t)
+	defer e.Close()
+
+	if err := e.WritePointsString("mm", "cpu,host=A value=1 20"); err != nil {
+		t.Fatal(err)
+	}
+	e.MustWriteSnapshot()
+
+	if err := e.WritePointsString("mm", "cpu,host=A value=2 10", "cpu,host=A value=3 30"); err != nil {
+		t.Fatal(err)
+	}
+	if got := e.Cache.LateSize(); got == 0 {
+		t.Fatal("expected out of order values in the cache")
+	}
+
+	e.CacheFlushLateSizeThreshold = 1
+	if got, exp := e.ShouldCompactCache(time.Now()), tsm1.CacheStatusLateSizeExceeded; got != exp {
+		t.Fatalf("got status %v, exp status %v - late size > flush threshold, so should compact", got, exp)
+	}
+	e.MustWriteSnapshot()
+
+	var ranges [][2]int64
+	for _, f := range e.FileStore.Stats() {
+		ranges = append(ranges, [2]int64{f.MinTime, f.MaxTime})
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	if exp := [][2]int64{{10, 10}, {20, 20}, {30, 30}}; !reflect.DeepEqual(ranges, exp) {
+		t.Fatalf("unexpected file time ranges: got %v, exp %v", ranges, exp)
+	}
+
+	// The watermark is restored from the files on disk.
+	if err := e.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if got, exp := e.Cache.Watermark(), int64(30); got != exp {
+		t.Fatalf("unexpected watermark: got %v, exp %v", got, exp)
+	}
+}
+
 func makeBlockTypeSlice(n int) []byte {
 	r := make([]byte, n)
 	b := tsm1.BlockFloat64
@@ -608,6 +651,8 @@ func newMockPlanner() tsm1.CompactionPlanner {
 func (m *mockPlanner) Plan(lastWrite time.Time) []tsm1.CompactionGroup { return nil }
 func (m *mockPlanner) PlanLevel(level int) []tsm1.CompactionGroup      { return nil }
 func (m *mockPlanner) PlanOptimize() []tsm1.CompactionGroup            { return nil }
+func (m *mockPlanner) PlanTier(before int64) []tsm1.CompactionGroup    { return nil }
+func (m *mockPlanner) PlanLate(level int) []tsm1.CompactionGroup       { return nil }
 func (m *mockPlanner) Release(groups []tsm1.CompactionGroup)           {}
 func (m *mockPlanner) FullyCompacted() bool                            { return false }
 func (m *mockPlanner) ForceFull()                                      {}
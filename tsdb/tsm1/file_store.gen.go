@@ -4,6 +4,7 @@ package tsm1
 
 // ReadFloatBlock reads the next block as a set of float values.
 func (c *KeyCursor) ReadFloatBlock(buf *[]FloatValue) ([]FloatValue, error) {
+	c.skipUnmatchedBlocks()
 LOOP:
 	// No matching blocks to decode
 	if len(c.current) == 0 {
@@ -190,6 +191,7 @@ func excludeTombstonesFloatValues(t []TimeRange, values FloatValues) FloatValues
 
 // ReadIntegerBlock reads the next block as a set of integer values.
 func (c *KeyCursor) ReadIntegerBlock(buf *[]IntegerValue) ([]IntegerValue, error) {
+	c.skipUnmatchedBlocks()
 LOOP:
 	// No matching blocks to decode
 	if len(c.current) == 0 {
@@ -376,6 +378,7 @@ func excludeTombstonesIntegerValues(t []TimeRange, values IntegerValues) Integer
 
 // ReadUnsignedBlock reads the next block as a set of unsigned values.
 func (c *KeyCursor) ReadUnsignedBlock(buf *[]UnsignedValue) ([]UnsignedValue, error) {
+	c.skipUnmatchedBlocks()
 LOOP:
 	// No matching blocks to decode
 	if len(c.current) == 0 {
@@ -562,6 +565,7 @@ func excludeTombstonesUnsignedValues(t []TimeRange, values UnsignedValues) Unsig
 
 // ReadStringBlock reads the next block as a set of string values.
 func (c *KeyCursor) ReadStringBlock(buf *[]StringValue) ([]StringValue, error) {
+	c.skipUnmatchedBlocks()
 LOOP:
 	// No matching blocks to decode
 	if len(c.current) == 0 {
@@ -748,6 +752,7 @@ func excludeTombstonesStringValues(t []TimeRange, values StringValues) StringVal
 
 // ReadBooleanBlock reads the next block as a set of boolean values.
 func (c *KeyCursor) ReadBooleanBlock(buf *[]BooleanValue) ([]BooleanValue, error) {
+	c.skipUnmatchedBlocks()
 LOOP:
 	// No matching blocks to decode
 	if len(c.current) == 0 {
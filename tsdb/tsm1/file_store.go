@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/pkg/bloom"
 	"github.com/influxdata/influxdb/v2/pkg/fs"
 	"github.com/influxdata/influxdb/v2/pkg/limiter"
 	"github.com/influxdata/influxdb/v2/pkg/metrics"
@@ -33,6 +34,10 @@ const (
 
 	// The extension used to describe corrupt snapshot files.
 	BadTSMFileExtension = "bad"
+
+	// The extension appended to the name of a TSM file moved to the storage
+	// tier, for the local marker left in its place.
+	TierTSMFileExtension = "tier"
 )
 
 type TSMIterator interface {
@@ -172,6 +177,14 @@ type TSMFile interface {
 
 	// Stats returns the statistics for the file.
 	MeasurementStats() (MeasurementStats, error)
+
+	// BlockStats returns the value statistics of the block identified by entry,
+	// if they were written for the file.
+	BlockStats(entry *IndexEntry) (BlockStats, bool)
+
+	// BlockValueFilter returns the bloom filter of the values of the block
+	// identified by entry, if they were indexed.
+	BlockValueFilter(entry *IndexEntry) (*bloom.Filter, bool)
 }
 
 // FileStoreObserver is passed notifications before the file store adds or deletes files. In this way, it can
@@ -195,6 +208,8 @@ var (
 	stringBlocksSizeCounter      = metrics.MustRegisterCounter("string_blocks_size_bytes", metrics.WithGroup(tsmGroup))
 	booleanBlocksDecodedCounter  = metrics.MustRegisterCounter("boolean_blocks_decoded", metrics.WithGroup(tsmGroup))
 	booleanBlocksSizeCounter     = metrics.MustRegisterCounter("boolean_blocks_size_bytes", metrics.WithGroup(tsmGroup))
+	blocksStatsCounter           = metrics.MustRegisterCounter("blocks_answered_from_stats", metrics.WithGroup(tsmGroup))
+	blocksSkippedCounter         = metrics.MustRegisterCounter("blocks_skipped_by_value_filter", metrics.WithGroup(tsmGroup))
 )
 
 // FileStore is an abstraction around multiple TSM files.
@@ -218,6 +233,10 @@ type FileStore struct {
 	tracker *fileTracker
 	purger  *purger
 
+	tierStore   TierStore       // store TSM files are moved to, nil if tiering is not configured
+	tierCache   *tierBlockCache // cache of the blocks read from tiered files
+	tierTracker *tierTracker
+
 	currentTempDirID int
 
 	parseFileName ParseFileNameFunc
@@ -233,6 +252,7 @@ type FileStat struct {
 	LastModified     int64
 	MinTime, MaxTime int64
 	MinKey, MaxKey   []byte
+	Tiered           bool // The file was moved to the storage tier.
 }
 
 // OverlapsTimeRange returns true if the time range of the file intersect min and max.
@@ -265,6 +285,7 @@ func NewFileStore(dir string) *FileStore {
 		obs:           noFileStoreObserver{},
 		parseFileName: DefaultParseFileName,
 		tracker:       newFileTracker(newFileMetrics(nil), nil),
+		tierTracker:   newTierTracker(newTierMetrics(nil), nil),
 	}
 	fs.purger.fileStore = fs
 	return fs
@@ -374,6 +395,13 @@ func (t *fileTracker) SetFileCount(files map[int]uint64) {
 	}
 }
 
+// SetOverlapRatio sets the ratio of the bytes of files whose time range overlaps
+// a file of another generation.
+func (t *fileTracker) SetOverlapRatio(ratio float64) {
+	labels := t.Labels()
+	t.metrics.OverlapRatio.With(labels).Set(ratio)
+}
+
 func (t *fileTracker) ClearFileCounts() {
 	labels := t.Labels()
 	for i := uint64(1); i <= 4; i++ {
@@ -636,6 +664,22 @@ func (f *FileStore) Open(ctx context.Context) error {
 		return err
 	}
 
+	// Files moved to the storage tier are opened from their markers. A marker
+	// next to its TSM file is left over from an incomplete move.
+	markers, err := filepath.Glob(filepath.Join(f.dir, fmt.Sprintf("*.%s.%s", TSMFileExtension, TierTSMFileExtension)))
+	if err != nil {
+		return err
+	}
+	for _, fn := range markers {
+		if _, err := os.Stat(fn[:len(fn)-len(TierTSMFileExtension)-1]); err == nil {
+			if err := os.Remove(fn); err != nil {
+				return err
+			}
+			continue
+		}
+		files = append(files, fn)
+	}
+
 	// struct to hold the result of opening each reader in a goroutine
 	type res struct {
 		r   *TSMReader
@@ -654,6 +698,31 @@ func (f *FileStore) Open(ctx context.Context) error {
 			f.currentGeneration = generation + 1
 		}
 
+		if strings.HasSuffix(fn, TierTSMFileExtension) {
+			go func(idx int, fn string) {
+				f.openLimiter.Take()
+				defer f.openLimiter.Release()
+
+				start := time.Now()
+				df, err := f.openTieredTSMReader(fn)
+				f.logger.Info("Opened tiered file",
+					zap.String("path", fn),
+					zap.Int("id", idx),
+					zap.Duration("duration", time.Since(start)))
+
+				// Unlike local files, tiered files are not renamed when they
+				// cannot be read, the tier may only be unavailable.
+				if err != nil {
+					readerC <- &res{err: fmt.Errorf("error opening tiered file %s: %v", fn, err)}
+					return
+				}
+
+				df.WithObserver(f.obs)
+				readerC <- &res{r: df}
+			}(i, fn)
+			continue
+		}
+
 		file, err := os.OpenFile(fn, os.O_RDONLY, 0666)
 		if err != nil {
 			return fmt.Errorf("error opening file %s: %v", fn, err)
@@ -692,6 +761,7 @@ func (f *FileStore) Open(ctx context.Context) error {
 	}
 
 	var lm int64
+	var tierFiles, tierBytes uint64
 	counts := make(map[int]uint64, 4)
 	sizes := make(map[int]uint64, 4)
 	for i := 1; i <= 4; i++ {
@@ -706,19 +776,25 @@ func (f *FileStore) Open(ctx context.Context) error {
 			continue
 		}
 		f.files = append(f.files, res.r)
-		name := filepath.Base(res.r.Stats().Path)
-		_, seq, err := f.parseFileName(name)
-		if err != nil {
-			return err
-		}
-		counts[seq]++
 
-		// Accumulate file store size stats
-		totalSize := uint64(res.r.Size())
-		for _, ts := range res.r.TombstoneFiles() {
-			totalSize += uint64(ts.Size)
+		if res.r.tiered() {
+			tierFiles++
+			tierBytes += uint64(res.r.Size())
+		} else {
+			name := filepath.Base(res.r.Stats().Path)
+			_, seq, err := f.parseFileName(name)
+			if err != nil {
+				return err
+			}
+			counts[seq]++
+
+			// Accumulate file store size stats
+			totalSize := uint64(res.r.Size())
+			for _, ts := range res.r.TombstoneFiles() {
+				totalSize += uint64(ts.Size)
+			}
+			sizes[seq] += totalSize
 		}
-		sizes[seq] += totalSize
 
 		// Re-initialize the lastModified time for the file store
 		if res.r.LastModified() > lm {
@@ -732,6 +808,8 @@ func (f *FileStore) Open(ctx context.Context) error {
 	sort.Sort(tsmReaders(f.files))
 	f.tracker.SetBytes(sizes)
 	f.tracker.SetFileCount(counts)
+	f.tracker.SetOverlapRatio(f.overlapRatio())
+	f.tierTracker.SetFiles(tierFiles, tierBytes)
 	return nil
 }
 
@@ -879,11 +957,12 @@ func (f *FileStore) replace(oldFiles, newFiles []string, updatedFn func(r []TSMF
 			return err
 		}
 
-		// Observe the associated statistics file, if available.
-		statsFile := StatsFilename(file)
-		if _, err := os.Stat(statsFile); err == nil {
-			if err := f.obs.FileFinishing(statsFile); err != nil {
-				return err
+		// Observe the associated statistics and index files, if available.
+		for _, statsFile := range sidecarFilenames(file) {
+			if _, err := os.Stat(statsFile); err == nil {
+				if err := f.obs.FileFinishing(statsFile); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -946,11 +1025,12 @@ func (f *FileStore) replace(oldFiles, newFiles []string, updatedFn func(r []TSMF
 					return err
 				}
 
-				// Remove associated stats file.
-				statsFile := StatsFilename(file.Path())
-				if _, err := os.Stat(statsFile); err == nil {
-					if err := f.obs.FileUnlinking(statsFile); err != nil {
-						return err
+				// Remove associated stats and index files.
+				for _, statsFile := range sidecarFilenames(file.Path()) {
+					if _, err := os.Stat(statsFile); err == nil {
+						if err := f.obs.FileUnlinking(statsFile); err != nil {
+							return err
+						}
 					}
 				}
 
@@ -1030,13 +1110,26 @@ func (f *FileStore) replace(oldFiles, newFiles []string, updatedFn func(r []TSMF
 	f.lastFileStats = nil
 	f.files = active
 	sort.Sort(tsmReaders(f.files))
+	return f.recalculateFileStats()
+}
+
+// recalculateFileStats recalculates the file count and size stats of the
+// local and tiered files. f.mu must be held.
+func (f *FileStore) recalculateFileStats() error {
 	f.tracker.ClearFileCounts()
 	f.tracker.ClearDiskSizes()
 
 	// Recalculate the disk size stat
+	var tierFiles, tierBytes uint64
 	sizes := make(map[int]uint64, 4)
 	counts := make(map[int]uint64, 4)
 	for _, file := range f.files {
+		if tieredFile(file) {
+			tierFiles++
+			tierBytes += uint64(file.Size())
+			continue
+		}
+
 		size := uint64(file.Size())
 		for _, ts := range file.TombstoneFiles() {
 			size += uint64(ts.Size)
@@ -1050,10 +1143,61 @@ func (f *FileStore) replace(oldFiles, newFiles []string, updatedFn func(r []TSMF
 	}
 	f.tracker.SetBytes(sizes)
 	f.tracker.SetFileCount(counts)
+	f.tracker.SetOverlapRatio(f.overlapRatio())
+	f.tierTracker.SetFiles(tierFiles, tierBytes)
 
 	return nil
 }
 
+// overlapRatio returns the ratio of the bytes of the files whose time range
+// overlaps a file of another generation. Reads of the overlapping time ranges
+// merge the blocks of several files, until the generations are compacted
+// together. f.mu must be held.
+func (f *FileStore) overlapRatio() float64 {
+	type generation struct {
+		min, max int64
+		size     uint64
+	}
+
+	var total uint64
+	generations := make(map[int]*generation)
+	for _, file := range f.files {
+		gen, _, err := f.parseFileName(file.Path())
+		if err != nil {
+			continue
+		}
+
+		min, max := file.TimeRange()
+		g := generations[gen]
+		if g == nil {
+			g = &generation{min: min, max: max}
+			generations[gen] = g
+		}
+		if min < g.min {
+			g.min = min
+		}
+		if max > g.max {
+			g.max = max
+		}
+		g.size += uint64(file.Size())
+		total += uint64(file.Size())
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var overlapping uint64
+	for id, g := range generations {
+		for other, o := range generations {
+			if other != id && g.min <= o.max && g.max >= o.min {
+				overlapping += g.size
+				break
+			}
+		}
+	}
+	return float64(overlapping) / float64(total)
+}
+
 // LastModified returns the last time the file store was updated with new
 // TSM files or a delete.
 func (f *FileStore) LastModified() time.Time {
@@ -1215,7 +1359,7 @@ func (f *FileStore) locations(key []byte, t int64, ascending bool) []*location {
 }
 
 // CreateSnapshot creates hardlinks for all tsm and tombstone files
-// in the path provided.
+// in the path provided. The tsm files in the storage tier are copied from it.
 func (f *FileStore) CreateSnapshot(ctx context.Context) (backupID int, backupDirFullPath string, err error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
@@ -1249,7 +1393,13 @@ func (f *FileStore) CreateSnapshot(ctx context.Context) (backupID int, backupDir
 	}
 	for _, tsmf := range files {
 		newpath := filepath.Join(backupDirFullPath, filepath.Base(tsmf.Path()))
-		if err := os.Link(tsmf.Path(), newpath); err != nil {
+		if a, ok := tierAccessorOf(tsmf); ok {
+			// Files in the storage tier are copied from it, so that the
+			// snapshot does not depend on their objects.
+			if err := a.copyObject(ctx, newpath); err != nil {
+				return 0, "", fmt.Errorf("error copying tiered tsm file: %q", err)
+			}
+		} else if err := os.Link(tsmf.Path(), newpath); err != nil {
 			return 0, "", fmt.Errorf("error creating tsm hard link: %q", err)
 		}
 		for _, tf := range tsmf.TombstoneFiles() {
@@ -1342,6 +1492,9 @@ type KeyCursor struct {
 	ctx context.Context
 	col *metrics.Group
 
+	// filter, if set, skips the blocks whose values cannot match it.
+	filter cursors.BlockFilter
+
 	// pos is the index within seeks.  Based on ascending, it will increment or
 	// decrement through the size of seeks slice.
 	pos       int
@@ -1494,6 +1647,112 @@ func (c *KeyCursor) Next() {
 	}
 }
 
+// NextBlockStats returns the time range and value statistics of the next block
+// and advances the cursor past it, if the block can be answered from its
+// statistics alone: all its values lie within [min, max], none of them have
+// been read or deleted, and no other unread block or value of cache overlaps
+// it. Otherwise it returns false and the block must be read by the ReadBlock
+// functions.
+func (c *KeyCursor) NextBlockStats(min, max int64, cache Values) (TimeRange, BlockStats, bool) {
+	if len(c.current) == 0 {
+		return TimeRange{}, BlockStats{}, false
+	}
+
+	first := c.current[0]
+	tr := TimeRange{Min: first.entry.MinTime, Max: first.entry.MaxTime}
+	if tr.Min < min || tr.Max > max {
+		return tr, BlockStats{}, false
+	} else if first.entry.OverlapsTimeRange(first.readMin, first.readMax) {
+		return tr, BlockStats{}, false
+	}
+
+	for _, cur := range c.current[1:] {
+		// Descending cursors list the first block again, skip it.
+		if cur != first && !cur.read() && cur.entry.OverlapsTimeRange(tr.Min, tr.Max) {
+			return tr, BlockStats{}, false
+		}
+	}
+
+	if i := sort.Search(len(cache), func(i int) bool {
+		return cache[i].UnixNano() >= tr.Min
+	}); i < len(cache) && cache[i].UnixNano() <= tr.Max {
+		return tr, BlockStats{}, false
+	}
+
+	c.trbuf = first.r.TombstoneRange(c.key, c.trbuf[:0])
+	for _, t := range c.trbuf {
+		if first.entry.OverlapsTimeRange(t.Min, t.Max) {
+			return tr, BlockStats{}, false
+		}
+	}
+
+	stats, ok := first.r.BlockStats(&first.entry)
+	if !ok {
+		return tr, BlockStats{}, false
+	}
+	if c.col != nil {
+		c.col.GetCounter(blocksStatsCounter).Add(1)
+	}
+
+	first.markRead(tr.Min, tr.Max)
+	c.Next()
+	return tr, stats, true
+}
+
+// skipUnmatchedBlocks advances the cursor past the next blocks whose values
+// cannot match its filter. A block is only skipped if no other unread block
+// overlaps it, since the values of an overlapping block may replace its own.
+func (c *KeyCursor) skipUnmatchedBlocks() {
+	for c.filter != nil && len(c.current) > 0 {
+		first := c.current[0]
+		for _, cur := range c.current[1:] {
+			// Descending cursors list the first block again, skip it.
+			if cur != first && !cur.read() && cur.entry.OverlapsTimeRange(first.entry.MinTime, first.entry.MaxTime) {
+				return
+			}
+		}
+
+		s, ok := c.blockSummary(first)
+		if !ok || c.filter.MayMatch(&s) {
+			return
+		}
+		if c.col != nil {
+			c.col.GetCounter(blocksSkippedCounter).Add(1)
+		}
+
+		first.markRead(first.entry.MinTime, first.entry.MaxTime)
+		c.Next()
+	}
+}
+
+// blockSummary describes the values of the block at l from its statistics and
+// value filter. It returns false if neither is available.
+func (c *KeyCursor) blockSummary(l *location) (cursors.BlockSummary, bool) {
+	typ, err := l.r.Type(c.key)
+	if err != nil {
+		return cursors.BlockSummary{}, false
+	}
+
+	s := cursors.BlockSummary{Type: BlockTypeToFieldType(typ)}
+	if stats, ok := l.r.BlockStats(&l.entry); ok {
+		switch typ {
+		case BlockFloat64:
+			min, max, _ := stats.FloatValues()
+			s.Min, s.Max = min, max
+		case BlockInteger:
+			min, max, _ := stats.IntegerValues()
+			s.Min, s.Max = min, max
+		case BlockUnsigned:
+			min, max, _ := stats.UnsignedValues()
+			s.Min, s.Max = min, max
+		}
+	}
+	if f, ok := l.r.BlockValueFilter(&l.entry); ok {
+		s.Contains = func(v interface{}) bool { return f.Contains(appendIndexValue(nil, v)) }
+	}
+	return s, s.Min != nil || s.Contains != nil
+}
+
 func (c *KeyCursor) nextAscending() {
 	for {
 		c.pos++
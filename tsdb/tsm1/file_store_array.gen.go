@@ -8,6 +8,7 @@ import (
 
 // ReadFloatArrayBlock reads the next block as a set of float values.
 func (c *KeyCursor) ReadFloatArrayBlock(values *cursors.FloatArray) (*cursors.FloatArray, error) {
+	c.skipUnmatchedBlocks()
 LOOP:
 	// No matching blocks to decode
 	if len(c.current) == 0 {
@@ -192,6 +193,7 @@ func excludeTombstonesFloatArray(t []TimeRange, values *cursors.FloatArray) {
 
 // ReadIntegerArrayBlock reads the next block as a set of integer values.
 func (c *KeyCursor) ReadIntegerArrayBlock(values *cursors.IntegerArray) (*cursors.IntegerArray, error) {
+	c.skipUnmatchedBlocks()
 LOOP:
 	// No matching blocks to decode
 	if len(c.current) == 0 {
@@ -376,6 +378,7 @@ func excludeTombstonesIntegerArray(t []TimeRange, values *cursors.IntegerArray)
 
 // ReadUnsignedArrayBlock reads the next block as a set of unsigned values.
 func (c *KeyCursor) ReadUnsignedArrayBlock(values *cursors.UnsignedArray) (*cursors.UnsignedArray, error) {
+	c.skipUnmatchedBlocks()
 LOOP:
 	// No matching blocks to decode
 	if len(c.current) == 0 {
@@ -560,6 +563,7 @@ func excludeTombstonesUnsignedArray(t []TimeRange, values *cursors.UnsignedArray
 
 // ReadStringArrayBlock reads the next block as a set of string values.
 func (c *KeyCursor) ReadStringArrayBlock(values *cursors.StringArray) (*cursors.StringArray, error) {
+	c.skipUnmatchedBlocks()
 LOOP:
 	// No matching blocks to decode
 	if len(c.current) == 0 {
@@ -744,6 +748,7 @@ func excludeTombstonesStringArray(t []TimeRange, values *cursors.StringArray) {
 
 // ReadBooleanArrayBlock reads the next block as a set of boolean values.
 func (c *KeyCursor) ReadBooleanArrayBlock(values *cursors.BooleanArray) (*cursors.BooleanArray, error) {
+	c.skipUnmatchedBlocks()
 LOOP:
 	// No matching blocks to decode
 	if len(c.current) == 0 {
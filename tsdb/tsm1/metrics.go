@@ -26,6 +26,7 @@ func PrometheusCollectors() []prometheus.Collector {
 		collectors = append(collectors, bms.fileMetrics.PrometheusCollectors()...)
 		collectors = append(collectors, bms.cacheMetrics.PrometheusCollectors()...)
 		collectors = append(collectors, bms.readMetrics.PrometheusCollectors()...)
+		collectors = append(collectors, bms.tierMetrics.PrometheusCollectors()...)
 	}
 	return collectors
 }
@@ -37,6 +38,7 @@ const compactionSubsystem = "compactions" // sub-system associated with metrics
 const fileStoreSubsystem = "tsm_files"    // sub-system associated with metrics for TSM files.
 const cacheSubsystem = "cache"            // sub-system associated with metrics for the cache.
 const readSubsystem = "reads"             // sub-system associated with metrics for reads.
+const tierSubsystem = "tier"              // sub-system associated with metrics for the storage tier.
 
 // blockMetrics are a set of metrics concerned with tracking data about block storage.
 type blockMetrics struct {
@@ -45,6 +47,7 @@ type blockMetrics struct {
 	*fileMetrics
 	*cacheMetrics
 	*readMetrics
+	*tierMetrics
 }
 
 // newBlockMetrics initialises the prometheus metrics for the block subsystem.
@@ -55,6 +58,7 @@ func newBlockMetrics(labels prometheus.Labels) *blockMetrics {
 		fileMetrics:       newFileMetrics(labels),
 		cacheMetrics:      newCacheMetrics(labels),
 		readMetrics:       newReadMetrics(labels),
+		tierMetrics:       newTierMetrics(labels),
 	}
 }
 
@@ -65,6 +69,7 @@ func (m *blockMetrics) PrometheusCollectors() []prometheus.Collector {
 	metrics = append(metrics, m.fileMetrics.PrometheusCollectors()...)
 	metrics = append(metrics, m.cacheMetrics.PrometheusCollectors()...)
 	metrics = append(metrics, m.readMetrics.PrometheusCollectors()...)
+	metrics = append(metrics, m.tierMetrics.PrometheusCollectors()...)
 	return metrics
 }
 
@@ -131,8 +136,9 @@ func (m *compactionMetrics) PrometheusCollectors() []prometheus.Collector {
 
 // fileMetrics are a set of metrics concerned with tracking data about compactions.
 type fileMetrics struct {
-	DiskSize *prometheus.GaugeVec
-	Files    *prometheus.GaugeVec
+	DiskSize     *prometheus.GaugeVec
+	Files        *prometheus.GaugeVec
+	OverlapRatio *prometheus.GaugeVec
 }
 
 // newFileMetrics initialises the prometheus metrics for tracking files on disk.
@@ -141,21 +147,29 @@ func newFileMetrics(labels prometheus.Labels) *fileMetrics {
 	for k := range labels {
 		names = append(names, k)
 	}
-	names = append(names, "level")
 	sort.Strings(names)
 
+	levelNames := append(append([]string(nil), names...), "level")
+	sort.Strings(levelNames)
+
 	return &fileMetrics{
 		DiskSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Subsystem: fileStoreSubsystem,
 			Name:      "disk_bytes",
 			Help:      "Number of bytes TSM files using on disk.",
-		}, names),
+		}, levelNames),
 		Files: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: namespace,
 			Subsystem: fileStoreSubsystem,
 			Name:      "total",
 			Help:      "Number of files.",
+		}, levelNames),
+		OverlapRatio: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: fileStoreSubsystem,
+			Name:      "overlap_ratio",
+			Help:      "Ratio of the bytes of TSM files whose time range overlaps a file of another generation.",
 		}, names),
 	}
 }
@@ -165,6 +179,7 @@ func (m *fileMetrics) PrometheusCollectors() []prometheus.Collector {
 	return []prometheus.Collector{
 		m.DiskSize,
 		m.Files,
+		m.OverlapRatio,
 	}
 }
 
@@ -175,10 +190,14 @@ type cacheMetrics struct {
 	SnapshotsActive  *prometheus.GaugeVec
 	Age              *prometheus.GaugeVec
 	SnapshottedBytes *prometheus.CounterVec
+	LateSize         *prometheus.GaugeVec
 
 	// The following metrics include a ``"status" = {ok, error, dropped}` label
 	WrittenBytes *prometheus.CounterVec
 	Writes       *prometheus.CounterVec
+
+	// The following metrics include a `"status" = {ok, dropped}` label
+	LateValues *prometheus.CounterVec
 }
 
 // newCacheMetrics initialises the prometheus metrics for compactions.
@@ -223,6 +242,12 @@ func newCacheMetrics(labels prometheus.Labels) *cacheMetrics {
 			Name:      "snapshot_bytes",
 			Help:      "Number of bytes snapshotted.",
 		}, names),
+		LateSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: cacheSubsystem,
+			Name:      "late_inuse_bytes",
+			Help:      "In-memory size of the out of order values staged in the cache.",
+		}, names),
 		WrittenBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: cacheSubsystem,
@@ -235,6 +260,12 @@ func newCacheMetrics(labels prometheus.Labels) *cacheMetrics {
 			Name:      "writes_total",
 			Help:      "Number of writes to the Cache.",
 		}, writeNames),
+		LateValues: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: cacheSubsystem,
+			Name:      "late_values_total",
+			Help:      "Number of out of order values written to the Cache.",
+		}, writeNames),
 	}
 }
 
@@ -246,8 +277,10 @@ func (m *cacheMetrics) PrometheusCollectors() []prometheus.Collector {
 		m.SnapshotsActive,
 		m.Age,
 		m.SnapshottedBytes,
+		m.LateSize,
 		m.WrittenBytes,
 		m.Writes,
+		m.LateValues,
 	}
 }
 
@@ -288,3 +321,75 @@ func (m *readMetrics) PrometheusCollectors() []prometheus.Collector {
 		m.Seeks,
 	}
 }
+
+// tierMetrics are a set of metrics concerned with tracking data about the storage tier.
+type tierMetrics struct {
+	Files     *prometheus.GaugeVec
+	Bytes     *prometheus.GaugeVec
+	ReadBytes *prometheus.CounterVec
+
+	// The following metrics include a `"cache" = {hit, miss}` label
+	BlockReads *prometheus.CounterVec
+
+	// The following metrics include a `"status" = {ok, error}` label
+	Moves *prometheus.CounterVec
+}
+
+// newTierMetrics initialises the prometheus metrics for tracking the storage tier.
+func newTierMetrics(labels prometheus.Labels) *tierMetrics {
+	var names []string
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	blockReadNames := append(append([]string(nil), names...), "cache")
+	sort.Strings(blockReadNames)
+
+	moveNames := append(append([]string(nil), names...), "status")
+	sort.Strings(moveNames)
+
+	return &tierMetrics{
+		Files: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: tierSubsystem,
+			Name:      "files",
+			Help:      "Number of TSM files in the storage tier.",
+		}, names),
+		Bytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: tierSubsystem,
+			Name:      "bytes",
+			Help:      "Size of the TSM files in the storage tier.",
+		}, names),
+		ReadBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: tierSubsystem,
+			Name:      "read_bytes",
+			Help:      "Number of bytes read from the storage tier.",
+		}, names),
+		BlockReads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: tierSubsystem,
+			Name:      "block_reads",
+			Help:      "Number of blocks of tiered TSM files read, by block cache outcome.",
+		}, blockReadNames),
+		Moves: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: tierSubsystem,
+			Name:      "moves",
+			Help:      "Number of TSM files moved to the storage tier.",
+		}, moveNames),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *tierMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.Files,
+		m.Bytes,
+		m.ReadBytes,
+		m.BlockReads,
+		m.Moves,
+	}
+}
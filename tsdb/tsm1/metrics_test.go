@@ -20,6 +20,7 @@ func TestMetrics_Filestore(t *testing.T) {
 	t2.AddBytes(200, 1)
 	t2.SetFileCount(map[int]uint64{1: 4, 4: 3, 5: 1})
 	t3.SetBytes(map[int]uint64{1: 500, 4: 100, 5: 100})
+	t3.SetOverlapRatio(0.25)
 
 	// Test that all the correct metrics are present.
 	mfs, err := reg.Gather()
@@ -33,6 +34,7 @@ func TestMetrics_Filestore(t *testing.T) {
 	m2Files2 := promtest.MustFindMetric(t, mfs, base+"total", prometheus.Labels{"engine_id": "1", "node_id": "0", "level": "4+"})
 	m3Bytes1 := promtest.MustFindMetric(t, mfs, base+"disk_bytes", prometheus.Labels{"engine_id": "2", "node_id": "0", "level": "1"})
 	m3Bytes2 := promtest.MustFindMetric(t, mfs, base+"disk_bytes", prometheus.Labels{"engine_id": "2", "node_id": "0", "level": "4+"})
+	m3Overlap := promtest.MustFindMetric(t, mfs, base+"overlap_ratio", prometheus.Labels{"engine_id": "2", "node_id": "0"})
 
 	if m, got, exp := m2Bytes, m2Bytes.GetGauge().GetValue(), 200.0; got != exp {
 		t.Errorf("[%s] got %v, expected %v", m, got, exp)
@@ -53,6 +55,10 @@ func TestMetrics_Filestore(t *testing.T) {
 	if m, got, exp := m3Bytes2, m3Bytes2.GetGauge().GetValue(), 200.0; got != exp {
 		t.Errorf("[%s] got %v, expected %v", m, got, exp)
 	}
+
+	if m, got, exp := m3Overlap, m3Overlap.GetGauge().GetValue(), 0.25; got != exp {
+		t.Errorf("[%s] got %v, expected %v", m, got, exp)
+	}
 }
 
 func TestMetrics_Cache(t *testing.T) {
@@ -73,12 +79,14 @@ func TestMetrics_Cache(t *testing.T) {
 		base + "disk_bytes",
 		base + "age_seconds",
 		base + "snapshots_active",
+		base + "late_inuse_bytes",
 	}
 
 	counters := []string{
 		base + "snapshot_bytes",
 		base + "written_bytes",
 		base + "writes_total",
+		base + "late_values_total",
 	}
 
 	// Generate some measurements.
@@ -87,6 +95,7 @@ func TestMetrics_Cache(t *testing.T) {
 		tracker.SetDiskBytes(uint64(i + len(gauges[1])))
 		tracker.metrics.Age.With(tracker.Labels()).Set(float64(i + len(gauges[2])))
 		tracker.SetSnapshotsActive(uint64(i + len(gauges[3])))
+		tracker.SetLateSize(uint64(i + len(gauges[4])))
 
 		tracker.AddSnapshottedBytes(uint64(i + len(counters[0])))
 		tracker.AddWrittenBytesOK(uint64(i + len(counters[1])))
@@ -94,6 +103,7 @@ func TestMetrics_Cache(t *testing.T) {
 		labels := tracker.Labels()
 		labels["status"] = "ok"
 		tracker.metrics.Writes.With(labels).Add(float64(i + len(counters[2])))
+		tracker.AddLateValuesOK(i + len(counters[3]))
 	}
 
 	// Test that all the correct metrics are present.
@@ -120,7 +130,7 @@ func TestMetrics_Cache(t *testing.T) {
 		for _, name := range counters {
 			exp := float64(i + len(name))
 
-			if name == counters[1] || name == counters[2] {
+			if name == counters[1] || name == counters[2] || name == counters[3] {
 				labels["status"] = "ok"
 			}
 			metric := promtest.MustFindMetric(t, mfs, name, labels)
@@ -216,3 +226,69 @@ func TestMetrics_Compactions(t *testing.T) {
 		}
 	}
 }
+
+func TestMetrics_Tier(t *testing.T) {
+	// metrics to be shared by multiple file stores.
+	metrics := newTierMetrics(prometheus.Labels{"engine_id": "", "node_id": ""})
+
+	t1 := newTierTracker(metrics, prometheus.Labels{"engine_id": "0", "node_id": "0"})
+	t2 := newTierTracker(metrics, prometheus.Labels{"engine_id": "1", "node_id": "0"})
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(metrics.PrometheusCollectors()...)
+
+	// Generate some measurements.
+	t1.SetFiles(2, 300)
+	t1.AddReadBytes(100)
+	t1.IncBlockReads(true)
+	t1.IncBlockReads(true)
+	t1.IncBlockReads(false)
+	t1.IncMoves(true)
+	t2.SetFiles(1, 50)
+	t2.IncMoves(false)
+
+	// Test that all the correct metrics are present.
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := namespace + "_" + tierSubsystem + "_"
+	labels0 := prometheus.Labels{"engine_id": "0", "node_id": "0"}
+	labels1 := prometheus.Labels{"engine_id": "1", "node_id": "0"}
+
+	gauges := []struct {
+		name   string
+		labels prometheus.Labels
+		exp    float64
+	}{
+		{base + "files", labels0, 2},
+		{base + "bytes", labels0, 300},
+		{base + "files", labels1, 1},
+		{base + "bytes", labels1, 50},
+	}
+	for _, g := range gauges {
+		metric := promtest.MustFindMetric(t, mfs, g.name, g.labels)
+		if got := metric.GetGauge().GetValue(); got != g.exp {
+			t.Errorf("[%s %v] got %v, expected %v", g.name, g.labels, got, g.exp)
+		}
+	}
+
+	counters := []struct {
+		name   string
+		labels prometheus.Labels
+		exp    float64
+	}{
+		{base + "read_bytes", labels0, 100},
+		{base + "block_reads", prometheus.Labels{"engine_id": "0", "node_id": "0", "cache": "hit"}, 2},
+		{base + "block_reads", prometheus.Labels{"engine_id": "0", "node_id": "0", "cache": "miss"}, 1},
+		{base + "moves", prometheus.Labels{"engine_id": "0", "node_id": "0", "status": "ok"}, 1},
+		{base + "moves", prometheus.Labels{"engine_id": "1", "node_id": "0", "status": "error"}, 1},
+	}
+	for _, c := range counters {
+		metric := promtest.MustFindMetric(t, mfs, c.name, c.labels)
+		if got := metric.GetCounter().GetValue(); got != c.exp {
+			t.Errorf("[%s %v] got %v, expected %v", c.name, c.labels, got, c.exp)
+		}
+	}
+}
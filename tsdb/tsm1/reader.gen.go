@@ -283,3 +283,83 @@ func (m *mmapAccessor) readBooleanArrayBlock(entry *IndexEntry, values *cursors.
 
 	return err
 }
+
+func (a *tierAccessor) readFloatBlock(entry *IndexEntry, values *[]FloatValue) ([]FloatValue, error) {
+	b, err := a.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeFloatBlock(b[4:], values)
+}
+
+func (a *tierAccessor) readFloatArrayBlock(entry *IndexEntry, values *cursors.FloatArray) error {
+	b, err := a.block(entry)
+	if err != nil {
+		return err
+	}
+	return DecodeFloatArrayBlock(b[4:], values)
+}
+
+func (a *tierAccessor) readIntegerBlock(entry *IndexEntry, values *[]IntegerValue) ([]IntegerValue, error) {
+	b, err := a.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeIntegerBlock(b[4:], values)
+}
+
+func (a *tierAccessor) readIntegerArrayBlock(entry *IndexEntry, values *cursors.IntegerArray) error {
+	b, err := a.block(entry)
+	if err != nil {
+		return err
+	}
+	return DecodeIntegerArrayBlock(b[4:], values)
+}
+
+func (a *tierAccessor) readUnsignedBlock(entry *IndexEntry, values *[]UnsignedValue) ([]UnsignedValue, error) {
+	b, err := a.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeUnsignedBlock(b[4:], values)
+}
+
+func (a *tierAccessor) readUnsignedArrayBlock(entry *IndexEntry, values *cursors.UnsignedArray) error {
+	b, err := a.block(entry)
+	if err != nil {
+		return err
+	}
+	return DecodeUnsignedArrayBlock(b[4:], values)
+}
+
+func (a *tierAccessor) readStringBlock(entry *IndexEntry, values *[]StringValue) ([]StringValue, error) {
+	b, err := a.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeStringBlock(b[4:], values)
+}
+
+func (a *tierAccessor) readStringArrayBlock(entry *IndexEntry, values *cursors.StringArray) error {
+	b, err := a.block(entry)
+	if err != nil {
+		return err
+	}
+	return DecodeStringArrayBlock(b[4:], values)
+}
+
+func (a *tierAccessor) readBooleanBlock(entry *IndexEntry, values *[]BooleanValue) ([]BooleanValue, error) {
+	b, err := a.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBooleanBlock(b[4:], values)
+}
+
+func (a *tierAccessor) readBooleanArrayBlock(entry *IndexEntry, values *cursors.BooleanArray) error {
+	b, err := a.block(entry)
+	if err != nil {
+		return err
+	}
+	return DecodeBooleanArrayBlock(b[4:], values)
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/influxdata/influxdb/v2/pkg/bloom"
 	"go.uber.org/zap"
 )
 
@@ -40,6 +41,14 @@ type TSMReader struct {
 
 	// deleteMu limits concurrent deletes
 	deleteMu sync.Mutex
+
+	// blockStats are the value statistics of the blocks, loaded on first use.
+	blockStatsOnce sync.Once
+	blockStats     BlockStatsIndex
+
+	// valueIndex are the value filters of the blocks, loaded on first use.
+	valueIndexOnce sync.Once
+	valueIndex     ValueIndex
 }
 
 type tsmReaderOption func(*TSMReader)
@@ -217,6 +226,58 @@ func (t *TSMReader) MeasurementStats() (MeasurementStats, error) {
 	return stats, err
 }
 
+// BlockStats returns the value statistics of the block identified by entry.
+// It returns false if the file has no block stats file, or one with an
+// unsupported version, in which case the block must be decoded.
+func (t *TSMReader) BlockStats(entry *IndexEntry) (BlockStats, bool) {
+	t.blockStatsOnce.Do(t.loadBlockStats)
+	return t.blockStats.Find(entry.Offset)
+}
+
+func (t *TSMReader) loadBlockStats() {
+	f, err := os.Open(BlockStatsFilename(t.Path()))
+	if os.IsNotExist(err) {
+		return
+	} else if err != nil {
+		t.logger.Info("Cannot open block stats file", zap.String("path", t.Path()), zap.Error(err))
+		return
+	}
+	defer f.Close()
+
+	var stats BlockStatsIndex
+	if _, err := stats.ReadFrom(bufio.NewReader(f)); err != nil {
+		t.logger.Info("Ignoring block stats file", zap.String("path", t.Path()), zap.Error(err))
+		return
+	}
+	t.blockStats = stats
+}
+
+// BlockValueFilter returns the bloom filter of the values of the block
+// identified by entry. It returns false if the values of the block are not
+// indexed, in which case the block may hold any value.
+func (t *TSMReader) BlockValueFilter(entry *IndexEntry) (*bloom.Filter, bool) {
+	t.valueIndexOnce.Do(t.loadValueIndex)
+	return t.valueIndex.Find(entry.Offset)
+}
+
+func (t *TSMReader) loadValueIndex() {
+	f, err := os.Open(ValueIndexFilename(t.Path()))
+	if os.IsNotExist(err) {
+		return
+	} else if err != nil {
+		t.logger.Info("Cannot open value index file", zap.String("path", t.Path()), zap.Error(err))
+		return
+	}
+	defer f.Close()
+
+	var index ValueIndex
+	if _, err := index.ReadFrom(bufio.NewReader(f)); err != nil {
+		t.logger.Info("Ignoring value index file", zap.String("path", t.Path()), zap.Error(err))
+		return
+	}
+	t.valueIndex = index
+}
+
 // Close closes the TSMReader.
 func (t *TSMReader) Close() error {
 	t.refsWG.Wait()
@@ -278,7 +339,7 @@ func (t *TSMReader) remove() error {
 	if path != "" {
 		if err := os.RemoveAll(path); err != nil {
 			return err
-		} else if err := os.RemoveAll(StatsFilename(path)); err != nil && !os.IsNotExist(err) {
+		} else if err := removeSidecarFiles(path); err != nil {
 			return err
 		}
 	}
@@ -286,6 +347,10 @@ func (t *TSMReader) remove() error {
 	if err := t.tombstoner.Delete(); err != nil {
 		return err
 	}
+
+	if a, ok := t.accessor.(*tierAccessor); ok {
+		return a.remove()
+	}
 	return nil
 }
 
@@ -463,6 +528,7 @@ func (t *TSMReader) Stats() FileStat {
 		MinKey:       minKey,
 		MaxKey:       maxKey,
 		HasTombstone: t.tombstoner.HasTombstones(),
+		Tiered:       t.tiered(),
 	}
 }
 
@@ -49,6 +49,14 @@ func (b *BlockIterator) Read() (key []byte, minTime int64, maxTime int64, typ by
 	return b.iter.Key(), b.entries[0].MinTime, b.entries[0].MaxTime, b.iter.Type(), checksum, buf, err
 }
 
+// blockMeta returns the value statistics and filter of the block to be
+// iterated, if the file has them.
+func (b *BlockIterator) blockMeta() (m blockMeta) {
+	m.stats, m.hasStats = b.r.BlockStats(&b.entries[0])
+	m.filter, _ = b.r.BlockValueFilter(&b.entries[0])
+	return m
+}
+
 // Err returns any errors encounter during iteration.
 func (b *BlockIterator) Err() error {
 	return b.iter.Err()
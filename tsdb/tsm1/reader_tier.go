@@ -0,0 +1,462 @@
+package tsm1
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/influxdata/influxdb/v2/pkg/fs"
+	"go.uber.org/zap"
+)
+
+// tierMarker is the content of the local marker file left in place of a TSM
+// file that was moved to the storage tier.
+type tierMarker struct {
+	// Object is the name of the TSM file in the tier store.
+	Object string `json:"object"`
+
+	// Size is the size of the TSM file in bytes.
+	Size int64 `json:"size"`
+
+	// LastModified is the modification time of the TSM file before it was moved.
+	LastModified int64 `json:"lastModified"`
+}
+
+// TierMarkerFilename returns the path of the marker of a TSM file moved to the
+// storage tier.
+func TierMarkerFilename(tsmPath string) string {
+	return tsmPath + "." + TierTSMFileExtension
+}
+
+func readTierMarker(path string) (*tierMarker, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &tierMarker{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("invalid tier marker %s: %v", path, err)
+	}
+	return m, nil
+}
+
+// writeTierMarker atomically writes the marker of a TSM file to path.
+func writeTierMarker(path string, m *tierMarker) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + "." + TmpTSMFileExtension
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return fs.RenameFile(tmp, path)
+}
+
+// newTieredTSMReader returns a TSMReader for the TSM file moved to the storage
+// tier that the marker at markerPath refers to.
+func newTieredTSMReader(markerPath string, store TierStore, cache *tierBlockCache, tracker *tierTracker, options ...tsmReaderOption) (*TSMReader, error) {
+	t := &TSMReader{
+		logger: zap.NewNop(),
+	}
+	for _, option := range options {
+		option(t)
+	}
+
+	m, err := readTierMarker(markerPath)
+	if err != nil {
+		return nil, err
+	}
+	t.size = m.Size
+	t.lastModified = m.LastModified
+	t.accessor = &tierAccessor{
+		logger:  t.logger,
+		store:   store,
+		object:  m.Object,
+		size:    m.Size,
+		cache:   cache,
+		tracker: tracker,
+		_path:   markerPath[:len(markerPath)-len(TierTSMFileExtension)-1],
+	}
+
+	index, err := t.accessor.init()
+	if err != nil {
+		return nil, err
+	}
+
+	t.index = index
+	t.tombstoner = NewTombstoner(t.Path(), index.MaybeContainsKey)
+
+	if err := t.applyTombstones(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// tiered returns true if the TSM file of the reader lives in the storage tier.
+func (t *TSMReader) tiered() bool {
+	_, ok := t.accessor.(*tierAccessor)
+	return ok
+}
+
+// tieredFile returns true if f lives in the storage tier.
+func tieredFile(f TSMFile) bool {
+	r, ok := f.(*TSMReader)
+	return ok && r.tiered()
+}
+
+// tierAccessorOf returns the accessor of f if it lives in the storage tier.
+func tierAccessorOf(f TSMFile) (*tierAccessor, bool) {
+	r, ok := f.(*TSMReader)
+	if !ok {
+		return nil, false
+	}
+	a, ok := r.accessor.(*tierAccessor)
+	return a, ok
+}
+
+// tierAccessor is a block accessor of a TSM file in the storage tier. It reads
+// the index when initialized and fetches blocks on demand, through a block
+// cache shared by all the tiered files of a FileStore.
+//
+// The path of the accessor is the path the TSM file had on disk, so that its
+// tombstones and statistics remain local.
+type tierAccessor struct {
+	logger  *zap.Logger
+	store   TierStore
+	object  string
+	size    int64
+	cache   *tierBlockCache
+	tracker *tierTracker
+
+	mu     sync.RWMutex
+	_path  string
+	closed bool
+
+	index *indirectIndex
+}
+
+func (a *tierAccessor) init() (*indirectIndex, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), tierRequestTimeout)
+	defer cancel()
+
+	r := &tierObjectReader{ctx: ctx, store: a.store, object: a.object}
+	if err := verifyVersion(io.NewSectionReader(r, 0, a.size)); err != nil {
+		return nil, err
+	}
+	if a.size < 8 {
+		return nil, fmt.Errorf("tierAccessor: object too small for indirectIndex")
+	}
+
+	var footer [8]byte
+	indexOfsPos := a.size - 8
+	if _, err := r.ReadAt(footer[:], indexOfsPos); err != nil {
+		return nil, err
+	}
+	indexStart := int64(binary.BigEndian.Uint64(footer[:]))
+	if indexStart >= indexOfsPos {
+		return nil, fmt.Errorf("tierAccessor: invalid indexStart")
+	}
+
+	b := make([]byte, indexOfsPos-indexStart)
+	if _, err := r.ReadAt(b, indexStart); err != nil {
+		return nil, err
+	}
+	a.tracker.AddReadBytes(uint64(len(b) + len(footer)))
+
+	a.index = NewIndirectIndex()
+	if err := a.index.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	a.index.logger = a.logger
+
+	return a.index, nil
+}
+
+// block returns the block of entry, checksum included.
+func (a *tierAccessor) block(entry *IndexEntry) ([]byte, error) {
+	a.mu.RLock()
+	closed := a.closed
+	a.mu.RUnlock()
+	if closed || a.size < entry.Offset+int64(entry.Size) {
+		return nil, ErrTSMClosed
+	}
+
+	key := tierBlockKey{object: a.object, offset: entry.Offset}
+	if b := a.cache.get(key); b != nil {
+		a.tracker.IncBlockReads(true)
+		return b, nil
+	}
+	a.tracker.IncBlockReads(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), tierRequestTimeout)
+	defer cancel()
+
+	b := make([]byte, entry.Size)
+	if err := a.store.ReadAt(ctx, a.object, b, entry.Offset); err != nil {
+		return nil, err
+	}
+	a.tracker.AddReadBytes(uint64(len(b)))
+	a.cache.put(key, b)
+	return b, nil
+}
+
+func (a *tierAccessor) read(key []byte, timestamp int64) ([]Value, error) {
+	entry := a.index.Entry(key, timestamp)
+	if entry == nil {
+		return nil, nil
+	}
+
+	return a.readBlock(entry, nil)
+}
+
+func (a *tierAccessor) readBlock(entry *IndexEntry, values []Value) ([]Value, error) {
+	b, err := a.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBlock(b[4:], values)
+}
+
+func (a *tierAccessor) readBytes(entry *IndexEntry, _ []byte) (uint32, []byte, error) {
+	b, err := a.block(entry)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// return the bytes after the 4 byte checksum
+	return binary.BigEndian.Uint32(b[:4]), b[4:], nil
+}
+
+// readAll returns all values for a key in all blocks.
+func (a *tierAccessor) readAll(key []byte) ([]Value, error) {
+	blocks, err := a.index.ReadEntries(key, nil)
+	if len(blocks) == 0 || err != nil {
+		return nil, err
+	}
+
+	tombstones := a.index.TombstoneRange(key, nil)
+
+	var temp []Value
+	var values []Value
+	for i := range blocks {
+		block := &blocks[i]
+
+		var skip bool
+		for _, t := range tombstones {
+			// Should we skip this block because it contains points that have been deleted
+			if t.Min <= block.MinTime && t.Max >= block.MaxTime {
+				skip = true
+				break
+			}
+		}
+
+		if skip {
+			continue
+		}
+
+		temp, err = a.readBlock(block, temp[:0])
+		if err != nil {
+			return nil, err
+		}
+
+		// Filter out any values that were deleted
+		for _, t := range tombstones {
+			temp = Values(temp).Exclude(t.Min, t.Max)
+		}
+
+		values = append(values, temp...)
+	}
+
+	return values, nil
+}
+
+// tierCopyBufferSize is the size of the reads of an object copied out of the
+// storage tier.
+const tierCopyBufferSize = 4 << 20
+
+// copyObject copies the TSM file of the accessor from the storage tier to a
+// new file at path.
+func (a *tierAccessor) copyObject(ctx context.Context, path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+
+	r := io.NewSectionReader(&tierObjectReader{ctx: ctx, store: a.store, object: a.object}, 0, a.size)
+	n, err := io.CopyBuffer(f, r, make([]byte, tierCopyBufferSize))
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	a.tracker.AddReadBytes(uint64(n))
+	return nil
+}
+
+// rename renames the local marker of the file, the object is left in place.
+func (a *tierAccessor) rename(path string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := fs.RenameFileWithReplacement(TierMarkerFilename(a._path), TierMarkerFilename(path)); err != nil {
+		return err
+	}
+	a._path = path
+	return nil
+}
+
+func (a *tierAccessor) path() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a._path
+}
+
+func (a *tierAccessor) close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+	return nil
+}
+
+// free is a noop, the block cache is bounded on its own.
+func (a *tierAccessor) free() error {
+	return nil
+}
+
+// remove deletes the object of the file from the tier store, then its marker.
+func (a *tierAccessor) remove() error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), tierRequestTimeout)
+	defer cancel()
+
+	if err := a.store.Delete(ctx, a.object); err != nil {
+		return err
+	}
+	a.cache.evictObject(a.object)
+	if err := os.Remove(TierMarkerFilename(a._path)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// tierObjectReader adapts an object of a tier store to an io.ReaderAt.
+type tierObjectReader struct {
+	ctx    context.Context
+	store  TierStore
+	object string
+}
+
+func (r *tierObjectReader) ReadAt(p []byte, off int64) (int, error) {
+	if err := r.store.ReadAt(r.ctx, r.object, p, off); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+type tierBlockKey struct {
+	object string
+	offset int64
+}
+
+type tierBlockCacheEntry struct {
+	key tierBlockKey
+	b   []byte
+}
+
+// tierBlockCache is a least recently used cache of the blocks read from the
+// storage tier, bounded by the total size of the blocks.
+type tierBlockCache struct {
+	mu      sync.Mutex
+	maxSize uint64
+	size    uint64
+	lru     *list.List
+	entries map[tierBlockKey]*list.Element
+}
+
+func newTierBlockCache(maxSize uint64) *tierBlockCache {
+	return &tierBlockCache{
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[tierBlockKey]*list.Element),
+	}
+}
+
+// get returns the cached block of key, or nil.
+func (c *tierBlockCache) get(key tierBlockKey) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*tierBlockCacheEntry).b
+}
+
+// put caches the block of key, evicting the least recently used blocks when
+// the cache is full. Blocks larger than the cache are not cached.
+func (c *tierBlockCache) put(key tierBlockKey, b []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; ok || uint64(len(b)) > c.maxSize {
+		return
+	}
+
+	for c.size+uint64(len(b)) > c.maxSize {
+		c.removeElement(c.lru.Back())
+	}
+	c.entries[key] = c.lru.PushFront(&tierBlockCacheEntry{key: key, b: b})
+	c.size += uint64(len(b))
+}
+
+// evictObject removes the cached blocks of a deleted object.
+func (c *tierBlockCache) evictObject(object string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, e := range c.entries {
+		if key.object == object {
+			c.removeElement(e)
+		}
+	}
+}
+
+func (c *tierBlockCache) removeElement(e *list.Element) {
+	entry := c.lru.Remove(e).(*tierBlockCacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= uint64(len(entry.b))
+}
@@ -0,0 +1,44 @@
+package tsm1
+
+import (
+	"testing"
+)
+
+func TestTierBlockCache(t *testing.T) {
+	c := newTierBlockCache(10)
+	a := tierBlockKey{object: "a.tsm", offset: 5}
+	b := tierBlockKey{object: "a.tsm", offset: 9}
+	d := tierBlockKey{object: "b.tsm", offset: 5}
+
+	c.put(a, make([]byte, 4))
+	c.put(b, make([]byte, 4))
+	if c.get(a) == nil {
+		t.Fatal("expected block to be cached")
+	}
+
+	// The least recently used block is evicted.
+	c.put(d, make([]byte, 4))
+	if c.get(b) != nil {
+		t.Fatal("expected block to be evicted")
+	}
+	if c.get(a) == nil || c.get(d) == nil {
+		t.Fatal("expected blocks to be cached")
+	}
+	if c.size != 8 {
+		t.Fatalf("unexpected size: got %d, exp 8", c.size)
+	}
+
+	// Blocks larger than the cache are not cached.
+	c.put(b, make([]byte, 11))
+	if c.get(b) != nil {
+		t.Fatal("expected block not to be cached")
+	}
+
+	c.evictObject("a.tsm")
+	if c.get(a) != nil || c.get(d) == nil {
+		t.Fatal("expected only the blocks of the object to be evicted")
+	}
+	if c.size != 4 {
+		t.Fatalf("unexpected size: got %d, exp 4", c.size)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"hash/crc32"
 	"io"
+	"os"
 	"sort"
 	"strings"
 
@@ -209,6 +210,22 @@ func (s MeasurementStats) writeMeasurementTo(w io.Writer, name string, sz int) (
 	return n, err
 }
 
+// sidecarFilenames returns the paths to the stats and index files written
+// next to a given TSM file path.
+func sidecarFilenames(tsmPath string) []string {
+	return []string{StatsFilename(tsmPath), BlockStatsFilename(tsmPath), ValueIndexFilename(tsmPath)}
+}
+
+// removeSidecarFiles removes the stats and index files of a given TSM file path.
+func removeSidecarFiles(tsmPath string) error {
+	for _, path := range sidecarFilenames(tsmPath) {
+		if err := os.RemoveAll(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 // StatsFilename returns the path to the stats file for a given TSM file path.
 func StatsFilename(tsmPath string) string {
 	if strings.HasSuffix(tsmPath, "."+TmpTSMFileExtension) {
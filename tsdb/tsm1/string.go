@@ -1,26 +1,131 @@
 package tsm1
 
-// String encoding uses snappy compression to compress each string.  Each string is
-// appended to byte slice prefixed with a variable byte length followed by the string
-// bytes.  The bytes are compressed using snappy compressor and a 1 byte header is used
-// to indicate the type of encoding.
+// String encoding uses snappy or zstd compression to compress each string.  Each string
+// is appended to byte slice prefixed with a variable byte length followed by the string
+// bytes.  The bytes are compressed using the snappy or zstd compressor and a 1 byte header
+// is used to indicate the type of encoding.
 
 import (
 	"encoding/binary"
 	"fmt"
+	"sync"
 
 	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 )
 
 // Note: an uncompressed format is not yet implemented.
 
-// stringCompressedSnappy is a compressed encoding using Snappy compression
-const stringCompressedSnappy = 1
+const (
+	// stringCompressedSnappy is a compressed encoding using Snappy compression
+	stringCompressedSnappy = 1
+
+	// stringCompressedZstd is a compressed encoding using Zstandard compression
+	stringCompressedZstd = 2
+)
+
+// StringCompression is the compression of the values of string blocks.
+type StringCompression byte
+
+const (
+	// StringCompressionSnappy compresses string values with snappy. It is the
+	// default compression.
+	StringCompressionSnappy StringCompression = stringCompressedSnappy
+
+	// StringCompressionZstd compresses string values with zstd, which is slower
+	// than snappy but compresses log-like values much better.
+	StringCompressionZstd StringCompression = stringCompressedZstd
+)
+
+// ParseStringCompression returns the compression named s. An empty name is
+// the default snappy compression.
+func ParseStringCompression(s string) (StringCompression, error) {
+	switch s {
+	case "", "snappy":
+		return StringCompressionSnappy, nil
+	case "zstd":
+		return StringCompressionZstd, nil
+	}
+	return 0, fmt.Errorf("unknown string compression %q", s)
+}
+
+// String returns the name of the compression.
+func (c StringCompression) String() string {
+	switch c {
+	case StringCompressionSnappy:
+		return "snappy"
+	case StringCompressionZstd:
+		return "zstd"
+	}
+	return fmt.Sprintf("StringCompression(%d)", byte(c))
+}
+
+// A StringCompressionFunc returns the compression of the string blocks of
+// the series key.
+type StringCompressionFunc func(key []byte) StringCompression
+
+// stringCompressionOf returns the compression fn chooses for key, or the
+// default snappy compression if fn is nil.
+func stringCompressionOf(fn StringCompressionFunc, key []byte) StringCompression {
+	if fn == nil {
+		return StringCompressionSnappy
+	}
+	return fn(key)
+}
+
+// The zstd encoder and decoder are safe for concurrent use through EncodeAll
+// and DecodeAll, and are shared by all string blocks. They are created on
+// first use, so that programs not using zstd don't start their goroutines.
+var (
+	zstdEncoderOnce sync.Once
+	zstdEncoder     *zstd.Encoder
+	zstdEncoderErr  error
+
+	zstdDecoderOnce sync.Once
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderErr  error
+)
+
+// sharedZstdEncoder returns the zstd encoder shared by all string blocks.
+func sharedZstdEncoder() (*zstd.Encoder, error) {
+	zstdEncoderOnce.Do(func() {
+		zstdEncoder, zstdEncoderErr = zstd.NewWriter(nil)
+	})
+	return zstdEncoder, zstdEncoderErr
+}
+
+// sharedZstdDecoder returns the zstd decoder shared by all string blocks.
+func sharedZstdDecoder() (*zstd.Decoder, error) {
+	zstdDecoderOnce.Do(func() {
+		zstdDecoder, zstdDecoderErr = zstd.NewReader(nil)
+	})
+	return zstdDecoder, zstdDecoderErr
+}
+
+// decompressStrings returns the decompressed values of the encoded string
+// values b. The returned slice is always newly allocated, as decoded strings
+// reference it directly.
+func decompressStrings(b []byte) ([]byte, error) {
+	switch b[0] >> 4 {
+	case stringCompressedSnappy:
+		return snappy.Decode(nil, b[1:])
+	case stringCompressedZstd:
+		dec, err := sharedZstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return dec.DecodeAll(b[1:], nil)
+	}
+	return nil, fmt.Errorf("unknown string compression %d", b[0]>>4)
+}
 
 // StringEncoder encodes multiple strings into a byte slice.
 type StringEncoder struct {
 	// The encoded bytes
 	bytes []byte
+
+	// compression of the encoded bytes, snappy if not set.
+	compression StringCompression
 }
 
 // NewStringEncoder returns a new StringEncoder with an initial buffer ready to hold sz bytes.
@@ -33,11 +138,16 @@ func NewStringEncoder(sz int) StringEncoder {
 // Flush is no-op
 func (e *StringEncoder) Flush() {}
 
-// Reset sets the encoder back to its initial state.
+// Reset sets the encoder back to its initial state, keeping its compression.
 func (e *StringEncoder) Reset() {
 	e.bytes = e.bytes[:0]
 }
 
+// SetCompression sets the compression of the bytes returned by Bytes.
+func (e *StringEncoder) SetCompression(c StringCompression) {
+	e.compression = c
+}
+
 // Write encodes s to the underlying buffer.
 func (e *StringEncoder) Write(s string) {
 	b := make([]byte, 10)
@@ -51,8 +161,15 @@ func (e *StringEncoder) Write(s string) {
 
 // Bytes returns a copy of the underlying buffer.
 func (e *StringEncoder) Bytes() ([]byte, error) {
-	// Compress the currently appended bytes using snappy and prefix with
-	// a 1 byte header for future extension
+	// Compress the currently appended bytes and prefix with a 1 byte header
+	// of the compression used
+	if e.compression == StringCompressionZstd {
+		enc, err := sharedZstdEncoder()
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(e.bytes, []byte{stringCompressedZstd << 4}), nil
+	}
 	data := snappy.Encode(nil, e.bytes)
 	return append([]byte{stringCompressedSnappy << 4}, data...), nil
 }
@@ -68,12 +185,11 @@ type StringDecoder struct {
 // SetBytes initializes the decoder with bytes to read from.
 // This must be called before calling any other method.
 func (e *StringDecoder) SetBytes(b []byte) error {
-	// First byte stores the encoding type, only have snappy format
-	// currently so ignore for now.
+	// First byte stores the encoding type.
 	var data []byte
 	if len(b) > 0 {
 		var err error
-		data, err = snappy.Decode(nil, b[1:])
+		data, err = decompressStrings(b)
 		if err != nil {
 			return fmt.Errorf("failed to decode string block: %v", err.Error())
 		}
@@ -180,6 +180,97 @@ func Test_StringDecoder_CorruptSetBytes(t *testing.T) {
 	}
 }
 
+func Test_StringEncoder_Zstd(t *testing.T) {
+	enc := NewStringEncoder(1024)
+	enc.SetCompression(StringCompressionZstd)
+
+	values := make([]string, 100)
+	for i := range values {
+		values[i] = fmt.Sprintf("level=info msg=\"request served\" path=/api/v2/write status=204 req=%d", i)
+		enc.Write(values[i])
+	}
+
+	b, err := enc.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, exp := b[0]>>4, byte(stringCompressedZstd); got != exp {
+		t.Fatalf("unexpected header: got %v, exp %v", got, exp)
+	}
+
+	var dec StringDecoder
+	if err := dec.SetBytes(b); err != nil {
+		t.Fatalf("unexpected error creating string decoder: %v", err)
+	}
+
+	var got []string
+	for dec.Next() {
+		got = append(got, dec.Read())
+	}
+	if err := dec.Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(got, values) {
+		t.Fatalf("unexpected values: -got/+exp\n%s", cmp.Diff(got, values))
+	}
+
+	// Resetting the encoder keeps its compression, unlike the pooled encoders.
+	enc.Reset()
+	enc.Write(values[0])
+	if b, err = enc.Bytes(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if got, exp := b[0]>>4, byte(stringCompressedZstd); got != exp {
+		t.Fatalf("unexpected header after reset: got %v, exp %v", got, exp)
+	}
+
+	penc := getStringEncoder(1024)
+	penc.Write(values[0])
+	if b, err = penc.Bytes(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if got, exp := b[0]>>4, byte(stringCompressedSnappy); got != exp {
+		t.Fatalf("unexpected header of pooled encoder: got %v, exp %v", got, exp)
+	}
+	putStringEncoder(penc)
+}
+
+func Test_StringDecoder_UnknownCompression(t *testing.T) {
+	enc := NewStringEncoder(1024)
+	enc.Write("v1")
+	b, err := enc.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b[0] = 7 << 4
+
+	var dec StringDecoder
+	if err := dec.SetBytes(b); err == nil {
+		t.Fatal("exp an err, got nil")
+	}
+}
+
+func TestParseStringCompression(t *testing.T) {
+	for _, tc := range []struct {
+		s   string
+		exp StringCompression
+	}{
+		{"", StringCompressionSnappy},
+		{"snappy", StringCompressionSnappy},
+		{"zstd", StringCompressionZstd},
+	} {
+		got, err := ParseStringCompression(tc.s)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", tc.s, err)
+		}
+		if got != tc.exp {
+			t.Fatalf("unexpected compression of %q: got %v, exp %v", tc.s, got, tc.exp)
+		}
+	}
+
+	if _, err := ParseStringCompression("gzip"); err == nil {
+		t.Fatal("exp an err, got nil")
+	}
+}
+
 func BenchmarkStringDecoder_DecodeAll(b *testing.B) {
 	benchmarks := []struct {
 		n int
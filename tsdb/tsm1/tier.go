@@ -0,0 +1,275 @@
+package tsm1
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/pkg/fs"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// ErrNoTierStore is returned when moving files without a storage tier configured.
+var ErrNoTierStore = errors.New("no storage tier configured")
+
+// tierRequestTimeout bounds the requests to the storage tier that are not made
+// on behalf of a caller with a context, such as the reads of blocks.
+const tierRequestTimeout = time.Minute
+
+// WithTierStore sets the store that TSM files are moved to by Tier, and the
+// maximum size of the blocks read from it that are cached in memory. It must
+// be called before the file store is opened.
+func (f *FileStore) WithTierStore(store TierStore, cacheSize uint64) {
+	f.tierStore = store
+	f.tierCache = newTierBlockCache(cacheSize)
+}
+
+// Tier moves the TSM files at paths to the storage tier, leaving a marker in
+// place of each file. Reads of moved files go through to the tier. Files in use
+// are skipped, so that they can be moved by a later call.
+func (f *FileStore) Tier(ctx context.Context, paths []string) error {
+	if f.tierStore == nil {
+		return ErrNoTierStore
+	}
+
+	for _, path := range paths {
+		moved, err := f.tierFile(ctx, path)
+		if err != nil {
+			f.tierTracker.IncMoves(false)
+			return err
+		} else if moved {
+			f.tierTracker.IncMoves(true)
+			f.logger.Info("Moved file to the storage tier", zap.String("path", path))
+		}
+	}
+	return nil
+}
+
+func (f *FileStore) tierFile(ctx context.Context, path string) (bool, error) {
+	r := f.TSMReader(path)
+	if r == nil {
+		// The file was removed in the meantime.
+		return false, nil
+	}
+	tiered := r.tiered()
+	r.Unref()
+	if tiered {
+		return false, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return false, err
+	}
+
+	object := filepath.Base(path)
+	err = f.tierStore.Put(ctx, object, file, stat.Size())
+	file.Close()
+	if err != nil {
+		return false, err
+	}
+
+	marker := TierMarkerFilename(path)
+	if err := writeTierMarker(marker, &tierMarker{
+		Object:       object,
+		Size:         stat.Size(),
+		LastModified: stat.ModTime().UnixNano(),
+	}); err != nil {
+		f.deleteTierObject(object)
+		return false, err
+	}
+
+	tr, err := newTieredTSMReader(marker, f.tierStore, f.tierCache, f.tierTracker, WithTSMReaderLogger(f.logger))
+	if err != nil {
+		os.Remove(marker)
+		f.deleteTierObject(object)
+		return false, err
+	}
+	tr.WithObserver(f.obs)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// InUse is only valid under the write lock, see replace.
+	i := -1
+	for j, file := range f.files {
+		if file.Path() == path {
+			i = j
+			break
+		}
+	}
+	if i == -1 || f.files[i].InUse() {
+		// Leave the file in place, if it's still there, and drop its object.
+		tr.Close()
+		f.deleteTierObject(object)
+		return false, os.Remove(marker)
+	}
+
+	if err := f.obs.FileUnlinking(path); err != nil {
+		return false, err
+	}
+	if err := f.files[i].Close(); err != nil {
+		return false, err
+	}
+	if err := os.Remove(path); err != nil {
+		return false, err
+	}
+	if err := fs.SyncDir(f.dir); err != nil {
+		return false, err
+	}
+
+	f.files[i] = tr
+	f.lastFileStats = nil
+
+	// The set of files to plan compactions from changed.
+	f.lastModified = f.lastModified.Add(1)
+
+	return true, f.recalculateFileStats()
+}
+
+// deleteTierObject deletes an object stored for a file that was not moved. The
+// object is only left unused if it can't be deleted.
+func (f *FileStore) deleteTierObject(object string) {
+	ctx, cancel := context.WithTimeout(context.Background(), tierRequestTimeout)
+	defer cancel()
+	if err := f.tierStore.Delete(ctx, object); err != nil {
+		f.logger.Info("Unable to delete unused object from the storage tier", zap.String("object", object), zap.Error(err))
+	}
+}
+
+// openTieredTSMReader opens the TSM file of the tier marker at path.
+func (f *FileStore) openTieredTSMReader(path string) (*TSMReader, error) {
+	if f.tierStore == nil {
+		return nil, ErrNoTierStore
+	}
+	return newTieredTSMReader(path, f.tierStore, f.tierCache, f.tierTracker, WithTSMReaderLogger(f.logger))
+}
+
+// removeTierMarker removes the tier marker at path and, if a tier store is
+// configured, the object it refers to.
+func (f *FileStore) removeTierMarker(path string) error {
+	if f.tierStore != nil {
+		m, err := readTierMarker(path)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), tierRequestTimeout)
+		defer cancel()
+		if err := f.tierStore.Delete(ctx, m.Object); err != nil {
+			return err
+		}
+	}
+	return os.Remove(path)
+}
+
+// DeleteTierObjects deletes the objects of the tier markers in dir from the
+// storage tier configured by c, for the files of an engine that is removed
+// without being opened. The directory of a Dir tier is removed once empty.
+func DeleteTierObjects(ctx context.Context, c TierConfig, dir string) error {
+	store, err := NewTierStore(c)
+	if err != nil || store == nil {
+		return err
+	}
+
+	// Markers renamed while being removed by compactions are matched too.
+	markers, err := filepath.Glob(filepath.Join(dir, "*."+TierTSMFileExtension))
+	if err != nil {
+		return err
+	}
+	for _, marker := range markers {
+		m, err := readTierMarker(marker)
+		if err != nil {
+			return err
+		}
+		if err := store.Delete(ctx, m.Object); err != nil {
+			return err
+		}
+	}
+
+	if c.Dir != "" {
+		if err := os.Remove(c.Dir); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// tierTracker tracks the files in the storage tier and the reads from it.
+type tierTracker struct {
+	metrics *tierMetrics
+	labels  prometheus.Labels
+	files   uint64
+	bytes   uint64
+
+	cacheHits   prometheus.Counter
+	cacheMisses prometheus.Counter
+}
+
+func newTierTracker(metrics *tierMetrics, defaultLabels prometheus.Labels) *tierTracker {
+	t := &tierTracker{metrics: metrics, labels: defaultLabels}
+
+	labels := t.Labels()
+	labels["cache"] = "hit"
+	t.cacheHits = metrics.BlockReads.With(labels)
+	labels["cache"] = "miss"
+	t.cacheMisses = metrics.BlockReads.With(labels)
+	return t
+}
+
+// Labels returns a copy of the default labels used by the tracker's metrics.
+// The returned map is safe for modification.
+func (t *tierTracker) Labels() prometheus.Labels {
+	labels := make(prometheus.Labels, len(t.labels))
+	for k, v := range t.labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// Files returns the number of files in the storage tier.
+func (t *tierTracker) Files() uint64 { return atomic.LoadUint64(&t.files) }
+
+// Bytes returns the size of the files in the storage tier.
+func (t *tierTracker) Bytes() uint64 { return atomic.LoadUint64(&t.bytes) }
+
+// SetFiles sets the number and size of the files in the storage tier.
+func (t *tierTracker) SetFiles(files, bytes uint64) {
+	atomic.StoreUint64(&t.files, files)
+	atomic.StoreUint64(&t.bytes, bytes)
+	t.metrics.Files.With(t.labels).Set(float64(files))
+	t.metrics.Bytes.With(t.labels).Set(float64(bytes))
+}
+
+// AddReadBytes increases the number of bytes read from the storage tier.
+func (t *tierTracker) AddReadBytes(n uint64) {
+	t.metrics.ReadBytes.With(t.labels).Add(float64(n))
+}
+
+// IncBlockReads increases the number of blocks read from tiered files, served
+// by the block cache or not.
+func (t *tierTracker) IncBlockReads(hit bool) {
+	if hit {
+		t.cacheHits.Inc()
+	} else {
+		t.cacheMisses.Inc()
+	}
+}
+
+// IncMoves increases the number of files moved to the storage tier.
+func (t *tierTracker) IncMoves(ok bool) {
+	labels := t.Labels()
+	labels["status"] = "ok"
+	if !ok {
+		labels["status"] = "error"
+	}
+	t.metrics.Moves.With(labels).Inc()
+}
@@ -0,0 +1,285 @@
+package tsm1
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/pkg/fs"
+)
+
+// TierStore stores the TSM files moved to the storage tier as objects.
+type TierStore interface {
+	// Put stores the size bytes read from r as the object name, replacing any
+	// existing object of that name.
+	Put(ctx context.Context, name string, r io.Reader, size int64) error
+
+	// ReadAt reads len(p) bytes of the object name starting at offset off.
+	ReadAt(ctx context.Context, name string, p []byte, off int64) error
+
+	// Delete removes the object name. Removing a missing object is not an error.
+	Delete(ctx context.Context, name string) error
+}
+
+// NewTierStore returns the tier store set in the config, or nil if tiering is
+// not configured.
+func NewTierStore(c TierConfig) (TierStore, error) {
+	switch {
+	case c.Dir != "" && c.S3.Bucket != "":
+		return nil, errors.New("tier: only one of a directory and an S3 bucket can be set")
+	case c.Dir != "":
+		return NewDirTierStore(c.Dir), nil
+	case c.S3.Bucket != "":
+		if c.S3.Endpoint == "" {
+			return nil, errors.New("tier: an S3 bucket requires an endpoint")
+		}
+		return NewS3TierStore(c.S3), nil
+	}
+	return nil, nil
+}
+
+// DirTierStore is a TierStore keeping objects as files of a directory,
+// typically on a cheaper disk.
+type DirTierStore struct {
+	dir string
+}
+
+// NewDirTierStore returns a TierStore keeping objects in dir.
+func NewDirTierStore(dir string) *DirTierStore {
+	return &DirTierStore{dir: dir}
+}
+
+// Put writes the object to a temporary file renamed into place once synced.
+func (s *DirTierStore) Put(ctx context.Context, name string, r io.Reader, size int64) error {
+	if err := os.MkdirAll(s.dir, 0777); err != nil {
+		return err
+	}
+
+	p := filepath.Join(s.dir, name)
+	tmp := p + "." + TmpTSMFileExtension
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(f, r, size); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := fs.RenameFileWithReplacement(tmp, p); err != nil {
+		return err
+	}
+	return fs.SyncDir(s.dir)
+}
+
+// ReadAt reads from the file of the object.
+func (s *DirTierStore) ReadAt(ctx context.Context, name string, p []byte, off int64) error {
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.ReadAt(p, off)
+	return err
+}
+
+// Delete removes the file of the object.
+func (s *DirTierStore) Delete(ctx context.Context, name string) error {
+	if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// s3UnsignedPayload is the payload hash of requests whose body is not signed.
+const s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// s3ResponseHeaderTimeout bounds how long a request waits for the object store
+// to respond once it is sent, without limiting the transfer of large objects.
+const s3ResponseHeaderTimeout = time.Minute
+
+// S3TierStore is a TierStore keeping objects in a bucket of an S3 compatible
+// object store. Buckets are addressed by path and requests are signed with
+// AWS Signature Version 4.
+type S3TierStore struct {
+	config S3TierConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3TierStore returns a TierStore keeping objects in the bucket set in c.
+func NewS3TierStore(c S3TierConfig) *S3TierStore {
+	if c.Region == "" {
+		c.Region = DefaultTierS3Region
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = s3ResponseHeaderTimeout
+	return &S3TierStore{
+		config: c,
+		client: &http.Client{Transport: transport},
+		now:    time.Now,
+	}
+}
+
+// objectURL returns the URL of the object name.
+func (s *S3TierStore) objectURL(name string) string {
+	return strings.TrimSuffix(s.config.Endpoint, "/") + "/" + path.Join(s.config.Bucket, s.config.Prefix, name)
+}
+
+// Put uploads the object in a single request.
+func (s *S3TierStore) Put(ctx context.Context, name string, r io.Reader, size int64) error {
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(name), ioutil.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+
+	resp, err := s.do(ctx, req, s3UnsignedPayload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(req, resp)
+	}
+	return nil
+}
+
+// ReadAt requests the range of the object.
+func (s *S3TierStore) ReadAt(ctx context.Context, name string, p []byte, off int64) error {
+	req, err := http.NewRequest(http.MethodGet, s.objectURL(name), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1))
+
+	resp, err := s.do(ctx, req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return s3Error(req, resp)
+	}
+	_, err = io.ReadFull(resp.Body, p)
+	return err
+}
+
+// Delete removes the object.
+func (s *S3TierStore) Delete(ctx context.Context, name string) error {
+	req, err := http.NewRequest(http.MethodDelete, s.objectURL(name), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(ctx, req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	}
+	return s3Error(req, resp)
+}
+
+func (s *S3TierStore) do(ctx context.Context, req *http.Request, payloadHash string) (*http.Response, error) {
+	req = req.WithContext(ctx)
+	signS3Request(req, s.config, payloadHash, s.now().UTC())
+	return s.client.Do(req)
+}
+
+func s3Error(req *http.Request, resp *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("tier: %s %s: unexpected status %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+}
+
+// emptyPayloadHash is the hex encoded SHA256 of an empty payload.
+var emptyPayloadHash = func() string {
+	sum := sha256.Sum256(nil)
+	return hex.EncodeToString(sum[:])
+}()
+
+// signS3Request signs req with AWS Signature Version 4 at time t, setting its
+// x-amz-date, x-amz-content-sha256 and Authorization headers. The host, range
+// and x-amz-* headers are signed.
+func signS3Request(req *http.Request, c S3TierConfig, payloadHash string, t time.Time) {
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if k == "range" || strings.HasPrefix(k, "x-amz-") {
+			headers[k] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		(&url.URL{Path: req.URL.Path}).EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + c.Region + "/s3/aws4_request"
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+
+	key := hmacSHA256([]byte("AWS4"+c.SecretAccessKey), date)
+	key = hmacSHA256(key, c.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,282 @@
+package tsm1_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+)
+
+func TestFileStore_Tier(t *testing.T) {
+	s3 := newS3StandIn("access")
+	srv := httptest.NewServer(s3)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name  string
+		store func(dir string) tsm1.TierStore
+	}{
+		{
+			name: "directory",
+			store: func(dir string) tsm1.TierStore {
+				return tsm1.NewDirTierStore(filepath.Join(dir, "tier"))
+			},
+		},
+		{
+			name: "s3",
+			store: func(string) tsm1.TierStore {
+				return tsm1.NewS3TierStore(tsm1.S3TierConfig{
+					Endpoint:        srv.URL,
+					Bucket:          "influxdb",
+					Prefix:          "engine",
+					AccessKeyID:     "access",
+					SecretAccessKey: "secret",
+				})
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := MustTempDir()
+			defer os.RemoveAll(dir)
+			store := tc.store(dir)
+			ctx := context.Background()
+
+			data := []keyValues{
+				{"cpu", []tsm1.Value{tsm1.NewValue(0, 1.0), tsm1.NewValue(1, 2.0)}},
+				{"mem", []tsm1.Value{tsm1.NewValue(0, int64(1)), tsm1.NewValue(1, int64(2))}},
+			}
+			files, err := newFiles(dir, data...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			open := func() *tsm1.FileStore {
+				fs := tsm1.NewFileStore(dir)
+				fs.WithTierStore(store, 1<<20)
+				if err := fs.Open(ctx); err != nil {
+					t.Fatal(err)
+				}
+				return fs
+			}
+			check := func(fs *tsm1.FileStore) {
+				for _, d := range data {
+					values, err := fs.Read([]byte(d.key), 0)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !reflect.DeepEqual(values, d.values) {
+						t.Fatalf("unexpected values of %s: got %v, exp %v", d.key, values, d.values)
+					}
+				}
+				if stats := fs.Stats(); !stats[0].Tiered || stats[1].Tiered {
+					t.Fatalf("expected only the first file to be tiered: %+v", stats)
+				}
+			}
+
+			tsm, err := ioutil.ReadFile(files[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fs := open()
+			if err := fs.Tier(ctx, files[:1]); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(files[0]); !os.IsNotExist(err) {
+				t.Fatalf("expected the local file to be removed: %v", err)
+			}
+			if _, err := os.Stat(tsm1.TierMarkerFilename(files[0])); err != nil {
+				t.Fatal(err)
+			}
+			check(fs)
+
+			// Snapshots hold the contents of tiered files, not their markers.
+			_, snapshot, err := fs.CreateSnapshot(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b, err := ioutil.ReadFile(filepath.Join(snapshot, filepath.Base(files[0]))); err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(b, tsm) {
+				t.Fatal("unexpected contents of tiered file in snapshot")
+			}
+			if _, err := os.Stat(filepath.Join(snapshot, filepath.Base(tsm1.TierMarkerFilename(files[0])))); !os.IsNotExist(err) {
+				t.Fatalf("unexpected marker in snapshot: %v", err)
+			}
+
+			if err := fs.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			fs = open()
+			defer fs.Close()
+			check(fs)
+
+			// Removing a tiered file removes its object.
+			if err := fs.Replace(files[:1], nil); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(tsm1.TierMarkerFilename(files[0])); !os.IsNotExist(err) {
+				t.Fatalf("expected the marker to be removed: %v", err)
+			}
+			if err := store.ReadAt(ctx, filepath.Base(files[0]), make([]byte, 1), 0); err == nil {
+				t.Fatal("expected the object to be removed")
+			}
+		})
+	}
+}
+
+func TestFileStore_Tier_NoStore(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	fs := tsm1.NewFileStore(dir)
+	if err := fs.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if err := fs.Tier(context.Background(), []string{"000000001-000000004.tsm"}); err != tsm1.ErrNoTierStore {
+		t.Fatalf("unexpected error: got %v, exp %v", err, tsm1.ErrNoTierStore)
+	}
+}
+
+func TestFileStore_Tier_InUse(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	tierDir := filepath.Join(dir, "tier")
+	ctx := context.Background()
+
+	files, err := newFiles(dir, keyValues{"cpu", []tsm1.Value{tsm1.NewValue(0, 1.0)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := tsm1.NewFileStore(dir)
+	fs.WithTierStore(tsm1.NewDirTierStore(tierDir), 1<<20)
+	if err := fs.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	r := fs.TSMReader(files[0])
+	if err := fs.Tier(ctx, files); err != nil {
+		t.Fatal(err)
+	}
+	r.Unref()
+
+	if _, err := os.Stat(files[0]); err != nil {
+		t.Fatalf("expected the file in use to be left in place: %v", err)
+	}
+	if _, err := os.Stat(tsm1.TierMarkerFilename(files[0])); !os.IsNotExist(err) {
+		t.Fatalf("unexpected marker: %v", err)
+	}
+	if objects, err := filepath.Glob(filepath.Join(tierDir, "*")); err != nil {
+		t.Fatal(err)
+	} else if len(objects) != 0 {
+		t.Fatalf("expected the object of the file in use to be deleted: %v", objects)
+	}
+}
+
+func TestDeleteTierObjects(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+
+	config := tsm1.NewTierConfig()
+	config.Dir = filepath.Join(dir, "tier")
+	config = config.WithPrefix("bucket")
+
+	files, err := newFiles(dir, keyValues{"cpu", []tsm1.Value{tsm1.NewValue(0, 1.0)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := tsm1.NewTierStore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := tsm1.NewFileStore(dir)
+	fs.WithTierStore(store, 1<<20)
+	if err := fs.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Tier(ctx, files); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tier", "bucket", filepath.Base(files[0]))); err != nil {
+		t.Fatalf("expected the object under the prefix: %v", err)
+	}
+
+	if err := tsm1.DeleteTierObjects(ctx, config, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(config.Dir); !os.IsNotExist(err) {
+		t.Fatalf("expected the objects and their directory to be removed: %v", err)
+	}
+}
+
+// s3StandIn is an in-memory stand-in for an S3 compatible object store.
+type s3StandIn struct {
+	accessKeyID string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newS3StandIn(accessKeyID string) *s3StandIn {
+	return &s3StandIn{
+		accessKeyID: accessKeyID,
+		objects:     make(map[string][]byte),
+	}
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="+s.accessKeyID+"/") {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.objects[r.URL.Path] = b
+	case http.MethodGet:
+		b, ok := s.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		var start, end int
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err != nil || end >= len(b) {
+			http.Error(w, "InvalidRange", http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(b)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(b[start : end+1])
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
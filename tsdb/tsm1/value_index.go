@@ -0,0 +1,189 @@
+package tsm1
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2/pkg/bloom"
+)
+
+const (
+	// ValueIndexMagicNumber is written as the first 4 bytes of a value index
+	// file to identify the file as a tsm1 value index file.
+	ValueIndexMagicNumber string = "TSV1"
+
+	// ValueIndexVersion indicates the version of the TSV1 file format. Readers
+	// ignore value index files with a different version and read every block.
+	ValueIndexVersion byte = 1
+
+	// ValueIndexFalsePositiveRate is the false positive rate of the bloom
+	// filters of the values of the blocks.
+	ValueIndexFalsePositiveRate = 0.01
+)
+
+// A ValueIndexFunc reports whether the values of the blocks of the series key
+// are indexed, so queries with conditions on the values can skip the blocks
+// that cannot match.
+type ValueIndexFunc func(key []byte) bool
+
+// appendIndexValue appends the encoding of v used by the value index to b.
+// v is the value of a float, integer, unsigned, string or boolean block.
+func appendIndexValue(b []byte, v interface{}) []byte {
+	var buf [8]byte
+	switch v := v.(type) {
+	case float64:
+		// Both zeros compare equal, so they must be indexed as one.
+		if v == 0 {
+			v = 0
+		}
+		binary.BigEndian.PutUint64(buf[:], math.Float64bits(v))
+		return append(b, buf[:]...)
+	case int64:
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		return append(b, buf[:]...)
+	case uint64:
+		binary.BigEndian.PutUint64(buf[:], v)
+		return append(b, buf[:]...)
+	case string:
+		return append(b, v...)
+	case bool:
+		if v {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	default:
+		panic(fmt.Sprintf("unsupported index value type: %T", v))
+	}
+}
+
+// newValueFilter returns a bloom filter of values, sized for the number of
+// distinct values.
+func newValueFilter(values []Value) *bloom.Filter {
+	distinct := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		distinct[string(appendIndexValue(nil, v.Value()))] = struct{}{}
+	}
+
+	m, k := bloom.Estimate(uint64(len(distinct)), ValueIndexFalsePositiveRate)
+	f := bloom.NewFilter(m, k)
+	for v := range distinct {
+		f.Insert([]byte(v))
+	}
+	return f
+}
+
+// ValueIndexEntry is the bloom filter of the values of a single block.
+type ValueIndexEntry struct {
+	Offset int64 // offset of the block within the TSM file
+	Filter *bloom.Filter
+}
+
+// ValueIndex is the set of value filters of the blocks of a TSM file, sorted by offset.
+type ValueIndex []ValueIndexEntry
+
+// Find returns the value filter of the block at offset, if available.
+func (a ValueIndex) Find(offset int64) (*bloom.Filter, bool) {
+	i := sort.Search(len(a), func(i int) bool { return a[i].Offset >= offset })
+	if i < len(a) && a[i].Offset == offset {
+		return a[i].Filter, true
+	}
+	return nil, false
+}
+
+// ReadFrom reads a value index from r in a binary format.
+func (a *ValueIndex) ReadFrom(r io.Reader) (n int64, err error) {
+	data, err := ioutil.ReadAll(r)
+	if n = int64(len(data)); err != nil {
+		return n, fmt.Errorf("tsm1.ValueIndex.ReadFrom: cannot read value index: %s", err)
+	}
+
+	// Verify magic & version.
+	if len(data) < 9 || string(data[:4]) != ValueIndexMagicNumber {
+		return n, fmt.Errorf("tsm1.ValueIndex.ReadFrom: invalid tsm1 value index file")
+	} else if data[4] != ValueIndexVersion {
+		return n, fmt.Errorf("tsm1.ValueIndex.ReadFrom: incompatible tsm1 value index version: %d", data[4])
+	}
+
+	// Verify checksum.
+	checksum, data := binary.BigEndian.Uint32(data[5:9]), data[9:]
+	if crc32.ChecksumIEEE(data) != checksum {
+		return n, fmt.Errorf("tsm1.ValueIndex.ReadFrom: value index checksum mismatch")
+	}
+
+	// Read entries.
+	var entries ValueIndex
+	for len(data) > 0 {
+		if len(data) < 9 {
+			return n, fmt.Errorf("tsm1.ValueIndex.ReadFrom: short value index entry")
+		}
+		offset, k := int64(binary.BigEndian.Uint64(data[0:8])), uint64(data[8])
+		data = data[9:]
+
+		sz, nn := binary.Uvarint(data)
+		if nn <= 0 || uint64(len(data)-nn) < sz {
+			return n, fmt.Errorf("tsm1.ValueIndex.ReadFrom: cannot read value filter size")
+		}
+		data = data[nn:]
+
+		f, err := bloom.NewFilterBuffer(data[:sz:sz], k)
+		if err != nil {
+			return n, fmt.Errorf("tsm1.ValueIndex.ReadFrom: %s", err)
+		}
+		data = data[sz:]
+
+		entries = append(entries, ValueIndexEntry{Offset: offset, Filter: f})
+	}
+	*a = entries
+
+	return n, nil
+}
+
+// WriteTo writes a value index to w in a binary format.
+func (a ValueIndex) WriteTo(w io.Writer) (n int64, err error) {
+	// Write magic & version.
+	nn, err := io.WriteString(w, ValueIndexMagicNumber)
+	if n += int64(nn); err != nil {
+		return n, err
+	}
+	nn, err = w.Write([]byte{ValueIndexVersion})
+	if n += int64(nn); err != nil {
+		return n, err
+	}
+
+	// Write entries.
+	var buf bytes.Buffer
+	b := make([]byte, 9+binary.MaxVarintLen64)
+	for _, e := range a {
+		binary.BigEndian.PutUint64(b[0:8], uint64(e.Offset))
+		b[8] = byte(e.Filter.K())
+		sz := binary.PutUvarint(b[9:], uint64(len(e.Filter.Bytes())))
+		buf.Write(b[:9+sz])
+		buf.Write(e.Filter.Bytes())
+	}
+	data := buf.Bytes()
+
+	// Compute & write checksum.
+	if err := binary.Write(w, binary.BigEndian, crc32.ChecksumIEEE(data)); err != nil {
+		return n, err
+	}
+	n += 4
+
+	// Write buffer.
+	nn, err = w.Write(data)
+	n += int64(nn)
+	return n, err
+}
+
+// ValueIndexFilename returns the path to the value index file for a given TSM file path.
+func ValueIndexFilename(tsmPath string) string {
+	tsmPath = strings.TrimSuffix(tsmPath, "."+TmpTSMFileExtension)
+	tsmPath = strings.TrimSuffix(tsmPath, "."+TSMFileExtension)
+	return tsmPath + "." + TSVFileExtension
+}
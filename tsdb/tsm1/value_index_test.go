@@ -0,0 +1,58 @@
+package tsm1_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2/pkg/bloom"
+	"github.com/influxdata/influxdb/v2/tsdb/tsm1"
+)
+
+func TestValueIndex_WriteTo(t *testing.T) {
+	f0 := bloom.NewFilter(64, 4)
+	f0.Insert([]byte("ok"))
+	f1 := bloom.NewFilter(1024, 7)
+	f1.Insert([]byte("warn"))
+
+	index := tsm1.ValueIndex{{Offset: 5, Filter: f0}, {Offset: 40, Filter: f1}}
+
+	var buf bytes.Buffer
+	var other tsm1.ValueIndex
+	if wn, err := index.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	} else if rn, err := other.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	} else if wn != rn {
+		t.Fatalf("byte count mismatch: w=%d r=%d", wn, rn)
+	} else if len(other) != len(index) {
+		t.Fatalf("unexpected entries: %d", len(other))
+	}
+
+	for i, e := range other {
+		if e.Offset != index[i].Offset || e.Filter.K() != index[i].Filter.K() || !bytes.Equal(e.Filter.Bytes(), index[i].Filter.Bytes()) {
+			t.Fatalf("unexpected entry %d: %v", i, e)
+		}
+	}
+
+	if f, ok := other.Find(40); !ok || !f.Contains([]byte("warn")) {
+		t.Fatalf("unexpected filter: %v, %v", f, ok)
+	} else if _, ok := other.Find(41); ok {
+		t.Fatal("expected no filter for offset 41")
+	}
+}
+
+func TestValueIndex_ReadFrom_UnknownVersion(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := (tsm1.ValueIndex{{Offset: 5, Filter: bloom.NewFilter(64, 4)}}).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	b := buf.Bytes()
+	b[len(tsm1.ValueIndexMagicNumber)] = tsm1.ValueIndexVersion + 1
+
+	var index tsm1.ValueIndex
+	if _, err := index.ReadFrom(bytes.NewReader(b)); err == nil {
+		t.Fatal("expected error")
+	} else if len(index) != 0 {
+		t.Fatalf("unexpected index: %v", index)
+	}
+}
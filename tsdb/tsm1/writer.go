@@ -60,6 +60,31 @@ The last section is the footer that stores the offset of the start of the index.
 │Index Ofs│
 │ 8 bytes │
 └─────────┘
+
+The value statistics of float, integer and unsigned blocks are written to a
+separate block stats (.tsb) file next to the TSM file, so files remain readable
+by versions that predate it.  It starts with its own magic number and version,
+followed by a CRC32 of the remaining data, the count of entries and the entries
+ordered by block offset.  Min, Max and Sum hold the bits of the block's values.
+
+┌──────────────────────────────────────────────────────────────────────────────┐
+│                                 Block Stats                                  │
+├─────────┬─────────┬─────────┬─────────┬────────┬───────┬─────┬─────┬─────┬───┤
+│  Magic  │ Version │  CRC    │  Count  │ Offset │ Count │ Min │ Max │ Sum │...│
+│ 4 bytes │ 1 byte  │ 4 bytes │ varint  │8 bytes │8 bytes│  8  │  8  │  8  │   │
+└─────────┴─────────┴─────────┴─────────┴────────┴───────┴─────┴─────┴─────┴───┘
+
+Blocks of keys with indexed values also have a bloom filter of their values
+written to a value index (.tsv) file, so queries with conditions on the values
+can skip the blocks that cannot match.  Each entry holds the offset of the
+block, the number of hash functions of the filter, and the filter bytes.
+
+┌──────────────────────────────────────────────────────────────────────┐
+│                              Value Index                             │
+├─────────┬─────────┬─────────┬─────────┬────────┬────────┬───────┬────┤
+│  Magic  │ Version │  CRC    │ Offset  │   K    │  Len   │Filter │... │
+│ 4 bytes │ 1 byte  │ 4 bytes │ 8 bytes │ 1 byte │ varint │N bytes│    │
+└─────────┴─────────┴─────────┴─────────┴────────┴────────┴───────┴────┘
 */
 
 import (
@@ -75,6 +100,7 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/pkg/bloom"
 	"github.com/influxdata/influxdb/v2/pkg/fs"
 )
 
@@ -592,7 +618,12 @@ type tsmWriter struct {
 	// The bytes written count of when we last fsync'd
 	lastSync int64
 
-	stats MeasurementStats
+	stats      MeasurementStats
+	blockStats BlockStatsIndex
+
+	// indexValues reports whether the values of a key are indexed.
+	indexValues ValueIndexFunc
+	valueIndex  ValueIndex
 }
 
 // NewTSMWriter returns a new TSMWriter writing to w.
@@ -691,6 +722,16 @@ func (t *tsmWriter) Write(key []byte, values Values) error {
 	// Record this block in index
 	t.index.Add(key, blockType, values[0].UnixNano(), values[len(values)-1].UnixNano(), t.n, uint32(n))
 
+	// Record value statistics of numeric blocks.
+	if s, ok := valuesBlockStats(t.n, values); ok {
+		t.blockStats = append(t.blockStats, s)
+	}
+
+	// Index the values of the block, if enabled for the key.
+	if t.indexValues != nil && t.indexValues(key) {
+		t.valueIndex = append(t.valueIndex, ValueIndexEntry{Offset: t.n, Filter: newValueFilter(values)})
+	}
+
 	// Add block size to measurement stats.
 	name := models.ParseName(key)
 	t.stats[string(name)] += n
@@ -709,6 +750,12 @@ func (t *tsmWriter) Write(key []byte, values Values) error {
 // exceeds max entries for a given key, ErrMaxBlocksExceeded is returned.  This indicates
 // that the index is now full for this key and no future writes to this key will succeed.
 func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte) error {
+	return t.writeBlock(key, minTime, maxTime, block, blockMeta{})
+}
+
+// writeBlock writes block like WriteBlock, using the value statistics and
+// filter of meta instead of decoding the block for them.
+func (t *tsmWriter) writeBlock(key []byte, minTime, maxTime int64, block []byte, meta blockMeta) error {
 	if len(key) > maxKeyLength {
 		return ErrMaxKeyLengthExceeded
 	}
@@ -747,6 +794,32 @@ func (t *tsmWriter) WriteBlock(key []byte, minTime, maxTime int64, block []byte)
 	// Record this block in index
 	t.index.Add(key, blockType, minTime, maxTime, t.n, uint32(n))
 
+	// Record value statistics of numeric blocks and index the values of the
+	// block, if enabled for the key. The block is decoded at most once, for
+	// what meta doesn't have.
+	stats, hasStats := meta.stats, meta.hasStats
+	var filter *bloom.Filter
+	if t.indexValues != nil && t.indexValues(key) {
+		if filter = meta.filter; filter == nil {
+			if values, err := DecodeBlock(block, nil); err == nil && len(values) > 0 {
+				filter = newValueFilter(values)
+				if !hasStats {
+					stats, hasStats = valuesBlockStats(t.n, values)
+				}
+			}
+		}
+	}
+	if !hasStats {
+		stats, hasStats = encodedBlockStats(t.n, blockType, block)
+	}
+	if hasStats {
+		stats.Offset = t.n
+		t.blockStats = append(t.blockStats, stats)
+	}
+	if filter != nil {
+		t.valueIndex = append(t.valueIndex, ValueIndexEntry{Offset: t.n, Filter: filter})
+	}
+
 	// Add block size to measurement stats.
 	name := models.ParseName(key)
 	t.stats[string(name)] += n
@@ -841,6 +914,28 @@ func (t *tsmWriter) writeStatsFile() error {
 	return f.Close()
 }
 
+// writeSidecarFile writes v to the file named by filename for the TSM file,
+// if the writer is a file.
+func (t *tsmWriter) writeSidecarFile(filename func(tsmPath string) string, v io.WriterTo) error {
+	fw, ok := t.wrapped.(syncer)
+	if !ok {
+		return nil
+	}
+
+	f, err := fs.CreateFile(filename(fw.Name()))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := v.WriteTo(f); err != nil {
+		return err
+	} else if err := f.Sync(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
 func (t *tsmWriter) Close() error {
 	if err := t.Flush(); err != nil {
 		return err
@@ -854,6 +949,16 @@ func (t *tsmWriter) Close() error {
 	if err := t.writeStatsFile(); err != nil {
 		return err
 	}
+	if len(t.blockStats) > 0 {
+		if err := t.writeSidecarFile(BlockStatsFilename, t.blockStats); err != nil {
+			return err
+		}
+	}
+	if len(t.valueIndex) > 0 {
+		if err := t.writeSidecarFile(ValueIndexFilename, t.valueIndex); err != nil {
+			return err
+		}
+	}
 
 	if c, ok := t.wrapped.(io.Closer); ok {
 		return c.Close()
@@ -881,7 +986,7 @@ func (t *tsmWriter) Remove() error {
 
 		if err := os.Remove(f.Name()); err != nil {
 			return err
-		} else if err := os.Remove(StatsFilename(f.Name())); err != nil && !os.IsNotExist(err) {
+		} else if err := removeSidecarFiles(f.Name()); err != nil {
 			return err
 		}
 	}
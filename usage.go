@@ -23,6 +23,10 @@ const (
 	UsageQueryRequestCount UsageMetric = "usage_query_request_count"
 	// UsageQueryRequestBytes is the name of the metrics for tracking the number of query bytes.
 	UsageQueryRequestBytes UsageMetric = "usage_query_request_bytes"
+
+	// UsageRateLimitedRequestCount is the name of the metrics for tracking the number of
+	// requests rejected for exceeding a rate limit.
+	UsageRateLimitedRequestCount UsageMetric = "usage_rate_limited_request_count"
 )
 
 // Usage is a metric associated with the utilization of a particular resource.
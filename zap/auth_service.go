@@ -2,6 +2,7 @@ package zap
 
 import (
 	"context"
+	"time"
 
 	platform "github.com/influxdata/influxdb/v2"
 	"go.uber.org/zap"
@@ -80,3 +81,14 @@ func (s *AuthorizationService) UpdateAuthorization(ctx context.Context, id platf
 
 	return s.AuthorizationService.UpdateAuthorization(ctx, id, upd)
 }
+
+// RotateAuthorization issues a new token for the authorization, and logs any errors.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (a *platform.Authorization, err error) {
+	defer func() {
+		if err != nil {
+			s.log.Info("Error rotating authorization", zap.Error(err))
+		}
+	}()
+
+	return s.AuthorizationService.RotateAuthorization(ctx, id, gracePeriod)
+}